@@ -1,48 +1,70 @@
 package v1beta1
 
 import (
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // DNSRecordSpec defines the desired state of DNSRecord
 type DNSRecordSpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
-	ForProvider     DNSRecordParameters `json:"forProvider"`
+	ForProvider              DNSRecordParameters `json:"forProvider"`
 }
 
 // DNSRecordParameters are the configurable fields of a DNSRecord.
+// +kubebuilder:validation:XValidation:rule="!has(self.caa) || self.type == 'CAA'",message="caa is only valid for CAA records"
+// +kubebuilder:validation:XValidation:rule="has(self.caa) != (has(self.value) && self.value != ”)",message="exactly one of value and caa must be set"
+// +kubebuilder:validation:XValidation:rule="!(has(self.weight) || has(self.port)) || self.type == 'SRV'",message="weight and port are only valid for SRV records"
+// +kubebuilder:validation:XValidation:rule="has(self.weight) == has(self.port)",message="weight and port must be set together"
 type DNSRecordParameters struct {
 	// Domain is the domain name this DNS record belongs to
 	// +kubebuilder:validation:Required
 	Domain string `json:"domain"`
 
-	// Type is the DNS record type (A, AAAA, CNAME, MX, TXT, SRV, etc.)
+	// Type is the DNS record type (A, AAAA, CNAME, MX, TXT, SRV, etc.), or
+	// one of Namecheap's redirect records: URL (302 redirect), URL301
+	// (permanent redirect) or FRAME (masked redirect), or MXE, which
+	// forwards mail to the IP address in value. The domain's email type is
+	// switched to MXE along with an MXE record. ALIAS points a name, such
+	// as the apex "@", at the hostname in value like a CNAME would.
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=A;AAAA;CNAME;MX;TXT;SRV;NS;PTR;CAA
+	// +kubebuilder:validation:Enum=A;AAAA;ALIAS;CNAME;MX;MXE;TXT;SRV;NS;PTR;CAA;URL;URL301;FRAME
 	Type string `json:"type"`
 
 	// Name is the record name (subdomain)
 	// +kubebuilder:validation:Required
 	Name string `json:"name"`
 
-	// Value is the record value
-	// +kubebuilder:validation:Required
-	Value string `json:"value"`
+	// Value is the record value. The value of a redirect record is the
+	// target URL, which is sent and compared as is. Required unless the
+	// value of a CAA record is given by caa.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// CAA is the value of a CAA record given by its flags, tag and value,
+	// which are composed into the form Namecheap expects. It is an
+	// alternative to giving the whole value in value.
+	// +optional
+	CAA *CAARecord `json:"caa,omitempty"`
 
-	// TTL is the time to live for the record in seconds
+	// TTL is the time to live for the record in seconds. Unset means
+	// Namecheap's Automatic TTL, which it reports as 1799. The TTL of an
+	// adopted record is initialized from Namecheap unless it is automatic.
 	// +kubebuilder:validation:Minimum=60
 	// +kubebuilder:validation:Maximum=86400
 	// +optional
 	TTL *int `json:"ttl,omitempty"`
 
-	// Priority is used for MX and SRV records
+	// Priority is used for MX and SRV records, defaulting to 10
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=65535
 	// +optional
 	Priority *int `json:"priority,omitempty"`
 
-	// Weight is used for SRV records
+	// Weight is used for SRV records. With weight and port set, value is
+	// the SRV target, and the priority, weight, port and target are
+	// composed into the value Namecheap stores. Without them value holds
+	// all four.
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=65535
 	// +optional
@@ -53,12 +75,37 @@ type DNSRecordParameters struct {
 	// +kubebuilder:validation:Maximum=65535
 	// +optional
 	Port *int `json:"port,omitempty"`
+
+	// StrictDelete only removes the record on deletion if its current value
+	// still matches the value last applied by the provider. Records that were
+	// repointed out-of-band are left in place.
+	// +optional
+	StrictDelete *bool `json:"strictDelete,omitempty"`
+}
+
+// CAARecord is the value of a CAA record.
+type CAARecord struct {
+	// Flags of the record; 128 marks it critical
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=255
+	// +optional
+	Flags int `json:"flags,omitempty"`
+
+	// Tag is the property the record sets
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=issue;issuewild;iodef
+	Tag string `json:"tag"`
+
+	// Value of the property, e.g. the domain of a certificate authority or
+	// an iodef URL, without quotes
+	// +kubebuilder:validation:Required
+	Value string `json:"value"`
 }
 
 // DNSRecordStatus defines the observed state of DNSRecord
 type DNSRecordStatus struct {
 	xpv1.ManagedResourceStatus `json:",inline"`
-	AtProvider        DNSRecordObservation `json:"atProvider,omitempty"`
+	AtProvider                 DNSRecordObservation `json:"atProvider,omitempty"`
 }
 
 // DNSRecordObservation are the observable fields of a DNSRecord.
@@ -69,11 +116,36 @@ type DNSRecordObservation struct {
 	// FQDN is the fully qualified domain name
 	FQDN string `json:"fqdn,omitempty"`
 
+	// LastAppliedValue is the record value last applied by the provider
+	LastAppliedValue string `json:"lastAppliedValue,omitempty"`
+
+	// NormalizedValue is the record value stored in Namecheap, normalized as
+	// it is compared with the spec. Setting the spec value to it removes any
+	// difference that the comparison doesn't ignore.
+	NormalizedValue string `json:"normalizedValue,omitempty"`
+
+	// DynamicDNS reports whether dynamic DNS is enabled for the record, whose
+	// address a dynamic DNS client may then change. Dynamic DNS is enabled
+	// in the Namecheap dashboard; the API can't enable it.
+	DynamicDNS bool `json:"dynamicDNS,omitempty"`
+
 	// CreatedDate is when the record was created
 	CreatedDate *metav1.Time `json:"createdDate,omitempty"`
 
 	// UpdatedDate is when the record was last updated
 	UpdatedDate *metav1.Time `json:"updatedDate,omitempty"`
+
+	// LastHandledRefresh is the most recent value of the
+	// namecheap.m.crossplane.io/refresh annotation that triggered a fresh read
+	LastHandledRefresh string `json:"lastHandledRefresh,omitempty"`
+
+	// Environment is the Namecheap environment, sandbox or production, the
+	// resource was first observed in
+	Environment string `json:"environment,omitempty"`
+
+	// DeleteFailures is the number of consecutive failed attempts to delete
+	// the record
+	DeleteFailures int `json:"deleteFailures,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -147,4 +219,4 @@ func (mg *DNSRecord) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretRefe
 
 func init() {
 	SchemeBuilder.Register(&DNSRecord{}, &DNSRecordList{})
-}
\ No newline at end of file
+}
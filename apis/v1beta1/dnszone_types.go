@@ -0,0 +1,159 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+)
+
+// DNSZoneSpec defines the desired state of DNSZone
+type DNSZoneSpec struct {
+	xpv1.ManagedResourceSpec `json:",inline"`
+	ForProvider              DNSZoneParameters `json:"forProvider"`
+}
+
+// DNSZoneParameters are the configurable fields of a DNSZone.
+type DNSZoneParameters struct {
+	// Domain is the domain name whose host records the zone owns
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="domain is immutable"
+	Domain string `json:"domain"`
+
+	// Records are every host record of the domain. Records missing from
+	// the list are removed.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Records []DNSZoneRecord `json:"records"`
+
+	// EmailType is the domain's email type. Unset keeps the current one
+	// while the records allow it, and otherwise switches it to MX or MXE
+	// when the records include MX or MXE records.
+	// +kubebuilder:validation:Enum=NONE;MX;MXE;FWD;OX
+	// +optional
+	EmailType *string `json:"emailType,omitempty"`
+}
+
+// DNSZoneRecord is a host record of a DNSZone.
+type DNSZoneRecord struct {
+	// Name is the record name (subdomain), e.g. "www" or "@"
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Type is the DNS record type, as for a DNSRecord
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=A;AAAA;ALIAS;CNAME;MX;MXE;TXT;SRV;NS;PTR;CAA;URL;URL301;FRAME
+	Type string `json:"type"`
+
+	// Value is the record value, compared and written as a DNSRecord's is
+	// +kubebuilder:validation:Required
+	Value string `json:"value"`
+
+	// TTL is the time to live for the record in seconds. Unset means
+	// Namecheap's Automatic TTL, which it reports as 1799.
+	// +kubebuilder:validation:Minimum=60
+	// +kubebuilder:validation:Maximum=86400
+	// +optional
+	TTL *int `json:"ttl,omitempty"`
+
+	// Priority is used for MX and SRV records, defaulting to 10
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Priority *int `json:"priority,omitempty"`
+}
+
+// DNSZoneStatus defines the observed state of DNSZone
+type DNSZoneStatus struct {
+	xpv1.ManagedResourceStatus `json:",inline"`
+	AtProvider                 DNSZoneObservation `json:"atProvider,omitempty"`
+}
+
+// DNSZoneObservation are the observable fields of a DNSZone.
+type DNSZoneObservation struct {
+	// RecordCount is the number of host records the domain was last
+	// observed with
+	RecordCount int `json:"recordCount,omitempty"`
+
+	// EmailType is the domain's email type as last observed
+	EmailType string `json:"emailType,omitempty"`
+
+	// LastSynced is when the provider last wrote the domain's host records
+	LastSynced *metav1.Time `json:"lastSynced,omitempty"`
+
+	// Environment is the Namecheap environment, sandbox or production, the
+	// resource was first observed in
+	Environment string `json:"environment,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,namecheap}
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="DOMAIN",type="string",JSONPath=".spec.forProvider.domain"
+// +kubebuilder:printcolumn:name="RECORDS",type="integer",JSONPath=".status.atProvider.recordCount"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// DNSZone is the Schema for the dnszones API. A DNSZone owns the complete
+// host set of a domain, which it writes in a single call.
+type DNSZone struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DNSZoneSpec   `json:"spec,omitempty"`
+	Status DNSZoneStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DNSZoneList contains a list of DNSZone
+type DNSZoneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DNSZone `json:"items"`
+}
+
+// GetCondition of this DNSZone.
+func (mg *DNSZone) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetManagementPolicies of this DNSZone.
+func (mg *DNSZone) GetManagementPolicies() xpv1.ManagementPolicies {
+	return mg.Spec.ManagementPolicies
+}
+
+// GetProviderConfigReference of this DNSZone.
+func (mg *DNSZone) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return mg.Spec.ProviderConfigReference
+}
+
+// GetWriteConnectionSecretToReference of this DNSZone.
+func (mg *DNSZone) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return mg.Spec.WriteConnectionSecretToReference
+}
+
+// SetConditions of this DNSZone.
+func (mg *DNSZone) SetConditions(c ...xpv1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetManagementPolicies of this DNSZone.
+func (mg *DNSZone) SetManagementPolicies(r xpv1.ManagementPolicies) {
+	mg.Spec.ManagementPolicies = r
+}
+
+// SetProviderConfigReference of this DNSZone.
+func (mg *DNSZone) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+// SetWriteConnectionSecretToReference of this DNSZone.
+func (mg *DNSZone) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	mg.Spec.WriteConnectionSecretToReference = r
+}
+
+func init() {
+	SchemeBuilder.Register(&DNSZone{}, &DNSZoneList{})
+}
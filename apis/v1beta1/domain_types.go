@@ -1,55 +1,205 @@
 package v1beta1
 
 import (
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // DomainSpec defines the desired state of Domain
 type DomainSpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
-	ForProvider     DomainParameters `json:"forProvider"`
+	ForProvider              DomainParameters `json:"forProvider"`
 }
 
 // DomainParameters are the configurable fields of a Domain.
+// +kubebuilder:validation:XValidation:rule="!(has(self.useDefaultDNS) && self.useDefaultDNS && has(self.nameservers) && size(self.nameservers) > 0)",message="useDefaultDNS can't be combined with nameservers"
 type DomainParameters struct {
-	// DomainName is the domain name to manage
+	// DomainName is the domain name to manage. An internationalized domain
+	// name may be given in its Unicode form, e.g. bücher.example, or its
+	// punycode form, e.g. xn--bcher-kva.example.
 	// +kubebuilder:validation:Required
 	DomainName string `json:"domainName"`
 
+	// IDNCode is the language code Namecheap requires to register an
+	// internationalized domain name, e.g. ger for German. It is ignored for
+	// other domain names and once the domain is registered.
+	// +optional
+	IDNCode *string `json:"idnCode,omitempty"`
+
 	// RegistrationYears specifies the number of years to register the domain for
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=10
 	// +optional
 	RegistrationYears *int `json:"registrationYears,omitempty"`
 
-	// RenewalYears specifies the number of years to renew the domain for
+	// Contacts are the contacts the domain is registered with. They are
+	// required to register a new domain. When set, the domain's contacts are
+	// kept in sync with them; when unset, an existing domain's contacts are
+	// left alone.
+	// +optional
+	Contacts *DomainContacts `json:"contacts,omitempty"`
+
+	// RenewalYears specifies the number of years to renew the domain for.
+	// The renewal is ordered once for each generation of the spec that sets
+	// it, as recorded in status.atProvider.lastOrder, so any later change to
+	// the spec renews the domain again; unset it once the renewal is ordered.
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=10
 	// +optional
 	RenewalYears *int `json:"renewalYears,omitempty"`
 
-	// Nameservers specifies custom nameservers for the domain
+	// Nameservers specifies custom nameservers for the domain. Listing only
+	// Namecheap's own nameservers (*.registrar-servers.com) switches the
+	// domain back to Namecheap DNS; mixing them with other nameservers is
+	// rejected.
 	// +optional
 	Nameservers []string `json:"nameservers,omitempty"`
 
+	// UseDefaultDNS keeps the domain on Namecheap's own nameservers and
+	// hosted DNS, switching it back whenever it is found on other
+	// nameservers. Removing nameservers the provider applied from the spec
+	// switches the domain back as well, unless UseDefaultDNS is false.
+	// +optional
+	UseDefaultDNS *bool `json:"useDefaultDNS,omitempty"`
+
 	// AutoRenew enables automatic domain renewal
 	// +optional
 	AutoRenew *bool `json:"autoRenew,omitempty"`
 
-	// PrivacyProtection enables WHOIS privacy protection
+	// ReactivateIfExpired reactivates the domain when Namecheap reports it
+	// as expired, while it is still in its grace or redemption period.
+	// Reactivation is charged to the account. A reactivation Namecheap
+	// refuses is reported in the Reactivation condition and not attempted
+	// again until the spec changes.
+	// +optional
+	ReactivateIfExpired *bool `json:"reactivateIfExpired,omitempty"`
+
+	// ReactivationPromoCode is a promotion code to apply when the domain is
+	// reactivated
+	// +optional
+	ReactivationPromoCode *string `json:"reactivationPromoCode,omitempty"`
+
+	// PrivacyProtection enables WHOIS privacy protection. A domain
+	// registered with it enabled is ordered with a free WhoisGuard
+	// subscription that is enabled from the start.
 	// +optional
 	PrivacyProtection *bool `json:"privacyProtection,omitempty"`
 
 	// WhoisGuardForwardEmail specifies the email address to forward WhoisGuard emails to
 	// +optional
 	WhoisGuardForwardEmail *string `json:"whoisGuardForwardEmail,omitempty"`
+
+	// RegistrarLock enables or disables the registrar lock, which prevents
+	// the domain from being transferred away. When unset, the lock is left
+	// as it is.
+	// +optional
+	RegistrarLock *bool `json:"registrarLock,omitempty"`
+
+	// DeletionBehavior controls what happens to the domain at Namecheap when
+	// the resource is deleted. Namecheap can't delete domains, so Orphan
+	// leaves the domain untouched. DisableRenewals disables WhoisGuard so
+	// the domain lapses at expiry; auto-renew can't be changed through the
+	// Namecheap API and must be turned off in the dashboard. ReleaseDNS
+	// resets the domain to Namecheap's default nameservers.
+	// +kubebuilder:validation:Enum=Orphan;DisableRenewals;ReleaseDNS
+	// +kubebuilder:default=Orphan
+	// +optional
+	DeletionBehavior *string `json:"deletionBehavior,omitempty"`
+
+	// ObserveDNSSummary additionally reads the domain's DNS host records on
+	// every observation and summarizes them in status.atProvider.dnsSummary.
+	// It costs an extra API call per observation, so it is off by default.
+	// +optional
+	ObserveDNSSummary *bool `json:"observeDNSSummary,omitempty"`
+}
+
+// DomainContacts are the contacts a domain is registered with. Namecheap
+// requires registrant, tech, admin and billing contacts; any of the latter
+// three that are omitted default to the registrant.
+type DomainContacts struct {
+	// Registrant is the domain's owner
+	Registrant DomainContact `json:"registrant"`
+
+	// Tech is the technical contact
+	// +optional
+	Tech *DomainContact `json:"tech,omitempty"`
+
+	// Admin is the administrative contact
+	// +optional
+	Admin *DomainContact `json:"admin,omitempty"`
+
+	// AuxBilling is the billing contact
+	// +optional
+	AuxBilling *DomainContact `json:"auxBilling,omitempty"`
+}
+
+// DomainContact is one of a domain's contacts
+type DomainContact struct {
+	// OrganizationName is the contact's organization
+	// +optional
+	OrganizationName *string `json:"organizationName,omitempty"`
+
+	// JobTitle is the contact's job title
+	// +optional
+	JobTitle *string `json:"jobTitle,omitempty"`
+
+	// FirstName is the contact's first name
+	// +kubebuilder:validation:MinLength=1
+	FirstName string `json:"firstName"`
+
+	// LastName is the contact's last name
+	// +kubebuilder:validation:MinLength=1
+	LastName string `json:"lastName"`
+
+	// Address1 is the first line of the contact's address
+	// +kubebuilder:validation:MinLength=1
+	Address1 string `json:"address1"`
+
+	// Address2 is the second line of the contact's address
+	// +optional
+	Address2 *string `json:"address2,omitempty"`
+
+	// City is the contact's city
+	// +kubebuilder:validation:MinLength=1
+	City string `json:"city"`
+
+	// StateProvince is the contact's state or province
+	// +kubebuilder:validation:MinLength=1
+	StateProvince string `json:"stateProvince"`
+
+	// PostalCode is the contact's postal code
+	// +kubebuilder:validation:MinLength=1
+	PostalCode string `json:"postalCode"`
+
+	// Country is the contact's two-letter ISO 3166-1 country code
+	// +kubebuilder:validation:Pattern=`^[A-Z]{2}$`
+	Country string `json:"country"`
+
+	// Phone is the contact's phone number, in the format +NNN.NNNNNNNNNN
+	// +kubebuilder:validation:Pattern=`^\+[0-9]{1,3}\.[0-9]{4,14}$`
+	Phone string `json:"phone"`
+
+	// PhoneExt is the contact's phone extension
+	// +optional
+	PhoneExt *string `json:"phoneExt,omitempty"`
+
+	// Fax is the contact's fax number, in the format +NNN.NNNNNNNNNN
+	// +kubebuilder:validation:Pattern=`^\+[0-9]{1,3}\.[0-9]{4,14}$`
+	// +optional
+	Fax *string `json:"fax,omitempty"`
+
+	// EmailAddress is the contact's email address
+	// +kubebuilder:validation:MinLength=1
+	EmailAddress string `json:"emailAddress"`
 }
 
 // DomainStatus defines the observed state of Domain
 type DomainStatus struct {
 	xpv1.ManagedResourceStatus `json:",inline"`
-	AtProvider        DomainObservation `json:"atProvider,omitempty"`
+	AtProvider                 DomainObservation `json:"atProvider,omitempty"`
 }
 
 // DomainObservation are the observable fields of a Domain.
@@ -75,7 +225,7 @@ type DomainObservation struct {
 	// IsExpired indicates if the domain has expired
 	IsExpired *bool `json:"isExpired,omitempty"`
 
-	// IsLocked indicates if the domain is locked
+	// IsLocked indicates if the registrar lock is enabled for the domain
 	IsLocked *bool `json:"isLocked,omitempty"`
 
 	// IsAutoRenew indicates if auto-renewal is enabled
@@ -87,11 +237,346 @@ type DomainObservation struct {
 	// WhoisGuardID is the WhoisGuard service ID
 	WhoisGuardID *int `json:"whoisGuardID,omitempty"`
 
+	// WhoisGuardExpirationDate is when the domain's WhoisGuard subscription
+	// expires, and with it the domain's privacy. WhoisGuard is only
+	// observed when privacyProtection is set.
+	WhoisGuardExpirationDate *metav1.Time `json:"whoisGuardExpirationDate,omitempty"`
+
 	// IsPremium indicates if this is a premium domain
 	IsPremium *bool `json:"isPremium,omitempty"`
 
 	// IsOurDNS indicates if using Namecheap DNS hosting
 	IsOurDNS *bool `json:"isOurDNS,omitempty"`
+
+	// DNSProviderType is the kind of DNS Namecheap reports the domain uses,
+	// such as FREE for Namecheap's free DNS or CUSTOM for custom nameservers
+	DNSProviderType string `json:"dnsProviderType,omitempty"`
+
+	// AppliedNameservers are the custom nameservers last applied by the
+	// provider. Once the spec no longer lists nameservers, the domain is
+	// switched back to Namecheap DNS.
+	AppliedNameservers []string `json:"appliedNameservers,omitempty"`
+
+	// TransferOutPending indicates a transfer-out (EPP code) request is in
+	// progress for the domain
+	TransferOutPending *bool `json:"transferOutPending,omitempty"`
+
+	// RegistrarLockEnabled indicates the registrar lock, set through
+	// Namecheap, is enabled for the domain
+	RegistrarLockEnabled *bool `json:"registrarLockEnabled,omitempty"`
+
+	// RegistryStatuses are the EPP status codes imposed by the registry,
+	// such as serverTransferProhibited. They cannot be changed through
+	// Namecheap and are reported for information only.
+	RegistryStatuses []string `json:"registryStatuses,omitempty"`
+
+	// LastHandledRefresh is the most recent value of the
+	// namecheap.m.crossplane.io/refresh annotation that triggered a fresh read
+	LastHandledRefresh string `json:"lastHandledRefresh,omitempty"`
+
+	// Environment is the Namecheap environment, sandbox or production, the
+	// resource was first observed in
+	Environment string `json:"environment,omitempty"`
+
+	// LastOrder is the most recent registration, renewal or reactivation
+	// order placed for the domain by this resource
+	LastOrder *DomainOrder `json:"lastOrder,omitempty"`
+
+	// DNSSummary summarizes the domain's DNS host records. It is only
+	// reported when observeDNSSummary is set.
+	DNSSummary *DNSSummary `json:"dnsSummary,omitempty"`
+
+	// PrivacyRetry tracks retries of enabling WhoisGuard while the
+	// subscription of a newly registered domain is not active yet
+	PrivacyRetry *PrivacyRetry `json:"privacyRetry,omitempty"`
+
+	// Capabilities are what Namecheap supports for the domain's TLD
+	Capabilities *TLDCapabilities `json:"capabilities,omitempty"`
+}
+
+// TLDCapabilities are what Namecheap supports for a TLD, from its TLD list
+type TLDCapabilities struct {
+	// TLD is the TLD the capabilities are of, e.g. co.uk
+	TLD string `json:"tld"`
+
+	// APIRegisterable indicates domains can be registered through the API
+	APIRegisterable bool `json:"apiRegisterable"`
+
+	// APIRenewable indicates domains can be renewed through the API
+	APIRenewable bool `json:"apiRenewable"`
+
+	// APITransferable indicates domains can be transferred in through the
+	// API
+	APITransferable bool `json:"apiTransferable"`
+
+	// EPPRequired indicates transfers in require the domain's EPP code
+	EPPRequired bool `json:"eppRequired"`
+
+	// SupportsRegistrarLock indicates domains can be locked against
+	// transfers away
+	SupportsRegistrarLock bool `json:"supportsRegistrarLock"`
+
+	// ContactsModifiable indicates a domain's contacts can be changed once
+	// it is registered
+	ContactsModifiable bool `json:"contactsModifiable"`
+
+	// WhoisVerification indicates the registrant must verify their contact
+	// details after registration
+	WhoisVerification bool `json:"whoisVerification"`
+
+	// RegistrationYears is the range of years domains can be registered
+	// for, if Namecheap reports it
+	// +optional
+	RegistrationYears *YearRange `json:"registrationYears,omitempty"`
+
+	// RenewalYears is the range of years domains can be renewed for, if
+	// Namecheap reports it
+	// +optional
+	RenewalYears *YearRange `json:"renewalYears,omitempty"`
+}
+
+// YearRange is an inclusive range of years
+type YearRange struct {
+	// Min is the fewest years
+	Min int `json:"min"`
+
+	// Max is the most years
+	Max int `json:"max"`
+}
+
+// PrivacyRetry tracks retries of enabling WhoisGuard for a domain
+type PrivacyRetry struct {
+	// Attempts is the number of attempts to enable WhoisGuard that failed
+	// because its subscription was not active yet
+	Attempts int `json:"attempts"`
+
+	// NextAttemptTime is when enabling WhoisGuard is next attempted
+	NextAttemptTime metav1.Time `json:"nextAttemptTime"`
+}
+
+// DNSSummary summarizes the DNS host records of a domain
+type DNSSummary struct {
+	// RecordCount is the number of host records
+	RecordCount int `json:"recordCount"`
+
+	// RecordTypes is the number of host records of each record type
+	RecordTypes map[string]int `json:"recordTypes,omitempty"`
+
+	// IsUsingOurDNS indicates the domain is delegated to Namecheap's DNS
+	IsUsingOurDNS bool `json:"isUsingOurDNS"`
+}
+
+// Actions of a DomainOrder.
+const (
+	// DomainOrderCreate is the registration of a domain.
+	DomainOrderCreate = "Create"
+
+	// DomainOrderRenew is the renewal of a domain.
+	DomainOrderRenew = "Renew"
+
+	// DomainOrderReactivate is the reactivation of an expired domain.
+	DomainOrderReactivate = "Reactivate"
+)
+
+// DomainOrder records a billable order placed for a domain
+type DomainOrder struct {
+	// Action is the ordered action, Create, Renew or Reactivate
+	Action string `json:"action"`
+
+	// OrderID is the Namecheap order ID
+	OrderID int `json:"orderID,omitempty"`
+
+	// TransactionID is the Namecheap transaction ID
+	TransactionID int `json:"transactionID,omitempty"`
+
+	// ChargedAmount is the amount charged for the order, in the account
+	// currency
+	ChargedAmount string `json:"chargedAmount,omitempty"`
+
+	// Generation is the generation of the spec the order was placed for. A
+	// renewal is only ordered once per generation.
+	Generation int64 `json:"generation,omitempty"`
+}
+
+// Domain condition types and reasons.
+const (
+	// TypeTransferOut reports whether a transfer-out is pending for a Domain.
+	TypeTransferOut xpv1.ConditionType = "TransferOut"
+
+	ReasonTransferOutPending xpv1.ConditionReason = "TransferOutPending"
+	ReasonNoTransferOut      xpv1.ConditionReason = "NoTransferOut"
+
+	// TypeRegistryStatus reports whether the registry imposes statuses on a
+	// Domain.
+	TypeRegistryStatus xpv1.ConditionType = "RegistryStatus"
+
+	ReasonRegistryRestricted xpv1.ConditionReason = "RegistryRestricted"
+	ReasonNoRegistryStatus   xpv1.ConditionReason = "NoRegistryStatus"
+
+	// ReasonProvisioning indicates a Domain was registered but Namecheap
+	// doesn't report it yet.
+	ReasonProvisioning xpv1.ConditionReason = "Provisioning"
+
+	// TypeConverging reports whether a Domain is still converging on its
+	// spec after steps that Namecheap can't complete straight away.
+	TypeConverging xpv1.ConditionType = "Converging"
+
+	ReasonPrivacyPending xpv1.ConditionReason = "PrivacyPending"
+	ReasonPrivacyFailed  xpv1.ConditionReason = "PrivacyFailed"
+	ReasonConverged      xpv1.ConditionReason = "Converged"
+
+	// TypeReactivation reports whether an expired Domain was reactivated.
+	TypeReactivation xpv1.ConditionType = "Reactivation"
+
+	ReasonReactivated        xpv1.ConditionReason = "Reactivated"
+	ReasonReactivationFailed xpv1.ConditionReason = "ReactivationFailed"
+
+	// TypeTLDSupport reports whether a Domain's TLD supports what its spec
+	// asks for.
+	TypeTLDSupport xpv1.ConditionType = "TLDSupport"
+
+	ReasonTLDSupported   xpv1.ConditionReason = "TLDSupported"
+	ReasonTLDUnsupported xpv1.ConditionReason = "TLDUnsupported"
+)
+
+// Provisioning returns a condition indicating the domain was registered but
+// Namecheap doesn't report it yet.
+func Provisioning() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonProvisioning,
+		Message:            "The domain was registered and is waiting to be reported by Namecheap",
+	}
+}
+
+// TransferOutPending returns a condition indicating a transfer-out request is
+// in progress for the domain.
+func TransferOutPending() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTransferOut,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTransferOutPending,
+		Message:            "A transfer-out request is pending for this domain",
+	}
+}
+
+// NoTransferOut returns a condition indicating no transfer-out request is in
+// progress for the domain.
+func NoTransferOut() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTransferOut,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoTransferOut,
+	}
+}
+
+// RegistryRestricted returns a condition indicating the registry imposes the
+// supplied statuses on the domain.
+func RegistryRestricted(statuses []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRegistryStatus,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRegistryRestricted,
+		Message:            "The registry imposes statuses on this domain: " + strings.Join(statuses, ", "),
+	}
+}
+
+// NoRegistryStatus returns a condition indicating the registry imposes no
+// statuses on the domain.
+func NoRegistryStatus() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRegistryStatus,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoRegistryStatus,
+	}
+}
+
+// PrivacyPending returns a condition indicating enabling WhoisGuard is being
+// retried until the domain's subscription is active.
+func PrivacyPending(message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeConverging,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPrivacyPending,
+		Message:            message,
+	}
+}
+
+// PrivacyFailed returns a condition indicating enabling WhoisGuard was given
+// up on after its retries were exhausted.
+func PrivacyFailed(message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeConverging,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPrivacyFailed,
+		Message:            message,
+	}
+}
+
+// Converged returns a condition indicating the domain has converged on its
+// spec.
+func Converged() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeConverging,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonConverged,
+	}
+}
+
+// Reactivated returns a condition indicating the expired domain was
+// reactivated.
+func Reactivated(message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReactivation,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonReactivated,
+		Message:            message,
+	}
+}
+
+// ReactivationFailed returns a condition indicating Namecheap refused to
+// reactivate the expired domain.
+func ReactivationFailed(message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReactivation,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonReactivationFailed,
+		Message:            message,
+	}
+}
+
+// TLDSupported returns a condition indicating the domain's TLD supports what
+// the spec asks for.
+func TLDSupported() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTLDSupport,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTLDSupported,
+	}
+}
+
+// TLDUnsupported returns a condition indicating the domain's TLD doesn't
+// support the supplied parts of the spec.
+func TLDUnsupported(problems []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTLDSupport,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTLDUnsupported,
+		Message:            strings.Join(problems, "; "),
+	}
 }
 
 // +kubebuilder:object:root=true
@@ -163,4 +648,4 @@ func (mg *Domain) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReferen
 
 func init() {
 	SchemeBuilder.Register(&Domain{}, &DomainList{})
-}
\ No newline at end of file
+}
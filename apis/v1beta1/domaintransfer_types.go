@@ -0,0 +1,215 @@
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+)
+
+// DomainTransferSpec defines the desired state of DomainTransfer
+type DomainTransferSpec struct {
+	xpv1.ManagedResourceSpec `json:",inline"`
+	ForProvider              DomainTransferParameters `json:"forProvider"`
+}
+
+// DomainTransferParameters are the configurable fields of a DomainTransfer.
+type DomainTransferParameters struct {
+	// DomainName is the domain to transfer into the Namecheap account from
+	// its current registrar
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="domainName is immutable"
+	DomainName string `json:"domainName"`
+
+	// EPPCodeSecretRef selects the key of a Secret, in the resource's
+	// namespace, holding the domain's EPP (authorization) code from its
+	// current registrar. The code is never set in the spec. Updating the
+	// Secret resubmits a transfer stalled on an invalid code, once per
+	// version of the Secret.
+	// +kubebuilder:validation:Required
+	EPPCodeSecretRef xpv1.LocalSecretKeySelector `json:"eppCodeSecretRef"`
+
+	// Years is the number of years the domain is renewed for once
+	// transferred
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +kubebuilder:default=1
+	// +optional
+	Years *int `json:"years,omitempty"`
+
+	// WhoisGuard adds a free WhoisGuard subscription to the domain and
+	// enables it once the domain is transferred
+	// +optional
+	WhoisGuard *bool `json:"whoisGuard,omitempty"`
+}
+
+// DomainTransferStatus defines the observed state of DomainTransfer
+type DomainTransferStatus struct {
+	xpv1.ManagedResourceStatus `json:",inline"`
+	AtProvider                 DomainTransferObservation `json:"atProvider,omitempty"`
+}
+
+// DomainTransferObservation are the observable fields of a DomainTransfer.
+type DomainTransferObservation struct {
+	// TransferID is the Namecheap ID of the transfer
+	TransferID *int `json:"transferID,omitempty"`
+
+	// StatusCode is the Namecheap status ID of the transfer. -1 is a
+	// completed transfer and other negative IDs are failed ones.
+	StatusCode *int `json:"statusCode,omitempty"`
+
+	// StatusDescription is the Namecheap status of the transfer
+	StatusDescription string `json:"statusDescription,omitempty"`
+
+	// Phase is the phase of the transfer: Pending, AwaitingEPP, Completed
+	// or Failed
+	Phase string `json:"phase,omitempty"`
+
+	// OrderID is the Namecheap order ID of the transfer
+	OrderID int `json:"orderID,omitempty"`
+
+	// TransactionID is the Namecheap transaction ID of the transfer
+	TransactionID int `json:"transactionID,omitempty"`
+
+	// ChargedAmount is the amount charged for the transfer, in the account
+	// currency
+	ChargedAmount string `json:"chargedAmount,omitempty"`
+
+	// EPPCodeSecretVersion is the resource version of the EPP code Secret
+	// the transfer was last submitted with
+	EPPCodeSecretVersion string `json:"eppCodeSecretVersion,omitempty"`
+
+	// Environment is the Namecheap environment, sandbox or production, the
+	// resource was first observed in
+	Environment string `json:"environment,omitempty"`
+}
+
+// DomainTransfer condition types and reasons.
+const (
+	// TypeTransfer reports the phase of a DomainTransfer. It is True once
+	// the transfer completed.
+	TypeTransfer xpv1.ConditionType = "Transfer"
+
+	ReasonTransferPending     xpv1.ConditionReason = "Pending"
+	ReasonTransferAwaitingEPP xpv1.ConditionReason = "AwaitingEPP"
+	ReasonTransferCompleted   xpv1.ConditionReason = "Completed"
+	ReasonTransferFailed      xpv1.ConditionReason = "Failed"
+)
+
+// TransferPending returns a condition indicating a transfer is in progress.
+func TransferPending(status string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTransfer,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTransferPending,
+		Message:            "The transfer is in progress: " + status,
+	}
+}
+
+// TransferAwaitingEPP returns a condition indicating a transfer stalled until
+// it is resubmitted with a valid EPP code.
+func TransferAwaitingEPP(status string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTransfer,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTransferAwaitingEPP,
+		Message:            "The transfer is waiting for a valid EPP code; update the EPP code Secret to resubmit it: " + status,
+	}
+}
+
+// TransferCompleted returns a condition indicating a transfer completed.
+func TransferCompleted() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTransfer,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTransferCompleted,
+		Message:            "The domain was transferred into the account",
+	}
+}
+
+// TransferFailed returns a condition indicating a transfer was cancelled or
+// rejected.
+func TransferFailed(status string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTransfer,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTransferFailed,
+		Message:            "The transfer failed; delete and recreate the resource to order it again: " + status,
+	}
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,namecheap}
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="DOMAIN",type="string",JSONPath=".spec.forProvider.domainName"
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.atProvider.phase"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// DomainTransfer is the Schema for the domaintransfers API
+type DomainTransfer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DomainTransferSpec   `json:"spec,omitempty"`
+	Status DomainTransferStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DomainTransferList contains a list of DomainTransfer
+type DomainTransferList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DomainTransfer `json:"items"`
+}
+
+// GetCondition of this DomainTransfer.
+func (mg *DomainTransfer) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetManagementPolicies of this DomainTransfer.
+func (mg *DomainTransfer) GetManagementPolicies() xpv1.ManagementPolicies {
+	return mg.Spec.ManagementPolicies
+}
+
+// GetProviderConfigReference of this DomainTransfer.
+func (mg *DomainTransfer) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return mg.Spec.ProviderConfigReference
+}
+
+// GetWriteConnectionSecretToReference of this DomainTransfer.
+func (mg *DomainTransfer) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return mg.Spec.WriteConnectionSecretToReference
+}
+
+// SetConditions of this DomainTransfer.
+func (mg *DomainTransfer) SetConditions(c ...xpv1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetManagementPolicies of this DomainTransfer.
+func (mg *DomainTransfer) SetManagementPolicies(r xpv1.ManagementPolicies) {
+	mg.Spec.ManagementPolicies = r
+}
+
+// SetProviderConfigReference of this DomainTransfer.
+func (mg *DomainTransfer) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+// SetWriteConnectionSecretToReference of this DomainTransfer.
+func (mg *DomainTransfer) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	mg.Spec.WriteConnectionSecretToReference = r
+}
+
+func init() {
+	SchemeBuilder.Register(&DomainTransfer{}, &DomainTransferList{})
+}
@@ -1,8 +1,8 @@
 package v1beta1
 
 import (
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ProviderConfigSpec defines the desired state of ProviderConfig
@@ -18,6 +18,36 @@ type ProviderConfigSpec struct {
 	// SandboxMode enables sandbox mode for testing
 	// +optional
 	SandboxMode *bool `json:"sandboxMode,omitempty"`
+
+	// PollInterval overrides the provider's --poll interval for resources
+	// using this ProviderConfig. A crossplane.io/poll-interval annotation on
+	// an individual resource still takes precedence. Must be at least 30s
+	// to protect the account's API quota.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('30s')",message="pollInterval must be at least 30s"
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+
+	// SyncInterval is the longest a resource using this ProviderConfig may
+	// go without being checked for drift, capping both the poll interval
+	// and any per-resource override. Must be at least 1m.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('1m')",message="syncInterval must be at least 1m"
+	SyncInterval *metav1.Duration `json:"syncInterval,omitempty"`
+
+	// RegistrationGracePeriod is how long after a Domain is registered that
+	// Namecheap reporting it as not found is treated as the registration
+	// still propagating, rather than the domain not existing. Defaults to
+	// 5m.
+	// +optional
+	RegistrationGracePeriod *metav1.Duration `json:"registrationGracePeriod,omitempty"`
+
+	// CheckBalance has the account's available balance checked against the
+	// price of registering or renewing a Domain, or of purchasing an
+	// SSLCertificate, before the order is placed. An order the balance
+	// doesn't cover is refused, and not attempted again until the
+	// resource's spec changes.
+	// +optional
+	CheckBalance *bool `json:"checkBalance,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -32,7 +62,14 @@ type ProviderCredentials struct {
 // ProviderConfigStatus defines the observed state of ProviderConfig
 type ProviderConfigStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
-	UserCount            *int64 `json:"userCount,omitempty"`
+	UserCount              *int64 `json:"userCount,omitempty"`
+
+	// CredentialsRevision is a checksum of the credentials the provider's
+	// clients were last built from: the first 16 hex digits of the SHA-256
+	// of the credentials payload. It matches the same checksum of the secret
+	// once a rotated secret has been picked up.
+	// +optional
+	CredentialsRevision string `json:"credentialsRevision,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -62,4 +99,4 @@ type ProviderConfigList struct {
 
 func init() {
 	SchemeBuilder.Register(&ProviderConfig{}, &ProviderConfigList{})
-}
\ No newline at end of file
+}
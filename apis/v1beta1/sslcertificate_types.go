@@ -1,22 +1,33 @@
 package v1beta1
 
 import (
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // SSLCertificateSpec defines the desired state of SSLCertificate
 type SSLCertificateSpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
-	ForProvider       SSLCertificateParameters `json:"forProvider"`
+	ForProvider              SSLCertificateParameters `json:"forProvider"`
 }
 
 // SSLCertificateParameters are the configurable fields of an SSLCertificate.
+// +kubebuilder:validation:XValidation:rule="has(self.certificateType) != has(self.certificateTypeName)",message="exactly one of certificateType and certificateTypeName must be set"
 type SSLCertificateParameters struct {
-	// CertificateType specifies the type of SSL certificate to purchase
-	// +kubebuilder:validation:Required
+	// CertificateType specifies the type of SSL certificate to purchase by
+	// its numeric Namecheap type ID. Prefer certificateTypeName.
 	// +kubebuilder:validation:Minimum=1
-	CertificateType int `json:"certificateType"`
+	// +optional
+	CertificateType int `json:"certificateType,omitempty"`
+
+	// CertificateTypeName specifies the type of SSL certificate to purchase
+	// by name, e.g. PositiveSSL or EssentialSSL Wildcard
+	// +kubebuilder:validation:Enum=PositiveSSL;EssentialSSL;InstantSSL;"InstantSSL Pro";PremiumSSL;"EV SSL";"PositiveSSL Multi-Domain";"Multi-Domain SSL";"Unified Communications";"EV Multi-Domain SSL";"PositiveSSL Wildcard";"EssentialSSL Wildcard";"PremiumSSL Wildcard"
+	// +optional
+	CertificateTypeName *string `json:"certificateTypeName,omitempty"`
 
 	// Years specifies the number of years to purchase the certificate for
 	// +kubebuilder:validation:Minimum=1
@@ -29,6 +40,15 @@ type SSLCertificateParameters struct {
 	// +optional
 	SANsToAdd *string `json:"sansToAdd,omitempty"`
 
+	// SANs are the certificate's additional domains and how control of
+	// each is validated on activation, e.g. for multi-domain certificates
+	// whose SANs have approvers of their own. Unset activates the primary
+	// domain alone.
+	// +listType=map
+	// +listMapKey=domainName
+	// +optional
+	SANs []SSLCertificateSAN `json:"sans,omitempty"`
+
 	// DomainName is the primary domain name for the certificate
 	// +kubebuilder:validation:Required
 	DomainName string `json:"domainName"`
@@ -45,7 +65,11 @@ type SSLCertificateParameters struct {
 	// +optional
 	HTTPDCValidation *string `json:"httpDCValidation,omitempty"`
 
-	// DNSValidation enables DNS domain control validation
+	// DNSValidation enables DNS domain control validation. Setting or
+	// changing it once activation was requested switches a pending
+	// certificate's validation to DNS, e.g. when it is stuck on email
+	// validation, and the CNAME records to create are reported in
+	// status.atProvider.dnsValidationRecords.
 	// +optional
 	DNSValidation *string `json:"dnsValidation,omitempty"`
 
@@ -56,12 +80,58 @@ type SSLCertificateParameters struct {
 	// AutoActivate automatically activates the certificate after purchase
 	// +optional
 	AutoActivate *bool `json:"autoActivate,omitempty"`
+
+	// RenewBeforeDays renews the certificate once it expires within this
+	// many days. Namecheap renews a certificate by purchasing a new one,
+	// which replaces the renewed one in the status and external name and,
+	// with AutoActivate, is activated with the CSR. Unset never renews.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=90
+	// +optional
+	RenewBeforeDays *int `json:"renewBeforeDays,omitempty"`
+
+	// DeletionBehavior controls what happens to the certificate at Namecheap
+	// when the resource is deleted. Certificates can't be deleted through
+	// the API, so Orphan leaves the certificate untouched. The Namecheap API
+	// has no auto-renew setting for certificates, so DisableRenewals makes
+	// no API call and records an event reminding to turn off auto-renew in
+	// the dashboard, leaving the certificate to lapse at expiry.
+	// +kubebuilder:validation:Enum=Orphan;DisableRenewals
+	// +kubebuilder:default=Orphan
+	// +optional
+	DeletionBehavior *string `json:"deletionBehavior,omitempty"`
+
+	// RevokeOnDelete revokes the certificate when the resource is deleted,
+	// e.g. when its key is compromised. A revoked certificate can't be used
+	// again. The resource isn't deleted until the revocation succeeds or
+	// this is turned off. Unset leaves the certificate untouched.
+	// +optional
+	RevokeOnDelete *bool `json:"revokeOnDelete,omitempty"`
+}
+
+// SSLCertificateSAN is an additional domain of a certificate and how
+// control of it is validated.
+// +kubebuilder:validation:XValidation:rule="(has(self.validation) && self.validation != 'Email') || has(self.approverEmail)",message="approverEmail is required for Email validation"
+type SSLCertificateSAN struct {
+	// DomainName is the additional domain
+	DomainName string `json:"domainName"`
+
+	// Validation is how control of the domain is validated: by Email to
+	// the approver, by HTTP or by DNS
+	// +kubebuilder:validation:Enum=Email;HTTP;DNS
+	// +kubebuilder:default=Email
+	// +optional
+	Validation string `json:"validation,omitempty"`
+
+	// ApproverEmail is emailed to validate the domain by Email
+	// +optional
+	ApproverEmail *string `json:"approverEmail,omitempty"`
 }
 
 // SSLCertificateStatus defines the observed state of SSLCertificate
 type SSLCertificateStatus struct {
 	xpv1.ManagedResourceStatus `json:",inline"`
-	AtProvider          SSLCertificateObservation `json:"atProvider,omitempty"`
+	AtProvider                 SSLCertificateObservation `json:"atProvider,omitempty"`
 }
 
 // SSLCertificateObservation are the observable fields of an SSLCertificate.
@@ -110,6 +180,83 @@ type SSLCertificateObservation struct {
 
 	// ApproverEmailList contains valid approver email addresses
 	ApproverEmailList []string `json:"approverEmailList,omitempty"`
+
+	// DNSValidation is the spec.forProvider.dnsValidation the certificate's
+	// domain control validation was last requested with
+	DNSValidation string `json:"dnsValidation,omitempty"`
+
+	// DNSValidationRecords are the CNAME records proving control of the
+	// certificate's domains, to be created in their DNS zones
+	DNSValidationRecords []DNSValidationRecord `json:"dnsValidationRecords,omitempty"`
+
+	// LastHandledRefresh is the most recent value of the
+	// namecheap.m.crossplane.io/refresh annotation that triggered a fresh read
+	LastHandledRefresh string `json:"lastHandledRefresh,omitempty"`
+
+	// Environment is the Namecheap environment, sandbox or production, the
+	// resource was first observed in
+	Environment string `json:"environment,omitempty"`
+}
+
+// DNSValidationRecord is a CNAME record proving control of a domain to the
+// certificate authority.
+type DNSValidationRecord struct {
+	// Domain is the domain the record proves control of
+	Domain string `json:"domain"`
+
+	// HostName is the record's name
+	HostName string `json:"hostName"`
+
+	// Target is the record's value
+	Target string `json:"target"`
+}
+
+// SSLCertificate condition types and reasons.
+const (
+	// TypeActivation reports whether an SSLCertificate's activation was
+	// requested, or why it can't be.
+	TypeActivation xpv1.ConditionType = "Activation"
+
+	ReasonActivationRequested   xpv1.ConditionReason = "ActivationRequested"
+	ReasonApproverEmailRejected xpv1.ConditionReason = "ApproverEmailRejected"
+	ReasonCSRRejected           xpv1.ConditionReason = "CSRRejected"
+)
+
+// ActivationRequested returns a condition indicating the certificate's
+// activation was requested.
+func ActivationRequested() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeActivation,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonActivationRequested,
+	}
+}
+
+// ApproverEmailRejected returns a condition indicating the certificate
+// authority doesn't accept the approver email for the domain, listing the
+// addresses it accepts.
+func ApproverEmailRejected(email, domain string, accepted []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeActivation,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonApproverEmailRejected,
+		Message: "spec.forProvider.approverEmail " + email + " is not accepted for " + domain +
+			"; use one of: " + strings.Join(accepted, ", "),
+	}
+}
+
+// CSRRejected returns a condition indicating the certificate can't be
+// activated with the CSR for the supplied reason.
+func CSRRejected(message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeActivation,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCSRRejected,
+		Message:            message,
+	}
 }
 
 // +kubebuilder:object:root=true
@@ -182,4 +329,4 @@ func (mg *SSLCertificate) SetWriteConnectionSecretToReference(r *xpv1.LocalSecre
 
 func init() {
 	SchemeBuilder.Register(&SSLCertificate{}, &SSLCertificateList{})
-}
\ No newline at end of file
+}
@@ -1,6 +1,7 @@
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -28,6 +29,18 @@ var (
 	DNSRecordKindAPIVersion   = DNSRecordKind + "." + SchemeGroupVersion.String()
 	DNSRecordGroupVersionKind = SchemeGroupVersion.WithKind(DNSRecordKind)
 
+	// DNSZone
+	DNSZoneKind             = "DNSZone"
+	DNSZoneGroupKind        = schema.GroupKind{Group: Group, Kind: DNSZoneKind}.String()
+	DNSZoneKindAPIVersion   = DNSZoneKind + "." + SchemeGroupVersion.String()
+	DNSZoneGroupVersionKind = SchemeGroupVersion.WithKind(DNSZoneKind)
+
+	// DomainTransfer
+	DomainTransferKind             = "DomainTransfer"
+	DomainTransferGroupKind        = schema.GroupKind{Group: Group, Kind: DomainTransferKind}.String()
+	DomainTransferKindAPIVersion   = DomainTransferKind + "." + SchemeGroupVersion.String()
+	DomainTransferGroupVersionKind = SchemeGroupVersion.WithKind(DomainTransferKind)
+
 	// ProviderConfig
 	ProviderConfigKind             = "ProviderConfig"
 	ProviderConfigGroupKind        = schema.GroupKind{Group: Group, Kind: ProviderConfigKind}.String()
@@ -47,6 +60,20 @@ var (
 	SSLCertificateGroupVersionKind = SchemeGroupVersion.WithKind(SSLCertificateKind)
 )
 
+// Deletion behaviors of Domain and SSLCertificate resources.
+const (
+	// DeletionBehaviorOrphan leaves the external resource untouched.
+	DeletionBehaviorOrphan = "Orphan"
+
+	// DeletionBehaviorDisableRenewals stops renewals so the external
+	// resource lapses at expiry.
+	DeletionBehaviorDisableRenewals = "DisableRenewals"
+
+	// DeletionBehaviorReleaseDNS resets a domain to Namecheap's default
+	// nameservers.
+	DeletionBehaviorReleaseDNS = "ReleaseDNS"
+)
+
 // A ProviderConfigUsage indicates that a resource is using a ProviderConfig.
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="CONFIG-NAME",type="string",JSONPath=".providerConfigRef.name"
@@ -89,7 +116,71 @@ type ProviderConfigUsageList struct {
 	Items           []ProviderConfigUsage `json:"items"`
 }
 
-
 func init() {
 	SchemeBuilder.Register(&ProviderConfigUsage{}, &ProviderConfigUsageList{})
-}
\ No newline at end of file
+}
+
+const (
+	// TypeEnvironment reports whether a managed resource is reconciled
+	// against the Namecheap environment it was recorded in.
+	TypeEnvironment xpv1.ConditionType = "Environment"
+
+	ReasonEnvironmentMatched  xpv1.ConditionReason = "EnvironmentMatched"
+	ReasonEnvironmentMismatch xpv1.ConditionReason = "EnvironmentMismatch"
+)
+
+// EnvironmentMatched returns a condition indicating a managed resource is
+// reconciled against the Namecheap environment it was recorded in.
+func EnvironmentMatched() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeEnvironment,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonEnvironmentMatched,
+	}
+}
+
+// EnvironmentMismatch returns a condition indicating a managed resource's
+// ProviderConfig now uses a different Namecheap environment than the one the
+// resource was recorded in.
+func EnvironmentMismatch(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeEnvironment,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonEnvironmentMismatch,
+		Message:            msg,
+	}
+}
+
+const (
+	// TypeFunds reports whether the account's balance covered the last
+	// order of a managed resource when balances are checked
+	TypeFunds xpv1.ConditionType = "Funds"
+
+	ReasonFundsSufficient   xpv1.ConditionReason = "FundsSufficient"
+	ReasonInsufficientFunds xpv1.ConditionReason = "InsufficientFunds"
+)
+
+// FundsSufficient returns a condition indicating the account's balance
+// covered a managed resource's order.
+func FundsSufficient() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeFunds,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonFundsSufficient,
+	}
+}
+
+// InsufficientFunds returns a condition indicating a managed resource's
+// order was refused because the account's balance doesn't cover it.
+func InsufficientFunds(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeFunds,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonInsufficientFunds,
+		Message:            msg,
+	}
+}
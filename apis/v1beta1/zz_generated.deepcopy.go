@@ -21,9 +21,25 @@ limitations under the License.
 package v1beta1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CAARecord) DeepCopyInto(out *CAARecord) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CAARecord.
+func (in *CAARecord) DeepCopy() *CAARecord {
+	if in == nil {
+		return nil
+	}
+	out := new(CAARecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSRecord) DeepCopyInto(out *DNSRecord) {
 	*out = *in
@@ -109,6 +125,11 @@ func (in *DNSRecordObservation) DeepCopy() *DNSRecordObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSRecordParameters) DeepCopyInto(out *DNSRecordParameters) {
 	*out = *in
+	if in.CAA != nil {
+		in, out := &in.CAA, &out.CAA
+		*out = new(CAARecord)
+		**out = **in
+	}
 	if in.TTL != nil {
 		in, out := &in.TTL, &out.TTL
 		*out = new(int)
@@ -129,6 +150,11 @@ func (in *DNSRecordParameters) DeepCopyInto(out *DNSRecordParameters) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.StrictDelete != nil {
+		in, out := &in.StrictDelete, &out.StrictDelete
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSRecordParameters.
@@ -175,6 +201,207 @@ func (in *DNSRecordStatus) DeepCopy() *DNSRecordStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSSummary) DeepCopyInto(out *DNSSummary) {
+	*out = *in
+	if in.RecordTypes != nil {
+		in, out := &in.RecordTypes, &out.RecordTypes
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSSummary.
+func (in *DNSSummary) DeepCopy() *DNSSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSValidationRecord) DeepCopyInto(out *DNSValidationRecord) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSValidationRecord.
+func (in *DNSValidationRecord) DeepCopy() *DNSValidationRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSValidationRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSZone) DeepCopyInto(out *DNSZone) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSZone.
+func (in *DNSZone) DeepCopy() *DNSZone {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DNSZone) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSZoneList) DeepCopyInto(out *DNSZoneList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DNSZone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSZoneList.
+func (in *DNSZoneList) DeepCopy() *DNSZoneList {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSZoneList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DNSZoneList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSZoneObservation) DeepCopyInto(out *DNSZoneObservation) {
+	*out = *in
+	if in.LastSynced != nil {
+		in, out := &in.LastSynced, &out.LastSynced
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSZoneObservation.
+func (in *DNSZoneObservation) DeepCopy() *DNSZoneObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSZoneObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSZoneParameters) DeepCopyInto(out *DNSZoneParameters) {
+	*out = *in
+	if in.Records != nil {
+		in, out := &in.Records, &out.Records
+		*out = make([]DNSZoneRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EmailType != nil {
+		in, out := &in.EmailType, &out.EmailType
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSZoneParameters.
+func (in *DNSZoneParameters) DeepCopy() *DNSZoneParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSZoneParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSZoneRecord) DeepCopyInto(out *DNSZoneRecord) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int)
+		**out = **in
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSZoneRecord.
+func (in *DNSZoneRecord) DeepCopy() *DNSZoneRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSZoneRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSZoneSpec) DeepCopyInto(out *DNSZoneSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSZoneSpec.
+func (in *DNSZoneSpec) DeepCopy() *DNSZoneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSZoneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSZoneStatus) DeepCopyInto(out *DNSZoneStatus) {
+	*out = *in
+	in.ManagedResourceStatus.DeepCopyInto(&out.ManagedResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSZoneStatus.
+func (in *DNSZoneStatus) DeepCopy() *DNSZoneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSZoneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Domain) DeepCopyInto(out *Domain) {
 	*out = *in
@@ -202,6 +429,77 @@ func (in *Domain) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainContact) DeepCopyInto(out *DomainContact) {
+	*out = *in
+	if in.OrganizationName != nil {
+		in, out := &in.OrganizationName, &out.OrganizationName
+		*out = new(string)
+		**out = **in
+	}
+	if in.JobTitle != nil {
+		in, out := &in.JobTitle, &out.JobTitle
+		*out = new(string)
+		**out = **in
+	}
+	if in.Address2 != nil {
+		in, out := &in.Address2, &out.Address2
+		*out = new(string)
+		**out = **in
+	}
+	if in.PhoneExt != nil {
+		in, out := &in.PhoneExt, &out.PhoneExt
+		*out = new(string)
+		**out = **in
+	}
+	if in.Fax != nil {
+		in, out := &in.Fax, &out.Fax
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainContact.
+func (in *DomainContact) DeepCopy() *DomainContact {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainContact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainContacts) DeepCopyInto(out *DomainContacts) {
+	*out = *in
+	in.Registrant.DeepCopyInto(&out.Registrant)
+	if in.Tech != nil {
+		in, out := &in.Tech, &out.Tech
+		*out = new(DomainContact)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Admin != nil {
+		in, out := &in.Admin, &out.Admin
+		*out = new(DomainContact)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuxBilling != nil {
+		in, out := &in.AuxBilling, &out.AuxBilling
+		*out = new(DomainContact)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainContacts.
+func (in *DomainContacts) DeepCopy() *DomainContacts {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainContacts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DomainList) DeepCopyInto(out *DomainList) {
 	*out = *in
@@ -279,6 +577,10 @@ func (in *DomainObservation) DeepCopyInto(out *DomainObservation) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.WhoisGuardExpirationDate != nil {
+		in, out := &in.WhoisGuardExpirationDate, &out.WhoisGuardExpirationDate
+		*out = (*in).DeepCopy()
+	}
 	if in.IsPremium != nil {
 		in, out := &in.IsPremium, &out.IsPremium
 		*out = new(bool)
@@ -289,6 +591,46 @@ func (in *DomainObservation) DeepCopyInto(out *DomainObservation) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.AppliedNameservers != nil {
+		in, out := &in.AppliedNameservers, &out.AppliedNameservers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TransferOutPending != nil {
+		in, out := &in.TransferOutPending, &out.TransferOutPending
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RegistrarLockEnabled != nil {
+		in, out := &in.RegistrarLockEnabled, &out.RegistrarLockEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RegistryStatuses != nil {
+		in, out := &in.RegistryStatuses, &out.RegistryStatuses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastOrder != nil {
+		in, out := &in.LastOrder, &out.LastOrder
+		*out = new(DomainOrder)
+		**out = **in
+	}
+	if in.DNSSummary != nil {
+		in, out := &in.DNSSummary, &out.DNSSummary
+		*out = new(DNSSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrivacyRetry != nil {
+		in, out := &in.PrivacyRetry, &out.PrivacyRetry
+		*out = new(PrivacyRetry)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(TLDCapabilities)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainObservation.
@@ -301,14 +643,39 @@ func (in *DomainObservation) DeepCopy() *DomainObservation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainOrder) DeepCopyInto(out *DomainOrder) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainOrder.
+func (in *DomainOrder) DeepCopy() *DomainOrder {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainOrder)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DomainParameters) DeepCopyInto(out *DomainParameters) {
 	*out = *in
+	if in.IDNCode != nil {
+		in, out := &in.IDNCode, &out.IDNCode
+		*out = new(string)
+		**out = **in
+	}
 	if in.RegistrationYears != nil {
 		in, out := &in.RegistrationYears, &out.RegistrationYears
 		*out = new(int)
 		**out = **in
 	}
+	if in.Contacts != nil {
+		in, out := &in.Contacts, &out.Contacts
+		*out = new(DomainContacts)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RenewalYears != nil {
 		in, out := &in.RenewalYears, &out.RenewalYears
 		*out = new(int)
@@ -319,11 +686,26 @@ func (in *DomainParameters) DeepCopyInto(out *DomainParameters) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.UseDefaultDNS != nil {
+		in, out := &in.UseDefaultDNS, &out.UseDefaultDNS
+		*out = new(bool)
+		**out = **in
+	}
 	if in.AutoRenew != nil {
 		in, out := &in.AutoRenew, &out.AutoRenew
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ReactivateIfExpired != nil {
+		in, out := &in.ReactivateIfExpired, &out.ReactivateIfExpired
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReactivationPromoCode != nil {
+		in, out := &in.ReactivationPromoCode, &out.ReactivationPromoCode
+		*out = new(string)
+		**out = **in
+	}
 	if in.PrivacyProtection != nil {
 		in, out := &in.PrivacyProtection, &out.PrivacyProtection
 		*out = new(bool)
@@ -334,6 +716,21 @@ func (in *DomainParameters) DeepCopyInto(out *DomainParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.RegistrarLock != nil {
+		in, out := &in.RegistrarLock, &out.RegistrarLock
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DeletionBehavior != nil {
+		in, out := &in.DeletionBehavior, &out.DeletionBehavior
+		*out = new(string)
+		**out = **in
+	}
+	if in.ObserveDNSSummary != nil {
+		in, out := &in.ObserveDNSSummary, &out.ObserveDNSSummary
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainParameters.
@@ -380,6 +777,166 @@ func (in *DomainStatus) DeepCopy() *DomainStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainTransfer) DeepCopyInto(out *DomainTransfer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainTransfer.
+func (in *DomainTransfer) DeepCopy() *DomainTransfer {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainTransfer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DomainTransfer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainTransferList) DeepCopyInto(out *DomainTransferList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DomainTransfer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainTransferList.
+func (in *DomainTransferList) DeepCopy() *DomainTransferList {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainTransferList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DomainTransferList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainTransferObservation) DeepCopyInto(out *DomainTransferObservation) {
+	*out = *in
+	if in.TransferID != nil {
+		in, out := &in.TransferID, &out.TransferID
+		*out = new(int)
+		**out = **in
+	}
+	if in.StatusCode != nil {
+		in, out := &in.StatusCode, &out.StatusCode
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainTransferObservation.
+func (in *DomainTransferObservation) DeepCopy() *DomainTransferObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainTransferObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainTransferParameters) DeepCopyInto(out *DomainTransferParameters) {
+	*out = *in
+	out.EPPCodeSecretRef = in.EPPCodeSecretRef
+	if in.Years != nil {
+		in, out := &in.Years, &out.Years
+		*out = new(int)
+		**out = **in
+	}
+	if in.WhoisGuard != nil {
+		in, out := &in.WhoisGuard, &out.WhoisGuard
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainTransferParameters.
+func (in *DomainTransferParameters) DeepCopy() *DomainTransferParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainTransferParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainTransferSpec) DeepCopyInto(out *DomainTransferSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainTransferSpec.
+func (in *DomainTransferSpec) DeepCopy() *DomainTransferSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainTransferSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainTransferStatus) DeepCopyInto(out *DomainTransferStatus) {
+	*out = *in
+	in.ManagedResourceStatus.DeepCopyInto(&out.ManagedResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainTransferStatus.
+func (in *DomainTransferStatus) DeepCopy() *DomainTransferStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainTransferStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivacyRetry) DeepCopyInto(out *PrivacyRetry) {
+	*out = *in
+	in.NextAttemptTime.DeepCopyInto(&out.NextAttemptTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivacyRetry.
+func (in *PrivacyRetry) DeepCopy() *PrivacyRetry {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivacyRetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderConfig) DeepCopyInto(out *ProviderConfig) {
 	*out = *in
@@ -453,6 +1010,26 @@ func (in *ProviderConfigSpec) DeepCopyInto(out *ProviderConfigSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.PollInterval != nil {
+		in, out := &in.PollInterval, &out.PollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SyncInterval != nil {
+		in, out := &in.SyncInterval, &out.SyncInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RegistrationGracePeriod != nil {
+		in, out := &in.RegistrationGracePeriod, &out.RegistrationGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.CheckBalance != nil {
+		in, out := &in.CheckBalance, &out.CheckBalance
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigSpec.
@@ -694,6 +1271,11 @@ func (in *SSLCertificateObservation) DeepCopyInto(out *SSLCertificateObservation
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DNSValidationRecords != nil {
+		in, out := &in.DNSValidationRecords, &out.DNSValidationRecords
+		*out = make([]DNSValidationRecord, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSLCertificateObservation.
@@ -709,6 +1291,11 @@ func (in *SSLCertificateObservation) DeepCopy() *SSLCertificateObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SSLCertificateParameters) DeepCopyInto(out *SSLCertificateParameters) {
 	*out = *in
+	if in.CertificateTypeName != nil {
+		in, out := &in.CertificateTypeName, &out.CertificateTypeName
+		*out = new(string)
+		**out = **in
+	}
 	if in.Years != nil {
 		in, out := &in.Years, &out.Years
 		*out = new(int)
@@ -719,6 +1306,13 @@ func (in *SSLCertificateParameters) DeepCopyInto(out *SSLCertificateParameters)
 		*out = new(string)
 		**out = **in
 	}
+	if in.SANs != nil {
+		in, out := &in.SANs, &out.SANs
+		*out = make([]SSLCertificateSAN, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.CSR != nil {
 		in, out := &in.CSR, &out.CSR
 		*out = new(string)
@@ -749,6 +1343,21 @@ func (in *SSLCertificateParameters) DeepCopyInto(out *SSLCertificateParameters)
 		*out = new(bool)
 		**out = **in
 	}
+	if in.RenewBeforeDays != nil {
+		in, out := &in.RenewBeforeDays, &out.RenewBeforeDays
+		*out = new(int)
+		**out = **in
+	}
+	if in.DeletionBehavior != nil {
+		in, out := &in.DeletionBehavior, &out.DeletionBehavior
+		*out = new(string)
+		**out = **in
+	}
+	if in.RevokeOnDelete != nil {
+		in, out := &in.RevokeOnDelete, &out.RevokeOnDelete
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSLCertificateParameters.
@@ -761,6 +1370,26 @@ func (in *SSLCertificateParameters) DeepCopy() *SSLCertificateParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSLCertificateSAN) DeepCopyInto(out *SSLCertificateSAN) {
+	*out = *in
+	if in.ApproverEmail != nil {
+		in, out := &in.ApproverEmail, &out.ApproverEmail
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSLCertificateSAN.
+func (in *SSLCertificateSAN) DeepCopy() *SSLCertificateSAN {
+	if in == nil {
+		return nil
+	}
+	out := new(SSLCertificateSAN)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SSLCertificateSpec) DeepCopyInto(out *SSLCertificateSpec) {
 	*out = *in
@@ -794,3 +1423,43 @@ func (in *SSLCertificateStatus) DeepCopy() *SSLCertificateStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLDCapabilities) DeepCopyInto(out *TLDCapabilities) {
+	*out = *in
+	if in.RegistrationYears != nil {
+		in, out := &in.RegistrationYears, &out.RegistrationYears
+		*out = new(YearRange)
+		**out = **in
+	}
+	if in.RenewalYears != nil {
+		in, out := &in.RenewalYears, &out.RenewalYears
+		*out = new(YearRange)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLDCapabilities.
+func (in *TLDCapabilities) DeepCopy() *TLDCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(TLDCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *YearRange) DeepCopyInto(out *YearRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new YearRange.
+func (in *YearRange) DeepCopy() *YearRange {
+	if in == nil {
+		return nil
+	}
+	out := new(YearRange)
+	in.DeepCopyInto(out)
+	return out
+}
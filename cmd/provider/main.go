@@ -12,6 +12,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
@@ -21,23 +22,27 @@ import (
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 
 	"github.com/rossigee/provider-namecheap/apis"
-	"github.com/rossigee/provider-namecheap/internal/controller/domain"
-	"github.com/rossigee/provider-namecheap/internal/controller/dnsrecord"
-	"github.com/rossigee/provider-namecheap/internal/controller/sslcertificate"
+	namecheapcontroller "github.com/rossigee/provider-namecheap/internal/controller"
+	"github.com/rossigee/provider-namecheap/internal/controller/common"
+	"github.com/rossigee/provider-namecheap/internal/supportbundle"
 	"github.com/rossigee/provider-namecheap/internal/version"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
 )
 
 func main() {
 	var (
-		app                     = kingpin.New(filepath.Base(os.Args[0]), "Crossplane provider for Namecheap").DefaultEnvars()
-		debug                   = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
-		syncInterval            = app.Flag("sync", "Sync interval controls how often all resources will be double checked for drift.").Short('s').Default("1h").Duration()
-		pollInterval            = app.Flag("poll", "Poll interval controls how often an individual resource should be checked for drift.").Default("1m").Duration()
-		leaderElection          = app.Flag("leader-election", "Use leader election for the controller manager.").Short('l').Default("false").Bool()
-		maxReconcileRate        = app.Flag("max-reconcile-rate", "The global maximum rate per second at which resources may checked for drift from the desired state.").Default("100").Int()
-		namespace               = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").String()
+		app                        = kingpin.New(filepath.Base(os.Args[0]), "Crossplane provider for Namecheap").DefaultEnvars()
+		debug                      = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
+		syncInterval               = app.Flag("sync", "Sync interval controls how often all resources will be double checked for drift.").Short('s').Default("1h").Duration()
+		pollInterval               = app.Flag("poll", "Poll interval controls how often an individual resource should be checked for drift.").Default("1m").Duration()
+		leaderElection             = app.Flag("leader-election", "Use leader election for the controller manager.").Short('l').Default("false").Bool()
+		maxReconcileRate           = app.Flag("max-reconcile-rate", "The global maximum rate per second at which resources may checked for drift from the desired state.").Default("100").Int()
+		namespace                  = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").String()
 		enableExternalSecretStores = app.Flag("enable-external-secret-stores", "Enable support for external secret stores.").Default("false").Bool()
 		enableManagementPolicies   = app.Flag("enable-management-policies", "Enable support for Management Policies.").Default("true").Bool()
+		enableSupportBundle        = app.Flag("enable-support-bundle", "Serve a sanitized support bundle at "+supportbundle.Path+" on the metrics server.").Default("false").Bool()
+		maxDriftSuspension         = app.Flag("max-drift-suspension", "Longest time ahead that the "+common.AnnotationKeySuspendUntil+" annotation may suspend drift enforcement.").Default(common.DefaultMaxDriftSuspension.String()).Duration()
+		domainWriteInterval        = app.Flag("domain-write-interval", "Minimum interval between writes to the same domain's DNS host records or nameservers; faster writes are queued. Zero disables the limit.").Default(namecheap.DefaultDomainWriteInterval.String()).Duration()
 	)
 
 	kingpin.MustParse(app.Parse(os.Args[1:]))
@@ -61,6 +66,7 @@ func main() {
 		"namespace", *namespace,
 		"external-secret-stores", *enableExternalSecretStores,
 		"management-policies", *enableManagementPolicies,
+		"max-drift-suspension", maxDriftSuspension.String(),
 		"debug-mode", *debug)
 
 	cfg, err := ctrl.GetConfig()
@@ -73,9 +79,9 @@ func main() {
 	}
 
 	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
-		LeaderElection:          *leaderElection,
-		LeaderElectionID:        "crossplane-leader-election-provider-namecheap",
-		LeaderElectionNamespace: leaderElectionNamespace,
+		LeaderElection:             *leaderElection,
+		LeaderElectionID:           "crossplane-leader-election-provider-namecheap",
+		LeaderElectionNamespace:    leaderElectionNamespace,
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		Cache: cache.Options{
 			SyncPeriod: syncInterval,
@@ -109,14 +115,36 @@ func main() {
 	}
 
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Namecheap APIs to scheme")
+	kingpin.FatalIfError(namecheap.RegisterMetrics(metrics.Registry), "Cannot register Namecheap API metrics")
 
-	kingpin.FatalIfError(domain.Setup(mgr, o), "Cannot setup Domain controller")
-	kingpin.FatalIfError(dnsrecord.Setup(mgr, o), "Cannot setup DNSRecord controller")
-	kingpin.FatalIfError(sslcertificate.Setup(mgr, o), "Cannot setup SSLCertificate controller")
+	common.MaxDriftSuspension = *maxDriftSuspension
+	common.DomainWriteInterval = *domainWriteInterval
+	if common.DomainWriteInterval == 0 {
+		// The client takes a zero interval as the default
+		common.DomainWriteInterval = -1
+	}
+
+	kingpin.FatalIfError(namecheapcontroller.Setup(mgr, o), "Cannot setup Namecheap controllers")
+	log.Info("Controllers registered", "kinds", namecheapcontroller.Kinds())
+
+	if *enableSupportBundle {
+		flags := map[string]string{}
+		for _, f := range app.Model().Flags {
+			flags[f.Name] = f.Value.String()
+		}
+		bundle := supportbundle.NewGenerator(mgr.GetCache(),
+			supportbundle.WithFlags(flags),
+			supportbundle.WithClients(common.Clients),
+			supportbundle.WithCacheStats(namecheap.ResponseCacheStats),
+			supportbundle.WithAuditLog(namecheap.RecentAuditEntries),
+			supportbundle.WithLogger(log))
+		kingpin.FatalIfError(mgr.AddMetricsServerExtraHandler(supportbundle.Path, bundle), "Cannot add support bundle handler")
+		log.Info("Support bundle enabled", "path", supportbundle.Path)
+	}
 
 	kingpin.FatalIfError(mgr.AddHealthzCheck("healthz", healthz.Ping), "Cannot add health check")
 	kingpin.FatalIfError(mgr.AddReadyzCheck("readyz", healthz.Ping), "Cannot add ready check")
 
 	ctx := ctrl.SetupSignalHandler()
 	kingpin.FatalIfError(mgr.Start(ctx), "Cannot start controller manager")
-}
\ No newline at end of file
+}
@@ -0,0 +1,45 @@
+// Package namecheap aliases the core of the Namecheap API client while code
+// moves to its public home.
+//
+// Deprecated: import github.com/rossigee/provider-namecheap/pkg/namecheap
+// instead. This package will be removed once no branch depends on it.
+package namecheap
+
+import "github.com/rossigee/provider-namecheap/pkg/namecheap"
+
+// Client types.
+type (
+	API         = namecheap.API
+	Client      = namecheap.Client
+	Config      = namecheap.Config
+	Credentials = namecheap.Credentials
+	Secret      = namecheap.Secret
+	Error       = namecheap.Error
+	HTTPError   = namecheap.HTTPError
+)
+
+// Resource types.
+type (
+	Domain         = namecheap.Domain
+	DNSHosts       = namecheap.DNSHosts
+	DNSRecord      = namecheap.DNSRecord
+	SSLCertificate = namecheap.SSLCertificate
+	Transfer       = namecheap.Transfer
+	WhoisGuard     = namecheap.WhoisGuard
+)
+
+// Namecheap API environments.
+const (
+	EnvironmentSandbox    = namecheap.EnvironmentSandbox
+	EnvironmentProduction = namecheap.EnvironmentProduction
+)
+
+// Constructors and helpers.
+var (
+	NewClient            = namecheap.NewClient
+	ParseCredentials     = namecheap.ParseCredentials
+	WithResource         = namecheap.WithResource
+	WithFreshRead        = namecheap.WithFreshRead
+	IsDomainNotInAccount = namecheap.IsDomainNotInAccount
+	ErrDNSRecordNotFound = namecheap.ErrDNSRecordNotFound
+)
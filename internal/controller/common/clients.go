@@ -0,0 +1,44 @@
+package common
+
+import (
+	"sync"
+
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+// ClientRegistry holds the Namecheap client most recently built for each
+// ProviderConfig, so that the state of its rate limiter and circuit breaker
+// can be reported in support bundles.
+type ClientRegistry struct {
+	mu      sync.Mutex
+	clients map[string]*namecheap.Client
+}
+
+// NewClientRegistry returns an empty ClientRegistry.
+func NewClientRegistry() *ClientRegistry {
+	return &ClientRegistry{clients: map[string]*namecheap.Client{}}
+}
+
+// Clients is the registry the controllers record their clients in.
+var Clients = NewClientRegistry()
+
+// Track records client as the most recent built for the ProviderConfig named
+// pc.
+func (r *ClientRegistry) Track(pc string, client *namecheap.Client) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clients[pc] = client
+}
+
+// Clients returns the client most recently built for each ProviderConfig, by
+// ProviderConfig name.
+func (r *ClientRegistry) Clients() map[string]*namecheap.Client {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make(map[string]*namecheap.Client, len(r.clients))
+	for pc, client := range r.clients {
+		out[pc] = client
+	}
+	return out
+}
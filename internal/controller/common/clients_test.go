@@ -0,0 +1,31 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+func TestClientRegistry(t *testing.T) {
+	r := NewClientRegistry()
+	assert.Empty(t, r.Clients())
+
+	first := namecheap.NewClient(namecheap.Config{APIUser: "testuser"})
+	second := namecheap.NewClient(namecheap.Config{APIUser: "testuser"})
+	other := namecheap.NewClient(namecheap.Config{APIUser: "otheruser"})
+	r.Track("default", first)
+	r.Track("other", other)
+
+	// The most recent client of each ProviderConfig is kept
+	r.Track("default", second)
+	clients := r.Clients()
+	assert.Len(t, clients, 2)
+	assert.Same(t, second, clients["default"])
+	assert.Same(t, other, clients["other"])
+
+	// The returned map is a copy
+	delete(clients, "other")
+	assert.Len(t, r.Clients(), 2)
+}
@@ -0,0 +1,29 @@
+package common
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+)
+
+// RecordCredentialsRevision logs the revision of the credentials a client is
+// being built from and records it in the ProviderConfig's status when it has
+// changed. Only the revision, never the credentials, is logged. Failing to
+// record it is logged rather than returned so that it cannot block
+// reconciling the managed resource.
+func RecordCredentialsRevision(ctx context.Context, kube client.Client, log logging.Logger, pc *v1beta1.ProviderConfig, revision string) {
+	log.Debug("Connecting with credentials", "providerConfig", pc.GetName(), "credentialsRevision", revision)
+	if pc.Status.CredentialsRevision == revision {
+		return
+	}
+
+	patch := client.MergeFrom(pc.DeepCopy())
+	pc.Status.CredentialsRevision = revision
+	if err := kube.Status().Patch(ctx, pc, patch); err != nil {
+		log.Debug("Cannot record credentials revision", "providerConfig", pc.GetName(), "error", err)
+	}
+}
@@ -0,0 +1,75 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+// recordingLogger records every message and key/value pair it is given.
+type recordingLogger struct {
+	lines *[]string
+}
+
+func (l recordingLogger) Info(msg string, keysAndValues ...any) {
+	*l.lines = append(*l.lines, fmt.Sprintf("%s %v", msg, keysAndValues))
+}
+
+func (l recordingLogger) Debug(msg string, keysAndValues ...any) {
+	*l.lines = append(*l.lines, fmt.Sprintf("%s %v", msg, keysAndValues))
+}
+
+func (l recordingLogger) WithValues(keysAndValues ...any) logging.Logger {
+	*l.lines = append(*l.lines, fmt.Sprintf("%v", keysAndValues))
+	return l
+}
+
+func TestRecordCredentialsRevision(t *testing.T) {
+	const apiKey = "super-secret-api-key"
+	data := []byte(`{"api_user":"testuser","api_key":"` + apiKey + `","username":"testuser","client_ip":"127.0.0.1"}`)
+	revision := namecheap.CredentialsRevision(data)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	pc := &v1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pc).WithStatusSubresource(pc).Build()
+
+	var lines []string
+	log := recordingLogger{lines: &lines}
+
+	// Parsing zeroes the payload, as Connect does once the revision is taken
+	_, err := namecheap.ParseCredentials(data)
+	require.NoError(t, err)
+
+	RecordCredentialsRevision(context.Background(), kube, log, pc, revision)
+
+	got := &v1beta1.ProviderConfig{}
+	require.NoError(t, kube.Get(context.Background(), client.ObjectKeyFromObject(pc), got))
+	assert.Equal(t, revision, got.Status.CredentialsRevision)
+
+	// The revision is logged, the credentials are not
+	require.NotEmpty(t, lines)
+	assert.Contains(t, lines[0], revision)
+	for _, line := range lines {
+		assert.NotContains(t, line, apiKey)
+		assert.NotContains(t, line, "api_key")
+	}
+
+	// An unchanged revision is not written again
+	rv := got.GetResourceVersion()
+	RecordCredentialsRevision(context.Background(), kube, log, got, revision)
+	require.NoError(t, kube.Get(context.Background(), client.ObjectKeyFromObject(pc), got))
+	assert.Equal(t, rv, got.GetResourceVersion())
+}
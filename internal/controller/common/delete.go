@@ -0,0 +1,25 @@
+package common
+
+import (
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationKeyForceDeleteAfterFailures is an escape hatch for resources
+// whose external deletion keeps failing. Once the number of failed deletion
+// attempts reaches the annotation's value, the provider stops calling the
+// Namecheap API and lets the resource be deleted, leaving any external
+// resource in place.
+const AnnotationKeyForceDeleteAfterFailures = "namecheap.m.crossplane.io/force-delete-after-failures"
+
+// ForceDelete reports whether deletion of o should skip the external API
+// after the supplied number of failed deletion attempts. Missing, malformed
+// and non-positive annotation values never force deletion.
+func ForceDelete(o metav1.Object, failures int) bool {
+	threshold, err := strconv.Atoi(o.GetAnnotations()[AnnotationKeyForceDeleteAfterFailures])
+	if err != nil || threshold < 1 {
+		return false
+	}
+	return failures >= threshold
+}
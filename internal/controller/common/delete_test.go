@@ -0,0 +1,34 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestForceDelete(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		failures   int
+		expected   bool
+	}{
+		{name: "no annotation", failures: 10},
+		{name: "malformed", annotation: "three", failures: 10},
+		{name: "zero", annotation: "0", failures: 10},
+		{name: "below threshold", annotation: "3", failures: 2},
+		{name: "at threshold", annotation: "3", failures: 3, expected: true},
+		{name: "above threshold", annotation: "3", failures: 5, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &metav1.ObjectMeta{}
+			if tt.annotation != "" {
+				o.Annotations = map[string]string{AnnotationKeyForceDeleteAfterFailures: tt.annotation}
+			}
+			assert.Equal(t, tt.expected, ForceDelete(o, tt.failures))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package common
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+)
+
+// ReasonDriftDetected is the reason of the events recorded when a managed
+// resource's external resource has drifted from its spec
+const ReasonDriftDetected event.Reason = "DriftDetected"
+
+// Drift is a field of an external resource whose observed value differs
+// from the expected one
+type Drift struct {
+	// Field names the drifted field, e.g. spec.forProvider.nameservers
+	Field string
+
+	// Expected is the value the spec asks for
+	Expected string
+
+	// Observed is the value Namecheap reports
+	Observed string
+}
+
+// DriftEvents records a Warning event for each drifted field of a managed
+// resource, at most once per interval for the same resource and field, so
+// that drift that persists across observations doesn't cause an event storm.
+// It is shared by every reconcile of a controller.
+type DriftEvents struct {
+	recorder event.Recorder
+	interval time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	recorded map[driftKey]time.Time
+}
+
+type driftKey struct {
+	uid   types.UID
+	field string
+}
+
+// NewDriftEvents returns DriftEvents that record events with recorder, at
+// most once per interval for the same resource and field
+func NewDriftEvents(recorder event.Recorder, interval time.Duration) *DriftEvents {
+	return &DriftEvents{
+		recorder: recorder,
+		interval: interval,
+		now:      time.Now,
+		recorded: map[driftKey]time.Time{},
+	}
+}
+
+// Record records an event for each drift of mg that hasn't had one in the
+// last interval. correction says whether and how the drift is corrected.
+func (d *DriftEvents) Record(mg resource.Managed, correction string, drifts ...Drift) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	for key, at := range d.recorded {
+		if now.Sub(at) >= d.interval {
+			delete(d.recorded, key)
+		}
+	}
+
+	for _, drift := range drifts {
+		key := driftKey{uid: mg.GetUID(), field: drift.Field}
+		if _, ok := d.recorded[key]; ok {
+			continue
+		}
+		d.recorded[key] = now
+		d.recorder.Event(mg, event.Warning(ReasonDriftDetected, errors.Errorf("%s drifted: expected %q, observed %q; %s",
+			drift.Field, drift.Expected, drift.Observed, correction)))
+	}
+}
+
+// DriftCorrection describes whether the provider corrects the drift of mg,
+// which it doesn't while drift enforcement is suspended or when mg's
+// management policies don't allow updates
+func DriftCorrection(mg resource.Managed, suspended bool) string {
+	if suspended {
+		return "not correcting it while drift enforcement is suspended"
+	}
+	// No management policies means the default policy, which allows updates
+	policies := mg.GetManagementPolicies()
+	if len(policies) == 0 {
+		return "correcting it"
+	}
+	for _, action := range policies {
+		if action == xpv1.ManagementActionAll || action == xpv1.ManagementActionUpdate {
+			return "correcting it"
+		}
+	}
+	return "not correcting it as the management policies don't allow updates"
+}
@@ -0,0 +1,91 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+)
+
+// recorder captures recorded events.
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+func TestDriftEvents(t *testing.T) {
+	rec := &recorder{}
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	d := NewDriftEvents(rec, time.Minute)
+	d.now = func() time.Time { return now }
+
+	a := &v1beta1.Domain{}
+	a.SetUID("a")
+	b := &v1beta1.Domain{}
+	b.SetUID("b")
+	nameservers := Drift{Field: "spec.forProvider.nameservers", Expected: "ns1.example.org", Observed: "ns1.example.net"}
+	privacy := Drift{Field: "spec.forProvider.privacyProtection", Expected: "true", Observed: "false"}
+
+	d.Record(a, "correcting it", nameservers)
+	require.Len(t, rec.events, 1)
+	assert.Equal(t, event.TypeWarning, rec.events[0].Type)
+	assert.Equal(t, ReasonDriftDetected, rec.events[0].Reason)
+	assert.Equal(t, `spec.forProvider.nameservers drifted: expected "ns1.example.org", observed "ns1.example.net"; correcting it`,
+		rec.events[0].Message)
+
+	// Within the interval only other fields and other resources are reported
+	now = now.Add(59 * time.Second)
+	d.Record(a, "correcting it", nameservers, privacy)
+	d.Record(b, "correcting it", nameservers)
+	assert.Len(t, rec.events, 3)
+
+	// Once the interval has passed the drift is reported again
+	now = now.Add(time.Second)
+	d.Record(a, "correcting it", nameservers)
+	assert.Len(t, rec.events, 4)
+}
+
+func TestDriftCorrection(t *testing.T) {
+	tests := []struct {
+		name      string
+		policies  xpv1.ManagementPolicies
+		suspended bool
+		want      string
+	}{
+		{name: "default", want: "correcting it"},
+		{name: "all", policies: xpv1.ManagementPolicies{xpv1.ManagementActionAll}, want: "correcting it"},
+		{
+			name:     "update",
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionUpdate},
+			want:     "correcting it",
+		},
+		{
+			name:     "observe only",
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+			want:     "not correcting it as the management policies don't allow updates",
+		},
+		{name: "suspended", suspended: true, want: "not correcting it while drift enforcement is suspended"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &v1beta1.DNSRecord{}
+			cr.SetManagementPolicies(tt.policies)
+			assert.Equal(t, tt.want, DriftCorrection(cr, tt.suspended))
+		})
+	}
+}
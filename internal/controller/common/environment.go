@@ -0,0 +1,26 @@
+package common
+
+import (
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationKeyMigrateEnvironment allows a managed resource recorded in one
+// Namecheap environment to be reconciled against another. Its value must name
+// the environment being migrated to, e.g. "production".
+const AnnotationKeyMigrateEnvironment = "namecheap.m.crossplane.io/migrate-environment"
+
+// CheckEnvironment returns an error if a managed resource whose environment
+// was recorded as recorded is now being reconciled against current, unless
+// the migrate annotation names current. Resources with no recorded
+// environment may be reconciled against any environment.
+func CheckEnvironment(o metav1.Object, recorded, current string) error {
+	if recorded == "" || recorded == current {
+		return nil
+	}
+	if o.GetAnnotations()[AnnotationKeyMigrateEnvironment] == current {
+		return nil
+	}
+	return errors.Errorf("resource was recorded in the Namecheap %s environment but its ProviderConfig now uses %s; "+
+		"set the %s annotation to %q to migrate it", recorded, current, AnnotationKeyMigrateEnvironment, current)
+}
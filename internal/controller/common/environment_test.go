@@ -0,0 +1,47 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckEnvironment(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		recorded    string
+		current     string
+		wantErr     bool
+	}{
+		{name: "not yet recorded", current: "production"},
+		{name: "same environment", recorded: "sandbox", current: "sandbox"},
+		{name: "mismatch", recorded: "sandbox", current: "production", wantErr: true},
+		{
+			name:        "migration to current environment",
+			annotations: map[string]string{AnnotationKeyMigrateEnvironment: "production"},
+			recorded:    "sandbox",
+			current:     "production",
+		},
+		{
+			name:        "migration to another environment",
+			annotations: map[string]string{AnnotationKeyMigrateEnvironment: "sandbox"},
+			recorded:    "production",
+			current:     "staging",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckEnvironment(&metav1.ObjectMeta{Annotations: tt.annotations}, tt.recorded, tt.current)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), AnnotationKeyMigrateEnvironment)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package common
+
+import (
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+)
+
+// WithManagementPolicies enables management policies on a reconciler when
+// the feature is enabled in o. When it is disabled the reconciler rejects
+// resources that set non-default management policies, rather than silently
+// ignoring them.
+func WithManagementPolicies(o controller.Options) managed.ReconcilerOption {
+	return func(r *managed.Reconciler) {
+		if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+			managed.WithManagementPolicies()(r)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+)
+
+// fakeManager provides the client and scheme a managed reconciler needs.
+type fakeManager struct {
+	manager.Manager
+	client client.Client
+}
+
+func (m *fakeManager) GetClient() client.Client {
+	return m.client
+}
+
+func (m *fakeManager) GetScheme() *runtime.Scheme {
+	return m.client.Scheme()
+}
+
+func TestWithManagementPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		synced  corev1.ConditionStatus
+	}{
+		{name: "Enabled", enabled: true, synced: corev1.ConditionTrue},
+		{name: "Disabled", enabled: false, synced: corev1.ConditionFalse},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, v1beta1.AddToScheme(scheme))
+
+			// An observe-only Domain whose external resource is up to date
+			cr := &v1beta1.Domain{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+				Spec: v1beta1.DomainSpec{
+					ManagedResourceSpec: xpv1.ManagedResourceSpec{
+						ManagementPolicies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+					},
+					ForProvider: v1beta1.DomainParameters{DomainName: "example.com"},
+				},
+			}
+			kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cr).WithStatusSubresource(cr).Build()
+
+			o := controller.Options{Features: &feature.Flags{}}
+			if tc.enabled {
+				o.Features.Enable(feature.EnableBetaManagementPolicies)
+			}
+
+			observed := 0
+			connector := managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+				return managed.ExternalClientFns{
+					ObserveFn: func(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+						observed++
+						return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+					},
+					DisconnectFn: func(context.Context) error { return nil },
+				}, nil
+			})
+
+			r := managed.NewReconciler(&fakeManager{client: kube},
+				resource.ManagedKind(v1beta1.DomainGroupVersionKind),
+				managed.WithExternalConnector(connector),
+				WithManagementPolicies(o))
+
+			_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+			require.NoError(t, err)
+
+			got := &v1beta1.Domain{}
+			require.NoError(t, kube.Get(context.Background(), client.ObjectKeyFromObject(cr), got))
+			synced := got.GetCondition(xpv1.TypeSynced)
+			assert.Equal(t, tc.synced, synced.Status, synced.Message)
+
+			if tc.enabled {
+				assert.Equal(t, 1, observed)
+			} else {
+				// The policy is rejected before the external resource is
+				// touched
+				assert.Zero(t, observed)
+				assert.Contains(t, synced.Message, "managementPolicies")
+			}
+		})
+	}
+}
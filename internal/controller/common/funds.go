@@ -0,0 +1,67 @@
+package common
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+)
+
+// ReasonInsufficientFunds is the reason of the events recorded when an order
+// is refused because the account's balance doesn't cover it
+const ReasonInsufficientFunds event.Reason = "InsufficientFunds"
+
+// annotationKeyInsufficientFunds records an order refused for insufficient
+// funds. The Funds condition is lost when Create refuses the order, as the
+// managed reconciler discards the status of a failed Create.
+const annotationKeyInsufficientFunds = "namecheap.m.crossplane.io/insufficient-funds"
+
+// fundsRefusal is the record of an order refused for insufficient funds
+type fundsRefusal struct {
+	Generation int64  `json:"generation"`
+	Message    string `json:"message"`
+}
+
+// RecordInsufficientFunds records that an order of mg was refused because
+// the account's balance doesn't cover it, in mg's Funds condition, a record
+// annotation and a Warning event. Both record the generation of mg's spec,
+// so that CheckFunds refuses the order until the spec changes.
+func RecordInsufficientFunds(mg resource.Managed, recorder event.Recorder, err error) {
+	mg.SetConditions(v1beta1.InsufficientFunds(err.Error()).WithObservedGeneration(mg.GetGeneration()))
+	if rerr := SetRecord(mg, annotationKeyInsufficientFunds, fundsRefusal{Generation: mg.GetGeneration(), Message: err.Error()}); rerr != nil {
+		recorder.Event(mg, event.Warning(ReasonInsufficientFunds, rerr))
+	}
+	recorder.Event(mg, event.Warning(ReasonInsufficientFunds, err))
+}
+
+// CheckFunds returns an error if an order of mg was refused for insufficient
+// funds at the current generation of its spec. Orders aren't attempted again
+// until the spec changes, as every attempt costs rate-limited requests and
+// adding funds doesn't change the resource.
+func CheckFunds(mg resource.Managed) error {
+	if cond := mg.GetCondition(v1beta1.TypeFunds); cond.Reason == v1beta1.ReasonInsufficientFunds &&
+		cond.ObservedGeneration == mg.GetGeneration() {
+		return errors.Errorf("%s; change the spec to place the order again", cond.Message)
+	}
+
+	// A record that can't be read doesn't refuse the order, which the
+	// balance check then refuses again if it still isn't covered
+	refusal := fundsRefusal{}
+	if ok, err := GetRecord(mg, annotationKeyInsufficientFunds, &refusal); err == nil && ok &&
+		refusal.Generation == mg.GetGeneration() {
+		return errors.Errorf("%s; change the spec to place the order again", refusal.Message)
+	}
+	return nil
+}
+
+// RecordFundsSufficient marks the Funds condition of mg as sufficient once
+// an order is placed, if an earlier order was refused
+func RecordFundsSufficient(mg resource.Managed) {
+	meta.RemoveAnnotations(mg, annotationKeyInsufficientFunds)
+	if mg.GetCondition(v1beta1.TypeFunds).Reason == v1beta1.ReasonInsufficientFunds {
+		mg.SetConditions(v1beta1.FundsSufficient())
+	}
+}
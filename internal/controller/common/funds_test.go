@@ -0,0 +1,57 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+)
+
+func TestFunds(t *testing.T) {
+	rec := &recorder{}
+	cr := &v1beta1.SSLCertificate{}
+	cr.SetGeneration(3)
+
+	// Nothing is refused until an order is
+	require.NoError(t, CheckFunds(cr))
+	RecordFundsSufficient(cr)
+	assert.Empty(t, cr.Status.Conditions, "no condition is added for accounts that never ran short")
+
+	RecordInsufficientFunds(cr, rec, errors.New("insufficient funds"))
+	require.Len(t, rec.events, 1)
+	assert.Equal(t, event.TypeWarning, rec.events[0].Type)
+	assert.Equal(t, ReasonInsufficientFunds, rec.events[0].Reason)
+	cond := cr.GetCondition(v1beta1.TypeFunds)
+	assert.Equal(t, v1beta1.ReasonInsufficientFunds, cond.Reason)
+	assert.Equal(t, int64(3), cond.ObservedGeneration)
+
+	// The order is refused until the spec changes
+	assert.EqualError(t, CheckFunds(cr), "insufficient funds; change the spec to place the order again")
+	cr.SetGeneration(4)
+	assert.NoError(t, CheckFunds(cr))
+
+	RecordFundsSufficient(cr)
+	assert.Equal(t, v1beta1.ReasonFundsSufficient, cr.GetCondition(v1beta1.TypeFunds).Reason)
+	assert.Empty(t, cr.GetAnnotations())
+}
+
+func TestFunds_StatusLost(t *testing.T) {
+	cr := &v1beta1.Domain{}
+	cr.SetGeneration(3)
+
+	// The record refuses the order once the status Create set is discarded
+	RecordInsufficientFunds(cr, &recorder{}, errors.New("insufficient funds"))
+	cr.Status = v1beta1.DomainStatus{}
+	assert.EqualError(t, CheckFunds(cr), "insufficient funds; change the spec to place the order again")
+	cr.SetGeneration(4)
+	assert.NoError(t, CheckFunds(cr))
+
+	RecordFundsSufficient(cr)
+	cr.SetGeneration(3)
+	assert.NoError(t, CheckFunds(cr), "a placed order clears the record")
+}
@@ -0,0 +1,145 @@
+package common
+
+import (
+	"net"
+	"strings"
+)
+
+// A Normalizer returns a record value in the form Namecheap stores it, as far
+// as that can be predicted. Two values are equivalent when they normalize to
+// the same form.
+type Normalizer func(value string) string
+
+// normalizers are the type-appropriate normalizers of record values, by
+// record type. Values of types without one are compared exactly.
+var normalizers = map[string]Normalizer{
+	"A":     normalizeIP,
+	"AAAA":  normalizeIP,
+	"ALIAS": NormalizeHostname,
+	"CNAME": NormalizeHostname,
+	"MX":    NormalizeHostname,
+	"MXE":   normalizeIP,
+	"NS":    NormalizeHostname,
+	"PTR":   NormalizeHostname,
+	"TXT":   normalizeText,
+	"SRV":   normalizeSRV,
+	"CAA":   normalizeCAA,
+
+	// Redirect targets are URLs whose paths and queries may be
+	// case-sensitive, so they are compared exactly
+	"URL":    exact,
+	"URL301": exact,
+	"FRAME":  exact,
+}
+
+// ValueNormalizer returns the Normalizer of the values of records of
+// recordType in the given value-compare mode. Exact comparison doesn't
+// normalize, and relaxed comparison normalizes everything that Namecheap may
+// normalize on top of the type-appropriate normalization.
+func ValueNormalizer(recordType, mode string) Normalizer {
+	if mode == ValueCompareExact {
+		return exact
+	}
+	normalize, ok := normalizers[strings.ToUpper(recordType)]
+	if !ok {
+		normalize = exact
+	}
+	if mode == ValueCompareRelaxed {
+		return func(value string) string {
+			return normalizeRelaxed(normalize(value))
+		}
+	}
+	return normalize
+}
+
+// writeNormalizers normalize record values before they are written, by record
+// type, so that they are stored in the form they are compared in and an
+// equivalent spec value isn't rewritten as drift. TXT values are only trimmed,
+// as the whitespace within them may be significant. Values of other types are
+// written as they are.
+var writeNormalizers = map[string]Normalizer{
+	"A":     normalizeIP,
+	"AAAA":  normalizeIP,
+	"MXE":   normalizeIP,
+	"ALIAS": NormalizeHostname,
+	"CNAME": NormalizeHostname,
+	"MX":    NormalizeHostname,
+	"NS":    NormalizeHostname,
+	"PTR":   NormalizeHostname,
+	"TXT":   strings.TrimSpace,
+}
+
+// WriteNormalizer returns the Normalizer of the values of records of
+// recordType before they are written. Values compared exactly are written
+// exactly as given.
+func WriteNormalizer(recordType, mode string) Normalizer {
+	normalize, ok := writeNormalizers[strings.ToUpper(recordType)]
+	if !ok || mode == ValueCompareExact {
+		return exact
+	}
+	return normalize
+}
+
+// exact leaves a value as is.
+func exact(value string) string {
+	return value
+}
+
+// normalizeIP returns an IP address in canonical form, e.g. with the zeros of
+// an IPv6 address compressed. Values that aren't IP addresses are trimmed.
+func normalizeIP(value string) string {
+	value = strings.TrimSpace(value)
+	if ip := net.ParseIP(value); ip != nil {
+		return ip.String()
+	}
+	return value
+}
+
+// NormalizeHostname lowercases a hostname and strips its trailing dot, as
+// Namecheap does with CNAME, MX, NS and PTR targets.
+func NormalizeHostname(value string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(value), "."))
+}
+
+// normalizeText collapses runs of whitespace in a TXT value into single
+// spaces and trims it. Case is significant in TXT values, e.g. DKIM keys,
+// and is kept.
+func normalizeText(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// normalizeSRV normalizes each whitespace-separated field of an SRV value as
+// a hostname, which leaves its numeric fields unchanged.
+func normalizeSRV(value string) string {
+	fields := strings.Fields(value)
+	for i, field := range fields {
+		fields[i] = NormalizeHostname(field)
+	}
+	return strings.Join(fields, " ")
+}
+
+// normalizeCAA collapses the whitespace between the flags, tag and value of a
+// CAA value and lowercases its tag, which is case-insensitive. The value,
+// e.g. an iodef URL, is kept as is.
+func normalizeCAA(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) > 1 {
+		fields[1] = strings.ToLower(fields[1])
+	}
+	return strings.Join(fields, " ")
+}
+
+// normalizeRelaxed normalizes a value in every way Namecheap may: it collapses
+// whitespace, lowercases, strips surrounding double quotes and strips the
+// trailing dot of each field.
+func normalizeRelaxed(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if len(value) > 1 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		value = value[1 : len(value)-1]
+	}
+	fields := strings.Fields(value)
+	for i, field := range fields {
+		fields[i] = strings.TrimSuffix(field, ".")
+	}
+	return strings.Join(fields, " ")
+}
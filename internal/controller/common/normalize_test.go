@@ -0,0 +1,111 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueNormalizer(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordType string
+		mode       string
+		desired    string
+		observed   string
+		equivalent bool
+		normalized string
+	}{
+		// A and AAAA values compare as IP addresses
+		{name: "A identical", recordType: "A", desired: "192.0.2.1", observed: "192.0.2.1", equivalent: true, normalized: "192.0.2.1"},
+		{name: "A padded", recordType: "A", desired: " 192.0.2.1 ", observed: "192.0.2.1", equivalent: true, normalized: "192.0.2.1"},
+		{name: "A different", recordType: "A", desired: "192.0.2.2", observed: "192.0.2.1", normalized: "192.0.2.1"},
+		{name: "A not an IP", recordType: "A", desired: "example", observed: "example", equivalent: true, normalized: "example"},
+		{name: "AAAA compressed", recordType: "AAAA", desired: "2001:db8:0:0:0:0:0:1", observed: "2001:db8::1", equivalent: true, normalized: "2001:db8::1"},
+		{name: "AAAA case", recordType: "AAAA", desired: "2001:DB8::1", observed: "2001:db8::1", equivalent: true, normalized: "2001:db8::1"},
+		{name: "MXE padded", recordType: "MXE", desired: "192.0.2.25 ", observed: "192.0.2.25", equivalent: true, normalized: "192.0.2.25"},
+		{name: "AAAA different", recordType: "AAAA", desired: "2001:db8::2", observed: "2001:db8::1", normalized: "2001:db8::1"},
+
+		// Hostname targets are case-insensitive and may be fully qualified
+		{name: "CNAME case", recordType: "CNAME", desired: "Target.Example.com", observed: "target.example.com", equivalent: true, normalized: "target.example.com"},
+		{name: "CNAME trailing dot", recordType: "CNAME", desired: "target.example.com.", observed: "target.example.com", equivalent: true, normalized: "target.example.com"},
+		{name: "CNAME observed trailing dot", recordType: "CNAME", desired: "target.example.com", observed: "target.example.com.", equivalent: true, normalized: "target.example.com"},
+		{name: "CNAME different", recordType: "CNAME", desired: "other.example.com", observed: "target.example.com", normalized: "target.example.com"},
+		{name: "ALIAS case and dot", recordType: "ALIAS", desired: "Example.CDN.net.", observed: "example.cdn.net", equivalent: true, normalized: "example.cdn.net"},
+		{name: "MX case and dot", recordType: "MX", desired: "MAIL.example.com.", observed: "mail.example.com", equivalent: true, normalized: "mail.example.com"},
+		{name: "NS case", recordType: "NS", desired: "NS1.example.net", observed: "ns1.example.net", equivalent: true, normalized: "ns1.example.net"},
+		{name: "PTR dot", recordType: "PTR", desired: "host.example.com.", observed: "host.example.com", equivalent: true, normalized: "host.example.com"},
+		{name: "lowercase type", recordType: "cname", desired: "Target.example.com", observed: "target.example.com", equivalent: true, normalized: "target.example.com"},
+
+		// TXT values ignore whitespace runs but not case
+		{name: "TXT whitespace", recordType: "TXT", desired: "v=spf1  include:_spf.google.com\t~all ", observed: "v=spf1 include:_spf.google.com ~all", equivalent: true, normalized: "v=spf1 include:_spf.google.com ~all"},
+		{name: "TXT case", recordType: "TXT", desired: "v=DKIM1; p=ABC", observed: "v=DKIM1; p=abc", normalized: "v=DKIM1; p=abc"},
+		{name: "TXT quotes", recordType: "TXT", desired: `"token"`, observed: "token", normalized: "token"},
+
+		// SRV fields are normalized one by one
+		{name: "SRV target", recordType: "SRV", desired: "10 5060 SIP.example.com.", observed: "10 5060 sip.example.com", equivalent: true, normalized: "10 5060 sip.example.com"},
+		{name: "SRV whitespace", recordType: "SRV", desired: "10  5060 sip.example.com", observed: "10 5060 sip.example.com", equivalent: true, normalized: "10 5060 sip.example.com"},
+		{name: "SRV port", recordType: "SRV", desired: "10 5061 sip.example.com", observed: "10 5060 sip.example.com", normalized: "10 5060 sip.example.com"},
+
+		// CAA tags are case-insensitive but their values aren't
+		{name: "CAA tag", recordType: "CAA", desired: `0 ISSUE "letsencrypt.org"`, observed: `0 issue "letsencrypt.org"`, equivalent: true, normalized: `0 issue "letsencrypt.org"`},
+		{name: "CAA whitespace", recordType: "CAA", desired: `0  issue  "letsencrypt.org"`, observed: `0 issue "letsencrypt.org"`, equivalent: true, normalized: `0 issue "letsencrypt.org"`},
+		{name: "CAA value", recordType: "CAA", desired: `0 iodef "mailto:Admin@example.com"`, observed: `0 iodef "mailto:admin@example.com"`, normalized: `0 iodef "mailto:admin@example.com"`},
+
+		// Redirect targets and other types compare exactly
+		{name: "URL case", recordType: "URL", desired: "http://Example.com", observed: "http://example.com", normalized: "http://example.com"},
+		{name: "URL identical", recordType: "URL", desired: "http://example.com", observed: "http://example.com", equivalent: true, normalized: "http://example.com"},
+		{name: "URL301 query", recordType: "URL301", desired: "https://example.net/Landing?a=1&b=2", observed: "https://example.net/landing?a=1&b=2", normalized: "https://example.net/landing?a=1&b=2"},
+		{name: "unknown type", recordType: "HINFO", desired: "Target.example.com", observed: "target.example.com", normalized: "target.example.com"},
+
+		// Exact comparison ignores nothing
+		{name: "exact CNAME dot", recordType: "CNAME", mode: ValueCompareExact, desired: "target.example.com.", observed: "target.example.com", normalized: "target.example.com"},
+		{name: "exact AAAA", recordType: "AAAA", mode: ValueCompareExact, desired: "2001:db8:0:0:0:0:0:1", observed: "2001:db8::1", normalized: "2001:db8::1"},
+		{name: "exact identical", recordType: "TXT", mode: ValueCompareExact, desired: "a  b", observed: "a  b", equivalent: true, normalized: "a  b"},
+
+		// Relaxed comparison ignores case, quotes and dots of any type
+		{name: "relaxed TXT case", recordType: "TXT", mode: ValueCompareRelaxed, desired: "Token", observed: "token", equivalent: true, normalized: "token"},
+		{name: "relaxed TXT quotes", recordType: "TXT", mode: ValueCompareRelaxed, desired: `"token"`, observed: "token", equivalent: true, normalized: "token"},
+		{name: "relaxed CAA value", recordType: "CAA", mode: ValueCompareRelaxed, desired: `0 iodef "mailto:Admin@example.com"`, observed: `0 iodef "mailto:admin@example.com"`, equivalent: true, normalized: `0 iodef "mailto:admin@example.com"`},
+		{name: "relaxed AAAA", recordType: "AAAA", mode: ValueCompareRelaxed, desired: "2001:DB8:0::1", observed: "2001:db8::1", equivalent: true, normalized: "2001:db8::1"},
+		{name: "relaxed URL", recordType: "URL", mode: ValueCompareRelaxed, desired: "HTTP://example.com.", observed: "http://example.com", equivalent: true, normalized: "http://example.com"},
+		{name: "relaxed different", recordType: "TXT", mode: ValueCompareRelaxed, desired: "token-a", observed: "token-b", normalized: "token-b"},
+		{name: "relaxed lone quote", recordType: "TXT", mode: ValueCompareRelaxed, desired: `"`, observed: `"`, equivalent: true, normalized: `"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			normalize := ValueNormalizer(tc.recordType, tc.mode)
+			assert.Equal(t, tc.equivalent, normalize(tc.desired) == normalize(tc.observed))
+			assert.Equal(t, tc.normalized, normalize(tc.observed))
+		})
+	}
+}
+
+func TestWriteNormalizer(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordType string
+		mode       string
+		value      string
+		written    string
+	}{
+		{name: "CNAME", recordType: "CNAME", value: " Target.Example.com. ", written: "target.example.com"},
+		{name: "NS", recordType: "NS", value: "NS1.example.net.", written: "ns1.example.net"},
+		{name: "ALIAS", recordType: "ALIAS", value: "Example.CDN.net.", written: "example.cdn.net"},
+		{name: "AAAA", recordType: "AAAA", value: "2001:DB8:0:0:0:0:0:1", written: "2001:db8::1"},
+		{name: "A", recordType: "A", value: " 192.0.2.1", written: "192.0.2.1"},
+		{name: "TXT trimmed", recordType: "TXT", value: "  v=spf1  -all\n", written: "v=spf1  -all"},
+		{name: "URL as is", recordType: "URL", value: "http://Example.com/Path", written: "http://Example.com/Path"},
+		{name: "CAA as is", recordType: "CAA", value: `0 ISSUE "letsencrypt.org"`, written: `0 ISSUE "letsencrypt.org"`},
+		{name: "lowercase type", recordType: "cname", value: "Target.example.com.", written: "target.example.com"},
+		{name: "relaxed", recordType: "CNAME", mode: ValueCompareRelaxed, value: "Target.example.com.", written: "target.example.com"},
+		{name: "exact", recordType: "CNAME", mode: ValueCompareExact, value: "Target.example.com.", written: "Target.example.com."},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.written, WriteNormalizer(tc.recordType, tc.mode)(tc.value))
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package common
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+)
+
+// The managed reconciler discards the status that Create sets, as it saves
+// the resource's critical annotations with an update that decodes the stored
+// status back into it. It saves every annotation Create sets though, so what
+// Create learns that can't be observed again is recorded in an annotation,
+// and recovered from there by Observe.
+
+// SetRecord records v in o's annotation key, encoded as JSON
+func SetRecord(o metav1.Object, key string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrapf(err, "cannot record %s", key)
+	}
+	meta.AddAnnotations(o, map[string]string{key: string(b)})
+	return nil
+}
+
+// GetRecord decodes the record in o's annotation key into v, reporting
+// whether o has one
+func GetRecord(o metav1.Object, key string, v any) (bool, error) {
+	value, ok := o.GetAnnotations()[key]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(value), v); err != nil {
+		return true, errors.Wrapf(err, "cannot read record %s", key)
+	}
+	return true, nil
+}
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+)
+
+func TestRecord(t *testing.T) {
+	const key = "namecheap.m.crossplane.io/test-record"
+	cr := &v1beta1.Domain{}
+
+	var order v1beta1.DomainOrder
+	ok, err := GetRecord(cr, key, &order)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	want := v1beta1.DomainOrder{Action: v1beta1.DomainOrderCreate, OrderID: 196074, ChargedAmount: "20.87"}
+	require.NoError(t, SetRecord(cr, key, want))
+	ok, err = GetRecord(cr, key, &order)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, want, order)
+
+	cr.SetAnnotations(map[string]string{key: "{"})
+	ok, err = GetRecord(cr, key, &order)
+	assert.True(t, ok)
+	assert.ErrorContains(t, err, "cannot read record "+key)
+}
@@ -0,0 +1,17 @@
+package common
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationKeyRefresh requests a guaranteed-fresh read of a managed
+// resource. Each new value triggers one Observe that bypasses client-side
+// caches; the handled value is recorded in the resource's status.
+const AnnotationKeyRefresh = "namecheap.m.crossplane.io/refresh"
+
+// RefreshRequest returns the value of the refresh annotation and whether it
+// requests a fresh read, i.e. is set and differs from lastHandled.
+func RefreshRequest(o metav1.Object, lastHandled string) (string, bool) {
+	token := o.GetAnnotations()[AnnotationKeyRefresh]
+	return token, token != "" && token != lastHandled
+}
@@ -0,0 +1,53 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRefreshRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		lastHandled string
+		token       string
+		fresh       bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:        "empty annotation",
+			annotations: map[string]string{AnnotationKeyRefresh: ""},
+		},
+		{
+			name:        "new value",
+			annotations: map[string]string{AnnotationKeyRefresh: "2024-01-01T00:00:00Z"},
+			token:       "2024-01-01T00:00:00Z",
+			fresh:       true,
+		},
+		{
+			name:        "already handled",
+			annotations: map[string]string{AnnotationKeyRefresh: "2024-01-01T00:00:00Z"},
+			lastHandled: "2024-01-01T00:00:00Z",
+			token:       "2024-01-01T00:00:00Z",
+		},
+		{
+			name:        "changed value",
+			annotations: map[string]string{AnnotationKeyRefresh: "2024-01-02T00:00:00Z"},
+			lastHandled: "2024-01-01T00:00:00Z",
+			token:       "2024-01-02T00:00:00Z",
+			fresh:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, fresh := RefreshRequest(&metav1.ObjectMeta{Annotations: tt.annotations}, tt.lastHandled)
+			assert.Equal(t, tt.token, token)
+			assert.Equal(t, tt.fresh, fresh)
+		})
+	}
+}
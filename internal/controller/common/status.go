@@ -0,0 +1,126 @@
+// Package common contains helpers shared by the provider's controllers.
+package common
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// FieldManager is the field manager the provider uses when applying status.
+const FieldManager = "provider-namecheap"
+
+const (
+	errGetGVK           = "cannot determine group, version and kind of object"
+	errToUnstructured   = "cannot convert object to unstructured"
+	errFromUnstructured = "cannot convert applied object from unstructured"
+	errApplyStatus      = "cannot apply status"
+)
+
+// NewStatusApplyManager wraps mgr so that clients obtained from it write the
+// status subresource using server-side apply rather than update. Pass the
+// result to managed.NewReconciler for kinds with large status structs, where
+// optimistic-concurrency conflicts on status updates are frequent.
+func NewStatusApplyManager(mgr ctrl.Manager) ctrl.Manager {
+	return &statusApplyManager{Manager: mgr, client: NewStatusApplyClient(mgr.GetClient())}
+}
+
+type statusApplyManager struct {
+	ctrl.Manager
+	client client.Client
+}
+
+func (m *statusApplyManager) GetClient() client.Client {
+	return m.client
+}
+
+// NewStatusApplyClient wraps c so that Status().Update applies the object's
+// status with server-side apply under FieldManager. Because the applied
+// object carries no resourceVersion, a write from another actor between our
+// read and our status write does not cause a conflict. If the API server does
+// not support server-side apply the client falls back to a regular update.
+func NewStatusApplyClient(c client.Client) client.Client {
+	return &statusApplyClient{Client: c, status: &statusApplyWriter{SubResourceWriter: c.Status(), scheme: c.Scheme()}}
+}
+
+type statusApplyClient struct {
+	client.Client
+	status *statusApplyWriter
+}
+
+func (c *statusApplyClient) Status() client.SubResourceWriter {
+	return c.status
+}
+
+type statusApplyWriter struct {
+	client.SubResourceWriter
+	scheme *runtime.Scheme
+
+	// unsupported is set once the API server rejects server-side apply, after
+	// which status is always written with a regular update.
+	unsupported atomic.Bool
+}
+
+// Update applies the status of obj. Options are only honored when falling
+// back to a regular update.
+func (w *statusApplyWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if w.unsupported.Load() {
+		return w.SubResourceWriter.Update(ctx, obj, opts...)
+	}
+
+	err := w.apply(ctx, obj)
+	if applyUnsupported(err) {
+		w.unsupported.Store(true)
+		return w.SubResourceWriter.Update(ctx, obj, opts...)
+	}
+	return err
+}
+
+func (w *statusApplyWriter) apply(ctx context.Context, obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, w.scheme)
+	if err != nil {
+		return errors.Wrap(err, errGetGVK)
+	}
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return errors.Wrap(err, errToUnstructured)
+	}
+
+	// Apply only the identity and status of the object, so that we claim
+	// ownership of nothing but status fields.
+	ac := &unstructured.Unstructured{}
+	ac.SetGroupVersionKind(gvk)
+	ac.SetName(obj.GetName())
+	ac.SetNamespace(obj.GetNamespace())
+	if status, ok := u["status"]; ok {
+		ac.Object["status"] = status
+	}
+
+	if err := w.SubResourceWriter.Apply(ctx, client.ApplyConfigurationFromUnstructured(ac),
+		client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		return errors.Wrap(err, errApplyStatus)
+	}
+
+	// The API server returns the full object, which we copy back so that the
+	// caller sees the new resourceVersion. Clients that don't return the
+	// object leave ac untouched, in which case obj is already up to date.
+	if ac.GetResourceVersion() == "" {
+		return nil
+	}
+	return errors.Wrap(runtime.DefaultUnstructuredConverter.FromUnstructured(ac.Object, obj), errFromUnstructured)
+}
+
+// applyUnsupported reports whether err indicates the API server does not
+// support server-side apply.
+func applyUnsupported(err error) bool {
+	err = errors.Cause(err)
+	return kerrors.IsUnsupportedMediaType(err) || kerrors.IsMethodNotSupported(err) || kerrors.IsNotAcceptable(err)
+}
@@ -0,0 +1,156 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+)
+
+// unconditionalApply emulates the API server applying status without a
+// resourceVersion. The fake client treats such an apply as a conflict, so the
+// stored resourceVersion is filled in after checking that the caller sent an
+// unconditional apply containing only identity and status.
+func unconditionalApply(t *testing.T) func(ctx context.Context, c client.Client, subResourceName string, obj runtime.ApplyConfiguration, opts ...client.SubResourceApplyOption) error {
+	return func(ctx context.Context, c client.Client, subResourceName string, obj runtime.ApplyConfiguration, opts ...client.SubResourceApplyOption) error {
+		data, err := json.Marshal(obj)
+		require.NoError(t, err)
+		u := &unstructured.Unstructured{}
+		require.NoError(t, json.Unmarshal(data, u))
+
+		assert.Empty(t, u.GetResourceVersion(), "apply must be unconditional")
+		assert.NotContains(t, u.Object, "spec", "apply must only contain status")
+
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(u.GroupVersionKind())
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(u), current))
+		u.SetResourceVersion(current.GetResourceVersion())
+
+		return c.SubResource(subResourceName).Apply(ctx, client.ApplyConfigurationFromUnstructured(u), opts...)
+	}
+}
+
+func newFakeClient(t *testing.T, funcs interceptor.Funcs) client.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	cr := &v1beta1.Domain{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+		Spec: v1beta1.DomainSpec{
+			ForProvider: v1beta1.DomainParameters{DomainName: "example.com"},
+		},
+	}
+
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithTypeConverters(managedfields.NewDeducedTypeConverter()).
+		WithObjects(cr).
+		WithStatusSubresource(cr).
+		WithInterceptorFuncs(funcs).
+		Build()
+}
+
+// getStale reads the Domain, then modifies it behind the reader's back so that
+// the returned copy has a stale resourceVersion.
+func getStale(t *testing.T, c client.Client) *v1beta1.Domain {
+	t.Helper()
+	ctx := context.Background()
+	key := client.ObjectKey{Name: "example", Namespace: "default"}
+
+	stale := &v1beta1.Domain{}
+	require.NoError(t, c.Get(ctx, key, stale))
+
+	concurrent := &v1beta1.Domain{}
+	require.NoError(t, c.Get(ctx, key, concurrent))
+	concurrent.SetAnnotations(map[string]string{"example.com/touched": "true"})
+	require.NoError(t, c.Update(ctx, concurrent))
+
+	stale.Status.AtProvider.ID = "12345"
+	return stale
+}
+
+func TestStatusApplyClient_Conflict(t *testing.T) {
+	ctx := context.Background()
+	key := client.ObjectKey{Name: "example", Namespace: "default"}
+
+	t.Run("update conflicts on a stale object", func(t *testing.T) {
+		c := newFakeClient(t, interceptor.Funcs{})
+		err := c.Status().Update(ctx, getStale(t, c))
+		assert.True(t, kerrors.IsConflict(err), "expected conflict, got %v", err)
+	})
+
+	t.Run("apply succeeds on a stale object", func(t *testing.T) {
+		updates := 0
+		c := newFakeClient(t, interceptor.Funcs{
+			SubResourceApply: unconditionalApply(t),
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				updates++
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		})
+
+		require.NoError(t, NewStatusApplyClient(c).Status().Update(ctx, getStale(t, c)))
+		assert.Equal(t, 0, updates, "status should not be written with update")
+
+		got := &v1beta1.Domain{}
+		require.NoError(t, c.Get(ctx, key, got))
+		assert.Equal(t, "12345", got.Status.AtProvider.ID)
+		assert.Equal(t, "true", got.GetAnnotations()["example.com/touched"], "concurrent change should be preserved")
+		assert.Equal(t, "example.com", got.Spec.ForProvider.DomainName)
+	})
+}
+
+func TestStatusApplyClient_Fallback(t *testing.T) {
+	ctx := context.Background()
+	applies, updates := 0, 0
+	c := newFakeClient(t, interceptor.Funcs{
+		SubResourceApply: func(ctx context.Context, c client.Client, subResourceName string, obj runtime.ApplyConfiguration, opts ...client.SubResourceApplyOption) error {
+			applies++
+			return kerrors.NewGenericServerResponse(http.StatusUnsupportedMediaType, "PATCH", v1beta1.SchemeGroupVersion.WithResource("domains").GroupResource(), "example", "", 0, true)
+		},
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			updates++
+			return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+		},
+	})
+	sc := NewStatusApplyClient(c)
+
+	for i := 0; i < 2; i++ {
+		cr := &v1beta1.Domain{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "example", Namespace: "default"}, cr))
+		cr.Status.AtProvider.ID = "12345"
+		require.NoError(t, sc.Status().Update(ctx, cr))
+	}
+
+	assert.Equal(t, 1, applies, "apply should not be retried once unsupported")
+	assert.Equal(t, 2, updates)
+}
+
+func TestStatusApplyClient_Error(t *testing.T) {
+	c := newFakeClient(t, interceptor.Funcs{
+		SubResourceApply: func(ctx context.Context, c client.Client, subResourceName string, obj runtime.ApplyConfiguration, opts ...client.SubResourceApplyOption) error {
+			return kerrors.NewInternalError(assert.AnError)
+		},
+	})
+
+	cr := &v1beta1.Domain{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: "example", Namespace: "default"}, cr))
+	err := NewStatusApplyClient(c).Status().Update(context.Background(), cr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), errApplyStatus)
+}
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationKeySuspendUntil suspends drift enforcement of a managed resource
+// until the RFC 3339 time it holds, e.g. to keep a record hot-fixed in the
+// Namecheap dashboard during an incident. While suspended, drift is reported
+// as up to date so that it is not reverted.
+const AnnotationKeySuspendUntil = "namecheap.m.crossplane.io/suspend-until"
+
+// DefaultMaxDriftSuspension is the default longest time ahead that the
+// suspend-until annotation may suspend drift enforcement
+const DefaultMaxDriftSuspension = 24 * time.Hour
+
+// MaxDriftSuspension is the longest time ahead that the suspend-until
+// annotation may suspend drift enforcement. It is set from the provider's
+// --max-drift-suspension flag.
+var MaxDriftSuspension = DefaultMaxDriftSuspension
+
+// DriftSuspension returns the time until which drift enforcement of o is
+// suspended, and whether it is suspended at now. An annotation that can't be
+// parsed, or that is more than max after now, is not honored and returns an
+// error explaining why.
+func DriftSuspension(o metav1.Object, now time.Time, max time.Duration) (time.Time, bool, error) {
+	value, ok := o.GetAnnotations()[AnnotationKeySuspendUntil]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, errors.Wrapf(err, "ignoring %s annotation: it must be an RFC 3339 time", AnnotationKeySuspendUntil)
+	}
+	if until.Sub(now) > max {
+		return until, false, errors.Errorf("ignoring %s annotation: %s is more than the maximum of %s away",
+			AnnotationKeySuspendUntil, value, max)
+	}
+	return until, now.Before(until), nil
+}
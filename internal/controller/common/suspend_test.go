@@ -0,0 +1,45 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDriftSuspension(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		annotation string
+		suspended  bool
+		err        string
+	}{
+		{name: "no annotation"},
+		{name: "active", annotation: "2026-10-16T14:00:00Z", suspended: true},
+		{name: "active with offset", annotation: "2026-10-16T15:00:00+02:00", suspended: true},
+		{name: "at maximum", annotation: "2026-10-17T12:00:00Z", suspended: true},
+		{name: "expired", annotation: "2026-10-16T11:59:59Z"},
+		{name: "over maximum", annotation: "2026-10-17T12:00:01Z", err: "more than the maximum of 24h0m0s away"},
+		{name: "malformed", annotation: "tomorrow", err: "must be an RFC 3339 time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &metav1.ObjectMeta{}
+			if tt.annotation != "" {
+				o.Annotations = map[string]string{AnnotationKeySuspendUntil: tt.annotation}
+			}
+
+			_, suspended, err := DriftSuspension(o, now, DefaultMaxDriftSuspension)
+			assert.Equal(t, tt.suspended, suspended)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
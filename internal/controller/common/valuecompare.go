@@ -0,0 +1,38 @@
+package common
+
+import (
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationKeyValueCompare selects how the value of a DNSRecord is compared
+// with the value Namecheap stores: ValueCompareExact or ValueCompareRelaxed.
+// When unset, the comparison appropriate to the record type is used.
+const AnnotationKeyValueCompare = "namecheap.m.crossplane.io/value-compare"
+
+// Value comparison modes of the value-compare annotation.
+const (
+	// ValueCompareDefault compares values the way appropriate to their
+	// record type, ignoring only what Namecheap is known to normalize
+	ValueCompareDefault = ""
+
+	// ValueCompareExact compares values byte for byte
+	ValueCompareExact = "exact"
+
+	// ValueCompareRelaxed ignores every difference Namecheap may normalize
+	// away, whatever the record type
+	ValueCompareRelaxed = "relaxed"
+)
+
+// ValueCompare returns the value comparison mode set on o by the
+// value-compare annotation. An unknown mode falls back to
+// ValueCompareDefault and returns an error explaining why.
+func ValueCompare(o metav1.Object) (string, error) {
+	switch mode := o.GetAnnotations()[AnnotationKeyValueCompare]; mode {
+	case ValueCompareDefault, ValueCompareExact, ValueCompareRelaxed:
+		return mode, nil
+	default:
+		return ValueCompareDefault, errors.Errorf("ignoring %s annotation: %q is neither %q nor %q",
+			AnnotationKeyValueCompare, mode, ValueCompareRelaxed, ValueCompareExact)
+	}
+}
@@ -0,0 +1,39 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValueCompare(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation *string
+		want       string
+		wantErr    bool
+	}{
+		{name: "Unset", want: ValueCompareDefault},
+		{name: "Empty", annotation: ptr(""), want: ValueCompareDefault},
+		{name: "Exact", annotation: ptr("exact"), want: ValueCompareExact},
+		{name: "Relaxed", annotation: ptr("relaxed"), want: ValueCompareRelaxed},
+		{name: "Unknown", annotation: ptr("Relaxed"), want: ValueCompareDefault, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &metav1.ObjectMeta{}
+			if tc.annotation != nil {
+				o.Annotations = map[string]string{AnnotationKeyValueCompare: *tc.annotation}
+			}
+			got, err := ValueCompare(o)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
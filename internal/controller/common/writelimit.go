@@ -0,0 +1,11 @@
+package common
+
+import (
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+// DomainWriteInterval is the minimum interval between writes to the same
+// domain's DNS settings, as passed to the Namecheap clients in
+// namecheap.Config. It is set from the provider's --domain-write-interval
+// flag, and is negative when the limit is disabled.
+var DomainWriteInterval = namecheap.DefaultDomainWriteInterval
@@ -0,0 +1,17 @@
+package common
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationKeyAllowZoneShrink allows a managed resource to rewrite its
+// domain's DNS host records even though Namecheap just reported far fewer
+// of them than were last known, which is otherwise refused until the drop is
+// confirmed. Set it to "true" only once the drop is known to be genuine.
+const AnnotationKeyAllowZoneShrink = "namecheap.m.crossplane.io/allow-zone-shrink"
+
+// AllowZoneShrink reports whether the allow-zone-shrink annotation is set to
+// "true" on o
+func AllowZoneShrink(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationKeyAllowZoneShrink] == "true"
+}
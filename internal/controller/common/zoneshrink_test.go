@@ -0,0 +1,14 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAllowZoneShrink(t *testing.T) {
+	assert.False(t, AllowZoneShrink(&metav1.ObjectMeta{}))
+	assert.False(t, AllowZoneShrink(&metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyAllowZoneShrink: "yes"}}))
+	assert.True(t, AllowZoneShrink(&metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyAllowZoneShrink: "true"}}))
+}
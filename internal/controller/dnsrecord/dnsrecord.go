@@ -2,24 +2,27 @@ package dnsrecord
 
 import (
 	"context"
-	"encoding/json"
 	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
 
 	"github.com/rossigee/provider-namecheap/apis/v1beta1"
-	"github.com/rossigee/provider-namecheap/internal/clients/namecheap"
+	"github.com/rossigee/provider-namecheap/internal/controller/common"
+	"github.com/rossigee/provider-namecheap/internal/controller/pollinterval"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
 )
 
 const (
@@ -28,26 +31,37 @@ const (
 	errGetPC        = "cannot get ProviderConfig"
 	errGetCreds     = "cannot get credentials"
 
-	errNewClient         = "cannot create new Service"
-	errCreateDNSRecord   = "cannot create DNS record"
-	errUpdateDNSRecord   = "cannot update DNS record"
-	errDeleteDNSRecord   = "cannot delete DNS record"
-	errGetDNSRecord      = "cannot get DNS record"
+	errNewClient       = "cannot create new Service"
+	errCreateDNSRecord = "cannot create DNS record"
+	errUpdateDNSRecord = "cannot update DNS record"
+	errDeleteDNSRecord = "cannot delete DNS record"
+	errGetDNSRecord    = "cannot get DNS record"
+
+	reasonDeleteSkipped  event.Reason = "DeleteSkipped"
+	reasonDriftSuspended event.Reason = "DriftSuspended"
+	reasonValueCompare   event.Reason = "ValueCompare"
+	reasonZoneShrunk     event.Reason = "ZoneShrunk"
 )
 
 // Setup adds a controller that reconciles DNSRecord managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1beta1.DNSRecordGroupKind)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name)) //nolint:staticcheck // SA1019: required for v2 API compatibility
 
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1beta1.DNSRecordGroupVersionKind),
 		managed.WithExternalConnector(&connector{
-			kube:   mgr.GetClient(),
-			usage:  resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
+			kube:     mgr.GetClient(),
+			usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
+			recorder: recorder,
+			log:      o.Logger.WithValues("controller", name),
+			drift:    common.NewDriftEvents(recorder, o.PollInterval),
 		}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))) //nolint:staticcheck // SA1019: required for v2 API compatibility
+		managed.WithPollIntervalHook(pollinterval.NewHook(mgr.GetCache(), o.Logger.WithValues("controller", name))),
+		managed.WithRecorder(recorder),
+		common.WithManagementPolicies(o))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -60,8 +74,11 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 // A connector is expected to produce an ExternalClient when its Connect method
 // is called.
 type connector struct {
-	kube  client.Client
-	usage *resource.ProviderConfigUsageTracker
+	kube     client.Client
+	usage    *resource.ProviderConfigUsageTracker
+	recorder event.Recorder
+	log      logging.Logger
+	drift    *common.DriftEvents
 }
 
 // Connect typically produces an ExternalClient by:
@@ -90,24 +107,23 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetCreds)
 	}
 
+	common.RecordCredentialsRevision(ctx, c.kube, c.log, pc, namecheap.CredentialsRevision(data))
+
 	// Parse credentials from the secret data
-	var creds struct {
-		APIUser  string `json:"api_user"`
-		APIKey   string `json:"api_key"`
-		Username string `json:"username"`
-		ClientIP string `json:"client_ip"`
-	}
-	if err := json.Unmarshal(data, &creds); err != nil {
+	creds, err := namecheap.ParseCredentials(data)
+	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse credentials JSON")
 	}
 
 	// Create Namecheap client
 	config := namecheap.Config{
-		APIUser:  creds.APIUser,
-		APIKey:   creds.APIKey,
-		Username: creds.Username,
-		ClientIP: creds.ClientIP,
-		Sandbox:  pc.Spec.SandboxMode != nil && *pc.Spec.SandboxMode,
+		APIUser:             creds.APIUser,
+		APIKey:              creds.APIKey,
+		Username:            creds.Username,
+		ClientIP:            creds.ClientIP,
+		ClientIPs:           creds.ClientIPs,
+		Sandbox:             pc.Spec.SandboxMode != nil && *pc.Spec.SandboxMode,
+		DomainWriteInterval: common.DomainWriteInterval,
 	}
 
 	if pc.Spec.APIBase != nil {
@@ -115,8 +131,9 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	}
 
 	client := namecheap.NewClient(config)
+	common.Clients.Track(pc.GetName(), client)
 
-	return &external{client: client}, nil
+	return &external{client: client, recorder: c.recorder, drift: c.drift}, nil
 }
 
 // Disconnect cleans up any resources created by Connect.
@@ -128,7 +145,9 @@ func (c *external) Disconnect(ctx context.Context) error {
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
-	client *namecheap.Client
+	client   namecheap.DNSAPI
+	recorder event.Recorder
+	drift    *common.DriftEvents
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -136,6 +155,7 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if !ok {
 		return managed.ExternalObservation{}, errors.New(errNotDNSRecord)
 	}
+	ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.DNSRecordKind)
 
 	domain := cr.Spec.ForProvider.Domain
 	recordName := cr.Spec.ForProvider.Name
@@ -145,80 +165,181 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, nil
 	}
 
-	// Check if DNS record exists
-	exists, err := c.client.DNSRecordExists(ctx, domain, recordName, recordType)
+	// Refuse to reconcile against a different Namecheap environment than
+	// the one the resource was recorded in
+	environment := c.client.Environment()
+	if err := common.CheckEnvironment(cr, cr.Status.AtProvider.Environment, environment); err != nil {
+		cr.SetConditions(v1beta1.EnvironmentMismatch(err.Error()))
+		return managed.ExternalObservation{}, err
+	}
+
+	// Stop calling the API for a record whose deletion is being forced
+	if meta.WasDeleted(cr) && c.forceDelete(cr, errors.New("force-delete annotation threshold reached")) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	// Bypass client-side caches once per refresh annotation value
+	refresh, fresh := common.RefreshRequest(cr, cr.Status.AtProvider.LastHandledRefresh)
+	if fresh {
+		ctx = namecheap.WithFreshRead(ctx)
+	}
+
+	// Find the record among those of its name and type, e.g. the A records
+	// of a round-robin name
+	records, err := c.client.FindDNSRecords(ctx, domain, recordName, recordType)
 	if err != nil {
+		// Don't let a record that can no longer be observed block deletion
+		if meta.WasDeleted(cr) && c.skipDelete(cr, err) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetDNSRecord)
 	}
 
-	if !exists {
+	// Compare values in the form Namecheap stores them, which it may
+	// normalize on store
+	mode, err := common.ValueCompare(cr)
+	if err != nil {
+		c.recorder.Event(cr, event.Warning(reasonValueCompare, err))
+	}
+	normalize := common.ValueNormalizer(recordType, mode)
+
+	record := managedRecord(cr, records, normalize)
+	if record == nil {
+		if fresh {
+			cr.Status.AtProvider.LastHandledRefresh = refresh
+		}
 		return managed.ExternalObservation{
 			ResourceExists: false,
 		}, nil
 	}
 
-	// Get DNS record details
-	record, err := c.client.GetDNSRecord(ctx, domain, recordName, recordType)
-	if err != nil {
-		return managed.ExternalObservation{}, errors.Wrap(err, errGetDNSRecord)
+	// A record strict deletion leaves in place is no longer ours, so that
+	// deletion completes rather than being attempted forever
+	if meta.WasDeleted(cr) && strictlyKept(cr, record, normalize) {
+		c.recorder.Event(cr, event.Normal(reasonDeleteSkipped, "DNS record "+recordName+"."+domain+
+			" no longer holds the value last applied; leaving it in place"))
+		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	// Update status with observed values
-	cr.Status.AtProvider.ID = strconv.Itoa(record.HostID)
-	cr.Status.AtProvider.FQDN = recordName + "." + domain
+	// Build the observation on a copy of the current one, which preserves
+	// the fields only set on create or update, and assign it once complete
+	obs := cr.Status.AtProvider.DeepCopy()
+	if fresh {
+		obs.LastHandledRefresh = refresh
+	}
+	obs.Environment = environment
+	obs.ID = strconv.Itoa(record.HostID)
+	obs.FQDN = recordName + "." + domain
+	obs.DynamicDNS = record.IsDDNSEnabled
 
 	// Set external name annotation
 	externalName := domain + "/" + recordType + "/" + recordName
 	meta.SetExternalName(cr, externalName)
 
-	// Check if resource is up to date
-	upToDate := record.Address == cr.Spec.ForProvider.Value
-	if cr.Spec.ForProvider.TTL != nil && record.TTL != *cr.Spec.ForProvider.TTL {
-		upToDate = false
+	obs.NormalizedValue = normalize(record.Address)
+	drifts := valueDrifts(cr, record.Address, normalize)
+	valueMatches := len(drifts) == 0
+
+	// Keep the last applied value in its stored form, which strict deletion
+	// compares byte for byte
+	if valueMatches && obs.LastAppliedValue != "" && normalize(obs.LastAppliedValue) == obs.NormalizedValue {
+		obs.LastAppliedValue = record.Address
+	}
+
+	// Late-initialize the TTL of an adopted record, unless it is automatic,
+	// which an unset TTL already means
+	lateInitialized := false
+	if cr.Spec.ForProvider.TTL == nil && obs.LastAppliedValue == "" && record.TTL != namecheap.AutomaticTTL {
+		ttl := record.TTL
+		cr.Spec.ForProvider.TTL = &ttl
+		lateInitialized = true
+	}
+	if ttl := desiredTTL(cr); record.TTL != ttl {
+		drifts = append(drifts, common.Drift{
+			Field:    "spec.forProvider.ttl",
+			Expected: strconv.Itoa(ttl),
+			Observed: strconv.Itoa(record.TTL),
+		})
+	}
+	// Compare the preference even when 0, which is a valid MX preference.
+	// The priority of a structured SRV record was compared in its value.
+	if cr.Spec.ForProvider.Priority != nil && !structuredSRV(cr.Spec.ForProvider) && record.MXPref != *cr.Spec.ForProvider.Priority {
+		drifts = append(drifts, common.Drift{
+			Field:    "spec.forProvider.priority",
+			Expected: strconv.Itoa(*cr.Spec.ForProvider.Priority),
+			Observed: strconv.Itoa(record.MXPref),
+		})
+	}
+	upToDate := len(drifts) == 0
+
+	// A record that matches the spec's value counts as applied when no
+	// value is recorded: the record was adopted, or created by a Create
+	// whose status the managed reconciler discarded
+	if valueMatches && obs.LastAppliedValue == "" {
+		obs.LastAppliedValue = record.Address
 	}
-	if cr.Spec.ForProvider.Priority != nil && record.MXPref != *cr.Spec.ForProvider.Priority {
-		upToDate = false
+
+	// Leave drift in place while its enforcement is suspended
+	if !upToDate {
+		suspended := c.driftSuspended(cr)
+		c.drift.Record(cr, common.DriftCorrection(cr, suspended), drifts...)
+		upToDate = suspended
 	}
 
+	cr.Status.AtProvider = *obs
+	cr.SetConditions(v1beta1.EnvironmentMatched())
 	cr.Status.SetConditions(xpv1.Available())
 
 	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: upToDate,
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: lateInitialized,
 	}, nil
 }
 
+// driftSuspended reports whether drift of cr is to be left in place because
+// the suspend-until annotation suspends its enforcement, recording an event
+// either way the annotation is handled
+func (c *external) driftSuspended(cr resource.Managed) bool {
+	until, suspended, err := common.DriftSuspension(cr, time.Now(), common.MaxDriftSuspension)
+	if err != nil {
+		c.recorder.Event(cr, event.Warning(reasonDriftSuspended, err))
+		return false
+	}
+	if suspended {
+		c.recorder.Event(cr, event.Normal(reasonDriftSuspended,
+			"Drift enforcement is suspended until "+until.Format(time.RFC3339)))
+	}
+	return suspended
+}
+
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1beta1.DNSRecord)
 	if !ok {
 		return managed.ExternalCreation{}, errors.New(errNotDNSRecord)
 	}
+	ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.DNSRecordKind)
 
 	cr.Status.SetConditions(xpv1.Creating())
 
 	domain := cr.Spec.ForProvider.Domain
 	recordName := cr.Spec.ForProvider.Name
 	recordType := cr.Spec.ForProvider.Type
-	recordValue := cr.Spec.ForProvider.Value
+	recordValue := writtenValue(cr)
 
 	// Create DNS record struct
 	record := namecheap.DNSRecord{
 		Name:    recordName,
 		Type:    recordType,
 		Address: recordValue,
-		TTL:     300, // Default TTL
+		TTL:     desiredTTL(cr),
 	}
 
-	if cr.Spec.ForProvider.TTL != nil {
-		record.TTL = *cr.Spec.ForProvider.TTL
-	}
-
-	if cr.Spec.ForProvider.Priority != nil {
-		record.MXPref = *cr.Spec.ForProvider.Priority
-	}
+	record.MXPref = mxPref(cr)
 
 	// Create the DNS record
-	if err := c.client.CreateDNSRecord(ctx, domain, record); err != nil {
+	if err := c.client.CreateDNSRecord(c.zoneWrite(ctx, cr), domain, record); err != nil {
+		c.zoneShrunk(cr, err)
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateDNSRecord)
 	}
 
@@ -226,6 +347,9 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	externalName := domain + "/" + recordType + "/" + recordName
 	meta.SetExternalName(cr, externalName)
 
+	cr.Status.AtProvider.LastAppliedValue = recordValue
+	c.recordWritten(ctx, cr)
+
 	return managed.ExternalCreation{}, nil
 }
 
@@ -234,17 +358,22 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotDNSRecord)
 	}
+	ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.DNSRecordKind)
 
 	domain := cr.Spec.ForProvider.Domain
 	recordName := cr.Spec.ForProvider.Name
 	recordType := cr.Spec.ForProvider.Type
-	recordValue := cr.Spec.ForProvider.Value
+	recordValue := writtenValue(cr)
 
-	// Get existing record to preserve HostID
-	existingRecord, err := c.client.GetDNSRecord(ctx, domain, recordName, recordType)
+	// Get the managed record, whose HostID identifies it among those of
+	// the same name and type
+	existingRecord, err := c.managedRecord(ctx, cr)
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errGetDNSRecord)
 	}
+	if existingRecord == nil {
+		return managed.ExternalUpdate{}, errors.Wrap(namecheap.ErrDNSRecordNotFound, errGetDNSRecord)
+	}
 
 	// Update DNS record struct
 	record := namecheap.DNSRecord{
@@ -252,22 +381,20 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		Name:    recordName,
 		Type:    recordType,
 		Address: recordValue,
-		TTL:     300, // Default TTL
+		TTL:     desiredTTL(cr),
 	}
 
-	if cr.Spec.ForProvider.TTL != nil {
-		record.TTL = *cr.Spec.ForProvider.TTL
-	}
-
-	if cr.Spec.ForProvider.Priority != nil {
-		record.MXPref = *cr.Spec.ForProvider.Priority
-	}
+	record.MXPref = mxPref(cr)
 
 	// Update the DNS record
-	if err := c.client.UpdateDNSRecord(ctx, domain, record); err != nil {
+	if err := c.client.UpdateDNSRecord(c.zoneWrite(ctx, cr), domain, record); err != nil {
+		c.zoneShrunk(cr, err)
 		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateDNSRecord)
 	}
 
+	cr.Status.AtProvider.LastAppliedValue = recordValue
+	c.recordWritten(ctx, cr)
+
 	return managed.ExternalUpdate{}, nil
 }
 
@@ -276,17 +403,180 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 	if !ok {
 		return managed.ExternalDelete{}, errors.New(errNotDNSRecord)
 	}
+	ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.DNSRecordKind)
 
 	cr.Status.SetConditions(xpv1.Deleting())
 
-	domain := cr.Spec.ForProvider.Domain
-	recordName := cr.Spec.ForProvider.Name
-	recordType := cr.Spec.ForProvider.Type
+	if c.forceDelete(cr, errors.New("force-delete annotation threshold reached")) {
+		return managed.ExternalDelete{}, nil
+	}
 
-	// Delete the DNS record
-	if err := c.client.DeleteDNSRecord(ctx, domain, recordName, recordType); err != nil {
-		return managed.ExternalDelete{}, errors.Wrap(err, errDeleteDNSRecord)
+	// Only delete the managed record, leaving others of the same name and
+	// type in place. In strict mode only delete it if it still holds the
+	// value we applied; a record repointed out-of-band is no longer ours to
+	// remove.
+	p := cr.Spec.ForProvider
+	mode, _ := common.ValueCompare(cr)
+	record, err := c.managedRecord(ctx, cr)
+	if err == nil && record != nil && !strictlyKept(cr, record, common.ValueNormalizer(p.Type, mode)) {
+		_, err = c.client.DeleteDNSRecordExact(c.zoneWrite(ctx, cr), p.Domain, *record)
+	}
+	c.zoneShrunk(cr, err)
+
+	// A record that is already gone has been deleted as far as we are concerned
+	if err != nil && !errors.Is(err, namecheap.ErrDNSRecordNotFound) {
+		if !c.skipDelete(cr, err) {
+			return managed.ExternalDelete{}, errors.Wrap(err, errDeleteDNSRecord)
+		}
+		return managed.ExternalDelete{}, nil
 	}
 
+	// Only consecutive failures count towards forcing deletion
+	cr.Status.AtProvider.DeleteFailures = 0
 	return managed.ExternalDelete{}, nil
-}
\ No newline at end of file
+}
+
+// managedRecord returns the record cr manages, or nil if there is none
+func (c *external) managedRecord(ctx context.Context, cr *v1beta1.DNSRecord) (*namecheap.DNSRecord, error) {
+	p := cr.Spec.ForProvider
+	records, err := c.client.FindDNSRecords(ctx, p.Domain, p.Name, p.Type)
+	if err != nil {
+		return nil, err
+	}
+	mode, _ := common.ValueCompare(cr)
+	return managedRecord(cr, records, common.ValueNormalizer(p.Type, mode)), nil
+}
+
+// managedRecord returns the record among records, those of cr's name and
+// type, that cr manages: the one with the host ID last observed, else the
+// first with the value last applied, else, when adopting a record, the
+// first whose value matches the spec. Namecheap assigns new host IDs
+// whenever it rewrites the domain's host records, e.g. for another
+// DNSRecord, so the ID may well be stale. It returns nil if cr manages none
+// of them, e.g. when they are all other records of a round-robin name.
+func managedRecord(cr *v1beta1.DNSRecord, records []namecheap.DNSRecord, normalize common.Normalizer) *namecheap.DNSRecord {
+	if id := cr.Status.AtProvider.ID; id != "" {
+		for i := range records {
+			if strconv.Itoa(records[i].HostID) == id {
+				return &records[i]
+			}
+		}
+	}
+	if applied := cr.Status.AtProvider.LastAppliedValue; applied != "" {
+		for i := range records {
+			if normalize(records[i].Address) == normalize(applied) {
+				return &records[i]
+			}
+		}
+	}
+	for i := range records {
+		if len(valueDrifts(cr, records[i].Address, normalize)) == 0 {
+			return &records[i]
+		}
+	}
+	return nil
+}
+
+// strictlyKept reports whether strict deletion leaves record in place
+// because it no longer holds the value last applied, compared byte for byte.
+// Without a recorded value, the record is compared with the spec's value.
+func strictlyKept(cr *v1beta1.DNSRecord, record *namecheap.DNSRecord, normalize common.Normalizer) bool {
+	if strict := cr.Spec.ForProvider.StrictDelete; strict == nil || !*strict {
+		return false
+	}
+	if applied := cr.Status.AtProvider.LastAppliedValue; applied != "" {
+		return record.Address != applied
+	}
+	return len(valueDrifts(cr, record.Address, normalize)) > 0
+}
+
+// recordWritten stores the host ID of cr's record just written, which
+// Namecheap assigned afresh when rewriting the domain's host records. The
+// zone is read again to find it by value; if it can't be found the ID is
+// cleared, and the next observation finds the record by value instead.
+func (c *external) recordWritten(ctx context.Context, cr *v1beta1.DNSRecord) {
+	cr.Status.AtProvider.ID = ""
+	p := cr.Spec.ForProvider
+	records, err := c.client.FindDNSRecords(namecheap.WithFreshRead(ctx), p.Domain, p.Name, p.Type)
+	if err != nil {
+		return
+	}
+	mode, _ := common.ValueCompare(cr)
+	normalize := common.ValueNormalizer(p.Type, mode)
+	for _, record := range records {
+		if len(valueDrifts(cr, record.Address, normalize)) == 0 {
+			cr.Status.AtProvider.ID = strconv.Itoa(record.HostID)
+			return
+		}
+	}
+}
+
+// zoneWrite returns the context to rewrite the domain's host records with,
+// which allows the rewrite after an unconfirmed drop in their number when
+// the allow-zone-shrink annotation is set
+func (c *external) zoneWrite(ctx context.Context, cr *v1beta1.DNSRecord) context.Context {
+	if common.AllowZoneShrink(cr) {
+		return namecheap.WithZoneShrinkAllowed(ctx)
+	}
+	return ctx
+}
+
+// zoneShrunk records a Warning event if err is a rewrite of the domain's host
+// records that was refused because Namecheap just reported far fewer of them
+// than were last known
+func (c *external) zoneShrunk(cr *v1beta1.DNSRecord, err error) {
+	if !errors.Is(err, namecheap.ErrZoneShrunk) {
+		return
+	}
+	c.recorder.Event(cr, event.Warning(reasonZoneShrunk, errors.Wrapf(err,
+		"not rewriting the host records of domain %s; set the %s annotation to \"true\" if the drop is genuine",
+		cr.Spec.ForProvider.Domain, common.AnnotationKeyAllowZoneShrink)))
+}
+
+// desiredTTL returns the desired TTL of a record. An unset TTL means
+// Namecheap's automatic TTL.
+func desiredTTL(cr *v1beta1.DNSRecord) int {
+	if cr.Spec.ForProvider.TTL != nil {
+		return *cr.Spec.ForProvider.TTL
+	}
+	return namecheap.AutomaticTTL
+}
+
+// mxPref returns the desired MX preference of a record. A priority of 0 is a
+// valid preference; an unset priority means Namecheap's default.
+func mxPref(cr *v1beta1.DNSRecord) int {
+	if cr.Spec.ForProvider.Priority != nil {
+		return *cr.Spec.ForProvider.Priority
+	}
+	return namecheap.DefaultMXPref
+}
+
+// skipDelete reports whether a failed deletion attempt should be treated as
+// successful. Records of a domain that is no longer in the account are gone
+// with it. Other failures are counted, and once the force-delete annotation's
+// threshold is reached the external record is abandoned. Either outcome is
+// recorded as an event.
+func (c *external) skipDelete(cr *v1beta1.DNSRecord, err error) bool {
+	if namecheap.IsDomainNotInAccount(err) {
+		c.recorder.Event(cr, event.Normal(reasonDeleteSkipped,
+			"Domain "+cr.Spec.ForProvider.Domain+" is no longer in the Namecheap account; treating the DNS record as deleted"))
+		return true
+	}
+
+	cr.Status.AtProvider.DeleteFailures++
+	return c.forceDelete(cr, err)
+}
+
+// forceDelete reports whether the consecutive failed deletion attempts of cr
+// have reached the force-delete annotation's threshold, in which case the
+// external record is abandoned without calling the API again. Abandoning it
+// is recorded as an event wrapping err, the reason for abandoning it.
+func (c *external) forceDelete(cr *v1beta1.DNSRecord, err error) bool {
+	if !common.ForceDelete(cr, cr.Status.AtProvider.DeleteFailures) {
+		return false
+	}
+
+	c.recorder.Event(cr, event.Warning(reasonDeleteSkipped, errors.Wrapf(err,
+		"forcing deletion after %d failed attempts; the DNS record may remain in Namecheap", cr.Status.AtProvider.DeleteFailures)))
+	return true
+}
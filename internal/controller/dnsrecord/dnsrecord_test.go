@@ -0,0 +1,1030 @@
+package dnsrecord
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/internal/controller/common"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+const (
+	hostsResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSGetHostsResult Domain="example.com" EmailType="NONE" IsUsingOurDNS="true">
+			<host HostId="1" Name="@" Type="A" Address="192.0.2.1" MXPref="10" TTL="300"/>
+		</DomainDNSGetHostsResult>
+	</CommandResponse>
+</ApiResponse>`
+
+	domainNotFoundResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="2019166">Domain not found</Error>
+	</Errors>
+</ApiResponse>`
+
+	outageResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="5050900">Unhandled exception</Error>
+	</Errors>
+</ApiResponse>`
+)
+
+// recorder captures the events recorded by an external client.
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+// withReason returns the recorded events with the given reason
+func (r *recorder) withReason(reason event.Reason) []event.Event {
+	var events []event.Event
+	for _, e := range r.events {
+		if e.Reason == reason {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// newTestExternal returns an external client backed by a fake Namecheap API
+// that answers every request with the supplied response.
+func newTestExternal(t *testing.T, response string) (*external, *recorder) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "namecheap.domains.dns.getHosts", r.FormValue("Command"))
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(response))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	client := namecheap.NewClient(namecheap.Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	rec := &recorder{}
+	return &external{client: client, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}, rec
+}
+
+func newDeletedRecord(annotations map[string]string) *v1beta1.DNSRecord {
+	now := metav1.Now()
+	cr := &v1beta1.DNSRecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "www",
+			DeletionTimestamp: &now,
+			Annotations:       annotations,
+		},
+	}
+	cr.Spec.ForProvider.Domain = "example.com"
+	cr.Spec.ForProvider.Name = "www"
+	cr.Spec.ForProvider.Type = "A"
+	cr.Spec.ForProvider.Value = "192.0.2.2"
+	return cr
+}
+
+func TestDelete_RecordNotFound(t *testing.T) {
+	e, rec := newTestExternal(t, hostsResponse)
+	cr := newDeletedRecord(nil)
+
+	_, err := e.Delete(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Empty(t, rec.events)
+	assert.Zero(t, cr.Status.AtProvider.DeleteFailures)
+}
+
+func TestDelete_DomainNotInAccount(t *testing.T) {
+	e, rec := newTestExternal(t, domainNotFoundResponse)
+	cr := newDeletedRecord(nil)
+
+	_, err := e.Delete(context.Background(), cr)
+	require.NoError(t, err)
+	require.Len(t, rec.events, 1)
+	assert.Equal(t, event.TypeNormal, rec.events[0].Type)
+	assert.Equal(t, reasonDeleteSkipped, rec.events[0].Reason)
+}
+
+func TestObserve_DeletedDomainNotInAccount(t *testing.T) {
+	e, rec := newTestExternal(t, domainNotFoundResponse)
+
+	// Outside deletion the error is surfaced
+	live := newDeletedRecord(nil)
+	live.DeletionTimestamp = nil
+	_, err := e.Observe(context.Background(), live)
+	require.Error(t, err)
+	assert.Empty(t, rec.events)
+
+	obs, err := e.Observe(context.Background(), newDeletedRecord(nil))
+	require.NoError(t, err)
+	assert.False(t, obs.ResourceExists)
+	assert.Len(t, rec.events, 1)
+}
+
+func TestDelete_Outage(t *testing.T) {
+	t.Run("without force-delete", func(t *testing.T) {
+		e, rec := newTestExternal(t, outageResponse)
+		cr := newDeletedRecord(nil)
+
+		for i := 1; i <= 3; i++ {
+			_, err := e.Delete(context.Background(), cr)
+			require.Error(t, err)
+			assert.Equal(t, i, cr.Status.AtProvider.DeleteFailures)
+		}
+		assert.Empty(t, rec.events)
+	})
+
+	t.Run("force-delete after failures", func(t *testing.T) {
+		e, rec := newTestExternal(t, outageResponse)
+		cr := newDeletedRecord(map[string]string{common.AnnotationKeyForceDeleteAfterFailures: "2"})
+
+		obs, err := e.Observe(context.Background(), cr)
+		require.Error(t, err, "first failure should be reported")
+		assert.False(t, obs.ResourceExists)
+		assert.Equal(t, 1, cr.Status.AtProvider.DeleteFailures)
+		assert.Empty(t, rec.events)
+
+		_, err = e.Delete(context.Background(), cr)
+		require.NoError(t, err, "deletion should be forced at the threshold")
+		assert.Equal(t, 2, cr.Status.AtProvider.DeleteFailures)
+		require.Len(t, rec.events, 1)
+		assert.Equal(t, event.TypeWarning, rec.events[0].Type)
+		assert.Equal(t, reasonDeleteSkipped, rec.events[0].Reason)
+	})
+}
+
+func TestDelete_ForceDeleteThreshold(t *testing.T) {
+	zone := &fakeZone{
+		domain:  "example.com",
+		records: []namecheap.DNSRecord{{Name: "www", Type: "A", Address: "192.0.2.2", TTL: 300}},
+	}
+
+	// A successful deletion resets the count of failed attempts
+	e, _ := newZoneExternal(t, zone)
+	cr := newDeletedRecord(map[string]string{common.AnnotationKeyForceDeleteAfterFailures: "3"})
+	cr.Status.AtProvider.DeleteFailures = 2
+	_, err := e.Delete(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Zero(t, cr.Status.AtProvider.DeleteFailures)
+	require.Len(t, zone.written, 1)
+
+	// Once the threshold is met, e.g. by lowering it, the API isn't called
+	// again and the record is left in place
+	zone.written = nil
+	zone.records = []namecheap.DNSRecord{{Name: "www", Type: "A", Address: "192.0.2.2", TTL: 300}}
+	e, rec := newZoneExternal(t, zone)
+	cr = newDeletedRecord(map[string]string{common.AnnotationKeyForceDeleteAfterFailures: "2"})
+	cr.Status.AtProvider.DeleteFailures = 2
+	obs, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, obs.ResourceExists)
+	_, err = e.Delete(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Empty(t, zone.written)
+	assert.Len(t, zone.records, 1)
+	assert.Len(t, rec.withReason(reasonDeleteSkipped), 2)
+}
+
+func TestObserve_MXPriority(t *testing.T) {
+	const mxHosts = `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSGetHostsResult Domain="example.com" EmailType="MX" IsUsingOurDNS="true">
+			<host HostId="1" Name="@" Type="MX" Address="mail.example.com" MXPref="%d" TTL="300"/>
+		</DomainDNSGetHostsResult>
+	</CommandResponse>
+</ApiResponse>`
+
+	tests := []struct {
+		name     string
+		observed int
+		desired  *int
+		upToDate bool
+	}{
+		{name: "priority 0 matches", observed: 0, desired: intPtr(0), upToDate: true},
+		{name: "priority 0 drifted to 10", observed: 10, desired: intPtr(0)},
+		{name: "priority 10 matches", observed: 10, desired: intPtr(10), upToDate: true},
+		{name: "priority 10 drifted to 0", observed: 0, desired: intPtr(10)},
+		{name: "unset priority", observed: 20, upToDate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _ := newTestExternal(t, fmt.Sprintf(mxHosts, tt.observed))
+
+			cr := &v1beta1.DNSRecord{}
+			cr.Spec.ForProvider.Domain = "example.com"
+			cr.Spec.ForProvider.Name = "@"
+			cr.Spec.ForProvider.Type = "MX"
+			cr.Spec.ForProvider.Value = "mail.example.com"
+			cr.Spec.ForProvider.Priority = tt.desired
+
+			obs, err := e.Observe(context.Background(), cr)
+			require.NoError(t, err)
+			assert.True(t, obs.ResourceExists)
+			assert.Equal(t, tt.upToDate, obs.ResourceUpToDate)
+		})
+	}
+}
+
+func TestObserve_AutomaticTTL(t *testing.T) {
+	const ttlHosts = `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSGetHostsResult Domain="example.com" EmailType="NONE" IsUsingOurDNS="true">
+			<host HostId="1" Name="@" Type="A" Address="192.0.2.1" MXPref="10" TTL="%d"/>
+		</DomainDNSGetHostsResult>
+	</CommandResponse>
+</ApiResponse>`
+
+	tests := []struct {
+		name            string
+		observed        int
+		desired         *int
+		adopted         bool
+		upToDate        bool
+		lateInitialized *int
+	}{
+		{name: "unset matches automatic", observed: namecheap.AutomaticTTL, upToDate: true},
+		{name: "unset drifted from automatic", observed: 300},
+		{name: "automatic matches automatic", observed: namecheap.AutomaticTTL, desired: intPtr(namecheap.AutomaticTTL), upToDate: true},
+		{name: "set drifted to automatic", observed: namecheap.AutomaticTTL, desired: intPtr(300)},
+		{name: "set matches", observed: 300, desired: intPtr(300), upToDate: true},
+		{name: "adopted automatic", observed: namecheap.AutomaticTTL, adopted: true, upToDate: true},
+		{name: "adopted late-initialized", observed: 300, adopted: true, upToDate: true, lateInitialized: intPtr(300)},
+		{name: "adopted set", observed: 300, desired: intPtr(600), adopted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _ := newTestExternal(t, fmt.Sprintf(ttlHosts, tt.observed))
+
+			cr := &v1beta1.DNSRecord{}
+			cr.Spec.ForProvider.Domain = "example.com"
+			cr.Spec.ForProvider.Name = "@"
+			cr.Spec.ForProvider.Type = "A"
+			cr.Spec.ForProvider.Value = "192.0.2.1"
+			cr.Spec.ForProvider.TTL = tt.desired
+			if !tt.adopted {
+				cr.Status.AtProvider.LastAppliedValue = "192.0.2.1"
+			}
+
+			obs, err := e.Observe(context.Background(), cr)
+			require.NoError(t, err)
+			assert.True(t, obs.ResourceExists)
+			assert.Equal(t, tt.upToDate, obs.ResourceUpToDate)
+			assert.Equal(t, tt.lateInitialized != nil, obs.ResourceLateInitialized)
+			if tt.lateInitialized != nil {
+				assert.Equal(t, tt.lateInitialized, cr.Spec.ForProvider.TTL)
+			} else {
+				assert.Equal(t, tt.desired, cr.Spec.ForProvider.TTL)
+			}
+		})
+	}
+}
+
+func TestObserve_Redirect(t *testing.T) {
+	const redirectHosts = `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSGetHostsResult Domain="example.com" EmailType="NONE" IsUsingOurDNS="true">
+			<host HostId="7" Name="go" Type="URL301" Address="https://www.example.net/landing?utm_source=vanity&amp;utm_medium=dns" MXPref="10" TTL="1800"/>
+		</DomainDNSGetHostsResult>
+	</CommandResponse>
+</ApiResponse>`
+
+	tests := []struct {
+		name     string
+		desired  string
+		upToDate bool
+	}{
+		{name: "same target", desired: "https://www.example.net/landing?utm_source=vanity&utm_medium=dns", upToDate: true},
+		{name: "different query", desired: "https://www.example.net/landing?utm_source=vanity&utm_medium=email"},
+		{name: "different path case", desired: "https://www.example.net/Landing?utm_source=vanity&utm_medium=dns"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _ := newTestExternal(t, redirectHosts)
+
+			cr := &v1beta1.DNSRecord{}
+			cr.Spec.ForProvider.Domain = "example.com"
+			cr.Spec.ForProvider.Name = "go"
+			cr.Spec.ForProvider.Type = "URL301"
+			cr.Spec.ForProvider.Value = tt.desired
+			cr.Status.AtProvider.ID = "7"
+
+			obs, err := e.Observe(context.Background(), cr)
+			require.NoError(t, err)
+			assert.True(t, obs.ResourceExists)
+			assert.Equal(t, tt.upToDate, obs.ResourceUpToDate)
+			assert.Equal(t, "7", cr.Status.AtProvider.ID)
+			assert.Equal(t, "https://www.example.net/landing?utm_source=vanity&utm_medium=dns", cr.Status.AtProvider.NormalizedValue)
+		})
+	}
+}
+
+func TestObserve_DriftSuspended(t *testing.T) {
+	tests := []struct {
+		name         string
+		suspendUntil time.Duration
+		upToDate     bool
+		eventType    event.Type
+	}{
+		{name: "active", suspendUntil: time.Hour, upToDate: true, eventType: event.TypeNormal},
+		{name: "expired", suspendUntil: -time.Minute},
+		{name: "over maximum", suspendUntil: common.DefaultMaxDriftSuspension + time.Hour, eventType: event.TypeWarning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, rec := newTestExternal(t, hostsResponse)
+
+			// The record was hot-fixed away from the spec's value
+			cr := &v1beta1.DNSRecord{}
+			cr.SetAnnotations(map[string]string{
+				common.AnnotationKeySuspendUntil: time.Now().Add(tt.suspendUntil).Format(time.RFC3339),
+			})
+			cr.Spec.ForProvider.Domain = "example.com"
+			cr.Spec.ForProvider.Name = "@"
+			cr.Spec.ForProvider.Type = "A"
+			cr.Spec.ForProvider.Value = "192.0.2.2"
+			cr.Status.AtProvider.ID = "1"
+
+			obs, err := e.Observe(context.Background(), cr)
+			require.NoError(t, err)
+			assert.True(t, obs.ResourceExists)
+			assert.Equal(t, tt.upToDate, obs.ResourceUpToDate)
+
+			events := rec.withReason(reasonDriftSuspended)
+			if tt.eventType == "" {
+				assert.Empty(t, events)
+				return
+			}
+			require.Len(t, events, 1)
+			assert.Equal(t, tt.eventType, events[0].Type)
+		})
+	}
+}
+
+func TestMXPref(t *testing.T) {
+	cr := &v1beta1.DNSRecord{}
+	assert.Equal(t, namecheap.DefaultMXPref, mxPref(cr))
+
+	cr.Spec.ForProvider.Priority = intPtr(0)
+	assert.Equal(t, 0, mxPref(cr))
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestObserve_FailedReadKeepsStatus(t *testing.T) {
+	// The record is found with a single read of the zone, which fails
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(outageResponse))
+	}))
+	t.Cleanup(server.Close)
+
+	e := &external{
+		client: namecheap.NewClient(namecheap.Config{
+			APIUser:    "testuser",
+			APIKey:     "testkey",
+			Username:   "testuser",
+			ClientIP:   "127.0.0.1",
+			BaseURL:    server.URL,
+			HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		}),
+		recorder: &recorder{},
+	}
+
+	cr := &v1beta1.DNSRecord{}
+	cr.Spec.ForProvider.Domain = "example.com"
+	cr.Spec.ForProvider.Name = "www"
+	cr.Spec.ForProvider.Type = "A"
+	cr.Spec.ForProvider.Value = "192.0.2.3"
+	cr.Status.AtProvider.ID = "1"
+	cr.Status.AtProvider.LastAppliedValue = "192.0.2.2"
+	observed := cr.Status.AtProvider.DeepCopy()
+
+	_, err := e.Observe(context.Background(), cr)
+	require.Error(t, err)
+	assert.Positive(t, calls)
+	assert.Equal(t, observed, &cr.Status.AtProvider)
+}
+
+func TestObserve_DriftEvents(t *testing.T) {
+	e, rec := newTestExternal(t, hostsResponse)
+
+	cr := &v1beta1.DNSRecord{}
+	cr.SetUID("7f9c3a2e")
+	cr.Spec.ForProvider.Domain = "example.com"
+	cr.Spec.ForProvider.Name = "@"
+	cr.Spec.ForProvider.Type = "A"
+	cr.Spec.ForProvider.Value = "192.0.2.2"
+	cr.Status.AtProvider.ID = "1"
+
+	_, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	events := rec.withReason(common.ReasonDriftDetected)
+	require.Len(t, events, 1)
+	assert.Equal(t, event.TypeWarning, events[0].Type)
+	assert.Equal(t, `spec.forProvider.value drifted: expected "192.0.2.2", observed "192.0.2.1"; correcting it`, events[0].Message)
+
+	// The same drift isn't reported again within the sync interval
+	_, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Len(t, rec.withReason(common.ReasonDriftDetected), 1)
+
+	// Drift that matches the spec again reports nothing
+	rec.events = nil
+	cr.Spec.ForProvider.Value = "192.0.2.1"
+	_, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Empty(t, rec.events)
+}
+
+func TestCreate_ZoneShrunk(t *testing.T) {
+	// The zone holds five records, then getHosts transiently reports none
+	hosts := 5
+	setHosts := 0
+	written := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		switch command := r.FormValue("Command"); command {
+		case "namecheap.domains.dns.getHosts":
+			records := ""
+			for i := 1; i <= hosts; i++ {
+				records += fmt.Sprintf(`<host HostId="%d" Name="host%d" Type="A" Address="192.0.2.%d" TTL="300"/>`, i, i, i)
+			}
+			// Once rewritten, the zone reads back as written
+			if setHosts > 0 {
+				records = written
+			}
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSGetHostsResult Domain="zone.example" IsUsingOurDNS="true">%s</DomainDNSGetHostsResult>
+	</CommandResponse>
+</ApiResponse>`, records)
+		case "namecheap.domains.dns.setHosts":
+			setHosts++
+			written = fmt.Sprintf(`<host HostId="1" Name="%s" Type="%s" Address="%s" TTL="300"/>`,
+				r.FormValue("HostName1"), r.FormValue("RecordType1"), r.FormValue("Address1"))
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSSetHostsResult Domain="zone.example" IsSuccess="true"/>
+	</CommandResponse>
+</ApiResponse>`)
+		default:
+			t.Errorf("unexpected command %s", command)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	rec := &recorder{}
+	e := &external{
+		client: namecheap.NewClient(namecheap.Config{
+			APIUser:    "zoneuser",
+			APIKey:     "testkey",
+			Username:   "zoneuser",
+			ClientIP:   "127.0.0.1",
+			BaseURL:    server.URL,
+			HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		}),
+		recorder: rec,
+		drift:    common.NewDriftEvents(rec, time.Minute),
+	}
+
+	cr := &v1beta1.DNSRecord{}
+	cr.Spec.ForProvider.Domain = "zone.example"
+	cr.Spec.ForProvider.Name = "www"
+	cr.Spec.ForProvider.Type = "A"
+	cr.Spec.ForProvider.Value = "192.0.2.10"
+
+	o, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, o.ResourceExists)
+
+	// The empty read makes the record look missing, but creating it must
+	// not rewrite the zone with it alone
+	hosts = 0
+	o, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, o.ResourceExists)
+	_, err = e.Create(context.Background(), cr)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, namecheap.ErrZoneShrunk)
+	assert.Zero(t, setHosts)
+	require.Len(t, rec.withReason(reasonZoneShrunk), 1)
+	assert.Equal(t, event.TypeWarning, rec.withReason(reasonZoneShrunk)[0].Type)
+
+	// The override annotation lets the write through
+	cr.SetAnnotations(map[string]string{common.AnnotationKeyAllowZoneShrink: "true"})
+	_, err = e.Create(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, 1, setHosts)
+}
+
+func TestObserve_ValueCompare(t *testing.T) {
+	const cnameHosts = `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSGetHostsResult Domain="example.com" EmailType="NONE" IsUsingOurDNS="true">
+			<host HostId="1" Name="www" Type="CNAME" Address="target.example.com" MXPref="10" TTL="300"/>
+		</DomainDNSGetHostsResult>
+	</CommandResponse>
+</ApiResponse>`
+
+	tests := []struct {
+		name        string
+		annotation  string
+		upToDate    bool
+		lastApplied string
+		warning     bool
+	}{
+		{name: "default", upToDate: true, lastApplied: "target.example.com"},
+		{name: "relaxed", annotation: common.ValueCompareRelaxed, upToDate: true, lastApplied: "target.example.com"},
+		{name: "exact", annotation: common.ValueCompareExact, lastApplied: "Target.Example.com."},
+		{name: "unknown", annotation: "loose", upToDate: true, lastApplied: "target.example.com", warning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, rec := newTestExternal(t, cnameHosts)
+
+			// Namecheap stored the target lowercased without its trailing dot
+			cr := &v1beta1.DNSRecord{}
+			if tt.annotation != "" {
+				cr.SetAnnotations(map[string]string{common.AnnotationKeyValueCompare: tt.annotation})
+			}
+			cr.Spec.ForProvider.Domain = "example.com"
+			cr.Spec.ForProvider.Name = "www"
+			cr.Spec.ForProvider.Type = "CNAME"
+			cr.Spec.ForProvider.Value = "Target.Example.com."
+			cr.Spec.ForProvider.TTL = intPtr(300)
+			cr.Status.AtProvider.LastAppliedValue = "Target.Example.com."
+			cr.Status.AtProvider.ID = "1"
+
+			obs, err := e.Observe(context.Background(), cr)
+			require.NoError(t, err)
+			assert.True(t, obs.ResourceExists)
+			assert.Equal(t, tt.upToDate, obs.ResourceUpToDate)
+			assert.Equal(t, "target.example.com", cr.Status.AtProvider.NormalizedValue)
+			assert.Equal(t, tt.lastApplied, cr.Status.AtProvider.LastAppliedValue)
+			assert.Equal(t, tt.warning, len(rec.withReason(reasonValueCompare)) == 1)
+		})
+	}
+}
+
+// fakeZone is a domain's host records served by a fake Namecheap API, which
+// replaces them on domains.dns.setHosts
+type fakeZone struct {
+	domain  string
+	records []namecheap.DNSRecord
+	written []url.Values
+}
+
+// newZoneExternal returns an external client backed by a fake Namecheap API
+// serving zone
+func newZoneExternal(t *testing.T, zone *fakeZone) (*external, *recorder) {
+	t.Helper()
+
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/xml")
+		switch command := r.FormValue("Command"); command {
+		case "namecheap.domains.dns.getHosts":
+			hosts := ""
+			for i, record := range zone.records {
+				hosts += fmt.Sprintf(`<host HostId="%d" Name="%s" Type="%s" Address="%s" MXPref="%d" TTL="%d" IsDDNSEnabled="%t"/>`,
+					i+1, html.EscapeString(record.Name), record.Type, html.EscapeString(record.Address), record.MXPref, record.TTL, record.IsDDNSEnabled)
+			}
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSGetHostsResult Domain="%s" IsUsingOurDNS="true">%s</DomainDNSGetHostsResult>
+	</CommandResponse>
+</ApiResponse>`, zone.domain, hosts)
+		case "namecheap.domains.dns.setHosts":
+			require.NoError(t, r.ParseForm())
+			zone.written = append(zone.written, r.Form)
+			zone.records = nil
+			for i := 1; r.FormValue(fmt.Sprintf("HostName%d", i)) != ""; i++ {
+				ttl, _ := strconv.Atoi(r.FormValue(fmt.Sprintf("TTL%d", i)))
+				mxPref, _ := strconv.Atoi(r.FormValue(fmt.Sprintf("MXPref%d", i)))
+				zone.records = append(zone.records, namecheap.DNSRecord{
+					Name:    r.FormValue(fmt.Sprintf("HostName%d", i)),
+					Type:    r.FormValue(fmt.Sprintf("RecordType%d", i)),
+					Address: r.FormValue(fmt.Sprintf("Address%d", i)),
+					MXPref:  mxPref,
+					TTL:     ttl,
+				})
+			}
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSSetHostsResult Domain="%s" IsSuccess="true"/>
+	</CommandResponse>
+</ApiResponse>`, zone.domain)
+		default:
+			t.Errorf("unexpected command %s", command)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	rec := &recorder{}
+	return &external{
+		client: namecheap.NewClient(namecheap.Config{
+			APIUser:             "zoneuser",
+			APIKey:              "testkey",
+			Username:            "zoneuser",
+			ClientIP:            "127.0.0.1",
+			BaseURL:             server.URL,
+			HTTPClient:          &http.Client{Timeout: 5 * time.Second},
+			RateLimitConfig:     &namecheap.RateLimitConfig{RequestsPerSecond: 1000, BurstSize: 1000},
+			DomainWriteInterval: -1,
+		}),
+		recorder: rec,
+		drift:    common.NewDriftEvents(rec, time.Minute),
+	}, rec
+}
+
+func TestCAA(t *testing.T) {
+	zone := &fakeZone{domain: "caa.example"}
+	e, _ := newZoneExternal(t, zone)
+
+	cr := &v1beta1.DNSRecord{}
+	cr.Spec.ForProvider.Domain = "caa.example"
+	cr.Spec.ForProvider.Name = "@"
+	cr.Spec.ForProvider.Type = "CAA"
+	cr.Spec.ForProvider.CAA = &v1beta1.CAARecord{Tag: "issue", Value: "letsencrypt.org"}
+
+	// The structured value is composed into the Address Namecheap expects
+	_, err := e.Create(context.Background(), cr)
+	require.NoError(t, err)
+	require.Len(t, zone.written, 1)
+	assert.Equal(t, `0 issue "letsencrypt.org"`, zone.written[0].Get("Address1"))
+	assert.Equal(t, "1799", zone.written[0].Get("TTL1"), "an unset TTL should be automatic")
+	assert.Equal(t, `0 issue "letsencrypt.org"`, cr.Status.AtProvider.LastAppliedValue)
+
+	// Namecheap may store the value unquoted, which isn't drift
+	zone.records[0].Address = "0 issue letsencrypt.org"
+	obs, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, obs.ResourceExists)
+	assert.True(t, obs.ResourceUpToDate)
+
+	// A changed field is, and is corrected
+	cr.Spec.ForProvider.CAA.Flags = 128
+	obs, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, obs.ResourceUpToDate)
+	_, err = e.Update(context.Background(), cr)
+	require.NoError(t, err)
+	require.Len(t, zone.written, 2)
+	assert.Equal(t, `128 issue "letsencrypt.org"`, zone.written[1].Get("Address1"))
+
+	// The whole value may still be given in value
+	legacy := cr.DeepCopy()
+	legacy.Spec.ForProvider.CAA = nil
+	legacy.Spec.ForProvider.Value = `128 issue "letsencrypt.org"`
+	obs, err = e.Observe(context.Background(), legacy)
+	require.NoError(t, err)
+	assert.True(t, obs.ResourceUpToDate)
+}
+
+func TestSRV(t *testing.T) {
+	zone := &fakeZone{domain: "srv.example"}
+	e, _ := newZoneExternal(t, zone)
+
+	cr := &v1beta1.DNSRecord{}
+	cr.Spec.ForProvider.Domain = "srv.example"
+	cr.Spec.ForProvider.Name = "_sip._tcp"
+	cr.Spec.ForProvider.Type = "SRV"
+	cr.Spec.ForProvider.Value = "sip.example.com"
+	cr.Spec.ForProvider.Priority = intPtr(10)
+	cr.Spec.ForProvider.Weight = intPtr(60)
+	cr.Spec.ForProvider.Port = intPtr(5060)
+
+	// The priority, weight, port and target are composed into the Address
+	_, err := e.Create(context.Background(), cr)
+	require.NoError(t, err)
+	require.Len(t, zone.written, 1)
+	assert.Equal(t, "_sip._tcp", zone.written[0].Get("HostName1"))
+	assert.Equal(t, "10 60 5060 sip.example.com", zone.written[0].Get("Address1"))
+	assert.Equal(t, "10", zone.written[0].Get("MXPref1"))
+
+	obs, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, obs.ResourceExists)
+	assert.True(t, obs.ResourceUpToDate)
+
+	// A changed weight or port is drift, and is corrected
+	for _, change := range []func(){
+		func() { cr.Spec.ForProvider.Weight = intPtr(40) },
+		func() { cr.Spec.ForProvider.Port = intPtr(5061) },
+	} {
+		change()
+		obs, err = e.Observe(context.Background(), cr)
+		require.NoError(t, err)
+		assert.False(t, obs.ResourceUpToDate)
+
+		_, err = e.Update(context.Background(), cr)
+		require.NoError(t, err)
+		obs, err = e.Observe(context.Background(), cr)
+		require.NoError(t, err)
+		assert.True(t, obs.ResourceUpToDate)
+	}
+	require.Len(t, zone.written, 3)
+	assert.Equal(t, "10 40 5061 sip.example.com", zone.written[2].Get("Address1"))
+	require.Len(t, zone.records, 1)
+	assert.Equal(t, "10 40 5061 sip.example.com", zone.records[0].Address)
+}
+
+func TestRoundRobin(t *testing.T) {
+	zone := &fakeZone{domain: "roundrobin.example", records: []namecheap.DNSRecord{
+		{Name: "www", Type: "A", Address: "192.0.2.1", MXPref: 10, TTL: 300},
+		{Name: "www", Type: "A", Address: "192.0.2.2", MXPref: 10, TTL: 300},
+	}}
+	e, _ := newZoneExternal(t, zone)
+
+	newRecord := func(value string) *v1beta1.DNSRecord {
+		cr := &v1beta1.DNSRecord{}
+		cr.Spec.ForProvider.Domain = "roundrobin.example"
+		cr.Spec.ForProvider.Name = "www"
+		cr.Spec.ForProvider.Type = "A"
+		cr.Spec.ForProvider.Value = value
+		return cr
+	}
+	first, second := newRecord("192.0.2.1"), newRecord("192.0.2.2")
+
+	// Each resource adopts the record with its value, and the other
+	// record of the name isn't drift
+	for _, cr := range []*v1beta1.DNSRecord{first, second} {
+		obs, err := e.Observe(context.Background(), cr)
+		require.NoError(t, err)
+		assert.True(t, obs.ResourceExists)
+		assert.True(t, obs.ResourceUpToDate)
+	}
+	assert.Equal(t, "1", first.Status.AtProvider.ID)
+	assert.Equal(t, "2", second.Status.AtProvider.ID)
+
+	// A third value is a record yet to be created
+	obs, err := e.Observe(context.Background(), newRecord("192.0.2.3"))
+	require.NoError(t, err)
+	assert.False(t, obs.ResourceExists)
+
+	// Once adopted, the record is identified by its host ID whatever its
+	// value, and only it is corrected
+	zone.records[1].Address = "192.0.2.9"
+	obs, err = e.Observe(context.Background(), second)
+	require.NoError(t, err)
+	assert.True(t, obs.ResourceExists)
+	assert.False(t, obs.ResourceUpToDate)
+	_, err = e.Update(context.Background(), second)
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.1", zone.records[0].Address)
+	assert.Equal(t, "192.0.2.2", zone.records[1].Address)
+
+	// Deleting a resource only deletes its own record
+	_, err = e.Delete(context.Background(), first)
+	require.NoError(t, err)
+	require.Len(t, zone.records, 1)
+	assert.Equal(t, "192.0.2.2", zone.records[0].Address)
+}
+
+func TestALIAS(t *testing.T) {
+	zone := &fakeZone{domain: "alias.example", records: []namecheap.DNSRecord{
+		{Name: "www", Type: "CNAME", Address: "alias.example.", MXPref: 10, TTL: 1800},
+	}}
+	e, _ := newZoneExternal(t, zone)
+
+	cr := &v1beta1.DNSRecord{}
+	cr.Spec.ForProvider.Domain = "alias.example"
+	cr.Spec.ForProvider.Name = "@"
+	cr.Spec.ForProvider.Type = "ALIAS"
+	cr.Spec.ForProvider.Value = "Example.CDN.net."
+	cr.Spec.ForProvider.TTL = intPtr(300)
+
+	obs, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, obs.ResourceExists)
+
+	// The apex ALIAS is written alongside the other records, with its
+	// target normalized
+	_, err = e.Create(context.Background(), cr)
+	require.NoError(t, err)
+	require.Len(t, zone.written, 1)
+	assert.Equal(t, "@", zone.written[0].Get("HostName2"))
+	assert.Equal(t, "ALIAS", zone.written[0].Get("RecordType2"))
+	assert.Equal(t, "example.cdn.net", zone.written[0].Get("Address2"))
+	assert.Equal(t, "300", zone.written[0].Get("TTL2"))
+
+	// Namecheap storing the target fully qualified isn't drift
+	zone.records[1].Address = "example.cdn.net."
+	obs, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, obs.ResourceExists)
+	assert.True(t, obs.ResourceUpToDate)
+
+	// but another target is, and is corrected
+	zone.records[1].Address = "other.cdn.net"
+	obs, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, obs.ResourceUpToDate)
+	_, err = e.Update(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, "example.cdn.net", zone.records[1].Address)
+
+	// Deleting it leaves the other records
+	_, err = e.Delete(context.Background(), cr)
+	require.NoError(t, err)
+	require.Len(t, zone.records, 1)
+	assert.Equal(t, "CNAME", zone.records[0].Type)
+}
+
+func TestWrittenValue(t *testing.T) {
+	zone := &fakeZone{domain: "normalize.example"}
+	e, _ := newZoneExternal(t, zone)
+
+	cr := &v1beta1.DNSRecord{}
+	cr.Spec.ForProvider.Domain = "normalize.example"
+	cr.Spec.ForProvider.Name = "www"
+	cr.Spec.ForProvider.Type = "CNAME"
+	cr.Spec.ForProvider.Value = "Target.Example.com."
+
+	// The target is written in the form it is compared in
+	_, err := e.Create(context.Background(), cr)
+	require.NoError(t, err)
+	require.Len(t, zone.written, 1)
+	assert.Equal(t, "target.example.com", zone.written[0].Get("Address1"))
+	assert.Equal(t, "target.example.com", cr.Status.AtProvider.LastAppliedValue)
+
+	// and isn't rewritten on the next poll
+	obs, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, obs.ResourceUpToDate)
+
+	// Exactly compared values are written exactly as given
+	cr.SetAnnotations(map[string]string{common.AnnotationKeyValueCompare: common.ValueCompareExact})
+	obs, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, obs.ResourceUpToDate)
+	_, err = e.Update(context.Background(), cr)
+	require.NoError(t, err)
+	require.Len(t, zone.written, 2)
+	assert.Equal(t, "Target.Example.com.", zone.written[1].Get("Address1"))
+}
+
+func TestObserve_DynamicDNS(t *testing.T) {
+	zone := &fakeZone{domain: "ddns.example", records: []namecheap.DNSRecord{
+		{Name: "home", Type: "A", Address: "192.0.2.1", MXPref: 10, TTL: 60, IsDDNSEnabled: true},
+		{Name: "www", Type: "A", Address: "192.0.2.2", MXPref: 10, TTL: 60},
+	}}
+	e, _ := newZoneExternal(t, zone)
+
+	for _, record := range zone.records {
+		cr := &v1beta1.DNSRecord{}
+		cr.Spec.ForProvider.Domain = "ddns.example"
+		cr.Spec.ForProvider.Name = record.Name
+		cr.Spec.ForProvider.Type = record.Type
+		cr.Spec.ForProvider.Value = record.Address
+
+		obs, err := e.Observe(context.Background(), cr)
+		require.NoError(t, err)
+		assert.True(t, obs.ResourceExists)
+		assert.Equal(t, record.IsDDNSEnabled, cr.Status.AtProvider.DynamicDNS, record.Name)
+	}
+}
+
+func TestStrictDelete(t *testing.T) {
+	zone := &fakeZone{domain: "strict.example"}
+	e, rec := newZoneExternal(t, zone)
+
+	cr := &v1beta1.DNSRecord{}
+	cr.Spec.ForProvider.Domain = "strict.example"
+	cr.Spec.ForProvider.Name = "www"
+	cr.Spec.ForProvider.Type = "A"
+	cr.Spec.ForProvider.Value = "192.0.2.1"
+	cr.Spec.ForProvider.StrictDelete = boolPtr(true)
+
+	// The managed reconciler discards the status Create sets, so the value
+	// applied is recorded again once observed
+	_, err := e.Create(context.Background(), cr)
+	require.NoError(t, err)
+	cr.Status = v1beta1.DNSRecordStatus{}
+	obs, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, obs.ResourceUpToDate)
+	assert.Equal(t, "192.0.2.1", cr.Status.AtProvider.LastAppliedValue)
+
+	// Once repointed out-of-band, the record is left in place and the
+	// deleted resource reported gone, rather than deletion being attempted
+	// forever
+	zone.records[0].Address = "198.51.100.7"
+	now := metav1.Now()
+	cr.SetDeletionTimestamp(&now)
+	obs, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, obs.ResourceExists)
+	require.Len(t, rec.withReason(reasonDeleteSkipped), 1)
+
+	_, err = e.Delete(context.Background(), cr)
+	require.NoError(t, err)
+	require.Len(t, zone.records, 1)
+	assert.Equal(t, "198.51.100.7", zone.records[0].Address)
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		records     []namecheap.DNSRecord
+		strict      bool
+		lastApplied string
+		wantWrites  int
+	}{
+		{
+			// Regression: a record removed out-of-band used to wedge the finalizer
+			name:        "record already deleted out-of-band",
+			records:     []namecheap.DNSRecord{{Name: "mail", Type: "A", Address: "192.0.2.9", TTL: 300}},
+			lastApplied: "192.0.2.1",
+		},
+		{
+			name:        "strict mode deletes unchanged record",
+			records:     []namecheap.DNSRecord{{Name: "www", Type: "A", Address: "192.0.2.1", TTL: 300}},
+			strict:      true,
+			lastApplied: "192.0.2.1",
+			wantWrites:  1,
+		},
+		{
+			name:        "strict mode leaves repointed record",
+			records:     []namecheap.DNSRecord{{Name: "www", Type: "A", Address: "198.51.100.7", TTL: 300}},
+			strict:      true,
+			lastApplied: "192.0.2.1",
+		},
+		{
+			name:        "strict mode tolerates missing record",
+			strict:      true,
+			lastApplied: "192.0.2.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone := &fakeZone{domain: "example.com", records: tt.records}
+			e, _ := newZoneExternal(t, zone)
+
+			cr := newDeletedRecord(nil)
+			cr.Spec.ForProvider.Value = "192.0.2.1"
+			cr.Spec.ForProvider.StrictDelete = boolPtr(tt.strict)
+			cr.Status.AtProvider.LastAppliedValue = tt.lastApplied
+
+			_, err := e.Delete(context.Background(), cr)
+			require.NoError(t, err)
+			assert.Len(t, zone.written, tt.wantWrites)
+		})
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,180 @@
+package dnsrecord
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/internal/controller/common"
+	"github.com/rossigee/provider-namecheap/internal/fakeserver"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+func TestIdempotency_Restart(t *testing.T) {
+	tests := []struct {
+		name string
+		// lose simulates the status lost while the provider was down, as
+		// when a resource is restored from a backup
+		lose func(cr *v1beta1.DNSRecord)
+	}{
+		{name: "StatusKept", lose: func(*v1beta1.DNSRecord) {}},
+		{name: "StatusLost", lose: func(cr *v1beta1.DNSRecord) { cr.Status = v1beta1.DNSRecordStatus{} }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := fakeserver.New(t)
+			server.AddDomain("example.com")
+			h := fakeserver.NewHarness(server, func(client *namecheap.Client) managed.ExternalClient {
+				rec := &recorder{}
+				return &external{client: client, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}
+			})
+
+			www := &v1beta1.DNSRecord{}
+			www.SetName("www")
+			www.Spec.ForProvider.Domain = "example.com"
+			www.Spec.ForProvider.Name = "www"
+			www.Spec.ForProvider.Type = "A"
+			www.Spec.ForProvider.Value = "192.0.2.1"
+
+			priority, ttl := 10, 3600
+			mx := &v1beta1.DNSRecord{}
+			mx.SetName("mx")
+			mx.Spec.ForProvider.Domain = "example.com"
+			mx.Spec.ForProvider.Name = "@"
+			mx.Spec.ForProvider.Type = "MX"
+			mx.Spec.ForProvider.Value = "mail.example.com"
+			mx.Spec.ForProvider.Priority = &priority
+			mx.Spec.ForProvider.TTL = &ttl
+
+			records := []*v1beta1.DNSRecord{www, mx}
+
+			ctx := context.Background()
+			reconcile := func(cr *v1beta1.DNSRecord) managed.ExternalObservation {
+				t.Helper()
+				obs, err := h.Reconcile(ctx, cr)
+				require.NoError(t, err)
+				return obs
+			}
+
+			for _, cr := range records {
+				reconcile(cr)
+				require.True(t, reconcile(cr).ResourceUpToDate)
+			}
+			writes := server.Calls(namecheap.CommandDomainsDNSSetHosts)
+			require.Equal(t, 2, writes)
+
+			for _, cr := range records {
+				tc.lose(cr)
+			}
+			h.Restart()
+
+			for range 3 {
+				for _, cr := range records {
+					obs := reconcile(cr)
+					assert.True(t, obs.ResourceExists)
+					assert.True(t, obs.ResourceUpToDate)
+				}
+			}
+
+			assert.Zero(t, server.BillableCalls())
+			assert.Equal(t, writes, server.Calls(namecheap.CommandDomainsDNSSetHosts),
+				"host records should not be rewritten after the restart")
+
+			d := server.Domain("example.com")
+			require.NotNil(t, d)
+			require.Len(t, d.Hosts, 2, "each record should be created once")
+			for i, cr := range records {
+				host := d.Hosts[i]
+				assert.Equal(t, cr.Spec.ForProvider.Name, host.Name)
+				assert.Equal(t, cr.Spec.ForProvider.Value, host.Address)
+				assert.Equal(t, strconv.Itoa(host.HostID), cr.Status.AtProvider.ID)
+				assert.Equal(t, cr.Spec.ForProvider.Value, cr.Status.AtProvider.LastAppliedValue)
+				assert.Equal(t, "example.com/"+cr.Spec.ForProvider.Type+"/"+cr.Spec.ForProvider.Name, meta.GetExternalName(cr))
+				assert.Equal(t, corev1.ConditionTrue, cr.GetCondition(xpv1.TypeReady).Status)
+			}
+			assert.Equal(t, 10, d.Hosts[1].MXPref)
+			assert.Equal(t, 3600, d.Hosts[1].TTL)
+		})
+	}
+}
+
+func TestHostIDs(t *testing.T) {
+	// The fake assigns new host IDs whenever it rewrites the host records
+	server := fakeserver.New(t)
+	server.AddDomain("example.com")
+	var e *external
+	h := fakeserver.NewHarness(server, func(client *namecheap.Client) managed.ExternalClient {
+		rec := &recorder{}
+		e = &external{client: client, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}
+		return e
+	})
+
+	newRecord := func(value string) *v1beta1.DNSRecord {
+		cr := &v1beta1.DNSRecord{}
+		cr.Spec.ForProvider.Domain = "example.com"
+		cr.Spec.ForProvider.Name = "www"
+		cr.Spec.ForProvider.Type = "A"
+		cr.Spec.ForProvider.Value = value
+		return cr
+	}
+	first, second := newRecord("192.0.2.1"), newRecord("192.0.2.2")
+
+	ctx := context.Background()
+	hostID := func(value string) string {
+		t.Helper()
+		for _, host := range server.Domain("example.com").Hosts {
+			if host.Address == value {
+				return strconv.Itoa(host.HostID)
+			}
+		}
+		t.Fatalf("no host record has value %s: %+v", value, server.Domain("example.com").Hosts)
+		return ""
+	}
+
+	// Every record's fresh host ID is observed once it is created, which the
+	// managed reconciler requeues the record for
+	create := func(cr *v1beta1.DNSRecord) {
+		t.Helper()
+		for range 2 {
+			_, err := h.Reconcile(ctx, cr)
+			require.NoError(t, err)
+		}
+	}
+	create(first)
+	assert.Equal(t, hostID("192.0.2.1"), first.Status.AtProvider.ID)
+	create(second)
+	assert.Equal(t, hostID("192.0.2.2"), second.Status.AtProvider.ID)
+
+	// The first record's ID went stale when the second was created, but the
+	// record it last applied still identifies it when its value changes
+	assert.NotEqual(t, hostID("192.0.2.1"), first.Status.AtProvider.ID)
+	first.Spec.ForProvider.Value = "192.0.2.3"
+	obs, err := h.Reconcile(ctx, first)
+	require.NoError(t, err)
+	assert.True(t, obs.ResourceExists)
+	assert.False(t, obs.ResourceUpToDate)
+	assert.Equal(t, hostID("192.0.2.3"), first.Status.AtProvider.ID)
+
+	hosts := server.Domain("example.com").Hosts
+	require.Len(t, hosts, 2, "the record should be updated, not created again")
+
+	// Deleting by the fresh ID leaves the other record of the name
+	_, err = h.Reconcile(ctx, second)
+	require.NoError(t, err)
+	_, err = e.Delete(ctx, first)
+	require.NoError(t, err)
+	hosts = server.Domain("example.com").Hosts
+	require.Len(t, hosts, 1)
+	assert.Equal(t, "192.0.2.2", hosts[0].Address)
+}
@@ -0,0 +1,151 @@
+package dnsrecord
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/internal/controller/common"
+)
+
+// desiredValue returns the value cr's record is written with, composed from
+// its structured fields when they are set.
+func desiredValue(cr *v1beta1.DNSRecord) string {
+	p := cr.Spec.ForProvider
+	switch {
+	case p.CAA != nil:
+		return composeCAA(*p.CAA)
+	case structuredSRV(p):
+		return composeSRV(mxPref(cr), *p.Weight, *p.Port, p.Value)
+	}
+	return p.Value
+}
+
+// writtenValue returns the desired value of cr's record in the normalized
+// form it is written in.
+func writtenValue(cr *v1beta1.DNSRecord) string {
+	mode, _ := common.ValueCompare(cr)
+	return common.WriteNormalizer(cr.Spec.ForProvider.Type, mode)(desiredValue(cr))
+}
+
+// valueDrifts compares a record value with the one cr's spec asks for.
+// Structured CAA and SRV values are compared field by field, whatever
+// quoting Namecheap stores them with, and other values in their normalized
+// form.
+func valueDrifts(cr *v1beta1.DNSRecord, address string, normalize common.Normalizer) []common.Drift {
+	p := cr.Spec.ForProvider
+	switch {
+	case p.CAA != nil:
+		return caaDrifts(*p.CAA, address)
+	case structuredSRV(p):
+		return srvDrifts(mxPref(cr), *p.Weight, *p.Port, p.Value, address)
+	case normalize(p.Value) != normalize(address):
+		return []common.Drift{{Field: "spec.forProvider.value", Expected: p.Value, Observed: address}}
+	}
+	return nil
+}
+
+// structuredSRV reports whether p is an SRV record whose value is only its
+// target, with the weight and port given separately.
+func structuredSRV(p v1beta1.DNSRecordParameters) bool {
+	return strings.EqualFold(p.Type, "SRV") && p.Weight != nil && p.Port != nil
+}
+
+// composeSRV returns an SRV value in the form Namecheap expects: the
+// priority, weight, port and target, separated by spaces.
+func composeSRV(priority, weight, port int, target string) string {
+	return strconv.Itoa(priority) + " " + strconv.Itoa(weight) + " " + strconv.Itoa(port) + " " + strings.TrimSpace(target)
+}
+
+// srvDrifts compares an SRV value field by field with the desired priority,
+// weight, port and target. Targets compare like hostnames.
+func srvDrifts(priority, weight, port int, target, address string) []common.Drift {
+	fields := strings.Fields(address)
+	if len(fields) != 4 {
+		return []common.Drift{{Field: "spec.forProvider.value", Expected: composeSRV(priority, weight, port, target), Observed: address}}
+	}
+
+	var drifts []common.Drift
+	for _, field := range []struct {
+		name     string
+		expected int
+		observed string
+	}{
+		{name: "priority", expected: priority, observed: fields[0]},
+		{name: "weight", expected: weight, observed: fields[1]},
+		{name: "port", expected: port, observed: fields[2]},
+	} {
+		if observed, err := strconv.Atoi(field.observed); err != nil || observed != field.expected {
+			drifts = append(drifts, common.Drift{
+				Field:    "spec.forProvider." + field.name,
+				Expected: strconv.Itoa(field.expected),
+				Observed: field.observed,
+			})
+		}
+	}
+	if common.NormalizeHostname(fields[3]) != common.NormalizeHostname(target) {
+		drifts = append(drifts, common.Drift{
+			Field:    "spec.forProvider.value",
+			Expected: target,
+			Observed: fields[3],
+		})
+	}
+	return drifts
+}
+
+// composeCAA returns a CAA value in the form Namecheap expects: the flags,
+// the lowercased tag and the quoted value, separated by spaces.
+func composeCAA(caa v1beta1.CAARecord) string {
+	return strconv.Itoa(caa.Flags) + " " + strings.ToLower(caa.Tag) + ` "` + caa.Value + `"`
+}
+
+// parseCAA splits a CAA value into its flags, tag and value, which may or
+// may not be quoted. It reports false if the value isn't a CAA value.
+func parseCAA(value string) (v1beta1.CAARecord, bool) {
+	flags, rest, _ := strings.Cut(strings.TrimSpace(value), " ")
+	tag, rest, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	rest = strings.TrimSpace(rest)
+
+	f, err := strconv.Atoi(flags)
+	if err != nil || tag == "" || rest == "" {
+		return v1beta1.CAARecord{}, false
+	}
+	if len(rest) > 1 && strings.HasPrefix(rest, `"`) && strings.HasSuffix(rest, `"`) {
+		rest = rest[1 : len(rest)-1]
+	}
+	return v1beta1.CAARecord{Flags: f, Tag: tag, Value: rest}, true
+}
+
+// caaDrifts compares a CAA value field by field with the desired one, so
+// that however Namecheap quotes the value only genuine differences count.
+// The tag is case-insensitive.
+func caaDrifts(desired v1beta1.CAARecord, address string) []common.Drift {
+	observed, ok := parseCAA(address)
+	if !ok {
+		return []common.Drift{{Field: "spec.forProvider.caa", Expected: composeCAA(desired), Observed: address}}
+	}
+
+	var drifts []common.Drift
+	if observed.Flags != desired.Flags {
+		drifts = append(drifts, common.Drift{
+			Field:    "spec.forProvider.caa.flags",
+			Expected: strconv.Itoa(desired.Flags),
+			Observed: strconv.Itoa(observed.Flags),
+		})
+	}
+	if !strings.EqualFold(observed.Tag, desired.Tag) {
+		drifts = append(drifts, common.Drift{
+			Field:    "spec.forProvider.caa.tag",
+			Expected: desired.Tag,
+			Observed: observed.Tag,
+		})
+	}
+	if observed.Value != desired.Value {
+		drifts = append(drifts, common.Drift{
+			Field:    "spec.forProvider.caa.value",
+			Expected: desired.Value,
+			Observed: observed.Value,
+		})
+	}
+	return drifts
+}
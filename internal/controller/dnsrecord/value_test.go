@@ -0,0 +1,95 @@
+package dnsrecord
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+)
+
+func TestComposeCAA(t *testing.T) {
+	assert.Equal(t, `0 issue "letsencrypt.org"`, composeCAA(v1beta1.CAARecord{Tag: "issue", Value: "letsencrypt.org"}))
+	assert.Equal(t, `128 iodef "mailto:security@example.com"`, composeCAA(v1beta1.CAARecord{Flags: 128, Tag: "IODEF", Value: "mailto:security@example.com"}))
+}
+
+func TestParseCAA(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  v1beta1.CAARecord
+		ok    bool
+	}{
+		{name: "quoted", value: `0 issue "letsencrypt.org"`, want: v1beta1.CAARecord{Tag: "issue", Value: "letsencrypt.org"}, ok: true},
+		{name: "unquoted", value: "0 issue letsencrypt.org", want: v1beta1.CAARecord{Tag: "issue", Value: "letsencrypt.org"}, ok: true},
+		{name: "whitespace", value: ` 128  issuewild   "sectigo.com" `, want: v1beta1.CAARecord{Flags: 128, Tag: "issuewild", Value: "sectigo.com"}, ok: true},
+		{name: "value with spaces", value: `0 issue "ca.example.net; account=12345"`, want: v1beta1.CAARecord{Tag: "issue", Value: "ca.example.net; account=12345"}, ok: true},
+		{name: "flags not a number", value: `issue "letsencrypt.org"`},
+		{name: "no value", value: "0 issue"},
+		{name: "empty"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := parseCAA(tc.value)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCAADrifts(t *testing.T) {
+	desired := v1beta1.CAARecord{Tag: "issue", Value: "letsencrypt.org"}
+
+	tests := []struct {
+		name     string
+		observed string
+		fields   []string
+	}{
+		{name: "quoted", observed: `0 issue "letsencrypt.org"`},
+		{name: "unquoted", observed: "0 issue letsencrypt.org"},
+		{name: "tag case", observed: `0 ISSUE "letsencrypt.org"`},
+		{name: "flags", observed: `128 issue "letsencrypt.org"`, fields: []string{"spec.forProvider.caa.flags"}},
+		{name: "tag", observed: `0 issuewild "letsencrypt.org"`, fields: []string{"spec.forProvider.caa.tag"}},
+		{name: "value", observed: `0 issue "sectigo.com"`, fields: []string{"spec.forProvider.caa.value"}},
+		{name: "value case", observed: `0 issue "LetsEncrypt.org"`, fields: []string{"spec.forProvider.caa.value"}},
+		{name: "not CAA", observed: "letsencrypt.org", fields: []string{"spec.forProvider.caa"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var fields []string
+			for _, drift := range caaDrifts(desired, tc.observed) {
+				fields = append(fields, drift.Field)
+			}
+			assert.Equal(t, tc.fields, fields)
+		})
+	}
+}
+
+func TestSRVDrifts(t *testing.T) {
+	tests := []struct {
+		name     string
+		observed string
+		fields   []string
+	}{
+		{name: "identical", observed: "10 60 5060 sip.example.com"},
+		{name: "target case and dot", observed: "10 60 5060 SIP.example.com."},
+		{name: "whitespace", observed: " 10  60 5060\tsip.example.com"},
+		{name: "priority", observed: "20 60 5060 sip.example.com", fields: []string{"spec.forProvider.priority"}},
+		{name: "weight and port", observed: "10 5 5061 sip.example.com", fields: []string{"spec.forProvider.weight", "spec.forProvider.port"}},
+		{name: "target", observed: "10 60 5060 voip.example.com", fields: []string{"spec.forProvider.value"}},
+		{name: "not a number", observed: "10 sixty 5060 sip.example.com", fields: []string{"spec.forProvider.weight"}},
+		{name: "weight, port and target only", observed: "60 5060 sip.example.com", fields: []string{"spec.forProvider.value"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var fields []string
+			for _, drift := range srvDrifts(10, 60, 5060, "sip.example.com", tc.observed) {
+				fields = append(fields, drift.Field)
+			}
+			assert.Equal(t, tc.fields, fields)
+		})
+	}
+}
@@ -0,0 +1,412 @@
+package dnszone
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/internal/controller/common"
+	"github.com/rossigee/provider-namecheap/internal/controller/pollinterval"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+const (
+	errNotDNSZone   = "managed resource is not a DNSZone custom resource"
+	errTrackPCUsage = "cannot track ProviderConfig usage"
+	errGetPC        = "cannot get ProviderConfig"
+	errGetCreds     = "cannot get credentials"
+	errIndexDomain  = "cannot index resources by domain"
+
+	errGetDNSZone    = "cannot get DNS zone"
+	errWriteDNSZone  = "cannot write DNS zone"
+	errListOwners    = "cannot list the resources managing the domain's records"
+	errDomainManaged = "refusing to manage the host records of a domain that other resources manage"
+
+	reasonZoneShrunk event.Reason = "ZoneShrunk"
+)
+
+// domainField indexes DNSRecords and DNSZones by the lowercased domain whose
+// host records they manage
+const domainField = "spec.forProvider.domain"
+
+// Setup adds a controller that reconciles DNSZone managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1beta1.DNSZoneGroupKind)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name)) //nolint:staticcheck // SA1019: required for v2 API compatibility
+
+	for obj, domain := range domainIndexes {
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), obj, domainField, domain); err != nil {
+			return errors.Wrap(err, errIndexDomain)
+		}
+	}
+
+	// DNSZone status is large, so write it with server-side apply to avoid
+	// conflicts with concurrent writers.
+	r := managed.NewReconciler(common.NewStatusApplyManager(mgr),
+		resource.ManagedKind(v1beta1.DNSZoneGroupVersionKind),
+		managed.WithExternalConnector(&connector{
+			kube:     mgr.GetClient(),
+			usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
+			recorder: recorder,
+			log:      o.Logger.WithValues("controller", name),
+			drift:    common.NewDriftEvents(recorder, o.PollInterval),
+		}),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithPollIntervalHook(pollinterval.NewHook(mgr.GetCache(), o.Logger.WithValues("controller", name))),
+		managed.WithRecorder(recorder),
+		common.WithManagementPolicies(o))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&v1beta1.DNSZone{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// domainIndexes are the functions indexing the resources that manage a
+// domain's host records by domainField
+var domainIndexes = map[client.Object]client.IndexerFunc{
+	&v1beta1.DNSRecord{}: func(o client.Object) []string {
+		return []string{strings.ToLower(o.(*v1beta1.DNSRecord).Spec.ForProvider.Domain)}
+	},
+	&v1beta1.DNSZone{}: func(o client.Object) []string {
+		return []string{strings.ToLower(o.(*v1beta1.DNSZone).Spec.ForProvider.Domain)}
+	},
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube     client.Client
+	usage    *resource.ProviderConfigUsageTracker
+	recorder event.Recorder
+	log      logging.Logger
+	drift    *common.DriftEvents
+}
+
+// Connect typically produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1beta1.DNSZone)
+	if !ok {
+		return nil, errors.New(errNotDNSZone)
+	}
+
+	if err := c.usage.Track(ctx, cr); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &v1beta1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	cd := pc.Spec.Credentials
+	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
+	common.RecordCredentialsRevision(ctx, c.kube, c.log, pc, namecheap.CredentialsRevision(data))
+
+	creds, err := namecheap.ParseCredentials(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse credentials JSON")
+	}
+
+	config := namecheap.Config{
+		APIUser:             creds.APIUser,
+		APIKey:              creds.APIKey,
+		Username:            creds.Username,
+		ClientIP:            creds.ClientIP,
+		ClientIPs:           creds.ClientIPs,
+		Sandbox:             pc.Spec.SandboxMode != nil && *pc.Spec.SandboxMode,
+		DomainWriteInterval: common.DomainWriteInterval,
+	}
+
+	if pc.Spec.APIBase != nil {
+		config.BaseURL = *pc.Spec.APIBase
+	}
+
+	client := namecheap.NewClient(config)
+	common.Clients.Track(pc.GetName(), client)
+
+	return &external{client: client, kube: c.kube, recorder: c.recorder, drift: c.drift}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	client   namecheap.DNSAPI
+	kube     client.Client
+	recorder event.Recorder
+	drift    *common.DriftEvents
+}
+
+// Disconnect cleans up any resources created by Connect.
+func (c *external) Disconnect(ctx context.Context) error {
+	return nil
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1beta1.DNSZone)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotDNSZone)
+	}
+	ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.DNSZoneKind)
+
+	// Deleting a zone leaves its records in place, so there is nothing to
+	// wait for
+	domain := cr.Spec.ForProvider.Domain
+	if domain == "" || meta.WasDeleted(cr) {
+		return managed.ExternalObservation{}, nil
+	}
+
+	// Refuse to reconcile against a different Namecheap environment than
+	// the one the resource was recorded in
+	environment := c.client.Environment()
+	if err := common.CheckEnvironment(cr, cr.Status.AtProvider.Environment, environment); err != nil {
+		cr.SetConditions(v1beta1.EnvironmentMismatch(err.Error()))
+		return managed.ExternalObservation{}, err
+	}
+
+	// A zone owns every host record of its domain, so it would fight any
+	// other resource managing them
+	if err := c.checkOwnership(ctx, cr); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	hosts, err := c.client.GetDNSHosts(ctx, domain)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetDNSZone)
+	}
+
+	cr.Status.AtProvider.Environment = environment
+	cr.Status.AtProvider.RecordCount = len(hosts.Records)
+	cr.Status.AtProvider.EmailType = hosts.EmailType
+	meta.SetExternalName(cr, domain)
+
+	drifts := zoneDrifts(desiredRecords(cr), hosts.Records)
+	if emailType := cr.Spec.ForProvider.EmailType; emailType != nil && *emailType != hosts.EmailType {
+		drifts = append(drifts, common.Drift{
+			Field:    "spec.forProvider.emailType",
+			Expected: *emailType,
+			Observed: hosts.EmailType,
+		})
+	}
+	upToDate := len(drifts) == 0
+
+	// The managed reconciler discards the status Create sets, so a zone
+	// first written by Create was last synced when the create succeeded
+	if created := meta.GetExternalCreateSucceeded(cr); cr.Status.AtProvider.LastSynced == nil && !created.IsZero() {
+		cr.Status.AtProvider.LastSynced = &metav1.Time{Time: created}
+	}
+
+	// Until the zone is first written, differences are the records it is
+	// yet to write rather than drift
+	if !upToDate && cr.Status.AtProvider.LastSynced == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if !upToDate {
+		c.drift.Record(cr, common.DriftCorrection(cr, false), drifts...)
+	}
+
+	cr.SetConditions(v1beta1.EnvironmentMatched())
+	cr.Status.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: upToDate,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1beta1.DNSZone)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotDNSZone)
+	}
+
+	cr.Status.SetConditions(xpv1.Creating())
+	return managed.ExternalCreation{}, c.write(ctx, cr)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1beta1.DNSZone)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotDNSZone)
+	}
+
+	return managed.ExternalUpdate{}, c.write(ctx, cr)
+}
+
+// Delete leaves the domain's host records in place. A domain always has
+// host records, so there is no empty zone to delete it to, and the records
+// are then managed in the Namecheap dashboard or by DNSRecords.
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*v1beta1.DNSZone)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotDNSZone)
+	}
+
+	cr.Status.SetConditions(xpv1.Deleting())
+	return managed.ExternalDelete{}, nil
+}
+
+// write replaces the domain's host records with those of cr in one call
+func (c *external) write(ctx context.Context, cr *v1beta1.DNSZone) error {
+	ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.DNSZoneKind)
+	if common.AllowZoneShrink(cr) {
+		ctx = namecheap.WithZoneShrinkAllowed(ctx)
+	}
+
+	hosts := namecheap.DNSHosts{Records: desiredRecords(cr)}
+	if cr.Spec.ForProvider.EmailType != nil {
+		hosts.EmailType = *cr.Spec.ForProvider.EmailType
+	}
+
+	domain := cr.Spec.ForProvider.Domain
+	if err := c.client.ReplaceDNSHosts(ctx, domain, hosts); err != nil {
+		if errors.Is(err, namecheap.ErrZoneShrunk) {
+			c.recorder.Event(cr, event.Warning(reasonZoneShrunk, errors.Wrapf(err,
+				"not rewriting the host records of domain %s; set the %s annotation to \"true\" if the drop is genuine",
+				domain, common.AnnotationKeyAllowZoneShrink)))
+		}
+		return errors.Wrap(err, errWriteDNSZone)
+	}
+
+	now := metav1.Now()
+	cr.Status.AtProvider.LastSynced = &now
+	cr.Status.AtProvider.RecordCount = len(hosts.Records)
+	return nil
+}
+
+// checkOwnership returns an error if DNSRecords or another DNSZone manage
+// the host records of cr's domain, in any namespace
+func (c *external) checkOwnership(ctx context.Context, cr *v1beta1.DNSZone) error {
+	domain := client.MatchingFields{domainField: strings.ToLower(cr.Spec.ForProvider.Domain)}
+
+	var owners []string
+	records := &v1beta1.DNSRecordList{}
+	if err := c.kube.List(ctx, records, domain); err != nil {
+		return errors.Wrap(err, errListOwners)
+	}
+	for _, record := range records.Items {
+		owners = append(owners, v1beta1.DNSRecordKind+" "+record.GetNamespace()+"/"+record.GetName())
+	}
+
+	zones := &v1beta1.DNSZoneList{}
+	if err := c.kube.List(ctx, zones, domain); err != nil {
+		return errors.Wrap(err, errListOwners)
+	}
+	for _, zone := range zones.Items {
+		if zone.GetUID() != cr.GetUID() {
+			owners = append(owners, v1beta1.DNSZoneKind+" "+zone.GetNamespace()+"/"+zone.GetName())
+		}
+	}
+
+	if len(owners) > 0 {
+		sort.Strings(owners)
+		return errors.Errorf("%s %s: %s", errDomainManaged, cr.Spec.ForProvider.Domain, strings.Join(owners, ", "))
+	}
+	return nil
+}
+
+// desiredRecords returns the host records cr's spec asks for, with their
+// values in the normalized form they are written in
+func desiredRecords(cr *v1beta1.DNSZone) []namecheap.DNSRecord {
+	records := make([]namecheap.DNSRecord, 0, len(cr.Spec.ForProvider.Records))
+	for _, r := range cr.Spec.ForProvider.Records {
+		record := namecheap.DNSRecord{
+			Name:    r.Name,
+			Type:    r.Type,
+			Address: common.WriteNormalizer(r.Type, common.ValueCompareDefault)(r.Value),
+			TTL:     namecheap.AutomaticTTL,
+			MXPref:  namecheap.DefaultMXPref,
+		}
+		if r.TTL != nil {
+			record.TTL = *r.TTL
+		}
+		if r.Priority != nil {
+			record.MXPref = *r.Priority
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// zoneDrifts compares the domain's host records with the desired ones,
+// whatever their order, and returns a drift for each name and type whose
+// records differ. Values are compared in normalized form, and preferences
+// only of the MX and SRV records they apply to.
+func zoneDrifts(desired, observed []namecheap.DNSRecord) []common.Drift {
+	want, got := recordsByName(desired), recordsByName(observed)
+
+	keys := map[string]bool{}
+	for key := range want {
+		keys[key] = true
+	}
+	for key := range got {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var drifts []common.Drift
+	for _, key := range sorted {
+		expected, actual := strings.Join(want[key], ", "), strings.Join(got[key], ", ")
+		if expected != actual {
+			drifts = append(drifts, common.Drift{
+				Field:    "spec.forProvider.records[" + key + "]",
+				Expected: expected,
+				Observed: actual,
+			})
+		}
+	}
+	return drifts
+}
+
+// recordsByName groups records by name and type, describing each by its
+// normalized value, TTL and preference, in sorted order
+func recordsByName(records []namecheap.DNSRecord) map[string][]string {
+	byName := map[string][]string{}
+	for _, record := range records {
+		recordType := strings.ToUpper(record.Type)
+		key := strings.ToLower(record.Name) + " " + recordType
+
+		description := common.ValueNormalizer(recordType, common.ValueCompareDefault)(record.Address) +
+			" ttl=" + strconv.Itoa(record.TTL)
+		if recordType == "MX" || recordType == "SRV" {
+			description += " priority=" + strconv.Itoa(record.MXPref)
+		}
+		byName[key] = append(byName[key], description)
+	}
+	for _, descriptions := range byName {
+		sort.Strings(descriptions)
+	}
+	return byName
+}
@@ -0,0 +1,196 @@
+package dnszone
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/internal/controller/common"
+	"github.com/rossigee/provider-namecheap/internal/fakeserver"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+// recorder captures the events recorded by an external client.
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+// newKube returns a Kubernetes API holding objs, indexed as the controller's
+// manager indexes them
+func newKube(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	b := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...)
+	for obj, domain := range domainIndexes {
+		b = b.WithIndex(obj, domainField, domain)
+	}
+	return b.Build()
+}
+
+// newTestHarness returns a harness reconciling DNSZones of example.com
+// against a fake Namecheap API, with a Kubernetes API holding objs
+func newTestHarness(t *testing.T, objs ...client.Object) (*fakeserver.Harness, *fakeserver.Server, *recorder) {
+	t.Helper()
+
+	server := fakeserver.New(t)
+	server.AddDomain("example.com")
+	kube := newKube(t, objs...)
+	rec := &recorder{}
+	h := fakeserver.NewHarness(server, func(client *namecheap.Client) managed.ExternalClient {
+		return &external{client: client, kube: kube, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}
+	})
+	return h, server, rec
+}
+
+func newZone(records ...v1beta1.DNSZoneRecord) *v1beta1.DNSZone {
+	cr := &v1beta1.DNSZone{}
+	cr.SetNamespace("default")
+	cr.SetName("example")
+	cr.SetUID(types.UID("zone"))
+	cr.Spec.ForProvider.Domain = "example.com"
+	cr.Spec.ForProvider.Records = records
+	return cr
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestDNSZone(t *testing.T) {
+	h, server, _ := newTestHarness(t)
+	ctx := context.Background()
+
+	// A record left over from before the zone was managed
+	require.NoError(t, server.Client().CreateDNSRecord(ctx, "example.com",
+		namecheap.DNSRecord{Name: "old", Type: "A", Address: "192.0.2.99", MXPref: 10, TTL: 1799}))
+
+	records := []v1beta1.DNSZoneRecord{
+		{Name: "@", Type: "MX", Value: "mail.example.com", Priority: intPtr(5)},
+		{Name: "www", Type: "CNAME", Value: "Target.Example.com.", TTL: intPtr(300)},
+	}
+	for i := 0; i < 40; i++ {
+		records = append(records, v1beta1.DNSZoneRecord{Name: fmt.Sprintf("host%d", i), Type: "A", Value: fmt.Sprintf("192.0.2.%d", i+1)})
+	}
+	cr := newZone(records...)
+	mx := namecheap.EmailTypeMX
+	cr.Spec.ForProvider.EmailType = &mx
+	setHosts := server.Calls(namecheap.CommandDomainsDNSSetHosts)
+
+	// The whole zone is written in one call, replacing what was there
+	obs, err := h.Reconcile(ctx, cr)
+	require.NoError(t, err)
+	assert.False(t, obs.ResourceExists)
+	assert.Equal(t, setHosts+1, server.Calls(namecheap.CommandDomainsDNSSetHosts))
+
+	d := server.Domain("example.com")
+	require.Len(t, d.Hosts, 42)
+	assert.Equal(t, namecheap.EmailTypeMX, d.EmailType)
+	assert.Equal(t, 5, d.Hosts[0].MXPref)
+	assert.Equal(t, "target.example.com", d.Hosts[1].Address)
+	assert.Equal(t, 300, d.Hosts[1].TTL)
+	assert.Equal(t, namecheap.AutomaticTTL, d.Hosts[2].TTL)
+
+	// and is then up to date, however its records are ordered
+	obs, err = h.Reconcile(ctx, cr)
+	require.NoError(t, err)
+	assert.True(t, obs.ResourceExists)
+	assert.True(t, obs.ResourceUpToDate)
+	assert.Equal(t, 42, cr.Status.AtProvider.RecordCount)
+	assert.Equal(t, namecheap.EmailTypeMX, cr.Status.AtProvider.EmailType)
+	assert.NotNil(t, cr.Status.AtProvider.LastSynced)
+	assert.Equal(t, setHosts+1, server.Calls(namecheap.CommandDomainsDNSSetHosts))
+
+	// A record changed elsewhere is drift, and the zone is rewritten once
+	// to correct it
+	require.NoError(t, server.Client().UpdateDNSRecord(ctx, "example.com",
+		namecheap.DNSRecord{HostID: d.Hosts[7].HostID, Name: "host5", Type: "A", Address: "198.51.100.1", MXPref: 10, TTL: 1799}))
+	obs, err = h.Reconcile(ctx, cr)
+	require.NoError(t, err)
+	assert.False(t, obs.ResourceUpToDate)
+	assert.Equal(t, setHosts+3, server.Calls(namecheap.CommandDomainsDNSSetHosts))
+	assert.Equal(t, "192.0.2.6", server.Domain("example.com").Hosts[7].Address)
+
+	// Deleting the zone leaves its records in place
+	now := metav1.Now()
+	cr.SetDeletionTimestamp(&now)
+	obs, err = h.Reconcile(ctx, cr)
+	require.NoError(t, err)
+	assert.False(t, obs.ResourceExists)
+	assert.Len(t, server.Domain("example.com").Hosts, 42)
+}
+
+func TestDNSZone_DomainManagedElsewhere(t *testing.T) {
+	record := &v1beta1.DNSRecord{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "www"}}
+	record.Spec.ForProvider.Domain = "Example.com"
+	other := newZone()
+	other.SetNamespace("other")
+	other.SetUID(types.UID("other"))
+	unrelated := &v1beta1.DNSRecord{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "unrelated"}}
+	unrelated.Spec.ForProvider.Domain = "example.net"
+
+	h, server, _ := newTestHarness(t, record, other, unrelated)
+	cr := newZone(v1beta1.DNSZoneRecord{Name: "@", Type: "A", Value: "192.0.2.1"})
+
+	// The zone doesn't touch a domain whose records other resources manage
+	_, err := h.Reconcile(context.Background(), cr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DNSRecord apps/www, DNSZone other/example")
+	assert.NotContains(t, err.Error(), "unrelated")
+	assert.Zero(t, server.Calls(namecheap.CommandDomainsDNSGetHosts))
+	assert.Zero(t, server.Calls(namecheap.CommandDomainsDNSSetHosts))
+}
+
+func TestZoneDrifts(t *testing.T) {
+	desired := []namecheap.DNSRecord{
+		{Name: "@", Type: "A", Address: "192.0.2.1", MXPref: 10, TTL: 1799},
+		{Name: "@", Type: "A", Address: "192.0.2.2", MXPref: 10, TTL: 1799},
+		{Name: "@", Type: "MX", Address: "mail.example.com", MXPref: 5, TTL: 1799},
+		{Name: "www", Type: "AAAA", Address: "2001:db8::1", MXPref: 10, TTL: 300},
+	}
+
+	// Order, case, normalization and preferences of types without one
+	// aren't drift
+	same := []namecheap.DNSRecord{
+		{Name: "WWW", Type: "AAAA", Address: "2001:db8:0:0:0:0:0:1", MXPref: 0, TTL: 300},
+		{Name: "@", Type: "MX", Address: "mail.example.com.", MXPref: 5, TTL: 1799},
+		{Name: "@", Type: "A", Address: "192.0.2.2", MXPref: 10, TTL: 1799},
+		{Name: "@", Type: "A", Address: "192.0.2.1", MXPref: 0, TTL: 1799},
+	}
+	assert.Empty(t, zoneDrifts(desired, same))
+
+	// A changed preference, a missing record and an extra one are
+	changed := []namecheap.DNSRecord{
+		{Name: "@", Type: "A", Address: "192.0.2.1", MXPref: 10, TTL: 1799},
+		{Name: "@", Type: "MX", Address: "mail.example.com", MXPref: 10, TTL: 1799},
+		{Name: "www", Type: "AAAA", Address: "2001:db8::1", MXPref: 10, TTL: 300},
+		{Name: "old", Type: "TXT", Address: "stale", MXPref: 10, TTL: 1799},
+	}
+	assert.Equal(t, []common.Drift{
+		{Field: "spec.forProvider.records[@ A]", Expected: "192.0.2.1 ttl=1799, 192.0.2.2 ttl=1799", Observed: "192.0.2.1 ttl=1799"},
+		{Field: "spec.forProvider.records[@ MX]", Expected: "mail.example.com ttl=1799 priority=5", Observed: "mail.example.com ttl=1799 priority=10"},
+		{Field: "spec.forProvider.records[old TXT]", Expected: "", Observed: "stale ttl=1799"},
+	}, zoneDrifts(desired, changed))
+}
@@ -2,8 +2,10 @@ package domain
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -11,16 +13,19 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
 
 	"github.com/rossigee/provider-namecheap/apis/v1beta1"
-	"github.com/rossigee/provider-namecheap/internal/clients/namecheap"
+	"github.com/rossigee/provider-namecheap/internal/controller/common"
+	"github.com/rossigee/provider-namecheap/internal/controller/pollinterval"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
 )
 
 const (
@@ -35,21 +40,67 @@ const (
 	errDeleteDomain     = "cannot delete domain"
 	errGetDomain        = "cannot get domain"
 	errSetNameservers   = "cannot set nameservers"
+	errGetRegistrarLock = "cannot get registrar lock"
+	errSetRegistrarLock = "cannot set registrar lock"
+	errGetDNSSummary    = "cannot get DNS host records"
+	errGetContacts      = "cannot get contacts"
+	errSetContacts      = "cannot set contacts"
+	errReactivateDomain = "cannot reactivate domain"
+	errRenewDomain      = "cannot renew domain"
+	errNoContacts       = "spec.forProvider.contacts is required to register a domain"
+	errMixedNameservers = "nameservers mix Namecheap's own (*.registrar-servers.com) with other nameservers; " +
+		"remove the Namecheap nameservers, or list only them to use Namecheap DNS"
+
+	reasonTransferOutPending     event.Reason = "TransferOutPending"
+	reasonDefaultNameservers     event.Reason = "DefaultNameservers"
+	reasonDeletionBehavior       event.Reason = "DeletionBehavior"
+	reasonDomainOrder            event.Reason = "DomainOrder"
+	reasonPostRegistration       event.Reason = "PostRegistration"
+	reasonDriftSuspended         event.Reason = "DriftSuspended"
+	reasonPrivacyRetry           event.Reason = "PrivacyRetry"
+	reasonContactsUpdated        event.Reason = "ContactsUpdated"
+	reasonRegistrarLock          event.Reason = "RegistrarLock"
+	reasonReactivation           event.Reason = "Reactivation"
+	reasonWhoisGuardForwardEmail event.Reason = "WhoisGuardForwardEmail"
+)
+
+// annotationKeyCreateOrder records the registration order placed by Create,
+// whose status the managed reconciler discards
+const annotationKeyCreateOrder = "namecheap.m.crossplane.io/create-order"
+
+// defaultRegistrationGracePeriod is how long after registration a domain
+// that Namecheap reports as not found is assumed to still be propagating
+const defaultRegistrationGracePeriod = 5 * time.Minute
+
+// Enabling WhoisGuard while a newly registered domain's subscription is not
+// active yet is retried after privacyRetryBackoff, doubling after every
+// failed attempt, for up to maxPrivacyAttempts attempts
+const (
+	privacyRetryBackoff = 30 * time.Second
+	maxPrivacyAttempts  = 5
 )
 
 // Setup adds a controller that reconciles Domain managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1beta1.DomainGroupKind)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name)) //nolint:staticcheck // SA1019: required for v2 API compatibility
 
-	r := managed.NewReconciler(mgr,
+	// Domain status is large, so write it with server-side apply to avoid
+	// conflicts with concurrent writers.
+	r := managed.NewReconciler(common.NewStatusApplyManager(mgr),
 		resource.ManagedKind(v1beta1.DomainGroupVersionKind),
 		managed.WithExternalConnector(&connector{
-			kube:   mgr.GetClient(),
-			usage:  resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
+			kube:     mgr.GetClient(),
+			usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
+			recorder: recorder,
+			log:      o.Logger.WithValues("controller", name),
+			drift:    common.NewDriftEvents(recorder, o.PollInterval),
 		}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))) //nolint:staticcheck // SA1019: required for v2 API compatibility
+		managed.WithPollIntervalHook(privacyRetryHook(pollinterval.NewHook(mgr.GetCache(), o.Logger.WithValues("controller", name)))),
+		managed.WithRecorder(recorder),
+		common.WithManagementPolicies(o))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -62,8 +113,11 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 // A connector is expected to produce an ExternalClient when its Connect method
 // is called.
 type connector struct {
-	kube  client.Client
-	usage *resource.ProviderConfigUsageTracker
+	kube     client.Client
+	usage    *resource.ProviderConfigUsageTracker
+	recorder event.Recorder
+	log      logging.Logger
+	drift    *common.DriftEvents
 }
 
 // Connect typically produces an ExternalClient by:
@@ -92,24 +146,24 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetCreds)
 	}
 
+	common.RecordCredentialsRevision(ctx, c.kube, c.log, pc, namecheap.CredentialsRevision(data))
+
 	// Parse credentials from the secret data
-	var creds struct {
-		APIUser  string `json:"api_user"`
-		APIKey   string `json:"api_key"`
-		Username string `json:"username"`
-		ClientIP string `json:"client_ip"`
-	}
-	if err := json.Unmarshal(data, &creds); err != nil {
+	creds, err := namecheap.ParseCredentials(data)
+	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse credentials JSON")
 	}
 
 	// Create Namecheap client
 	config := namecheap.Config{
-		APIUser:  creds.APIUser,
-		APIKey:   creds.APIKey,
-		Username: creds.Username,
-		ClientIP: creds.ClientIP,
-		Sandbox:  pc.Spec.SandboxMode != nil && *pc.Spec.SandboxMode,
+		APIUser:             creds.APIUser,
+		APIKey:              creds.APIKey,
+		Username:            creds.Username,
+		ClientIP:            creds.ClientIP,
+		ClientIPs:           creds.ClientIPs,
+		Sandbox:             pc.Spec.SandboxMode != nil && *pc.Spec.SandboxMode,
+		CheckBalance:        pc.Spec.CheckBalance != nil && *pc.Spec.CheckBalance,
+		DomainWriteInterval: common.DomainWriteInterval,
 	}
 
 	if pc.Spec.APIBase != nil {
@@ -117,8 +171,14 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	}
 
 	client := namecheap.NewClient(config)
+	common.Clients.Track(pc.GetName(), client)
 
-	return &external{client: client}, nil
+	gracePeriod := defaultRegistrationGracePeriod
+	if pc.Spec.RegistrationGracePeriod != nil {
+		gracePeriod = pc.Spec.RegistrationGracePeriod.Duration
+	}
+
+	return &external{client: client, recorder: c.recorder, registrationGracePeriod: gracePeriod, drift: c.drift}, nil
 }
 
 // Disconnect cleans up any resources created by Connect.
@@ -130,7 +190,32 @@ func (c *external) Disconnect(ctx context.Context) error {
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
-	client *namecheap.Client
+	client   namecheap.DomainAPI
+	recorder event.Recorder
+
+	// registrationGracePeriod is how long after registration a domain that
+	// isn't found is reported as provisioning rather than non-existent
+	registrationGracePeriod time.Duration
+
+	// privacyRetryOnly is set by Observe when the only reason the domain is
+	// out of date is a due retry of enabling WhoisGuard, so that Update
+	// retries that step alone
+	privacyRetryOnly bool
+
+	// contactsDrifted is set by Observe when the domain's contacts differ
+	// from the spec, so that Update only replaces them when they do
+	contactsDrifted bool
+
+	// reactivate is set by Observe when the domain has expired and the spec
+	// asks for it to be reactivated, so that Update reactivates it
+	reactivate bool
+
+	// defaultDNS is set by Observe when the domain is on other nameservers
+	// but the spec wants it on Namecheap DNS, so that Update switches it back
+	defaultDNS bool
+
+	// drift records events about drift of the domain
+	drift *common.DriftEvents
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -138,12 +223,44 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if !ok {
 		return managed.ExternalObservation{}, errors.New(errNotDomain)
 	}
+	ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.DomainKind)
 
 	domainName := cr.Spec.ForProvider.DomainName
 	if domainName == "" {
 		return managed.ExternalObservation{}, nil
 	}
 
+	// Domains can't be deleted, so a deleted resource is gone once Delete
+	// applied its deletion behavior
+	if meta.WasDeleted(cr) && deleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	// Namecheap reports internationalized domains in punycode, whichever
+	// form the spec names them in
+	externalName, err := namecheap.ToASCII(domainName)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	// Refuse to reconcile against a different Namecheap environment than
+	// the one the resource was recorded in
+	environment := c.client.Environment()
+	if err := common.CheckEnvironment(cr, cr.Status.AtProvider.Environment, environment); err != nil {
+		cr.SetConditions(v1beta1.EnvironmentMismatch(err.Error()))
+		return managed.ExternalObservation{}, err
+	}
+
+	// Bypass client-side caches once per refresh annotation value
+	refresh, fresh := common.RefreshRequest(cr, cr.Status.AtProvider.LastHandledRefresh)
+	if fresh {
+		ctx = namecheap.WithFreshRead(ctx)
+	}
+
+	// Look up what Namecheap supports for the domain's TLD. The TLD list
+	// is cached, so this rarely costs an API call.
+	tld, tldErr := c.client.GetTLDForDomain(ctx, domainName)
+
 	// Check if domain exists
 	exists, err := c.client.DomainExists(ctx, domainName)
 	if err != nil {
@@ -151,36 +268,219 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 
 	if !exists {
-		return managed.ExternalObservation{
-			ResourceExists: false,
-		}, nil
+		if fresh {
+			cr.Status.AtProvider.LastHandledRefresh = refresh
+		}
+		setTLDSupport(cr, tld, tldErr, false)
+		return c.notFound(cr), nil
 	}
 
-	// Get domain details
-	domain, err := c.client.GetDomain(ctx, domainName)
+	// Make every read before changing the status, so that a failed read
+	// leaves the previous observation intact
+	info, err := c.client.GetDomainInfo(ctx, domainName)
+	if namecheap.IsDomainNotInAccount(err) {
+		setTLDSupport(cr, tld, tldErr, false)
+		return c.notFound(cr), nil
+	}
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetDomain)
 	}
+	domain := &info.Domain
 
-	// Update status with observed values
-	cr.Status.AtProvider.ID = strconv.Itoa(domain.ID)
-	cr.Status.AtProvider.Status = "Active" // Namecheap doesn't provide status in API response
+	locked, err := c.client.GetRegistrarLock(ctx, domainName)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetRegistrarLock)
+	}
+
+	// Summarize the DNS host records only on request, as it costs an extra
+	// API call per observation
+	var summary *v1beta1.DNSSummary
+	if cr.Spec.ForProvider.ObserveDNSSummary != nil && *cr.Spec.ForProvider.ObserveDNSSummary {
+		hosts, err := c.client.GetDNSHosts(ctx, domainName)
+		switch {
+		case namecheap.IsNotUsingOurDNS(err):
+			summary = &v1beta1.DNSSummary{}
+		case err != nil:
+			return managed.ExternalObservation{}, errors.Wrap(err, errGetDNSSummary)
+		default:
+			summary = dnsSummary(hosts)
+		}
+	}
+
+	// Compare contacts only when the spec declares them
+	var drifts []common.Drift
+	if cr.Spec.ForProvider.Contacts != nil {
+		contacts, err := c.client.GetDomainContacts(ctx, domainName)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errGetContacts)
+		}
+		drifts = contactsDrift(domainContacts(cr.Spec.ForProvider.Contacts), *contacts)
+	}
+	c.contactsDrifted = len(drifts) > 0
+
+	// Look up WhoisGuard only when the spec declares it. A domain without
+	// WhoisGuard has no privacy to compare.
+	var whoisGuard *namecheap.WhoisGuard
+	if cr.Spec.ForProvider.PrivacyProtection != nil {
+		if wg, err := c.client.GetWhoisGuardForDomain(ctx, domainName); err == nil {
+			whoisGuard = wg
+		}
+	}
+
+	// Compare WhoisGuard only when no retry of enabling it is scheduled, and
+	// its forward email only while it is enabled as the spec requests
+	if enabled := cr.Spec.ForProvider.PrivacyProtection; whoisGuard != nil && cr.Status.AtProvider.PrivacyRetry == nil {
+		forward := forwardEmail(cr)
+		if currentlyEnabled := whoisGuard.Status == "ENABLED"; currentlyEnabled != *enabled {
+			drifts = append(drifts, common.Drift{
+				Field:    "spec.forProvider.privacyProtection",
+				Expected: strconv.FormatBool(*enabled),
+				Observed: strconv.FormatBool(currentlyEnabled),
+			})
+		} else if *enabled && forwardEmailDrifted(forward, info.WhoisGuardForwardedTo) {
+			drifts = append(drifts, common.Drift{
+				Field:    "spec.forProvider.whoisGuardForwardEmail",
+				Expected: forward,
+				Observed: info.WhoisGuardForwardedTo,
+			})
+		}
+	}
+
+	// Build the observation on a copy of the current one, which preserves
+	// the fields only set on create or update
+	obs := cr.Status.AtProvider.DeepCopy()
+	if fresh {
+		obs.LastHandledRefresh = refresh
+	}
+	obs.Environment = environment
+
+	// Keep the ID recorded on create if the API doesn't report one
+	if domain.ID != 0 {
+		obs.ID = strconv.Itoa(domain.ID)
+	}
+	obs.Status = "Active" // Namecheap doesn't provide status in API response
 	if !domain.Created.IsZero() {
-		cr.Status.AtProvider.CreatedDate = &metav1.Time{Time: domain.Created}
+		obs.CreatedDate = &metav1.Time{Time: domain.Created.Time}
 	}
 	if !domain.Expires.IsZero() {
-		cr.Status.AtProvider.ExpirationDate = &metav1.Time{Time: domain.Expires}
+		obs.ExpirationDate = &metav1.Time{Time: domain.Expires.Time}
+	}
+	obs.TransferOutPending = &domain.TransferOutPending
+	obs.IsExpired = &domain.IsExpired
+
+	// Report the registrar lock separately from registry-imposed statuses.
+	// Registry statuses can't be changed through Namecheap, so they are
+	// informational and never make the domain out of date.
+	obs.RegistrarLockEnabled = &locked
+	obs.IsLocked = &locked
+	obs.RegistryStatuses = namecheap.RegistryStatuses(domain.Statuses)
+	obs.Nameservers = info.DNS.Nameservers
+	obs.IsOurDNS = &info.DNS.IsUsingOurDNS
+	obs.DNSProviderType = info.DNS.ProviderType
+	obs.DNSSummary = summary
+	if whoisGuard != nil {
+		obs.WhoisGuardID = &whoisGuard.ID
+		obs.WhoisGuardStatus = &whoisGuard.Status
+		if !whoisGuard.Expires.IsZero() {
+			obs.WhoisGuardExpirationDate = &metav1.Time{Time: whoisGuard.Expires.Time}
+		}
+	}
+
+	// Recover the registration order from its record, as the managed
+	// reconciler discards the status Create sets
+	if obs.LastOrder == nil {
+		order := &v1beta1.DomainOrder{}
+		if ok, err := common.GetRecord(cr, annotationKeyCreateOrder, order); err != nil {
+			c.recorder.Event(cr, event.Warning(reasonDomainOrder, err))
+		} else if ok {
+			obs.LastOrder = order
+		}
+	}
+
+	// Nameservers the spec lists that the domain uses were applied by the
+	// provider, whether by Create, whose status is discarded, or by Update
+	if desired := namecheap.NormalizeNameservers(cr.Spec.ForProvider.Nameservers); obs.AppliedNameservers == nil &&
+		len(desired) > 0 && namecheap.NameserversEqual(desired, info.DNS.Nameservers) {
+		obs.AppliedNameservers = desired
+	}
+
+	wasPending := cr.Status.AtProvider.TransferOutPending != nil && *cr.Status.AtProvider.TransferOutPending
+	cr.Status.AtProvider = *obs
+	cr.SetConditions(v1beta1.EnvironmentMatched())
+	setTLDSupport(cr, tld, tldErr, true)
+
+	// Surface transfer-out requests, alerting when one first appears for a
+	// domain the spec wants locked against transfers
+	if domain.TransferOutPending {
+		cr.Status.SetConditions(v1beta1.TransferOutPending())
+		if lock := cr.Spec.ForProvider.RegistrarLock; !wasPending && lock != nil && *lock {
+			c.recorder.Event(cr, event.Warning(reasonTransferOutPending,
+				errors.Errorf("unexpected transfer-out request pending for domain %s", domainName)))
+		}
+	} else {
+		cr.Status.SetConditions(v1beta1.NoTransferOut())
+	}
+
+	if len(obs.RegistryStatuses) > 0 {
+		cr.Status.SetConditions(v1beta1.RegistryRestricted(obs.RegistryStatuses))
+	} else {
+		cr.Status.SetConditions(v1beta1.NoRegistryStatus())
 	}
 
 	// Set external name annotation
-	meta.SetExternalName(cr, domainName)
+	meta.SetExternalName(cr, externalName)
+
+	if lock := cr.Spec.ForProvider.RegistrarLock; lock != nil && *lock != locked {
+		drifts = append(drifts, common.Drift{
+			Field:    "spec.forProvider.registrarLock",
+			Expected: strconv.FormatBool(*lock),
+			Observed: strconv.FormatBool(locked),
+		})
+	}
+
+	// Compare nameservers in normalized form so that formatting differences
+	// in the spec don't cause perpetual drift
+	if desired := namecheap.NormalizeNameservers(cr.Spec.ForProvider.Nameservers); len(desired) > 0 &&
+		!namecheap.NameserversEqual(desired, info.DNS.Nameservers) {
+		drifts = append(drifts, common.Drift{
+			Field:    "spec.forProvider.nameservers",
+			Expected: strings.Join(desired, ","),
+			Observed: strings.Join(info.DNS.Nameservers, ","),
+		})
+	}
+
+	// Switch the domain back to Namecheap DNS when the spec asks for it or
+	// no longer lists the nameservers the provider applied
+	c.defaultDNS = wantsDefaultDNS(cr) && !info.DNS.IsUsingOurDNS
+	if c.defaultDNS {
+		drifts = append(drifts, common.Drift{
+			Field:    "spec.forProvider.useDefaultDNS",
+			Expected: "Namecheap DNS",
+			Observed: strings.Join(info.DNS.Nameservers, ","),
+		})
+	}
 
-	// Check if resource is up to date
-	upToDate := true
+	// Leave drift in place while its enforcement is suspended
+	upToDate := len(drifts) == 0
+	if !upToDate {
+		suspended := c.driftSuspended(cr)
+		c.drift.Record(cr, common.DriftCorrection(cr, suspended), drifts...)
+		upToDate = suspended
+	}
 
-	// Check nameservers if specified
-	// Note: Nameserver comparison would require additional API call
-	// For now, we assume nameservers are up to date if domain exists
+	// Reactivate an expired domain on request, unless Namecheap refused to
+	// reactivate it for the current spec
+	reactivate := cr.Spec.ForProvider.ReactivateIfExpired
+	c.reactivate = reactivate != nil && *reactivate && domain.IsExpired && !reactivationRefused(cr)
+	if c.reactivate {
+		upToDate = false
+	}
+
+	// Retry enabling WhoisGuard once a scheduled retry is due
+	if retry := cr.Status.AtProvider.PrivacyRetry; retry != nil && !time.Now().Before(retry.NextAttemptTime.Time) {
+		c.privacyRetryOnly = upToDate
+		upToDate = false
+	}
 
 	cr.Status.SetConditions(xpv1.Available())
 
@@ -190,98 +490,714 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}, nil
 }
 
+// driftSuspended reports whether drift of cr is to be left in place because
+// the suspend-until annotation suspends its enforcement, recording an event
+// either way the annotation is handled
+func (c *external) driftSuspended(cr resource.Managed) bool {
+	until, suspended, err := common.DriftSuspension(cr, time.Now(), common.MaxDriftSuspension)
+	if err != nil {
+		c.recorder.Event(cr, event.Warning(reasonDriftSuspended, err))
+		return false
+	}
+	if suspended {
+		c.recorder.Event(cr, event.Normal(reasonDriftSuspended,
+			"Drift enforcement is suspended until "+until.Format(time.RFC3339)))
+	}
+	return suspended
+}
+
+// setTLDSupport publishes the capabilities of the domain's TLD and whether
+// they support what the spec asks for. A failed lookup of the TLD leaves the
+// previous ones in place.
+func setTLDSupport(cr *v1beta1.Domain, tld *namecheap.TLD, err error, exists bool) {
+	switch {
+	case namecheap.IsTLDNotFound(err):
+		cr.Status.AtProvider.Capabilities = nil
+		cr.Status.SetConditions(v1beta1.TLDUnsupported([]string{"Namecheap doesn't list the domain's TLD"}))
+		return
+	case err != nil:
+		return
+	}
+
+	capabilities := &v1beta1.TLDCapabilities{
+		TLD:                   tld.Name,
+		APIRegisterable:       tld.IsApiRegisterable,
+		APIRenewable:          tld.IsApiRenewable,
+		APITransferable:       tld.IsApiTransferable,
+		EPPRequired:           tld.IsEppRequired,
+		SupportsRegistrarLock: tld.SupportsRegistrarLock,
+		ContactsModifiable:    !tld.IsDisableModContact,
+		WhoisVerification:     tld.WhoisVerification,
+		RegistrationYears:     yearRange(tld.MinRegisterYears, tld.MaxRegisterYears),
+		RenewalYears:          yearRange(tld.MinRenewYears, tld.MaxRenewYears),
+	}
+	cr.Status.AtProvider.Capabilities = capabilities
+
+	if problems := tldProblems(cr.Spec.ForProvider, capabilities, exists); len(problems) > 0 {
+		cr.Status.SetConditions(v1beta1.TLDUnsupported(problems))
+		return
+	}
+	cr.Status.SetConditions(v1beta1.TLDSupported())
+}
+
+// tldProblems returns the parts of the spec the domain's TLD doesn't
+// support, so that they are reported before Namecheap rejects them
+func tldProblems(p v1beta1.DomainParameters, capabilities *v1beta1.TLDCapabilities, exists bool) []string {
+	tld := "." + capabilities.TLD
+	var problems []string
+	if !exists && !capabilities.APIRegisterable {
+		problems = append(problems, tld+" domains can't be registered through the API")
+	}
+	if !exists {
+		if problem := yearsProblem(capabilities.RegistrationYears, registrationYears(p), tld, "registration"); problem != "" {
+			problems = append(problems, "spec.forProvider.registrationYears: "+problem)
+		}
+	}
+	if p.RenewalYears != nil && !capabilities.APIRenewable {
+		problems = append(problems, "spec.forProvider.renewalYears: "+tld+" domains can't be renewed through the API")
+	}
+	if p.RenewalYears != nil {
+		if problem := yearsProblem(capabilities.RenewalYears, *p.RenewalYears, tld, "renewal"); problem != "" {
+			problems = append(problems, "spec.forProvider.renewalYears: "+problem)
+		}
+	}
+	if p.RegistrarLock != nil && *p.RegistrarLock && !capabilities.SupportsRegistrarLock {
+		problems = append(problems, "spec.forProvider.registrarLock: "+tld+" domains don't support a registrar lock")
+	}
+	if exists && p.Contacts != nil && !capabilities.ContactsModifiable {
+		problems = append(problems, "spec.forProvider.contacts: the contacts of "+tld+" domains can't be changed")
+	}
+	return problems
+}
+
+// yearRange returns the range of years a TLD reports, or nil if it reports
+// none
+func yearRange(minYears, maxYears int) *v1beta1.YearRange {
+	if maxYears == 0 {
+		return nil
+	}
+	return &v1beta1.YearRange{Min: minYears, Max: maxYears}
+}
+
+// yearsProblem describes why years are outside the range a TLD supports for
+// an operation, or returns an empty string if they aren't or the range is
+// unknown
+func yearsProblem(r *v1beta1.YearRange, years int, tld, operation string) string {
+	if r == nil || (years >= r.Min && years <= r.Max) {
+		return ""
+	}
+	return fmt.Sprintf("TLD %s supports %d–%d year %s", tld, r.Min, r.Max, operation)
+}
+
+// checkYears returns an error if years are outside the range the domain's
+// TLD supports for an operation, so that the order is refused before
+// Namecheap rejects it with an opaque error. The TLD list is cached, so this
+// rarely costs an API call; a failed lookup leaves the check to Namecheap.
+func (c *external) checkYears(ctx context.Context, domainName string, years int, operation string) error {
+	tld, err := c.client.GetTLDForDomain(ctx, domainName)
+	if err != nil {
+		return nil
+	}
+	r := yearRange(tld.MinRegisterYears, tld.MaxRegisterYears)
+	if operation == "renewal" {
+		r = yearRange(tld.MinRenewYears, tld.MaxRenewYears)
+	}
+	if problem := yearsProblem(r, years, "."+tld.Name, operation); problem != "" {
+		return errors.New(problem)
+	}
+	return nil
+}
+
+// registrationYears returns the years a domain is registered for
+func registrationYears(p v1beta1.DomainParameters) int {
+	if p.RegistrationYears != nil {
+		return *p.RegistrationYears
+	}
+	return 1
+}
+
+// dnsSummary summarizes a domain's DNS host records
+func dnsSummary(hosts *namecheap.DNSHosts) *v1beta1.DNSSummary {
+	summary := &v1beta1.DNSSummary{
+		RecordCount:   len(hosts.Records),
+		IsUsingOurDNS: hosts.IsUsingOurDNS,
+	}
+	for _, record := range hosts.Records {
+		if summary.RecordTypes == nil {
+			summary.RecordTypes = map[string]int{}
+		}
+		summary.RecordTypes[strings.ToUpper(record.Type)]++
+	}
+	return summary
+}
+
+// notFound returns the observation for a domain Namecheap doesn't report.
+// Right after registration Namecheap may not report the domain for a minute
+// or two, so within the registration grace period the domain is reported as
+// existing but not ready, rather than re-triggering Create.
+func (c *external) notFound(cr *v1beta1.Domain) managed.ExternalObservation {
+	created := meta.GetExternalCreateSucceeded(cr)
+	if created.IsZero() || time.Since(created) >= c.registrationGracePeriod {
+		return managed.ExternalObservation{ResourceExists: false}
+	}
+
+	cr.Status.SetConditions(v1beta1.Provisioning())
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: true,
+	}
+}
+
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1beta1.Domain)
 	if !ok {
 		return managed.ExternalCreation{}, errors.New(errNotDomain)
 	}
+	ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.DomainKind)
 
 	cr.Status.SetConditions(xpv1.Creating())
 
 	domainName := cr.Spec.ForProvider.DomainName
-	years := 1
-	if cr.Spec.ForProvider.RegistrationYears != nil {
-		years = *cr.Spec.ForProvider.RegistrationYears
+	years := registrationYears(cr.Spec.ForProvider)
+
+	if cr.Spec.ForProvider.Contacts == nil {
+		return managed.ExternalCreation{}, errors.New(errNoContacts)
 	}
 
-	// Create the domain
-	domain, err := c.client.CreateDomain(ctx, domainName, years)
-	if err != nil {
+	// Don't order a registration the balance didn't cover again until the
+	// spec changes
+	if err := common.CheckFunds(cr); err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateDomain)
 	}
 
-	// Set external name
-	meta.SetExternalName(cr, domainName)
+	if err := c.checkYears(ctx, domainName, years, "registration"); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateDomain)
+	}
+
+	// Reject invalid nameservers before the domain is registered and charged
+	nameservers := namecheap.NormalizeNameservers(cr.Spec.ForProvider.Nameservers)
+	if len(nameservers) > 0 {
+		if _, err := checkNameservers(nameservers); err != nil {
+			return managed.ExternalCreation{}, err
+		}
+	}
+
+	// Create the domain, with WhoisGuard enabled from the start when
+	// privacy is requested rather than once Update gets to it. A
+	// registration whose details can't be read back has still been ordered
+	// and charged, so it is recorded regardless.
+	privacy := cr.Spec.ForProvider.PrivacyProtection != nil && *cr.Spec.ForProvider.PrivacyProtection
+	registration, err := c.client.CreateDomain(ctx, domainName, years, domainContacts(cr.Spec.ForProvider.Contacts),
+		namecheap.DomainCreateOptions{
+			IdnCode:           stringValue(cr.Spec.ForProvider.IDNCode),
+			AddFreeWhoisguard: privacy,
+			EnableWhoisguard:  privacy,
+		})
+	if namecheap.IsInsufficientFunds(err) {
+		err = errors.Wrap(err, errCreateDomain)
+		common.RecordInsufficientFunds(cr, c.recorder, err)
+		return managed.ExternalCreation{}, err
+	}
+	if registration == nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateDomain)
+	}
+	if err != nil {
+		c.recorder.Event(cr, event.Warning(reasonDomainOrder, err))
+	}
+	common.RecordFundsSufficient(cr)
+
+	// Set external name, in the punycode form Namecheap reports. The name
+	// was valid to be registered, so it converts.
+	externalName, _ := namecheap.ToASCII(domainName)
+	meta.SetExternalName(cr, externalName)
 
 	// Update status
-	cr.Status.AtProvider.ID = strconv.Itoa(domain.ID)
+	cr.Status.AtProvider.ID = strconv.Itoa(registration.DomainID)
+	cr.Status.AtProvider.LastOrder = domainOrder(cr, v1beta1.DomainOrderCreate,
+		registration.OrderID, registration.TransactionID, registration.ChargedAmount)
+	if err := common.SetRecord(cr, annotationKeyCreateOrder, cr.Status.AtProvider.LastOrder); err != nil {
+		c.recorder.Event(cr, event.Warning(reasonDomainOrder, err))
+	}
 
-	// Set nameservers if specified
-	if len(cr.Spec.ForProvider.Nameservers) > 0 {
-		if err := c.client.SetNameservers(ctx, domainName, cr.Spec.ForProvider.Nameservers); err != nil {
-			return managed.ExternalCreation{}, errors.Wrap(err, errSetNameservers)
+	// Setting nameservers is best effort once the domain is registered.
+	// Failing creation here would have the registration attempted again,
+	// while Update retries the nameservers alone once Observe finds them
+	// out of date.
+	if len(nameservers) > 0 {
+		if err := c.setNameservers(ctx, cr); err != nil {
+			c.recorder.Event(cr, event.Warning(reasonPostRegistration,
+				errors.Wrap(err, "domain registered, retrying nameservers on the next update")))
 		}
 	}
+	if err := c.setPrivacy(ctx, cr); err != nil {
+		c.recorder.Event(cr, event.Warning(reasonPostRegistration,
+			errors.Wrap(err, "domain registered, retrying WhoisGuard on the next update")))
+	}
 
 	return managed.ExternalCreation{}, nil
 }
 
+// domainOrder returns the status record of a placed order
+func domainOrder(cr *v1beta1.Domain, action string, orderID, transactionID int, chargedAmount float64) *v1beta1.DomainOrder {
+	return &v1beta1.DomainOrder{
+		Action:        action,
+		OrderID:       orderID,
+		TransactionID: transactionID,
+		ChargedAmount: strconv.FormatFloat(chargedAmount, 'f', 2, 64),
+		Generation:    cr.GetGeneration(),
+	}
+}
+
+// domainContacts returns the client's contacts for the given spec, defaulting
+// omitted tech, admin and billing contacts to the registrant
+func domainContacts(spec *v1beta1.DomainContacts) namecheap.DomainContacts {
+	registrant := contact(spec.Registrant)
+	contacts := namecheap.DomainContacts{
+		Registrant: registrant,
+		Tech:       registrant,
+		Admin:      registrant,
+		AuxBilling: registrant,
+	}
+	if spec.Tech != nil {
+		contacts.Tech = contact(*spec.Tech)
+	}
+	if spec.Admin != nil {
+		contacts.Admin = contact(*spec.Admin)
+	}
+	if spec.AuxBilling != nil {
+		contacts.AuxBilling = contact(*spec.AuxBilling)
+	}
+	return contacts
+}
+
+// contact returns the client's contact for the given spec
+func contact(spec v1beta1.DomainContact) namecheap.Contact {
+	return namecheap.Contact{
+		OrganizationName: stringValue(spec.OrganizationName),
+		JobTitle:         stringValue(spec.JobTitle),
+		FirstName:        spec.FirstName,
+		LastName:         spec.LastName,
+		Address1:         spec.Address1,
+		Address2:         stringValue(spec.Address2),
+		City:             spec.City,
+		StateProvince:    spec.StateProvince,
+		PostalCode:       spec.PostalCode,
+		Country:          spec.Country,
+		Phone:            spec.Phone,
+		PhoneExt:         stringValue(spec.PhoneExt),
+		Fax:              stringValue(spec.Fax),
+		EmailAddress:     spec.EmailAddress,
+	}
+}
+
+// contactsDrift returns the fields of the observed contacts that differ from
+// the desired ones, ignoring surrounding whitespace, the case of email
+// addresses and StateProvinceChoice, which the spec doesn't set. Empty
+// optional fields are equal to unset ones.
+func contactsDrift(desired, observed namecheap.DomainContacts) []common.Drift {
+	var drifts []common.Drift
+	for _, role := range []struct {
+		name              string
+		desired, observed namecheap.Contact
+	}{
+		{"registrant", desired.Registrant, observed.Registrant},
+		{"tech", desired.Tech, observed.Tech},
+		{"admin", desired.Admin, observed.Admin},
+		{"auxBilling", desired.AuxBilling, observed.AuxBilling},
+	} {
+		d, o := role.desired, role.observed
+		for _, f := range []struct {
+			name              string
+			desired, observed string
+		}{
+			{"organizationName", d.OrganizationName, o.OrganizationName},
+			{"jobTitle", d.JobTitle, o.JobTitle},
+			{"firstName", d.FirstName, o.FirstName},
+			{"lastName", d.LastName, o.LastName},
+			{"address1", d.Address1, o.Address1},
+			{"address2", d.Address2, o.Address2},
+			{"city", d.City, o.City},
+			{"stateProvince", d.StateProvince, o.StateProvince},
+			{"postalCode", d.PostalCode, o.PostalCode},
+			{"country", d.Country, o.Country},
+			{"phone", d.Phone, o.Phone},
+			{"phoneExt", d.PhoneExt, o.PhoneExt},
+			{"fax", d.Fax, o.Fax},
+			{"emailAddress", strings.ToLower(d.EmailAddress), strings.ToLower(o.EmailAddress)},
+		} {
+			desired, observed := strings.TrimSpace(f.desired), strings.TrimSpace(f.observed)
+			if desired != observed {
+				drifts = append(drifts, common.Drift{
+					Field:    "spec.forProvider.contacts." + role.name + "." + f.name,
+					Expected: desired,
+					Observed: observed,
+				})
+			}
+		}
+	}
+	return drifts
+}
+
+// stringValue returns the string p points to, or "" if p is nil
+func stringValue(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
+
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
 	cr, ok := mg.(*v1beta1.Domain)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotDomain)
 	}
+	ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.DomainKind)
+
+	// Reactivate an expired domain before anything else, as Namecheap
+	// won't change an expired domain
+	if c.reactivate {
+		return managed.ExternalUpdate{}, c.reactivateDomain(ctx, cr)
+	}
+
+	// Retry enabling WhoisGuard alone when nothing else is out of date
+	if c.privacyRetryOnly {
+		return managed.ExternalUpdate{}, c.setPrivacy(ctx, cr)
+	}
 
 	domainName := cr.Spec.ForProvider.DomainName
 
-	// Handle domain renewal if requested
-	if cr.Spec.ForProvider.RenewalYears != nil {
-		years := *cr.Spec.ForProvider.RenewalYears
-		_, err := c.client.RenewDomain(ctx, domainName, years)
-		if err != nil {
-			return managed.ExternalUpdate{}, errors.Wrap(err, "cannot renew domain")
+	// Handle domain renewal if requested, unless the balance didn't cover
+	// it for the current spec
+	if renewalRequested(cr) && common.CheckFunds(cr) == nil {
+		if err := c.renewDomain(ctx, cr); err != nil {
+			return managed.ExternalUpdate{}, err
 		}
-		// Clear the renewal request after successful renewal
-		cr.Spec.ForProvider.RenewalYears = nil
 	}
 
 	// Handle WhoisGuard privacy protection
-	if cr.Spec.ForProvider.PrivacyProtection != nil {
-		whoisGuard, err := c.client.GetWhoisGuardForDomain(ctx, domainName)
-		enabled := *cr.Spec.ForProvider.PrivacyProtection
-
-		if err == nil {
-			// WhoisGuard exists, check if we need to enable/disable it
-			currentlyEnabled := whoisGuard.Status == "ENABLED"
-
-			if enabled && !currentlyEnabled {
-				// Enable WhoisGuard
-				forwardEmail := ""
-				if cr.Spec.ForProvider.WhoisGuardForwardEmail != nil {
-					forwardEmail = *cr.Spec.ForProvider.WhoisGuardForwardEmail
-				}
-				if err := c.client.EnableWhoisGuard(ctx, whoisGuard.ID, domainName, forwardEmail); err != nil {
-					return managed.ExternalUpdate{}, errors.Wrap(err, "cannot enable WhoisGuard")
-				}
-			} else if !enabled && currentlyEnabled {
-				// Disable WhoisGuard
-				if err := c.client.DisableWhoisGuard(ctx, whoisGuard.ID, domainName); err != nil {
-					return managed.ExternalUpdate{}, errors.Wrap(err, "cannot disable WhoisGuard")
-				}
-			}
-		}
+	if err := c.setPrivacy(ctx, cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	// Handle the registrar lock
+	if err := c.setRegistrarLock(ctx, cr); err != nil {
+		return managed.ExternalUpdate{}, err
 	}
 
-	// Update nameservers if specified
-	if len(cr.Spec.ForProvider.Nameservers) > 0 {
-		if err := c.client.SetNameservers(ctx, domainName, cr.Spec.ForProvider.Nameservers); err != nil {
+	// Update nameservers if specified, or switch back to Namecheap DNS
+	if len(namecheap.NormalizeNameservers(cr.Spec.ForProvider.Nameservers)) > 0 {
+		if err := c.setNameservers(ctx, cr); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	} else if c.defaultDNS {
+		if err := c.client.SetDefaultNameservers(ctx, domainName); err != nil {
 			return managed.ExternalUpdate{}, errors.Wrap(err, errSetNameservers)
 		}
+		cr.Status.AtProvider.AppliedNameservers = nil
+		c.recorder.Event(cr, event.Normal(reasonDefaultNameservers,
+			"Switched domain "+domainName+" back to Namecheap DNS"))
+	}
+
+	// Replace the contacts only when they have drifted, as changing the
+	// registrant can start a registrant verification
+	if c.contactsDrifted {
+		if err := c.client.SetDomainContacts(ctx, domainName, domainContacts(cr.Spec.ForProvider.Contacts)); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errSetContacts)
+		}
+		c.recorder.Event(cr, event.Normal(reasonContactsUpdated,
+			"updated contacts of domain "+domainName))
 	}
 
 	return managed.ExternalUpdate{}, nil
 }
 
+// renewDomain renews the domain for the requested years, recording the
+// order. A renewal the balance doesn't cover is reported in the Funds
+// condition rather than returned, so that the rest of the spec is still
+// applied.
+func (c *external) renewDomain(ctx context.Context, cr *v1beta1.Domain) error {
+	domainName := cr.Spec.ForProvider.DomainName
+	years := *cr.Spec.ForProvider.RenewalYears
+	if err := c.checkYears(ctx, domainName, years, "renewal"); err != nil {
+		return errors.Wrap(err, errRenewDomain)
+	}
+	renewal, err := c.client.RenewDomain(ctx, domainName, years)
+	if namecheap.IsInsufficientFunds(err) {
+		common.RecordInsufficientFunds(cr, c.recorder, errors.Wrap(err, errRenewDomain))
+		return nil
+	}
+	if renewal == nil {
+		return errors.Wrap(err, errRenewDomain)
+	}
+	if err != nil {
+		c.recorder.Event(cr, event.Warning(reasonDomainOrder, err))
+	}
+	common.RecordFundsSufficient(cr)
+	cr.Status.AtProvider.LastOrder = domainOrder(cr, v1beta1.DomainOrderRenew,
+		renewal.OrderID, renewal.TransactionID, renewal.ChargedAmount)
+	return nil
+}
+
+// renewalRequested reports whether the spec requests a renewal that hasn't
+// been ordered yet. The managed reconciler doesn't save spec changes made in
+// Update, so the request can't be cleared once ordered; instead a renewal is
+// ordered once per generation of the spec, as recorded in the last order.
+func renewalRequested(cr *v1beta1.Domain) bool {
+	if cr.Spec.ForProvider.RenewalYears == nil {
+		return false
+	}
+	last := cr.Status.AtProvider.LastOrder
+	return last == nil || last.Action != v1beta1.DomainOrderRenew || last.Generation != cr.GetGeneration()
+}
+
+// reactivateDomain reactivates an expired domain, recording the order. A
+// reactivation Namecheap refuses, for example because the domain is past its
+// redemption period, is reported in the Reactivation condition rather than
+// returned, so that it isn't attempted again until the spec changes.
+func (c *external) reactivateDomain(ctx context.Context, cr *v1beta1.Domain) error {
+	domainName := cr.Spec.ForProvider.DomainName
+	reactivation, err := c.client.ReactivateDomain(ctx, domainName, stringValue(cr.Spec.ForProvider.ReactivationPromoCode))
+	if namecheap.IsReactivationRefused(err) {
+		err = errors.Wrap(err, errReactivateDomain)
+		cr.Status.SetConditions(v1beta1.ReactivationFailed(err.Error()).WithObservedGeneration(cr.GetGeneration()))
+		c.recorder.Event(cr, event.Warning(reasonReactivation, err))
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errReactivateDomain)
+	}
+
+	order := domainOrder(cr, v1beta1.DomainOrderReactivate,
+		reactivation.OrderID, reactivation.TransactionID, reactivation.ChargedAmount)
+	cr.Status.AtProvider.LastOrder = order
+	expired := false
+	cr.Status.AtProvider.IsExpired = &expired
+
+	message := fmt.Sprintf("reactivated domain %s in order %d, charged %s", domainName, order.OrderID, order.ChargedAmount)
+	cr.Status.SetConditions(v1beta1.Reactivated(message))
+	c.recorder.Event(cr, event.Normal(reasonDomainOrder, message))
+	return nil
+}
+
+// reactivationRefused reports whether Namecheap refused to reactivate the
+// domain for the current generation of its spec
+func reactivationRefused(cr *v1beta1.Domain) bool {
+	cond := cr.Status.GetCondition(v1beta1.TypeReactivation)
+	return cond.Reason == v1beta1.ReasonReactivationFailed && cond.ObservedGeneration == cr.GetGeneration()
+}
+
+// setRegistrarLock enables or disables the registrar lock when the lock
+// observed last differs from the spec
+func (c *external) setRegistrarLock(ctx context.Context, cr *v1beta1.Domain) error {
+	lock, observed := cr.Spec.ForProvider.RegistrarLock, cr.Status.AtProvider.IsLocked
+	if lock == nil || (observed != nil && *observed == *lock) {
+		return nil
+	}
+
+	domainName := cr.Spec.ForProvider.DomainName
+	if err := c.client.SetRegistrarLock(ctx, domainName, *lock); err != nil {
+		return errors.Wrap(err, errSetRegistrarLock)
+	}
+	locked := *lock
+	cr.Status.AtProvider.IsLocked = &locked
+	cr.Status.AtProvider.RegistrarLockEnabled = &locked
+
+	action := "disabled"
+	if *lock {
+		action = "enabled"
+	}
+	c.recorder.Event(cr, event.Normal(reasonRegistrarLock,
+		"registrar lock "+action+" for domain "+domainName))
+	return nil
+}
+
+// setPrivacy enables or disables WhoisGuard as the spec requests. Enabling
+// WhoisGuard before a newly registered domain's subscription is active is
+// scheduled for retry, tracked in the Converging condition, rather than
+// returned as an error until the retries are exhausted.
+func (c *external) setPrivacy(ctx context.Context, cr *v1beta1.Domain) error {
+	if cr.Spec.ForProvider.PrivacyProtection == nil {
+		c.privacyConverged(cr)
+		return nil
+	}
+	domainName := cr.Spec.ForProvider.DomainName
+	enabled := *cr.Spec.ForProvider.PrivacyProtection
+
+	whoisGuard, err := c.client.GetWhoisGuardForDomain(ctx, domainName)
+	if err != nil {
+		// A domain without WhoisGuard has no privacy to change
+		return nil
+	}
+
+	currentlyEnabled := whoisGuard.Status == "ENABLED"
+	forward := forwardEmail(cr)
+	switch {
+	case enabled && !currentlyEnabled:
+		err := c.client.EnableWhoisGuard(ctx, whoisGuard.ID, domainName, forward)
+		if namecheap.IsWhoisGuardNotReady(err) {
+			return c.retryPrivacy(cr, err)
+		}
+		if err != nil {
+			return errors.Wrap(err, "cannot enable WhoisGuard")
+		}
+	case enabled && forward != "":
+		// whoisguard.getList doesn't report the forwarded-to address, so it
+		// is read from domains.getInfo
+		info, err := c.client.GetDomainInfo(ctx, domainName)
+		if err != nil {
+			return errors.Wrap(err, errGetDomain)
+		}
+		if !forwardEmailDrifted(forward, info.WhoisGuardForwardedTo) {
+			break
+		}
+		// Enabling WhoisGuard again is how its forwarded-to address is
+		// changed
+		if err := c.client.EnableWhoisGuard(ctx, whoisGuard.ID, domainName, forward); err != nil {
+			return errors.Wrap(err, "cannot change WhoisGuard forward email")
+		}
+		c.recorder.Event(cr, event.Normal(reasonWhoisGuardForwardEmail, fmt.Sprintf(
+			"WhoisGuard emails for domain %s are forwarded to %s instead of %s",
+			domainName, forward, info.WhoisGuardForwardedTo)))
+	case !enabled && currentlyEnabled:
+		if err := c.client.DisableWhoisGuard(ctx, whoisGuard.ID, domainName); err != nil {
+			return errors.Wrap(err, "cannot disable WhoisGuard")
+		}
+	}
+
+	c.privacyConverged(cr)
+	return nil
+}
+
+// forwardEmail returns the address the spec forwards WhoisGuard emails to,
+// empty when it declares none
+func forwardEmail(cr *v1beta1.Domain) string {
+	if forward := cr.Spec.ForProvider.WhoisGuardForwardEmail; forward != nil {
+		return *forward
+	}
+	return ""
+}
+
+// forwardEmailDrifted reports whether WhoisGuard emails are forwarded to
+// forwardedTo rather than the declared address forward. Only a declared
+// address is compared.
+func forwardEmailDrifted(forward, forwardedTo string) bool {
+	return forward != "" && !strings.EqualFold(strings.TrimSpace(forward), strings.TrimSpace(forwardedTo))
+}
+
+// retryPrivacy schedules the next attempt to enable WhoisGuard, or returns
+// the cause once maxPrivacyAttempts attempts have failed
+func (c *external) retryPrivacy(cr *v1beta1.Domain, cause error) error {
+	attempts := 1
+	if retry := cr.Status.AtProvider.PrivacyRetry; retry != nil {
+		attempts = retry.Attempts + 1
+	}
+
+	if attempts >= maxPrivacyAttempts {
+		cr.Status.AtProvider.PrivacyRetry = nil
+		err := errors.Wrapf(cause, "cannot enable WhoisGuard after %d attempts", attempts)
+		cr.Status.SetConditions(v1beta1.PrivacyFailed(err.Error()))
+		return err
+	}
+
+	backoff := privacyRetryBackoff << (attempts - 1)
+	cr.Status.AtProvider.PrivacyRetry = &v1beta1.PrivacyRetry{
+		Attempts:        attempts,
+		NextAttemptTime: metav1.NewTime(time.Now().Add(backoff)),
+	}
+	message := "WhoisGuard is not active yet; enabling it again in " + backoff.String()
+	cr.Status.SetConditions(v1beta1.PrivacyPending(message))
+	c.recorder.Event(cr, event.Normal(reasonPrivacyRetry, message))
+	return nil
+}
+
+// privacyConverged records that WhoisGuard is as the spec requests, ending
+// any scheduled retry
+func (c *external) privacyConverged(cr *v1beta1.Domain) {
+	if cr.Status.AtProvider.PrivacyRetry == nil && cr.GetCondition(v1beta1.TypeConverging).Reason != v1beta1.ReasonPrivacyPending {
+		return
+	}
+	cr.Status.AtProvider.PrivacyRetry = nil
+	cr.Status.SetConditions(v1beta1.Converged())
+}
+
+// privacyRetryHook shortens the poll interval of a Domain with a scheduled
+// retry of enabling WhoisGuard so that it is observed when the retry is due
+func privacyRetryHook(hook managed.PollIntervalHook) managed.PollIntervalHook {
+	return func(mg resource.Managed, pollInterval time.Duration) time.Duration {
+		interval := hook(mg, pollInterval)
+		cr, ok := mg.(*v1beta1.Domain)
+		if !ok || cr.Status.AtProvider.PrivacyRetry == nil {
+			return interval
+		}
+		// A zero interval would never requeue
+		return min(interval, max(time.Until(cr.Status.AtProvider.PrivacyRetry.NextAttemptTime.Time), time.Second))
+	}
+}
+
+// setNameservers applies the desired nameservers. Setting Namecheap's own
+// nameservers as custom nameservers would disable the hosted DNS zone and
+// break every DNSRecord of the domain, so a list made up only of Namecheap
+// nameservers switches the domain back to Namecheap DNS instead, and a list
+// mixing them with other nameservers is rejected.
+func (c *external) setNameservers(ctx context.Context, cr *v1beta1.Domain) error {
+	domainName := cr.Spec.ForProvider.DomainName
+	nameservers := namecheap.NormalizeNameservers(cr.Spec.ForProvider.Nameservers)
+	namecheapOnly, err := checkNameservers(nameservers)
+	if err != nil {
+		return err
+	}
+
+	if namecheapOnly {
+		c.recorder.Event(cr, event.Normal(reasonDefaultNameservers,
+			"Nameservers are Namecheap's own; using Namecheap DNS instead of setting them as custom nameservers"))
+		if err := c.client.SetDefaultNameservers(ctx, domainName); err != nil {
+			return errors.Wrap(err, errSetNameservers)
+		}
+		cr.Status.AtProvider.AppliedNameservers = nil
+		return nil
+	}
+	if err := c.client.SetNameservers(ctx, domainName, nameservers); err != nil {
+		return errors.Wrap(err, errSetNameservers)
+	}
+	cr.Status.AtProvider.AppliedNameservers = nameservers
+	return nil
+}
+
+// wantsDefaultDNS reports whether the spec wants the domain on Namecheap DNS:
+// either it says so, or it no longer lists the custom nameservers the
+// provider applied. A spec that never listed nameservers leaves them alone.
+func wantsDefaultDNS(cr *v1beta1.Domain) bool {
+	if useDefault := cr.Spec.ForProvider.UseDefaultDNS; useDefault != nil {
+		return *useDefault
+	}
+	return len(namecheap.NormalizeNameservers(cr.Spec.ForProvider.Nameservers)) == 0 &&
+		len(cr.Status.AtProvider.AppliedNameservers) > 0
+}
+
+// checkNameservers validates normalized nameservers and reports whether they
+// are all Namecheap's own
+func checkNameservers(nameservers []string) (bool, error) {
+	if err := namecheap.ValidateNameservers(nameservers); err != nil {
+		return false, errors.Wrap(err, errSetNameservers)
+	}
+
+	namecheapNS := 0
+	for _, ns := range nameservers {
+		if namecheap.IsNamecheapNameserver(ns) {
+			namecheapNS++
+		}
+	}
+
+	switch namecheapNS {
+	case 0:
+		return false, nil
+	case len(nameservers):
+		return true, nil
+	default:
+		return false, errors.New(errMixedNameservers)
+	}
+}
+
 func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
 	cr, ok := mg.(*v1beta1.Domain)
 	if !ok {
@@ -291,8 +1207,54 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 	cr.Status.SetConditions(xpv1.Deleting())
 
 	// Note: Namecheap doesn't support domain deletion via API
-	// Domains remain in the account but cannot be programmatically deleted
-	// This is a limitation of the Namecheap API
+	// Domains remain in the account but cannot be programmatically deleted,
+	// so the deletion behavior decides what, if anything, to clean up
+	behavior := v1beta1.DeletionBehaviorOrphan
+	if cr.Spec.ForProvider.DeletionBehavior != nil {
+		behavior = *cr.Spec.ForProvider.DeletionBehavior
+	}
+
+	switch behavior {
+	case v1beta1.DeletionBehaviorDisableRenewals:
+		ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.DomainKind)
+		return managed.ExternalDelete{}, c.disableRenewals(ctx, cr)
+	case v1beta1.DeletionBehaviorReleaseDNS:
+		ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.DomainKind)
+		if err := c.client.SetDefaultNameservers(ctx, cr.Spec.ForProvider.DomainName); err != nil {
+			return managed.ExternalDelete{}, errors.Wrap(err, errDeleteDomain)
+		}
+		c.recorder.Event(cr, event.Normal(reasonDeletionBehavior,
+			"Reset the domain to Namecheap's default nameservers"))
+	}
 
 	return managed.ExternalDelete{}, nil
-}
\ No newline at end of file
+}
+
+// deleted reports whether Delete succeeded for cr. The managed reconciler
+// records every deletion attempt in the Deleting reason of the Ready
+// condition, and whether it succeeded in the Synced condition.
+func deleted(cr *v1beta1.Domain) bool {
+	return cr.GetCondition(xpv1.TypeReady).Reason == xpv1.ReasonDeleting &&
+		cr.GetCondition(xpv1.TypeSynced).Reason == xpv1.ReasonReconcileSuccess
+}
+
+// disableRenewals disables WhoisGuard so that it lapses with the domain. The
+// Namecheap API can't change a domain's auto-renew setting, so that is left
+// to the user with a warning.
+func (c *external) disableRenewals(ctx context.Context, cr *v1beta1.Domain) error {
+	domainName := cr.Spec.ForProvider.DomainName
+
+	whoisGuard, err := c.client.GetWhoisGuardForDomain(ctx, domainName)
+	if err == nil && whoisGuard.Status == "ENABLED" {
+		if err := c.client.DisableWhoisGuard(ctx, whoisGuard.ID, domainName); err != nil {
+			return errors.Wrap(err, errDeleteDomain)
+		}
+		c.recorder.Event(cr, event.Normal(reasonDeletionBehavior,
+			"Disabled WhoisGuard so that it lapses with the domain"))
+	}
+
+	c.recorder.Event(cr, event.Warning(reasonDeletionBehavior,
+		errors.New("auto-renew cannot be turned off through the Namecheap API; "+
+			"turn it off in the Namecheap dashboard to let the domain lapse")))
+	return nil
+}
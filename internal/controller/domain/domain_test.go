@@ -0,0 +1,1797 @@
+package domain
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/internal/controller/common"
+	"github.com/rossigee/provider-namecheap/internal/fakeserver"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+// recorder captures the events recorded by an external client.
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+// withReason returns the recorded events with the given reason
+func (r *recorder) withReason(reason event.Reason) []event.Event {
+	var events []event.Event
+	for _, e := range r.events {
+		if e.Reason == reason {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// fakeDomain is the domain state reported by the fake Namecheap API.
+type fakeDomain struct {
+	transferOutPending bool
+	registrarLock      bool
+	statuses           []string
+	nameservers        []string
+	whoisGuardStatus   string
+
+	// whoisGuardForwardedTo is where WhoisGuard emails are forwarded, as
+	// last set by whoisguard.enable
+	whoisGuardForwardedTo string
+
+	// expired makes domains.getInfo report the domain as expired until it
+	// is reactivated, which fails if reactivationRefused is set
+	expired             bool
+	reactivationRefused bool
+
+	// missing makes domains.getInfo report the domain as not found
+	missing bool
+
+	// hostTypes are the types of the domain's DNS host records, reported by
+	// domains.dns.getHosts unless the domain isn't using Namecheap DNS
+	hostTypes []string
+	notOurDNS bool
+
+	// lockUnavailable makes domains.getRegistrarLock fail
+	lockUnavailable bool
+
+	// nameserversUnavailable makes domains.dns.setCustom fail
+	nameserversUnavailable bool
+
+	// whoisGuardNotReady is the number of times whoisguard.enable fails
+	// because the WhoisGuard subscription is not active yet
+	whoisGuardNotReady int
+
+	// contacts are the contacts reported by domains.getContacts and
+	// replaced by domains.setContacts
+	contacts namecheap.DomainContacts
+
+	// calls records the mutating commands received
+	calls []string
+
+	// created records the parameters of the last domains.create request
+	created url.Values
+
+	// infoRequested records the DomainName of the last domains.getInfo
+	// request
+	infoRequested string
+
+	// tlds are the Tld elements reported by domains.getTldList, by default
+	// a com TLD supporting everything
+	tlds string
+
+	// availableBalance, when set, is the balance reported by
+	// users.getBalances, and has the client check it before ordering
+	availableBalance *float64
+}
+
+// tldCom is a TLD supporting everything through the API
+const tldCom = `<Tld Name="com" IsApiRegisterable="true" IsApiRenewable="true" IsApiTransferable="true" IsEppRequired="true" ` +
+	`IsDisableModContact="false" SupportsRegistrarLock="true" WhoisVerification="false"/>`
+
+// setContacts replaces the domain's contacts with those in the parameters of
+// a domains.create or domains.setContacts request
+func (d *fakeDomain) setContacts(form url.Values) {
+	for _, role := range []struct {
+		prefix  string
+		contact *namecheap.Contact
+	}{
+		{"Registrant", &d.contacts.Registrant},
+		{"Tech", &d.contacts.Tech},
+		{"Admin", &d.contacts.Admin},
+		{"AuxBilling", &d.contacts.AuxBilling},
+	} {
+		*role.contact = namecheap.Contact{
+			OrganizationName: form.Get(role.prefix + "OrganizationName"),
+			JobTitle:         form.Get(role.prefix + "JobTitle"),
+			FirstName:        form.Get(role.prefix + "FirstName"),
+			LastName:         form.Get(role.prefix + "LastName"),
+			Address1:         form.Get(role.prefix + "Address1"),
+			Address2:         form.Get(role.prefix + "Address2"),
+			City:             form.Get(role.prefix + "City"),
+			StateProvince:    form.Get(role.prefix + "StateProvince"),
+			PostalCode:       form.Get(role.prefix + "PostalCode"),
+			Country:          form.Get(role.prefix + "Country"),
+			Phone:            form.Get(role.prefix + "Phone"),
+			PhoneExt:         form.Get(role.prefix + "PhoneExt"),
+			Fax:              form.Get(role.prefix + "Fax"),
+			EmailAddress:     form.Get(role.prefix + "EmailAddress"),
+		}
+	}
+}
+
+// newTestExternal returns an external client backed by a fake Namecheap API
+// that reports the supplied domain state, plus a pointer to the number of
+// requests that asked to bypass caches.
+func newTestExternal(t *testing.T, d *fakeDomain) (*external, *recorder, *int) {
+	t.Helper()
+
+	freshReads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Cache-Control") == "no-cache" {
+			freshReads++
+		}
+		w.Header().Set("Content-Type", "application/xml")
+
+		switch command := r.FormValue("Command"); command {
+		case "namecheap.domains.getInfo":
+			d.infoRequested = r.FormValue("DomainName")
+			if d.missing {
+				fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="2019166">Domain not found</Error>
+	</Errors>
+</ApiResponse>`)
+				return
+			}
+			statuses := ""
+			for _, status := range d.statuses {
+				statuses += "<Status>" + status + "</Status>"
+			}
+			nameservers := ""
+			for _, ns := range d.nameservers {
+				nameservers += "<Nameserver>" + ns + "</Nameserver>"
+			}
+			status := "Ok"
+			if d.expired {
+				status = "Expired"
+			}
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainGetInfoResult Status="%s" ID="125" DomainName="example.com" OwnerName="testuser" IsOwner="true" IsPremium="false">
+			<DomainDetails>
+				<CreatedDate>01/01/2024</CreatedDate>
+				<ExpiredDate>01/01/2025</ExpiredDate>
+			</DomainDetails>
+			<LockDetails TransferOutPending="%t"/>
+			<Whoisguard Enabled="%t">
+				<EmailDetails ForwardedTo="%s"/>
+			</Whoisguard>
+			<DomainStatuses>%s</DomainStatuses>
+			<DnsDetails ProviderType="CUSTOM" IsUsingOurDNS="false">%s</DnsDetails>
+		</DomainGetInfoResult>
+	</CommandResponse>
+</ApiResponse>`, status, d.transferOutPending, d.whoisGuardStatus == "ENABLED", d.whoisGuardForwardedTo, statuses, nameservers)
+		case "namecheap.domains.getRegistrarLock":
+			if d.lockUnavailable {
+				fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="5050900">Unhandled exception</Error>
+	</Errors>
+</ApiResponse>`)
+				return
+			}
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainGetRegistrarLockResult Domain="example.com" RegistrarLockStatus="%t"/>
+	</CommandResponse>
+</ApiResponse>`, d.registrarLock)
+		case "namecheap.domains.setRegistrarLock":
+			d.calls = append(d.calls, command)
+			d.registrarLock = r.FormValue("LockAction") == "LOCK"
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainSetRegistrarLockResult Domain="example.com" IsSuccess="true"/>
+	</CommandResponse>
+</ApiResponse>`)
+		case "namecheap.domains.dns.setCustom", "namecheap.domains.dns.setDefault":
+			d.calls = append(d.calls, command)
+			if d.nameserversUnavailable {
+				fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="5050900">Unhandled exception</Error>
+	</Errors>
+</ApiResponse>`)
+				return
+			}
+			result := "DomainDNSSetCustomResult"
+			if command == "namecheap.domains.dns.setDefault" {
+				result = "DomainDNSSetDefaultResult"
+			}
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<%s Domain="example.com" Updated="true"/>
+	</CommandResponse>
+</ApiResponse>`, result)
+		case "namecheap.domains.dns.getHosts":
+			if d.notOurDNS {
+				fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="2030288">Domain is not using Namecheap DNS</Error>
+	</Errors>
+</ApiResponse>`)
+				return
+			}
+			hosts := ""
+			for i, hostType := range d.hostTypes {
+				hosts += fmt.Sprintf(`<host HostId="%d" Name="@" Type="%s" Address="192.0.2.1"/>`, i+1, hostType)
+			}
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSGetHostsResult Domain="example.com" IsUsingOurDNS="true">%s</DomainDNSGetHostsResult>
+	</CommandResponse>
+</ApiResponse>`, hosts)
+		case "namecheap.domains.create":
+			d.calls = append(d.calls, command)
+			d.created = r.Form
+			d.setContacts(r.Form)
+			if r.FormValue("WGEnabled") == "yes" {
+				d.whoisGuardStatus = "ENABLED"
+			}
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainCreateResult Domain="example.com" Registered="true" ChargedAmount="20.87" DomainID="125" OrderID="196074" TransactionID="380716"/>
+	</CommandResponse>
+</ApiResponse>`)
+		case "namecheap.domains.reactivate":
+			d.calls = append(d.calls, command)
+			if d.reactivationRefused {
+				fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="2019166">Domain not found</Error>
+	</Errors>
+</ApiResponse>`)
+				return
+			}
+			d.expired = false
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainReactivateResult Domain="example.com" IsSuccess="true" ChargedAmount="650.00" OrderID="23569" TransactionID="25080"/>
+	</CommandResponse>
+</ApiResponse>`)
+		case "namecheap.domains.getTldList":
+			tlds := d.tlds
+			if tlds == "" {
+				tlds = tldCom
+			}
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<Tlds>%s</Tlds>
+	</CommandResponse>
+</ApiResponse>`, tlds)
+		case "namecheap.users.getPricing":
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<UserGetPricingResult>
+			<ProductType Name="domains">
+				<ProductCategory Name="%s">
+					<Product Name="com">
+						<Price Duration="1" DurationType="YEAR" Price="10.98" YourPrice="10.98" YourAdditonalCost="0.18" Currency="USD"/>
+					</Product>
+				</ProductCategory>
+			</ProductType>
+		</UserGetPricingResult>
+	</CommandResponse>
+</ApiResponse>`, strings.ToLower(r.FormValue("ActionName")))
+		case "namecheap.users.getBalances":
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<UserGetBalancesResult Currency="USD" AvailableBalance="%.2f" AccountBalance="%.2f"/>
+	</CommandResponse>
+</ApiResponse>`, *d.availableBalance, *d.availableBalance)
+		case "namecheap.whoisguard.getList":
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<WhoisguardGetListResult>
+			<Whoisguard ID="42" DomainName="example.com" Expires="03/15/2027" Status="%s"/>
+		</WhoisguardGetListResult>
+	</CommandResponse>
+</ApiResponse>`, d.whoisGuardStatus)
+		case "namecheap.domains.getContacts":
+			result, err := xml.Marshal(struct {
+				XMLName xml.Name `xml:"DomainContactsResult"`
+				namecheap.DomainContacts
+			}{DomainContacts: d.contacts})
+			require.NoError(t, err)
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>%s</CommandResponse>
+</ApiResponse>`, result)
+		case "namecheap.domains.setContacts":
+			d.calls = append(d.calls, command)
+			d.setContacts(r.Form)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainSetContactResult Domain="example.com" IsSuccess="true"/>
+	</CommandResponse>
+</ApiResponse>`)
+		case "namecheap.whoisguard.enable":
+			d.calls = append(d.calls, command)
+			if d.whoisGuardNotReady > 0 {
+				d.whoisGuardNotReady--
+				fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="3031510">WhoisGuard subscription is not active</Error>
+	</Errors>
+</ApiResponse>`)
+				return
+			}
+			d.whoisGuardStatus = "ENABLED"
+			if forward := r.Form.Get("ForwardedToEmail"); forward != "" {
+				d.whoisGuardForwardedTo = forward
+			}
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<WhoisguardEnableResult Domain="example.com" IsSuccess="true"/>
+	</CommandResponse>
+</ApiResponse>`)
+		case "namecheap.whoisguard.disable":
+			d.calls = append(d.calls, command)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<WhoisguardDisableResult Domain="example.com" IsSuccess="true"/>
+	</CommandResponse>
+</ApiResponse>`)
+		default:
+			t.Errorf("unexpected command %s", command)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := namecheap.NewClient(namecheap.Config{
+		APIUser:      "testuser",
+		APIKey:       "testkey",
+		Username:     "testuser",
+		ClientIP:     "127.0.0.1",
+		BaseURL:      server.URL,
+		HTTPClient:   &http.Client{Timeout: 5 * time.Second},
+		CheckBalance: d.availableBalance != nil,
+	})
+
+	rec := &recorder{}
+	return &external{client: client, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}, rec, &freshReads
+}
+
+func TestObserve_TransferOutPending(t *testing.T) {
+	d := &fakeDomain{}
+	e, rec, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+
+	observe := func() {
+		t.Helper()
+		_, err := e.Observe(context.Background(), cr)
+		require.NoError(t, err)
+	}
+
+	// A transfer-out of a domain the spec doesn't lock is reported, but not
+	// alerted on
+	d.transferOutPending = true
+	observe()
+	assert.Equal(t, corev1.ConditionTrue, cr.Status.GetCondition(v1beta1.TypeTransferOut).Status)
+	assert.Empty(t, rec.withReason(reasonTransferOutPending))
+
+	d.transferOutPending = false
+	d.registrarLock = true
+	cr.Spec.ForProvider.RegistrarLock = boolPtr(true)
+	observe()
+	require.NotNil(t, cr.Status.AtProvider.TransferOutPending)
+	assert.False(t, *cr.Status.AtProvider.TransferOutPending)
+	assert.Equal(t, corev1.ConditionFalse, cr.Status.GetCondition(v1beta1.TypeTransferOut).Status)
+	assert.Empty(t, rec.events)
+
+	// A transfer-out request appears: alert once
+	d.transferOutPending = true
+	observe()
+	assert.True(t, *cr.Status.AtProvider.TransferOutPending)
+	assert.Equal(t, corev1.ConditionTrue, cr.Status.GetCondition(v1beta1.TypeTransferOut).Status)
+	require.Len(t, rec.events, 1)
+	assert.Equal(t, event.TypeWarning, rec.events[0].Type)
+	assert.Equal(t, reasonTransferOutPending, rec.events[0].Reason)
+
+	// Still pending: no further alerts
+	observe()
+	assert.Len(t, rec.events, 1)
+
+	// Request goes away
+	d.transferOutPending = false
+	observe()
+	assert.False(t, *cr.Status.AtProvider.TransferOutPending)
+	assert.Equal(t, corev1.ConditionFalse, cr.Status.GetCondition(v1beta1.TypeTransferOut).Status)
+	assert.Len(t, rec.events, 1)
+}
+
+func TestObserve_Refresh(t *testing.T) {
+	e, _, freshReads := newTestExternal(t, &fakeDomain{})
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+
+	observe := func() {
+		t.Helper()
+		_, err := e.Observe(context.Background(), cr)
+		require.NoError(t, err)
+	}
+
+	observe()
+	assert.Equal(t, 0, *freshReads, "no refresh requested")
+
+	cr.SetAnnotations(map[string]string{common.AnnotationKeyRefresh: "2024-01-01T00:00:00Z"})
+	observe()
+	assert.Positive(t, *freshReads, "refresh should bypass caches")
+	assert.Equal(t, "2024-01-01T00:00:00Z", cr.Status.AtProvider.LastHandledRefresh)
+
+	// The same value does not trigger another fresh read
+	handled := *freshReads
+	observe()
+	assert.Equal(t, handled, *freshReads)
+
+	// A new value triggers exactly one more
+	cr.SetAnnotations(map[string]string{common.AnnotationKeyRefresh: "2024-01-02T00:00:00Z"})
+	observe()
+	assert.Equal(t, 2*handled, *freshReads)
+	observe()
+	assert.Equal(t, 2*handled, *freshReads)
+	assert.Equal(t, "2024-01-02T00:00:00Z", cr.Status.AtProvider.LastHandledRefresh)
+}
+
+func TestObserve_RegistryStatuses(t *testing.T) {
+	tests := []struct {
+		name             string
+		domain           fakeDomain
+		registryStatuses []string
+		condition        corev1.ConditionStatus
+	}{
+		{
+			name:      "registrar lock only",
+			domain:    fakeDomain{registrarLock: true, statuses: []string{"clientTransferProhibited"}},
+			condition: corev1.ConditionFalse,
+		},
+		{
+			name:             "registry imposed",
+			domain:           fakeDomain{statuses: []string{"clientTransferProhibited", "serverTransferProhibited", "serverHold"}},
+			registryStatuses: []string{"serverTransferProhibited", "serverHold"},
+			condition:        corev1.ConditionTrue,
+		},
+		{
+			name:      "no statuses",
+			condition: corev1.ConditionFalse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _, _ := newTestExternal(t, &tt.domain)
+
+			cr := &v1beta1.Domain{}
+			cr.Spec.ForProvider.DomainName = "example.com"
+
+			obs, err := e.Observe(context.Background(), cr)
+			require.NoError(t, err)
+			assert.True(t, obs.ResourceUpToDate, "registry statuses must not trigger an update")
+
+			require.NotNil(t, cr.Status.AtProvider.RegistrarLockEnabled)
+			assert.Equal(t, tt.domain.registrarLock, *cr.Status.AtProvider.RegistrarLockEnabled)
+			assert.Equal(t, tt.registryStatuses, cr.Status.AtProvider.RegistryStatuses)
+			assert.Equal(t, tt.condition, cr.Status.GetCondition(v1beta1.TypeRegistryStatus).Status)
+		})
+	}
+}
+
+func TestUpdate_Nameservers(t *testing.T) {
+	tests := []struct {
+		name        string
+		nameservers []string
+		calls       []string
+		events      int
+		wantErr     bool
+	}{
+		{
+			name:        "custom nameservers",
+			nameservers: []string{"ns1.example.net", "ns2.example.net"},
+			calls:       []string{"namecheap.domains.dns.setCustom"},
+		},
+		{
+			name:        "Namecheap nameservers use Namecheap DNS",
+			nameservers: []string{"dns1.registrar-servers.com", "DNS2.registrar-servers.com."},
+			calls:       []string{"namecheap.domains.dns.setDefault"},
+			events:      1,
+		},
+		{
+			name:        "messy custom nameservers",
+			nameservers: []string{"", "ns1.example.net ", "NS2.example.net."},
+			calls:       []string{"namecheap.domains.dns.setCustom"},
+		},
+		{
+			name:        "malformed nameservers are rejected",
+			nameservers: []string{"ns1.example.net", "ns2_example"},
+			wantErr:     true,
+		},
+		{
+			name:        "mixed nameservers are rejected",
+			nameservers: []string{"dns1.registrar-servers.com", "ns1.example.net"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &fakeDomain{}
+			e, rec, _ := newTestExternal(t, d)
+
+			cr := &v1beta1.Domain{}
+			cr.Spec.ForProvider.DomainName = "example.com"
+			cr.Spec.ForProvider.Nameservers = tt.nameservers
+
+			_, err := e.Update(context.Background(), cr)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.calls, d.calls)
+			assert.Len(t, rec.events, tt.events)
+		})
+	}
+}
+
+func TestObserve_Nameservers(t *testing.T) {
+	tests := []struct {
+		name     string
+		desired  []string
+		observed []string
+		upToDate bool
+	}{
+		{
+			name:     "unspecified",
+			observed: []string{"ns1.example.net"},
+			upToDate: true,
+		},
+		{
+			name:     "only empty entries",
+			desired:  []string{"", "  "},
+			observed: []string{"ns1.example.net"},
+			upToDate: true,
+		},
+		{
+			name:     "messy but equal",
+			desired:  []string{"", "NS2.example.net. ", " ns1.example.net"},
+			observed: []string{"ns1.example.net", "ns2.example.net"},
+			upToDate: true,
+		},
+		{
+			name:     "different",
+			desired:  []string{"ns1.example.net", "ns3.example.net"},
+			observed: []string{"ns1.example.net", "ns2.example.net"},
+		},
+		{
+			name:     "subset",
+			desired:  []string{"ns1.example.net"},
+			observed: []string{"ns1.example.net", "ns2.example.net"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _, _ := newTestExternal(t, &fakeDomain{nameservers: tt.observed})
+
+			cr := &v1beta1.Domain{}
+			cr.Spec.ForProvider.DomainName = "example.com"
+			cr.Spec.ForProvider.Nameservers = tt.desired
+
+			obs, err := e.Observe(context.Background(), cr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.upToDate, obs.ResourceUpToDate)
+			assert.Equal(t, tt.observed, cr.Status.AtProvider.Nameservers)
+			assert.Equal(t, "CUSTOM", cr.Status.AtProvider.DNSProviderType)
+			require.NotNil(t, cr.Status.AtProvider.IsOurDNS)
+			assert.False(t, *cr.Status.AtProvider.IsOurDNS)
+		})
+	}
+}
+
+func TestObserve_Environment(t *testing.T) {
+	e, _, _ := newTestExternal(t, &fakeDomain{})
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+
+	// The environment is recorded at the first successful observation
+	_, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, namecheap.EnvironmentProduction, cr.Status.AtProvider.Environment)
+	assert.Equal(t, corev1.ConditionTrue, cr.Status.GetCondition(v1beta1.TypeEnvironment).Status)
+
+	// A resource recorded in the sandbox is not reconciled against production
+	cr.Status.AtProvider.Environment = namecheap.EnvironmentSandbox
+	_, err = e.Observe(context.Background(), cr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), common.AnnotationKeyMigrateEnvironment)
+	cond := cr.Status.GetCondition(v1beta1.TypeEnvironment)
+	assert.Equal(t, corev1.ConditionFalse, cond.Status)
+	assert.Equal(t, v1beta1.ReasonEnvironmentMismatch, cond.Reason)
+	assert.Equal(t, namecheap.EnvironmentSandbox, cr.Status.AtProvider.Environment)
+
+	// Until it is explicitly migrated
+	cr.SetAnnotations(map[string]string{common.AnnotationKeyMigrateEnvironment: namecheap.EnvironmentProduction})
+	_, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, namecheap.EnvironmentProduction, cr.Status.AtProvider.Environment)
+	assert.Equal(t, corev1.ConditionTrue, cr.Status.GetCondition(v1beta1.TypeEnvironment).Status)
+}
+
+func TestDelete_DeletionBehavior(t *testing.T) {
+	tests := []struct {
+		name       string
+		behavior   *string
+		whoisGuard string
+		calls      []string
+		events     []event.Type
+	}{
+		{
+			name:  "OrphanByDefault",
+			calls: nil,
+		},
+		{
+			name:     "Orphan",
+			behavior: strPtr(v1beta1.DeletionBehaviorOrphan),
+			calls:    nil,
+		},
+		{
+			name:       "DisableRenewals",
+			behavior:   strPtr(v1beta1.DeletionBehaviorDisableRenewals),
+			whoisGuard: "ENABLED",
+			calls:      []string{"namecheap.whoisguard.disable"},
+			events:     []event.Type{event.TypeNormal, event.TypeWarning},
+		},
+		{
+			name:       "DisableRenewalsWhoisGuardAlreadyDisabled",
+			behavior:   strPtr(v1beta1.DeletionBehaviorDisableRenewals),
+			whoisGuard: "DISABLED",
+			calls:      nil,
+			events:     []event.Type{event.TypeWarning},
+		},
+		{
+			name:     "ReleaseDNS",
+			behavior: strPtr(v1beta1.DeletionBehaviorReleaseDNS),
+			calls:    []string{"namecheap.domains.dns.setDefault"},
+			events:   []event.Type{event.TypeNormal},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &fakeDomain{whoisGuardStatus: tc.whoisGuard}
+			e, rec, _ := newTestExternal(t, d)
+
+			cr := &v1beta1.Domain{}
+			cr.Spec.ForProvider.DomainName = "example.com"
+			cr.Spec.ForProvider.DeletionBehavior = tc.behavior
+
+			_, err := e.Delete(context.Background(), cr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.calls, d.calls)
+
+			var events []event.Type
+			for _, e := range rec.events {
+				assert.Equal(t, reasonDeletionBehavior, e.Reason)
+				events = append(events, e.Type)
+			}
+			assert.Equal(t, tc.events, events)
+		})
+	}
+}
+
+func TestObserve_RegistrationGracePeriod(t *testing.T) {
+	tests := []struct {
+		name       string
+		registered time.Duration
+		exists     bool
+		reason     xpv1.ConditionReason
+	}{
+		{
+			name:   "NeverRegistered",
+			exists: false,
+		},
+		{
+			name:       "RegisteredRecently",
+			registered: time.Minute,
+			exists:     true,
+			reason:     v1beta1.ReasonProvisioning,
+		},
+		{
+			name:       "RegisteredLongAgo",
+			registered: time.Hour,
+			exists:     false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &fakeDomain{missing: true}
+			e, _, _ := newTestExternal(t, d)
+			e.registrationGracePeriod = 5 * time.Minute
+
+			cr := &v1beta1.Domain{}
+			cr.Spec.ForProvider.DomainName = "example.com"
+			if tc.registered != 0 {
+				meta.SetExternalCreateSucceeded(cr, time.Now().Add(-tc.registered))
+			}
+
+			o, err := e.Observe(context.Background(), cr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exists, o.ResourceExists)
+			assert.Equal(t, tc.reason, cr.GetCondition(xpv1.TypeReady).Reason)
+		})
+	}
+}
+
+func TestObserve_RegistrationPropagates(t *testing.T) {
+	d := &fakeDomain{missing: true}
+	e, _, _ := newTestExternal(t, d)
+	e.registrationGracePeriod = 5 * time.Minute
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	meta.SetExternalCreateSucceeded(cr, time.Now())
+
+	// Not yet reported by Namecheap: still provisioning, so no new Create
+	o, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, o.ResourceExists)
+	assert.Equal(t, corev1.ConditionFalse, cr.GetCondition(xpv1.TypeReady).Status)
+	assert.Equal(t, v1beta1.ReasonProvisioning, cr.GetCondition(xpv1.TypeReady).Reason)
+
+	// The registration propagates
+	d.missing = false
+	o, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, o.ResourceExists)
+	assert.Equal(t, corev1.ConditionTrue, cr.GetCondition(xpv1.TypeReady).Status)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+// testContacts returns contacts with only the registrant set
+func testContacts() *v1beta1.DomainContacts {
+	return &v1beta1.DomainContacts{
+		Registrant: v1beta1.DomainContact{
+			FirstName:     "John",
+			LastName:      "Smith",
+			Address1:      "8939 S. Cross Blvd",
+			City:          "Phoenix",
+			StateProvince: "AZ",
+			PostalCode:    "85284",
+			Country:       "US",
+			Phone:         "+1.6613102107",
+			EmailAddress:  "john@example.com",
+		},
+	}
+}
+
+func TestCreate_Order(t *testing.T) {
+	tests := []struct {
+		name    string
+		missing bool
+		events  int
+	}{
+		{name: "DetailsRead"},
+		// The domain was registered and charged even though its details
+		// can't be read back yet, so the order is still recorded
+		{name: "DetailsUnavailable", missing: true, events: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &fakeDomain{missing: tc.missing}
+			e, rec, _ := newTestExternal(t, d)
+
+			cr := &v1beta1.Domain{}
+			cr.Spec.ForProvider.DomainName = "example.com"
+			cr.Spec.ForProvider.Contacts = testContacts()
+
+			_, err := e.Create(context.Background(), cr)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"namecheap.domains.create"}, d.calls)
+			assert.Equal(t, "example.com", meta.GetExternalName(cr))
+			assert.Equal(t, "125", cr.Status.AtProvider.ID)
+			assert.Equal(t, &v1beta1.DomainOrder{
+				Action:        v1beta1.DomainOrderCreate,
+				OrderID:       196074,
+				TransactionID: 380716,
+				ChargedAmount: "20.87",
+			}, cr.Status.AtProvider.LastOrder)
+
+			require.Len(t, rec.events, tc.events)
+			for _, e := range rec.events {
+				assert.Equal(t, event.TypeWarning, e.Type)
+				assert.Equal(t, reasonDomainOrder, e.Reason)
+			}
+		})
+	}
+}
+
+func TestCreate_InsufficientFunds(t *testing.T) {
+	balance := 5.0
+	d := &fakeDomain{availableBalance: &balance}
+	e, rec, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.SetGeneration(1)
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.Contacts = testContacts()
+
+	// The registration isn't ordered
+	_, err := e.Create(context.Background(), cr)
+	assert.True(t, namecheap.IsInsufficientFunds(err))
+	assert.Empty(t, d.calls)
+	cond := cr.Status.GetCondition(v1beta1.TypeFunds)
+	assert.Equal(t, v1beta1.ReasonInsufficientFunds, cond.Reason)
+	assert.Contains(t, cond.Message, "costs 11.16 USD but only 5.00 USD is available")
+	assert.Len(t, rec.withReason(common.ReasonInsufficientFunds), 1)
+
+	// Nor is the balance checked again for the same spec
+	balance = 50
+	_, err = e.Create(context.Background(), cr)
+	assert.ErrorContains(t, err, "change the spec to place the order again")
+	assert.Empty(t, d.calls)
+
+	// Changing the spec orders it
+	cr.SetGeneration(2)
+	_, err = e.Create(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"namecheap.domains.create"}, d.calls)
+	assert.Equal(t, v1beta1.ReasonFundsSufficient, cr.Status.GetCondition(v1beta1.TypeFunds).Reason)
+}
+
+func TestUpdate_RenewalInsufficientFunds(t *testing.T) {
+	balance := 5.0
+	d := &fakeDomain{availableBalance: &balance}
+	e, rec, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.SetGeneration(1)
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.RenewalYears = intPtr(1)
+
+	// A renewal the balance doesn't cover is a condition, not a reconcile
+	// error, and is requested again
+	_, err := e.Update(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, v1beta1.ReasonInsufficientFunds, cr.Status.GetCondition(v1beta1.TypeFunds).Reason)
+	assert.Len(t, rec.withReason(common.ReasonInsufficientFunds), 1)
+	assert.Equal(t, intPtr(1), cr.Spec.ForProvider.RenewalYears)
+	assert.Nil(t, cr.Status.AtProvider.LastOrder)
+
+	// It isn't attempted again for the same spec
+	_, err = e.Update(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Len(t, rec.withReason(common.ReasonInsufficientFunds), 1)
+	assert.Empty(t, d.calls)
+}
+
+func TestObserve_DNSSummary(t *testing.T) {
+	d := &fakeDomain{hostTypes: []string{"A", "A", "MX", "TXT"}}
+	e, _, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+
+	observe := func() {
+		t.Helper()
+		_, err := e.Observe(context.Background(), cr)
+		require.NoError(t, err)
+	}
+
+	// Off by default
+	observe()
+	assert.Nil(t, cr.Status.AtProvider.DNSSummary)
+
+	enabled := true
+	cr.Spec.ForProvider.ObserveDNSSummary = &enabled
+	observe()
+	assert.Equal(t, &v1beta1.DNSSummary{
+		RecordCount:   4,
+		RecordTypes:   map[string]int{"A": 2, "MX": 1, "TXT": 1},
+		IsUsingOurDNS: true,
+	}, cr.Status.AtProvider.DNSSummary)
+
+	// A domain delegated elsewhere has no Namecheap host records
+	d.notOurDNS = true
+	observe()
+	assert.Equal(t, &v1beta1.DNSSummary{}, cr.Status.AtProvider.DNSSummary)
+
+	// Turning the summary off clears it
+	enabled = false
+	observe()
+	assert.Nil(t, cr.Status.AtProvider.DNSSummary)
+}
+
+func TestDNSSummary(t *testing.T) {
+	tests := map[string]struct {
+		hosts *namecheap.DNSHosts
+		want  *v1beta1.DNSSummary
+	}{
+		"Empty": {
+			hosts: &namecheap.DNSHosts{IsUsingOurDNS: true},
+			want:  &v1beta1.DNSSummary{IsUsingOurDNS: true},
+		},
+		"CountsPerType": {
+			hosts: &namecheap.DNSHosts{
+				IsUsingOurDNS: true,
+				Records: []namecheap.DNSRecord{
+					{Type: "A"}, {Type: "a"}, {Type: "AAAA"}, {Type: "CNAME"}, {Type: "MX"}, {Type: "MX"},
+				},
+			},
+			want: &v1beta1.DNSSummary{
+				RecordCount:   6,
+				RecordTypes:   map[string]int{"A": 2, "AAAA": 1, "CNAME": 1, "MX": 2},
+				IsUsingOurDNS: true,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, dnsSummary(tc.hosts))
+		})
+	}
+}
+
+func TestObserve_FailedReadKeepsStatus(t *testing.T) {
+	d := &fakeDomain{nameservers: []string{"ns1.example.net"}}
+	e, _, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Status.AtProvider.LastOrder = &v1beta1.DomainOrder{Action: v1beta1.DomainOrderCreate, OrderID: 196074}
+
+	_, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, "125", cr.Status.AtProvider.ID)
+	assert.Equal(t, []string{"ns1.example.net"}, cr.Status.AtProvider.Nameservers)
+	// Fields set only on create survive observation
+	assert.Equal(t, 196074, cr.Status.AtProvider.LastOrder.OrderID)
+	observed := cr.Status.AtProvider.DeepCopy()
+
+	// The domain changes, but reading its registrar lock then fails: none
+	// of the changes read before the failure are recorded
+	d.nameservers = []string{"ns2.example.net"}
+	d.transferOutPending = true
+	d.lockUnavailable = true
+	_, err = e.Observe(context.Background(), cr)
+	require.Error(t, err)
+	assert.Equal(t, observed, &cr.Status.AtProvider)
+}
+
+func TestCreate_NameserversFail(t *testing.T) {
+	d := &fakeDomain{nameserversUnavailable: true}
+	e, rec, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.Nameservers = []string{"ns1.example.net", "ns2.example.net"}
+	cr.Spec.ForProvider.Contacts = testContacts()
+
+	// The registration succeeds even though the nameservers can't be set
+	_, err := e.Create(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", meta.GetExternalName(cr))
+	assert.Equal(t, "125", cr.Status.AtProvider.ID)
+	require.Len(t, rec.events, 1)
+	assert.Equal(t, event.TypeWarning, rec.events[0].Type)
+	assert.Equal(t, reasonPostRegistration, rec.events[0].Reason)
+
+	// The domain exists with the wrong nameservers, so the next reconcile
+	// updates rather than registers it again
+	obs, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, obs.ResourceExists)
+	assert.False(t, obs.ResourceUpToDate)
+
+	d.nameserversUnavailable = false
+	_, err = e.Update(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"namecheap.domains.create",
+		"namecheap.domains.dns.setCustom",
+		"namecheap.domains.dns.setCustom",
+	}, d.calls)
+}
+
+func TestCreateObserve_IDN(t *testing.T) {
+	d := &fakeDomain{}
+	e, _, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "bücher.example"
+	cr.Spec.ForProvider.IDNCode = strPtr("ger")
+	cr.Spec.ForProvider.Contacts = testContacts()
+
+	// The domain is registered and named in punycode
+	_, err := e.Create(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, "xn--bcher-kva.example", d.created.Get("DomainName"))
+	assert.Equal(t, "ger", d.created.Get("IdnCode"))
+	assert.Equal(t, "xn--bcher-kva.example", meta.GetExternalName(cr))
+
+	// and observed in punycode, so it is found
+	obs, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, obs.ResourceExists)
+	assert.Equal(t, "xn--bcher-kva.example", d.infoRequested)
+	assert.Equal(t, "xn--bcher-kva.example", meta.GetExternalName(cr))
+}
+
+func TestObserve_TLDSupport(t *testing.T) {
+	// co.uk domains can't be renewed through the API, locked or have their
+	// contacts changed in this fixture
+	tldCoUK := `<Tld Name="co.uk" IsApiRegisterable="true" IsApiRenewable="false" IsApiTransferable="true" IsEppRequired="false" ` +
+		`IsDisableModContact="true" SupportsRegistrarLock="false" WhoisVerification="true"/>`
+	// ai domains are registered and renewed for two years at a time
+	tldAI := `<Tld Name="ai" IsApiRegisterable="true" IsApiRenewable="true" IsApiTransferable="true" IsEppRequired="true" ` +
+		`IsDisableModContact="false" SupportsRegistrarLock="true" WhoisVerification="false" ` +
+		`MinRegisterYears="2" MaxRegisterYears="2" MinRenewYears="2" MaxRenewYears="2"/>`
+
+	tests := []struct {
+		name         string
+		domainName   string
+		missing      bool
+		capabilities *v1beta1.TLDCapabilities
+		status       corev1.ConditionStatus
+		message      string
+	}{
+		{
+			name:       "supported",
+			domainName: "example.com",
+			capabilities: &v1beta1.TLDCapabilities{
+				TLD: "com", APIRegisterable: true, APIRenewable: true, APITransferable: true,
+				EPPRequired: true, SupportsRegistrarLock: true, ContactsModifiable: true,
+			},
+			status: corev1.ConditionTrue,
+		},
+		{
+			name:       "unsupported",
+			domainName: "example.co.uk",
+			capabilities: &v1beta1.TLDCapabilities{
+				TLD: "co.uk", APIRegisterable: true, APITransferable: true, WhoisVerification: true,
+			},
+			status: corev1.ConditionFalse,
+			message: "spec.forProvider.renewalYears: .co.uk domains can't be renewed through the API; " +
+				"spec.forProvider.registrarLock: .co.uk domains don't support a registrar lock; " +
+				"spec.forProvider.contacts: the contacts of .co.uk domains can't be changed",
+		},
+		{
+			name:       "not registered",
+			domainName: "example.co.uk",
+			missing:    true,
+			capabilities: &v1beta1.TLDCapabilities{
+				TLD: "co.uk", APIRegisterable: true, APITransferable: true, WhoisVerification: true,
+			},
+			status: corev1.ConditionFalse,
+			// Contacts are set on registration
+			message: "spec.forProvider.renewalYears: .co.uk domains can't be renewed through the API; " +
+				"spec.forProvider.registrarLock: .co.uk domains don't support a registrar lock",
+		},
+		{
+			name:       "unsupported years",
+			domainName: "example.ai",
+			missing:    true,
+			capabilities: &v1beta1.TLDCapabilities{
+				TLD: "ai", APIRegisterable: true, APIRenewable: true, APITransferable: true,
+				EPPRequired: true, SupportsRegistrarLock: true, ContactsModifiable: true,
+				RegistrationYears: &v1beta1.YearRange{Min: 2, Max: 2},
+				RenewalYears:      &v1beta1.YearRange{Min: 2, Max: 2},
+			},
+			status: corev1.ConditionFalse,
+			message: "spec.forProvider.registrationYears: TLD .ai supports 2–2 year registration; " +
+				"spec.forProvider.renewalYears: TLD .ai supports 2–2 year renewal",
+		},
+		{
+			name:       "unlisted",
+			domainName: "example.xyz",
+			status:     corev1.ConditionFalse,
+			message:    "Namecheap doesn't list the domain's TLD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &fakeDomain{missing: tt.missing, registrarLock: true, tlds: tldCom + tldCoUK + tldAI}
+			e, _, _ := newTestExternal(t, d)
+
+			cr := &v1beta1.Domain{}
+			cr.Spec.ForProvider.DomainName = tt.domainName
+			cr.Spec.ForProvider.RenewalYears = intPtr(1)
+			cr.Spec.ForProvider.RegistrarLock = boolPtr(true)
+			cr.Spec.ForProvider.Contacts = testContacts()
+
+			_, err := e.Observe(context.Background(), cr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.capabilities, cr.Status.AtProvider.Capabilities)
+			condition := cr.Status.GetCondition(v1beta1.TypeTLDSupport)
+			assert.Equal(t, tt.status, condition.Status)
+			assert.Equal(t, tt.message, condition.Message)
+		})
+	}
+}
+
+func TestCreateUpdate_TLDYears(t *testing.T) {
+	tldAI := `<Tld Name="ai" IsApiRegisterable="true" IsApiRenewable="true" MinRegisterYears="2" MaxRegisterYears="2" ` +
+		`MinRenewYears="2" MaxRenewYears="10"/>`
+	d := &fakeDomain{tlds: tldAI}
+	e, _, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.ai"
+	cr.Spec.ForProvider.Contacts = testContacts()
+
+	// A registration period the TLD doesn't support is refused before the
+	// domain is ordered
+	_, err := e.Create(context.Background(), cr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TLD .ai supports 2–2 year registration")
+
+	cr.Spec.ForProvider.RenewalYears = intPtr(1)
+	_, err = e.Update(context.Background(), cr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TLD .ai supports 2–10 year renewal")
+	assert.Empty(t, d.calls)
+
+	cr.Spec.ForProvider.RegistrationYears = intPtr(2)
+	_, err = e.Create(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, "2", d.created.Get("Years"))
+}
+
+func TestCreate_InvalidNameservers(t *testing.T) {
+	d := &fakeDomain{}
+	e, _, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.Nameservers = []string{"dns1.registrar-servers.com", "ns1.example.net"}
+	cr.Spec.ForProvider.Contacts = testContacts()
+
+	// Invalid nameservers are rejected before the domain is registered
+	_, err := e.Create(context.Background(), cr)
+	require.Error(t, err)
+	assert.Empty(t, d.calls)
+}
+
+func TestCreate_Contacts(t *testing.T) {
+	d := &fakeDomain{}
+	e, _, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+
+	// A domain can't be registered without contacts
+	_, err := e.Create(context.Background(), cr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spec.forProvider.contacts is required")
+	assert.Empty(t, d.calls)
+
+	cr.Spec.ForProvider.Contacts = testContacts()
+	tech := cr.Spec.ForProvider.Contacts.Registrant
+	tech.FirstName = "Jane"
+	tech.EmailAddress = "jane@example.com"
+	tech.Fax = strPtr("+1.6613102108")
+	cr.Spec.ForProvider.Contacts.Tech = &tech
+
+	_, err = e.Create(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, "John", d.created.Get("RegistrantFirstName"))
+	assert.Equal(t, "Jane", d.created.Get("TechFirstName"))
+	assert.Equal(t, "jane@example.com", d.created.Get("TechEmailAddress"))
+	assert.Equal(t, "+1.6613102108", d.created.Get("TechFax"))
+
+	// Omitted admin and billing contacts default to the registrant
+	assert.Equal(t, "John", d.created.Get("AdminFirstName"))
+	assert.Equal(t, "john@example.com", d.created.Get("AuxBillingEmailAddress"))
+	assert.False(t, d.created.Has("RegistrantFax"))
+}
+
+func TestCreate_Privacy(t *testing.T) {
+	tests := []struct {
+		name    string
+		privacy *bool
+		want    string
+	}{
+		{name: "Unset", want: "no"},
+		{name: "Disabled", privacy: boolPtr(false), want: "no"},
+		{name: "Enabled", privacy: boolPtr(true), want: "yes"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &fakeDomain{whoisGuardStatus: "DISABLED"}
+			e, _, _ := newTestExternal(t, d)
+
+			cr := &v1beta1.Domain{}
+			cr.Spec.ForProvider.DomainName = "example.com"
+			cr.Spec.ForProvider.Contacts = testContacts()
+			cr.Spec.ForProvider.PrivacyProtection = tc.privacy
+
+			// Privacy is requested with the registration, so WhoisGuard
+			// doesn't have to be enabled afterwards
+			_, err := e.Create(context.Background(), cr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, d.created.Get("AddFreeWhoisguard"))
+			assert.Equal(t, tc.want, d.created.Get("WGEnabled"))
+			assert.Equal(t, []string{"namecheap.domains.create"}, d.calls)
+		})
+	}
+}
+
+func TestObserve_DriftSuspended(t *testing.T) {
+	d := &fakeDomain{nameservers: []string{"ns1.example.net", "ns2.example.net"}}
+	e, rec, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.Nameservers = []string{"ns1.example.org", "ns2.example.org"}
+
+	obs, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, obs.ResourceUpToDate)
+
+	// The nameservers' drift is left in place while suspended
+	cr.SetAnnotations(map[string]string{
+		common.AnnotationKeySuspendUntil: time.Now().Add(time.Hour).Format(time.RFC3339),
+	})
+	obs, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, obs.ResourceUpToDate)
+	events := rec.withReason(reasonDriftSuspended)
+	require.Len(t, events, 1)
+	assert.Equal(t, event.TypeNormal, events[0].Type)
+}
+
+func TestObserve_DriftEvents(t *testing.T) {
+	d := &fakeDomain{
+		nameservers:      []string{"ns1.example.net", "ns2.example.net"},
+		whoisGuardStatus: "DISABLED",
+	}
+	e, rec, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.SetUID("5d1e0b7a")
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.Nameservers = []string{"NS1.example.org.", "ns2.example.org"}
+	cr.Spec.ForProvider.PrivacyProtection = boolPtr(true)
+
+	_, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	var messages []string
+	for _, e := range rec.withReason(common.ReasonDriftDetected) {
+		assert.Equal(t, event.TypeWarning, e.Type)
+		messages = append(messages, e.Message)
+	}
+	assert.Equal(t, []string{
+		`spec.forProvider.privacyProtection drifted: expected "true", observed "false"; correcting it`,
+		`spec.forProvider.nameservers drifted: expected "ns1.example.org,ns2.example.org", observed "ns1.example.net,ns2.example.net"; correcting it`,
+	}, messages)
+
+	// Drift that persists isn't reported again within the sync interval
+	rec.events = nil
+	_, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Empty(t, rec.events)
+
+	// Observe-only domains report that their drift isn't corrected
+	other := cr.DeepCopy()
+	other.SetUID("9b4f2c61")
+	other.SetManagementPolicies(xpv1.ManagementPolicies{xpv1.ManagementActionObserve})
+	other.Spec.ForProvider.PrivacyProtection = nil
+	_, err = e.Observe(context.Background(), other)
+	require.NoError(t, err)
+	events := rec.withReason(common.ReasonDriftDetected)
+	require.Len(t, events, 1)
+	assert.Contains(t, events[0].Message, "not correcting it as the management policies don't allow updates")
+}
+
+func TestUpdate_PrivacyRetry(t *testing.T) {
+	d := &fakeDomain{whoisGuardStatus: "DISABLED", whoisGuardNotReady: 3}
+	e, rec, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.PrivacyProtection = boolPtr(true)
+
+	// The first attempt fails and schedules a retry
+	_, err := e.Update(context.Background(), cr)
+	require.NoError(t, err)
+
+	for attempt, backoff := range []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute} {
+		retry := cr.Status.AtProvider.PrivacyRetry
+		require.NotNil(t, retry, "attempt %d", attempt+1)
+		assert.Equal(t, attempt+1, retry.Attempts)
+		assert.WithinDuration(t, time.Now().Add(backoff), retry.NextAttemptTime.Time, 5*time.Second)
+		converging := cr.Status.GetCondition(v1beta1.TypeConverging)
+		assert.Equal(t, corev1.ConditionTrue, converging.Status)
+		assert.Equal(t, v1beta1.ReasonPrivacyPending, converging.Reason)
+
+		// The domain is up to date until the retry is due
+		e.privacyRetryOnly = false
+		o, err := e.Observe(context.Background(), cr)
+		require.NoError(t, err)
+		assert.True(t, o.ResourceUpToDate)
+
+		cr.Status.AtProvider.PrivacyRetry.NextAttemptTime.Time = time.Now().Add(-time.Second)
+		o, err = e.Observe(context.Background(), cr)
+		require.NoError(t, err)
+		assert.False(t, o.ResourceUpToDate)
+		assert.True(t, e.privacyRetryOnly)
+
+		_, err = e.Update(context.Background(), cr)
+		require.NoError(t, err)
+	}
+
+	// The fourth attempt succeeds, having retried only the privacy step
+	assert.Nil(t, cr.Status.AtProvider.PrivacyRetry)
+	assert.Equal(t, "ENABLED", d.whoisGuardStatus)
+	assert.Equal(t, []string{
+		"namecheap.whoisguard.enable",
+		"namecheap.whoisguard.enable",
+		"namecheap.whoisguard.enable",
+		"namecheap.whoisguard.enable",
+	}, d.calls)
+	converging := cr.Status.GetCondition(v1beta1.TypeConverging)
+	assert.Equal(t, corev1.ConditionFalse, converging.Status)
+	assert.Equal(t, v1beta1.ReasonConverged, converging.Reason)
+	require.Len(t, rec.events, 3)
+	for _, e := range rec.events {
+		assert.Equal(t, reasonPrivacyRetry, e.Reason)
+	}
+}
+
+func TestUpdate_PrivacyRetryExhausted(t *testing.T) {
+	d := &fakeDomain{whoisGuardStatus: "DISABLED", whoisGuardNotReady: maxPrivacyAttempts}
+	e, _, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.PrivacyProtection = boolPtr(true)
+
+	for range maxPrivacyAttempts - 1 {
+		_, err := e.Update(context.Background(), cr)
+		require.NoError(t, err)
+		require.NotNil(t, cr.Status.AtProvider.PrivacyRetry)
+	}
+
+	_, err := e.Update(context.Background(), cr)
+	require.Error(t, err)
+	assert.True(t, namecheap.IsWhoisGuardNotReady(err))
+	assert.Nil(t, cr.Status.AtProvider.PrivacyRetry)
+	converging := cr.Status.GetCondition(v1beta1.TypeConverging)
+	assert.Equal(t, corev1.ConditionFalse, converging.Status)
+	assert.Equal(t, v1beta1.ReasonPrivacyFailed, converging.Reason)
+}
+
+func TestObserve_WhoisGuard(t *testing.T) {
+	d := &fakeDomain{whoisGuardStatus: "ENABLED"}
+	e, _, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.PrivacyProtection = boolPtr(true)
+
+	_, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	obs := cr.Status.AtProvider
+	require.NotNil(t, obs.WhoisGuardID)
+	assert.Equal(t, 42, *obs.WhoisGuardID)
+	require.NotNil(t, obs.WhoisGuardStatus)
+	assert.Equal(t, "ENABLED", *obs.WhoisGuardStatus)
+	require.NotNil(t, obs.WhoisGuardExpirationDate)
+	assert.Equal(t, time.Date(2027, 3, 15, 0, 0, 0, 0, time.UTC), obs.WhoisGuardExpirationDate.Time)
+}
+
+func TestUpdate_WhoisGuardForwardEmail(t *testing.T) {
+	d := &fakeDomain{whoisGuardStatus: "ENABLED", whoisGuardForwardedTo: "old@example.org"}
+	e, rec, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.PrivacyProtection = boolPtr(true)
+	cr.Spec.ForProvider.WhoisGuardForwardEmail = strPtr("new@example.org")
+
+	o, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, o.ResourceUpToDate)
+
+	_, err = e.Update(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"namecheap.whoisguard.enable"}, d.calls)
+	assert.Equal(t, "new@example.org", d.whoisGuardForwardedTo)
+	require.Len(t, rec.withReason(reasonWhoisGuardForwardEmail), 1)
+
+	// The address is compared case-insensitively
+	cr.Spec.ForProvider.WhoisGuardForwardEmail = strPtr("New@Example.org")
+	o, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, o.ResourceUpToDate)
+}
+
+func TestObserve_WhoisGuardForwardEmailPrivacyDisabled(t *testing.T) {
+	// The forward email of a WhoisGuard the spec keeps disabled is not
+	// compared, and disabled WhoisGuard forwards nothing
+	d := &fakeDomain{whoisGuardStatus: "DISABLED"}
+	e, _, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.PrivacyProtection = boolPtr(false)
+	cr.Spec.ForProvider.WhoisGuardForwardEmail = strPtr("owner@example.org")
+
+	o, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, o.ResourceUpToDate)
+
+	_, err = e.Update(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Empty(t, d.calls)
+}
+
+func TestPrivacyRetryHook(t *testing.T) {
+	hook := privacyRetryHook(func(_ resource.Managed, interval time.Duration) time.Duration { return interval })
+
+	cr := &v1beta1.Domain{}
+	assert.Equal(t, 10*time.Minute, hook(cr, 10*time.Minute))
+
+	cr.Status.AtProvider.PrivacyRetry = &v1beta1.PrivacyRetry{
+		Attempts:        1,
+		NextAttemptTime: metav1.NewTime(time.Now().Add(time.Minute)),
+	}
+	assert.InDelta(t, time.Minute, hook(cr, 10*time.Minute), float64(5*time.Second))
+	assert.Equal(t, 30*time.Second, hook(cr, 30*time.Second))
+
+	// An overdue retry is observed promptly rather than never
+	cr.Status.AtProvider.PrivacyRetry.NextAttemptTime = metav1.NewTime(time.Now().Add(-time.Minute))
+	assert.Equal(t, time.Second, hook(cr, 10*time.Minute))
+}
+
+func TestObserve_Contacts(t *testing.T) {
+	// The registrant as Namecheap reports it, with padding and fields the
+	// spec doesn't set
+	registrant := namecheap.Contact{
+		FirstName:           "John",
+		LastName:            "Smith",
+		Address1:            "8939 S. Cross Blvd",
+		City:                "Phoenix",
+		StateProvince:       "AZ",
+		StateProvinceChoice: "A",
+		PostalCode:          "85284",
+		Country:             "US",
+		Phone:               "+1.6613102107",
+		PhoneExt:            " ",
+		EmailAddress:        "John@Example.com",
+	}
+
+	d := &fakeDomain{contacts: namecheap.DomainContacts{
+		Registrant: registrant,
+		Tech:       registrant,
+		Admin:      registrant,
+		AuxBilling: registrant,
+	}}
+	e, rec, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.Contacts = testContacts()
+
+	o, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, o.ResourceUpToDate)
+
+	// A changed registrant email address is drift
+	cr.Spec.ForProvider.Contacts.Registrant.EmailAddress = "compliance@example.com"
+	o, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, o.ResourceUpToDate)
+
+	_, err = e.Update(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"namecheap.domains.setContacts"}, d.calls)
+	assert.Equal(t, "compliance@example.com", d.contacts.Registrant.EmailAddress)
+	// Omitted roles follow the registrant
+	assert.Equal(t, "compliance@example.com", d.contacts.AuxBilling.EmailAddress)
+	require.Len(t, rec.withReason(reasonContactsUpdated), 1)
+
+	// Contacts aren't replaced when only other fields drift
+	d.calls = nil
+	d.contacts = domainContacts(cr.Spec.ForProvider.Contacts)
+	o, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, o.ResourceUpToDate)
+	_, err = e.Update(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Empty(t, d.calls)
+}
+
+func TestContactsDrift(t *testing.T) {
+	contacts := domainContacts(testContacts())
+
+	padded := contacts
+	padded.Tech.FirstName = " John "
+	padded.Admin.PhoneExt = " "
+	padded.AuxBilling.EmailAddress = "JOHN@example.com"
+	padded.Registrant.StateProvinceChoice = "A"
+	assert.Empty(t, contactsDrift(contacts, padded))
+
+	changed := contacts
+	changed.Admin.Address2 = "Suite 100"
+	assert.Equal(t, []common.Drift{{
+		Field:    "spec.forProvider.contacts.admin.address2",
+		Expected: "",
+		Observed: "Suite 100",
+	}}, contactsDrift(contacts, changed))
+}
+
+func TestObserve_RegistrarLock(t *testing.T) {
+	d := &fakeDomain{}
+	e, rec, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+
+	// The lock is reported but not enforced unless the spec sets it
+	o, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, o.ResourceUpToDate)
+	require.NotNil(t, cr.Status.AtProvider.IsLocked)
+	assert.False(t, *cr.Status.AtProvider.IsLocked)
+
+	cr.Spec.ForProvider.RegistrarLock = boolPtr(true)
+	o, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, o.ResourceUpToDate)
+
+	_, err = e.Update(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"namecheap.domains.setRegistrarLock"}, d.calls)
+	assert.True(t, d.registrarLock)
+	assert.True(t, *cr.Status.AtProvider.IsLocked)
+	assert.Len(t, rec.withReason(reasonRegistrarLock), 1)
+
+	// Once locked, the domain is up to date and isn't locked again
+	d.calls = nil
+	o, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, o.ResourceUpToDate)
+	assert.True(t, *cr.Status.AtProvider.IsLocked)
+	_, err = e.Update(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Empty(t, d.calls)
+}
+
+func TestObserve_Reactivate(t *testing.T) {
+	d := &fakeDomain{expired: true}
+	e, rec, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+
+	// An expired domain is reported but not reactivated unless requested
+	o, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, o.ResourceUpToDate)
+	require.NotNil(t, cr.Status.AtProvider.IsExpired)
+	assert.True(t, *cr.Status.AtProvider.IsExpired)
+
+	cr.Spec.ForProvider.ReactivateIfExpired = boolPtr(true)
+	cr.Spec.ForProvider.ReactivationPromoCode = strPtr("REACT10")
+	o, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, o.ResourceUpToDate)
+
+	_, err = e.Update(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"namecheap.domains.reactivate"}, d.calls)
+	assert.Equal(t, &v1beta1.DomainOrder{
+		Action:        v1beta1.DomainOrderReactivate,
+		OrderID:       23569,
+		TransactionID: 25080,
+		ChargedAmount: "650.00",
+	}, cr.Status.AtProvider.LastOrder)
+	assert.Equal(t, v1beta1.ReasonReactivated, cr.Status.GetCondition(v1beta1.TypeReactivation).Reason)
+	assert.Len(t, rec.withReason(reasonDomainOrder), 1)
+
+	// Once reactivated, the domain is up to date
+	d.calls = nil
+	o, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, o.ResourceUpToDate)
+	assert.False(t, *cr.Status.AtProvider.IsExpired)
+}
+
+func TestUpdate_ReactivationRefused(t *testing.T) {
+	d := &fakeDomain{expired: true, reactivationRefused: true}
+	e, rec, _ := newTestExternal(t, d)
+
+	cr := &v1beta1.Domain{}
+	cr.SetGeneration(1)
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.ReactivateIfExpired = boolPtr(true)
+
+	o, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, o.ResourceUpToDate)
+
+	// A refused reactivation is a condition, not a reconcile error
+	_, err = e.Update(context.Background(), cr)
+	require.NoError(t, err)
+	cond := cr.Status.GetCondition(v1beta1.TypeReactivation)
+	assert.Equal(t, v1beta1.ReasonReactivationFailed, cond.Reason)
+	assert.Contains(t, cond.Message, "2019166")
+	assert.Nil(t, cr.Status.AtProvider.LastOrder)
+	assert.Len(t, rec.withReason(reasonReactivation), 1)
+
+	// It isn't attempted again for the same spec
+	o, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, o.ResourceUpToDate)
+
+	// Changing the spec attempts it again
+	cr.SetGeneration(2)
+	o, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, o.ResourceUpToDate)
+	assert.Equal(t, []string{"namecheap.domains.reactivate"}, d.calls)
+}
+
+func TestUpdate_DefaultDNS(t *testing.T) {
+	custom := []string{"ns1.example.net", "ns2.example.net"}
+
+	tests := []struct {
+		name string
+		// setup brings the domain onto custom nameservers and the spec into
+		// the state tested
+		setup       func(t *testing.T, server *fakeserver.Server, reconcile func() managed.ExternalObservation, cr *v1beta1.Domain)
+		wantDefault bool
+	}{
+		{
+			name: "nameservers removed",
+			setup: func(t *testing.T, _ *fakeserver.Server, reconcile func() managed.ExternalObservation, cr *v1beta1.Domain) {
+				cr.Spec.ForProvider.Nameservers = custom
+				reconcile()
+				require.Equal(t, custom, cr.Status.AtProvider.AppliedNameservers)
+				cr.Spec.ForProvider.Nameservers = nil
+			},
+			wantDefault: true,
+		},
+		{
+			name: "nameservers removed with useDefaultDNS false",
+			setup: func(t *testing.T, _ *fakeserver.Server, reconcile func() managed.ExternalObservation, cr *v1beta1.Domain) {
+				cr.Spec.ForProvider.Nameservers = custom
+				reconcile()
+				cr.Spec.ForProvider.Nameservers = nil
+				cr.Spec.ForProvider.UseDefaultDNS = boolPtr(false)
+			},
+		},
+		{
+			name: "useDefaultDNS",
+			setup: func(t *testing.T, server *fakeserver.Server, _ func() managed.ExternalObservation, cr *v1beta1.Domain) {
+				require.NoError(t, server.Client().SetNameservers(context.Background(), "example.com", custom))
+				cr.Spec.ForProvider.UseDefaultDNS = boolPtr(true)
+			},
+			wantDefault: true,
+		},
+		{
+			name: "nameservers never managed",
+			setup: func(t *testing.T, server *fakeserver.Server, _ func() managed.ExternalObservation, _ *v1beta1.Domain) {
+				require.NoError(t, server.Client().SetNameservers(context.Background(), "example.com", custom))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := fakeserver.New(t)
+			server.AddDomain("example.com")
+			rec := &recorder{}
+			h := fakeserver.NewHarness(server, func(client *namecheap.Client) managed.ExternalClient {
+				return &external{client: client, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}
+			})
+
+			cr := &v1beta1.Domain{}
+			cr.SetName("example")
+			cr.Spec.ForProvider.DomainName = "example.com"
+			ctx := context.Background()
+			reconcile := func() managed.ExternalObservation {
+				t.Helper()
+				obs, err := h.Reconcile(ctx, cr)
+				require.NoError(t, err)
+				return obs
+			}
+
+			tc.setup(t, server, reconcile, cr)
+			setDefault := server.Calls(namecheap.CommandDomainsDNSSetDefault)
+
+			assert.Equal(t, !tc.wantDefault, reconcile().ResourceUpToDate)
+			obs := reconcile()
+			assert.True(t, obs.ResourceUpToDate)
+
+			require.NotNil(t, cr.Status.AtProvider.IsOurDNS)
+			if !tc.wantDefault {
+				assert.Equal(t, setDefault, server.Calls(namecheap.CommandDomainsDNSSetDefault))
+				assert.Equal(t, custom, server.Domain("example.com").Nameservers)
+				assert.False(t, *cr.Status.AtProvider.IsOurDNS)
+				return
+			}
+			assert.Equal(t, setDefault+1, server.Calls(namecheap.CommandDomainsDNSSetDefault))
+			assert.Empty(t, server.Domain("example.com").Nameservers)
+			assert.True(t, *cr.Status.AtProvider.IsOurDNS)
+			assert.Empty(t, cr.Status.AtProvider.AppliedNameservers)
+			assert.Len(t, rec.withReason(reasonDefaultNameservers), 1)
+		})
+	}
+}
@@ -0,0 +1,699 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/internal/controller/common"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap/fake"
+)
+
+// newFakeExternal returns an external client backed by api
+func newFakeExternal(api *fake.DomainAPI) (*external, *recorder) {
+	rec := &recorder{}
+	return &external{client: api, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}, rec
+}
+
+// fakeCom is a com TLD supporting everything for up to 10 years
+var fakeCom = &namecheap.TLD{
+	Name:                  "com",
+	MinRegisterYears:      1,
+	MaxRegisterYears:      10,
+	MinRenewYears:         1,
+	MaxRenewYears:         10,
+	IsApiRegisterable:     true,
+	IsApiRenewable:        true,
+	IsApiTransferable:     true,
+	SupportsRegistrarLock: true,
+}
+
+// fakeDomainAPI returns a fake of an existing, unlocked example.com using
+// Namecheap DNS
+func fakeDomainAPI() *fake.DomainAPI {
+	return &fake.DomainAPI{
+		MockGetTLDForDomain: func(context.Context, string) (*namecheap.TLD, error) {
+			return fakeCom, nil
+		},
+		MockDomainExists: func(context.Context, string) (bool, error) {
+			return true, nil
+		},
+		MockGetDomainInfo: func(_ context.Context, domainName string) (*namecheap.DomainInfo, error) {
+			info := &namecheap.DomainInfo{
+				Domain: namecheap.Domain{ID: 125, Name: domainName},
+				DNS: namecheap.DNSDetails{
+					ProviderType:  "FREE",
+					IsUsingOurDNS: true,
+					Nameservers:   []string{"dns1.registrar-servers.com", "dns2.registrar-servers.com"},
+				},
+			}
+			info.Expires.Time = time.Date(2027, 3, 15, 0, 0, 0, 0, time.UTC)
+			return info, nil
+		},
+		MockGetRegistrarLock: func(context.Context, string) (bool, error) {
+			return false, nil
+		},
+	}
+}
+
+func TestExternal_Observe(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*fake.DomainAPI, *v1beta1.Domain)
+		want     func(*testing.T, *v1beta1.Domain)
+		exists   bool
+		upToDate bool
+		err      string
+	}{
+		{
+			name: "NotFound",
+			modify: func(api *fake.DomainAPI, _ *v1beta1.Domain) {
+				api.MockDomainExists = func(context.Context, string) (bool, error) { return false, nil }
+			},
+		},
+		{
+			name: "NotInAccount",
+			modify: func(api *fake.DomainAPI, _ *v1beta1.Domain) {
+				api.MockGetDomainInfo = func(context.Context, string) (*namecheap.DomainInfo, error) {
+					return nil, namecheap.Error{Number: "2016166", Description: "Domain is not associated with your account"}
+				}
+			},
+		},
+		{
+			name:     "UpToDate",
+			exists:   true,
+			upToDate: true,
+			want: func(t *testing.T, cr *v1beta1.Domain) {
+				obs := cr.Status.AtProvider
+				assert.Equal(t, "125", obs.ID)
+				assert.Equal(t, namecheap.EnvironmentProduction, obs.Environment)
+				assert.Equal(t, time.Date(2027, 3, 15, 0, 0, 0, 0, time.UTC), obs.ExpirationDate.Time)
+				assert.Equal(t, boolPtr(false), obs.RegistrarLockEnabled)
+				assert.Equal(t, boolPtr(true), obs.IsOurDNS)
+				assert.Equal(t, "com", obs.Capabilities.TLD)
+				assert.Equal(t, "example.com", meta.GetExternalName(cr))
+				assert.Equal(t, corev1.ConditionTrue, cr.GetCondition(xpv1.TypeReady).Status)
+				assert.Equal(t, v1beta1.ReasonTLDSupported, cr.GetCondition(v1beta1.TypeTLDSupport).Reason)
+			},
+		},
+		{
+			name:   "RegistrarLockDrifted",
+			exists: true,
+			modify: func(_ *fake.DomainAPI, cr *v1beta1.Domain) {
+				cr.Spec.ForProvider.RegistrarLock = boolPtr(true)
+			},
+		},
+		{
+			name:   "NameserversDrifted",
+			exists: true,
+			modify: func(_ *fake.DomainAPI, cr *v1beta1.Domain) {
+				cr.Spec.ForProvider.Nameservers = []string{"ns1.example.net", "ns2.example.net"}
+			},
+		},
+		{
+			name: "EnvironmentMismatch",
+			modify: func(_ *fake.DomainAPI, cr *v1beta1.Domain) {
+				cr.Status.AtProvider.Environment = namecheap.EnvironmentSandbox
+			},
+			want: func(t *testing.T, cr *v1beta1.Domain) {
+				assert.Equal(t, v1beta1.ReasonEnvironmentMismatch, cr.GetCondition(v1beta1.TypeEnvironment).Reason)
+			},
+			err: "recorded in the Namecheap sandbox environment",
+		},
+		{
+			name: "InfoUnavailable",
+			modify: func(api *fake.DomainAPI, _ *v1beta1.Domain) {
+				api.MockGetDomainInfo = func(context.Context, string) (*namecheap.DomainInfo, error) {
+					return nil, errors.New("boom")
+				}
+			},
+			err: errGetDomain + ": boom",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			api := fakeDomainAPI()
+			cr := &v1beta1.Domain{}
+			cr.Spec.ForProvider.DomainName = "example.com"
+			if tc.modify != nil {
+				tc.modify(api, cr)
+			}
+			e, _ := newFakeExternal(api)
+
+			o, err := e.Observe(context.Background(), cr)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.exists, o.ResourceExists)
+				assert.Equal(t, tc.upToDate, o.ResourceUpToDate)
+			}
+			if tc.want != nil {
+				tc.want(t, cr)
+			}
+		})
+	}
+}
+
+func TestExternal_Observe_ContactsDrifted(t *testing.T) {
+	api := fakeDomainAPI()
+	api.MockGetDomainContacts = func(context.Context, string) (*namecheap.DomainContacts, error) {
+		contacts := domainContacts(testContacts())
+		return &contacts, nil
+	}
+	e, _ := newFakeExternal(api)
+
+	cr := &v1beta1.Domain{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.Contacts = testContacts()
+
+	_, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, e.contactsDrifted)
+
+	cr.Spec.ForProvider.Contacts.Registrant.City = "Los Angeles"
+	o, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, o.ResourceUpToDate)
+	assert.True(t, e.contactsDrifted)
+}
+
+func TestExternal_Create(t *testing.T) {
+	type order struct {
+		domainName string
+		years      int
+		contacts   namecheap.DomainContacts
+		opts       namecheap.DomainCreateOptions
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*fake.DomainAPI, *v1beta1.Domain)
+		order  *order
+		err    string
+		want   func(*testing.T, *v1beta1.Domain, *recorder)
+	}{
+		{
+			name:  "Registers",
+			order: &order{domainName: "example.com", years: 1, contacts: domainContacts(testContacts())},
+			want: func(t *testing.T, cr *v1beta1.Domain, _ *recorder) {
+				assert.Equal(t, "example.com", meta.GetExternalName(cr))
+				assert.Equal(t, "125", cr.Status.AtProvider.ID)
+				assert.Equal(t, &v1beta1.DomainOrder{
+					Action:        v1beta1.DomainOrderCreate,
+					OrderID:       196074,
+					TransactionID: 380716,
+					ChargedAmount: "20.87",
+				}, cr.Status.AtProvider.LastOrder)
+			},
+		},
+		{
+			name: "WithPrivacyAndNameservers",
+			modify: func(api *fake.DomainAPI, cr *v1beta1.Domain) {
+				cr.Spec.ForProvider.RegistrationYears = intPtr(2)
+				cr.Spec.ForProvider.PrivacyProtection = boolPtr(true)
+				cr.Spec.ForProvider.Nameservers = []string{"NS1.example.net", "ns2.example.net."}
+				api.MockGetWhoisGuardForDomain = func(context.Context, string) (*namecheap.WhoisGuard, error) {
+					return &namecheap.WhoisGuard{ID: 7, Status: "ENABLED"}, nil
+				}
+				api.MockSetNameservers = func(_ context.Context, _ string, nameservers []string) error {
+					assert.Equal(t, []string{"ns1.example.net", "ns2.example.net"}, nameservers)
+					return nil
+				}
+			},
+			order: &order{
+				domainName: "example.com",
+				years:      2,
+				contacts:   domainContacts(testContacts()),
+				opts:       namecheap.DomainCreateOptions{AddFreeWhoisguard: true, EnableWhoisguard: true},
+			},
+			want: func(t *testing.T, cr *v1beta1.Domain, rec *recorder) {
+				assert.Equal(t, []string{"ns1.example.net", "ns2.example.net"}, cr.Status.AtProvider.AppliedNameservers)
+				assert.Empty(t, rec.events)
+			},
+		},
+		{
+			name: "NoContacts",
+			modify: func(_ *fake.DomainAPI, cr *v1beta1.Domain) {
+				cr.Spec.ForProvider.Contacts = nil
+			},
+			err: errNoContacts,
+		},
+		{
+			name: "YearsUnsupported",
+			modify: func(_ *fake.DomainAPI, cr *v1beta1.Domain) {
+				cr.Spec.ForProvider.RegistrationYears = intPtr(11)
+			},
+			err: errCreateDomain,
+		},
+		{
+			name: "OrderFails",
+			modify: func(api *fake.DomainAPI, _ *v1beta1.Domain) {
+				api.MockCreateDomain = func(context.Context, string, int, namecheap.DomainContacts, namecheap.DomainCreateOptions) (*namecheap.DomainRegistration, error) {
+					return nil, errors.New("boom")
+				}
+			},
+			err: errCreateDomain + ": boom",
+			want: func(t *testing.T, cr *v1beta1.Domain, _ *recorder) {
+				assert.Empty(t, meta.GetExternalName(cr))
+				assert.Nil(t, cr.Status.AtProvider.LastOrder)
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			modify: func(api *fake.DomainAPI, _ *v1beta1.Domain) {
+				api.MockCreateDomain = func(context.Context, string, int, namecheap.DomainContacts, namecheap.DomainCreateOptions) (*namecheap.DomainRegistration, error) {
+					return nil, namecheap.ErrInsufficientFunds{Required: 11.16, Available: 5, Currency: "USD"}
+				}
+			},
+			err: "insufficient funds",
+			want: func(t *testing.T, cr *v1beta1.Domain, rec *recorder) {
+				assert.Equal(t, v1beta1.ReasonInsufficientFunds, cr.GetCondition(v1beta1.TypeFunds).Reason)
+				assert.Len(t, rec.withReason(common.ReasonInsufficientFunds), 1)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			api := fakeDomainAPI()
+			var ordered *order
+			api.MockCreateDomain = func(_ context.Context, domainName string, years int, contacts namecheap.DomainContacts, opts namecheap.DomainCreateOptions) (*namecheap.DomainRegistration, error) {
+				ordered = &order{domainName: domainName, years: years, contacts: contacts, opts: opts}
+				return &namecheap.DomainRegistration{
+					DomainName:    domainName,
+					Registered:    true,
+					ChargedAmount: 20.87,
+					DomainID:      125,
+					OrderID:       196074,
+					TransactionID: 380716,
+				}, nil
+			}
+
+			cr := &v1beta1.Domain{}
+			cr.Spec.ForProvider.DomainName = "example.com"
+			cr.Spec.ForProvider.Contacts = testContacts()
+			if tc.modify != nil {
+				tc.modify(api, cr)
+			}
+			e, rec := newFakeExternal(api)
+
+			_, err := e.Create(context.Background(), cr)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.order, ordered)
+			if tc.want != nil {
+				tc.want(t, cr, rec)
+			}
+		})
+	}
+}
+
+func TestExternal_Update(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*fake.DomainAPI, *external, *v1beta1.Domain, *[]string)
+		calls  []string
+		err    string
+		want   func(*testing.T, *v1beta1.Domain)
+	}{
+		{
+			name: "NothingToDo",
+		},
+		{
+			name: "Renews",
+			modify: func(api *fake.DomainAPI, _ *external, cr *v1beta1.Domain, calls *[]string) {
+				cr.SetGeneration(3)
+				cr.Spec.ForProvider.RenewalYears = intPtr(2)
+				api.MockRenewDomain = func(_ context.Context, domainName string, years int) (*namecheap.DomainRenewal, error) {
+					*calls = append(*calls, "RenewDomain")
+					assert.Equal(t, 2, years)
+					return &namecheap.DomainRenewal{DomainName: domainName, Renewed: true, ChargedAmount: 24.96, OrderID: 1, TransactionID: 2}, nil
+				}
+			},
+			calls: []string{"RenewDomain"},
+			want: func(t *testing.T, cr *v1beta1.Domain) {
+				assert.Equal(t, &v1beta1.DomainOrder{
+					Action:        v1beta1.DomainOrderRenew,
+					OrderID:       1,
+					TransactionID: 2,
+					ChargedAmount: "24.96",
+					Generation:    3,
+				}, cr.Status.AtProvider.LastOrder)
+			},
+		},
+		{
+			name: "AlreadyRenewedForGeneration",
+			modify: func(api *fake.DomainAPI, _ *external, cr *v1beta1.Domain, calls *[]string) {
+				cr.SetGeneration(3)
+				cr.Spec.ForProvider.RenewalYears = intPtr(2)
+				cr.Status.AtProvider.LastOrder = &v1beta1.DomainOrder{Action: v1beta1.DomainOrderRenew, OrderID: 1, Generation: 3}
+				api.MockRenewDomain = func(context.Context, string, int) (*namecheap.DomainRenewal, error) {
+					*calls = append(*calls, "RenewDomain")
+					return nil, errors.New("renewed twice")
+				}
+			},
+		},
+		{
+			name: "RenewalFails",
+			modify: func(api *fake.DomainAPI, _ *external, cr *v1beta1.Domain, _ *[]string) {
+				cr.Spec.ForProvider.RenewalYears = intPtr(1)
+				api.MockRenewDomain = func(context.Context, string, int) (*namecheap.DomainRenewal, error) {
+					return nil, errors.New("boom")
+				}
+			},
+			err: errRenewDomain + ": boom",
+		},
+		{
+			name: "RegistrarLock",
+			modify: func(api *fake.DomainAPI, _ *external, cr *v1beta1.Domain, calls *[]string) {
+				cr.Spec.ForProvider.RegistrarLock = boolPtr(true)
+				cr.Status.AtProvider.IsLocked = boolPtr(false)
+				api.MockSetRegistrarLock = func(_ context.Context, _ string, locked bool) error {
+					*calls = append(*calls, "SetRegistrarLock")
+					assert.True(t, locked)
+					return nil
+				}
+			},
+			calls: []string{"SetRegistrarLock"},
+			want: func(t *testing.T, cr *v1beta1.Domain) {
+				assert.Equal(t, boolPtr(true), cr.Status.AtProvider.RegistrarLockEnabled)
+			},
+		},
+		{
+			name: "DefaultDNS",
+			modify: func(api *fake.DomainAPI, e *external, cr *v1beta1.Domain, calls *[]string) {
+				e.defaultDNS = true
+				cr.Status.AtProvider.AppliedNameservers = []string{"ns1.example.net"}
+				api.MockSetDefaultNameservers = func(context.Context, string) error {
+					*calls = append(*calls, "SetDefaultNameservers")
+					return nil
+				}
+			},
+			calls: []string{"SetDefaultNameservers"},
+			want: func(t *testing.T, cr *v1beta1.Domain) {
+				assert.Nil(t, cr.Status.AtProvider.AppliedNameservers)
+			},
+		},
+		{
+			name: "Contacts",
+			modify: func(api *fake.DomainAPI, e *external, cr *v1beta1.Domain, calls *[]string) {
+				e.contactsDrifted = true
+				cr.Spec.ForProvider.Contacts = testContacts()
+				api.MockSetDomainContacts = func(_ context.Context, _ string, contacts namecheap.DomainContacts) error {
+					*calls = append(*calls, "SetDomainContacts")
+					assert.Equal(t, domainContacts(testContacts()), contacts)
+					return nil
+				}
+			},
+			calls: []string{"SetDomainContacts"},
+		},
+		{
+			name: "Reactivates",
+			modify: func(api *fake.DomainAPI, e *external, cr *v1beta1.Domain, calls *[]string) {
+				e.reactivate = true
+				cr.Spec.ForProvider.RegistrarLock = boolPtr(true)
+				api.MockReactivateDomain = func(context.Context, string, string) (*namecheap.DomainReactivation, error) {
+					*calls = append(*calls, "ReactivateDomain")
+					return &namecheap.DomainReactivation{DomainName: "example.com", ChargedAmount: 650, OrderID: 3, TransactionID: 4}, nil
+				}
+			},
+			// Nothing else is changed until the domain is reactivated
+			calls: []string{"ReactivateDomain"},
+			want: func(t *testing.T, cr *v1beta1.Domain) {
+				assert.Equal(t, boolPtr(false), cr.Status.AtProvider.IsExpired)
+				assert.Equal(t, v1beta1.DomainOrderReactivate, cr.Status.AtProvider.LastOrder.Action)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			api := fakeDomainAPI()
+			cr := &v1beta1.Domain{}
+			cr.Spec.ForProvider.DomainName = "example.com"
+			e, _ := newFakeExternal(api)
+			var calls []string
+			if tc.modify != nil {
+				tc.modify(api, e, cr, &calls)
+			}
+
+			_, err := e.Update(context.Background(), cr)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.calls, calls)
+			if tc.want != nil {
+				tc.want(t, cr)
+			}
+		})
+	}
+}
+
+// fakeManager provides the client and scheme a managed reconciler needs
+type fakeManager struct {
+	manager.Manager
+	client client.Client
+}
+
+func (m *fakeManager) GetClient() client.Client {
+	return m.client
+}
+
+func (m *fakeManager) GetScheme() *runtime.Scheme {
+	return m.client.Scheme()
+}
+
+// TestReconcile_RenewsOnce runs a drifting Domain that requests a renewal
+// through the managed reconciler, which saves only the status Update sets
+func TestReconcile_RenewsOnce(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	cr := &v1beta1.Domain{ObjectMeta: metav1.ObjectMeta{Name: "example.com", Namespace: "default", Generation: 1}}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.RenewalYears = intPtr(1)
+	cr.Spec.ForProvider.RegistrarLock = boolPtr(true)
+	kube := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(cr).WithStatusSubresource(cr).Build()
+
+	// The lock never sticks, so every reconcile finds the domain drifted
+	renewals := 0
+	api := fakeDomainAPI()
+	api.MockSetRegistrarLock = func(context.Context, string, bool) error { return nil }
+	api.MockRenewDomain = func(_ context.Context, domainName string, years int) (*namecheap.DomainRenewal, error) {
+		renewals++
+		return &namecheap.DomainRenewal{DomainName: domainName, Renewed: true, ChargedAmount: 12.48, OrderID: 1, TransactionID: 2}, nil
+	}
+	connector := managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+		e, _ := newFakeExternal(api)
+		return e, nil
+	})
+	r := managed.NewReconciler(&fakeManager{client: kube},
+		resource.ManagedKind(v1beta1.DomainGroupVersionKind),
+		managed.WithExternalConnector(connector))
+
+	for range 2 {
+		_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, renewals)
+
+	got := &v1beta1.Domain{}
+	require.NoError(t, kube.Get(context.Background(), client.ObjectKeyFromObject(cr), got))
+	require.NotNil(t, got.Status.AtProvider.LastOrder)
+	assert.Equal(t, v1beta1.DomainOrderRenew, got.Status.AtProvider.LastOrder.Action)
+	assert.Equal(t, int64(1), got.Status.AtProvider.LastOrder.Generation)
+}
+
+// TestReconcile_InsufficientFunds runs a Domain whose registration the
+// balance doesn't cover through the managed reconciler, which discards the
+// status of a failed Create
+func TestReconcile_InsufficientFunds(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	cr := &v1beta1.Domain{ObjectMeta: metav1.ObjectMeta{Name: "example.com", Namespace: "default", Generation: 1}}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.Contacts = testContacts()
+	kube := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(cr).WithStatusSubresource(cr).Build()
+
+	orders := 0
+	api := fakeDomainAPI()
+	api.MockDomainExists = func(context.Context, string) (bool, error) {
+		return false, nil
+	}
+	api.MockCreateDomain = func(context.Context, string, int, namecheap.DomainContacts, namecheap.DomainCreateOptions) (*namecheap.DomainRegistration, error) {
+		orders++
+		return nil, namecheap.ErrInsufficientFunds{Required: 11.16, Available: 5, Currency: "USD"}
+	}
+	rec := &recorder{}
+	connector := managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+		return &external{client: api, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}, nil
+	})
+	r := managed.NewReconciler(&fakeManager{client: kube},
+		resource.ManagedKind(v1beta1.DomainGroupVersionKind),
+		managed.WithExternalConnector(connector))
+
+	reconcile := func() {
+		t.Helper()
+		_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+		require.NoError(t, err)
+	}
+
+	// The registration is refused once, then not ordered again for the
+	// same spec
+	for range 3 {
+		reconcile()
+	}
+	assert.Equal(t, 1, orders)
+	assert.Len(t, rec.withReason(common.ReasonInsufficientFunds), 1)
+
+	got := &v1beta1.Domain{}
+	require.NoError(t, kube.Get(context.Background(), client.ObjectKeyFromObject(cr), got))
+	assert.Contains(t, got.GetCondition(xpv1.TypeSynced).Message, "change the spec to place the order again")
+
+	// Changing the spec orders it again
+	got.Spec.ForProvider.RegistrationYears = intPtr(2)
+	got.SetGeneration(2)
+	require.NoError(t, kube.Update(context.Background(), got))
+	reconcile()
+	assert.Equal(t, 2, orders)
+}
+
+// TestReconcile_Delete runs a deleted Domain through the managed reconciler,
+// which only removes the finalizer once the domain is reported gone
+func TestReconcile_Create(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	cr := &v1beta1.Domain{ObjectMeta: metav1.ObjectMeta{Name: "example.com", Namespace: "default", Generation: 1}}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.Contacts = testContacts()
+	cr.Spec.ForProvider.Nameservers = []string{"ns1.example.net", "ns2.example.net"}
+	kube := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(cr).WithStatusSubresource(cr).Build()
+
+	registered := false
+	api := fakeDomainAPI()
+	api.MockDomainExists = func(context.Context, string) (bool, error) {
+		return registered, nil
+	}
+	api.MockCreateDomain = func(_ context.Context, domainName string, _ int, _ namecheap.DomainContacts, _ namecheap.DomainCreateOptions) (*namecheap.DomainRegistration, error) {
+		registered = true
+		return &namecheap.DomainRegistration{DomainName: domainName, Registered: true, ChargedAmount: 20.87, DomainID: 125, OrderID: 196074, TransactionID: 380716}, nil
+	}
+	api.MockSetNameservers = func(context.Context, string, []string) error { return nil }
+	api.MockGetDomainContacts = func(context.Context, string) (*namecheap.DomainContacts, error) {
+		contacts := domainContacts(testContacts())
+		return &contacts, nil
+	}
+	getInfo := api.MockGetDomainInfo
+	api.MockGetDomainInfo = func(ctx context.Context, domainName string) (*namecheap.DomainInfo, error) {
+		info, err := getInfo(ctx, domainName)
+		info.DNS = namecheap.DNSDetails{ProviderType: "CUSTOM", Nameservers: []string{"ns1.example.net", "ns2.example.net"}}
+		return info, err
+	}
+	connector := managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+		e, _ := newFakeExternal(api)
+		return e, nil
+	})
+	r := managed.NewReconciler(&fakeManager{client: kube},
+		resource.ManagedKind(v1beta1.DomainGroupVersionKind),
+		managed.WithExternalConnector(connector))
+
+	// The first reconcile registers the domain, the second observes it
+	for range 2 {
+		_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+		require.NoError(t, err)
+	}
+
+	got := &v1beta1.Domain{}
+	require.NoError(t, kube.Get(context.Background(), client.ObjectKeyFromObject(cr), got))
+	assert.Equal(t, &v1beta1.DomainOrder{
+		Action:        v1beta1.DomainOrderCreate,
+		OrderID:       196074,
+		TransactionID: 380716,
+		ChargedAmount: "20.87",
+		Generation:    1,
+	}, got.Status.AtProvider.LastOrder)
+	assert.Equal(t, []string{"ns1.example.net", "ns2.example.net"}, got.Status.AtProvider.AppliedNameservers)
+}
+
+func TestReconcile_Delete(t *testing.T) {
+	tests := []struct {
+		name      string
+		failFirst bool
+		calls     int
+	}{
+		{name: "Deleted", calls: 1},
+		{name: "RetriedAfterFailure", failFirst: true, calls: 2},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, v1beta1.AddToScheme(scheme))
+
+			now := metav1.Now()
+			cr := &v1beta1.Domain{ObjectMeta: metav1.ObjectMeta{
+				Name:              "example.com",
+				Namespace:         "default",
+				DeletionTimestamp: &now,
+				Finalizers:        []string{"finalizer.managedresource.crossplane.io"},
+			}}
+			cr.Spec.ForProvider.DomainName = "example.com"
+			cr.Spec.ForProvider.DeletionBehavior = strPtr(v1beta1.DeletionBehaviorReleaseDNS)
+			kube := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(cr).WithStatusSubresource(cr).Build()
+
+			calls := 0
+			api := fakeDomainAPI()
+			api.MockSetDefaultNameservers = func(context.Context, string) error {
+				calls++
+				if tc.failFirst && calls == 1 {
+					return errors.New("boom")
+				}
+				return nil
+			}
+			connector := managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+				e, _ := newFakeExternal(api)
+				return e, nil
+			})
+			r := managed.NewReconciler(&fakeManager{client: kube},
+				resource.ManagedKind(v1beta1.DomainGroupVersionKind),
+				managed.WithExternalConnector(connector))
+
+			// Each reconcile either deletes or finds the domain gone
+			for range 3 {
+				_, _ = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+			}
+			assert.Equal(t, tc.calls, calls)
+
+			err := kube.Get(context.Background(), client.ObjectKeyFromObject(cr), &v1beta1.Domain{})
+			assert.True(t, kerrors.IsNotFound(err), "the finalizer should be removed once the domain is gone")
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/internal/controller/common"
+	"github.com/rossigee/provider-namecheap/internal/fakeserver"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+func TestIdempotency_Restart(t *testing.T) {
+	tests := []struct {
+		name string
+		// lose simulates the status lost while the provider was down, as
+		// when a resource is restored from a backup
+		lose func(cr *v1beta1.Domain)
+	}{
+		{name: "StatusKept", lose: func(*v1beta1.Domain) {}},
+		{name: "StatusLost", lose: func(cr *v1beta1.Domain) { cr.Status = v1beta1.DomainStatus{} }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := fakeserver.New(t)
+			h := fakeserver.NewHarness(server, func(client *namecheap.Client) managed.ExternalClient {
+				rec := &recorder{}
+				return &external{client: client, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}
+			})
+
+			cr := &v1beta1.Domain{}
+			cr.SetName("example")
+			cr.Spec.ForProvider.DomainName = "example.com"
+			cr.Spec.ForProvider.RegistrarLock = boolPtr(true)
+			cr.Spec.ForProvider.PrivacyProtection = boolPtr(true)
+			cr.Spec.ForProvider.Nameservers = []string{"ns1.example.net", "ns2.example.net"}
+			cr.Spec.ForProvider.Contacts = testContacts()
+
+			ctx := context.Background()
+			reconcile := func() managed.ExternalObservation {
+				t.Helper()
+				obs, err := h.Reconcile(ctx, cr)
+				require.NoError(t, err)
+				return obs
+			}
+
+			// Register, then converge the settings Create left behind
+			reconcile()
+			require.Equal(t, 1, server.BillableCalls())
+			reconcile()
+			require.True(t, reconcile().ResourceUpToDate)
+
+			writes := map[namecheap.Command]int{}
+			for _, command := range []namecheap.Command{
+				namecheap.CommandDomainsDNSSetCustom,
+				namecheap.CommandDomainsSetRegistrarLock,
+				namecheap.CommandDomainsSetContacts,
+				namecheap.CommandWhoisGuardEnable,
+			} {
+				writes[command] = server.Calls(command)
+			}
+
+			tc.lose(cr)
+			h.Restart()
+
+			for range 3 {
+				obs := reconcile()
+				assert.True(t, obs.ResourceExists)
+				assert.True(t, obs.ResourceUpToDate)
+			}
+
+			assert.Equal(t, 1, server.BillableCalls(), "the domain should be registered once")
+			assert.Equal(t, 1, server.Calls(namecheap.CommandDomainsCreate))
+			for command, calls := range writes {
+				assert.Equal(t, calls, server.Calls(command), "%s should not be sent again after the restart", command)
+			}
+
+			d := server.Domain("example.com")
+			require.NotNil(t, d)
+			assert.Equal(t, "example.com", meta.GetExternalName(cr))
+			assert.Equal(t, strconv.Itoa(d.ID), cr.Status.AtProvider.ID)
+			assert.True(t, d.Locked)
+			assert.Equal(t, "ENABLED", d.WhoisGuardStatus)
+			assert.Equal(t, []string{"ns1.example.net", "ns2.example.net"}, d.Nameservers)
+			assert.Equal(t, corev1.ConditionTrue, cr.GetCondition(xpv1.TypeReady).Status)
+			require.NotNil(t, cr.Status.AtProvider.IsLocked)
+			assert.True(t, *cr.Status.AtProvider.IsLocked)
+			assert.Equal(t, d.Nameservers, cr.Status.AtProvider.Nameservers)
+		})
+	}
+}
@@ -0,0 +1,406 @@
+package domaintransfer
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/internal/controller/common"
+	"github.com/rossigee/provider-namecheap/internal/controller/pollinterval"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+const (
+	errNotDomainTransfer = "managed resource is not a DomainTransfer custom resource"
+	errTrackPCUsage      = "cannot track ProviderConfig usage"
+	errGetPC             = "cannot get ProviderConfig"
+	errGetCreds          = "cannot get credentials"
+
+	errGetEPPCode       = "cannot get EPP code"
+	errCreateTransfer   = "cannot order domain transfer"
+	errGetTransfer      = "cannot get domain transfer status"
+	errResubmitTransfer = "cannot resubmit domain transfer"
+
+	reasonTransferFailed    event.Reason = "TransferFailed"
+	reasonTransferCompleted event.Reason = "TransferCompleted"
+	reasonTransferResubmit  event.Reason = "TransferResubmitted"
+	reasonDeletionBehavior  event.Reason = "DeletionBehavior"
+	reasonTransferOrder     event.Reason = "TransferOrder"
+)
+
+// annotationKeyTransferOrder records the transfer order placed by Create,
+// whose status the managed reconciler discards
+const annotationKeyTransferOrder = "namecheap.m.crossplane.io/transfer-order"
+
+// transferOrder is the record of a transfer order
+type transferOrder struct {
+	OrderID              int    `json:"orderID,omitempty"`
+	TransactionID        int    `json:"transactionID,omitempty"`
+	ChargedAmount        string `json:"chargedAmount,omitempty"`
+	EPPCodeSecretVersion string `json:"eppCodeSecretVersion,omitempty"`
+}
+
+// Setup adds a controller that reconciles DomainTransfer managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1beta1.DomainTransferGroupKind)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name)) //nolint:staticcheck // SA1019: required for v2 API compatibility
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1beta1.DomainTransferGroupVersionKind),
+		managed.WithExternalConnector(&connector{
+			kube:     mgr.GetClient(),
+			usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
+			recorder: recorder,
+			log:      o.Logger.WithValues("controller", name),
+		}),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithPollIntervalHook(pollinterval.NewHook(mgr.GetCache(), o.Logger.WithValues("controller", name))),
+		managed.WithRecorder(recorder),
+		common.WithManagementPolicies(o))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&v1beta1.DomainTransfer{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube     client.Client
+	usage    *resource.ProviderConfigUsageTracker
+	recorder event.Recorder
+	log      logging.Logger
+}
+
+// Connect typically produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1beta1.DomainTransfer)
+	if !ok {
+		return nil, errors.New(errNotDomainTransfer)
+	}
+
+	if err := c.usage.Track(ctx, cr); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &v1beta1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	cd := pc.Spec.Credentials
+	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
+	common.RecordCredentialsRevision(ctx, c.kube, c.log, pc, namecheap.CredentialsRevision(data))
+
+	// Parse credentials from the secret data
+	creds, err := namecheap.ParseCredentials(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse credentials JSON")
+	}
+
+	// Create Namecheap client
+	config := namecheap.Config{
+		APIUser:   creds.APIUser,
+		APIKey:    creds.APIKey,
+		Username:  creds.Username,
+		ClientIP:  creds.ClientIP,
+		ClientIPs: creds.ClientIPs,
+		Sandbox:   pc.Spec.SandboxMode != nil && *pc.Spec.SandboxMode,
+	}
+
+	if pc.Spec.APIBase != nil {
+		config.BaseURL = *pc.Spec.APIBase
+	}
+
+	client := namecheap.NewClient(config)
+	common.Clients.Track(pc.GetName(), client)
+
+	return &external{client: client, kube: c.kube, recorder: c.recorder}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	client   namecheap.TransferAPI
+	kube     client.Client
+	recorder event.Recorder
+
+	// resubmit is set by Observe when the transfer stalled on an invalid EPP
+	// code and the EPP code Secret changed since it was last submitted, so
+	// that Update resubmits it
+	resubmit bool
+}
+
+// Disconnect cleans up any resources created by Connect.
+func (c *external) Disconnect(ctx context.Context) error {
+	// No cleanup needed for HTTP client
+	return nil
+}
+
+// observedTransferID returns the ID of the transfer ordered for cr. The
+// status records it, but the status can be lost, e.g. when the resource is
+// restored from a backup, and ordering the transfer again would charge for
+// it twice. The external name set on create then still holds the ID. Until
+// then the external name defaults to the resource's name, which is never
+// taken for an ID.
+func observedTransferID(cr *v1beta1.DomainTransfer) (int, bool) {
+	if id := cr.Status.AtProvider.TransferID; id != nil {
+		return *id, true
+	}
+
+	externalName := meta.GetExternalName(cr)
+	if externalName == cr.GetName() {
+		return 0, false
+	}
+	id, err := strconv.Atoi(externalName)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// eppCode returns the EPP code held by the Secret the spec selects, and the
+// Secret's resource version
+func (c *external) eppCode(ctx context.Context, cr *v1beta1.DomainTransfer) (string, string, error) {
+	ref := cr.Spec.ForProvider.EPPCodeSecretRef
+	secret := &corev1.Secret{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: cr.GetNamespace(), Name: ref.Name}, secret); err != nil {
+		return "", "", errors.Wrap(err, errGetEPPCode)
+	}
+
+	code := strings.TrimSpace(string(secret.Data[ref.Key]))
+	if code == "" {
+		return "", "", errors.Errorf("%s: key %s of Secret %s is empty or missing", errGetEPPCode, ref.Key, ref.Name)
+	}
+	return code, secret.ResourceVersion, nil
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1beta1.DomainTransfer)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotDomainTransfer)
+	}
+	ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.DomainTransferKind)
+
+	// If we don't have a transfer ID, the transfer hasn't been ordered yet
+	transferID, ok := observedTransferID(cr)
+	if !ok {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	// Transfers can't be deleted, so a deleted resource is gone once Delete
+	// ran. The conditions set below would replace the Deleting reason that
+	// records it.
+	if meta.WasDeleted(cr) && cr.GetCondition(xpv1.TypeReady).Reason == xpv1.ReasonDeleting {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	// Refuse to reconcile against a different Namecheap environment than
+	// the one the resource was recorded in
+	environment := c.client.Environment()
+	if err := common.CheckEnvironment(cr, cr.Status.AtProvider.Environment, environment); err != nil {
+		cr.SetConditions(v1beta1.EnvironmentMismatch(err.Error()))
+		return managed.ExternalObservation{}, err
+	}
+
+	status, err := c.client.GetTransferStatus(ctx, transferID)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetTransfer)
+	}
+
+	// Build the observation on a copy of the current one, which preserves
+	// the fields only set on create or update
+	phase := status.Phase()
+	previous := namecheap.TransferPhase(cr.Status.AtProvider.Phase)
+	statusCode := int(status.StatusID)
+	obs := cr.Status.AtProvider.DeepCopy()
+	obs.Environment = environment
+	obs.TransferID = &transferID
+	obs.StatusCode = &statusCode
+	obs.StatusDescription = status.Status
+	obs.Phase = string(phase)
+
+	// Recover the order from its record, as the managed reconciler discards
+	// the status Create sets. The EPP code Secret version the status holds
+	// is newer than the recorded one once the transfer was resubmitted.
+	order := transferOrder{}
+	if ok, err := common.GetRecord(cr, annotationKeyTransferOrder, &order); err != nil {
+		c.recorder.Event(cr, event.Warning(reasonTransferOrder, err))
+	} else if ok {
+		if obs.OrderID == 0 {
+			obs.OrderID = order.OrderID
+			obs.TransactionID = order.TransactionID
+			obs.ChargedAmount = order.ChargedAmount
+		}
+		if obs.EPPCodeSecretVersion == "" {
+			obs.EPPCodeSecretVersion = order.EPPCodeSecretVersion
+		}
+	}
+
+	cr.Status.AtProvider = *obs
+	cr.SetConditions(v1beta1.EnvironmentMatched())
+	meta.SetExternalName(cr, strconv.Itoa(transferID))
+
+	domainName := cr.Spec.ForProvider.DomainName
+	c.resubmit = false
+	switch phase {
+	case namecheap.TransferPhaseCompleted:
+		cr.SetConditions(v1beta1.TransferCompleted(), xpv1.Available())
+		if previous != phase {
+			c.recorder.Event(cr, event.Normal(reasonTransferCompleted,
+				"domain "+domainName+" was transferred into the account"))
+		}
+	case namecheap.TransferPhaseFailed:
+		cr.SetConditions(v1beta1.TransferFailed(status.Status), xpv1.Unavailable())
+		if previous != phase {
+			c.recorder.Event(cr, event.Warning(reasonTransferFailed,
+				errors.Errorf("transfer of domain %s failed: %s", domainName, status.Status)))
+		}
+	case namecheap.TransferPhaseAwaitingEPP:
+		cr.SetConditions(v1beta1.TransferAwaitingEPP(status.Status), xpv1.Creating())
+
+		// Resubmit once the EPP code Secret has changed since the transfer
+		// was last submitted
+		_, version, err := c.eppCode(ctx, cr)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		c.resubmit = namecheap.ShouldResubmitTransfer(status.Status, version, obs.EPPCodeSecretVersion)
+	default:
+		cr.SetConditions(v1beta1.TransferPending(status.Status), xpv1.Creating())
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: !c.resubmit,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1beta1.DomainTransfer)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotDomainTransfer)
+	}
+	ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.DomainTransferKind)
+
+	cr.Status.SetConditions(xpv1.Creating())
+
+	// Read the EPP code before ordering, as most TLDs reject a transfer
+	// without one
+	code, version, err := c.eppCode(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	years := 1
+	if cr.Spec.ForProvider.Years != nil {
+		years = *cr.Spec.ForProvider.Years
+	}
+	whoisGuard := cr.Spec.ForProvider.WhoisGuard != nil && *cr.Spec.ForProvider.WhoisGuard
+
+	order, err := c.client.CreateTransfer(ctx, cr.Spec.ForProvider.DomainName, years, namecheap.TransferOptions{
+		EPPCode:           code,
+		AddFreeWhoisguard: whoisGuard,
+		EnableWhoisguard:  whoisGuard,
+	})
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateTransfer)
+	}
+
+	// Set external name
+	meta.SetExternalName(cr, strconv.Itoa(order.TransferID))
+
+	// Update status
+	statusCode := int(order.StatusID)
+	cr.Status.AtProvider.TransferID = &order.TransferID
+	cr.Status.AtProvider.StatusCode = &statusCode
+	cr.Status.AtProvider.OrderID = order.OrderID
+	cr.Status.AtProvider.TransactionID = order.TransactionID
+	cr.Status.AtProvider.ChargedAmount = strconv.FormatFloat(order.ChargedAmount, 'f', 2, 64)
+	cr.Status.AtProvider.EPPCodeSecretVersion = version
+	if err := common.SetRecord(cr, annotationKeyTransferOrder, transferOrder{
+		OrderID:              order.OrderID,
+		TransactionID:        order.TransactionID,
+		ChargedAmount:        cr.Status.AtProvider.ChargedAmount,
+		EPPCodeSecretVersion: version,
+	}); err != nil {
+		c.recorder.Event(cr, event.Warning(reasonTransferOrder, err))
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1beta1.DomainTransfer)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotDomainTransfer)
+	}
+	ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.DomainTransferKind)
+
+	// A transfer can't be changed once ordered, only resubmitted with a
+	// corrected EPP code
+	if !c.resubmit {
+		return managed.ExternalUpdate{}, nil
+	}
+
+	code, version, err := c.eppCode(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if err := c.client.ResubmitTransfer(ctx, *cr.Status.AtProvider.TransferID, code); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errResubmitTransfer)
+	}
+	cr.Status.AtProvider.EPPCodeSecretVersion = version
+
+	c.recorder.Event(cr, event.Normal(reasonTransferResubmit,
+		"resubmitted transfer of domain "+cr.Spec.ForProvider.DomainName+" with the updated EPP code"))
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*v1beta1.DomainTransfer)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotDomainTransfer)
+	}
+
+	// Transfers can't be cancelled through the API, and a completed
+	// transfer leaves a domain that is managed as a Domain
+	cr.SetConditions(xpv1.Deleting())
+
+	if cr.Status.AtProvider.Phase != string(namecheap.TransferPhaseCompleted) &&
+		cr.Status.AtProvider.Phase != string(namecheap.TransferPhaseFailed) {
+		c.recorder.Event(cr, event.Warning(reasonDeletionBehavior,
+			errors.New("transfers can't be cancelled through the Namecheap API; "+
+				"cancel it in the Namecheap dashboard to stop it")))
+	}
+
+	return managed.ExternalDelete{}, nil
+}
@@ -0,0 +1,452 @@
+package domaintransfer
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/internal/fakeserver"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap/fake"
+)
+
+// recorder captures the events recorded by an external client.
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+// withReason returns the recorded events with the given reason
+func (r *recorder) withReason(reason event.Reason) []event.Event {
+	var events []event.Event
+	for _, e := range r.events {
+		if e.Reason == reason {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// eppSecret returns the Secret holding the EPP code of the test transfer
+func eppSecret(code string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "epp"},
+		Data:       map[string][]byte{"code": []byte(code)},
+	}
+}
+
+// newTransfer returns a DomainTransfer of example.com reading its EPP code
+// from eppSecret
+func newTransfer() *v1beta1.DomainTransfer {
+	cr := &v1beta1.DomainTransfer{}
+	cr.SetNamespace("default")
+	cr.SetName("example")
+	// The managed reconciler defaults the external name to the name
+	meta.SetExternalName(cr, cr.GetName())
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.EPPCodeSecretRef = xpv1.LocalSecretKeySelector{
+		LocalSecretReference: xpv1.LocalSecretReference{Name: "epp"},
+		Key:                  "code",
+	}
+	return cr
+}
+
+// newTestHarness returns a harness reconciling DomainTransfers against a fake
+// Namecheap API, with a Kubernetes API holding objs, and the recorder of
+// its current external client
+func newTestHarness(t *testing.T, objs ...client.Object) (*fakeserver.Harness, *fakeserver.Server, client.Client, *recorder) {
+	t.Helper()
+
+	kube := fakeclient.NewClientBuilder().WithObjects(objs...).Build()
+	server := fakeserver.New(t)
+	rec := &recorder{}
+	h := fakeserver.NewHarness(server, func(client *namecheap.Client) managed.ExternalClient {
+		*rec = recorder{}
+		return &external{client: client, kube: kube, recorder: rec}
+	})
+	return h, server, kube, rec
+}
+
+func TestCreate_EPPCodeFromSecret(t *testing.T) {
+	h, server, _, _ := newTestHarness(t, eppSecret("s3cret-code\n"))
+	cr := newTransfer()
+	whoisGuard, years := true, 2
+	cr.Spec.ForProvider.WhoisGuard = &whoisGuard
+	cr.Spec.ForProvider.Years = &years
+
+	_, err := h.Reconcile(context.Background(), cr)
+	require.NoError(t, err)
+
+	transfers := server.Transfers()
+	require.Len(t, transfers, 1)
+	assert.Equal(t, "example.com", transfers[0].DomainName)
+	assert.Equal(t, "s3cret-code", transfers[0].EPPCode)
+	assert.Equal(t, 2, transfers[0].Years)
+
+	id := transfers[0].ID
+	assert.Equal(t, strconv.Itoa(id), meta.GetExternalName(cr))
+
+	// The order is observed once Create's status is discarded
+	_, err = h.Reconcile(context.Background(), cr)
+	require.NoError(t, err)
+	require.NotNil(t, cr.Status.AtProvider.TransferID)
+	assert.Equal(t, id, *cr.Status.AtProvider.TransferID)
+	assert.Equal(t, "9.10", cr.Status.AtProvider.ChargedAmount)
+	assert.NotEmpty(t, cr.Status.AtProvider.EPPCodeSecretVersion)
+}
+
+func TestCreate_NoEPPCode(t *testing.T) {
+	tests := []struct {
+		name string
+		objs []client.Object
+	}{
+		{name: "SecretMissing"},
+		{name: "KeyEmpty", objs: []client.Object{eppSecret(" ")}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h, server, _, _ := newTestHarness(t, tc.objs...)
+			cr := newTransfer()
+
+			_, err := h.Reconcile(context.Background(), cr)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), errGetEPPCode)
+			assert.Zero(t, server.BillableCalls(), "no transfer should be ordered without an EPP code")
+			assert.Nil(t, cr.Status.AtProvider.TransferID)
+		})
+	}
+}
+
+func TestObserve_Phases(t *testing.T) {
+	h, server, _, rec := newTestHarness(t, eppSecret("code"))
+	cr := newTransfer()
+	ctx := context.Background()
+
+	reconcile := func() {
+		t.Helper()
+		_, err := h.Reconcile(ctx, cr)
+		require.NoError(t, err)
+	}
+
+	reconcile()
+	id := server.Transfers()[0].ID
+
+	reconcile()
+	assert.Equal(t, string(namecheap.TransferPhasePending), cr.Status.AtProvider.Phase)
+	assert.Equal(t, v1beta1.ReasonTransferPending, cr.GetCondition(v1beta1.TypeTransfer).Reason)
+	assert.Equal(t, corev1.ConditionFalse, cr.GetCondition(xpv1.TypeReady).Status)
+
+	server.SetTransferStatus(id, 15, "Awaiting release from the losing registrar")
+	reconcile()
+	require.NotNil(t, cr.Status.AtProvider.StatusCode)
+	assert.Equal(t, 15, *cr.Status.AtProvider.StatusCode)
+	assert.Equal(t, "Awaiting release from the losing registrar", cr.Status.AtProvider.StatusDescription)
+	assert.Equal(t, v1beta1.ReasonTransferPending, cr.GetCondition(v1beta1.TypeTransfer).Reason)
+
+	server.SetTransferStatus(id, namecheap.TransferStatusCompleted, "Completed")
+	reconcile()
+	reconcile()
+	assert.Equal(t, string(namecheap.TransferPhaseCompleted), cr.Status.AtProvider.Phase)
+	assert.Equal(t, corev1.ConditionTrue, cr.GetCondition(v1beta1.TypeTransfer).Status)
+	assert.Equal(t, corev1.ConditionTrue, cr.GetCondition(xpv1.TypeReady).Status)
+	assert.Len(t, rec.withReason(reasonTransferCompleted), 1, "completion should be announced once")
+	assert.NotNil(t, server.Domain("example.com"))
+
+	assert.Equal(t, 1, server.BillableCalls())
+}
+
+func TestObserve_Failed(t *testing.T) {
+	h, server, _, rec := newTestHarness(t, eppSecret("code"))
+	cr := newTransfer()
+	ctx := context.Background()
+
+	_, err := h.Reconcile(ctx, cr)
+	require.NoError(t, err)
+
+	server.SetTransferStatus(server.Transfers()[0].ID, -22, "Cancelled by the losing registrar")
+	for range 2 {
+		obs, err := h.Reconcile(ctx, cr)
+		require.NoError(t, err)
+		assert.True(t, obs.ResourceExists, "a failed transfer shouldn't be ordered again")
+	}
+
+	assert.Equal(t, v1beta1.ReasonTransferFailed, cr.GetCondition(v1beta1.TypeTransfer).Reason)
+	assert.Equal(t, corev1.ConditionFalse, cr.GetCondition(xpv1.TypeReady).Status)
+	assert.Len(t, rec.withReason(reasonTransferFailed), 1)
+	assert.Equal(t, 1, server.BillableCalls())
+}
+
+func TestUpdate_ResubmitOnNewEPPCode(t *testing.T) {
+	secret := eppSecret("wrong")
+	h, server, kube, rec := newTestHarness(t, secret)
+	cr := newTransfer()
+	ctx := context.Background()
+
+	reconcile := func() managed.ExternalObservation {
+		t.Helper()
+		obs, err := h.Reconcile(ctx, cr)
+		require.NoError(t, err)
+		return obs
+	}
+
+	reconcile()
+	id := server.Transfers()[0].ID
+	server.SetTransferStatus(id, namecheap.TransferStatusEPPInvalid, "EPP invalid")
+
+	// The code the transfer was submitted with is still wrong
+	obs := reconcile()
+	assert.True(t, obs.ResourceUpToDate)
+	assert.Equal(t, v1beta1.ReasonTransferAwaitingEPP, cr.GetCondition(v1beta1.TypeTransfer).Reason)
+	assert.Zero(t, server.Calls(namecheap.CommandDomainsTransferUpdateStatus))
+
+	// Correcting the code resubmits the transfer once
+	require.NoError(t, kube.Get(ctx, client.ObjectKeyFromObject(secret), secret))
+	secret.Data["code"] = []byte("right")
+	require.NoError(t, kube.Update(ctx, secret))
+
+	assert.False(t, reconcile().ResourceUpToDate)
+	assert.Equal(t, 1, server.Calls(namecheap.CommandDomainsTransferUpdateStatus))
+	assert.Equal(t, "right", server.Transfers()[0].EPPCode)
+	assert.Len(t, rec.withReason(reasonTransferResubmit), 1)
+
+	reconcile()
+	assert.Equal(t, v1beta1.ReasonTransferPending, cr.GetCondition(v1beta1.TypeTransfer).Reason)
+
+	// A code rejected again waits for the next change of the Secret
+	server.SetTransferStatus(id, namecheap.TransferStatusEPPInvalid, "EPP invalid")
+	assert.True(t, reconcile().ResourceUpToDate)
+	assert.Equal(t, 1, server.Calls(namecheap.CommandDomainsTransferUpdateStatus))
+	assert.Equal(t, 1, server.BillableCalls())
+}
+
+// fakeManager provides the client and scheme a managed reconciler needs
+type fakeManager struct {
+	manager.Manager
+	client client.Client
+}
+
+func (m *fakeManager) GetClient() client.Client {
+	return m.client
+}
+
+func (m *fakeManager) GetScheme() *runtime.Scheme {
+	return m.client.Scheme()
+}
+
+// TestReconcile_ResubmitOnNewEPPCode runs a transfer stalled on its EPP code
+// through the managed reconciler, which discards the status Create sets
+func TestReconcile_ResubmitOnNewEPPCode(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	secret := eppSecret("wrong")
+	cr := newTransfer()
+	kube := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(cr, secret).WithStatusSubresource(cr).Build()
+	ctx := context.Background()
+
+	var resubmitted []string
+	api := &fake.TransferAPI{
+		MockCreateTransfer: func(_ context.Context, domainName string, _ int, _ namecheap.TransferOptions) (*namecheap.TransferOrder, error) {
+			return &namecheap.TransferOrder{DomainName: domainName, TransferID: 15, ChargedAmount: 9.1, OrderID: 1002, TransactionID: 2003}, nil
+		},
+		MockGetTransferStatus: func(_ context.Context, transferID int) (*namecheap.TransferStatus, error) {
+			return &namecheap.TransferStatus{TransferID: transferID, Status: "EPP invalid", StatusID: namecheap.TransferStatusEPPInvalid}, nil
+		},
+		MockResubmitTransfer: func(_ context.Context, _ int, eppCode string) error {
+			resubmitted = append(resubmitted, eppCode)
+			return nil
+		},
+	}
+	connector := managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+		return &external{client: api, kube: kube, recorder: &recorder{}}, nil
+	})
+	r := managed.NewReconciler(&fakeManager{client: kube},
+		resource.ManagedKind(v1beta1.DomainTransferGroupVersionKind),
+		managed.WithExternalConnector(connector))
+
+	reconcile := func() {
+		t.Helper()
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+		require.NoError(t, err)
+	}
+
+	// The transfer is ordered, then found stalled on the code it was
+	// ordered with, which isn't resubmitted
+	for range 3 {
+		reconcile()
+	}
+	assert.Empty(t, resubmitted)
+
+	got := &v1beta1.DomainTransfer{}
+	require.NoError(t, kube.Get(ctx, client.ObjectKeyFromObject(cr), got))
+	assert.Equal(t, 1002, got.Status.AtProvider.OrderID)
+	assert.Equal(t, 2003, got.Status.AtProvider.TransactionID)
+	assert.Equal(t, "9.10", got.Status.AtProvider.ChargedAmount)
+	assert.NotEmpty(t, got.Status.AtProvider.EPPCodeSecretVersion)
+
+	// Correcting the code resubmits the transfer once
+	require.NoError(t, kube.Get(ctx, client.ObjectKeyFromObject(secret), secret))
+	secret.Data["code"] = []byte("right")
+	require.NoError(t, kube.Update(ctx, secret))
+	for range 2 {
+		reconcile()
+	}
+	assert.Equal(t, []string{"right"}, resubmitted)
+}
+
+// TestReconcile_Delete runs the deletion of an ordered transfer through the
+// managed reconciler, which deletes the resource once Observe reports the
+// transfer gone
+func TestReconcile_Delete(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	cr := newTransfer()
+	kube := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(cr, eppSecret("code")).WithStatusSubresource(cr).Build()
+	ctx := context.Background()
+
+	api := &fake.TransferAPI{
+		MockCreateTransfer: func(_ context.Context, domainName string, _ int, _ namecheap.TransferOptions) (*namecheap.TransferOrder, error) {
+			return &namecheap.TransferOrder{DomainName: domainName, TransferID: 15}, nil
+		},
+		MockGetTransferStatus: func(_ context.Context, transferID int) (*namecheap.TransferStatus, error) {
+			return &namecheap.TransferStatus{TransferID: transferID, Status: "Completed", StatusID: namecheap.TransferStatusCompleted}, nil
+		},
+	}
+	connector := managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+		return &external{client: api, kube: kube, recorder: &recorder{}}, nil
+	})
+	// Without a grace period the reconciler trusts at once that a transfer
+	// ordered moments ago is gone
+	r := managed.NewReconciler(&fakeManager{client: kube},
+		resource.ManagedKind(v1beta1.DomainTransferGroupVersionKind),
+		managed.WithExternalConnector(connector),
+		managed.WithCreationGracePeriod(0))
+
+	reconcile := func() {
+		t.Helper()
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+		require.NoError(t, err)
+	}
+
+	// The transfer is ordered and completes
+	for range 2 {
+		reconcile()
+	}
+	got := &v1beta1.DomainTransfer{}
+	require.NoError(t, kube.Get(ctx, client.ObjectKeyFromObject(cr), got))
+	assert.NotEmpty(t, got.GetFinalizers())
+
+	// Deleting it runs Delete, after which the transfer is reported gone
+	// and the finalizer removed, although the API still reports it
+	require.NoError(t, kube.Delete(ctx, got))
+	for range 2 {
+		reconcile()
+	}
+	err := kube.Get(ctx, client.ObjectKeyFromObject(cr), got)
+	assert.True(t, kerrors.IsNotFound(err), "the DomainTransfer should be deleted, got %v", err)
+}
+
+func TestIdempotency_Restart(t *testing.T) {
+	tests := []struct {
+		name string
+		// lose simulates the status lost while the provider was down, as
+		// when a resource is restored from a backup
+		lose func(cr *v1beta1.DomainTransfer)
+	}{
+		{name: "StatusKept", lose: func(*v1beta1.DomainTransfer) {}},
+		{name: "StatusLost", lose: func(cr *v1beta1.DomainTransfer) { cr.Status = v1beta1.DomainTransferStatus{} }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h, server, _, _ := newTestHarness(t, eppSecret("code"))
+			cr := newTransfer()
+			ctx := context.Background()
+
+			_, err := h.Reconcile(ctx, cr)
+			require.NoError(t, err)
+			id := server.Transfers()[0].ID
+			server.SetTransferStatus(id, namecheap.TransferStatusCompleted, "Completed")
+
+			tc.lose(cr)
+			h.Restart()
+
+			for range 3 {
+				obs, err := h.Reconcile(ctx, cr)
+				require.NoError(t, err)
+				assert.True(t, obs.ResourceExists)
+				assert.True(t, obs.ResourceUpToDate)
+			}
+
+			assert.Equal(t, 1, server.BillableCalls(), "the transfer should be ordered once")
+			require.NotNil(t, cr.Status.AtProvider.TransferID)
+			assert.Equal(t, id, *cr.Status.AtProvider.TransferID)
+			assert.Equal(t, corev1.ConditionTrue, cr.GetCondition(xpv1.TypeReady).Status)
+		})
+	}
+}
+
+func TestObservedTransferID(t *testing.T) {
+	id := 42
+	tests := []struct {
+		name         string
+		resource     string
+		statusID     *int
+		externalName string
+		want         int
+		wantOK       bool
+	}{
+		{name: "Status", statusID: &id, externalName: "7", want: 42, wantOK: true},
+		{name: "ExternalName", externalName: "42", want: 42, wantOK: true},
+		{name: "DefaultExternalName", externalName: "example"},
+		{name: "NumericName", resource: "123", externalName: "123"},
+		{name: "NoExternalName"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cr := &v1beta1.DomainTransfer{}
+			cr.SetName("example")
+			if tc.resource != "" {
+				cr.SetName(tc.resource)
+			}
+			if tc.externalName != "" {
+				meta.SetExternalName(cr, tc.externalName)
+			}
+			cr.Status.AtProvider.TransferID = tc.statusID
+
+			got, ok := observedTransferID(cr)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
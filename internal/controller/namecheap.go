@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/internal/controller/dnsrecord"
+	"github.com/rossigee/provider-namecheap/internal/controller/dnszone"
+	"github.com/rossigee/provider-namecheap/internal/controller/domain"
+	"github.com/rossigee/provider-namecheap/internal/controller/domaintransfer"
+	"github.com/rossigee/provider-namecheap/internal/controller/sslcertificate"
+)
+
+// A Registration is a managed resource controller run by the provider
+type Registration struct {
+	// Kind is the kind of managed resource the controller reconciles
+	Kind string
+
+	// Setup adds the controller to a manager
+	Setup func(ctrl.Manager, controller.Options) error
+}
+
+// Registrations are all controllers run by the provider. Every package under
+// internal/controller with a Setup function must be registered here.
+var Registrations = []Registration{
+	{Kind: v1beta1.DomainKind, Setup: domain.Setup},
+	{Kind: v1beta1.DNSRecordKind, Setup: dnsrecord.Setup},
+	{Kind: v1beta1.SSLCertificateKind, Setup: sslcertificate.Setup},
+	{Kind: v1beta1.DomainTransferKind, Setup: domaintransfer.Setup},
+	{Kind: v1beta1.DNSZoneKind, Setup: dnszone.Setup},
+}
+
+// Setup adds every registered controller to the manager
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	for _, r := range Registrations {
+		if err := r.Setup(mgr, o); err != nil {
+			return errors.Wrapf(err, "cannot setup %s controller", r.Kind)
+		}
+	}
+	return nil
+}
+
+// Kinds returns the kinds of managed resource reconciled by the registered
+// controllers
+func Kinds() []string {
+	kinds := make([]string, 0, len(Registrations))
+	for _, r := range Registrations {
+		kinds = append(kinds, r.Kind)
+	}
+	return kinds
+}
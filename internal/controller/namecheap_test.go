@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRegistrations checks that every package under internal/controller that
+// provides a Setup function is registered, so that a new controller can't be
+// silently left out of the provider.
+func TestRegistrations(t *testing.T) {
+	registered := map[string]bool{}
+	for _, r := range Registrations {
+		name := runtime.FuncForPC(reflect.ValueOf(r.Setup).Pointer()).Name()
+		pkg := strings.TrimSuffix(name[strings.LastIndex(name, "/")+1:], ".Setup")
+		assert.False(t, registered[pkg], "package %s is registered more than once", pkg)
+		registered[pkg] = true
+	}
+
+	entries, err := os.ReadDir(".")
+	require.NoError(t, err)
+
+	for _, entry := range entries {
+		if entry.IsDir() && hasSetup(t, entry.Name()) {
+			assert.True(t, registered[entry.Name()], "controller package %s provides Setup but is not registered",
+				filepath.Join("internal/controller", entry.Name()))
+		}
+	}
+}
+
+// hasSetup reports whether the package in dir declares a Setup function
+func hasSetup(t *testing.T, dir string) bool {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	require.NoError(t, err)
+
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), f, nil, parser.SkipObjectResolution)
+		require.NoError(t, err)
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "Setup" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+// Package pollinterval resolves how often a managed resource is polled for
+// drift, taking its ProviderConfig into account.
+package pollinterval
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+)
+
+const (
+	// MinPollInterval is the shortest poll interval a ProviderConfig may set.
+	MinPollInterval = 30 * time.Second
+
+	// MinSyncInterval is the shortest sync interval a ProviderConfig may set.
+	MinSyncInterval = time.Minute
+)
+
+// NewHook returns a managed.PollIntervalHook that resolves a resource's poll
+// interval with the following precedence:
+//
+//  1. The resource's crossplane.io/poll-interval annotation.
+//  2. The pollInterval of the resource's ProviderConfig.
+//  3. The controller-wide --poll default.
+//
+// The result is then capped by the ProviderConfig's syncInterval, if set.
+//
+// The supplied reader should be backed by the manager's informer cache so
+// that resolving the interval does not cost an API server round trip per
+// reconcile.
+func NewHook(kube client.Reader, log logging.Logger) managed.PollIntervalHook {
+	return func(mg resource.Managed, pollInterval time.Duration) time.Duration {
+		pcr, ok := mg.(resource.TypedProviderConfigReferencer)
+		if !ok {
+			return pollInterval
+		}
+
+		ref := pcr.GetProviderConfigReference()
+		if ref == nil || ref.Name == "" {
+			return pollInterval
+		}
+
+		pc := &v1beta1.ProviderConfig{}
+		if err := kube.Get(context.TODO(), types.NamespacedName{Name: ref.Name}, pc); err != nil {
+			log.Debug("Cannot get ProviderConfig, using default poll interval", "providerConfig", ref.Name, "error", err)
+			return pollInterval
+		}
+
+		return Resolve(mg, pc, pollInterval)
+	}
+}
+
+// Resolve returns the poll interval for a resource using the supplied
+// ProviderConfig. pollInterval is the controller-wide default, or the
+// resource's annotation override when one is set.
+func Resolve(mg resource.Managed, pc *v1beta1.ProviderConfig, pollInterval time.Duration) time.Duration {
+	interval := pollInterval
+
+	if _, annotated := meta.GetPollInterval(mg); !annotated && pc.Spec.PollInterval != nil {
+		interval = max(pc.Spec.PollInterval.Duration, MinPollInterval)
+	}
+
+	if pc.Spec.SyncInterval != nil {
+		interval = min(interval, max(pc.Spec.SyncInterval.Duration, MinSyncInterval))
+	}
+
+	return interval
+}
@@ -0,0 +1,110 @@
+package pollinterval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+)
+
+func TestNewHook(t *testing.T) {
+	const flagDefault = time.Minute
+
+	duration := func(d time.Duration) *metav1.Duration {
+		return &metav1.Duration{Duration: d}
+	}
+
+	tests := []struct {
+		name       string
+		annotation string
+		pcPoll     *metav1.Duration
+		pcSync     *metav1.Duration
+		noPC       bool
+		expected   time.Duration
+	}{
+		{
+			name:     "flag default",
+			expected: flagDefault,
+		},
+		{
+			name:     "ProviderConfig overrides flag default",
+			pcPoll:   duration(10 * time.Minute),
+			expected: 10 * time.Minute,
+		},
+		{
+			name:       "annotation overrides ProviderConfig",
+			annotation: "5m",
+			pcPoll:     duration(10 * time.Minute),
+			expected:   5 * time.Minute,
+		},
+		{
+			name:     "ProviderConfig interval below minimum is raised",
+			pcPoll:   duration(time.Second),
+			expected: MinPollInterval,
+		},
+		{
+			name:     "sync interval caps ProviderConfig interval",
+			pcPoll:   duration(2 * time.Hour),
+			pcSync:   duration(time.Hour),
+			expected: time.Hour,
+		},
+		{
+			name:       "sync interval caps annotation",
+			annotation: "24h",
+			pcSync:     duration(time.Hour),
+			expected:   time.Hour,
+		},
+		{
+			name:     "missing ProviderConfig falls back to flag default",
+			noPC:     true,
+			expected: flagDefault,
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc := &v1beta1.ProviderConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "default"},
+				Spec: v1beta1.ProviderConfigSpec{
+					PollInterval: tt.pcPoll,
+					SyncInterval: tt.pcSync,
+				},
+			}
+
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if !tt.noPC {
+				builder = builder.WithObjects(pc)
+			}
+
+			cr := &v1beta1.Domain{}
+			cr.SetName("example")
+			cr.SetProviderConfigReference(&xpv1.ProviderConfigReference{Name: "default", Kind: "ProviderConfig"})
+
+			// The reconciler hands the hook the annotation value when one is
+			// set, otherwise the flag default.
+			pollInterval := flagDefault
+			if tt.annotation != "" {
+				meta.AddAnnotations(cr, map[string]string{meta.AnnotationKeyPollInterval: tt.annotation})
+				d, ok := meta.GetPollInterval(cr)
+				require.True(t, ok)
+				pollInterval = d
+			}
+
+			hook := NewHook(builder.Build(), logging.NewNopLogger())
+			assert.Equal(t, tt.expected, hook(cr, pollInterval))
+		})
+	}
+}
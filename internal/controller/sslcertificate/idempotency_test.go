@@ -0,0 +1,125 @@
+package sslcertificate
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/internal/controller/common"
+	"github.com/rossigee/provider-namecheap/internal/fakeserver"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+func TestIdempotency_Restart(t *testing.T) {
+	tests := []struct {
+		name string
+		// lose simulates the status lost while the provider was down, as
+		// when a resource is restored from a backup
+		lose func(cr *v1beta1.SSLCertificate)
+	}{
+		{name: "StatusKept", lose: func(*v1beta1.SSLCertificate) {}},
+		{name: "StatusLost", lose: func(cr *v1beta1.SSLCertificate) { cr.Status = v1beta1.SSLCertificateStatus{} }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := fakeserver.New(t)
+			h := fakeserver.NewHarness(server, func(client *namecheap.Client) managed.ExternalClient {
+				rec := &recorder{}
+				return &external{service: client, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}
+			})
+
+			autoActivate, csr, approver := true, fakeserver.NewCSR(t, "example.com"), "admin@example.com"
+			cr := &v1beta1.SSLCertificate{}
+			cr.SetName("example")
+			// The managed reconciler defaults the external name to the name
+			meta.SetExternalName(cr, cr.GetName())
+			cr.Spec.ForProvider.DomainName = "example.com"
+			cr.Spec.ForProvider.CertificateType = 1
+			cr.Spec.ForProvider.AutoActivate = &autoActivate
+			cr.Spec.ForProvider.CSR = &csr
+			cr.Spec.ForProvider.ApproverEmail = &approver
+
+			ctx := context.Background()
+			reconcile := func() managed.ExternalObservation {
+				t.Helper()
+				obs, err := h.Reconcile(ctx, cr)
+				require.NoError(t, err)
+				return obs
+			}
+
+			reconcile()
+			require.True(t, reconcile().ResourceExists)
+			require.Equal(t, 1, server.BillableCalls())
+
+			tc.lose(cr)
+			h.Restart()
+
+			for range 3 {
+				obs := reconcile()
+				assert.True(t, obs.ResourceExists)
+				assert.True(t, obs.ResourceUpToDate)
+			}
+
+			assert.Equal(t, 1, server.BillableCalls(), "the certificate should be purchased once")
+			assert.Equal(t, 1, server.Calls(namecheap.CommandSSLCreate))
+			assert.Equal(t, 1, server.Calls(namecheap.CommandSSLActivate))
+
+			certificates := server.Certificates()
+			require.Len(t, certificates, 1)
+			certificate := certificates[0]
+			assert.Equal(t, strconv.Itoa(certificate.ID), meta.GetExternalName(cr))
+			require.NotNil(t, cr.Status.AtProvider.CertificateID)
+			assert.Equal(t, certificate.ID, *cr.Status.AtProvider.CertificateID)
+			require.NotNil(t, cr.Status.AtProvider.HostName)
+			assert.Equal(t, "example.com", *cr.Status.AtProvider.HostName)
+			assert.Equal(t, corev1.ConditionTrue, cr.GetCondition(xpv1.TypeReady).Status)
+		})
+	}
+}
+
+func TestObservedCertificateID(t *testing.T) {
+	id := 42
+	tests := []struct {
+		name         string
+		resource     string
+		statusID     *int
+		externalName string
+		want         int
+		wantOK       bool
+	}{
+		{name: "Status", statusID: &id, externalName: "7", want: 42, wantOK: true},
+		{name: "ExternalName", externalName: "42", want: 42, wantOK: true},
+		{name: "DefaultExternalName", externalName: "example"},
+		{name: "NumericName", resource: "123", externalName: "123"},
+		{name: "NoExternalName"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cr := &v1beta1.SSLCertificate{}
+			cr.SetName("example")
+			if tc.resource != "" {
+				cr.SetName(tc.resource)
+			}
+			if tc.externalName != "" {
+				meta.SetExternalName(cr, tc.externalName)
+			}
+			cr.Status.AtProvider.CertificateID = tc.statusID
+
+			got, ok := observedCertificateID(cr)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
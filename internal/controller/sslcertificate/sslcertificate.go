@@ -2,8 +2,10 @@ package sslcertificate
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -11,43 +13,75 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
 
 	"github.com/rossigee/provider-namecheap/apis/v1beta1"
-	"github.com/rossigee/provider-namecheap/internal/clients/namecheap"
+	"github.com/rossigee/provider-namecheap/internal/controller/common"
+	"github.com/rossigee/provider-namecheap/internal/controller/pollinterval"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
 )
 
 const (
-	errNotSSLCertificate   = "managed resource is not an SSLCertificate custom resource"
-	errTrackPCUsage       = "cannot track ProviderConfig usage"
-	errGetPC              = "cannot get ProviderConfig"
-	errGetCreds           = "cannot get credentials"
-	errNewClient          = "cannot create new Service"
-	errGetSSLCertificate  = "cannot get SSL certificate"
-	errCreateSSLCertificate = "cannot create SSL certificate"
+	errNotSSLCertificate      = "managed resource is not an SSLCertificate custom resource"
+	errTrackPCUsage           = "cannot track ProviderConfig usage"
+	errGetPC                  = "cannot get ProviderConfig"
+	errGetCreds               = "cannot get credentials"
+	errNewClient              = "cannot create new Service"
+	errGetSSLCertificate      = "cannot get SSL certificate"
+	errCreateSSLCertificate   = "cannot create SSL certificate"
 	errActivateSSLCertificate = "cannot activate SSL certificate"
-	errDeleteSSLCertificate = "cannot delete SSL certificate"
+	errDeleteSSLCertificate   = "cannot delete SSL certificate"
+	errGetApproverEmails      = "cannot get accepted approver emails"
+	errApproverEmail          = "approver email is not accepted"
+	errParseCSR               = "cannot parse CSR"
+	errRenewSSLCertificate    = "cannot renew SSL certificate"
+	errRevokeSSLCertificate   = "cannot revoke SSL certificate"
+	errEditDCValidation       = "cannot switch SSL certificate to DNS validation"
+	errGetIssuedCertificate   = "cannot download issued SSL certificate"
+
+	reasonDeletionBehavior event.Reason = "DeletionBehavior"
+	reasonRenewal          event.Reason = "Renewal"
+	reasonRevoked          event.Reason = "Revoked"
+	reasonDCValidation     event.Reason = "DCValidation"
+	reasonActivation       event.Reason = "Activation"
 )
 
+// annotationKeyActivation records the activation requested by Create, whose
+// status the managed reconciler discards
+const annotationKeyActivation = "namecheap.m.crossplane.io/activation"
+
+// activationRecord is the record of a certificate's requested activation
+type activationRecord struct {
+	CertificateID int    `json:"certificateID"`
+	DNSValidation string `json:"dnsValidation,omitempty"`
+}
+
 // Setup adds a controller that reconciles SSLCertificate managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1beta1.SSLCertificateGroupKind)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name)) //nolint:staticcheck // SA1019: required for v2 API compatibility
 
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1beta1.SSLCertificateGroupVersionKind),
 		managed.WithExternalConnector(&connector{
-			kube:   mgr.GetClient(),
-			usage:  resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
+			kube:     mgr.GetClient(),
+			usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
+			recorder: recorder,
+			log:      o.Logger.WithValues("controller", name),
+			drift:    common.NewDriftEvents(recorder, o.PollInterval),
 		}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))) //nolint:staticcheck // SA1019: required for v2 API compatibility
+		managed.WithPollIntervalHook(pollinterval.NewHook(mgr.GetCache(), o.Logger.WithValues("controller", name))),
+		managed.WithRecorder(recorder),
+		common.WithManagementPolicies(o))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -60,8 +94,11 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 // A connector is expected to produce an ExternalClient when its Connect method
 // is called.
 type connector struct {
-	kube  client.Client
-	usage *resource.ProviderConfigUsageTracker
+	kube     client.Client
+	usage    *resource.ProviderConfigUsageTracker
+	recorder event.Recorder
+	log      logging.Logger
+	drift    *common.DriftEvents
 }
 
 // Connect typically produces an ExternalClient by:
@@ -90,29 +127,29 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetCreds)
 	}
 
+	common.RecordCredentialsRevision(ctx, c.kube, c.log, pc, namecheap.CredentialsRevision(data))
+
 	// Parse credentials from the secret data
-	var creds struct {
-		APIUser  string `json:"api_user"`
-		APIKey   string `json:"api_key"`
-		Username string `json:"username"`
-		ClientIP string `json:"client_ip"`
-	}
-	if err := json.Unmarshal(data, &creds); err != nil {
+	creds, err := namecheap.ParseCredentials(data)
+	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse credentials JSON")
 	}
 
 	// Create Namecheap client
 	config := namecheap.Config{
-		APIUser:  creds.APIUser,
-		APIKey:   creds.APIKey,
-		Username: creds.Username,
-		ClientIP: creds.ClientIP,
-		Sandbox:  pc.Spec.SandboxMode != nil && *pc.Spec.SandboxMode,
+		APIUser:      creds.APIUser,
+		APIKey:       creds.APIKey,
+		Username:     creds.Username,
+		ClientIP:     creds.ClientIP,
+		ClientIPs:    creds.ClientIPs,
+		Sandbox:      pc.Spec.SandboxMode != nil && *pc.Spec.SandboxMode,
+		CheckBalance: pc.Spec.CheckBalance != nil && *pc.Spec.CheckBalance,
 	}
 
 	client := namecheap.NewClient(config)
+	common.Clients.Track(pc.GetName(), client)
 
-	return &external{service: client}, nil
+	return &external{service: client, recorder: c.recorder, drift: c.drift}, nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -120,7 +157,16 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 type external struct {
 	// A 'client' used to connect to the external resource API. In practice this
 	// would be something like an AWS SDK client.
-	service *namecheap.Client
+	service  namecheap.SSLAPI
+	recorder event.Recorder
+	drift    *common.DriftEvents
+
+	// renew is set by Observe when the certificate is due for renewal
+	renew bool
+
+	// switchToDNS is set by Observe when a pending certificate's validation
+	// is to be switched to DNS
+	switchToDNS bool
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -128,73 +174,264 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if !ok {
 		return managed.ExternalObservation{}, errors.New(errNotSSLCertificate)
 	}
+	ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.SSLCertificateKind)
 
 	// If we don't have a certificate ID, the resource doesn't exist yet
-	if cr.Status.AtProvider.CertificateID == nil {
+	certificateID, ok := observedCertificateID(cr)
+	if !ok {
 		return managed.ExternalObservation{
 			ResourceExists: false,
 		}, nil
 	}
 
-	certificateID := *cr.Status.AtProvider.CertificateID
+	// Refuse to reconcile against a different Namecheap environment than
+	// the one the resource was recorded in
+	environment := c.service.Environment()
+	if err := common.CheckEnvironment(cr, cr.Status.AtProvider.Environment, environment); err != nil {
+		cr.SetConditions(v1beta1.EnvironmentMismatch(err.Error()))
+		return managed.ExternalObservation{}, err
+	}
+
+	// Bypass client-side caches once per refresh annotation value
+	refresh, fresh := common.RefreshRequest(cr, cr.Status.AtProvider.LastHandledRefresh)
+	if fresh {
+		ctx = namecheap.WithFreshRead(ctx)
+	}
+
 	cert, err := c.service.GetSSLCertificate(ctx, certificateID)
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetSSLCertificate)
 	}
 
-	// Update the status with observed values
-	cr.Status.AtProvider.CertificateID = &cert.CommandResponse.SSLGetInfoResult.CertificateID
-	cr.Status.AtProvider.HostName = &cert.CommandResponse.SSLGetInfoResult.HostName
-	cr.Status.AtProvider.SSLType = &cert.CommandResponse.SSLGetInfoResult.SSLType
-	cr.Status.AtProvider.IsExpired = &cert.CommandResponse.SSLGetInfoResult.IsExpiredYN
-	cr.Status.AtProvider.Status = &cert.CommandResponse.SSLGetInfoResult.Status
-	cr.Status.AtProvider.StatusDescription = &cert.CommandResponse.SSLGetInfoResult.StatusDescription
-	cr.Status.AtProvider.Years = &cert.CommandResponse.SSLGetInfoResult.Years
+	// Build the observation on a copy of the current one, which preserves
+	// the fields only set on create, and assign it once complete
+	info := cert.CommandResponse.SSLGetInfoResult
+	obs := cr.Status.AtProvider.DeepCopy()
+	if fresh {
+		obs.LastHandledRefresh = refresh
+	}
+	obs.Environment = environment
+
+	// ssl.getInfo doesn't report the certificate's ID, years, purchase date
+	// or approver emails, so those observed elsewhere are kept
+	expired := strings.EqualFold(info.Status, namecheap.SSLStatusExpired)
+	obs.CertificateID = &certificateID
+	if commonName := info.CertificateDetails.CommonName; commonName != "" {
+		obs.HostName = &commonName
+	}
+	obs.SSLType = &info.Type
+	obs.IsExpired = &expired
+	obs.Status = &info.Status
+	obs.StatusDescription = &info.StatusDescription
+	if info.OrderID != 0 {
+		obs.OrderID = &info.OrderID
+	}
 
-	if !cert.CommandResponse.SSLGetInfoResult.PurchaseDate.IsZero() {
-		cr.Status.AtProvider.PurchaseDate = &metav1.Time{Time: cert.CommandResponse.SSLGetInfoResult.PurchaseDate}
+	if !info.Expires.IsZero() {
+		obs.ExpireDate = &metav1.Time{Time: info.Expires.Time}
 	}
-	if !cert.CommandResponse.SSLGetInfoResult.ExpireDate.IsZero() {
-		cr.Status.AtProvider.ExpireDate = &metav1.Time{Time: cert.CommandResponse.SSLGetInfoResult.ExpireDate}
+	if !info.ActivationExpireDate.IsZero() {
+		obs.ActivationExpireDate = &metav1.Time{Time: info.ActivationExpireDate.Time}
 	}
-	if !cert.CommandResponse.SSLGetInfoResult.ActivationExpireDate.IsZero() {
-		cr.Status.AtProvider.ActivationExpireDate = &metav1.Time{Time: cert.CommandResponse.SSLGetInfoResult.ActivationExpireDate}
+
+	obs.ProviderName = &info.Provider.Name
+
+	// Publish the certificate once issued, which is only downloaded then.
+	// A failed download leaves the observation untouched to be retried.
+	var details managed.ConnectionDetails
+	if !meta.WasDeleted(cr) && certificateIssued(cr.Status.AtProvider.Status, info.Status) {
+		details, err = c.issuedCertificate(ctx, certificateID)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
 	}
 
-	cr.Status.AtProvider.ProviderName = &cert.CommandResponse.SSLGetInfoResult.Provider.Name
-	cr.Status.AtProvider.ApproverEmailList = cert.CommandResponse.SSLGetInfoResult.ApproverEmailList
+	// Recover the requested activation of the certificate from its record,
+	// as the managed reconciler discards the status Create sets
+	activationRequested := false
+	if cr.GetCondition(v1beta1.TypeActivation).Reason == "" {
+		activation := activationRecord{}
+		if ok, err := common.GetRecord(cr, annotationKeyActivation, &activation); err != nil {
+			c.recorder.Event(cr, event.Warning(reasonActivation, err))
+		} else if ok && activation.CertificateID == certificateID {
+			obs.DNSValidation = activation.DNSValidation
+			activationRequested = true
+		}
+	}
+
+	cr.Status.AtProvider = *obs
+	cr.SetConditions(v1beta1.EnvironmentMatched())
+	if activationRequested {
+		cr.SetConditions(v1beta1.ActivationRequested())
+	}
+
+	// Certificates can't be deleted, so a deleted resource is gone once
+	// Delete ran, unless the certificate is still to be revoked
+	if meta.WasDeleted(cr) {
+		deleted := cr.GetCondition(xpv1.TypeReady).Reason == xpv1.ReasonDeleting
+		return managed.ExternalObservation{
+			ResourceExists:   !deleted || revocationPending(cr),
+			ResourceUpToDate: true,
+		}, nil
+	}
 
 	// Set resource as ready if certificate is active
-	if cert.CommandResponse.SSLGetInfoResult.Status == "ACTIVE" {
+	if strings.EqualFold(info.Status, namecheap.SSLStatusActive) {
 		cr.SetConditions(xpv1.Available())
 	}
 
+	// Renew a certificate expiring within the renewal window
+	c.renew = renewalDue(cr.Spec.ForProvider.RenewBeforeDays, info.Status, expired, info.Expires.Time, time.Now())
+
+	// An expired certificate has drifted from the certificate the resource
+	// describes, but only a renewal or new purchase can correct it
+	if expired && !c.renew {
+		c.drift.Record(cr, "not correcting it as expired certificates must be renewed or purchased again", common.Drift{
+			Field:    "status.atProvider.isExpired",
+			Expected: "false",
+			Observed: "true",
+		})
+	}
+
+	// A renewal replaces the certificate in the status, but the external
+	// name can only be updated once it is observed
+	lateInitialized := false
+	if id := strconv.Itoa(certificateID); meta.GetExternalName(cr) != id {
+		meta.SetExternalName(cr, id)
+		lateInitialized = true
+	}
+
+	// Switch a pending certificate's validation to DNS when asked to
+	c.switchToDNS = dnsValidationPending(cr, info.Status, expired)
+
 	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: true,
+		ResourceExists:          true,
+		ResourceUpToDate:        !c.renew && !c.switchToDNS,
+		ResourceLateInitialized: lateInitialized,
+		ConnectionDetails:       details,
+	}, nil
+}
+
+// certificateIssued reports whether a certificate observed in status was
+// issued since it was last observed in previous: it became active, was
+// reissued, or replaced by a renewal, which forgets the previous status.
+func certificateIssued(previous *string, status string) bool {
+	return strings.EqualFold(status, namecheap.SSLStatusActive) && (previous == nil || !strings.EqualFold(*previous, namecheap.SSLStatusActive))
+}
+
+// issuedCertificate downloads the issued certificate and the certificate
+// authority's certificates as connection details
+func (c *external) issuedCertificate(ctx context.Context, certificateID int) (managed.ConnectionDetails, error) {
+	cert, err := c.service.GetSSLCertificateWithOptions(ctx, certificateID, namecheap.SSLInfoOptions{ReturnCertificate: true})
+	if err != nil {
+		return nil, errors.Wrap(err, errGetIssuedCertificate)
+	}
+
+	issued := cert.CommandResponse.SSLGetInfoResult.CertificateDetails.Certificates
+	if issued.CertificatePEM() == "" {
+		return nil, errors.Errorf("%s: certificate %d was not returned", errGetIssuedCertificate, certificateID)
+	}
+	return managed.ConnectionDetails{
+		"tls.crt":   []byte(issued.CertificatePEM()),
+		"ca.crt":    []byte(issued.CABundle()),
+		"chain.crt": []byte(issued.Chain()),
 	}, nil
 }
 
+// renewalDue reports whether a certificate in the given status and expiring
+// at expires is due for renewal at now, renewing renewBeforeDays before its
+// expiry. Only active and expired certificates are renewed.
+func renewalDue(renewBeforeDays *int, status string, expired bool, expires, now time.Time) bool {
+	if renewBeforeDays == nil || expires.IsZero() {
+		return false
+	}
+	if !expired && !strings.EqualFold(status, namecheap.SSLStatusActive) {
+		return false
+	}
+	return expires.Sub(now) <= time.Duration(*renewBeforeDays)*24*time.Hour
+}
+
+// dnsValidationPending reports whether the validation of cr's certificate,
+// in the given status, is to be switched to DNS: its activation was
+// requested but hasn't completed, and spec.forProvider.dnsValidation isn't
+// what the validation was last requested with.
+func dnsValidationPending(cr *v1beta1.SSLCertificate, status string, expired bool) bool {
+	p := cr.Spec.ForProvider
+	if p.DNSValidation == nil || *p.DNSValidation == "" || *p.DNSValidation == cr.Status.AtProvider.DNSValidation {
+		return false
+	}
+	if expired || strings.EqualFold(status, namecheap.SSLStatusActive) || strings.EqualFold(status, namecheap.SSLStatusRevoked) {
+		return false
+	}
+	return cr.GetCondition(v1beta1.TypeActivation).Reason == v1beta1.ReasonActivationRequested
+}
+
+// observedCertificateID returns the ID of the certificate purchased for cr.
+// The status records it, but the status can be lost, e.g. when the resource
+// is restored from a backup, and purchasing the certificate again would
+// charge for it twice. The external name set on create then still holds
+// the ID. Until then the external name defaults to the resource's name,
+// which is never taken for an ID.
+func observedCertificateID(cr *v1beta1.SSLCertificate) (int, bool) {
+	if id := cr.Status.AtProvider.CertificateID; id != nil {
+		return *id, true
+	}
+
+	externalName := meta.GetExternalName(cr)
+	if externalName == cr.GetName() {
+		return 0, false
+	}
+	id, err := strconv.Atoi(externalName)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1beta1.SSLCertificate)
 	if !ok {
 		return managed.ExternalCreation{}, errors.New(errNotSSLCertificate)
 	}
+	ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.SSLCertificateKind)
 
-	years := 1
-	if cr.Spec.ForProvider.Years != nil {
-		years = *cr.Spec.ForProvider.Years
+	// Don't purchase a certificate the balance didn't cover again until the
+	// spec changes
+	if err := common.CheckFunds(cr); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateSSLCertificate)
+	}
+
+	// Check the CSR and approver email before the purchase, as a
+	// certificate that can't be activated would otherwise be paid for and
+	// left stuck
+	activate := autoActivates(cr)
+	if activate {
+		if err := c.checkActivation(ctx, cr); err != nil {
+			return managed.ExternalCreation{}, err
+		}
+	}
+
+	certificateType, err := certificateTypeID(cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
 	}
+	years := certificateYears(cr)
 
 	sansToAdd := ""
 	if cr.Spec.ForProvider.SANsToAdd != nil {
 		sansToAdd = *cr.Spec.ForProvider.SANsToAdd
 	}
 
-	certificateID, err := c.service.CreateSSLCertificate(ctx, cr.Spec.ForProvider.CertificateType, years, sansToAdd)
+	certificateID, err := c.service.CreateSSLCertificate(ctx, certificateType, years, sansToAdd)
+	if namecheap.IsInsufficientFunds(err) {
+		err = errors.Wrap(err, errCreateSSLCertificate)
+		common.RecordInsufficientFunds(cr, c.recorder, err)
+		return managed.ExternalCreation{}, err
+	}
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateSSLCertificate)
 	}
+	common.RecordFundsSufficient(cr)
 
 	// Store the certificate ID
 	cr.Status.AtProvider.CertificateID = &certificateID
@@ -203,29 +440,9 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	meta.SetExternalName(cr, strconv.Itoa(certificateID))
 
 	// Auto-activate if requested and CSR is provided
-	if cr.Spec.ForProvider.AutoActivate != nil && *cr.Spec.ForProvider.AutoActivate &&
-		cr.Spec.ForProvider.CSR != nil && cr.Spec.ForProvider.ApproverEmail != nil {
-
-		httpDCValidation := ""
-		if cr.Spec.ForProvider.HTTPDCValidation != nil {
-			httpDCValidation = *cr.Spec.ForProvider.HTTPDCValidation
-		}
-
-		dnsValidation := ""
-		if cr.Spec.ForProvider.DNSValidation != nil {
-			dnsValidation = *cr.Spec.ForProvider.DNSValidation
-		}
-
-		webServerType := ""
-		if cr.Spec.ForProvider.WebServerType != nil {
-			webServerType = *cr.Spec.ForProvider.WebServerType
-		}
-
-		err = c.service.ActivateSSLCertificate(ctx, certificateID, *cr.Spec.ForProvider.CSR,
-			cr.Spec.ForProvider.DomainName, *cr.Spec.ForProvider.ApproverEmail,
-			httpDCValidation, dnsValidation, webServerType)
-		if err != nil {
-			return managed.ExternalCreation{}, errors.Wrap(err, errActivateSSLCertificate)
+	if activate {
+		if err := c.activate(ctx, cr, certificateID); err != nil {
+			return managed.ExternalCreation{}, err
 		}
 	}
 
@@ -239,11 +456,162 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}, nil
 }
 
+// certificateTypeID returns the numeric type ID of cr's certificate, given
+// by name or by ID
+func certificateTypeID(cr *v1beta1.SSLCertificate) (int, error) {
+	if name := cr.Spec.ForProvider.CertificateTypeName; name != nil {
+		return namecheap.SSLTypeID(*name)
+	}
+	return cr.Spec.ForProvider.CertificateType, nil
+}
+
+// certificateYears returns the number of years cr's certificate is
+// purchased and renewed for
+func certificateYears(cr *v1beta1.SSLCertificate) int {
+	if cr.Spec.ForProvider.Years != nil {
+		return *cr.Spec.ForProvider.Years
+	}
+	return 1
+}
+
+// autoActivates reports whether cr's certificates are activated once
+// purchased
+func autoActivates(cr *v1beta1.SSLCertificate) bool {
+	p := cr.Spec.ForProvider
+	return p.AutoActivate != nil && *p.AutoActivate && p.CSR != nil && p.ApproverEmail != nil
+}
+
+// activate activates the certificate with cr's CSR and validation settings
+func (c *external) activate(ctx context.Context, cr *v1beta1.SSLCertificate, certificateID int) error {
+	p := cr.Spec.ForProvider
+	req := namecheap.ActivationRequest{
+		CSR:           *p.CSR,
+		DomainName:    p.DomainName,
+		ApproverEmail: *p.ApproverEmail,
+	}
+	if p.HTTPDCValidation != nil {
+		req.HTTPDCValidation = *p.HTTPDCValidation
+	}
+	if p.DNSValidation != nil {
+		req.DNSValidation = *p.DNSValidation
+	}
+	if p.WebServerType != nil {
+		req.WebServerType = *p.WebServerType
+	}
+	for _, san := range p.SANs {
+		activation := namecheap.SANActivation{DomainName: san.DomainName, Method: san.Validation}
+		if san.ApproverEmail != nil {
+			activation.ApproverEmail = *san.ApproverEmail
+		}
+		req.SANs = append(req.SANs, activation)
+	}
+
+	if err := c.service.ActivateSSLCertificate(ctx, certificateID, req); err != nil {
+		return errors.Wrap(err, errActivateSSLCertificate)
+	}
+	cr.Status.AtProvider.DNSValidation = req.DNSValidation
+	cr.SetConditions(v1beta1.ActivationRequested())
+	if err := common.SetRecord(cr, annotationKeyActivation, activationRecord{
+		CertificateID: certificateID,
+		DNSValidation: req.DNSValidation,
+	}); err != nil {
+		c.recorder.Event(cr, event.Warning(reasonActivation, err))
+	}
+	return nil
+}
+
+// checkActivation returns an error if the certificate can't be activated
+// with cr's CSR and approver email
+func (c *external) checkActivation(ctx context.Context, cr *v1beta1.SSLCertificate) error {
+	if err := c.checkCSR(ctx, cr); err != nil {
+		return err
+	}
+	return c.checkApproverEmail(ctx, cr)
+}
+
+// checkCSR returns an error if Namecheap can't parse cr's CSR, or the CSR's
+// common name isn't cr's domain name. The error and the Activation condition
+// explain why.
+func (c *external) checkCSR(ctx context.Context, cr *v1beta1.SSLCertificate) error {
+	p := cr.Spec.ForProvider
+	certificateType, err := certificateTypeID(cr)
+	if err != nil {
+		return err
+	}
+	details, err := c.service.ParseCSR(ctx, *p.CSR, certificateType)
+	if err != nil {
+		if namecheap.IsCSRRejected(err) {
+			cr.SetConditions(v1beta1.CSRRejected(err.Error()))
+		}
+		return errors.Wrap(err, errParseCSR)
+	}
+
+	if commonName := strings.TrimSuffix(details.CommonName, "."); !strings.EqualFold(commonName, p.DomainName) {
+		msg := fmt.Sprintf("CSR CN %s does not match spec.domainName %s", commonName, p.DomainName)
+		cr.SetConditions(v1beta1.CSRRejected(msg))
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// checkApproverEmail returns an error if the certificate authority doesn't
+// accept cr's approver email for its domain, or a SAN's approver email for
+// the SAN. The addresses it accepts are listed by the error and the
+// Activation condition.
+// Domain control validation by HTTP or DNS doesn't email the approver, so
+// any address is accepted then.
+func (c *external) checkApproverEmail(ctx context.Context, cr *v1beta1.SSLCertificate) error {
+	p := cr.Spec.ForProvider
+	certificateType, err := certificateTypeID(cr)
+	if err != nil {
+		return err
+	}
+
+	if (p.HTTPDCValidation == nil || *p.HTTPDCValidation == "") && (p.DNSValidation == nil || *p.DNSValidation == "") {
+		if err := c.checkApprover(ctx, cr, p.DomainName, *p.ApproverEmail, certificateType); err != nil {
+			return err
+		}
+	}
+	for _, san := range p.SANs {
+		if san.Validation != "" && san.Validation != namecheap.DCVMethodEmail {
+			continue
+		}
+		if san.ApproverEmail == nil {
+			return errors.Errorf("%s: SAN %s has no approver email", errApproverEmail, san.DomainName)
+		}
+		if err := c.checkApprover(ctx, cr, san.DomainName, *san.ApproverEmail, certificateType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkApprover returns an error if the certificate authority doesn't
+// accept email as the approver of domainName, listing the addresses it
+// accepts. The error is all that is reported of a check run by Create, as the
+// managed reconciler discards the Activation condition of a failed Create.
+func (c *external) checkApprover(ctx context.Context, cr *v1beta1.SSLCertificate, domainName, email string, certificateType int) error {
+	emails, err := c.service.GetSSLApproverEmailList(ctx, domainName, certificateType)
+	if err != nil {
+		return errors.Wrap(err, errGetApproverEmails)
+	}
+	if !emails.Accepts(email) {
+		cr.SetConditions(v1beta1.ApproverEmailRejected(email, domainName, emails.All()))
+		return errors.Errorf("%s: %s for %s; use one of: %s", errApproverEmail, email, domainName, strings.Join(emails.All(), ", "))
+	}
+	return nil
+}
+
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
 	cr, ok := mg.(*v1beta1.SSLCertificate)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotSSLCertificate)
 	}
+	ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.SSLCertificateKind)
+
+	if c.renew {
+		return c.renewCertificate(ctx, cr)
+	}
 
 	// SSL certificates are mostly read-only after creation
 	// The main updates would be reissuing or resending approval emails
@@ -251,6 +619,12 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	certificateID := *cr.Status.AtProvider.CertificateID
 
+	if c.switchToDNS {
+		if err := c.switchValidationToDNS(ctx, cr, certificateID); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	}
+
 	// Check for reissue annotation
 	if cr.Annotations != nil {
 		if _, exists := cr.Annotations["namecheap.crossplane.io/reissue"]; exists {
@@ -278,6 +652,106 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	return managed.ExternalUpdate{}, nil
 }
 
+// switchValidationToDNS switches the domain control validation of cr's
+// pending certificate to DNS for its domain name and SANs, and reports the
+// CNAME records to create in the status
+func (c *external) switchValidationToDNS(ctx context.Context, cr *v1beta1.SSLCertificate, certificateID int) error {
+	p := cr.Spec.ForProvider
+	records, err := c.service.EditSSLDCValidation(ctx, certificateID, certificateDomains(cr))
+	if err != nil {
+		return errors.Wrap(err, errEditDCValidation)
+	}
+
+	obs := &cr.Status.AtProvider
+	obs.DNSValidation = *p.DNSValidation
+	obs.DNSValidationRecords = make([]v1beta1.DNSValidationRecord, 0, len(records))
+	for _, r := range records {
+		obs.DNSValidationRecords = append(obs.DNSValidationRecords, v1beta1.DNSValidationRecord{
+			Domain:   r.Domain,
+			HostName: r.HostName,
+			Target:   r.Target,
+		})
+	}
+	c.recorder.Event(cr, event.Normal(reasonDCValidation, fmt.Sprintf(
+		"switched certificate %d to DNS validation; create the CNAME records in status.atProvider.dnsValidationRecords", certificateID)))
+	return nil
+}
+
+// certificateDomains returns the domain name of cr's certificate followed
+// by its SANs, each listed once
+func certificateDomains(cr *v1beta1.SSLCertificate) []string {
+	p := cr.Spec.ForProvider
+	names := []string{p.DomainName}
+	seen := map[string]bool{strings.ToLower(p.DomainName): true}
+	add := func(name string) {
+		if name = strings.TrimSpace(name); name != "" && !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			names = append(names, name)
+		}
+	}
+	if p.SANsToAdd != nil {
+		for _, san := range strings.Split(*p.SANsToAdd, ",") {
+			add(san)
+		}
+	}
+	for _, san := range p.SANs {
+		add(san.DomainName)
+	}
+	return names
+}
+
+// renewCertificate renews cr's certificate, which Namecheap does by
+// purchasing a new certificate. The new certificate replaces the renewed one
+// and, with autoActivate, is activated with the CSR, which is checked before
+// the renewal as it is before a purchase.
+func (c *external) renewCertificate(ctx context.Context, cr *v1beta1.SSLCertificate) (managed.ExternalUpdate, error) {
+	activate := autoActivates(cr)
+	if activate {
+		if err := c.checkActivation(ctx, cr); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	}
+
+	obs := &cr.Status.AtProvider
+	renewedID := *obs.CertificateID
+	sslType := ""
+	if obs.SSLType != nil {
+		sslType = *obs.SSLType
+	}
+	renewal, err := c.service.RenewSSLCertificate(ctx, renewedID, sslType, certificateYears(cr), "")
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errRenewSSLCertificate)
+	}
+
+	// Forget the renewed certificate's dates and status, which the new
+	// one's replace once it is observed, publishing it once issued
+	certificateID := renewal.CertificateID
+	chargedAmount := strconv.FormatFloat(renewal.ChargedAmount, 'f', 2, 64)
+	obs.CertificateID = &certificateID
+	obs.OrderID = &renewal.OrderID
+	obs.TransactionID = &renewal.TransactionID
+	obs.ChargedAmount = &chargedAmount
+	obs.PurchaseDate, obs.ExpireDate, obs.ActivationExpireDate = nil, nil, nil
+	obs.DNSValidationRecords = nil
+	obs.Status = nil
+	meta.SetExternalName(cr, strconv.Itoa(certificateID))
+	c.recorder.Event(cr, event.Normal(reasonRenewal,
+		fmt.Sprintf("renewed certificate %d as certificate %d", renewedID, certificateID)))
+
+	if activate {
+		if err := c.activate(ctx, cr, certificateID); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	}
+
+	return managed.ExternalUpdate{
+		ConnectionDetails: managed.ConnectionDetails{
+			"certificate_id": []byte(strconv.Itoa(certificateID)),
+			"domain_name":    []byte(cr.Spec.ForProvider.DomainName),
+		},
+	}, nil
+}
+
 func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
 	cr, ok := mg.(*v1beta1.SSLCertificate)
 	if !ok {
@@ -288,10 +762,42 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 	// We'll just mark the resource as being deleted
 	cr.SetConditions(xpv1.Deleting())
 
+	// The Namecheap API has no auto-renew setting for certificates, so
+	// disabling renewals is left to the user
+	if cr.Spec.ForProvider.DeletionBehavior != nil && *cr.Spec.ForProvider.DeletionBehavior == v1beta1.DeletionBehaviorDisableRenewals {
+		c.recorder.Event(cr, event.Warning(reasonDeletionBehavior,
+			errors.New("auto-renew cannot be turned off through the Namecheap API; "+
+				"turn it off in the Namecheap dashboard to let the certificate lapse")))
+	}
+
+	// Revoke the certificate only when asked to. A failed revocation is
+	// returned, which keeps the resource until it succeeds.
+	if certificateID, ok := observedCertificateID(cr); ok && revocationPending(cr) {
+		ctx = namecheap.WithResource(ctx, cr.GetNamespace(), v1beta1.SSLCertificateKind)
+		certificateType, err := certificateTypeID(cr)
+		if err != nil {
+			return managed.ExternalDelete{}, err
+		}
+		if err := c.service.RevokeSSLCertificate(ctx, certificateID, certificateType); err != nil {
+			return managed.ExternalDelete{}, errors.Wrap(err, errRevokeSSLCertificate)
+		}
+		c.recorder.Event(cr, event.Normal(reasonRevoked, fmt.Sprintf("revoked certificate %d", certificateID)))
+	}
+
 	return managed.ExternalDelete{}, nil
 }
 
+// revocationPending reports whether cr asks for its certificate to be
+// revoked on deletion and the certificate isn't observed revoked yet
+func revocationPending(cr *v1beta1.SSLCertificate) bool {
+	if cr.Spec.ForProvider.RevokeOnDelete == nil || !*cr.Spec.ForProvider.RevokeOnDelete {
+		return false
+	}
+	status := cr.Status.AtProvider.Status
+	return status == nil || !strings.EqualFold(*status, namecheap.SSLStatusRevoked)
+}
+
 func (c *external) Disconnect(ctx context.Context) error {
 	// No persistent connection to close
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,848 @@
+package sslcertificate
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/internal/controller/common"
+	"github.com/rossigee/provider-namecheap/internal/fakeserver"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap/fake"
+)
+
+// recorder captures the events recorded by an external client.
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+func TestDelete_DeletionBehavior(t *testing.T) {
+	tests := []struct {
+		name     string
+		behavior string
+		events   int
+	}{
+		{name: "OrphanByDefault"},
+		{name: "Orphan", behavior: v1beta1.DeletionBehaviorOrphan},
+		{name: "DisableRenewals", behavior: v1beta1.DeletionBehaviorDisableRenewals, events: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// No deletion behavior of a certificate calls the API
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Errorf("unexpected command %s", r.FormValue("Command"))
+			}))
+			t.Cleanup(server.Close)
+
+			rec := &recorder{}
+			e := &external{
+				service: namecheap.NewClient(namecheap.Config{
+					APIUser:    "testuser",
+					APIKey:     "testkey",
+					Username:   "testuser",
+					ClientIP:   "127.0.0.1",
+					BaseURL:    server.URL,
+					HTTPClient: &http.Client{Timeout: 5 * time.Second},
+				}),
+				recorder: rec,
+			}
+
+			cr := &v1beta1.SSLCertificate{}
+			if tc.behavior != "" {
+				cr.Spec.ForProvider.DeletionBehavior = &tc.behavior
+			}
+
+			_, err := e.Delete(context.Background(), cr)
+			require.NoError(t, err)
+			require.Len(t, rec.events, tc.events)
+			for _, ev := range rec.events {
+				assert.Equal(t, event.TypeWarning, ev.Type)
+				assert.Equal(t, reasonDeletionBehavior, ev.Reason)
+			}
+		})
+	}
+}
+
+func TestObserve_KeepsCreateTimeStatus(t *testing.T) {
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		if fail {
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="5050900">Unhandled exception</Error>
+	</Errors>
+</ApiResponse>`))
+			return
+		}
+		// A response that doesn't report the certificate ID
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<SSLGetInfoResult Status="active" Type="PositiveSSL"><CertificateDetails><CommonName>example.com</CommonName></CertificateDetails></SSLGetInfoResult>
+	</CommandResponse>
+</ApiResponse>`))
+	}))
+	t.Cleanup(server.Close)
+
+	e := &external{
+		service: namecheap.NewClient(namecheap.Config{
+			APIUser:    "testuser",
+			APIKey:     "testkey",
+			Username:   "testuser",
+			ClientIP:   "127.0.0.1",
+			BaseURL:    server.URL,
+			HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		}),
+		recorder: &recorder{},
+	}
+
+	// An active certificate that was already published
+	certificateID, status := 52556, "ACTIVE"
+	cr := &v1beta1.SSLCertificate{}
+	cr.Status.AtProvider.CertificateID = &certificateID
+	cr.Status.AtProvider.Status = &status
+
+	_, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	require.NotNil(t, cr.Status.AtProvider.CertificateID)
+	assert.Equal(t, 52556, *cr.Status.AtProvider.CertificateID)
+	require.NotNil(t, cr.Status.AtProvider.HostName)
+	assert.Equal(t, "example.com", *cr.Status.AtProvider.HostName)
+	observed := cr.Status.AtProvider.DeepCopy()
+
+	// A failed read leaves the observation untouched
+	fail = true
+	_, err = e.Observe(context.Background(), cr)
+	require.Error(t, err)
+	assert.Equal(t, observed, &cr.Status.AtProvider)
+}
+
+func TestObserve_Expired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<SSLGetInfoResult Status="expired" Type="PositiveSSL"><CertificateDetails><CommonName>example.com</CommonName></CertificateDetails></SSLGetInfoResult>
+	</CommandResponse>
+</ApiResponse>`))
+	}))
+	t.Cleanup(server.Close)
+
+	rec := &recorder{}
+	e := &external{
+		service: namecheap.NewClient(namecheap.Config{
+			APIUser:    "testuser",
+			APIKey:     "testkey",
+			Username:   "testuser",
+			ClientIP:   "127.0.0.1",
+			BaseURL:    server.URL,
+			HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		}),
+		recorder: rec,
+		drift:    common.NewDriftEvents(rec, time.Minute),
+	}
+
+	certificateID := 52556
+	cr := &v1beta1.SSLCertificate{}
+	cr.Status.AtProvider.CertificateID = &certificateID
+
+	// Expiry is reported once per sync interval, and never corrected
+	for range 2 {
+		o, err := e.Observe(context.Background(), cr)
+		require.NoError(t, err)
+		assert.True(t, o.ResourceUpToDate)
+	}
+	require.Len(t, rec.events, 1)
+	assert.Equal(t, event.TypeWarning, rec.events[0].Type)
+	assert.Equal(t, common.ReasonDriftDetected, rec.events[0].Reason)
+	assert.Equal(t, `status.atProvider.isExpired drifted: expected "false", observed "true"; `+
+		"not correcting it as expired certificates must be renewed or purchased again", rec.events[0].Message)
+}
+
+func TestCreate_ApproverEmail(t *testing.T) {
+	tests := []struct {
+		name          string
+		approver      string
+		dnsValidation string
+		wantErr       bool
+	}{
+		{name: "Accepted", approver: "Admin@example.com"},
+		{name: "Rejected", approver: "info@example.com", wantErr: true},
+		{name: "DNSValidation", approver: "info@example.com", dnsValidation: "true"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := fakeserver.New(t)
+			h := fakeserver.NewHarness(server, func(client *namecheap.Client) managed.ExternalClient {
+				rec := &recorder{}
+				return &external{service: client, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}
+			})
+
+			autoActivate, csr := true, fakeserver.NewCSR(t, "example.com")
+			cr := &v1beta1.SSLCertificate{}
+			cr.SetName("example")
+			meta.SetExternalName(cr, cr.GetName())
+			cr.Spec.ForProvider.DomainName = "example.com"
+			cr.Spec.ForProvider.CertificateType = 1
+			cr.Spec.ForProvider.AutoActivate = &autoActivate
+			cr.Spec.ForProvider.CSR = &csr
+			cr.Spec.ForProvider.ApproverEmail = &tc.approver
+			if tc.dnsValidation != "" {
+				cr.Spec.ForProvider.DNSValidation = &tc.dnsValidation
+			}
+
+			_, err := h.Reconcile(context.Background(), cr)
+			if !tc.wantErr {
+				require.NoError(t, err)
+				assert.Equal(t, 1, server.Calls(namecheap.CommandSSLActivate))
+
+				// The requested activation is observed once Create's
+				// status is discarded
+				_, err = h.Reconcile(context.Background(), cr)
+				require.NoError(t, err)
+				condition := cr.GetCondition(v1beta1.TypeActivation)
+				assert.Equal(t, v1beta1.ReasonActivationRequested, condition.Reason)
+				assert.Equal(t, corev1.ConditionTrue, condition.Status)
+				return
+			}
+
+			// A certificate that couldn't be activated isn't purchased, and
+			// the accepted addresses are listed
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "info@example.com")
+			assert.Contains(t, err.Error(), "use one of: admin@example.com, administrator@example.com")
+			assert.Zero(t, server.Calls(namecheap.CommandSSLCreate))
+			assert.Zero(t, server.BillableCalls())
+		})
+	}
+}
+
+func TestCreate_SANs(t *testing.T) {
+	approver := func(email string) *string { return &email }
+	tests := []struct {
+		name          string
+		sans          []v1beta1.SSLCertificateSAN
+		wantErr       string
+		wantApprovers []string
+	}{
+		{
+			name: "Activated",
+			sans: []v1beta1.SSLCertificateSAN{
+				{DomainName: "www.example.com", Validation: namecheap.DCVMethodEmail, ApproverEmail: approver("hostmaster@www.example.com")},
+				{DomainName: "example.net", Validation: namecheap.DCVMethodDNS},
+			},
+			wantApprovers: []string{"hostmaster@www.example.com", "CNAMECSRHASH"},
+		},
+		{
+			name: "ApproverRejected",
+			sans: []v1beta1.SSLCertificateSAN{
+				{DomainName: "example.net", Validation: namecheap.DCVMethodEmail, ApproverEmail: approver("admin@example.com")},
+			},
+			wantErr: "admin@example.com for example.net",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := fakeserver.New(t)
+			h := fakeserver.NewHarness(server, func(client *namecheap.Client) managed.ExternalClient {
+				rec := &recorder{}
+				return &external{service: client, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}
+			})
+
+			autoActivate, csr, primary := true, fakeserver.NewCSR(t, "example.com"), "admin@example.com"
+			cr := &v1beta1.SSLCertificate{}
+			cr.SetName("example")
+			meta.SetExternalName(cr, cr.GetName())
+			cr.Spec.ForProvider.DomainName = "example.com"
+			cr.Spec.ForProvider.CertificateType = 1
+			cr.Spec.ForProvider.AutoActivate = &autoActivate
+			cr.Spec.ForProvider.CSR = &csr
+			cr.Spec.ForProvider.ApproverEmail = &primary
+			cr.Spec.ForProvider.SANs = tc.sans
+
+			_, err := h.Reconcile(context.Background(), cr)
+			if tc.wantErr != "" {
+				// A SAN that couldn't be validated isn't purchased
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				assert.Zero(t, server.BillableCalls())
+				return
+			}
+
+			// Each SAN is activated with its own approver or validation
+			require.NoError(t, err)
+			certificates := server.Certificates()
+			require.Len(t, certificates, 1)
+			assert.Equal(t, []string{"www.example.com", "example.net"}, certificates[0].SANs)
+			assert.Equal(t, tc.wantApprovers, certificates[0].SANApprovers)
+		})
+	}
+}
+
+func TestCreate_CertificateTypeName(t *testing.T) {
+	server := fakeserver.New(t)
+	h := fakeserver.NewHarness(server, func(client *namecheap.Client) managed.ExternalClient {
+		rec := &recorder{}
+		return &external{service: client, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}
+	})
+
+	typeName := namecheap.SSLTypeEssentialSSLWildcard
+	cr := &v1beta1.SSLCertificate{}
+	cr.SetName("example")
+	meta.SetExternalName(cr, cr.GetName())
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.CertificateTypeName = &typeName
+
+	// The certificate is purchased by the type's ID
+	_, err := h.Reconcile(context.Background(), cr)
+	require.NoError(t, err)
+	certificates := server.Certificates()
+	require.Len(t, certificates, 1)
+	assert.Equal(t, 36, certificates[0].Type)
+
+	// An unknown name isn't purchased
+	typeName = "CheapSSL"
+	cr = &v1beta1.SSLCertificate{}
+	cr.SetName("unknown")
+	meta.SetExternalName(cr, cr.GetName())
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.CertificateTypeName = &typeName
+	_, err = h.Reconcile(context.Background(), cr)
+	require.Error(t, err)
+	assert.Len(t, server.Certificates(), 1)
+}
+
+func TestCreate_CSR(t *testing.T) {
+	tests := []struct {
+		name    string
+		csr     string
+		message string
+	}{
+		{name: "Valid", csr: fakeserver.NewCSR(t, "Example.com")},
+		{name: "CommonNameMismatch", csr: fakeserver.NewCSR(t, "example.org"), message: "CSR CN example.org does not match spec.domainName example.com"},
+		{name: "Unparseable", csr: "-----BEGIN CERTIFICATE REQUEST-----", message: "Invalid CSR"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := fakeserver.New(t)
+			h := fakeserver.NewHarness(server, func(client *namecheap.Client) managed.ExternalClient {
+				rec := &recorder{}
+				return &external{service: client, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}
+			})
+
+			autoActivate, approver := true, "admin@example.com"
+			cr := &v1beta1.SSLCertificate{}
+			cr.SetName("example")
+			meta.SetExternalName(cr, cr.GetName())
+			cr.Spec.ForProvider.DomainName = "example.com"
+			cr.Spec.ForProvider.CertificateType = 1
+			cr.Spec.ForProvider.AutoActivate = &autoActivate
+			cr.Spec.ForProvider.CSR = &tc.csr
+			cr.Spec.ForProvider.ApproverEmail = &approver
+
+			_, err := h.Reconcile(context.Background(), cr)
+			if tc.message == "" {
+				require.NoError(t, err)
+				assert.Equal(t, 1, server.Calls(namecheap.CommandSSLActivate))
+				return
+			}
+
+			// A certificate that couldn't be activated isn't purchased
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.message)
+			assert.Zero(t, server.BillableCalls())
+		})
+	}
+}
+
+func TestUpdate_Renewal(t *testing.T) {
+	server := fakeserver.New(t)
+	rec := &recorder{}
+	h := fakeserver.NewHarness(server, func(client *namecheap.Client) managed.ExternalClient {
+		return &external{service: client, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}
+	})
+
+	autoActivate, csr, approver, renewBeforeDays := true, fakeserver.NewCSR(t, "example.com"), "admin@example.com", 30
+	cr := &v1beta1.SSLCertificate{}
+	cr.SetName("example")
+	meta.SetExternalName(cr, cr.GetName())
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.CertificateType = 1
+	cr.Spec.ForProvider.AutoActivate = &autoActivate
+	cr.Spec.ForProvider.CSR = &csr
+	cr.Spec.ForProvider.ApproverEmail = &approver
+	cr.Spec.ForProvider.RenewBeforeDays = &renewBeforeDays
+
+	ctx := context.Background()
+	reconcile := func() managed.ExternalObservation {
+		t.Helper()
+		obs, err := h.Reconcile(ctx, cr)
+		require.NoError(t, err)
+		return obs
+	}
+
+	// A certificate activated for a year isn't due for renewal
+	reconcile()
+	assert.True(t, reconcile().ResourceUpToDate)
+	renewed := *cr.Status.AtProvider.CertificateID
+	assert.Equal(t, 1, server.BillableCalls())
+
+	// Once it expires within the window, it is renewed and the new
+	// certificate activated in its place
+	server.SetCertificateExpires(renewed, time.Now().Add(10*24*time.Hour))
+	assert.False(t, reconcile().ResourceUpToDate)
+	assert.Equal(t, 2, server.Calls(namecheap.CommandSSLActivate))
+	certificateID := *cr.Status.AtProvider.CertificateID
+	assert.NotEqual(t, renewed, certificateID)
+	assert.Equal(t, strconv.Itoa(certificateID), meta.GetExternalName(cr))
+	require.NotNil(t, cr.Status.AtProvider.ChargedAmount)
+	assert.Equal(t, "9.00", *cr.Status.AtProvider.ChargedAmount)
+	require.NotEmpty(t, rec.events)
+	assert.Equal(t, reasonRenewal, rec.events[len(rec.events)-1].Reason)
+
+	for _, c := range server.Certificates() {
+		if c.ID == certificateID {
+			assert.Equal(t, renewed, c.RenewedFrom)
+			assert.Equal(t, "ACTIVE", c.Status)
+		}
+	}
+
+	// and the new certificate isn't renewed again
+	for range 2 {
+		assert.True(t, reconcile().ResourceUpToDate)
+	}
+	assert.Equal(t, 2, server.BillableCalls())
+	assert.Equal(t, 1, server.Calls(namecheap.CommandSSLRenew))
+}
+
+func TestObserve_IssuedCertificate(t *testing.T) {
+	server := fakeserver.New(t)
+	rec := &recorder{}
+	h := fakeserver.NewHarness(server, func(client *namecheap.Client) managed.ExternalClient {
+		return &external{service: client, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}
+	})
+
+	autoActivate, csr, approver, renewBeforeDays := true, fakeserver.NewCSR(t, "example.com"), "admin@example.com", 30
+	cr := &v1beta1.SSLCertificate{}
+	cr.SetName("example")
+	meta.SetExternalName(cr, cr.GetName())
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.CertificateType = 1
+	cr.Spec.ForProvider.AutoActivate = &autoActivate
+	cr.Spec.ForProvider.CSR = &csr
+	cr.Spec.ForProvider.ApproverEmail = &approver
+	cr.Spec.ForProvider.RenewBeforeDays = &renewBeforeDays
+
+	ctx := context.Background()
+	reconcile := func() managed.ExternalObservation {
+		t.Helper()
+		obs, err := h.Reconcile(ctx, cr)
+		require.NoError(t, err)
+		return obs
+	}
+	published := func(certificateID int) managed.ConnectionDetails {
+		return managed.ConnectionDetails{
+			"tls.crt":   []byte(fakeserver.IssuedCertificate(certificateID)),
+			"ca.crt":    []byte(fakeserver.IntermediateCertificate + fakeserver.RootCertificate),
+			"chain.crt": []byte(fakeserver.IssuedCertificate(certificateID) + fakeserver.IntermediateCertificate),
+		}
+	}
+
+	// The certificate is published once it is active
+	assert.Nil(t, reconcile().ConnectionDetails)
+	certificateID := server.Certificates()[0].ID
+	assert.Equal(t, published(certificateID), reconcile().ConnectionDetails)
+
+	// and not downloaded again while it stays active
+	assert.Nil(t, reconcile().ConnectionDetails)
+
+	// A renewal's certificate is published in its place
+	server.SetCertificateExpires(certificateID, time.Now().Add(10*24*time.Hour))
+	assert.Nil(t, reconcile().ConnectionDetails)
+	renewal := *cr.Status.AtProvider.CertificateID
+	assert.NotEqual(t, certificateID, renewal)
+	assert.Equal(t, published(renewal), reconcile().ConnectionDetails)
+	assert.Nil(t, reconcile().ConnectionDetails)
+}
+
+func TestRenewalDue(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	days := 30
+	tests := []struct {
+		name            string
+		renewBeforeDays *int
+		status          string
+		expired         bool
+		expires         time.Time
+		want            bool
+	}{
+		{name: "NoWindow", status: "ACTIVE", expires: now.AddDate(0, 0, 10)},
+		{name: "NoExpiry", renewBeforeDays: &days, status: "ACTIVE"},
+		{name: "OutsideWindow", renewBeforeDays: &days, status: "ACTIVE", expires: now.AddDate(0, 0, 31)},
+		{name: "InsideWindow", renewBeforeDays: &days, status: "active", expires: now.AddDate(0, 0, 30), want: true},
+		{name: "Expired", renewBeforeDays: &days, status: "EXPIRED", expired: true, expires: now.AddDate(0, 0, -1), want: true},
+		{name: "NotActive", renewBeforeDays: &days, status: "REPLACED", expires: now.AddDate(0, 0, 10)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, renewalDue(tc.renewBeforeDays, tc.status, tc.expired, tc.expires, now))
+		})
+	}
+}
+
+func TestDelete_RevokeOnDelete(t *testing.T) {
+	server := fakeserver.New(t)
+	rec := &recorder{}
+	h := fakeserver.NewHarness(server, func(client *namecheap.Client) managed.ExternalClient {
+		return &external{service: client, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}
+	})
+	e := &external{service: server.Client(), recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}
+
+	newCertificate := func(revoke *bool) *v1beta1.SSLCertificate {
+		t.Helper()
+		cr := &v1beta1.SSLCertificate{}
+		cr.SetName("example")
+		meta.SetExternalName(cr, cr.GetName())
+		cr.Spec.ForProvider.DomainName = "example.com"
+		cr.Spec.ForProvider.CertificateType = 1
+		cr.Spec.ForProvider.RevokeOnDelete = revoke
+		_, err := h.Reconcile(context.Background(), cr)
+		require.NoError(t, err)
+		now := metav1.Now()
+		cr.SetDeletionTimestamp(&now)
+		return cr
+	}
+
+	// deleteCertificate deletes cr as the managed reconciler does, returning
+	// whether the resource is gone
+	deleteCertificate := func(cr *v1beta1.SSLCertificate) bool {
+		t.Helper()
+		obs, err := e.Observe(context.Background(), cr)
+		require.NoError(t, err)
+		if obs.ResourceExists {
+			_, err = e.Delete(context.Background(), cr)
+			require.NoError(t, err)
+		}
+		obs, err = e.Observe(context.Background(), cr)
+		require.NoError(t, err)
+		return !obs.ResourceExists
+	}
+
+	// By default the certificate is left untouched
+	cr := newCertificate(nil)
+	assert.True(t, deleteCertificate(cr))
+	assert.Zero(t, server.Calls(namecheap.CommandSSLRevoke))
+
+	// and otherwise revoked
+	revoke := true
+	cr = newCertificate(&revoke)
+	assert.True(t, deleteCertificate(cr))
+	assert.Equal(t, 1, server.Calls(namecheap.CommandSSLRevoke))
+	assert.Equal(t, "revoked", *cr.Status.AtProvider.Status)
+	assert.Equal(t, reasonRevoked, rec.events[len(rec.events)-1].Reason)
+}
+
+func TestDelete_RevokeFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		if r.FormValue("Command") == namecheap.CommandSSLRevoke.String() {
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="5050900">Unhandled exception</Error>
+	</Errors>
+</ApiResponse>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<SSLGetInfoResult Status="active" Type="PositiveSSL"><CertificateDetails><CommonName>example.com</CommonName></CertificateDetails></SSLGetInfoResult>
+	</CommandResponse>
+</ApiResponse>`))
+	}))
+	t.Cleanup(server.Close)
+
+	rec := &recorder{}
+	e := &external{
+		service: namecheap.NewClient(namecheap.Config{
+			APIUser:    "testuser",
+			APIKey:     "testkey",
+			Username:   "testuser",
+			ClientIP:   "127.0.0.1",
+			BaseURL:    server.URL,
+			HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		}),
+		recorder: rec,
+		drift:    common.NewDriftEvents(rec, time.Minute),
+	}
+
+	certificateID, revoke := 52556, true
+	cr := &v1beta1.SSLCertificate{}
+	cr.Status.AtProvider.CertificateID = &certificateID
+	cr.Spec.ForProvider.RevokeOnDelete = &revoke
+	now := metav1.Now()
+	cr.SetDeletionTimestamp(&now)
+
+	// A failed revocation keeps the resource
+	_, err := e.Delete(context.Background(), cr)
+	require.Error(t, err)
+	obs, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, obs.ResourceExists)
+
+	// until revoking is turned off
+	revoke = false
+	obs, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, obs.ResourceExists)
+}
+
+func TestUpdate_SwitchToDNSValidation(t *testing.T) {
+	var dnsNames []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		if r.FormValue("Command") == namecheap.CommandSSLEditDCValidation.String() {
+			dnsNames = append(dnsNames, r.FormValue("DNSNames"))
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<SSLEditDCVResult ID="52556" IsSuccess="true">
+			<DNSDCValidation>
+				<DNS domain="example.com"><HostName>_abc.example.com</HostName><Target>abc.comodoca.com</Target></DNS>
+				<DNS domain="www.example.com"><HostName>_abc.www.example.com</HostName><Target>abc.comodoca.com</Target></DNS>
+			</DNSDCValidation>
+		</SSLEditDCVResult>
+	</CommandResponse>
+</ApiResponse>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<SSLGetInfoResult Status="purchased" Type="PositiveSSL"><CertificateDetails><CommonName>example.com</CommonName></CertificateDetails></SSLGetInfoResult>
+	</CommandResponse>
+</ApiResponse>`))
+	}))
+	t.Cleanup(server.Close)
+
+	rec := &recorder{}
+	e := &external{
+		service: namecheap.NewClient(namecheap.Config{
+			APIUser:    "testuser",
+			APIKey:     "testkey",
+			Username:   "testuser",
+			ClientIP:   "127.0.0.1",
+			BaseURL:    server.URL,
+			HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		}),
+		recorder: rec,
+		drift:    common.NewDriftEvents(rec, time.Minute),
+	}
+
+	certificateID, sans := 52556, "www.example.com"
+	cr := &v1beta1.SSLCertificate{}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.SANsToAdd = &sans
+	cr.Status.AtProvider.CertificateID = &certificateID
+
+	// Nothing is switched before activation was requested
+	dnsValidation := "true"
+	cr.Spec.ForProvider.DNSValidation = &dnsValidation
+	o, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, o.ResourceUpToDate)
+
+	// A pending activation is switched to DNS validation once
+	cr.SetConditions(v1beta1.ActivationRequested())
+	o, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, o.ResourceUpToDate)
+	_, err = e.Update(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com,www.example.com"}, dnsNames)
+	assert.Equal(t, []v1beta1.DNSValidationRecord{
+		{Domain: "example.com", HostName: "_abc.example.com", Target: "abc.comodoca.com"},
+		{Domain: "www.example.com", HostName: "_abc.www.example.com", Target: "abc.comodoca.com"},
+	}, cr.Status.AtProvider.DNSValidationRecords)
+	assert.Equal(t, reasonDCValidation, rec.events[len(rec.events)-1].Reason)
+
+	o, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.True(t, o.ResourceUpToDate)
+
+	// and again when dnsValidation changes
+	dnsValidation = "CNAME_CSR_HASH"
+	o, err = e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	assert.False(t, o.ResourceUpToDate)
+}
+
+// fakeManager provides the client and scheme a managed reconciler needs
+type fakeManager struct {
+	manager.Manager
+	client client.Client
+}
+
+func (m *fakeManager) GetClient() client.Client {
+	return m.client
+}
+
+func (m *fakeManager) GetScheme() *runtime.Scheme {
+	return m.client.Scheme()
+}
+
+// TestReconcile_InsufficientFunds runs a certificate whose purchase the
+// balance doesn't cover through the managed reconciler, which discards the
+// status of a failed Create
+func TestReconcile_InsufficientFunds(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	cr := &v1beta1.SSLCertificate{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default", Generation: 1}}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.CertificateType = 1
+	kube := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(cr).WithStatusSubresource(cr).Build()
+
+	purchases := 0
+	api := &fake.SSLAPI{
+		MockCreateSSLCertificate: func(context.Context, int, int, string) (int, error) {
+			purchases++
+			return 0, namecheap.ErrInsufficientFunds{Required: 9, Available: 5, Currency: "USD"}
+		},
+	}
+	rec := &recorder{}
+	connector := managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+		return &external{service: api, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}, nil
+	})
+	r := managed.NewReconciler(&fakeManager{client: kube},
+		resource.ManagedKind(v1beta1.SSLCertificateGroupVersionKind),
+		managed.WithExternalConnector(connector))
+
+	// The purchase is refused once, then not attempted again for the same
+	// spec
+	for range 3 {
+		_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, purchases)
+	insufficient := 0
+	for _, e := range rec.events {
+		if e.Reason == common.ReasonInsufficientFunds {
+			insufficient++
+		}
+	}
+	assert.Equal(t, 1, insufficient)
+}
+
+// TestReconcile_SwitchToDNSValidation runs a purchased certificate pending
+// email validation through the managed reconciler, which discards the status
+// Create sets when it activates the certificate
+func TestReconcile_SwitchToDNSValidation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	autoActivate, csr, approver := true, "csr", "admin@example.com"
+	cr := &v1beta1.SSLCertificate{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default", Generation: 1}}
+	cr.Spec.ForProvider.DomainName = "example.com"
+	cr.Spec.ForProvider.CertificateType = 1
+	cr.Spec.ForProvider.AutoActivate = &autoActivate
+	cr.Spec.ForProvider.CSR = &csr
+	cr.Spec.ForProvider.ApproverEmail = &approver
+	kube := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(cr).WithStatusSubresource(cr).Build()
+
+	var switched [][]string
+	api := &fake.SSLAPI{
+		MockParseCSR: func(context.Context, string, int) (*namecheap.CSRDetails, error) {
+			return &namecheap.CSRDetails{CommonName: "example.com"}, nil
+		},
+		MockGetSSLApproverEmailList: func(context.Context, string, int) (*namecheap.SSLApproverEmails, error) {
+			return &namecheap.SSLApproverEmails{Generic: []string{"admin@example.com"}}, nil
+		},
+		MockCreateSSLCertificate: func(context.Context, int, int, string) (int, error) {
+			return 52556, nil
+		},
+		MockActivateSSLCertificate: func(context.Context, int, namecheap.ActivationRequest) error {
+			return nil
+		},
+		MockGetSSLCertificate: func(context.Context, int) (*namecheap.SSLGetInfoResponse, error) {
+			resp := &namecheap.SSLGetInfoResponse{}
+			resp.CommandResponse.SSLGetInfoResult.Status = "purchased"
+			resp.CommandResponse.SSLGetInfoResult.CertificateDetails.CommonName = "example.com"
+			return resp, nil
+		},
+		MockEditSSLDCValidation: func(_ context.Context, _ int, dnsNames []string) ([]namecheap.DNSValidationRecord, error) {
+			switched = append(switched, dnsNames)
+			return []namecheap.DNSValidationRecord{{Domain: "example.com", HostName: "_abc.example.com", Target: "abc.comodoca.com"}}, nil
+		},
+	}
+	rec := &recorder{}
+	connector := managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+		return &external{service: api, recorder: rec, drift: common.NewDriftEvents(rec, time.Minute)}, nil
+	})
+	r := managed.NewReconciler(&fakeManager{client: kube},
+		resource.ManagedKind(v1beta1.SSLCertificateGroupVersionKind),
+		managed.WithExternalConnector(connector))
+
+	ctx := context.Background()
+	reconcile := func() {
+		t.Helper()
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+		require.NoError(t, err)
+	}
+
+	// The certificate is purchased and activated for email validation
+	reconcile()
+	reconcile()
+	assert.Empty(t, switched)
+
+	// Asking for DNS validation switches it once
+	got := &v1beta1.SSLCertificate{}
+	require.NoError(t, kube.Get(ctx, client.ObjectKeyFromObject(cr), got))
+	dnsValidation := "true"
+	got.Spec.ForProvider.DNSValidation = &dnsValidation
+	got.SetGeneration(2)
+	require.NoError(t, kube.Update(ctx, got))
+	reconcile()
+	reconcile()
+	assert.Equal(t, [][]string{{"example.com"}}, switched)
+
+	require.NoError(t, kube.Get(ctx, client.ObjectKeyFromObject(cr), got))
+	assert.Equal(t, "true", got.Status.AtProvider.DNSValidation)
+	assert.Len(t, got.Status.AtProvider.DNSValidationRecords, 1)
+}
@@ -0,0 +1,86 @@
+package fakeserver
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+// A Connector returns a controller's external client using a Namecheap
+// client, as the controller's Connect would with real credentials
+type Connector func(client *namecheap.Client) managed.ExternalClient
+
+// Harness drives a controller's external client against a Server the way
+// the managed reconciler does, one reconcile at a time. Restart discards
+// the external client and the client-side state behind it, so that tests
+// can check that a restarted provider neither orders anything twice nor
+// loses track of what it ordered.
+type Harness struct {
+	server   *Server
+	connect  Connector
+	external managed.ExternalClient
+}
+
+// NewHarness returns a harness connected to the server
+func NewHarness(server *Server, connect Connector) *Harness {
+	return &Harness{server: server, connect: connect, external: connect(server.Client())}
+}
+
+// Reconcile observes the resource, then creates it if it doesn't exist or
+// updates it if it isn't up to date, returning the observation. As the
+// managed reconciler does, a create is recorded in the resource's
+// annotations, which are kept along with any others Create sets, while its
+// status is reset to the one stored before the reconcile, whether or not
+// the create succeeded.
+func (h *Harness) Reconcile(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	stored := mg.DeepCopyObject()
+
+	obs, err := h.external.Observe(ctx, mg)
+	if err != nil {
+		return obs, err
+	}
+
+	switch {
+	case !obs.ResourceExists:
+		meta.SetExternalCreatePending(mg, time.Now())
+		_, err := h.external.Create(ctx, mg)
+		restoreStatus(mg, stored)
+		if err != nil {
+			meta.SetExternalCreateFailed(mg, time.Now())
+			return obs, err
+		}
+		meta.SetExternalCreateSucceeded(mg, time.Now())
+	case !obs.ResourceUpToDate:
+		if _, err := h.external.Update(ctx, mg); err != nil {
+			return obs, err
+		}
+	}
+	return obs, nil
+}
+
+// restoreStatus sets the status of mg to that of stored, as the managed
+// reconciler's update of a created resource's critical annotations does by
+// decoding the resource the API server stored
+func restoreStatus(mg resource.Managed, stored runtime.Object) {
+	status := reflect.ValueOf(mg).Elem().FieldByName("Status")
+	status.Set(reflect.ValueOf(stored).Elem().FieldByName("Status"))
+}
+
+// Restart simulates a provider restart: the server moves to a new URL, which
+// leaves every client-side cache behind, and a new external client is
+// connected to it. The account's state is kept.
+func (h *Harness) Restart() {
+	if err := h.external.Disconnect(context.Background()); err != nil {
+		h.server.t.Errorf("cannot disconnect external client: %v", err)
+	}
+	h.server.Restart()
+	h.external = h.connect(h.server.Client())
+}
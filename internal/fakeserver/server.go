@@ -0,0 +1,729 @@
+// Package fakeserver provides a stateful fake of the Namecheap API for tests.
+// Unlike the per-test handlers that answer each command with a canned
+// response, it keeps an account's domains, DNS host records, WhoisGuard
+// subscriptions, SSL certificates and domain transfers across requests and
+// restarts, so that tests can drive controllers through several reconciles
+// and check what they ordered.
+package fakeserver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+// Domain is a domain registered in the fake account
+type Domain struct {
+	ID      int
+	Name    string
+	Created time.Time
+	Expires time.Time
+
+	// Locked is the registrar lock
+	Locked bool
+
+	// Nameservers are the domain's custom nameservers. A domain without
+	// any uses Namecheap DNS.
+	Nameservers []string
+
+	Contacts namecheap.DomainContacts
+	Hosts    []namecheap.DNSRecord
+
+	// EmailType is the email type set along with the host records
+	EmailType string
+
+	WhoisGuardID     int
+	WhoisGuardStatus string
+}
+
+// Certificate is an SSL certificate purchased in the fake account
+type Certificate struct {
+	ID       int
+	Type     int
+	Years    int
+	Status   string
+	HostName string
+	// Expires is set when the certificate is activated
+	Expires time.Time
+	// RenewedFrom is the ID of the certificate this one renewed
+	RenewedFrom int
+	// SANs and their approvers, or validation placeholders, are set when
+	// the certificate is activated
+	SANs         []string
+	SANApprovers []string
+}
+
+// Transfer is a domain transfer ordered in the fake account
+type Transfer struct {
+	ID         int
+	DomainName string
+	Years      int
+	EPPCode    string
+	StatusID   namecheap.TransferStatusID
+	Status     string
+}
+
+// Server is a fake Namecheap API serving one account. Its state survives
+// Restart, which moves it to a new URL as a restarted provider would see a
+// new client: every client-side cache is keyed by the API endpoint, so none
+// of them carries over.
+type Server struct {
+	t testing.TB
+
+	mu           sync.Mutex
+	srv          *httptest.Server
+	nextID       int
+	domains      map[string]*Domain
+	certificates map[int]*Certificate
+	transfers    map[int]*Transfer
+	calls        map[namecheap.Command]int
+}
+
+// New starts a fake Namecheap API with an empty account. It is closed when
+// the test finishes.
+func New(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		t:            t,
+		nextID:       100,
+		domains:      map[string]*Domain{},
+		certificates: map[int]*Certificate{},
+		transfers:    map[int]*Transfer{},
+		calls:        map[namecheap.Command]int{},
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(func() { s.close() })
+	return s
+}
+
+// URL returns the endpoint the server is currently listening on
+func (s *Server) URL() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.srv.URL
+}
+
+// Restart stops the server and starts it again on a new URL, keeping the
+// account's state
+func (s *Server) Restart() {
+	s.close()
+	srv := httptest.NewServer(http.HandlerFunc(s.serve))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.srv = srv
+}
+
+func (s *Server) close() {
+	s.mu.Lock()
+	srv := s.srv
+	s.mu.Unlock()
+	srv.Close()
+}
+
+// Client returns a new client of the server's current URL, without the
+// rate limits that would slow tests down
+func (s *Server) Client() *namecheap.Client {
+	return namecheap.NewClient(namecheap.Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    s.URL(),
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		RateLimitConfig: &namecheap.RateLimitConfig{
+			RequestsPerSecond: 1000,
+			BurstSize:         1000,
+			MaxRetries:        1,
+			RetryDelay:        time.Millisecond,
+		},
+		DomainWriteInterval: -1,
+	})
+}
+
+// AddDomain registers a domain in the account without going through the
+// API, for tests of resources that need an existing domain
+func (s *Server) AddDomain(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.register(strings.ToLower(name), 1)
+}
+
+// Domain returns a copy of a domain in the account, or nil if it isn't
+// registered
+func (s *Server) Domain(name string) *Domain {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.domains[strings.ToLower(name)]
+	if !ok {
+		return nil
+	}
+	c := *d
+	c.Nameservers = append([]string(nil), d.Nameservers...)
+	c.Hosts = append([]namecheap.DNSRecord(nil), d.Hosts...)
+	return &c
+}
+
+// Certificates returns copies of the certificates in the account
+func (s *Server) Certificates() []Certificate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	certificates := make([]Certificate, 0, len(s.certificates))
+	for _, c := range s.certificates {
+		certificates = append(certificates, *c)
+	}
+	return certificates
+}
+
+// Transfers returns copies of the transfers in the account, in the order
+// they were ordered
+func (s *Server) Transfers() []Transfer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transfers := make([]Transfer, 0, len(s.transfers))
+	for _, t := range s.transfers {
+		transfers = append(transfers, *t)
+	}
+	slices.SortFunc(transfers, func(a, b Transfer) int { return a.ID - b.ID })
+	return transfers
+}
+
+// SetTransferStatus moves a transfer to a new status, as its registries
+// would. A completed transfer adds the domain to the account.
+func (s *Server) SetTransferStatus(id int, statusID namecheap.TransferStatusID, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.transfers[id]
+	if !ok {
+		s.t.Errorf("fake Namecheap API has no transfer %d", id)
+		return
+	}
+	t.StatusID, t.Status = statusID, status
+	if statusID == namecheap.TransferStatusCompleted {
+		s.register(strings.ToLower(t.DomainName), t.Years)
+	}
+}
+
+// SetCertificateExpires moves the expiry of a certificate, as time passing
+// would
+func (s *Server) SetCertificateExpires(id int, expires time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.certificates[id]
+	if !ok {
+		s.t.Errorf("fake Namecheap API has no certificate %d", id)
+		return
+	}
+	c.Expires = expires
+}
+
+// Calls returns the number of requests received for a command
+func (s *Server) Calls(command namecheap.Command) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[command]
+}
+
+// BillableCalls returns the number of requests received for commands that
+// place a charged order. The server fulfils and charges every order, even
+// one that duplicates an earlier order, so that duplicates show up here.
+func (s *Server) BillableCalls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for command, calls := range s.calls {
+		if command.Category() == namecheap.CategoryBillable {
+			n += calls
+		}
+	}
+	return n
+}
+
+// id returns a new ID for an object in the account
+func (s *Server) id() int {
+	s.nextID++
+	return s.nextID
+}
+
+// register adds a domain to the account, with a disabled WhoisGuard
+// subscription as Namecheap allots one to every new registration
+func (s *Server) register(name string, years int) *Domain {
+	created := time.Now().UTC().Truncate(time.Second)
+	d := &Domain{
+		ID:               s.id(),
+		Name:             name,
+		Created:          created,
+		Expires:          created.AddDate(years, 0, 0),
+		WhoisGuardID:     s.id(),
+		WhoisGuardStatus: "DISABLED",
+	}
+	s.domains[name] = d
+	return d
+}
+
+// domain returns the domain a request names, by DomainName or by SLD and
+// TLD, or writes a "Domain not found" error response
+func (s *Server) domain(w http.ResponseWriter, form url.Values) (*Domain, bool) {
+	name := form.Get("DomainName")
+	if name == "" {
+		name = form.Get("SLD") + "." + form.Get("TLD")
+	}
+	d, ok := s.domains[strings.ToLower(name)]
+	if !ok {
+		writeError(w, "2019166", "Domain not found")
+	}
+	return d, ok
+}
+
+// whoisGuard returns the domain whose WhoisGuard subscription a request
+// names, or writes an error response
+func (s *Server) whoisGuard(w http.ResponseWriter, form url.Values) (*Domain, bool) {
+	id, _ := strconv.Atoi(form.Get("WhoisguardID"))
+	for _, d := range s.domains {
+		if d.WhoisGuardID == id {
+			return d, true
+		}
+	}
+	writeError(w, "2019166", "Domain not found")
+	return nil, false
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	form := r.Form
+	command := namecheap.Command(form.Get("Command"))
+	s.calls[command]++
+
+	switch command {
+	case namecheap.CommandDomainsGetTLDList:
+		writeOK(w, `<Tlds>`+tld("com")+tld("net")+tld("org")+`</Tlds>`)
+
+	case namecheap.CommandDomainsCreate:
+		name := strings.ToLower(form.Get("DomainName"))
+		years, _ := strconv.Atoi(form.Get("Years"))
+		d := s.register(name, years)
+		d.Contacts = contacts(form)
+		if form.Get("WGEnabled") == "yes" {
+			d.WhoisGuardStatus = "ENABLED"
+		}
+		writeOK(w, fmt.Sprintf(`<DomainCreateResult Domain="%s" Registered="true" ChargedAmount="20.87" DomainID="%d" OrderID="%d" TransactionID="%d"/>`,
+			escape(name), d.ID, s.id(), s.id()))
+
+	case namecheap.CommandDomainsRenew:
+		d, ok := s.domain(w, form)
+		if !ok {
+			return
+		}
+		years, _ := strconv.Atoi(form.Get("Years"))
+		d.Expires = d.Expires.AddDate(years, 0, 0)
+		writeOK(w, fmt.Sprintf(`<DomainRenewResult DomainName="%s" DomainID="%d" Renew="true" ChargedAmount="20.87" OrderID="%d" TransactionID="%d"/>`,
+			escape(d.Name), d.ID, s.id(), s.id()))
+
+	case namecheap.CommandDomainsGetInfo:
+		d, ok := s.domain(w, form)
+		if !ok {
+			return
+		}
+		dns := `<DnsDetails ProviderType="FREE" IsUsingOurDNS="true"><Nameserver>dns1.registrar-servers.com</Nameserver><Nameserver>dns2.registrar-servers.com</Nameserver></DnsDetails>`
+		if len(d.Nameservers) > 0 {
+			dns = `<DnsDetails ProviderType="CUSTOM" IsUsingOurDNS="false">`
+			for _, ns := range d.Nameservers {
+				dns += `<Nameserver>` + escape(ns) + `</Nameserver>`
+			}
+			dns += `</DnsDetails>`
+		}
+		writeOK(w, fmt.Sprintf(`<DomainGetInfoResult Status="Ok" ID="%d" DomainName="%s" OwnerName="%s" IsOwner="true" IsPremium="false">`+
+			`<DomainDetails><CreatedDate>%s</CreatedDate><ExpiredDate>%s</ExpiredDate></DomainDetails>`+
+			`<LockDetails TransferOutPending="false"/><DomainStatuses/>%s</DomainGetInfoResult>`,
+			d.ID, escape(d.Name), escape(form.Get("UserName")), d.Created.Format("01/02/2006"), d.Expires.Format("01/02/2006"), dns))
+
+	case namecheap.CommandDomainsGetRegistrarLock:
+		d, ok := s.domain(w, form)
+		if !ok {
+			return
+		}
+		writeOK(w, fmt.Sprintf(`<DomainGetRegistrarLockResult Domain="%s" RegistrarLockStatus="%t"/>`, escape(d.Name), d.Locked))
+
+	case namecheap.CommandDomainsSetRegistrarLock:
+		d, ok := s.domain(w, form)
+		if !ok {
+			return
+		}
+		d.Locked = form.Get("LockAction") == "LOCK"
+		writeOK(w, fmt.Sprintf(`<DomainSetRegistrarLockResult Domain="%s" IsSuccess="true"/>`, escape(d.Name)))
+
+	case namecheap.CommandDomainsGetContacts:
+		d, ok := s.domain(w, form)
+		if !ok {
+			return
+		}
+		result, err := xml.Marshal(struct {
+			XMLName xml.Name `xml:"DomainContactsResult"`
+			Domain  string   `xml:"Domain,attr"`
+			namecheap.DomainContacts
+		}{Domain: d.Name, DomainContacts: d.Contacts})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeOK(w, string(result))
+
+	case namecheap.CommandDomainsSetContacts:
+		d, ok := s.domain(w, form)
+		if !ok {
+			return
+		}
+		d.Contacts = contacts(form)
+		writeOK(w, fmt.Sprintf(`<DomainSetContactResult Domain="%s" IsSuccess="true"/>`, escape(d.Name)))
+
+	case namecheap.CommandDomainsDNSSetCustom:
+		d, ok := s.domain(w, form)
+		if !ok {
+			return
+		}
+		d.Nameservers = strings.Split(form.Get("Nameservers"), ",")
+		writeOK(w, fmt.Sprintf(`<DomainDNSSetCustomResult Domain="%s" Update="true"/>`, escape(d.Name)))
+
+	case namecheap.CommandDomainsDNSSetDefault:
+		d, ok := s.domain(w, form)
+		if !ok {
+			return
+		}
+		d.Nameservers = nil
+		writeOK(w, fmt.Sprintf(`<DomainDNSSetDefaultResult Domain="%s" Updated="true"/>`, escape(d.Name)))
+
+	case namecheap.CommandDomainsDNSGetHosts:
+		d, ok := s.domain(w, form)
+		if !ok {
+			return
+		}
+		if len(d.Nameservers) > 0 {
+			writeError(w, "2030288", "Domain is not using Namecheap DNS")
+			return
+		}
+		hosts := ""
+		for _, h := range d.Hosts {
+			hosts += fmt.Sprintf(`<host HostId="%d" Name="%s" Type="%s" Address="%s" MXPref="%d" TTL="%d"/>`,
+				h.HostID, escape(h.Name), escape(h.Type), escape(h.Address), h.MXPref, h.TTL)
+		}
+		writeOK(w, fmt.Sprintf(`<DomainDNSGetHostsResult Domain="%s" EmailType="%s" IsUsingOurDNS="true">%s</DomainDNSGetHostsResult>`,
+			escape(d.Name), escape(d.EmailType), hosts))
+
+	case namecheap.CommandDomainsDNSSetHosts:
+		d, ok := s.domain(w, form)
+		if !ok {
+			return
+		}
+		d.Hosts = nil
+		d.EmailType = form.Get("EmailType")
+		for i := 1; form.Has("HostName" + strconv.Itoa(i)); i++ {
+			n := strconv.Itoa(i)
+			mxPref, _ := strconv.Atoi(form.Get("MXPref" + n))
+			ttl, _ := strconv.Atoi(form.Get("TTL" + n))
+			d.Hosts = append(d.Hosts, namecheap.DNSRecord{
+				HostID:  s.id(),
+				Name:    form.Get("HostName" + n),
+				Type:    form.Get("RecordType" + n),
+				Address: form.Get("Address" + n),
+				MXPref:  mxPref,
+				TTL:     ttl,
+			})
+		}
+		writeOK(w, fmt.Sprintf(`<DomainDNSSetHostsResult Domain="%s" IsSuccess="true"/>`, escape(d.Name)))
+
+	case namecheap.CommandWhoisGuardGetList:
+		list := ""
+		for _, d := range s.domains {
+			list += fmt.Sprintf(`<Whoisguard ID="%d" DomainName="%s" Status="%s"/>`, d.WhoisGuardID, escape(d.Name), d.WhoisGuardStatus)
+		}
+		writeOK(w, `<WhoisguardGetListResult>`+list+`</WhoisguardGetListResult>`+
+			fmt.Sprintf(`<Paging><TotalItems>%d</TotalItems></Paging>`, len(s.domains)))
+
+	case namecheap.CommandWhoisGuardEnable, namecheap.CommandWhoisGuardDisable:
+		d, ok := s.whoisGuard(w, form)
+		if !ok {
+			return
+		}
+		result := "WhoisguardEnableResult"
+		d.WhoisGuardStatus = "ENABLED"
+		if command == namecheap.CommandWhoisGuardDisable {
+			result = "WhoisguardDisableResult"
+			d.WhoisGuardStatus = "DISABLED"
+		}
+		writeOK(w, fmt.Sprintf(`<%s DomainName="%s" IsSuccess="true"/>`, result, escape(d.Name)))
+
+	case namecheap.CommandDomainsTransferCreate:
+		years, _ := strconv.Atoi(form.Get("Years"))
+		t := &Transfer{
+			ID:         s.id(),
+			DomainName: strings.ToLower(form.Get("DomainName")),
+			Years:      years,
+			EPPCode:    form.Get("EPPCode"),
+			Status:     "Transfer pending",
+		}
+		s.transfers[t.ID] = t
+		writeOK(w, fmt.Sprintf(`<DomainTransferCreateResult DomainName="%s" Transfer="true" TransferID="%d" StatusID="%d" OrderID="%d" TransactionID="%d" ChargedAmount="9.10"/>`,
+			escape(t.DomainName), t.ID, t.StatusID, s.id(), s.id()))
+
+	case namecheap.CommandDomainsTransferGetStatus:
+		t, ok := s.transfer(w, form)
+		if !ok {
+			return
+		}
+		writeOK(w, fmt.Sprintf(`<DomainTransferGetStatusResult TransferID="%d" Status="%s" StatusID="%d"/>`,
+			t.ID, escape(t.Status), t.StatusID))
+
+	case namecheap.CommandDomainsTransferUpdateStatus:
+		t, ok := s.transfer(w, form)
+		if !ok {
+			return
+		}
+		if code := form.Get("EPPCode"); code != "" {
+			t.EPPCode = code
+		}
+		t.StatusID, t.Status = 0, "Transfer resubmitted"
+		writeOK(w, fmt.Sprintf(`<TransferUpdateStatusResult TransferID="%d" Resubmit="true"/>`, t.ID))
+
+	case namecheap.CommandSSLCreate:
+		certificateType, _ := strconv.Atoi(form.Get("Type"))
+		years, _ := strconv.Atoi(form.Get("Years"))
+		c := &Certificate{ID: s.id(), Type: certificateType, Years: years, Status: "NEWPURCHASE"}
+		s.certificates[c.ID] = c
+		writeOK(w, fmt.Sprintf(`<SSLCreateResult IsSuccess="true" OrderId="%d" TransactionId="%d" ChargedAmount="9.00">`+
+			`<SSLCertificate CertificateID="%d" SSLType="PositiveSSL" Years="%d" Status="%s"/></SSLCreateResult>`,
+			s.id(), s.id(), c.ID, c.Years, c.Status))
+
+	case namecheap.CommandSSLActivate:
+		c, ok := s.certificate(w, form)
+		if !ok {
+			return
+		}
+		// Activation is approved at once
+		c.HostName = form.Get("DomainName")
+		if names := form.Get("DNSNames"); names != "" {
+			c.SANs = strings.Split(names, ",")
+			c.SANApprovers = strings.Split(form.Get("DNSApproverEmails"), ",")
+		}
+		c.Status = "ACTIVE"
+		c.Expires = time.Now().AddDate(max(c.Years, 1), 0, 0)
+		writeOK(w, fmt.Sprintf(`<SSLActivateResult IsSuccess="true" ID="%d"/>`, c.ID))
+
+	case namecheap.CommandSSLGetInfo:
+		c, ok := s.certificate(w, form)
+		if !ok {
+			return
+		}
+		expires := ""
+		if !c.Expires.IsZero() {
+			expires = c.Expires.Format("01/02/2006")
+		}
+		// An active certificate is returned when asked for
+		certificates := ""
+		if form.Get("Returncertificate") == "true" && c.Status == "ACTIVE" {
+			certificates = fmt.Sprintf(`<Certificates CertificateReturned="true" ReturnType="INDIVIDUAL">`+
+				`<Certificate>%s</Certificate><CaCertificates>`+
+				`<Certificate Type="INTERMEDIATE"><Certificate>%s</Certificate></Certificate>`+
+				`<Certificate Type="ROOT"><Certificate>%s</Certificate></Certificate>`+
+				`</CaCertificates></Certificates>`,
+				IssuedCertificate(c.ID), IntermediateCertificate, RootCertificate)
+		}
+		// ssl.getInfo reports statuses in lower case
+		writeOK(w, fmt.Sprintf(`<SSLGetInfoResult Status="%s" Type="PositiveSSL" Expires="%s">`+
+			`<CertificateDetails><CommonName>%s</CommonName>%s</CertificateDetails>`+
+			`<Provider><Name>COMODO</Name></Provider></SSLGetInfoResult>`,
+			strings.ToLower(c.Status), expires, escape(c.HostName), certificates))
+
+	case namecheap.CommandSSLRevoke:
+		c, ok := s.certificate(w, form)
+		if !ok {
+			return
+		}
+		c.Status = "REVOKED"
+		writeOK(w, fmt.Sprintf(`<RevokeCertificateResult ID="%d" IsSuccess="true"/>`, c.ID))
+
+	case namecheap.CommandSSLRenew:
+		renewed, ok := s.certificate(w, form)
+		if !ok {
+			return
+		}
+		years, _ := strconv.Atoi(form.Get("Years"))
+		c := &Certificate{ID: s.id(), Type: renewed.Type, Years: years, Status: "NEWPURCHASE", RenewedFrom: renewed.ID}
+		s.certificates[c.ID] = c
+		writeOK(w, fmt.Sprintf(`<SSLRenewResult CertificateID="%d" Years="%d" SSLType="%s" OrderId="%d" TransactionId="%d" ChargedAmount="9.00"/>`,
+			c.ID, c.Years, escape(form.Get("SSLType")), s.id(), s.id()))
+
+	case namecheap.CommandSSLGetApproverEmailList:
+		// Only the generic mailboxes, as the fake account has no WHOIS
+		domain := form.Get("DomainName")
+		var emails strings.Builder
+		for _, mailbox := range []string{"admin", "administrator", "hostmaster", "postmaster", "webmaster"} {
+			fmt.Fprintf(&emails, "<email>%s@%s</email>", mailbox, escape(domain))
+		}
+		writeOK(w, fmt.Sprintf(`<GetApproverEmailListResult Domain="%s"><Domainemails/><Genericemails>%s</Genericemails></GetApproverEmailListResult>`,
+			escape(domain), emails.String()))
+
+	case namecheap.CommandSSLParseCSR:
+		block, _ := pem.Decode([]byte(form.Get("csr")))
+		if block == nil {
+			writeError(w, "2011280", "Invalid CSR")
+			return
+		}
+		request, err := x509.ParseCertificateRequest(block.Bytes)
+		if err != nil {
+			writeError(w, "2011280", "Invalid CSR")
+			return
+		}
+		writeOK(w, fmt.Sprintf(`<SSLParseCSRResult><CSRDetails><CommonName>%s</CommonName></CSRDetails></SSLParseCSRResult>`,
+			escape(request.Subject.CommonName)))
+
+	default:
+		s.t.Errorf("fake Namecheap API received unsupported command %q", command)
+		http.Error(w, "unsupported command", http.StatusBadRequest)
+	}
+}
+
+// certificate returns the certificate a request names. An unknown
+// certificate fails the test, as no scenario looks one up on purpose.
+func (s *Server) certificate(w http.ResponseWriter, form url.Values) (*Certificate, bool) {
+	id, _ := strconv.Atoi(form.Get("CertificateID"))
+	c, ok := s.certificates[id]
+	if !ok {
+		s.t.Errorf("fake Namecheap API has no certificate %q", form.Get("CertificateID"))
+		http.Error(w, "unknown certificate", http.StatusBadRequest)
+	}
+	return c, ok
+}
+
+// transfer returns the transfer a request names. An unknown transfer fails
+// the test, as no scenario looks one up on purpose.
+func (s *Server) transfer(w http.ResponseWriter, form url.Values) (*Transfer, bool) {
+	id, _ := strconv.Atoi(form.Get("TransferID"))
+	t, ok := s.transfers[id]
+	if !ok {
+		s.t.Errorf("fake Namecheap API has no transfer %q", form.Get("TransferID"))
+		http.Error(w, "unknown transfer", http.StatusBadRequest)
+	}
+	return t, ok
+}
+
+// contacts returns the contacts in the parameters of a domains.create or
+// domains.setContacts request
+func contacts(form url.Values) namecheap.DomainContacts {
+	contact := func(prefix string) namecheap.Contact {
+		return namecheap.Contact{
+			OrganizationName:    form.Get(prefix + "OrganizationName"),
+			JobTitle:            form.Get(prefix + "JobTitle"),
+			FirstName:           form.Get(prefix + "FirstName"),
+			LastName:            form.Get(prefix + "LastName"),
+			Address1:            form.Get(prefix + "Address1"),
+			Address2:            form.Get(prefix + "Address2"),
+			City:                form.Get(prefix + "City"),
+			StateProvince:       form.Get(prefix + "StateProvince"),
+			StateProvinceChoice: form.Get(prefix + "StateProvinceChoice"),
+			PostalCode:          form.Get(prefix + "PostalCode"),
+			Country:             form.Get(prefix + "Country"),
+			Phone:               form.Get(prefix + "Phone"),
+			PhoneExt:            form.Get(prefix + "PhoneExt"),
+			Fax:                 form.Get(prefix + "Fax"),
+			EmailAddress:        form.Get(prefix + "EmailAddress"),
+		}
+	}
+	return namecheap.DomainContacts{
+		Registrant: contact("Registrant"),
+		Tech:       contact("Tech"),
+		Admin:      contact("Admin"),
+		AuxBilling: contact("AuxBilling"),
+	}
+}
+
+// tld returns the Tld element of a TLD supporting everything through the API
+func tld(name string) string {
+	return `<Tld Name="` + name + `" IsApiRegisterable="true" IsApiRenewable="true" IsApiTransferable="true" IsEppRequired="true" ` +
+		`IsDisableModContact="false" SupportsRegistrarLock="true" WhoisVerification="false"/>`
+}
+
+// NewCSR returns a PEM encoded certificate signing request for commonName
+// and sans, with a new P-256 key.
+func NewCSR(t testing.TB, commonName string, sans ...string) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: commonName},
+		DNSNames: sans,
+	}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+// The certificate authority's certificates returned with every issued
+// certificate
+const (
+	IntermediateCertificate = "-----BEGIN CERTIFICATE-----\nintermediate\n-----END CERTIFICATE-----\n"
+	RootCertificate         = "-----BEGIN CERTIFICATE-----\nroot\n-----END CERTIFICATE-----\n"
+)
+
+// IssuedCertificate returns the PEM the fake account returns as the
+// certificate issued for the certificate with the given ID
+func IssuedCertificate(id int) string {
+	return fmt.Sprintf("-----BEGIN CERTIFICATE-----\ncertificate %d\n-----END CERTIFICATE-----\n", id)
+}
+
+// escape escapes a value for use in XML text or attributes
+func escape(value string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
+
+func writeOK(w http.ResponseWriter, result string) {
+	w.Header().Set("Content-Type", "application/xml")
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>%s</CommandResponse>
+</ApiResponse>`, result)
+}
+
+func writeError(w http.ResponseWriter, number, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="%s">%s</Error>
+	</Errors>
+</ApiResponse>`, number, escape(message))
+}
@@ -0,0 +1,469 @@
+// Package supportbundle assembles a sanitized snapshot of provider state that
+// users can attach to bug reports.
+package supportbundle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/internal/version"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+const (
+	// Path is where the support bundle handler is served.
+	Path = "/debug/support-bundle"
+
+	redacted = "REDACTED"
+
+	errListResources       = "cannot list resources"
+	errListProviderConfigs = "cannot list ProviderConfigs"
+)
+
+var (
+	// sensitiveFlag matches flag names whose values must not leave the cluster.
+	sensitiveFlag = regexp.MustCompile(`(?i)(secret|key|token|password|credential)`)
+
+	// sensitiveParam matches key=value pairs, such as the query parameters of
+	// a Namecheap API URL, that may appear in condition messages.
+	sensitiveParam = regexp.MustCompile(`(?i)\b(api_?key|password|token|secret)=[^&\s"':;,]+`)
+)
+
+// A Bundle is a sanitized snapshot of provider state.
+type Bundle struct {
+	GeneratedAt     time.Time               `json:"generatedAt"`
+	Version         string                  `json:"version"`
+	GoVersion       string                  `json:"goVersion"`
+	Platform        string                  `json:"platform"`
+	Flags           map[string]string       `json:"flags,omitempty"`
+	ProviderConfigs []ProviderConfigSummary `json:"providerConfigs"`
+	Resources       []KindSummary           `json:"resources"`
+	Caches          []CacheSummary          `json:"caches,omitempty"`
+	AuditLog        []AuditEntry            `json:"auditLog,omitempty"`
+	Webhook         map[string]interface{}  `json:"webhook,omitempty"`
+	Errors          []string                `json:"errors,omitempty"`
+}
+
+// ProviderConfigSummary describes a ProviderConfig without its credentials.
+type ProviderConfigSummary struct {
+	Name             string `json:"name"`
+	CredentialSource string `json:"credentialSource"`
+	SandboxMode      bool   `json:"sandboxMode"`
+	APIBase          string `json:"apiBase,omitempty"`
+	Users            int64  `json:"users"`
+
+	// Client describes the client most recently built for the
+	// ProviderConfig, if any has been since the provider started.
+	Client *ClientSummary `json:"client,omitempty"`
+}
+
+// ClientSummary describes the rate limiter and circuit breaker of a Namecheap
+// client.
+type ClientSummary struct {
+	RequestsPerSecond float64    `json:"requestsPerSecond"`
+	Burst             int        `json:"burst"`
+	CircuitState      string     `json:"circuitState"`
+	Failures          int        `json:"failures"`
+	LastFailure       *time.Time `json:"lastFailure,omitempty"`
+}
+
+// CacheSummary describes one of the caches of Namecheap API responses shared
+// by every client.
+type CacheSummary struct {
+	Cache       string     `json:"cache"`
+	Entries     int        `json:"entries"`
+	Fetches     int        `json:"fetches"`
+	Hits        int64      `json:"hits"`
+	Misses      int64      `json:"misses"`
+	OldestEntry *time.Time `json:"oldestEntry,omitempty"`
+}
+
+// AuditEntry describes a recent mutating or billable Namecheap API request.
+type AuditEntry struct {
+	Time      time.Time `json:"time"`
+	Command   string    `json:"command"`
+	Category  string    `json:"category"`
+	Domain    string    `json:"domain,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	Kind      string    `json:"kind,omitempty"`
+	Outcome   string    `json:"outcome"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// KindSummary counts the resources of one kind and their condition states.
+type KindSummary struct {
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+
+	// Conditions counts resources by condition type, then by status.
+	Conditions map[xpv1.ConditionType]map[string]int `json:"conditions"`
+
+	// Unhealthy lists resources that are not both Ready and Synced.
+	Unhealthy []ResourceSummary `json:"unhealthy,omitempty"`
+}
+
+// ResourceSummary describes a resource that needs attention.
+type ResourceSummary struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Ready     string `json:"ready,omitempty"`
+	Synced    string `json:"synced,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// MetricsSource supplies a snapshot of metrics, such as the webhook server's.
+type MetricsSource interface {
+	GetAll() map[string]interface{}
+}
+
+// ClientSource supplies the Namecheap client most recently built for each
+// ProviderConfig, by ProviderConfig name.
+type ClientSource interface {
+	Clients() map[string]*namecheap.Client
+}
+
+// An Option configures a Generator.
+type Option func(*Generator)
+
+// WithFlags records the provider's command line flags. Values of flags that
+// look like they hold secret material are redacted.
+func WithFlags(flags map[string]string) Option {
+	return func(g *Generator) {
+		g.flags = flags
+	}
+}
+
+// WithWebhookMetrics includes the webhook server's metrics in the bundle.
+func WithWebhookMetrics(m MetricsSource) Option {
+	return func(g *Generator) {
+		g.webhook = m
+	}
+}
+
+// WithClients includes the state of the rate limiter and circuit breaker of
+// each ProviderConfig's client in its summary.
+func WithClients(c ClientSource) Option {
+	return func(g *Generator) {
+		g.clients = c
+	}
+}
+
+// WithCacheStats includes the stats of the Namecheap API response caches,
+// such as those of namecheap.ResponseCacheStats, in the bundle.
+func WithCacheStats(fn func() []namecheap.CacheStats) Option {
+	return func(g *Generator) {
+		g.caches = fn
+	}
+}
+
+// WithAuditLog includes recent audit log entries, such as those of
+// namecheap.RecentAuditEntries, in the bundle. Their errors are redacted.
+func WithAuditLog(fn func() []namecheap.AuditEntry) Option {
+	return func(g *Generator) {
+		g.audit = fn
+	}
+}
+
+// WithLogger sets the logger used when a bundle cannot be served.
+func WithLogger(l logging.Logger) Option {
+	return func(g *Generator) {
+		g.log = l
+	}
+}
+
+// managedKind lists the resources of one managed resource kind.
+type managedKind struct {
+	kind  string
+	list  client.ObjectList
+	items func() []resource.Managed
+}
+
+// A Generator assembles support bundles.
+type Generator struct {
+	kube    client.Reader
+	flags   map[string]string
+	webhook MetricsSource
+	clients ClientSource
+	caches  func() []namecheap.CacheStats
+	audit   func() []namecheap.AuditEntry
+	log     logging.Logger
+	now     func() time.Time
+}
+
+// NewGenerator returns a Generator that reads resources from the supplied
+// reader, which should be backed by the manager's informer cache.
+func NewGenerator(kube client.Reader, o ...Option) *Generator {
+	g := &Generator{
+		kube: kube,
+		log:  logging.NewNopLogger(),
+		now:  time.Now,
+	}
+	for _, fn := range o {
+		fn(g)
+	}
+	return g
+}
+
+// Generate assembles a support bundle. Failures to read individual kinds are
+// recorded in the bundle rather than aborting it, so that a partial bundle is
+// still available when the provider is unhealthy.
+func (g *Generator) Generate(ctx context.Context) *Bundle {
+	b := &Bundle{
+		GeneratedAt: g.now().UTC(),
+		Version:     version.Version,
+		GoVersion:   runtime.Version(),
+		Platform:    runtime.GOOS + "/" + runtime.GOARCH,
+		Flags:       RedactFlags(g.flags),
+	}
+
+	pcs := &v1beta1.ProviderConfigList{}
+	if err := g.kube.List(ctx, pcs); err != nil {
+		b.Errors = append(b.Errors, errors.Wrap(err, errListProviderConfigs).Error())
+	}
+	var clients map[string]*namecheap.Client
+	if g.clients != nil {
+		clients = g.clients.Clients()
+	}
+	b.ProviderConfigs = summarizeProviderConfigs(pcs.Items, clients)
+
+	domains := &v1beta1.DomainList{}
+	records := &v1beta1.DNSRecordList{}
+	certs := &v1beta1.SSLCertificateList{}
+	transfers := &v1beta1.DomainTransferList{}
+	zones := &v1beta1.DNSZoneList{}
+
+	kinds := []managedKind{
+		{kind: v1beta1.DomainKind, list: domains, items: func() []resource.Managed {
+			mgs := make([]resource.Managed, 0, len(domains.Items))
+			for i := range domains.Items {
+				mgs = append(mgs, &domains.Items[i])
+			}
+			return mgs
+		}},
+		{kind: v1beta1.DNSRecordKind, list: records, items: func() []resource.Managed {
+			mgs := make([]resource.Managed, 0, len(records.Items))
+			for i := range records.Items {
+				mgs = append(mgs, &records.Items[i])
+			}
+			return mgs
+		}},
+		{kind: v1beta1.SSLCertificateKind, list: certs, items: func() []resource.Managed {
+			mgs := make([]resource.Managed, 0, len(certs.Items))
+			for i := range certs.Items {
+				mgs = append(mgs, &certs.Items[i])
+			}
+			return mgs
+		}},
+		{kind: v1beta1.DomainTransferKind, list: transfers, items: func() []resource.Managed {
+			mgs := make([]resource.Managed, 0, len(transfers.Items))
+			for i := range transfers.Items {
+				mgs = append(mgs, &transfers.Items[i])
+			}
+			return mgs
+		}},
+		{kind: v1beta1.DNSZoneKind, list: zones, items: func() []resource.Managed {
+			mgs := make([]resource.Managed, 0, len(zones.Items))
+			for i := range zones.Items {
+				mgs = append(mgs, &zones.Items[i])
+			}
+			return mgs
+		}},
+	}
+
+	for _, k := range kinds {
+		if err := g.kube.List(ctx, k.list); err != nil {
+			b.Errors = append(b.Errors, errors.Wrapf(err, "%s: %s", errListResources, k.kind).Error())
+			continue
+		}
+		b.Resources = append(b.Resources, summarizeKind(k.kind, k.items()))
+	}
+
+	if g.caches != nil {
+		b.Caches = summarizeCaches(g.caches())
+	}
+
+	if g.audit != nil {
+		b.AuditLog = summarizeAuditLog(g.audit())
+	}
+
+	if g.webhook != nil {
+		b.Webhook = g.webhook.GetAll()
+	}
+
+	return b
+}
+
+// ServeHTTP serves a freshly generated support bundle as JSON.
+func (g *Generator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="provider-namecheap-support-bundle.json"`)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(g.Generate(r.Context())); err != nil {
+		g.log.Info("Cannot write support bundle", "error", err)
+	}
+}
+
+// RedactFlags returns a copy of flags with the values of any flag whose name
+// suggests secret material replaced.
+func RedactFlags(flags map[string]string) map[string]string {
+	if flags == nil {
+		return nil
+	}
+	out := make(map[string]string, len(flags))
+	for name, value := range flags {
+		if value != "" && sensitiveFlag.MatchString(name) {
+			value = redacted
+		}
+		out[name] = value
+	}
+	return out
+}
+
+// RedactMessage replaces secret values embedded in free text, such as an API
+// URL quoted in a condition message.
+func RedactMessage(msg string) string {
+	return sensitiveParam.ReplaceAllString(msg, "${1}="+redacted)
+}
+
+func summarizeProviderConfigs(pcs []v1beta1.ProviderConfig, clients map[string]*namecheap.Client) []ProviderConfigSummary {
+	out := make([]ProviderConfigSummary, 0, len(pcs))
+	for _, pc := range pcs {
+		s := ProviderConfigSummary{
+			Name:             pc.GetName(),
+			CredentialSource: string(pc.Spec.Credentials.Source),
+			SandboxMode:      pc.Spec.SandboxMode != nil && *pc.Spec.SandboxMode,
+		}
+		if pc.Spec.APIBase != nil {
+			s.APIBase = RedactMessage(*pc.Spec.APIBase)
+		}
+		if pc.Status.UserCount != nil {
+			s.Users = *pc.Status.UserCount
+		}
+		if client, ok := clients[pc.GetName()]; ok {
+			s.Client = summarizeClient(client)
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func summarizeClient(client *namecheap.Client) *ClientSummary {
+	rps, burst := client.RateLimiter().GetCurrentLimit()
+	state, failures, lastFailure := client.CircuitBreaker().GetState()
+	s := &ClientSummary{
+		RequestsPerSecond: rps,
+		Burst:             burst,
+		CircuitState:      state.String(),
+		Failures:          failures,
+	}
+	if !lastFailure.IsZero() {
+		t := lastFailure.UTC()
+		s.LastFailure = &t
+	}
+	return s
+}
+
+func summarizeCaches(stats []namecheap.CacheStats) []CacheSummary {
+	out := make([]CacheSummary, 0, len(stats))
+	for _, st := range stats {
+		s := CacheSummary{
+			Cache:   st.Cache,
+			Entries: st.Entries,
+			Fetches: st.Fetches,
+			Hits:    st.Hits,
+			Misses:  st.Misses,
+		}
+		if !st.OldestEntry.IsZero() {
+			t := st.OldestEntry.UTC()
+			s.OldestEntry = &t
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func summarizeAuditLog(entries []namecheap.AuditEntry) []AuditEntry {
+	out := make([]AuditEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, AuditEntry{
+			Time:      e.Time.UTC(),
+			Command:   e.Command.String(),
+			Category:  string(e.Category),
+			Domain:    e.Domain,
+			Namespace: e.Namespace,
+			Kind:      e.Kind,
+			Outcome:   e.Outcome,
+			Error:     RedactMessage(e.Error),
+		})
+	}
+	return out
+}
+
+func summarizeKind(kind string, mgs []resource.Managed) KindSummary {
+	s := KindSummary{
+		Kind:       kind,
+		Count:      len(mgs),
+		Conditions: map[xpv1.ConditionType]map[string]int{},
+	}
+
+	for _, mg := range mgs {
+		ready := mg.GetCondition(xpv1.TypeReady)
+		synced := mg.GetCondition(xpv1.TypeSynced)
+
+		for _, c := range []xpv1.Condition{ready, synced} {
+			if s.Conditions[c.Type] == nil {
+				s.Conditions[c.Type] = map[string]int{}
+			}
+			s.Conditions[c.Type][string(c.Status)]++
+		}
+
+		if ready.Status == "True" && synced.Status == "True" {
+			continue
+		}
+
+		var msgs []string
+		for _, c := range []xpv1.Condition{synced, ready} {
+			if c.Message != "" {
+				msgs = append(msgs, RedactMessage(c.Message))
+			}
+		}
+		s.Unhealthy = append(s.Unhealthy, ResourceSummary{
+			Namespace: mg.GetNamespace(),
+			Name:      mg.GetName(),
+			Ready:     string(ready.Reason),
+			Synced:    string(synced.Reason),
+			Message:   strings.Join(msgs, "; "),
+		})
+	}
+
+	sort.Slice(s.Unhealthy, func(i, j int) bool {
+		if s.Unhealthy[i].Namespace != s.Unhealthy[j].Namespace {
+			return s.Unhealthy[i].Namespace < s.Unhealthy[j].Namespace
+		}
+		return s.Unhealthy[i].Name < s.Unhealthy[j].Name
+	})
+
+	return s
+}
@@ -0,0 +1,268 @@
+package supportbundle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	"github.com/rossigee/provider-namecheap/apis/v1beta1"
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+type fakeMetrics map[string]interface{}
+
+func (m fakeMetrics) GetAll() map[string]interface{} {
+	return m
+}
+
+type fakeClients map[string]*namecheap.Client
+
+func (c fakeClients) Clients() map[string]*namecheap.Client {
+	return c
+}
+
+func TestRedactFlags(t *testing.T) {
+	flags := RedactFlags(map[string]string{
+		"debug":          "true",
+		"poll":           "1m0s",
+		"webhook-secret": "s3cr3t",
+		"api-key":        "abc123",
+		"token-file":     "",
+	})
+
+	assert.Equal(t, map[string]string{
+		"debug":          "true",
+		"poll":           "1m0s",
+		"webhook-secret": redacted,
+		"api-key":        redacted,
+		"token-file":     "",
+	}, flags)
+	assert.Nil(t, RedactFlags(nil))
+}
+
+func TestRedactMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      string
+		expected string
+	}{
+		{
+			name:     "API URL",
+			msg:      "Get https://api.namecheap.com/xml.response?ApiUser=user&ApiKey=abc123&Command=namecheap.domains.getList: timeout",
+			expected: "Get https://api.namecheap.com/xml.response?ApiUser=user&ApiKey=REDACTED&Command=namecheap.domains.getList: timeout",
+		},
+		{
+			name:     "multiple secrets",
+			msg:      "password=hunter2 token=xyz",
+			expected: "password=REDACTED token=REDACTED",
+		},
+		{
+			name:     "nothing to redact",
+			msg:      "cannot get domain: domain not found",
+			expected: "cannot get domain: domain not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RedactMessage(tt.msg))
+		})
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	sandbox := true
+	users := int64(2)
+	pc := &v1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: v1beta1.ProviderConfigSpec{
+			Credentials: v1beta1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+					SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Name: "namecheap-credentials", Namespace: "crossplane-system"},
+						Key:             "credentials",
+					},
+				},
+			},
+			SandboxMode: &sandbox,
+		},
+		Status: v1beta1.ProviderConfigStatus{UserCount: &users},
+	}
+
+	healthy := &v1beta1.Domain{ObjectMeta: metav1.ObjectMeta{Name: "healthy", Namespace: "default"}}
+	healthy.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+
+	failing := &v1beta1.Domain{ObjectMeta: metav1.ObjectMeta{Name: "failing", Namespace: "default"}}
+	failing.SetConditions(xpv1.Creating(), xpv1.ReconcileError(errorString(
+		"Get https://api.namecheap.com/xml.response?ApiKey=abc123: timeout")))
+
+	record := &v1beta1.DNSRecord{ObjectMeta: metav1.ObjectMeta{Name: "www", Namespace: "default"}}
+	record.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+
+	kube := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(pc, healthy, failing, record).
+		WithStatusSubresource(pc).
+		Build()
+
+	// The client of the ProviderConfig has failed a request, and one of a
+	// ProviderConfig since deleted is left behind
+	client := namecheap.NewClient(namecheap.Config{
+		APIUser:              "bundleuser",
+		APIKey:               "abc123",
+		RateLimitConfig:      &namecheap.RateLimitConfig{RequestsPerSecond: 2, BurstSize: 5},
+		CircuitBreakerConfig: &namecheap.CircuitBreakerConfig{MaxFailures: 5, ResetTimeout: time.Minute},
+	})
+	require.Error(t, client.CircuitBreaker().Execute(context.Background(), func() error {
+		return errorString("timeout")
+	}))
+	_, _, lastFailure := client.CircuitBreaker().GetState()
+	lastFailure = lastFailure.UTC()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fetchedAt := now.Add(-time.Hour)
+	g := NewGenerator(kube,
+		WithFlags(map[string]string{"poll": "1m0s", "webhook-secret": "s3cr3t"}),
+		WithWebhookMetrics(fakeMetrics{"requests_total": int64(3)}),
+		WithClients(fakeClients{"default": client, "deleted": namecheap.NewClient(namecheap.Config{})}),
+		WithCacheStats(func() []namecheap.CacheStats {
+			return []namecheap.CacheStats{
+				{Cache: "pricing"},
+				{Cache: "tlds", Entries: 1, Hits: 4, Misses: 1, OldestEntry: fetchedAt},
+			}
+		}),
+		WithAuditLog(func() []namecheap.AuditEntry {
+			return []namecheap.AuditEntry{{
+				Time:      now,
+				Command:   namecheap.CommandDomainsDNSSetHosts,
+				Category:  namecheap.CategoryMutating,
+				Domain:    "example.com",
+				Namespace: "default",
+				Kind:      v1beta1.DNSRecordKind,
+				Outcome:   namecheap.OutcomeError,
+				Error:     "failed to execute request: Post https://api.namecheap.com/xml.response?ApiKey=abc123: timeout",
+			}}
+		}))
+	g.now = func() time.Time { return now }
+
+	b := g.Generate(context.Background())
+
+	assert.Equal(t, now, b.GeneratedAt)
+	assert.Empty(t, b.Errors)
+	assert.Equal(t, map[string]string{"poll": "1m0s", "webhook-secret": redacted}, b.Flags)
+	assert.Equal(t, []ProviderConfigSummary{{
+		Name:             "default",
+		CredentialSource: "Secret",
+		SandboxMode:      true,
+		Users:            2,
+		Client: &ClientSummary{
+			RequestsPerSecond: 2,
+			Burst:             5,
+			CircuitState:      "closed",
+			Failures:          1,
+			LastFailure:       &lastFailure,
+		},
+	}}, b.ProviderConfigs)
+	assert.Equal(t, []CacheSummary{
+		{Cache: "pricing"},
+		{Cache: "tlds", Entries: 1, Hits: 4, Misses: 1, OldestEntry: &fetchedAt},
+	}, b.Caches)
+	assert.Equal(t, []AuditEntry{{
+		Time:      now,
+		Command:   "namecheap.domains.dns.setHosts",
+		Category:  "mutating",
+		Domain:    "example.com",
+		Namespace: "default",
+		Kind:      v1beta1.DNSRecordKind,
+		Outcome:   "error",
+		Error:     "failed to execute request: Post https://api.namecheap.com/xml.response?ApiKey=REDACTED: timeout",
+	}}, b.AuditLog)
+	assert.Equal(t, map[string]interface{}{"requests_total": int64(3)}, b.Webhook)
+
+	require.Len(t, b.Resources, 5)
+
+	domains := b.Resources[0]
+	assert.Equal(t, v1beta1.DomainKind, domains.Kind)
+	assert.Equal(t, 2, domains.Count)
+	assert.Equal(t, map[string]int{"True": 1, "False": 1}, domains.Conditions[xpv1.TypeReady])
+	assert.Equal(t, map[string]int{"True": 1, "False": 1}, domains.Conditions[xpv1.TypeSynced])
+	require.Len(t, domains.Unhealthy, 1)
+	assert.Equal(t, "failing", domains.Unhealthy[0].Name)
+	assert.Equal(t, "Creating", domains.Unhealthy[0].Ready)
+	assert.Equal(t, "ReconcileError", domains.Unhealthy[0].Synced)
+	assert.Equal(t, "Get https://api.namecheap.com/xml.response?ApiKey=REDACTED: timeout", domains.Unhealthy[0].Message)
+
+	assert.Equal(t, v1beta1.DNSRecordKind, b.Resources[1].Kind)
+	assert.Equal(t, 1, b.Resources[1].Count)
+	assert.Empty(t, b.Resources[1].Unhealthy)
+
+	assert.Equal(t, v1beta1.SSLCertificateKind, b.Resources[2].Kind)
+	assert.Equal(t, 0, b.Resources[2].Count)
+
+	assert.Equal(t, v1beta1.DomainTransferKind, b.Resources[3].Kind)
+	assert.Equal(t, 0, b.Resources[3].Count)
+
+	assert.Equal(t, v1beta1.DNSZoneKind, b.Resources[4].Kind)
+	assert.Equal(t, 0, b.Resources[4].Count)
+
+	// Nothing in the serialized bundle may leak secret material
+	data, err := json.Marshal(b)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "abc123")
+	assert.NotContains(t, string(data), "s3cr3t")
+}
+
+func TestGenerator_ServeHTTP(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	g := NewGenerator(fake.NewClientBuilder().WithScheme(scheme).Build())
+
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, Path, nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var b Bundle
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &b))
+	assert.Len(t, b.Resources, 5)
+
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodPost, Path, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestGenerator_PartialBundle(t *testing.T) {
+	// A scheme without the provider's types makes every list fail
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	g := NewGenerator(fake.NewClientBuilder().WithScheme(scheme).Build())
+
+	b := g.Generate(context.Background())
+
+	assert.Len(t, b.Errors, 6)
+	assert.Empty(t, b.Resources)
+	assert.Empty(t, b.ProviderConfigs)
+}
+
+type errorString string
+
+func (e errorString) Error() string {
+	return string(e)
+}
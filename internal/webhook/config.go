@@ -3,6 +3,7 @@ package webhook
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -11,24 +12,24 @@ import (
 // WebhookConfig represents the configuration for webhook endpoints
 type WebhookConfig struct {
 	// Endpoint configuration
-	URL              string        `json:"url"`
-	Secret           string        `json:"secret"`
-	Events           []EventType   `json:"events"`
-	Active           bool          `json:"active"`
+	URL    string      `json:"url"`
+	Secret string      `json:"secret"`
+	Events []EventType `json:"events"`
+	Active bool        `json:"active"`
 
 	// HTTP configuration
-	Timeout          time.Duration `json:"timeout"`
-	MaxRetries       int           `json:"max_retries"`
-	RetryDelay       time.Duration `json:"retry_delay"`
+	Timeout    time.Duration `json:"timeout"`
+	MaxRetries int           `json:"max_retries"`
+	RetryDelay time.Duration `json:"retry_delay"`
 
 	// Security configuration
-	VerifySSL        bool          `json:"verify_ssl"`
-	UserAgent        string        `json:"user_agent"`
+	VerifySSL bool   `json:"verify_ssl"`
+	UserAgent string `json:"user_agent"`
 
 	// Metadata
-	Description      string        `json:"description"`
-	CreatedAt        time.Time     `json:"created_at"`
-	UpdatedAt        time.Time     `json:"updated_at"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 // DefaultWebhookConfig returns sensible defaults for webhook configuration
@@ -55,8 +56,10 @@ func DefaultWebhookConfig() WebhookConfig {
 
 // WebhookManager manages webhook configurations and processors
 type WebhookManager struct {
-	server     *Server
-	logger     logr.Logger
+	server *Server
+	logger logr.Logger
+
+	mu         sync.RWMutex
 	processors map[EventType][]EventProcessor
 }
 
@@ -113,12 +116,16 @@ func (wm *WebhookManager) RegisterDefaultProcessors() {
 
 // AddProcessor adds an additional processor for an event type
 func (wm *WebhookManager) AddProcessor(eventType EventType, processor EventProcessor) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
 	wm.processors[eventType] = append(wm.processors[eventType], processor)
 	wm.logger.Info("Added additional processor", "event_type", eventType)
 }
 
 // RemoveProcessor removes a processor for an event type
 func (wm *WebhookManager) RemoveProcessor(eventType EventType, processor EventProcessor) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
 	processors := wm.processors[eventType]
 	for i, p := range processors {
 		if p == processor {
@@ -131,7 +138,9 @@ func (wm *WebhookManager) RemoveProcessor(eventType EventType, processor EventPr
 
 // GetProcessors returns all processors for an event type
 func (wm *WebhookManager) GetProcessors(eventType EventType) []EventProcessor {
-	return wm.processors[eventType]
+	wm.mu.RLock()
+	defer wm.mu.RUnlock()
+	return append([]EventProcessor(nil), wm.processors[eventType]...)
 }
 
 // ValidateConfig validates webhook configuration
@@ -153,25 +162,8 @@ func ValidateConfig(config WebhookConfig) error {
 	}
 
 	// Validate event types
-	validEvents := map[EventType]bool{
-		EventDomainRegistered:  true,
-		EventDomainRenewed:     true,
-		EventDomainExpired:     true,
-		EventDomainTransferred: true,
-		EventDNSRecordCreated:  true,
-		EventDNSRecordUpdated:  true,
-		EventDNSRecordDeleted:  true,
-		EventSSLIssued:         true,
-		EventSSLRenewed:        true,
-		EventSSLExpired:        true,
-		EventSSLRevoked:        true,
-		EventAccountUpdated:    true,
-		EventPaymentReceived:   true,
-		EventPaymentFailed:     true,
-	}
-
 	for _, event := range config.Events {
-		if !validEvents[event] {
+		if !IsKnownEventType(event) {
 			return fmt.Errorf("invalid event type: %s", event)
 		}
 	}
@@ -234,4 +226,4 @@ func (ws *WebhookSetup) StartWebhookServer(ctx context.Context, server *Server,
 		defer cancel()
 		return server.Stop(shutdownCtx)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,238 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// EventSchemaVersion is the webhook envelope version understood by this server.
+// Events without a version are treated as this version.
+const EventSchemaVersion = "1"
+
+// DomainEventData is the payload of domain.* events
+type DomainEventData struct {
+	Domain     string `json:"domain"`
+	ExpiryDate string `json:"expiry_date,omitempty"`
+}
+
+func (d *DomainEventData) missingFields() []string {
+	var missing []string
+	if d.Domain == "" {
+		missing = append(missing, "domain")
+	}
+	return missing
+}
+
+// DNSRecordEventData is the payload of dns.record.* events
+type DNSRecordEventData struct {
+	Domain string            `json:"domain"`
+	Record *DNSRecordPayload `json:"record"`
+}
+
+// DNSRecordPayload describes the DNS record a dns.record.* event refers to
+type DNSRecordPayload struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+}
+
+func (d *DNSRecordEventData) missingFields() []string {
+	var missing []string
+	if d.Domain == "" {
+		missing = append(missing, "domain")
+	}
+	if d.Record == nil {
+		return append(missing, "record")
+	}
+	if d.Record.Type == "" {
+		missing = append(missing, "record.type")
+	}
+	if d.Record.Name == "" {
+		missing = append(missing, "record.name")
+	}
+	return missing
+}
+
+// SSLEventData is the payload of ssl.* events
+type SSLEventData struct {
+	CertificateID string `json:"certificate_id"`
+	Domain        string `json:"domain"`
+}
+
+func (d *SSLEventData) missingFields() []string {
+	var missing []string
+	if d.CertificateID == "" {
+		missing = append(missing, "certificate_id")
+	}
+	if d.Domain == "" {
+		missing = append(missing, "domain")
+	}
+	return missing
+}
+
+// PaymentEventData is the payload of payment.* events
+type PaymentEventData struct {
+	Amount   *float64 `json:"amount"`
+	Currency string   `json:"currency"`
+	Reason   string   `json:"reason,omitempty"`
+}
+
+func (d *PaymentEventData) missingFields() []string {
+	var missing []string
+	if d.Amount == nil {
+		missing = append(missing, "amount")
+	}
+	if d.Currency == "" {
+		missing = append(missing, "currency")
+	}
+	return missing
+}
+
+// eventPayload is implemented by the typed event payloads
+type eventPayload interface {
+	missingFields() []string
+}
+
+// PayloadError reports the fields of an event payload that failed validation
+type PayloadError struct {
+	// Missing lists required fields that are absent or empty
+	Missing []string
+	// WrongType lists fields whose JSON type does not match the schema
+	WrongType []string
+	// Version is set when the envelope version is not supported
+	Version string
+}
+
+// Error implements the error interface
+func (e *PayloadError) Error() string {
+	var problems []string
+	if e.Version != "" {
+		problems = append(problems, fmt.Sprintf("unsupported event schema version %q", e.Version))
+	}
+	if len(e.Missing) == 1 {
+		problems = append(problems, fmt.Sprintf("missing or invalid %s field", e.Missing[0]))
+	} else if len(e.Missing) > 1 {
+		problems = append(problems, fmt.Sprintf("missing or invalid fields: %s", strings.Join(e.Missing, ", ")))
+	}
+	if len(e.WrongType) > 0 {
+		problems = append(problems, fmt.Sprintf("fields with wrong type: %s", strings.Join(e.WrongType, ", ")))
+	}
+	return strings.Join(problems, "; ")
+}
+
+// DomainData decodes the payload of a domain event
+func (e *WebhookEvent) DomainData() (*DomainEventData, error) {
+	data := &DomainEventData{}
+	return data, decodeEventData(e.Data, data)
+}
+
+// DNSRecordData decodes the payload of a DNS record event
+func (e *WebhookEvent) DNSRecordData() (*DNSRecordEventData, error) {
+	data := &DNSRecordEventData{}
+	return data, decodeEventData(e.Data, data)
+}
+
+// SSLData decodes the payload of an SSL event
+func (e *WebhookEvent) SSLData() (*SSLEventData, error) {
+	data := &SSLEventData{}
+	return data, decodeEventData(e.Data, data)
+}
+
+// PaymentData decodes the payload of a payment event
+func (e *WebhookEvent) PaymentData() (*PaymentEventData, error) {
+	data := &PaymentEventData{}
+	return data, decodeEventData(e.Data, data)
+}
+
+// Validate checks the envelope version and decodes the payload against the
+// schema of the event's family. Unknown payload fields are tolerated; missing
+// required fields and fields of the wrong type are reported in a PayloadError.
+// Event types without a typed payload are not validated.
+func (e *WebhookEvent) Validate() error {
+	if e.Version != "" && e.Version != EventSchemaVersion {
+		return &PayloadError{Version: e.Version}
+	}
+
+	var err error
+	switch e.Type {
+	case EventDomainRegistered, EventDomainRenewed, EventDomainExpired, EventDomainTransferred:
+		_, err = e.DomainData()
+	case EventDNSRecordCreated, EventDNSRecordUpdated, EventDNSRecordDeleted:
+		_, err = e.DNSRecordData()
+	case EventSSLIssued, EventSSLRenewed, EventSSLExpired, EventSSLRevoked:
+		_, err = e.SSLData()
+	case EventPaymentReceived, EventPaymentFailed:
+		_, err = e.PaymentData()
+	}
+	return err
+}
+
+// decodeEventData decodes an untyped event payload into a typed one and
+// checks its required fields
+func decodeEventData(data map[string]interface{}, out eventPayload) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode event data: %w", err)
+	}
+
+	payloadErr := &PayloadError{}
+
+	// json.Unmarshal keeps decoding after a type mismatch and reports the first
+	// one, so decode repeatedly with offending fields removed to find them all
+	for {
+		err := json.Unmarshal(raw, out)
+		if err == nil {
+			break
+		}
+		typeErr, ok := err.(*json.UnmarshalTypeError)
+		if !ok || typeErr.Field == "" {
+			return fmt.Errorf("failed to decode event data: %w", err)
+		}
+		payloadErr.WrongType = append(payloadErr.WrongType, typeErr.Field)
+		if raw, err = deleteField(raw, typeErr.Field); err != nil {
+			return fmt.Errorf("failed to decode event data: %w", err)
+		}
+	}
+
+	for _, field := range out.missingFields() {
+		if !containsString(payloadErr.WrongType, field) {
+			payloadErr.Missing = append(payloadErr.Missing, field)
+		}
+	}
+
+	if len(payloadErr.Missing) > 0 || len(payloadErr.WrongType) > 0 {
+		return payloadErr
+	}
+	return nil
+}
+
+// deleteField removes a dotted field path from a JSON object
+func deleteField(raw []byte, field string) ([]byte, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(field, ".")
+	current := obj
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot locate field %s", field)
+		}
+		current = next
+	}
+	delete(current, parts[len(parts)-1])
+
+	return json.Marshal(obj)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookEvent_Validate(t *testing.T) {
+	tests := []struct {
+		name              string
+		eventType         EventType
+		version           string
+		data              map[string]interface{}
+		expectedMissing   []string
+		expectedWrongType []string
+		expectedError     string
+	}{
+		{
+			name:      "domain valid",
+			eventType: EventDomainRenewed,
+			data:      map[string]interface{}{"domain": "example.com", "expiry_date": "2027-01-01", "extra": true},
+		},
+		{
+			name:            "domain missing field",
+			eventType:       EventDomainRegistered,
+			data:            map[string]interface{}{},
+			expectedMissing: []string{"domain"},
+			expectedError:   "missing or invalid domain field",
+		},
+		{
+			name:              "domain wrong type",
+			eventType:         EventDomainExpired,
+			data:              map[string]interface{}{"domain": 42},
+			expectedWrongType: []string{"domain"},
+			expectedError:     "fields with wrong type: domain",
+		},
+		{
+			name:      "dns record valid",
+			eventType: EventDNSRecordDeleted,
+			data: map[string]interface{}{
+				"domain": "example.com",
+				"record": map[string]interface{}{"type": "A", "name": "www"},
+			},
+		},
+		{
+			name:            "dns record missing fields",
+			eventType:       EventDNSRecordCreated,
+			data:            map[string]interface{}{"record": map[string]interface{}{"value": "192.0.2.1"}},
+			expectedMissing: []string{"domain", "record.type", "record.name"},
+			expectedError:   "missing or invalid fields: domain, record.type, record.name",
+		},
+		{
+			name:      "dns record wrong type",
+			eventType: EventDNSRecordUpdated,
+			data: map[string]interface{}{
+				"domain": "example.com",
+				"record": map[string]interface{}{"type": "A", "name": 7, "value": []string{"x"}},
+			},
+			expectedWrongType: []string{"record.name", "record.value"},
+			expectedMissing:   []string{},
+		},
+		{
+			name:      "ssl valid",
+			eventType: EventSSLIssued,
+			data:      map[string]interface{}{"certificate_id": "1234", "domain": "example.com"},
+		},
+		{
+			name:            "ssl missing field",
+			eventType:       EventSSLExpired,
+			data:            map[string]interface{}{"domain": "example.com"},
+			expectedMissing: []string{"certificate_id"},
+		},
+		{
+			name:              "ssl wrong type",
+			eventType:         EventSSLRevoked,
+			data:              map[string]interface{}{"certificate_id": 1234, "domain": "example.com"},
+			expectedWrongType: []string{"certificate_id"},
+		},
+		{
+			name:      "payment valid",
+			eventType: EventPaymentFailed,
+			data:      map[string]interface{}{"amount": 10.5, "currency": "USD", "reason": "card declined"},
+		},
+		{
+			name:            "payment missing fields",
+			eventType:       EventPaymentReceived,
+			data:            map[string]interface{}{},
+			expectedMissing: []string{"amount", "currency"},
+		},
+		{
+			name:              "payment wrong type",
+			eventType:         EventPaymentReceived,
+			data:              map[string]interface{}{"amount": "ten", "currency": "USD"},
+			expectedWrongType: []string{"amount"},
+		},
+		{
+			name:      "account update is free-form",
+			eventType: EventAccountUpdated,
+			data:      map[string]interface{}{},
+		},
+		{
+			name:      "current version",
+			eventType: EventDomainRenewed,
+			version:   EventSchemaVersion,
+			data:      map[string]interface{}{"domain": "example.com"},
+		},
+		{
+			name:          "unsupported version",
+			eventType:     EventDomainRenewed,
+			version:       "99",
+			data:          map[string]interface{}{"domain": "example.com"},
+			expectedError: `unsupported event schema version "99"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &WebhookEvent{ID: "test-id", Version: tt.version, Type: tt.eventType, Data: tt.data}
+			err := event.Validate()
+
+			if len(tt.expectedMissing) == 0 && len(tt.expectedWrongType) == 0 && tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			var payloadErr *PayloadError
+			require.ErrorAs(t, err, &payloadErr)
+			if tt.expectedMissing != nil {
+				assert.ElementsMatch(t, tt.expectedMissing, payloadErr.Missing)
+			}
+			if tt.expectedWrongType != nil {
+				assert.ElementsMatch(t, tt.expectedWrongType, payloadErr.WrongType)
+			}
+			if tt.expectedError != "" {
+				assert.Contains(t, err.Error(), tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestWebhookServer_InvalidPayload(t *testing.T) {
+	server := NewServer(Config{
+		Port:   8080,
+		Path:   "/webhook",
+		Logger: logr.Discard(),
+	})
+
+	processed := false
+	server.RegisterProcessor(EventDNSRecordCreated, EventProcessorFunc(func(ctx context.Context, event *WebhookEvent) error {
+		processed = true
+		return nil
+	}))
+
+	body, err := json.Marshal(WebhookEvent{
+		ID:        "test-event-id",
+		Type:      EventDNSRecordCreated,
+		Timestamp: time.Now(),
+		Data:      map[string]interface{}{"domain": "example.com", "record": map[string]interface{}{"value": "192.0.2.1"}},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/webhook", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	server.handleWebhook(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "record.type, record.name")
+	assert.False(t, processed, "Invalid event should not have been processed")
+	assert.Equal(t, int64(1), server.metrics.RequestsErrors.Value())
+}
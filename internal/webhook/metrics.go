@@ -2,57 +2,94 @@ package webhook
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// Metrics provides observability for webhook operations
+// Metrics provides observability for webhook operations. Counters and the
+// histogram are created once and never replaced, so handlers may hold on to
+// them. Updates share a read lock on the metrics, while Reset and Snapshot
+// take it exclusively; a reset or snapshot therefore sees either all or none
+// of an in-flight update, and no update is ever lost.
 type Metrics struct {
-	mu                sync.RWMutex
-	RequestsTotal     *Counter
-	RequestsErrors    *Counter
-	ProcessingErrors  *Counter
-	EventsProcessed   *Counter
-	RequestDuration   *Histogram
-	lastReset         time.Time
+	mu               sync.RWMutex
+	RequestsTotal    *Counter
+	RequestsErrors   *Counter
+	ProcessingErrors *Counter
+	EventsProcessed  *Counter
+	RequestDuration  *Histogram
+	lastReset        time.Time
+
+	// unhandled counts events without a registered processor, per event
+	// type. unhandledMu guards the map itself.
+	unhandledMu sync.Mutex
+	unhandled   map[EventType]*Counter
 }
 
 // Counter represents a simple counter metric
 type Counter struct {
-	mu    sync.RWMutex
-	value int64
+	value atomic.Int64
+
+	// guard is the owning Metrics' lock, held shared while updating
+	guard *sync.RWMutex
 }
 
 // Inc increments the counter by 1
 func (c *Counter) Inc() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.value++
+	c.Add(1)
 }
 
 // Add increments the counter by the given value
 func (c *Counter) Add(v int64) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.value += v
+	if c.guard != nil {
+		c.guard.RLock()
+		defer c.guard.RUnlock()
+	}
+	c.value.Add(v)
 }
 
 // Value returns the current counter value
 func (c *Counter) Value() int64 {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return c.value
+	return c.value.Load()
+}
+
+// swap resets the counter, returning its previous value
+func (c *Counter) swap() int64 {
+	return c.value.Swap(0)
 }
 
 // Histogram tracks request duration metrics
 type Histogram struct {
-	mu      sync.RWMutex
+	mu      sync.Mutex
 	samples []float64
 	sum     float64
 	count   int64
+
+	// guard is the owning Metrics' lock, held shared while updating
+	guard *sync.RWMutex
+}
+
+// HistogramSnapshot is a point-in-time copy of a Histogram
+type HistogramSnapshot struct {
+	Samples []float64
+	Sum     float64
+	Count   int64
+}
+
+// Average returns the average of the snapshot's observations
+func (s HistogramSnapshot) Average() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / float64(s.Count)
 }
 
 // Observe records a new sample
 func (h *Histogram) Observe(v float64) {
+	if h.guard != nil {
+		h.guard.RLock()
+		defer h.guard.RUnlock()
+	}
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	h.samples = append(h.samples, v)
@@ -61,65 +98,156 @@ func (h *Histogram) Observe(v float64) {
 
 	// Keep only recent samples to prevent memory growth
 	if len(h.samples) > 1000 {
-		h.samples = h.samples[len(h.samples)-500:]
+		h.samples = append([]float64(nil), h.samples[len(h.samples)-500:]...)
+	}
+}
+
+// Snapshot returns a copy of the histogram's current state
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return HistogramSnapshot{
+		Samples: append([]float64(nil), h.samples...),
+		Sum:     h.sum,
+		Count:   h.count,
 	}
 }
 
 // Average returns the average duration
 func (h *Histogram) Average() float64 {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-	if h.count == 0 {
-		return 0
-	}
-	return h.sum / float64(h.count)
+	return h.Snapshot().Average()
 }
 
 // Count returns the number of observations
 func (h *Histogram) Count() int64 {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	return h.count
 }
 
+// swap resets the histogram, returning its previous state
+func (h *Histogram) swap() HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := HistogramSnapshot{Samples: h.samples, Sum: h.sum, Count: h.count}
+	h.samples, h.sum, h.count = nil, 0, 0
+	return s
+}
+
 // NewMetrics creates a new metrics instance
 func NewMetrics() *Metrics {
-	return &Metrics{
-		RequestsTotal:    &Counter{},
-		RequestsErrors:   &Counter{},
-		ProcessingErrors: &Counter{},
-		EventsProcessed:  &Counter{},
-		RequestDuration:  &Histogram{},
-		lastReset:        time.Now(),
+	m := &Metrics{
+		lastReset: time.Now(),
+		unhandled: make(map[EventType]*Counter),
 	}
+	m.RequestsTotal = &Counter{guard: &m.mu}
+	m.RequestsErrors = &Counter{guard: &m.mu}
+	m.ProcessingErrors = &Counter{guard: &m.mu}
+	m.EventsProcessed = &Counter{guard: &m.mu}
+	m.RequestDuration = &Histogram{guard: &m.mu}
+	return m
 }
 
-// GetAll returns all metrics as a map for JSON serialization
-func (m *Metrics) GetAll() map[string]interface{} {
+// IncUnhandled counts an event that had no registered processor
+func (m *Metrics) IncUnhandled(eventType EventType) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	m.unhandledMu.Lock()
+	counter, ok := m.unhandled[eventType]
+	if !ok {
+		// The counter is updated under the read lock already held here
+		counter = &Counter{}
+		m.unhandled[eventType] = counter
+	}
+	m.unhandledMu.Unlock()
+
+	counter.Inc()
+}
+
+// UnhandledEvents returns the number of events without a registered
+// processor, keyed by event type
+func (m *Metrics) UnhandledEvents() map[string]int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.unhandledEvents()
+}
+
+// unhandledEvents copies the unhandled event counts. m.mu must be held
+// exclusively.
+func (m *Metrics) unhandledEvents() map[string]int64 {
+	counts := make(map[string]int64, len(m.unhandled))
+	for eventType, counter := range m.unhandled {
+		counts[string(eventType)] = counter.Value()
+	}
+	return counts
+}
+
+// Snapshot is a consistent point-in-time copy of all metrics
+type Snapshot struct {
+	RequestsTotal    int64
+	RequestsErrors   int64
+	ProcessingErrors int64
+	EventsProcessed  int64
+	RequestDuration  HistogramSnapshot
+	UnhandledEvents  map[string]int64
+	LastReset        time.Time
+}
+
+// Snapshot returns a consistent copy of all metrics
+func (m *Metrics) Snapshot() Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return Snapshot{
+		RequestsTotal:    m.RequestsTotal.Value(),
+		RequestsErrors:   m.RequestsErrors.Value(),
+		ProcessingErrors: m.ProcessingErrors.Value(),
+		EventsProcessed:  m.EventsProcessed.Value(),
+		RequestDuration:  m.RequestDuration.Snapshot(),
+		UnhandledEvents:  m.unhandledEvents(),
+		LastReset:        m.lastReset,
+	}
+}
+
+// GetAll returns all metrics as a map for JSON serialization
+func (m *Metrics) GetAll() map[string]interface{} {
+	s := m.Snapshot()
+
 	return map[string]interface{}{
-		"requests_total":        m.RequestsTotal.Value(),
-		"requests_errors":       m.RequestsErrors.Value(),
-		"processing_errors":     m.ProcessingErrors.Value(),
-		"events_processed":      m.EventsProcessed.Value(),
-		"request_duration_avg":  m.RequestDuration.Average(),
-		"request_count":         m.RequestDuration.Count(),
-		"uptime_seconds":        time.Since(m.lastReset).Seconds(),
-		"last_reset":            m.lastReset.Format(time.RFC3339),
+		"unhandled_events":     s.UnhandledEvents,
+		"requests_total":       s.RequestsTotal,
+		"requests_errors":      s.RequestsErrors,
+		"processing_errors":    s.ProcessingErrors,
+		"events_processed":     s.EventsProcessed,
+		"request_duration_avg": s.RequestDuration.Average(),
+		"request_count":        s.RequestDuration.Count,
+		"uptime_seconds":       time.Since(s.LastReset).Seconds(),
+		"last_reset":           s.LastReset.Format(time.RFC3339),
 	}
 }
 
 // Reset resets all metrics counters
 func (m *Metrics) Reset() {
+	m.SnapshotAndReset()
+}
+
+// SnapshotAndReset atomically resets all metrics, returning their values
+// from just before the reset
+func (m *Metrics) SnapshotAndReset() Snapshot {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.RequestsTotal = &Counter{}
-	m.RequestsErrors = &Counter{}
-	m.ProcessingErrors = &Counter{}
-	m.EventsProcessed = &Counter{}
-	m.RequestDuration = &Histogram{}
+	s := Snapshot{
+		RequestsTotal:    m.RequestsTotal.swap(),
+		RequestsErrors:   m.RequestsErrors.swap(),
+		ProcessingErrors: m.ProcessingErrors.swap(),
+		EventsProcessed:  m.EventsProcessed.swap(),
+		RequestDuration:  m.RequestDuration.swap(),
+		UnhandledEvents:  m.unhandledEvents(),
+		LastReset:        m.lastReset,
+	}
+	m.unhandled = make(map[EventType]*Counter)
 	m.lastReset = time.Now()
-}
\ No newline at end of file
+	return s
+}
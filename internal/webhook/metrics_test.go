@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMetrics_ConcurrentReset checks that no updates are lost when metrics
+// are reset while handlers update them. Run with -race to also check for
+// data races.
+func TestMetrics_ConcurrentReset(t *testing.T) {
+	m := NewMetrics()
+
+	const workers, updates = 8, 2000
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < updates; j++ {
+				m.RequestsTotal.Inc()
+				m.RequestDuration.Observe(1)
+				m.IncUnhandled(EventDomainRenewed)
+			}
+		}()
+	}
+
+	stop, done := make(chan struct{}), make(chan struct{})
+	var requests, observations, unhandled int64
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			s := m.SnapshotAndReset()
+			requests += s.RequestsTotal
+			observations += s.RequestDuration.Count
+			unhandled += s.UnhandledEvents[string(EventDomainRenewed)]
+			_ = m.GetAll()
+		}
+	}()
+
+	wg.Wait()
+	close(stop)
+	<-done
+
+	final := m.SnapshotAndReset()
+	assert.Equal(t, int64(workers*updates), requests+final.RequestsTotal)
+	assert.Equal(t, int64(workers*updates), observations+final.RequestDuration.Count)
+	assert.Equal(t, int64(workers*updates), unhandled+final.UnhandledEvents[string(EventDomainRenewed)])
+}
+
+func TestMetrics_SnapshotIsACopy(t *testing.T) {
+	m := NewMetrics()
+	m.RequestDuration.Observe(1)
+	m.IncUnhandled(EventDomainRenewed)
+
+	s := m.Snapshot()
+	m.RequestDuration.Observe(3)
+	m.IncUnhandled(EventDomainRenewed)
+
+	assert.Equal(t, []float64{1}, s.RequestDuration.Samples)
+	assert.Equal(t, float64(1), s.RequestDuration.Average())
+	assert.Equal(t, int64(1), s.UnhandledEvents[string(EventDomainRenewed)])
+
+	// Counters obtained before a reset keep counting after it
+	counter := m.RequestsTotal
+	m.Reset()
+	counter.Inc()
+	assert.Equal(t, int64(1), m.Snapshot().RequestsTotal)
+}
@@ -28,48 +28,48 @@ func (p *DomainEventProcessor) Process(ctx context.Context, event *WebhookEvent)
 		"timestamp", event.Timestamp)
 
 	// Extract domain information from event data
-	domainName, ok := event.Data["domain"].(string)
-	if !ok {
-		return fmt.Errorf("missing or invalid domain field in event data")
+	data, err := event.DomainData()
+	if err != nil {
+		return err
 	}
 
 	switch event.Type {
 	case EventDomainRegistered:
-		return p.handleDomainRegistered(ctx, domainName, event.Data)
+		return p.handleDomainRegistered(ctx, data)
 	case EventDomainRenewed:
-		return p.handleDomainRenewed(ctx, domainName, event.Data)
+		return p.handleDomainRenewed(ctx, data)
 	case EventDomainExpired:
-		return p.handleDomainExpired(ctx, domainName, event.Data)
+		return p.handleDomainExpired(ctx, data)
 	case EventDomainTransferred:
-		return p.handleDomainTransferred(ctx, domainName, event.Data)
+		return p.handleDomainTransferred(ctx, data)
 	default:
 		return fmt.Errorf("unsupported domain event type: %s", event.Type)
 	}
 }
 
-func (p *DomainEventProcessor) handleDomainRegistered(ctx context.Context, domain string, data map[string]interface{}) error {
-	p.logger.Info("Domain registered successfully", "domain", domain)
+func (p *DomainEventProcessor) handleDomainRegistered(ctx context.Context, data *DomainEventData) error {
+	p.logger.Info("Domain registered successfully", "domain", data.Domain)
 	// Here you could update the domain resource status in Kubernetes
 	// or trigger additional provisioning workflows
 	return nil
 }
 
-func (p *DomainEventProcessor) handleDomainRenewed(ctx context.Context, domain string, data map[string]interface{}) error {
-	p.logger.Info("Domain renewed", "domain", domain)
-	if expiryDate, ok := data["expiry_date"].(string); ok {
-		p.logger.Info("Domain renewal details", "domain", domain, "new_expiry", expiryDate)
+func (p *DomainEventProcessor) handleDomainRenewed(ctx context.Context, data *DomainEventData) error {
+	p.logger.Info("Domain renewed", "domain", data.Domain)
+	if data.ExpiryDate != "" {
+		p.logger.Info("Domain renewal details", "domain", data.Domain, "new_expiry", data.ExpiryDate)
 	}
 	return nil
 }
 
-func (p *DomainEventProcessor) handleDomainExpired(ctx context.Context, domain string, data map[string]interface{}) error {
-	p.logger.Error(nil, "Domain expired", "domain", domain)
+func (p *DomainEventProcessor) handleDomainExpired(ctx context.Context, data *DomainEventData) error {
+	p.logger.Error(nil, "Domain expired", "domain", data.Domain)
 	// Could trigger alerts or automatic renewal workflows
 	return nil
 }
 
-func (p *DomainEventProcessor) handleDomainTransferred(ctx context.Context, domain string, data map[string]interface{}) error {
-	p.logger.Info("Domain transferred", "domain", domain)
+func (p *DomainEventProcessor) handleDomainTransferred(ctx context.Context, data *DomainEventData) error {
+	p.logger.Info("Domain transferred", "domain", data.Domain)
 	return nil
 }
 
@@ -93,51 +93,46 @@ func (p *DNSEventProcessor) Process(ctx context.Context, event *WebhookEvent) er
 		"timestamp", event.Timestamp)
 
 	// Extract DNS record information
-	recordData, ok := event.Data["record"].(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("missing or invalid record field in event data")
+	data, err := event.DNSRecordData()
+	if err != nil {
+		return err
 	}
 
-	recordType, _ := recordData["type"].(string)
-	recordName, _ := recordData["name"].(string)
-	recordValue, _ := recordData["value"].(string)
-	domain, _ := event.Data["domain"].(string)
-
 	switch event.Type {
 	case EventDNSRecordCreated:
-		return p.handleRecordCreated(ctx, domain, recordType, recordName, recordValue, recordData)
+		return p.handleRecordCreated(ctx, data)
 	case EventDNSRecordUpdated:
-		return p.handleRecordUpdated(ctx, domain, recordType, recordName, recordValue, recordData)
+		return p.handleRecordUpdated(ctx, data)
 	case EventDNSRecordDeleted:
-		return p.handleRecordDeleted(ctx, domain, recordType, recordName, recordData)
+		return p.handleRecordDeleted(ctx, data)
 	default:
 		return fmt.Errorf("unsupported DNS event type: %s", event.Type)
 	}
 }
 
-func (p *DNSEventProcessor) handleRecordCreated(ctx context.Context, domain, recordType, name, value string, data map[string]interface{}) error {
+func (p *DNSEventProcessor) handleRecordCreated(ctx context.Context, data *DNSRecordEventData) error {
 	p.logger.Info("DNS record created",
-		"domain", domain,
-		"type", recordType,
-		"name", name,
-		"value", value)
+		"domain", data.Domain,
+		"type", data.Record.Type,
+		"name", data.Record.Name,
+		"value", data.Record.Value)
 	return nil
 }
 
-func (p *DNSEventProcessor) handleRecordUpdated(ctx context.Context, domain, recordType, name, value string, data map[string]interface{}) error {
+func (p *DNSEventProcessor) handleRecordUpdated(ctx context.Context, data *DNSRecordEventData) error {
 	p.logger.Info("DNS record updated",
-		"domain", domain,
-		"type", recordType,
-		"name", name,
-		"value", value)
+		"domain", data.Domain,
+		"type", data.Record.Type,
+		"name", data.Record.Name,
+		"value", data.Record.Value)
 	return nil
 }
 
-func (p *DNSEventProcessor) handleRecordDeleted(ctx context.Context, domain, recordType, name string, data map[string]interface{}) error {
+func (p *DNSEventProcessor) handleRecordDeleted(ctx context.Context, data *DNSRecordEventData) error {
 	p.logger.Info("DNS record deleted",
-		"domain", domain,
-		"type", recordType,
-		"name", name)
+		"domain", data.Domain,
+		"type", data.Record.Type,
+		"name", data.Record.Name)
 	return nil
 }
 
@@ -161,40 +156,42 @@ func (p *SSLEventProcessor) Process(ctx context.Context, event *WebhookEvent) er
 		"timestamp", event.Timestamp)
 
 	// Extract SSL certificate information
-	certID, _ := event.Data["certificate_id"].(string)
-	domain, _ := event.Data["domain"].(string)
+	data, err := event.SSLData()
+	if err != nil {
+		return err
+	}
 
 	switch event.Type {
 	case EventSSLIssued:
-		return p.handleSSLIssued(ctx, certID, domain, event.Data)
+		return p.handleSSLIssued(ctx, data)
 	case EventSSLRenewed:
-		return p.handleSSLRenewed(ctx, certID, domain, event.Data)
+		return p.handleSSLRenewed(ctx, data)
 	case EventSSLExpired:
-		return p.handleSSLExpired(ctx, certID, domain, event.Data)
+		return p.handleSSLExpired(ctx, data)
 	case EventSSLRevoked:
-		return p.handleSSLRevoked(ctx, certID, domain, event.Data)
+		return p.handleSSLRevoked(ctx, data)
 	default:
 		return fmt.Errorf("unsupported SSL event type: %s", event.Type)
 	}
 }
 
-func (p *SSLEventProcessor) handleSSLIssued(ctx context.Context, certID, domain string, data map[string]interface{}) error {
-	p.logger.Info("SSL certificate issued", "cert_id", certID, "domain", domain)
+func (p *SSLEventProcessor) handleSSLIssued(ctx context.Context, data *SSLEventData) error {
+	p.logger.Info("SSL certificate issued", "cert_id", data.CertificateID, "domain", data.Domain)
 	return nil
 }
 
-func (p *SSLEventProcessor) handleSSLRenewed(ctx context.Context, certID, domain string, data map[string]interface{}) error {
-	p.logger.Info("SSL certificate renewed", "cert_id", certID, "domain", domain)
+func (p *SSLEventProcessor) handleSSLRenewed(ctx context.Context, data *SSLEventData) error {
+	p.logger.Info("SSL certificate renewed", "cert_id", data.CertificateID, "domain", data.Domain)
 	return nil
 }
 
-func (p *SSLEventProcessor) handleSSLExpired(ctx context.Context, certID, domain string, data map[string]interface{}) error {
-	p.logger.Error(nil, "SSL certificate expired", "cert_id", certID, "domain", domain)
+func (p *SSLEventProcessor) handleSSLExpired(ctx context.Context, data *SSLEventData) error {
+	p.logger.Error(nil, "SSL certificate expired", "cert_id", data.CertificateID, "domain", data.Domain)
 	return nil
 }
 
-func (p *SSLEventProcessor) handleSSLRevoked(ctx context.Context, certID, domain string, data map[string]interface{}) error {
-	p.logger.Error(nil, "SSL certificate revoked", "cert_id", certID, "domain", domain)
+func (p *SSLEventProcessor) handleSSLRevoked(ctx context.Context, data *SSLEventData) error {
+	p.logger.Error(nil, "SSL certificate revoked", "cert_id", data.CertificateID, "domain", data.Domain)
 	return nil
 }
 
@@ -220,10 +217,15 @@ func (p *AccountEventProcessor) Process(ctx context.Context, event *WebhookEvent
 	switch event.Type {
 	case EventAccountUpdated:
 		return p.handleAccountUpdated(ctx, event.Data)
-	case EventPaymentReceived:
-		return p.handlePaymentReceived(ctx, event.Data)
-	case EventPaymentFailed:
-		return p.handlePaymentFailed(ctx, event.Data)
+	case EventPaymentReceived, EventPaymentFailed:
+		data, err := event.PaymentData()
+		if err != nil {
+			return err
+		}
+		if event.Type == EventPaymentReceived {
+			return p.handlePaymentReceived(ctx, data)
+		}
+		return p.handlePaymentFailed(ctx, data)
 	default:
 		return fmt.Errorf("unsupported account event type: %s", event.Type)
 	}
@@ -234,22 +236,16 @@ func (p *AccountEventProcessor) handleAccountUpdated(ctx context.Context, data m
 	return nil
 }
 
-func (p *AccountEventProcessor) handlePaymentReceived(ctx context.Context, data map[string]interface{}) error {
-	amount, _ := data["amount"].(float64)
-	currency, _ := data["currency"].(string)
-	p.logger.Info("Payment received", "amount", amount, "currency", currency)
+func (p *AccountEventProcessor) handlePaymentReceived(ctx context.Context, data *PaymentEventData) error {
+	p.logger.Info("Payment received", "amount", *data.Amount, "currency", data.Currency)
 	return nil
 }
 
-func (p *AccountEventProcessor) handlePaymentFailed(ctx context.Context, data map[string]interface{}) error {
-	amount, _ := data["amount"].(float64)
-	currency, _ := data["currency"].(string)
-	reason, _ := data["reason"].(string)
-
+func (p *AccountEventProcessor) handlePaymentFailed(ctx context.Context, data *PaymentEventData) error {
 	p.logger.Error(nil, "Payment failed",
-		"amount", amount,
-		"currency", currency,
-		"reason", reason)
+		"amount", *data.Amount,
+		"currency", data.Currency,
+		"reason", data.Reason)
 
 	// Could trigger alerts or retry mechanisms
 	return nil
@@ -281,4 +277,4 @@ func (p *LoggingEventProcessor) Process(ctx context.Context, event *WebhookEvent
 		"event_data", string(eventJSON))
 
 	return nil
-}
\ No newline at end of file
+}
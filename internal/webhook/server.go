@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -19,26 +21,47 @@ import (
 
 // Server represents a webhook server for processing Namecheap events
 type Server struct {
-	router     *mux.Router
-	server     *http.Server
-	logger     logr.Logger
-	secret     string
-	processors map[EventType]EventProcessor
-	metrics    *Metrics
+	router  *mux.Router
+	server  *http.Server
+	logger  logr.Logger
+	secret  string
+	metrics *Metrics
+
+	strictEventTypes bool
+	eventTimeout     time.Duration
+
+	// processorsMu guards processors, which may change while the server runs
+	processorsMu sync.RWMutex
+	processors   map[EventType]EventProcessor
 }
 
 // Config holds webhook server configuration
 type Config struct {
-	Port          int
-	Path          string
-	Secret        string
-	Logger        logr.Logger
-	TLSCertFile   string
-	TLSKeyFile    string
-	ReadTimeout   time.Duration
-	WriteTimeout  time.Duration
+	Port         int
+	Path         string
+	Secret       string
+	Logger       logr.Logger
+	TLSCertFile  string
+	TLSKeyFile   string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// StrictEventTypes rejects events whose type is not a known EventType
+	// with 422 Unprocessable Entity instead of silently accepting them.
+	// Known event types without a registered processor are still accepted.
+	StrictEventTypes bool
+
+	// EventTimeout bounds how long a processor may run for a single event.
+	// Processing is decoupled from the HTTP request, so a sender that
+	// disconnects early does not abort a processor mid-work. Defaults to
+	// DefaultEventTimeout.
+	EventTimeout time.Duration
 }
 
+// DefaultEventTimeout is the per-event processing timeout used when
+// Config.EventTimeout is not set
+const DefaultEventTimeout = 30 * time.Second
+
 // DefaultConfig returns sensible defaults for webhook server
 func DefaultConfig() Config {
 	return Config{
@@ -46,6 +69,7 @@ func DefaultConfig() Config {
 		Path:         "/webhook",
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
+		EventTimeout: DefaultEventTimeout,
 	}
 }
 
@@ -54,31 +78,55 @@ type EventType string
 
 const (
 	// Domain events
-	EventDomainRegistered   EventType = "domain.registered"
-	EventDomainRenewed      EventType = "domain.renewed"
-	EventDomainExpired      EventType = "domain.expired"
-	EventDomainTransferred  EventType = "domain.transferred"
+	EventDomainRegistered  EventType = "domain.registered"
+	EventDomainRenewed     EventType = "domain.renewed"
+	EventDomainExpired     EventType = "domain.expired"
+	EventDomainTransferred EventType = "domain.transferred"
 
 	// DNS events
-	EventDNSRecordCreated   EventType = "dns.record.created"
-	EventDNSRecordUpdated   EventType = "dns.record.updated"
-	EventDNSRecordDeleted   EventType = "dns.record.deleted"
+	EventDNSRecordCreated EventType = "dns.record.created"
+	EventDNSRecordUpdated EventType = "dns.record.updated"
+	EventDNSRecordDeleted EventType = "dns.record.deleted"
 
 	// SSL events
-	EventSSLIssued          EventType = "ssl.issued"
-	EventSSLRenewed         EventType = "ssl.renewed"
-	EventSSLExpired         EventType = "ssl.expired"
-	EventSSLRevoked         EventType = "ssl.revoked"
+	EventSSLIssued  EventType = "ssl.issued"
+	EventSSLRenewed EventType = "ssl.renewed"
+	EventSSLExpired EventType = "ssl.expired"
+	EventSSLRevoked EventType = "ssl.revoked"
 
 	// Account events
-	EventAccountUpdated     EventType = "account.updated"
-	EventPaymentReceived    EventType = "payment.received"
-	EventPaymentFailed      EventType = "payment.failed"
+	EventAccountUpdated  EventType = "account.updated"
+	EventPaymentReceived EventType = "payment.received"
+	EventPaymentFailed   EventType = "payment.failed"
 )
 
+// knownEventTypes is the set of event types Namecheap may send
+var knownEventTypes = map[EventType]bool{
+	EventDomainRegistered:  true,
+	EventDomainRenewed:     true,
+	EventDomainExpired:     true,
+	EventDomainTransferred: true,
+	EventDNSRecordCreated:  true,
+	EventDNSRecordUpdated:  true,
+	EventDNSRecordDeleted:  true,
+	EventSSLIssued:         true,
+	EventSSLRenewed:        true,
+	EventSSLExpired:        true,
+	EventSSLRevoked:        true,
+	EventAccountUpdated:    true,
+	EventPaymentReceived:   true,
+	EventPaymentFailed:     true,
+}
+
+// IsKnownEventType reports whether the event type is one Namecheap may send
+func IsKnownEventType(eventType EventType) bool {
+	return knownEventTypes[eventType]
+}
+
 // WebhookEvent represents a Namecheap webhook event
 type WebhookEvent struct {
 	ID        string                 `json:"id"`
+	Version   string                 `json:"version,omitempty"`
 	Type      EventType              `json:"type"`
 	Timestamp time.Time              `json:"timestamp"`
 	Data      map[string]interface{} `json:"data"`
@@ -102,6 +150,9 @@ func NewServer(config Config) *Server {
 	if config.Logger.GetSink() == nil {
 		config.Logger = logr.Discard()
 	}
+	if config.EventTimeout <= 0 {
+		config.EventTimeout = DefaultEventTimeout
+	}
 
 	router := mux.NewRouter()
 
@@ -119,6 +170,9 @@ func NewServer(config Config) *Server {
 		secret:     config.Secret,
 		processors: make(map[EventType]EventProcessor),
 		metrics:    NewMetrics(),
+
+		strictEventTypes: config.StrictEventTypes,
+		eventTimeout:     config.EventTimeout,
 	}
 
 	// Setup routes
@@ -129,12 +183,46 @@ func NewServer(config Config) *Server {
 	return s
 }
 
-// RegisterProcessor registers an event processor for a specific event type
+// RegisterProcessor registers an event processor for a specific event type.
+// It is safe to call while the server is handling requests.
 func (s *Server) RegisterProcessor(eventType EventType, processor EventProcessor) {
+	s.processorsMu.Lock()
 	s.processors[eventType] = processor
+	s.processorsMu.Unlock()
 	s.logger.Info("Registered webhook event processor", "eventType", eventType)
 }
 
+// UnregisterProcessor removes the event processor for a specific event type.
+// It is safe to call while the server is handling requests.
+func (s *Server) UnregisterProcessor(eventType EventType) {
+	s.processorsMu.Lock()
+	delete(s.processors, eventType)
+	s.processorsMu.Unlock()
+	s.logger.Info("Unregistered webhook event processor", "eventType", eventType)
+}
+
+// RegisteredEventTypes returns a sorted snapshot of the event types that
+// currently have a processor registered
+func (s *Server) RegisteredEventTypes() []EventType {
+	s.processorsMu.RLock()
+	defer s.processorsMu.RUnlock()
+
+	types := make([]EventType, 0, len(s.processors))
+	for t := range s.processors {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// processor returns the processor registered for an event type
+func (s *Server) processor(eventType EventType) (EventProcessor, bool) {
+	s.processorsMu.RLock()
+	defer s.processorsMu.RUnlock()
+	processor, exists := s.processors[eventType]
+	return processor, exists
+}
+
 // Start starts the webhook server
 func (s *Server) Start(ctx context.Context, tlsCertFile, tlsKeyFile string) error {
 	s.logger.Info("Starting webhook server", "addr", s.server.Addr)
@@ -205,14 +293,44 @@ func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
 		"timestamp", event.Timestamp)
 
 	// Process the event
-	processor, exists := s.processors[event.Type]
+	processor, exists := s.processor(event.Type)
 	if !exists {
-		s.logger.Info("No processor registered for event type", "type", event.Type)
+		s.metrics.IncUnhandled(event.Type)
+
+		if !IsKnownEventType(event.Type) {
+			s.logger.Info("Received webhook event of unknown type",
+				"severity", "warning",
+				"id", event.ID,
+				"type", event.Type,
+				"strict", s.strictEventTypes)
+			if s.strictEventTypes {
+				http.Error(w, fmt.Sprintf("Unknown event type: %s", event.Type), http.StatusUnprocessableEntity)
+				return
+			}
+		} else {
+			s.logger.Info("No processor registered for event type",
+				"severity", "warning",
+				"id", event.ID,
+				"type", event.Type)
+		}
+
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	// Validate the payload against the schema of its event family
+	if err := event.Validate(); err != nil {
+		s.logger.Error(err, "Invalid webhook event payload",
+			"id", event.ID,
+			"type", event.Type)
+		s.metrics.RequestsErrors.Inc()
+		http.Error(w, "Invalid event payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Detach from the request so that a sender disconnecting early does not
+	// cancel a processor that is part-way through its work
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), s.eventTimeout)
 	defer cancel()
 
 	if err := processor.Process(ctx, &event); err != nil {
@@ -262,15 +380,9 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(map[string]interface{}{
-		"status":    "healthy",
-		"timestamp": time.Now(),
-		"processors": func() []string {
-			types := make([]string, 0, len(s.processors))
-			for t := range s.processors {
-				types = append(types, string(t))
-			}
-			return types
-		}(),
+		"status":     "healthy",
+		"timestamp":  time.Now(),
+		"processors": s.RegisteredEventTypes(),
 	}); err != nil {
 		s.logger.Error(err, "Failed to encode health response")
 	}
@@ -285,4 +397,4 @@ func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
 	if err := json.NewEncoder(w).Encode(s.metrics.GetAll()); err != nil {
 		s.logger.Error(err, "Failed to encode metrics response")
 	}
-}
\ No newline at end of file
+}
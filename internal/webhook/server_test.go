@@ -10,10 +10,12 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -191,7 +193,10 @@ func TestWebhookServer(t *testing.T) {
 			ID:        "test-event-id",
 			Type:      EventDNSRecordCreated,
 			Timestamp: time.Now(),
-			Data:      map[string]interface{}{"domain": "example.com"},
+			Data: map[string]interface{}{
+				"domain": "example.com",
+				"record": map[string]interface{}{"type": "A", "name": "www", "value": "192.168.1.1"},
+			},
 		}
 
 		body, err := json.Marshal(event)
@@ -412,4 +417,253 @@ func TestEventProcessors(t *testing.T) {
 		err := processor.Process(context.Background(), event)
 		assert.NoError(t, err)
 	})
-}
\ No newline at end of file
+}
+
+func TestServer_ConcurrentRegistration(t *testing.T) {
+	server := NewServer(Config{
+		Port:   8080,
+		Path:   "/webhook",
+		Logger: logr.Discard(),
+	})
+
+	noop := EventProcessorFunc(func(ctx context.Context, event *WebhookEvent) error {
+		return nil
+	})
+
+	body, err := json.Marshal(WebhookEvent{
+		ID:        "test-event-id",
+		Type:      EventDomainRenewed,
+		Timestamp: time.Now(),
+		Data:      map[string]interface{}{"domain": "example.com"},
+	})
+	require.NoError(t, err)
+
+	eventTypes := []EventType{EventDomainRegistered, EventDomainRenewed, EventDomainExpired, EventDNSRecordCreated}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(3)
+
+		go func(i int) {
+			defer wg.Done()
+			eventType := eventTypes[i%len(eventTypes)]
+			server.RegisterProcessor(eventType, noop)
+			if i%3 == 0 {
+				server.UnregisterProcessor(eventType)
+			}
+		}(i)
+
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest("POST", "/webhook", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+			server.handleWebhook(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+		}()
+
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			server.handleHealth(w, httptest.NewRequest("GET", "/health", nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+		}()
+	}
+	wg.Wait()
+
+	// Registration after the concurrent phase is reflected in the snapshot
+	server.RegisterProcessor(EventSSLIssued, noop)
+	assert.Contains(t, server.RegisteredEventTypes(), EventSSLIssued)
+
+	server.UnregisterProcessor(EventSSLIssued)
+	assert.NotContains(t, server.RegisteredEventTypes(), EventSSLIssued)
+}
+
+func TestServer_UnhandledEvents(t *testing.T) {
+	send := func(server *Server, eventType EventType) int {
+		body, err := json.Marshal(WebhookEvent{
+			ID:        "test-event-id",
+			Type:      eventType,
+			Timestamp: time.Now(),
+			Data:      map[string]interface{}{"domain": "example.com"},
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("POST", "/webhook", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		server.handleWebhook(w, req)
+		return w.Code
+	}
+
+	tests := []struct {
+		name           string
+		strict         bool
+		eventType      EventType
+		expectedStatus int
+		expectedLog    string
+	}{
+		{
+			name:           "known unregistered type",
+			eventType:      EventDomainRenewed,
+			expectedStatus: http.StatusOK,
+			expectedLog:    "No processor registered for event type",
+		},
+		{
+			name:           "unknown type",
+			eventType:      EventType("domain.unknown"),
+			expectedStatus: http.StatusOK,
+			expectedLog:    "Received webhook event of unknown type",
+		},
+		{
+			name:           "known unregistered type in strict mode",
+			strict:         true,
+			eventType:      EventDomainRenewed,
+			expectedStatus: http.StatusOK,
+			expectedLog:    "No processor registered for event type",
+		},
+		{
+			name:           "unknown type in strict mode",
+			strict:         true,
+			eventType:      EventType("domain.unknown"),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedLog:    "Received webhook event of unknown type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Warnings are logged at the default level with a severity
+			var warnings []map[string]interface{}
+			logger := funcr.NewJSON(func(obj string) {
+				entry := map[string]interface{}{}
+				require.NoError(t, json.Unmarshal([]byte(obj), &entry))
+				if _, ok := entry["severity"]; ok {
+					warnings = append(warnings, entry)
+				}
+			}, funcr.Options{})
+
+			server := NewServer(Config{
+				Port:             8080,
+				Path:             "/webhook",
+				Logger:           logger,
+				StrictEventTypes: tt.strict,
+			})
+
+			assert.Equal(t, tt.expectedStatus, send(server, tt.eventType))
+			assert.Equal(t, tt.expectedStatus, send(server, tt.eventType))
+
+			require.Len(t, warnings, 2)
+			for _, warning := range warnings {
+				assert.Equal(t, tt.expectedLog, warning["msg"])
+				assert.Equal(t, "warning", warning["severity"])
+				assert.Equal(t, float64(0), warning["level"])
+				assert.NotContains(t, warning, "error")
+				assert.Equal(t, string(tt.eventType), warning["type"])
+			}
+
+			unhandled := server.metrics.UnhandledEvents()
+			assert.Equal(t, map[string]int64{string(tt.eventType): 2}, unhandled)
+			assert.Equal(t, unhandled, server.metrics.GetAll()["unhandled_events"])
+		})
+	}
+}
+
+func TestServer_ProcessingOutlivesRequest(t *testing.T) {
+	server := NewServer(Config{
+		Port:         8080,
+		Path:         "/webhook",
+		Logger:       logr.Discard(),
+		EventTimeout: 5 * time.Second,
+	})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	server.RegisterProcessor(EventDomainRegistered, EventProcessorFunc(func(ctx context.Context, event *WebhookEvent) error {
+		close(started)
+		<-release
+		err := ctx.Err()
+		done <- err
+		return err
+	}))
+
+	requestCtx := make(chan context.Context, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCtx <- r.Context()
+		server.handleWebhook(w, r)
+	}))
+	defer ts.Close()
+
+	body, err := json.Marshal(WebhookEvent{
+		ID:        "test-event-id",
+		Type:      EventDomainRegistered,
+		Timestamp: time.Now(),
+		Data:      map[string]interface{}{"domain": "example.com"},
+	})
+	require.NoError(t, err)
+
+	ctx, disconnect := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, "POST", ts.URL, bytes.NewReader(body))
+	require.NoError(t, err)
+
+	clientErr := make(chan error, 1)
+	go func() {
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		clientErr <- err
+	}()
+
+	// Disconnect as soon as the processor has picked up the event
+	<-started
+	disconnect()
+	assert.Error(t, <-clientErr)
+
+	select {
+	case <-(<-requestCtx).Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("request context was not cancelled after the client disconnected")
+	}
+
+	close(release)
+	select {
+	case err := <-done:
+		assert.NoError(t, err, "processor context should survive the client disconnecting")
+	case <-time.After(5 * time.Second):
+		t.Fatal("processor did not complete")
+	}
+
+	assert.Eventually(t, func() bool {
+		return server.metrics.EventsProcessed.Value() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(0), server.metrics.ProcessingErrors.Value())
+}
+
+func TestServer_EventTimeout(t *testing.T) {
+	server := NewServer(Config{
+		Port:         8080,
+		Path:         "/webhook",
+		Logger:       logr.Discard(),
+		EventTimeout: 10 * time.Millisecond,
+	})
+
+	server.RegisterProcessor(EventDomainRegistered, EventProcessorFunc(func(ctx context.Context, event *WebhookEvent) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	body, err := json.Marshal(WebhookEvent{
+		ID:        "test-event-id",
+		Type:      EventDomainRegistered,
+		Timestamp: time.Now(),
+		Data:      map[string]interface{}{"domain": "example.com"},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/webhook", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	server.handleWebhook(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, int64(1), server.metrics.ProcessingErrors.Value())
+}
@@ -0,0 +1,115 @@
+package namecheap
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Statuses of an add funds request reported by users.getAddFundsStatus
+const (
+	// AddFundsStatusCreated is reported until the payment is made
+	AddFundsStatusCreated = "CREATED"
+	// AddFundsStatusCompleted is reported once the funds are added
+	AddFundsStatusCompleted = "COMPLETED"
+	// AddFundsStatusFailed is reported when the payment failed
+	AddFundsStatusFailed = "FAILED"
+	// AddFundsStatusExpired is reported when the request expired unpaid
+	AddFundsStatusExpired = "EXPIRED"
+)
+
+// addFundsPaymentType is the only payment type
+// users.createaddfundsrequest accepts
+const addFundsPaymentType = "Creditcard"
+
+// AddFundsRequest is a request to add funds to the account, created by
+// users.createaddfundsrequest. The funds are added once the payment is
+// made at RedirectURL, after which Namecheap sends the user to ReturnURL.
+type AddFundsRequest struct {
+	TokenID     string `xml:"TokenID,attr"`
+	ReturnURL   string `xml:"ReturnURL,attr"`
+	RedirectURL string `xml:"RedirectURL,attr"`
+}
+
+// AddFundsStatus is the status of an add funds request. TransactionID and
+// Amount are only reported once the funds are added.
+type AddFundsStatus struct {
+	TransactionID string  `xml:"TransactionID,attr"`
+	Amount        float64 `xml:"Amount,attr"`
+	Status        string  `xml:"Status,attr"`
+}
+
+// Pending reports whether the request is waiting for its payment
+func (s AddFundsStatus) Pending() bool {
+	return s.Status == AddFundsStatusCreated
+}
+
+// Completed reports whether the funds were added to the account
+func (s AddFundsStatus) Completed() bool {
+	return s.Status == AddFundsStatusCompleted
+}
+
+// AddFundsRequestResponse represents the response from
+// users.createaddfundsrequest
+type AddFundsRequestResponse struct {
+	APIResponse
+	CommandResponse struct {
+		CreateAddFundsRequestResult AddFundsRequest `xml:"Createaddfundsrequestresult"`
+	} `xml:"CommandResponse"`
+}
+
+// AddFundsStatusResponse represents the response from
+// users.getAddFundsStatus
+type AddFundsStatusResponse struct {
+	APIResponse
+	CommandResponse struct {
+		GetAddFundsStatusResult AddFundsStatus `xml:"GetAddFundsStatusResult"`
+	} `xml:"CommandResponse"`
+}
+
+// CreateAddFundsRequest starts adding amount, in the account's currency, to
+// the account. The funds are only added once the payment is made at the
+// returned request's RedirectURL; track it with GetAddFundsStatus.
+func (c *Client) CreateAddFundsRequest(ctx context.Context, amount float64, returnURL string) (*AddFundsRequest, error) {
+	if amount <= 0 {
+		return nil, errors.Errorf("amount to add must be positive, got %.2f", amount)
+	}
+
+	resp, err := c.makeRequest(ctx, CommandUsersCreateAddFundsRequest, newParams().
+		set("Username", c.username).
+		set("PaymentType", addFundsPaymentType).
+		set("Amount", strconv.FormatFloat(amount, 'f', 2, 64)).
+		setRequired("ReturnUrl", returnURL))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make users.createaddfundsrequest request")
+	}
+
+	var result AddFundsRequestResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse users.createaddfundsrequest response")
+	}
+
+	request := result.CommandResponse.CreateAddFundsRequestResult
+	if request.TokenID == "" {
+		return nil, errors.New("users.createaddfundsrequest returned no token")
+	}
+	return &request, nil
+}
+
+// GetAddFundsStatus retrieves the status of the add funds request with
+// tokenID
+func (c *Client) GetAddFundsStatus(ctx context.Context, tokenID string) (*AddFundsStatus, error) {
+	resp, err := c.makeRequest(ctx, CommandUsersGetAddFundsStatus, newParams().
+		setRequired("TokenId", tokenID))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make users.getAddFundsStatus request")
+	}
+
+	var result AddFundsStatusResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse users.getAddFundsStatus response")
+	}
+
+	return &result.CommandResponse.GetAddFundsStatusResult, nil
+}
@@ -0,0 +1,94 @@
+package namecheap
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_CreateAddFundsRequest(t *testing.T) {
+	client := newCommandClient(t, CommandUsersCreateAddFundsRequest, string(commandFixture(t, CommandUsersCreateAddFundsRequest)), func(r *http.Request) {
+		assert.Equal(t, "testuser", r.FormValue("Username"))
+		assert.Equal(t, "Creditcard", r.FormValue("PaymentType"))
+		assert.Equal(t, "100.50", r.FormValue("Amount"))
+		assert.Equal(t, "https://example.com/funds/return", r.FormValue("ReturnUrl"))
+	})
+
+	request, err := client.CreateAddFundsRequest(context.Background(), 100.5, "https://example.com/funds/return")
+	require.NoError(t, err)
+	assert.Equal(t, &AddFundsRequest{
+		TokenID:     "3b9f1a5e7c2d4e8f",
+		ReturnURL:   "https://example.com/funds/return",
+		RedirectURL: "https://ap.www.namecheap.com/myaccount/addfunds.aspx?token=3b9f1a5e7c2d4e8f",
+	}, request)
+}
+
+func TestClient_CreateAddFundsRequest_Invalid(t *testing.T) {
+	client := newCommandClient(t, CommandUsersCreateAddFundsRequest, "", func(*http.Request) {
+		t.Error("invalid request was sent to the API")
+	})
+
+	_, err := client.CreateAddFundsRequest(context.Background(), 0, "https://example.com/funds/return")
+	assert.EqualError(t, err, "amount to add must be positive, got 0.00")
+
+	_, err = client.CreateAddFundsRequest(context.Background(), 10, "")
+	assert.ErrorContains(t, err, "missing required parameter ReturnUrl")
+}
+
+func TestClient_GetAddFundsStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		want      AddFundsStatus
+		pending   bool
+		completed bool
+	}{
+		{
+			name:      "Completed",
+			want:      AddFundsStatus{TransactionID: "1483951", Amount: 100, Status: AddFundsStatusCompleted},
+			completed: true,
+		},
+		{
+			name: "Pending",
+			body: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<GetAddFundsStatusResult TransactionID="" Amount="" Status="CREATED"/>
+	</CommandResponse>
+</ApiResponse>`,
+			want:    AddFundsStatus{Status: AddFundsStatusCreated},
+			pending: true,
+		},
+		{
+			name: "Expired",
+			body: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<GetAddFundsStatusResult Status="EXPIRED"/>
+	</CommandResponse>
+</ApiResponse>`,
+			want: AddFundsStatus{Status: AddFundsStatusExpired},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := tt.body
+			if body == "" {
+				body = string(commandFixture(t, CommandUsersGetAddFundsStatus))
+			}
+			client := newCommandClient(t, CommandUsersGetAddFundsStatus, body, func(r *http.Request) {
+				assert.Equal(t, "3b9f1a5e7c2d4e8f", r.FormValue("TokenId"))
+			})
+
+			status, err := client.GetAddFundsStatus(context.Background(), "3b9f1a5e7c2d4e8f")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *status)
+			assert.Equal(t, tt.pending, status.Pending())
+			assert.Equal(t, tt.completed, status.Completed())
+		})
+	}
+}
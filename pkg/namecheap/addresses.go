@@ -0,0 +1,341 @@
+package namecheap
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AccountAddressSummary is an address as users.address.getList lists it
+type AccountAddressSummary struct {
+	ID        int    `xml:"AddressId,attr"`
+	Name      string `xml:"AddressName,attr"`
+	IsDefault bool   `xml:"IsDefault,attr"`
+}
+
+// AccountAddress is one of the addresses registered with the account, which
+// Namecheap offers as the contacts of new domains. Fields map onto the
+// parameters of users.address.create and users.address.update, and onto the
+// elements users.address.getInfo returns.
+type AccountAddress struct {
+	// ID identifies the address. It is ignored when creating one.
+	ID int `xml:"AddressId"`
+
+	// Name is the name the address is listed under
+	Name string `xml:"AddressName"`
+
+	// IsDefault is set for the account's default address
+	IsDefault bool `xml:"Default_YN"`
+
+	OrganizationName    string `xml:"Organization"`
+	JobTitle            string `xml:"JobTitle"`
+	FirstName           string `xml:"FirstName"`
+	LastName            string `xml:"LastName"`
+	Address1            string `xml:"Address1"`
+	Address2            string `xml:"Address2"`
+	City                string `xml:"City"`
+	StateProvince       string `xml:"StateProvince"`
+	StateProvinceChoice string `xml:"StateProvinceChoice"`
+	PostalCode          string `xml:"Zip"`
+	Country             string `xml:"Country"`
+	// Phone is in the format +NNN.NNNNNNNNNN
+	Phone        string `xml:"Phone"`
+	PhoneExt     string `xml:"PhoneExt"`
+	Fax          string `xml:"Fax"`
+	EmailAddress string `xml:"EmailAddress"`
+}
+
+// AccountAddressListResponse represents the response from
+// users.address.getList
+type AccountAddressListResponse struct {
+	APIResponse
+	CommandResponse struct {
+		AddressGetListResult struct {
+			Addresses []AccountAddressSummary `xml:"List"`
+		} `xml:"AddressGetListResult"`
+	} `xml:"CommandResponse"`
+}
+
+// AccountAddressInfoResponse represents the response from
+// users.address.getInfo
+type AccountAddressInfoResponse struct {
+	APIResponse
+	CommandResponse struct {
+		GetAddressInfoResult AccountAddress `xml:"GetAddressInfoResult"`
+	} `xml:"CommandResponse"`
+}
+
+// AccountAddressCreateResponse represents the response from
+// users.address.create
+type AccountAddressCreateResponse struct {
+	APIResponse
+	CommandResponse struct {
+		AddressCreateResult struct {
+			Success     bool   `xml:"Success,attr"`
+			AddressID   int    `xml:"AddressId,attr"`
+			AddressName string `xml:"AddressName,attr"`
+		} `xml:"AddressCreateResult"`
+	} `xml:"CommandResponse"`
+}
+
+// AccountAddressUpdateResponse represents the response from
+// users.address.update
+type AccountAddressUpdateResponse struct {
+	APIResponse
+	CommandResponse struct {
+		AddressUpdateResult struct {
+			Success     bool   `xml:"Success,attr"`
+			AddressID   int    `xml:"AddressId,attr"`
+			AddressName string `xml:"AddressName,attr"`
+		} `xml:"AddressUpdateResult"`
+	} `xml:"CommandResponse"`
+}
+
+// AccountAddressDeleteResponse represents the response from
+// users.address.delete
+type AccountAddressDeleteResponse struct {
+	APIResponse
+	CommandResponse struct {
+		AddressDeleteResult struct {
+			Success   bool   `xml:"Success,attr"`
+			ProfileID int    `xml:"ProfileId,attr"`
+			UserName  string `xml:"UserName,attr"`
+		} `xml:"AddressDeleteResult"`
+	} `xml:"CommandResponse"`
+}
+
+// AccountAddressSetDefaultResponse represents the response from
+// users.address.setDefault
+type AccountAddressSetDefaultResponse struct {
+	APIResponse
+	CommandResponse struct {
+		AddressSetDefaultResult struct {
+			Success   bool `xml:"Success,attr"`
+			AddressID int  `xml:"AddressId,attr"`
+		} `xml:"AddressSetDefaultResult"`
+	} `xml:"CommandResponse"`
+}
+
+// Contact returns the address as a domain contact
+func (a AccountAddress) Contact() Contact {
+	return Contact{
+		OrganizationName:    a.OrganizationName,
+		JobTitle:            a.JobTitle,
+		FirstName:           a.FirstName,
+		LastName:            a.LastName,
+		Address1:            a.Address1,
+		Address2:            a.Address2,
+		City:                a.City,
+		StateProvince:       a.StateProvince,
+		StateProvinceChoice: a.StateProvinceChoice,
+		PostalCode:          a.PostalCode,
+		Country:             a.Country,
+		Phone:               a.Phone,
+		PhoneExt:            a.PhoneExt,
+		Fax:                 a.Fax,
+		EmailAddress:        a.EmailAddress,
+	}
+}
+
+// Validate checks that the address has the fields Namecheap requires and
+// that its phone and fax numbers are in the format it accepts
+func (a AccountAddress) Validate() error {
+	var missing []string
+	for _, f := range []struct{ name, value string }{
+		{"Name", a.Name},
+		{"FirstName", a.FirstName},
+		{"LastName", a.LastName},
+		{"Address1", a.Address1},
+		{"City", a.City},
+		{"StateProvince", a.StateProvince},
+		{"PostalCode", a.PostalCode},
+		{"Country", a.Country},
+		{"Phone", a.Phone},
+		{"EmailAddress", a.EmailAddress},
+	} {
+		if strings.TrimSpace(f.value) == "" {
+			missing = append(missing, f.name)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("address is missing %s", strings.Join(missing, ", "))
+	}
+
+	if !phonePattern.MatchString(a.Phone) {
+		return errors.Errorf("address phone %q must be in the format +NNN.NNNNNNNNNN", a.Phone)
+	}
+	if a.Fax != "" && !phonePattern.MatchString(a.Fax) {
+		return errors.Errorf("address fax %q must be in the format +NNN.NNNNNNNNNN", a.Fax)
+	}
+	if !strings.Contains(a.EmailAddress, "@") {
+		return errors.Errorf("address email address %q is not valid", a.EmailAddress)
+	}
+	return nil
+}
+
+// addParams adds the address's fields to params, named as
+// users.address.create and users.address.update name them
+func (a AccountAddress) addParams(params *params) {
+	params.
+		setRequired("AddressName", a.Name).
+		set("DefaultYN", oneZero(a.IsDefault)).
+		setOptional("Organization", a.OrganizationName).
+		setOptional("JobTitle", a.JobTitle).
+		setRequired("FirstName", a.FirstName).
+		setRequired("LastName", a.LastName).
+		setRequired("Address1", a.Address1).
+		setOptional("Address2", a.Address2).
+		setRequired("City", a.City).
+		setRequired("StateProvince", a.StateProvince).
+		setOptional("StateProvinceChoice", a.StateProvinceChoice).
+		setRequired("Zip", a.PostalCode).
+		setRequired("Country", a.Country).
+		setRequired("Phone", a.Phone).
+		setOptional("PhoneExt", a.PhoneExt).
+		setOptional("Fax", a.Fax).
+		setRequired("EmailAddress", a.EmailAddress)
+}
+
+// trimSpace trims the whitespace Namecheap pads some fields with
+func (a *AccountAddress) trimSpace() {
+	for _, f := range []*string{
+		&a.Name, &a.OrganizationName, &a.JobTitle, &a.FirstName, &a.LastName,
+		&a.Address1, &a.Address2, &a.City, &a.StateProvince,
+		&a.StateProvinceChoice, &a.PostalCode, &a.Country, &a.Phone,
+		&a.PhoneExt, &a.Fax, &a.EmailAddress,
+	} {
+		*f = strings.TrimSpace(*f)
+	}
+}
+
+// oneZero formats a boolean as the 1 or 0 users.address commands expect
+func oneZero(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// GetAccountAddresses lists the addresses registered with the account
+func (c *Client) GetAccountAddresses(ctx context.Context) ([]AccountAddressSummary, error) {
+	resp, err := c.makeRequest(ctx, CommandUsersAddressGetList, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make users.address.getList request")
+	}
+
+	var result AccountAddressListResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse users.address.getList response")
+	}
+
+	return result.CommandResponse.AddressGetListResult.Addresses, nil
+}
+
+// GetAccountAddress retrieves one of the account's addresses
+func (c *Client) GetAccountAddress(ctx context.Context, addressID int) (*AccountAddress, error) {
+	resp, err := c.makeRequest(ctx, CommandUsersAddressGetInfo, newParams().
+		setInt("AddressId", addressID))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make users.address.getInfo request")
+	}
+
+	var result AccountAddressInfoResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse users.address.getInfo response")
+	}
+
+	address := result.CommandResponse.GetAddressInfoResult
+	address.trimSpace()
+	return &address, nil
+}
+
+// CreateAccountAddress registers a new address with the account and returns
+// its ID. The address is validated before any API call.
+func (c *Client) CreateAccountAddress(ctx context.Context, address AccountAddress) (int, error) {
+	if err := address.Validate(); err != nil {
+		return 0, err
+	}
+
+	params := newParams()
+	address.addParams(params)
+
+	resp, err := c.makeRequest(ctx, CommandUsersAddressCreate, params)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to make users.address.create request")
+	}
+
+	var result AccountAddressCreateResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return 0, errors.Wrap(err, "failed to parse users.address.create response")
+	}
+
+	if !result.CommandResponse.AddressCreateResult.Success {
+		return 0, errors.New("failed to create address")
+	}
+	return result.CommandResponse.AddressCreateResult.AddressID, nil
+}
+
+// UpdateAccountAddress replaces every field of the address with address.ID.
+// The address is validated before any API call.
+func (c *Client) UpdateAccountAddress(ctx context.Context, address AccountAddress) error {
+	if err := address.Validate(); err != nil {
+		return err
+	}
+
+	params := newParams().setInt("AddressId", address.ID)
+	address.addParams(params)
+
+	resp, err := c.makeRequest(ctx, CommandUsersAddressUpdate, params)
+	if err != nil {
+		return errors.Wrap(err, "failed to make users.address.update request")
+	}
+
+	var result AccountAddressUpdateResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse users.address.update response")
+	}
+
+	if !result.CommandResponse.AddressUpdateResult.Success {
+		return errors.New("failed to update address")
+	}
+	return nil
+}
+
+// DeleteAccountAddress removes an address from the account
+func (c *Client) DeleteAccountAddress(ctx context.Context, addressID int) error {
+	resp, err := c.makeRequest(ctx, CommandUsersAddressDelete, newParams().
+		setInt("AddressId", addressID))
+	if err != nil {
+		return errors.Wrap(err, "failed to make users.address.delete request")
+	}
+
+	var result AccountAddressDeleteResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse users.address.delete response")
+	}
+
+	if !result.CommandResponse.AddressDeleteResult.Success {
+		return errors.New("failed to delete address")
+	}
+	return nil
+}
+
+// SetDefaultAccountAddress makes an address the account's default
+func (c *Client) SetDefaultAccountAddress(ctx context.Context, addressID int) error {
+	resp, err := c.makeRequest(ctx, CommandUsersAddressSetDefault, newParams().
+		setInt("AddressId", addressID))
+	if err != nil {
+		return errors.Wrap(err, "failed to make users.address.setDefault request")
+	}
+
+	var result AccountAddressSetDefaultResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse users.address.setDefault response")
+	}
+
+	if !result.CommandResponse.AddressSetDefaultResult.Success {
+		return errors.New("failed to set default address")
+	}
+	return nil
+}
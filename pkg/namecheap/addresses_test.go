@@ -0,0 +1,251 @@
+package namecheap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAccountAddress returns an address with every required field set
+func testAccountAddress() AccountAddress {
+	contact := testContact()
+	return AccountAddress{
+		Name:          "Office",
+		FirstName:     contact.FirstName,
+		LastName:      contact.LastName,
+		Address1:      contact.Address1,
+		City:          contact.City,
+		StateProvince: contact.StateProvince,
+		PostalCode:    contact.PostalCode,
+		Country:       contact.Country,
+		Phone:         contact.Phone,
+		EmailAddress:  contact.EmailAddress,
+	}
+}
+
+// newCommandClient returns a client whose API answers command with body,
+// checking each request with check
+func newCommandClient(t *testing.T, command Command, body string, check func(*http.Request)) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, command.Method(), r.Method)
+		assert.Equal(t, command.String(), r.FormValue("Command"))
+		if check != nil {
+			check(r)
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	return NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+}
+
+func TestAccountAddress_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*AccountAddress)
+		err    string
+	}{
+		{name: "Valid", modify: func(*AccountAddress) {}},
+		{
+			name:   "MissingFields",
+			modify: func(a *AccountAddress) { a.Name = ""; a.PostalCode = " " },
+			err:    "address is missing Name, PostalCode",
+		},
+		{
+			name:   "Phone",
+			modify: func(a *AccountAddress) { a.Phone = "555-1234" },
+			err:    `address phone "555-1234" must be in the format +NNN.NNNNNNNNNN`,
+		},
+		{
+			name:   "Fax",
+			modify: func(a *AccountAddress) { a.Fax = "555-1234" },
+			err:    `address fax "555-1234" must be in the format +NNN.NNNNNNNNNN`,
+		},
+		{
+			name:   "EmailAddress",
+			modify: func(a *AccountAddress) { a.EmailAddress = "john" },
+			err:    `address email address "john" is not valid`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := testAccountAddress()
+			tt.modify(&address)
+			err := address.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestClient_GetAccountAddresses(t *testing.T) {
+	client := newCommandClient(t, CommandUsersAddressGetList, string(commandFixture(t, CommandUsersAddressGetList)), nil)
+
+	addresses, err := client.GetAccountAddresses(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []AccountAddressSummary{
+		{ID: 0, Name: "Primary Address"},
+		{ID: 1234, Name: "Office", IsDefault: true},
+	}, addresses)
+}
+
+func TestClient_GetAccountAddress(t *testing.T) {
+	client := newCommandClient(t, CommandUsersAddressGetInfo, string(commandFixture(t, CommandUsersAddressGetInfo)), func(r *http.Request) {
+		assert.Equal(t, "1234", r.FormValue("AddressId"))
+	})
+
+	address, err := client.GetAccountAddress(context.Background(), 1234)
+	require.NoError(t, err)
+	assert.Equal(t, &AccountAddress{
+		ID:                  1234,
+		Name:                "Office",
+		IsDefault:           true,
+		OrganizationName:    "NameCheap.com",
+		JobTitle:            "Software Developer",
+		FirstName:           "John",
+		LastName:            "Smith",
+		Address1:            "8939 S. Cross Blvd",
+		Address2:            "ca 110-708",
+		City:                "Phoenix",
+		StateProvince:       "AZ",
+		StateProvinceChoice: "S",
+		PostalCode:          "85284",
+		Country:             "US",
+		Phone:               "+1.6613102107",
+		EmailAddress:        "john@example.com",
+	}, address)
+
+	// The address read back is complete enough to be written again, and
+	// to register a domain with
+	assert.NoError(t, address.Validate())
+	assert.NoError(t, DomainContacts{
+		Registrant: address.Contact(),
+		Tech:       address.Contact(),
+		Admin:      address.Contact(),
+		AuxBilling: address.Contact(),
+	}.Validate())
+}
+
+func TestClient_CreateAccountAddress(t *testing.T) {
+	address := testAccountAddress()
+	address.IsDefault = true
+	address.OrganizationName = "NameCheap.com"
+
+	client := newCommandClient(t, CommandUsersAddressCreate, string(commandFixture(t, CommandUsersAddressCreate)), func(r *http.Request) {
+		assert.Equal(t, "Office", r.FormValue("AddressName"))
+		assert.Equal(t, "1", r.FormValue("DefaultYN"))
+		assert.Equal(t, "NameCheap.com", r.FormValue("Organization"))
+		assert.Equal(t, "85284", r.FormValue("Zip"))
+		assert.Equal(t, "john@example.com", r.FormValue("EmailAddress"))
+		assert.Empty(t, r.Form["AddressId"])
+		assert.Empty(t, r.Form["Fax"])
+	})
+
+	id, err := client.CreateAccountAddress(context.Background(), address)
+	require.NoError(t, err)
+	assert.Equal(t, 1235, id)
+}
+
+func TestClient_CreateAccountAddress_Invalid(t *testing.T) {
+	client := newCommandClient(t, CommandUsersAddressCreate, "", func(*http.Request) {
+		t.Error("invalid address was sent to the API")
+	})
+
+	address := testAccountAddress()
+	address.Phone = ""
+	_, err := client.CreateAccountAddress(context.Background(), address)
+	assert.EqualError(t, err, "address is missing Phone")
+}
+
+func TestClient_UpdateAccountAddress(t *testing.T) {
+	address := testAccountAddress()
+	address.ID = 1234
+
+	client := newCommandClient(t, CommandUsersAddressUpdate, string(commandFixture(t, CommandUsersAddressUpdate)), func(r *http.Request) {
+		assert.Equal(t, "1234", r.FormValue("AddressId"))
+		assert.Equal(t, "0", r.FormValue("DefaultYN"))
+		assert.Equal(t, "Phoenix", r.FormValue("City"))
+	})
+
+	require.NoError(t, client.UpdateAccountAddress(context.Background(), address))
+}
+
+func TestClient_DeleteAccountAddress(t *testing.T) {
+	client := newCommandClient(t, CommandUsersAddressDelete, string(commandFixture(t, CommandUsersAddressDelete)), func(r *http.Request) {
+		assert.Equal(t, "1234", r.FormValue("AddressId"))
+	})
+
+	require.NoError(t, client.DeleteAccountAddress(context.Background(), 1234))
+}
+
+func TestClient_SetDefaultAccountAddress(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  string
+	}{
+		{
+			name: "Success",
+			body: "",
+		},
+		{
+			name: "NotSuccessful",
+			body: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<AddressSetDefaultResult Success="false" AddressId="1234"/>
+	</CommandResponse>
+</ApiResponse>`,
+			err: "failed to set default address",
+		},
+		{
+			name: "APIError",
+			body: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="2011170">Address not found</Error>
+	</Errors>
+</ApiResponse>`,
+			err: "Address not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := tt.body
+			if body == "" {
+				body = string(commandFixture(t, CommandUsersAddressSetDefault))
+			}
+			client := newCommandClient(t, CommandUsersAddressSetDefault, body, func(r *http.Request) {
+				assert.Equal(t, "1234", r.FormValue("AddressId"))
+			})
+
+			err := client.SetDefaultAccountAddress(context.Background(), 1234)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package namecheap
+
+import "context"
+
+// API is the Namecheap API implemented by Client
+type API interface {
+	// Environment returns the Namecheap environment, sandbox or production
+	Environment() string
+
+	// Domains
+	GetDomains(ctx context.Context) ([]Domain, error)
+	GetDomainsWithOptions(ctx context.Context, opts DomainListOptions) ([]Domain, error)
+	GetExpiringDomains(ctx context.Context, withinDays int) ([]Domain, error)
+	GetDomainCount(ctx context.Context) (int, error)
+	GetDomain(ctx context.Context, domainName string) (*Domain, error)
+	GetDomainInfo(ctx context.Context, domainName string) (*DomainInfo, error)
+	DomainExists(ctx context.Context, domainName string) (bool, error)
+	CheckDomainAvailability(ctx context.Context, domainNames []string) ([]DomainCheckResult, error)
+	CreateDomain(ctx context.Context, domainName string, years int, contacts DomainContacts, opts DomainCreateOptions) (*DomainRegistration, error)
+	RenewDomain(ctx context.Context, domainName string, years int) (*DomainRenewal, error)
+	ReactivateDomain(ctx context.Context, domainName, promoCode string) (*DomainReactivation, error)
+	GetRegistrarLock(ctx context.Context, domainName string) (bool, error)
+	SetRegistrarLock(ctx context.Context, domainName string, locked bool) error
+	GetDomainContacts(ctx context.Context, domainName string) (*DomainContacts, error)
+	SetDomainContacts(ctx context.Context, domainName string, contacts DomainContacts) error
+	SetNameservers(ctx context.Context, domainName string, nameservers []string) error
+	SetDefaultNameservers(ctx context.Context, domainName string) error
+
+	// DNS
+	GetDNSHosts(ctx context.Context, domainName string) (*DNSHosts, error)
+	GetDNSRecords(ctx context.Context, domainName string) ([]DNSRecord, error)
+	GetDNSRecord(ctx context.Context, domainName, recordName, recordType string) (*DNSRecord, error)
+	FindDNSRecords(ctx context.Context, domainName, recordName, recordType string) ([]DNSRecord, error)
+	DNSRecordExists(ctx context.Context, domainName, recordName, recordType string) (bool, error)
+	CreateDNSRecord(ctx context.Context, domainName string, record DNSRecord) error
+	UpdateDNSRecord(ctx context.Context, domainName string, record DNSRecord) error
+	DeleteDNSRecord(ctx context.Context, domainName string, recordName, recordType string) error
+	DeleteDNSRecordIfValue(ctx context.Context, domainName, recordName, recordType, value string) (bool, error)
+	DeleteDNSRecordExact(ctx context.Context, domainName string, record DNSRecord) (bool, error)
+	SetDNSRecords(ctx context.Context, domainName string, records []DNSRecord) error
+	ReplaceDNSHosts(ctx context.Context, domainName string, hosts DNSHosts) error
+
+	// Email forwarding
+	GetEmailForwarding(ctx context.Context, domainName string) ([]EmailForward, error)
+	SetEmailForwarding(ctx context.Context, domainName string, forwards []EmailForward) error
+	AddEmailForward(ctx context.Context, domainName string, forward EmailForward) error
+
+	// Transfers
+	CreateTransfer(ctx context.Context, domainName string, years int, opts TransferOptions) (*TransferOrder, error)
+	GetTransferStatus(ctx context.Context, transferID int) (*TransferStatus, error)
+	GetTransfers(ctx context.Context, listType, searchTerm string) ([]Transfer, error)
+	ResubmitTransfer(ctx context.Context, transferID int, eppCode string) error
+
+	// SSL certificates
+	GetSSLCertificates(ctx context.Context) ([]SSLCertificate, error)
+	GetSSLCertificatesWithOptions(ctx context.Context, opts SSLListOptions) ([]SSLCertificate, error)
+	GetSSLCertificatesByDomain(ctx context.Context, domainName string) ([]SSLCertificate, error)
+	GetSSLCertificate(ctx context.Context, certificateID int) (*SSLGetInfoResponse, error)
+	GetSSLCertificateWithOptions(ctx context.Context, certificateID int, opts SSLInfoOptions) (*SSLGetInfoResponse, error)
+	SSLCertificateExists(ctx context.Context, domainName string) (bool, error)
+	CreateSSLCertificate(ctx context.Context, certificateType, years int, sansToAdd string) (int, error)
+	ActivateSSLCertificate(ctx context.Context, certificateID int, req ActivationRequest) error
+	ReissueSSLCertificate(ctx context.Context, certificateID int, csr, approverEmail string) error
+	ResendSSLApprovalEmail(ctx context.Context, certificateID int) error
+	GetSSLApproverEmailList(ctx context.Context, domainName string, certificateType int) (*SSLApproverEmails, error)
+	ParseCSR(ctx context.Context, csr string, certificateType int) (*CSRDetails, error)
+	RenewSSLCertificate(ctx context.Context, certificateID int, sslType string, years int, promoCode string) (*SSLRenewal, error)
+	RevokeSSLCertificate(ctx context.Context, certificateID, certificateType int) error
+	EditSSLDCValidation(ctx context.Context, certificateID int, dnsNames []string) ([]DNSValidationRecord, error)
+
+	// WhoisGuard
+	GetWhoisGuards(ctx context.Context) ([]WhoisGuard, error)
+	GetWhoisGuardForDomain(ctx context.Context, domainName string) (*WhoisGuard, error)
+	IsWhoisGuardEnabled(ctx context.Context, domainName string) (bool, error)
+	EnableWhoisGuard(ctx context.Context, whoisGuardID int, domainName, forwardedToEmail string) error
+	DisableWhoisGuard(ctx context.Context, whoisGuardID int, domainName string) error
+	RenewWhoisGuard(ctx context.Context, whoisGuardID int, years int) error
+	GetUnallottedWhoisGuards(ctx context.Context) ([]WhoisGuard, error)
+	AllotWhoisGuard(ctx context.Context, whoisGuardID int, domainName string) error
+	UnallotWhoisGuard(ctx context.Context, whoisGuardID int) error
+	DiscardWhoisGuard(ctx context.Context, whoisGuardID int) error
+	ChangeWhoisGuardEmail(ctx context.Context, whoisGuardID int) (*WhoisGuardEmailChange, error)
+
+	// Account
+	GetUserBalances(ctx context.Context) (*UserBalance, error)
+	HasSufficientBalance(ctx context.Context, requiredAmount float64) (bool, error)
+	CreateAddFundsRequest(ctx context.Context, amount float64, returnURL string) (*AddFundsRequest, error)
+	GetAddFundsStatus(ctx context.Context, tokenID string) (*AddFundsStatus, error)
+	GetTLDList(ctx context.Context) ([]TLD, error)
+	GetTLDByName(ctx context.Context, tldName string) (*TLD, error)
+	GetTLDForDomain(ctx context.Context, domainName string) (*TLD, error)
+	IsTLDSupported(ctx context.Context, tldName, operation string) (bool, error)
+	GetPricing(ctx context.Context, productType, productCategory, action string) ([]PricingType, error)
+	GetDomainPricing(ctx context.Context, action string) ([]PricingType, error)
+	GetSSLPricing(ctx context.Context, action string) ([]PricingType, error)
+	GetWhoisGuardPricing(ctx context.Context, action string) ([]PricingType, error)
+	GetAccountAddresses(ctx context.Context) ([]AccountAddressSummary, error)
+	GetAccountAddress(ctx context.Context, addressID int) (*AccountAddress, error)
+	CreateAccountAddress(ctx context.Context, address AccountAddress) (int, error)
+	UpdateAccountAddress(ctx context.Context, address AccountAddress) error
+	DeleteAccountAddress(ctx context.Context, addressID int) error
+	SetDefaultAccountAddress(ctx context.Context, addressID int) error
+}
+
+var _ API = (*Client)(nil)
+
+// DomainAPI is the part of the API the Domain controller uses
+type DomainAPI interface {
+	Environment() string
+
+	DomainExists(ctx context.Context, domainName string) (bool, error)
+	GetDomainInfo(ctx context.Context, domainName string) (*DomainInfo, error)
+	CreateDomain(ctx context.Context, domainName string, years int, contacts DomainContacts, opts DomainCreateOptions) (*DomainRegistration, error)
+	RenewDomain(ctx context.Context, domainName string, years int) (*DomainRenewal, error)
+	ReactivateDomain(ctx context.Context, domainName, promoCode string) (*DomainReactivation, error)
+	GetRegistrarLock(ctx context.Context, domainName string) (bool, error)
+	SetRegistrarLock(ctx context.Context, domainName string, locked bool) error
+	GetDomainContacts(ctx context.Context, domainName string) (*DomainContacts, error)
+	SetDomainContacts(ctx context.Context, domainName string, contacts DomainContacts) error
+	SetNameservers(ctx context.Context, domainName string, nameservers []string) error
+	SetDefaultNameservers(ctx context.Context, domainName string) error
+	GetDNSHosts(ctx context.Context, domainName string) (*DNSHosts, error)
+	GetTLDForDomain(ctx context.Context, domainName string) (*TLD, error)
+
+	GetWhoisGuardForDomain(ctx context.Context, domainName string) (*WhoisGuard, error)
+	EnableWhoisGuard(ctx context.Context, whoisGuardID int, domainName, forwardedToEmail string) error
+	DisableWhoisGuard(ctx context.Context, whoisGuardID int, domainName string) error
+}
+
+// DNSAPI is the part of the API the DNSRecord and DNSZone controllers use
+type DNSAPI interface {
+	Environment() string
+
+	GetDNSHosts(ctx context.Context, domainName string) (*DNSHosts, error)
+	ReplaceDNSHosts(ctx context.Context, domainName string, hosts DNSHosts) error
+	FindDNSRecords(ctx context.Context, domainName, recordName, recordType string) ([]DNSRecord, error)
+	CreateDNSRecord(ctx context.Context, domainName string, record DNSRecord) error
+	UpdateDNSRecord(ctx context.Context, domainName string, record DNSRecord) error
+	DeleteDNSRecordExact(ctx context.Context, domainName string, record DNSRecord) (bool, error)
+}
+
+// TransferAPI is the part of the API the DomainTransfer controller uses
+type TransferAPI interface {
+	Environment() string
+
+	CreateTransfer(ctx context.Context, domainName string, years int, opts TransferOptions) (*TransferOrder, error)
+	GetTransferStatus(ctx context.Context, transferID int) (*TransferStatus, error)
+	ResubmitTransfer(ctx context.Context, transferID int, eppCode string) error
+}
+
+// SSLAPI is the part of the API the SSLCertificate controller uses
+type SSLAPI interface {
+	Environment() string
+
+	GetSSLCertificate(ctx context.Context, certificateID int) (*SSLGetInfoResponse, error)
+	GetSSLCertificateWithOptions(ctx context.Context, certificateID int, opts SSLInfoOptions) (*SSLGetInfoResponse, error)
+	CreateSSLCertificate(ctx context.Context, certificateType, years int, sansToAdd string) (int, error)
+	ActivateSSLCertificate(ctx context.Context, certificateID int, req ActivationRequest) error
+	ReissueSSLCertificate(ctx context.Context, certificateID int, csr, approverEmail string) error
+	ResendSSLApprovalEmail(ctx context.Context, certificateID int) error
+	GetSSLApproverEmailList(ctx context.Context, domainName string, certificateType int) (*SSLApproverEmails, error)
+	ParseCSR(ctx context.Context, csr string, certificateType int) (*CSRDetails, error)
+	RenewSSLCertificate(ctx context.Context, certificateID int, sslType string, years int, promoCode string) (*SSLRenewal, error)
+	RevokeSSLCertificate(ctx context.Context, certificateID, certificateType int) error
+	EditSSLDCValidation(ctx context.Context, certificateID int, dnsNames []string) ([]DNSValidationRecord, error)
+}
+
+// Every controller's API is a part of API
+var (
+	_ DomainAPI   = API(nil)
+	_ DNSAPI      = API(nil)
+	_ TransferAPI = API(nil)
+	_ SSLAPI      = API(nil)
+)
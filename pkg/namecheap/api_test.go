@@ -0,0 +1,159 @@
+package namecheap
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update testdata/api.golden with the current exported API")
+
+// exportedAPI lists the package's exported identifiers, one per line, with
+// the signatures of functions, methods and fields.
+func exportedAPI(t *testing.T) string {
+	t.Helper()
+
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+	node := func(n ast.Node) string {
+		// Anonymous structs are summarized; their fields are response
+		// details rather than API
+		if _, ok := n.(*ast.StructType); ok {
+			return "struct{...}"
+		}
+		var b bytes.Buffer
+		require.NoError(t, printer.Fprint(&b, fset, n))
+		return b.String()
+	}
+
+	var lines []string
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, f, nil, 0)
+		require.NoError(t, err)
+
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if !decl.Name.IsExported() {
+					continue
+				}
+				signature := strings.TrimPrefix(node(decl.Type), "func")
+				if decl.Recv == nil {
+					lines = append(lines, "func "+decl.Name.Name+signature)
+					continue
+				}
+				recv := node(decl.Recv.List[0].Type)
+				if ast.IsExported(strings.TrimPrefix(recv, "*")) {
+					lines = append(lines, "method ("+recv+") "+decl.Name.Name+signature)
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							if name.IsExported() {
+								lines = append(lines, decl.Tok.String()+" "+name.Name)
+							}
+						}
+					case *ast.TypeSpec:
+						if !spec.Name.IsExported() {
+							continue
+						}
+						lines = append(lines, typeAPI(spec, node)...)
+					}
+				}
+			}
+		}
+	}
+
+	sort.Strings(lines)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// typeAPI lists an exported type together with its exported fields or
+// interface methods
+func typeAPI(spec *ast.TypeSpec, node func(ast.Node) string) []string {
+	name := spec.Name.Name
+	switch typ := spec.Type.(type) {
+	case *ast.StructType:
+		lines := []string{"type " + name + " struct"}
+		for _, field := range typ.Fields.List {
+			if len(field.Names) == 0 {
+				lines = append(lines, "field "+name+"."+node(field.Type)+" embedded")
+				continue
+			}
+			for _, n := range field.Names {
+				if n.IsExported() {
+					lines = append(lines, "field "+name+"."+n.Name+" "+node(field.Type))
+				}
+			}
+		}
+		return lines
+	case *ast.InterfaceType:
+		lines := []string{"type " + name + " interface"}
+		for _, method := range typ.Methods.List {
+			for _, n := range method.Names {
+				lines = append(lines, "method "+name+"."+n.Name+strings.TrimPrefix(node(method.Type), "func"))
+			}
+		}
+		return lines
+	default:
+		if spec.Assign.IsValid() {
+			return []string{"type " + name + " = " + node(spec.Type)}
+		}
+		return []string{"type " + name + " " + node(spec.Type)}
+	}
+}
+
+// TestExportedAPI guards the package's public surface: any change to its
+// exported identifiers must be accepted by updating testdata/api.golden.
+func TestExportedAPI(t *testing.T) {
+	golden := filepath.Join("testdata", "api.golden")
+	got := exportedAPI(t)
+
+	if *update {
+		require.NoError(t, os.WriteFile(golden, []byte(got), 0o600))
+	}
+
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), got,
+		"the exported API changed; if intended, run go test ./pkg/namecheap -run TestExportedAPI -update")
+}
+
+// TestAPICoversClient checks that API declares every method of Client, so
+// that consumers depending on API can reach the whole client.
+func TestAPICoversClient(t *testing.T) {
+	api := reflect.TypeOf((*API)(nil)).Elem()
+	client := reflect.TypeOf(&Client{})
+	for i := 0; i < client.NumMethod(); i++ {
+		name := client.Method(i).Name
+		switch name {
+		case "WithRetry":
+			// A helper for wrapping calls, not part of the Namecheap API
+			continue
+		case "RateLimiter", "CircuitBreaker":
+			// The client's own state, reported in support bundles
+			continue
+		}
+		_, ok := api.MethodByName(name)
+		assert.True(t, ok, "API does not declare Client.%s", name)
+	}
+}
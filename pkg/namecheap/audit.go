@@ -0,0 +1,122 @@
+package namecheap
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// auditLogSize is how many of the most recent mutating and billable requests
+// the audit log keeps
+const auditLogSize = 100
+
+// An AuditEntry records a mutating or billable API request. It holds the
+// domain the request was made for but none of its other parameters, so it
+// carries no secret material beyond what the API's error may quote.
+type AuditEntry struct {
+	Time     time.Time
+	Command  Command
+	Category CommandCategory
+
+	// Domain is the domain the request was made for, empty for commands
+	// that don't take one
+	Domain string
+
+	// Namespace and Kind are those of the managed resource the request was
+	// made for, empty when made outside a reconcile
+	Namespace string
+	Kind      string
+
+	// Outcome is OutcomeSuccess or OutcomeError, with the error in Error.
+	// Unlike in the request metrics, an error the API reports in its
+	// response is an OutcomeError.
+	Outcome string
+	Error   string
+}
+
+// auditLog keeps the most recent requests that changed an account. Clients
+// are created for every reconcile, so the log is shared by every client.
+type auditLog struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+	next    int
+}
+
+var audit = &auditLog{}
+
+// record adds e to the log, dropping the oldest entry once the log is full
+func (l *auditLog) record(e AuditEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.entries) < auditLogSize {
+		l.entries = append(l.entries, e)
+		return
+	}
+	l.entries[l.next] = e
+	l.next = (l.next + 1) % auditLogSize
+}
+
+// recent returns the entries in the log, oldest first
+func (l *auditLog) recent() []AuditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := make([]AuditEntry, 0, len(l.entries))
+	out = append(out, l.entries[l.next:]...)
+	return append(out, l.entries[:l.next]...)
+}
+
+// RecentAuditEntries returns the most recent mutating and billable requests
+// made by every client, oldest first
+func RecentAuditEntries() []AuditEntry {
+	return audit.recent()
+}
+
+// auditRequest records a completed request, and the error the API reported
+// in resp if it succeeded, in the audit log if it is mutating or billable
+func auditRequest(ctx context.Context, command Command, params *params, resp *http.Response, err error) {
+	if !command.IsMutating() {
+		return
+	}
+	if err == nil {
+		err = responseError(resp)
+	}
+
+	e := AuditEntry{
+		Time:     time.Now(),
+		Command:  command,
+		Category: command.Category(),
+		Domain:   params.domain(),
+		Outcome:  OutcomeSuccess,
+	}
+	e.Namespace, e.Kind = resourceFromContext(ctx)
+	if err != nil {
+		e.Outcome = OutcomeError
+		e.Error = err.Error()
+	}
+	audit.record(e)
+}
+
+// responseError returns the first error the API reported in resp, leaving
+// resp's body to be read again. Responses that can't be parsed are left for
+// the caller to report.
+func responseError(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to read response body")
+	}
+
+	var base APIResponse
+	if err := unmarshalXML(body, &base); err != nil || base.Status == "OK" || len(base.Errors) == 0 {
+		return nil
+	}
+	return base.Errors[0]
+}
@@ -0,0 +1,86 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog_Recent(t *testing.T) {
+	l := &auditLog{}
+	assert.Empty(t, l.recent())
+
+	// Once full, the oldest entries are dropped
+	for i := 0; i < auditLogSize+3; i++ {
+		l.record(AuditEntry{Domain: fmt.Sprintf("%d.example", i)})
+	}
+	recent := l.recent()
+	require.Len(t, recent, auditLogSize)
+	assert.Equal(t, "3.example", recent[0].Domain)
+	assert.Equal(t, fmt.Sprintf("%d.example", auditLogSize+2), recent[auditLogSize-1].Domain)
+}
+
+func TestClient_AuditLog(t *testing.T) {
+	previous := audit
+	audit = &auditLog{}
+	t.Cleanup(func() { audit = previous })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		switch r.FormValue("Command") {
+		case "namecheap.domains.dns.setHosts":
+			_, err := w.Write([]byte(testSetHostsXML))
+			require.NoError(t, err)
+		case "namecheap.domains.setRegistrarLock":
+			_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="2019166">Domain not found</Error>
+	</Errors>
+</ApiResponse>`))
+			require.NoError(t, err)
+		default:
+			_, err := w.Write([]byte(testHostsXML))
+			require.NoError(t, err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:             "audituser",
+		APIKey:              "testkey",
+		Username:            "audituser",
+		ClientIP:            "127.0.0.1",
+		BaseURL:             server.URL,
+		HTTPClient:          &http.Client{Timeout: 5 * time.Second},
+		DomainWriteInterval: -1,
+	})
+	ctx := WithResource(context.Background(), "default", "DNSRecord")
+
+	// Reads aren't audited; changes are, whether they succeed or not
+	_, err := client.GetDNSHosts(ctx, "example.com")
+	require.NoError(t, err)
+	require.NoError(t, client.setHosts(ctx, "example.com", "", []DNSRecord{{Name: "@", Type: "A", Address: "192.0.2.1"}}))
+	require.Error(t, client.SetRegistrarLock(context.Background(), "missing.example", true))
+
+	recent := RecentAuditEntries()
+	require.Len(t, recent, 2)
+	assert.Equal(t, CommandDomainsDNSSetHosts, recent[0].Command)
+	assert.Equal(t, CategoryMutating, recent[0].Category)
+	assert.Equal(t, "example.com", recent[0].Domain)
+	assert.Equal(t, "default", recent[0].Namespace)
+	assert.Equal(t, "DNSRecord", recent[0].Kind)
+	assert.Equal(t, OutcomeSuccess, recent[0].Outcome)
+	assert.Empty(t, recent[0].Error)
+
+	assert.Equal(t, CommandDomainsSetRegistrarLock, recent[1].Command)
+	assert.Equal(t, "missing.example", recent[1].Domain)
+	assert.Equal(t, OutcomeError, recent[1].Outcome)
+	assert.Contains(t, recent[1].Error, "Domain not found")
+}
@@ -0,0 +1,101 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// Product types and actions orders are priced under by users.getPricing
+const (
+	productTypeDomain         = "DOMAIN"
+	productTypeSSLCertificate = "SSLCERTIFICATE"
+
+	actionRegister = "REGISTER"
+	actionRenew    = "RENEW"
+	actionPurchase = "PURCHASE"
+)
+
+// ErrInsufficientFunds is returned for an order the account's available
+// balance doesn't cover. The order is not placed.
+type ErrInsufficientFunds struct {
+	// Required is the price of the order
+	Required float64
+	// Available is the account's available balance
+	Available float64
+	// Currency is the currency of both amounts
+	Currency string
+}
+
+func (e ErrInsufficientFunds) Error() string {
+	return fmt.Sprintf("insufficient funds: the order costs %.2f %s but only %.2f %s is available",
+		e.Required, e.Currency, e.Available, e.Currency)
+}
+
+// IsInsufficientFunds reports whether err is an order refused because the
+// account's balance doesn't cover it
+func IsInsufficientFunds(err error) bool {
+	var insufficient ErrInsufficientFunds
+	return errors.As(err, &insufficient)
+}
+
+// checkFunds returns ErrInsufficientFunds when the client checks balances
+// and the account's available balance doesn't cover the price of ordering
+// product for years. Orders Namecheap lists no price for, such as premium
+// domains, and prices in another currency than the balance are let through
+// for the API to judge.
+func (c *Client) checkFunds(ctx context.Context, productType, action, product string, years int) error {
+	if !c.checkBalance {
+		return nil
+	}
+
+	price, currency, ok, err := c.orderPrice(ctx, productType, action, product, years)
+	if err != nil || !ok {
+		return err
+	}
+
+	balance, err := c.GetUserBalances(ctx)
+	if err != nil {
+		return errors.Wrap(err, "cannot check the account balance")
+	}
+	if !strings.EqualFold(balance.Currency, currency) {
+		return nil
+	}
+	if balance.AvailableBalance < price {
+		return ErrInsufficientFunds{Required: price, Available: balance.AvailableBalance, Currency: balance.Currency}
+	}
+	return nil
+}
+
+// orderPrice returns what the account pays for ordering product for years,
+// including any additional fee, and whether Namecheap lists a price for it.
+// Prices are read through the pricing cache.
+func (c *Client) orderPrice(ctx context.Context, productType, action, product string, years int) (float64, string, bool, error) {
+	prices, err := c.GetPricing(ctx, productType, "", action)
+	if err != nil {
+		return 0, "", false, errors.Wrap(err, "cannot read prices")
+	}
+
+	want := productKey(product)
+	for _, p := range prices {
+		if productKey(p.Name) == want && strings.EqualFold(p.Category, action) && p.Duration == years {
+			return p.YourPrice + p.YourAdditionalCost, p.Currency, true, nil
+		}
+	}
+	return 0, "", false, nil
+}
+
+// productKey returns the name of a product as prices are matched by: lower
+// cased without spaces or punctuation, as users.getPricing names SSL
+// certificate types differently than ssl.create does
+func productKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
@@ -0,0 +1,197 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const balancePricingXML = `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<UserGetPricingResult>
+			<ProductType Name="%s">
+				<ProductCategory Name="%s">
+					<Product Name="com">
+						<Price Duration="1" DurationType="YEAR" Price="10.98" YourPrice="10.98" YourAdditonalCost="0.18" Currency="USD"/>
+						<Price Duration="2" DurationType="YEAR" Price="24.96" YourPrice="24.96" YourAdditonalCost="0.36" Currency="USD"/>
+					</Product>
+					<Product Name="positivessl-multi-domain">
+						<Price Duration="1" DurationType="YEAR" Price="29.88" YourPrice="29.88" Currency="USD"/>
+					</Product>
+				</ProductCategory>
+			</ProductType>
+		</UserGetPricingResult>
+	</CommandResponse>
+</ApiResponse>`
+
+const balanceXML = `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<UserGetBalancesResult Currency="%s" AvailableBalance="%.2f" AccountBalance="%.2f"/>
+	</CommandResponse>
+</ApiResponse>`
+
+// newBalanceClient returns a client that checks balances against an account
+// with available in currency, counting the requests made of each command
+func newBalanceClient(t *testing.T, available float64, currency string) (*Client, map[Command]int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	requests := map[Command]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		command := Command(r.FormValue("Command"))
+		mu.Lock()
+		requests[command]++
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/xml")
+		switch command {
+		case CommandUsersGetPricing:
+			productType := map[string]string{"DOMAIN": "domains", "SSLCERTIFICATE": "ssl"}[r.FormValue("ProductType")]
+			_, _ = fmt.Fprintf(w, balancePricingXML, productType, r.FormValue("ActionName"))
+		case CommandUsersGetBalances:
+			_, _ = fmt.Fprintf(w, balanceXML, currency, available, available)
+		default:
+			_, _ = w.Write(commandFixture(t, command))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return NewClient(Config{
+		APIUser:      "testuser",
+		APIKey:       "testkey",
+		Username:     "testuser",
+		ClientIP:     "127.0.0.1",
+		BaseURL:      server.URL,
+		HTTPClient:   &http.Client{Timeout: 5 * time.Second},
+		CheckBalance: true,
+	}), requests
+}
+
+func TestClient_CheckFunds(t *testing.T) {
+	tests := []struct {
+		name      string
+		available float64
+		currency  string
+		order     func(*Client) error
+		required  float64
+	}{
+		{
+			name:      "RegisterCovered",
+			available: 11.16,
+			currency:  "USD",
+			order: func(c *Client) error {
+				return c.checkFunds(context.Background(), productTypeDomain, actionRegister, "com", 1)
+			},
+		},
+		{
+			name:      "RegisterNotCovered",
+			available: 11.15,
+			currency:  "USD",
+			order: func(c *Client) error {
+				return c.checkFunds(context.Background(), productTypeDomain, actionRegister, "com", 1)
+			},
+			required: 11.16,
+		},
+		{
+			name:      "RenewForYears",
+			available: 20,
+			currency:  "USD",
+			order: func(c *Client) error {
+				return c.checkFunds(context.Background(), productTypeDomain, actionRenew, "COM", 2)
+			},
+			required: 25.32,
+		},
+		{
+			name:      "SSLTypeNamedDifferently",
+			available: 10,
+			currency:  "USD",
+			order: func(c *Client) error {
+				return c.checkFunds(context.Background(), productTypeSSLCertificate, actionPurchase, SSLTypePositiveSSLMultiDomain, 1)
+			},
+			required: 29.88,
+		},
+		{
+			name:      "NoListedPrice",
+			available: 0,
+			currency:  "USD",
+			order: func(c *Client) error {
+				return c.checkFunds(context.Background(), productTypeDomain, actionRegister, "io", 1)
+			},
+		},
+		{
+			name:      "OtherCurrency",
+			available: 1,
+			currency:  "EUR",
+			order: func(c *Client) error {
+				return c.checkFunds(context.Background(), productTypeDomain, actionRegister, "com", 1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newBalanceClient(t, tt.available, tt.currency)
+			err := tt.order(client)
+			if tt.required == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var insufficient ErrInsufficientFunds
+			require.ErrorAs(t, err, &insufficient)
+			assert.InDelta(t, tt.required, insufficient.Required, 0.001)
+			assert.Equal(t, tt.available, insufficient.Available)
+			assert.Equal(t, "USD", insufficient.Currency)
+			assert.True(t, IsInsufficientFunds(errors.Wrap(err, "cannot create domain")))
+		})
+	}
+}
+
+func TestClient_CheckFunds_Orders(t *testing.T) {
+	ctx := context.Background()
+
+	client, requests := newBalanceClient(t, 5, "USD")
+
+	_, err := client.CreateDomain(ctx, "example.com", 1, testContacts(), DomainCreateOptions{})
+	assert.True(t, IsInsufficientFunds(err))
+	assert.EqualError(t, err, "insufficient funds: the order costs 11.16 USD but only 5.00 USD is available")
+
+	_, err = client.RenewDomain(ctx, "example.com", 1)
+	assert.True(t, IsInsufficientFunds(err))
+
+	_, err = client.CreateSSLCertificate(ctx, 1, 1, "")
+	assert.NoError(t, err, "orders without a listed price are let through")
+
+	// No order the balance doesn't cover is placed, and prices are read once
+	// per action
+	assert.Zero(t, requests[CommandDomainsCreate])
+	assert.Zero(t, requests[CommandDomainsRenew])
+	assert.Equal(t, 1, requests[CommandSSLCreate])
+	assert.Equal(t, 3, requests[CommandUsersGetPricing])
+
+	_, err = client.RenewDomain(ctx, "example.com", 1)
+	assert.True(t, IsInsufficientFunds(err))
+	assert.Equal(t, 3, requests[CommandUsersGetPricing], "prices are cached")
+	assert.Equal(t, 3, requests[CommandUsersGetBalances], "balances are read for every order")
+}
+
+func TestClient_CheckFunds_Disabled(t *testing.T) {
+	client, requests := newBalanceClient(t, 0, "USD")
+	client.checkBalance = false
+
+	_, err := client.RenewDomain(context.Background(), "example.com", 1)
+	require.NoError(t, err)
+	assert.Zero(t, requests[CommandUsersGetPricing])
+	assert.Zero(t, requests[CommandUsersGetBalances])
+	assert.Equal(t, 1, requests[CommandDomainsRenew])
+}
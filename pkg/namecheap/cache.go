@@ -0,0 +1,190 @@
+package namecheap
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultCacheTTL is how long TLD lists and prices are cached unless
+// Config.CacheTTL says otherwise. Both are large, and rarely change.
+const DefaultCacheTTL = 6 * time.Hour
+
+// Caches reported in the cache metrics
+const (
+	cacheTLDs    = "tlds"
+	cachePricing = "pricing"
+)
+
+// cacheKey identifies a cached response. The API endpoint stands in for the
+// environment, as the sandbox offers different TLDs at different prices.
+type cacheKey struct {
+	apiUser string
+	baseURL string
+
+	// request identifies the response among the account's, such as the
+	// product and action prices were read for
+	request string
+}
+
+type cacheEntry struct {
+	cache     string
+	value     any
+	fetchedAt time.Time
+}
+
+// cacheCounts counts the loads of one cache by result
+type cacheCounts struct {
+	hits   int64
+	misses int64
+}
+
+// cacheFetch is a fetch in progress. Concurrent loads of its key wait for
+// it rather than making requests of their own.
+type cacheFetch struct {
+	cache string
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// responseCache caches the responses of every account. Clients are created
+// for every reconcile, so the responses must outlive any single client.
+type responseCache struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	entries map[cacheKey]cacheEntry
+	fetches map[cacheKey]*cacheFetch
+	counts  map[string]*cacheCounts
+}
+
+func newResponseCache(now func() time.Time) *responseCache {
+	return &responseCache{
+		now:     now,
+		entries: map[cacheKey]cacheEntry{},
+		fetches: map[cacheKey]*cacheFetch{},
+		counts:  map[string]*cacheCounts{cacheTLDs: {}, cachePricing: {}},
+	}
+}
+
+var responses = newResponseCache(time.Now)
+
+// load returns the response cached under key if it is younger than ttl, and
+// otherwise fetches and caches it. A fresh read ignores the cached response
+// and replaces it. Concurrent loads of a key share a single fetch, made with
+// the context of the load that started it.
+func (c *responseCache) load(ctx context.Context, cache string, key cacheKey, ttl time.Duration, fetch func() (any, error)) (any, error) {
+	c.mu.Lock()
+	counts := c.counts[cache]
+	if counts == nil {
+		counts = &cacheCounts{}
+		c.counts[cache] = counts
+	}
+	if entry, ok := c.entries[key]; ok && !IsFreshRead(ctx) && c.now().Sub(entry.fetchedAt) < ttl {
+		counts.hits++
+		c.mu.Unlock()
+		observeCache(cache, CacheResultHit)
+		return entry.value, nil
+	}
+	if f, ok := c.fetches[key]; ok {
+		counts.hits++
+		c.mu.Unlock()
+		observeCache(cache, CacheResultHit)
+		select {
+		case <-f.done:
+			return f.value, f.err
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "cancelled waiting for a cached response")
+		}
+	}
+	f := &cacheFetch{cache: cache, done: make(chan struct{})}
+	c.fetches[key] = f
+	counts.misses++
+	c.mu.Unlock()
+	observeCache(cache, CacheResultMiss)
+
+	f.value, f.err = fetch()
+
+	c.mu.Lock()
+	delete(c.fetches, key)
+	if f.err == nil && ttl > 0 {
+		c.entries[key] = cacheEntry{cache: cache, value: f.value, fetchedAt: c.now()}
+	}
+	c.mu.Unlock()
+	close(f.done)
+	return f.value, f.err
+}
+
+// CacheStats describes one of the caches of API responses shared by every
+// client
+type CacheStats struct {
+	// Cache names the cache as LabelCache does, tlds or pricing
+	Cache string
+
+	// Entries is the number of cached responses, including any older than
+	// the TTL of the clients reading them
+	Entries int
+
+	// Fetches is the number of fetches in progress
+	Fetches int
+
+	// Hits and Misses count the loads of the cache since the provider
+	// started, as the cache metrics do
+	Hits   int64
+	Misses int64
+
+	// OldestEntry is when the oldest cached response was fetched, zero if
+	// there is none
+	OldestEntry time.Time
+}
+
+// ResponseCacheStats returns the stats of the caches of API responses,
+// ordered by cache
+func ResponseCacheStats() []CacheStats {
+	return responses.stats()
+}
+
+// stats returns the stats of every cache loaded through c
+func (c *responseCache) stats() []CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	byCache := map[string]*CacheStats{}
+	for cache, counts := range c.counts {
+		byCache[cache] = &CacheStats{Cache: cache, Hits: counts.hits, Misses: counts.misses}
+	}
+	for _, entry := range c.entries {
+		s := byCache[entry.cache]
+		s.Entries++
+		if s.OldestEntry.IsZero() || entry.fetchedAt.Before(s.OldestEntry) {
+			s.OldestEntry = entry.fetchedAt
+		}
+	}
+	for _, f := range c.fetches {
+		byCache[f.cache].Fetches++
+	}
+
+	out := make([]CacheStats, 0, len(byCache))
+	for _, s := range byCache {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Cache < out[j].Cache })
+	return out
+}
+
+// cachedSlice loads a slice through the response cache, returning a copy
+// the caller may change
+func cachedSlice[T any](ctx context.Context, c *Client, cache, request string, fetch func() ([]T, error)) ([]T, error) {
+	key := cacheKey{apiUser: c.apiUser, baseURL: c.baseURL, request: request}
+	value, err := responses.load(ctx, cache, key, c.cacheTTL, func() (any, error) {
+		return fetch()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(value.([]T)), nil
+}
@@ -0,0 +1,219 @@
+package namecheap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newResponseCache(func() time.Time { return now })
+	key := cacheKey{apiUser: "testuser", baseURL: "https://api.namecheap.com/xml.response"}
+	fetches := 0
+	fetch := func() (any, error) {
+		fetches++
+		return fetches, nil
+	}
+	load := func(ctx context.Context, key cacheKey, ttl time.Duration) any {
+		value, err := c.load(ctx, cacheTLDs, key, ttl, fetch)
+		require.NoError(t, err)
+		return value
+	}
+	ctx := context.Background()
+
+	assert.Equal(t, 1, load(ctx, key, time.Hour))
+	assert.Equal(t, 1, load(ctx, key, time.Hour))
+
+	// Other accounts and requests have their own responses
+	assert.Equal(t, 2, load(ctx, cacheKey{apiUser: "otheruser", baseURL: key.baseURL}, time.Hour))
+	assert.Equal(t, 3, load(ctx, cacheKey{apiUser: key.apiUser, baseURL: key.baseURL, request: "DOMAIN//REGISTER"}, time.Hour))
+
+	// A fresh read replaces the cached response
+	assert.Equal(t, 4, load(WithFreshRead(ctx), key, time.Hour))
+	assert.Equal(t, 4, load(ctx, key, time.Hour))
+
+	// The response expires
+	now = now.Add(time.Hour)
+	assert.Equal(t, 5, load(ctx, key, time.Hour))
+
+	// Failed fetches aren't cached
+	_, err := c.load(ctx, cacheTLDs, cacheKey{request: "failing"}, time.Hour, func() (any, error) {
+		return nil, errors.New("boom")
+	})
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, 6, load(ctx, cacheKey{request: "failing"}, time.Hour))
+
+	// Nothing is cached without a TTL
+	assert.Equal(t, 7, load(ctx, cacheKey{request: "uncached"}, 0))
+	assert.Equal(t, 8, load(ctx, cacheKey{request: "uncached"}, 0))
+}
+
+func TestResponseCache_Stats(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newResponseCache(func() time.Time { return now })
+	fetch := func() (any, error) { return "response", nil }
+	ctx := context.Background()
+
+	// Both caches are reported before they are first loaded
+	assert.Equal(t, []CacheStats{{Cache: cachePricing}, {Cache: cacheTLDs}}, c.stats())
+
+	_, err := c.load(ctx, cacheTLDs, cacheKey{apiUser: "testuser"}, time.Hour, fetch)
+	require.NoError(t, err)
+	_, err = c.load(ctx, cacheTLDs, cacheKey{apiUser: "testuser"}, time.Hour, fetch)
+	require.NoError(t, err)
+	fetchedAt := now
+	now = now.Add(time.Minute)
+	_, err = c.load(ctx, cachePricing, cacheKey{apiUser: "testuser", request: "DOMAIN/REGISTER/"}, time.Hour, fetch)
+	require.NoError(t, err)
+	_, err = c.load(ctx, cachePricing, cacheKey{apiUser: "testuser", request: "DOMAIN/RENEW/"}, time.Hour, fetch)
+	require.NoError(t, err)
+
+	assert.Equal(t, []CacheStats{
+		{Cache: cachePricing, Entries: 2, Misses: 2, OldestEntry: now},
+		{Cache: cacheTLDs, Entries: 1, Hits: 1, Misses: 1, OldestEntry: fetchedAt},
+	}, c.stats())
+}
+
+func TestResponseCache_SharedFetch(t *testing.T) {
+	c := newResponseCache(time.Now)
+	key := cacheKey{apiUser: "testuser"}
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	fetch := func() (any, error) {
+		fetches.Add(1)
+		<-release
+		return "tlds", nil
+	}
+
+	// The first load starts the fetch the others wait for
+	started := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i == 0 {
+				close(started)
+			}
+			value, err := c.load(context.Background(), cacheTLDs, key, time.Hour, fetch)
+			assert.NoError(t, err)
+			assert.Equal(t, "tlds", value)
+		}()
+		if i == 0 {
+			<-started
+			assert.Eventually(t, func() bool { return fetches.Load() == 1 }, time.Second, time.Millisecond)
+		}
+	}
+
+	// A waiting load gives up with its own context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.load(ctx, cacheTLDs, key, time.Hour, fetch)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), fetches.Load())
+}
+
+func TestClient_GetTLDList_Cached(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write(commandFixture(t, CommandDomainsGetTLDList))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	for range 2 {
+		tlds, err := client.GetTLDList(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, tlds, 2)
+	}
+	assert.Equal(t, 1, requests, "the second read should be cached")
+
+	_, err := client.GetTLDList(WithFreshRead(context.Background()))
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests, "a fresh read should bypass the cache")
+}
+
+func TestClient_GetPricing_Cached(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.FormValue("ProductType")+"/"+r.FormValue("ActionName")]++
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write(commandFixture(t, CommandUsersGetPricing))
+	}))
+	defer server.Close()
+
+	newClient := func(ttl time.Duration) *Client {
+		return NewClient(Config{
+			APIUser:    "testuser",
+			APIKey:     "testkey",
+			Username:   "testuser",
+			ClientIP:   "127.0.0.1",
+			BaseURL:    server.URL,
+			HTTPClient: &http.Client{Timeout: 5 * time.Second},
+			CacheTTL:   ttl,
+		})
+	}
+	hits := testutil.ToFloat64(cacheRequests.WithLabelValues(cachePricing, CacheResultHit))
+	misses := testutil.ToFloat64(cacheRequests.WithLabelValues(cachePricing, CacheResultMiss))
+
+	// Clients of the account share the cached prices, per product and
+	// action
+	for range 2 {
+		client := newClient(0)
+		_, err := client.GetDomainPricing(context.Background(), "REGISTER")
+		require.NoError(t, err)
+		_, err = client.GetSSLPricing(context.Background(), "PURCHASE")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, map[string]int{"DOMAIN/REGISTER": 1, "SSLCERTIFICATE/PURCHASE": 1}, requests)
+	assert.Equal(t, hits+2, testutil.ToFloat64(cacheRequests.WithLabelValues(cachePricing, CacheResultHit)))
+	assert.Equal(t, misses+2, testutil.ToFloat64(cacheRequests.WithLabelValues(cachePricing, CacheResultMiss)))
+
+	// A negative TTL disables the cache
+	for range 2 {
+		_, err := newClient(-1).GetWhoisGuardPricing(context.Background(), "PURCHASE")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, requests["WHOISGUARD/PURCHASE"])
+}
+
+func TestClient_GetTLDForDomain(t *testing.T) {
+	client := newFixtureClient(t, nil)
+
+	tld, err := client.GetTLDForDomain(context.Background(), "Example.COM")
+	require.NoError(t, err)
+	assert.Equal(t, "com", tld.Name)
+	assert.True(t, tld.SupportsRegistrarLock)
+
+	_, err = client.GetTLDForDomain(context.Background(), "example.co.uk")
+	assert.True(t, IsTLDNotFound(err))
+	assert.EqualError(t, err, "TLD 'co.uk' not found")
+
+	_, err = client.GetTLDForDomain(context.Background(), "example")
+	assert.Error(t, err)
+	assert.False(t, IsTLDNotFound(err))
+}
@@ -0,0 +1,424 @@
+package namecheap
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+)
+
+// Client represents a Namecheap API client
+type Client struct {
+	apiUser             string
+	apiKey              Secret
+	username            string
+	clientIPs           []string
+	clientIPIndex       int
+	clientIPMu          sync.Mutex
+	baseURL             string
+	httpClient          *http.Client
+	sandbox             bool
+	logger              logr.Logger
+	rateLimiter         *RateLimiter
+	circuitBreaker      *CircuitBreaker
+	retryConfig         *RetryConfig
+	cacheTTL            time.Duration
+	checkBalance        bool
+	domainWriteInterval time.Duration
+}
+
+// Config holds the configuration for the Namecheap client
+type Config struct {
+	APIUser  string
+	APIKey   Secret
+	Username string
+	ClientIP string
+	// ClientIPs are additional whitelisted client IPs, tried in order when
+	// the API rejects the current one
+	ClientIPs            []string
+	BaseURL              string
+	Sandbox              bool
+	HTTPClient           *http.Client
+	Logger               logr.Logger
+	RateLimitConfig      *RateLimitConfig
+	CircuitBreakerConfig *CircuitBreakerConfig
+	RetryConfig          *RetryConfig
+	// CacheTTL is how long TLD lists and prices are cached, DefaultCacheTTL
+	// when zero. A negative TTL disables caching.
+	CacheTTL time.Duration
+	// CheckBalance has domains registered and renewed and SSL certificates
+	// purchased only once their price is found to be covered by the
+	// account's available balance, returning ErrInsufficientFunds otherwise
+	CheckBalance bool
+	// DomainWriteInterval is the minimum interval between writes to the same
+	// domain's DNS settings, DefaultDomainWriteInterval when zero. Writes
+	// that arrive faster are queued in arrival order. A negative interval
+	// disables the limit.
+	DomainWriteInterval time.Duration
+}
+
+// NewClient creates a new Namecheap API client
+func NewClient(config Config) *Client {
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{
+			Timeout: 30 * time.Second,
+		}
+	}
+
+	if config.BaseURL == "" {
+		if config.Sandbox {
+			config.BaseURL = "https://api.sandbox.namecheap.com/xml.response"
+		} else {
+			config.BaseURL = "https://api.namecheap.com/xml.response"
+		}
+	}
+
+	// Initialize production hardening features with defaults if not provided
+	rateLimitConfig := config.RateLimitConfig
+	if rateLimitConfig == nil {
+		defaultConfig := DefaultRateLimitConfig()
+		rateLimitConfig = &defaultConfig
+	}
+
+	circuitBreakerConfig := config.CircuitBreakerConfig
+	if circuitBreakerConfig == nil {
+		defaultConfig := DefaultCircuitBreakerConfig()
+		circuitBreakerConfig = &defaultConfig
+	}
+
+	retryConfig := config.RetryConfig
+	if retryConfig == nil {
+		defaultConfig := DefaultRetryConfig()
+		retryConfig = &defaultConfig
+	}
+
+	cacheTTL := config.CacheTTL
+	if cacheTTL == 0 {
+		cacheTTL = DefaultCacheTTL
+	}
+
+	domainWriteInterval := config.DomainWriteInterval
+	if domainWriteInterval == 0 {
+		domainWriteInterval = DefaultDomainWriteInterval
+	}
+
+	// Invalid client IPs are rejected by ParseCredentials, so pass them
+	// through here and let the API report them
+	clientIPs, err := clientIPCandidates(config.ClientIP, config.ClientIPs)
+	if err != nil {
+		clientIPs = append([]string{config.ClientIP}, config.ClientIPs...)
+	}
+
+	// Start from the candidate that last worked for this API user
+	clientIPIndex := 0
+	if preferred, ok := preferredClientIPs.Load(config.APIUser); ok {
+		for i, ip := range clientIPs {
+			if ip == preferred {
+				clientIPIndex = i
+			}
+		}
+	}
+
+	return &Client{
+		apiUser:             config.APIUser,
+		apiKey:              config.APIKey,
+		username:            config.Username,
+		clientIPs:           clientIPs,
+		clientIPIndex:       clientIPIndex,
+		baseURL:             config.BaseURL,
+		httpClient:          config.HTTPClient,
+		sandbox:             config.Sandbox,
+		logger:              config.Logger,
+		rateLimiter:         NewRateLimiter(*rateLimitConfig),
+		circuitBreaker:      NewCircuitBreaker(*circuitBreakerConfig),
+		retryConfig:         retryConfig,
+		cacheTTL:            cacheTTL,
+		checkBalance:        config.CheckBalance,
+		domainWriteInterval: domainWriteInterval,
+	}
+}
+
+// APIResponse represents the base structure of Namecheap API responses
+type APIResponse struct {
+	XMLName xml.Name `xml:"ApiResponse"`
+	Status  string   `xml:"Status,attr"`
+	Errors  []Error  `xml:"Errors>Error"`
+}
+
+// Paging describes the page of results returned by a list command
+type Paging struct {
+	TotalItems  int `xml:"TotalItems"`
+	CurrentPage int `xml:"CurrentPage"`
+	PageSize    int `xml:"PageSize"`
+}
+
+// Error represents an API error
+type Error struct {
+	Number      string `xml:"Number,attr"`
+	Description string `xml:",chardata"`
+}
+
+// Error implements the error interface. Known error numbers carry a
+// remediation hint from the error catalog.
+func (e Error) Error() string {
+	msg := fmt.Sprintf("Namecheap API Error %s: %s", e.Number, e.Description)
+	if info, ok := LookupError(e.Number); ok {
+		msg += " (hint: " + info.Remediation + ")"
+	}
+	return msg
+}
+
+// IsDomainNotInAccount reports whether err is a Namecheap API error stating
+// that the domain does not exist or is not associated with the account
+func IsDomainNotInAccount(err error) bool {
+	var ncErr Error
+	if !errors.As(err, &ncErr) {
+		return false
+	}
+	switch ncErr.Number {
+	case "2019166", // Domain not found
+		"2016166": // Domain is not associated with your account
+		return true
+	}
+	return false
+}
+
+// IsNotUsingOurDNS reports whether err is a Namecheap API error stating that
+// the domain is not delegated to Namecheap's DNS
+func IsNotUsingOurDNS(err error) bool {
+	var ncErr Error
+	return errors.As(err, &ncErr) && ncErr.Number == "2030288"
+}
+
+// Namecheap API environments
+const (
+	EnvironmentSandbox    = "sandbox"
+	EnvironmentProduction = "production"
+)
+
+// Environment returns the Namecheap environment the client talks to, derived
+// from its effective API base URL
+func (c *Client) Environment() string {
+	u, err := url.Parse(c.baseURL)
+	if err == nil && strings.Contains(strings.ToLower(u.Hostname()), "sandbox") {
+		return EnvironmentSandbox
+	}
+	return EnvironmentProduction
+}
+
+// RateLimiter returns the rate limiter the client's requests wait for
+func (c *Client) RateLimiter() *RateLimiter {
+	return c.rateLimiter
+}
+
+// CircuitBreaker returns the circuit breaker the client's requests are made
+// through
+func (c *Client) CircuitBreaker() *CircuitBreaker {
+	return c.circuitBreaker
+}
+
+// makeRequest performs an API request to Namecheap with production hardening
+func (c *Client) makeRequest(ctx context.Context, command Command, params *params) (*http.Response, error) {
+	var resp *http.Response
+
+	if params == nil {
+		params = newParams()
+	}
+	if err := params.validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s request", command)
+	}
+
+	// Space out writes to the same domain
+	if err := c.waitForDomainWrite(ctx, command, params); err != nil {
+		return nil, err
+	}
+
+	// Apply rate limiting
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, errors.Wrap(err, "rate limit exceeded")
+	}
+
+	start := time.Now()
+
+	// Execute with circuit breaker and retry logic
+	execute := func(clientIP string) error {
+		return c.circuitBreaker.Execute(ctx, func() error {
+			return c.WithRetry(ctx, command.String(), func(ctx context.Context) error {
+				var err error
+				resp, err = c.doHTTPRequest(ctx, command, clientIP, params)
+				return err
+			})
+		})
+	}
+
+	clientIP := c.clientIP()
+	err := execute(clientIP)
+
+	// With several candidate client IPs, retry once with the next candidate
+	// if the API rejects the current one
+	if err == nil && len(c.clientIPs) > 1 {
+		rejected, rerr := clientIPRejected(resp)
+		if rerr != nil {
+			err = rerr
+		} else if rejected && c.failoverClientIP(clientIP) {
+			c.logger.Info("Client IP rejected by the Namecheap API, failing over", "rejected", clientIP, "next", c.clientIP())
+			clientIP = c.clientIP()
+			err = execute(clientIP)
+			if err == nil {
+				rejected, err = clientIPRejected(resp)
+			}
+		}
+		if err == nil && !rejected {
+			c.rememberClientIP(clientIP)
+		}
+	}
+	observeRequest(ctx, command, start, err)
+	auditRequest(ctx, command, params, resp, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// maxQueryLength is the longest encoded query string sent in a GET request.
+// Namecheap rejects URLs much beyond this length.
+const maxQueryLength = 2048
+
+// doHTTPRequest performs the actual HTTP request
+func (c *Client) doHTTPRequest(ctx context.Context, command Command, clientIP string, params *params) (*http.Response, error) {
+	values := url.Values{}
+	values.Set("ApiUser", c.apiUser)
+	values.Set("ApiKey", c.apiKey.Value())
+	values.Set("UserName", c.username)
+	values.Set("ClientIp", clientIP)
+	values.Set("Command", command.String())
+
+	// Add additional parameters
+	params.addTo(values)
+
+	// Requests too large for a URL are sent as POST whatever the command
+	encoded := values.Encode()
+	method := command.Method()
+	if len(encoded) > maxQueryLength {
+		method = http.MethodPost
+	}
+
+	var body io.Reader
+	if method == http.MethodPost {
+		body = strings.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		req.URL.RawQuery = encoded
+	}
+	req.Header.Set("User-Agent", "crossplane-provider-namecheap/1.0")
+	if IsFreshRead(ctx) {
+		req.Header.Set("Cache-Control", "no-cache")
+	}
+
+	if c.logger.Enabled() {
+		c.logger.V(1).Info("Making API request",
+			"command", command,
+			"category", command.Category(),
+			"method", method,
+			"url", redactURL(req.URL, params.sensitiveKeys()...),
+			"params", params.String())
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute request")
+	}
+
+	// Check for HTTP-level errors that should trigger retries
+	if resp.StatusCode >= 500 {
+		return nil, &HTTPError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("Server error: %s", resp.Status),
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, &HTTPError{
+			StatusCode: resp.StatusCode,
+			Message:    "Rate limit exceeded",
+		}
+	}
+
+	return resp, nil
+}
+
+// parseResponse parses the API response and checks for errors
+func parseResponse(resp *http.Response, result interface{}) error {
+	defer func() {
+		_ = resp.Body.Close() // Ignore close errors
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response body")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// First parse the base response to check for API errors
+	var baseResp APIResponse
+	if err := unmarshalXML(body, &baseResp); err != nil {
+		return errors.Wrap(err, "failed to parse API response")
+	}
+
+	if baseResp.Status != "OK" {
+		if len(baseResp.Errors) > 0 {
+			return baseResp.Errors[0]
+		}
+		return errors.New("API request failed with unknown error")
+	}
+
+	// Parse the full response into the result struct
+	if err := unmarshalXML(body, result); err != nil {
+		return errors.Wrap(err, "failed to parse response into result struct")
+	}
+
+	return nil
+}
+
+// utf8BOM is the UTF-8 encoded byte order mark
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// unmarshalXML parses an API response body into v. A leading byte order mark
+// is ignored, and the declared encoding may be any spelling of UTF-8 or
+// US-ASCII, which the sandbox uses interchangeably.
+func unmarshalXML(body []byte, v interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader(bytes.TrimPrefix(body, utf8BOM)))
+	decoder.CharsetReader = charsetReader
+	return decoder.Decode(v)
+}
+
+// charsetReader accepts the encodings that are byte compatible with UTF-8
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.ReplaceAll(charset, "_", "-")) {
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		return input, nil
+	}
+	return nil, errors.Errorf("unsupported response encoding %q", charset)
+}
@@ -0,0 +1,113 @@
+package namecheap
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNumberInvalidClientIP is the API error returned when the request's
+// ClientIp is not whitelisted for the API user
+const ErrNumberInvalidClientIP = "1011150"
+
+// preferredClientIPs remembers, per API user, the candidate client IP that
+// was last accepted by the API. Clients are created for every reconcile, so
+// this outlives any single client.
+var preferredClientIPs sync.Map
+
+// NormalizeClientIP validates a client IP and returns it in canonical form.
+// The Namecheap API only accepts IPv4 client IPs, so IPv6 addresses are
+// rejected; IPv4-mapped IPv6 addresses are converted to IPv4.
+func NormalizeClientIP(clientIP string) (string, error) {
+	ip := net.ParseIP(strings.TrimSpace(clientIP))
+	if ip == nil {
+		return "", errors.Errorf("client IP %q is not a valid IP address", clientIP)
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return v4.String(), nil
+	}
+	return "", errors.Errorf("client IP %q is an IPv6 address, but the Namecheap API only accepts IPv4; "+
+		"set the IPv4 address the cluster egresses through and whitelist it in Namecheap", clientIP)
+}
+
+// clientIPCandidates returns the de-duplicated, normalized list of the
+// primary client IP followed by any additional candidates
+func clientIPCandidates(primary string, additional []string) ([]string, error) {
+	var out []string
+	seen := map[string]bool{}
+	for _, candidate := range append([]string{primary}, additional...) {
+		if strings.TrimSpace(candidate) == "" {
+			continue
+		}
+		ip, err := NormalizeClientIP(candidate)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[ip] {
+			seen[ip] = true
+			out = append(out, ip)
+		}
+	}
+	return out, nil
+}
+
+// clientIP returns the candidate client IP requests are currently sent with
+func (c *Client) clientIP() string {
+	c.clientIPMu.Lock()
+	defer c.clientIPMu.Unlock()
+	if len(c.clientIPs) == 0 {
+		return ""
+	}
+	return c.clientIPs[c.clientIPIndex]
+}
+
+// failoverClientIP switches to the candidate client IP after rejected, and
+// reports whether there is another candidate to try
+func (c *Client) failoverClientIP(rejected string) bool {
+	c.clientIPMu.Lock()
+	defer c.clientIPMu.Unlock()
+	if len(c.clientIPs) < 2 {
+		return false
+	}
+	// Another request may already have failed over
+	if c.clientIPs[c.clientIPIndex] == rejected {
+		c.clientIPIndex = (c.clientIPIndex + 1) % len(c.clientIPs)
+	}
+	return true
+}
+
+// rememberClientIP records that the API accepted a client IP
+func (c *Client) rememberClientIP(ip string) {
+	if len(c.clientIPs) > 1 {
+		preferredClientIPs.Store(c.apiUser, ip)
+	}
+}
+
+// clientIPRejected reports whether a response is the API rejecting the
+// request's client IP. The response body is buffered so it can still be
+// parsed by the caller.
+func clientIPRejected(resp *http.Response) (bool, error) {
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to read response body")
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var base APIResponse
+	if err := unmarshalXML(body, &base); err != nil {
+		// Leave reporting malformed responses to the caller
+		return false, nil
+	}
+	for _, e := range base.Errors {
+		if e.Number == ErrNumberInvalidClientIP {
+			return true, nil
+		}
+	}
+	return false, nil
+}
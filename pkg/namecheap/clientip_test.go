@@ -0,0 +1,148 @@
+package namecheap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeClientIP(t *testing.T) {
+	tests := []struct {
+		name     string
+		clientIP string
+		expected string
+		errMsg   string
+	}{
+		{name: "IPv4", clientIP: "192.0.2.1", expected: "192.0.2.1"},
+		{name: "surrounding whitespace", clientIP: " 192.0.2.1\n", expected: "192.0.2.1"},
+		{name: "IPv4-mapped IPv6", clientIP: "::ffff:192.0.2.1", expected: "192.0.2.1"},
+		{name: "IPv6", clientIP: "2001:db8::1", errMsg: "only accepts IPv4"},
+		{name: "invalid", clientIP: "not-an-ip", errMsg: "not a valid IP address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip, err := NormalizeClientIP(tt.clientIP)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ip)
+		})
+	}
+}
+
+// newClientIPServer returns a fake API that rejects requests from any client
+// IP not in accepted, and a pointer to the client IPs it has seen.
+func newClientIPServer(t *testing.T, accepted ...string) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientIP := r.FormValue("ClientIp")
+		seen = append(seen, clientIP)
+		w.Header().Set("Content-Type", "application/xml")
+
+		for _, ip := range accepted {
+			if ip == clientIP {
+				_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainGetInfoResult Status="Ok" ID="125" DomainName="example.com">
+		</DomainGetInfoResult>
+	</CommandResponse>
+</ApiResponse>`))
+				require.NoError(t, err)
+				return
+			}
+		}
+
+		_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="1011150">Parameter RequestIP is invalid</Error>
+	</Errors>
+</ApiResponse>`))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+	return server, &seen
+}
+
+func TestClient_ClientIPFailover(t *testing.T) {
+	server, seen := newClientIPServer(t, "192.0.2.2")
+
+	newClient := func() *Client {
+		return NewClient(Config{
+			APIUser:    "failover-user",
+			APIKey:     "testkey",
+			Username:   "failover-user",
+			ClientIP:   "192.0.2.1",
+			ClientIPs:  []string{"192.0.2.2", "192.0.2.3"},
+			BaseURL:    server.URL,
+			HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		})
+	}
+	t.Cleanup(func() { preferredClientIPs.Delete("failover-user") })
+
+	client := newClient()
+	_, err := client.GetDomain(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2"}, *seen)
+
+	// The client sticks with the candidate that worked
+	_, err = client.GetDomain(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2", "192.0.2.2"}, *seen)
+
+	// New clients for the same API user start from it too
+	_, err = newClient().GetDomain(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2", "192.0.2.2", "192.0.2.2"}, *seen)
+}
+
+func TestClient_ClientIPFailover_RetriesOnce(t *testing.T) {
+	server, seen := newClientIPServer(t)
+
+	client := NewClient(Config{
+		APIUser:    "rejected-user",
+		APIKey:     "testkey",
+		Username:   "rejected-user",
+		ClientIP:   "192.0.2.1",
+		ClientIPs:  []string{"192.0.2.2", "192.0.2.3"},
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	_, err := client.GetDomain(context.Background(), "example.com")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ErrNumberInvalidClientIP)
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2"}, *seen)
+
+	_, ok := preferredClientIPs.Load("rejected-user")
+	assert.False(t, ok, "a rejected client IP must not be remembered")
+}
+
+func TestClient_SingleClientIP(t *testing.T) {
+	server, seen := newClientIPServer(t)
+
+	client := NewClient(Config{
+		APIUser:    "single-user",
+		APIKey:     "testkey",
+		Username:   "single-user",
+		ClientIP:   "192.0.2.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	_, err := client.GetDomain(context.Background(), "example.com")
+	require.Error(t, err)
+	assert.Equal(t, []string{"192.0.2.1"}, *seen, "nothing to fail over to")
+}
@@ -0,0 +1,223 @@
+package namecheap
+
+import "net/http"
+
+// Command is a Namecheap API command, such as namecheap.domains.getInfo
+type Command string
+
+// Namecheap API commands used by the client
+const (
+	CommandDomainsGetList    Command = "namecheap.domains.getList"
+	CommandDomainsGetInfo    Command = "namecheap.domains.getInfo"
+	CommandDomainsGetTLDList Command = "namecheap.domains.getTldList"
+	CommandDomainsCheck      Command = "namecheap.domains.check"
+	CommandDomainsCreate     Command = "namecheap.domains.create"
+	CommandDomainsRenew      Command = "namecheap.domains.renew"
+	CommandDomainsReactivate Command = "namecheap.domains.reactivate"
+
+	CommandDomainsGetRegistrarLock Command = "namecheap.domains.getRegistrarLock"
+	CommandDomainsSetRegistrarLock Command = "namecheap.domains.setRegistrarLock"
+	CommandDomainsGetContacts      Command = "namecheap.domains.getContacts"
+	CommandDomainsSetContacts      Command = "namecheap.domains.setContacts"
+
+	CommandDomainsDNSSetCustom  Command = "namecheap.domains.dns.setCustom"
+	CommandDomainsDNSSetDefault Command = "namecheap.domains.dns.setDefault"
+	CommandDomainsDNSGetHosts   Command = "namecheap.domains.dns.getHosts"
+	CommandDomainsDNSSetHosts   Command = "namecheap.domains.dns.setHosts"
+
+	CommandDomainsDNSGetEmailForwarding Command = "namecheap.domains.dns.getEmailForwarding"
+	CommandDomainsDNSSetEmailForwarding Command = "namecheap.domains.dns.setEmailForwarding"
+
+	CommandDomainsTransferCreate       Command = "namecheap.domains.transfer.create"
+	CommandDomainsTransferGetStatus    Command = "namecheap.domains.transfer.getStatus"
+	CommandDomainsTransferGetList      Command = "namecheap.domains.transfer.getList"
+	CommandDomainsTransferUpdateStatus Command = "namecheap.domains.transfer.updateStatus"
+
+	CommandSSLGetList  Command = "namecheap.ssl.getList"
+	CommandSSLGetInfo  Command = "namecheap.ssl.getInfo"
+	CommandSSLCreate   Command = "namecheap.ssl.create"
+	CommandSSLActivate Command = "namecheap.ssl.activate"
+	CommandSSLResend   Command = "namecheap.ssl.resend"
+	CommandSSLReissue  Command = "namecheap.ssl.reissue"
+
+	CommandSSLGetApproverEmailList Command = "namecheap.ssl.getApproverEmailList"
+	CommandSSLParseCSR             Command = "namecheap.ssl.parseCSR"
+	CommandSSLRenew                Command = "namecheap.ssl.renew"
+	CommandSSLRevoke               Command = "namecheap.ssl.revoke"
+	CommandSSLEditDCValidation     Command = "namecheap.ssl.editDCValidation"
+
+	CommandUsersGetBalances           Command = "namecheap.users.getBalances"
+	CommandUsersGetPricing            Command = "namecheap.users.getPricing"
+	CommandUsersCreateAddFundsRequest Command = "namecheap.users.createaddfundsrequest"
+	CommandUsersGetAddFundsStatus     Command = "namecheap.users.getAddFundsStatus"
+
+	CommandUsersAddressGetList    Command = "namecheap.users.address.getList"
+	CommandUsersAddressGetInfo    Command = "namecheap.users.address.getInfo"
+	CommandUsersAddressCreate     Command = "namecheap.users.address.create"
+	CommandUsersAddressUpdate     Command = "namecheap.users.address.update"
+	CommandUsersAddressDelete     Command = "namecheap.users.address.delete"
+	CommandUsersAddressSetDefault Command = "namecheap.users.address.setDefault"
+
+	CommandWhoisGuardGetList     Command = "namecheap.whoisguard.getList"
+	CommandWhoisGuardEnable      Command = "namecheap.whoisguard.enable"
+	CommandWhoisGuardDisable     Command = "namecheap.whoisguard.disable"
+	CommandWhoisGuardRenew       Command = "namecheap.whoisguard.renew"
+	CommandWhoisGuardAllot       Command = "namecheap.whoisguard.allot"
+	CommandWhoisGuardUnallot     Command = "namecheap.whoisguard.unallot"
+	CommandWhoisGuardDiscard     Command = "namecheap.whoisguard.discard"
+	CommandWhoisGuardChangeEmail Command = "namecheap.whoisguard.changeemailaddress"
+)
+
+// CommandCategory classifies a command by its effect on the account
+type CommandCategory string
+
+const (
+	// CategoryRead commands only read account state
+	CategoryRead CommandCategory = "read"
+
+	// CategoryMutating commands change account state without charging it
+	CategoryMutating CommandCategory = "mutating"
+
+	// CategoryBillable commands charge the account
+	CategoryBillable CommandCategory = "billable"
+
+	// CategoryUnknown is reported for commands missing from the registry
+	CategoryUnknown CommandCategory = "unknown"
+)
+
+// commands is the registry of every command the client may send
+var commands = map[Command]CommandCategory{
+	CommandDomainsGetList:    CategoryRead,
+	CommandDomainsGetInfo:    CategoryRead,
+	CommandDomainsGetTLDList: CategoryRead,
+	CommandDomainsCheck:      CategoryRead,
+	CommandDomainsCreate:     CategoryBillable,
+	CommandDomainsRenew:      CategoryBillable,
+	CommandDomainsReactivate: CategoryBillable,
+
+	CommandDomainsGetRegistrarLock: CategoryRead,
+	CommandDomainsSetRegistrarLock: CategoryMutating,
+	CommandDomainsGetContacts:      CategoryRead,
+	CommandDomainsSetContacts:      CategoryMutating,
+
+	CommandDomainsDNSSetCustom:  CategoryMutating,
+	CommandDomainsDNSSetDefault: CategoryMutating,
+	CommandDomainsDNSGetHosts:   CategoryRead,
+	CommandDomainsDNSSetHosts:   CategoryMutating,
+
+	CommandDomainsDNSGetEmailForwarding: CategoryRead,
+	CommandDomainsDNSSetEmailForwarding: CategoryMutating,
+
+	CommandDomainsTransferCreate:       CategoryBillable,
+	CommandDomainsTransferGetStatus:    CategoryRead,
+	CommandDomainsTransferGetList:      CategoryRead,
+	CommandDomainsTransferUpdateStatus: CategoryMutating,
+
+	CommandSSLGetList:  CategoryRead,
+	CommandSSLGetInfo:  CategoryRead,
+	CommandSSLCreate:   CategoryBillable,
+	CommandSSLActivate: CategoryMutating,
+	CommandSSLResend:   CategoryMutating,
+	CommandSSLReissue:  CategoryMutating,
+
+	CommandSSLGetApproverEmailList: CategoryRead,
+	CommandSSLParseCSR:             CategoryRead,
+	CommandSSLRenew:                CategoryBillable,
+	CommandSSLRevoke:               CategoryMutating,
+	CommandSSLEditDCValidation:     CategoryMutating,
+
+	CommandUsersGetBalances:           CategoryRead,
+	CommandUsersGetPricing:            CategoryRead,
+	CommandUsersCreateAddFundsRequest: CategoryMutating,
+	CommandUsersGetAddFundsStatus:     CategoryRead,
+
+	CommandUsersAddressGetList:    CategoryRead,
+	CommandUsersAddressGetInfo:    CategoryRead,
+	CommandUsersAddressCreate:     CategoryMutating,
+	CommandUsersAddressUpdate:     CategoryMutating,
+	CommandUsersAddressDelete:     CategoryMutating,
+	CommandUsersAddressSetDefault: CategoryMutating,
+
+	CommandWhoisGuardGetList:     CategoryRead,
+	CommandWhoisGuardEnable:      CategoryMutating,
+	CommandWhoisGuardDisable:     CategoryMutating,
+	CommandWhoisGuardRenew:       CategoryBillable,
+	CommandWhoisGuardAllot:       CategoryMutating,
+	CommandWhoisGuardUnallot:     CategoryMutating,
+	CommandWhoisGuardDiscard:     CategoryMutating,
+	CommandWhoisGuardChangeEmail: CategoryMutating,
+}
+
+// commandMethods lists the commands sent as GET requests. Every other command
+// is sent as a POST with its parameters form encoded in the body, so large
+// values such as CSRs and host lists never end up in the URL.
+var commandMethods = map[Command]string{
+	CommandDomainsGetList:               http.MethodGet,
+	CommandDomainsGetInfo:               http.MethodGet,
+	CommandDomainsGetTLDList:            http.MethodGet,
+	CommandDomainsCheck:                 http.MethodGet,
+	CommandDomainsGetRegistrarLock:      http.MethodGet,
+	CommandDomainsGetContacts:           http.MethodGet,
+	CommandDomainsDNSGetHosts:           http.MethodGet,
+	CommandDomainsDNSGetEmailForwarding: http.MethodGet,
+	CommandDomainsTransferGetStatus:     http.MethodGet,
+	CommandDomainsTransferGetList:       http.MethodGet,
+	CommandSSLGetList:                   http.MethodGet,
+	CommandSSLGetInfo:                   http.MethodGet,
+	CommandSSLGetApproverEmailList:      http.MethodGet,
+	CommandUsersGetBalances:             http.MethodGet,
+	CommandUsersGetPricing:              http.MethodGet,
+	CommandUsersGetAddFundsStatus:       http.MethodGet,
+	CommandUsersAddressGetList:          http.MethodGet,
+	CommandUsersAddressGetInfo:          http.MethodGet,
+	CommandWhoisGuardGetList:            http.MethodGet,
+}
+
+// String returns the command as sent in the Command query parameter
+func (c Command) String() string {
+	return string(c)
+}
+
+// Category returns the command's category, or CategoryUnknown if the command
+// is not registered
+func (c Command) Category() CommandCategory {
+	if category, ok := commands[c]; ok {
+		return category
+	}
+	return CategoryUnknown
+}
+
+// Method returns the HTTP method the command is sent with
+func (c Command) Method() string {
+	if method, ok := commandMethods[c]; ok {
+		return method
+	}
+	return http.MethodPost
+}
+
+// Registered reports whether the command is in the registry
+func (c Command) Registered() bool {
+	_, ok := commands[c]
+	return ok
+}
+
+// IsMutating reports whether the command changes account state, including
+// billable commands
+func (c Command) IsMutating() bool {
+	category := c.Category()
+	return category == CategoryMutating || category == CategoryBillable
+}
+
+// IsBillable reports whether the command charges the account
+func (c Command) IsBillable() bool {
+	return c.Category() == CategoryBillable
+}
+
+// Commands returns every registered command
+func Commands() []Command {
+	out := make([]Command, 0, len(commands))
+	for c := range commands {
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+package namecheap
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommandsRegistered parses the package source and checks that every
+// makeRequest call passes a Command constant that is in the registry.
+func TestCommandsRegistered(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, f, nil, 0)
+		require.NoError(t, err)
+		parsed = append(parsed, file)
+	}
+
+	// Collect the values of all Command constants
+	constants := map[string]Command{}
+	for _, file := range parsed {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				if ident, ok := vs.Type.(*ast.Ident); !ok || ident.Name != "Command" {
+					continue
+				}
+				for i, name := range vs.Names {
+					lit, ok := vs.Values[i].(*ast.BasicLit)
+					require.True(t, ok, "command %s must be a string literal", name.Name)
+					value, err := strconv.Unquote(lit.Value)
+					require.NoError(t, err)
+					constants[name.Name] = Command(value)
+				}
+			}
+		}
+	}
+
+	calls := 0
+	for _, file := range parsed {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "makeRequest" || len(call.Args) < 2 {
+				return true
+			}
+			calls++
+
+			pos := fset.Position(call.Pos())
+			ident, ok := call.Args[1].(*ast.Ident)
+			if !assert.True(t, ok, "%s: makeRequest must be called with a Command constant", pos) {
+				return true
+			}
+			command, ok := constants[ident.Name]
+			if assert.True(t, ok, "%s: %s is not a Command constant", pos, ident.Name) {
+				assert.True(t, command.Registered(), "%s: %s is not in the command registry", pos, command)
+			}
+			return true
+		})
+	}
+	assert.NotZero(t, calls, "expected to find makeRequest calls")
+
+	// Every constant should be registered, even if not yet called
+	for name, command := range constants {
+		assert.True(t, command.Registered(), "%s (%s) is not in the command registry", name, command)
+	}
+}
+
+func TestCommand_Category(t *testing.T) {
+	tests := []struct {
+		command  Command
+		category CommandCategory
+		mutating bool
+		billable bool
+	}{
+		{CommandDomainsGetInfo, CategoryRead, false, false},
+		{CommandDomainsDNSSetHosts, CategoryMutating, true, false},
+		{CommandDomainsCreate, CategoryBillable, true, true},
+		{Command("namecheap.unknown"), CategoryUnknown, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command.String(), func(t *testing.T) {
+			assert.Equal(t, tt.category, tt.command.Category())
+			assert.Equal(t, tt.mutating, tt.command.IsMutating())
+			assert.Equal(t, tt.billable, tt.command.IsBillable())
+		})
+	}
+
+	for _, c := range Commands() {
+		assert.True(t, strings.HasPrefix(c.String(), "namecheap."), c)
+		assert.NotEqual(t, CategoryUnknown, c.Category(), c)
+	}
+}
+
+func TestCommand_Method(t *testing.T) {
+	assert.Equal(t, "GET", CommandDomainsGetInfo.Method())
+	assert.Equal(t, "POST", CommandDomainsDNSSetHosts.Method())
+	assert.Equal(t, "POST", CommandSSLActivate.Method())
+	assert.Equal(t, "POST", Command("namecheap.unknown").Method())
+
+	// Only reads may be sent as GET
+	for command := range commandMethods {
+		assert.True(t, command.Registered(), command)
+		assert.Equal(t, CategoryRead, command.Category(), command)
+	}
+}
@@ -0,0 +1,218 @@
+package namecheap
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Contact is one of a domain's contacts. Fields map one to one onto the
+// contact parameters of domains.create and domains.setContacts, prefixed
+// with the contact's role, and onto the elements domains.getContacts
+// returns.
+type Contact struct {
+	OrganizationName    string
+	JobTitle            string
+	FirstName           string
+	LastName            string
+	Address1            string
+	Address2            string
+	City                string
+	StateProvince       string
+	StateProvinceChoice string
+	PostalCode          string
+	Country             string
+	// Phone is in the format +NNN.NNNNNNNNNN
+	Phone    string
+	PhoneExt string
+	Fax      string
+	// EmailAddress is the contact's email address
+	EmailAddress string
+}
+
+// DomainContacts are the four contacts every Namecheap domain has
+type DomainContacts struct {
+	Registrant Contact
+	Tech       Contact
+	Admin      Contact
+	AuxBilling Contact
+}
+
+// DomainContactsResponse represents the response from domains.getContacts
+type DomainContactsResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainContactsResult struct {
+			Domain     string  `xml:"Domain,attr"`
+			Registrant Contact `xml:"Registrant"`
+			Tech       Contact `xml:"Tech"`
+			Admin      Contact `xml:"Admin"`
+			AuxBilling Contact `xml:"AuxBilling"`
+		} `xml:"DomainContactsResult"`
+	} `xml:"CommandResponse"`
+}
+
+// DomainSetContactsResponse represents the response from domains.setContacts
+type DomainSetContactsResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainSetContactResult struct {
+			Domain    string `xml:"Domain,attr"`
+			IsSuccess bool   `xml:"IsSuccess,attr"`
+		} `xml:"DomainSetContactResult"`
+	} `xml:"CommandResponse"`
+}
+
+// phonePattern matches the +NNN.NNNNNNNNNN format Namecheap expects for
+// contact phone and fax numbers
+var phonePattern = regexp.MustCompile(`^\+[0-9]{1,3}\.[0-9]{4,14}$`)
+
+// roles returns each contact with the prefix of its parameters
+func (c DomainContacts) roles() []struct {
+	prefix  string
+	contact Contact
+} {
+	return []struct {
+		prefix  string
+		contact Contact
+	}{
+		{"Registrant", c.Registrant},
+		{"Tech", c.Tech},
+		{"Admin", c.Admin},
+		{"AuxBilling", c.AuxBilling},
+	}
+}
+
+// Validate checks that every contact has the fields Namecheap requires and
+// that phone and fax numbers are in the format it accepts, so that a
+// registration or contact update is not rejected by the API with a less
+// helpful error.
+func (c DomainContacts) Validate() error {
+	if c == (DomainContacts{}) {
+		return errors.New("registrant, tech, admin and aux billing contacts are required")
+	}
+
+	for _, r := range c.roles() {
+		var missing []string
+		for _, f := range []struct{ name, value string }{
+			{"FirstName", r.contact.FirstName},
+			{"LastName", r.contact.LastName},
+			{"Address1", r.contact.Address1},
+			{"City", r.contact.City},
+			{"StateProvince", r.contact.StateProvince},
+			{"PostalCode", r.contact.PostalCode},
+			{"Country", r.contact.Country},
+			{"Phone", r.contact.Phone},
+			{"EmailAddress", r.contact.EmailAddress},
+		} {
+			if strings.TrimSpace(f.value) == "" {
+				missing = append(missing, f.name)
+			}
+		}
+		if len(missing) > 0 {
+			return errors.Errorf("%s contact is missing %s", r.prefix, strings.Join(missing, ", "))
+		}
+
+		if !phonePattern.MatchString(r.contact.Phone) {
+			return errors.Errorf("%s contact phone %q must be in the format +NNN.NNNNNNNNNN", r.prefix, r.contact.Phone)
+		}
+		if r.contact.Fax != "" && !phonePattern.MatchString(r.contact.Fax) {
+			return errors.Errorf("%s contact fax %q must be in the format +NNN.NNNNNNNNNN", r.prefix, r.contact.Fax)
+		}
+		if !strings.Contains(r.contact.EmailAddress, "@") {
+			return errors.Errorf("%s contact email address %q is not valid", r.prefix, r.contact.EmailAddress)
+		}
+	}
+	return nil
+}
+
+// addParams adds every contact's non-empty fields to params, named as
+// domains.create and domains.setContacts name them
+func (c DomainContacts) addParams(params *params) {
+	for _, r := range c.roles() {
+		params.
+			setOptional(r.prefix+"OrganizationName", r.contact.OrganizationName).
+			setOptional(r.prefix+"JobTitle", r.contact.JobTitle).
+			setRequired(r.prefix+"FirstName", r.contact.FirstName).
+			setRequired(r.prefix+"LastName", r.contact.LastName).
+			setRequired(r.prefix+"Address1", r.contact.Address1).
+			setOptional(r.prefix+"Address2", r.contact.Address2).
+			setRequired(r.prefix+"City", r.contact.City).
+			setRequired(r.prefix+"StateProvince", r.contact.StateProvince).
+			setOptional(r.prefix+"StateProvinceChoice", r.contact.StateProvinceChoice).
+			setRequired(r.prefix+"PostalCode", r.contact.PostalCode).
+			setRequired(r.prefix+"Country", r.contact.Country).
+			setRequired(r.prefix+"Phone", r.contact.Phone).
+			setOptional(r.prefix+"PhoneExt", r.contact.PhoneExt).
+			setOptional(r.prefix+"Fax", r.contact.Fax).
+			setRequired(r.prefix+"EmailAddress", r.contact.EmailAddress)
+	}
+}
+
+// trimSpace trims the whitespace Namecheap pads some fields with, such as an
+// empty PhoneExt returned as a CDATA section holding a space
+func (c *Contact) trimSpace() {
+	for _, f := range []*string{
+		&c.OrganizationName, &c.JobTitle, &c.FirstName, &c.LastName,
+		&c.Address1, &c.Address2, &c.City, &c.StateProvince,
+		&c.StateProvinceChoice, &c.PostalCode, &c.Country, &c.Phone,
+		&c.PhoneExt, &c.Fax, &c.EmailAddress,
+	} {
+		*f = strings.TrimSpace(*f)
+	}
+}
+
+// GetDomainContacts retrieves a domain's registrant, tech, admin and aux
+// billing contacts
+func (c *Client) GetDomainContacts(ctx context.Context, domainName string) (*DomainContacts, error) {
+	resp, err := c.makeRequest(ctx, CommandDomainsGetContacts, newParams().
+		setDomainName(domainName))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make domains.getContacts request")
+	}
+
+	var result DomainContactsResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse domains.getContacts response")
+	}
+
+	got := result.CommandResponse.DomainContactsResult
+	contacts := &DomainContacts{
+		Registrant: got.Registrant,
+		Tech:       got.Tech,
+		Admin:      got.Admin,
+		AuxBilling: got.AuxBilling,
+	}
+	for _, contact := range []*Contact{&contacts.Registrant, &contacts.Tech, &contacts.Admin, &contacts.AuxBilling} {
+		contact.trimSpace()
+	}
+	return contacts, nil
+}
+
+// SetDomainContacts replaces a domain's registrant, tech, admin and aux
+// billing contacts. The contacts are validated before any API call.
+func (c *Client) SetDomainContacts(ctx context.Context, domainName string, contacts DomainContacts) error {
+	if err := contacts.Validate(); err != nil {
+		return err
+	}
+
+	params := newParams().setDomainName(domainName)
+	contacts.addParams(params)
+
+	resp, err := c.makeRequest(ctx, CommandDomainsSetContacts, params)
+	if err != nil {
+		return errors.Wrap(err, "failed to make domains.setContacts request")
+	}
+
+	var result DomainSetContactsResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse domains.setContacts response")
+	}
+
+	if !result.CommandResponse.DomainSetContactResult.IsSuccess {
+		return errors.New("failed to update contacts")
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package namecheap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testContact returns a contact with every required field set
+func testContact() Contact {
+	return Contact{
+		FirstName:     "John",
+		LastName:      "Smith",
+		Address1:      "8939 S. Cross Blvd",
+		City:          "Phoenix",
+		StateProvince: "AZ",
+		PostalCode:    "85284",
+		Country:       "US",
+		Phone:         "+1.6613102107",
+		EmailAddress:  "john@example.com",
+	}
+}
+
+// testContacts returns valid contacts with the same contact in every role
+func testContacts() DomainContacts {
+	return DomainContacts{
+		Registrant: testContact(),
+		Tech:       testContact(),
+		Admin:      testContact(),
+		AuxBilling: testContact(),
+	}
+}
+
+func TestDomainContacts_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*DomainContacts)
+		err    string
+	}{
+		{
+			name:   "Valid",
+			modify: func(*DomainContacts) {},
+		},
+		{
+			name:   "Omitted",
+			modify: func(c *DomainContacts) { *c = DomainContacts{} },
+			err:    "registrant, tech, admin and aux billing contacts are required",
+		},
+		{
+			name:   "MissingRole",
+			modify: func(c *DomainContacts) { c.AuxBilling = Contact{} },
+			err:    "AuxBilling contact is missing FirstName, LastName, Address1, City, StateProvince, PostalCode, Country, Phone, EmailAddress",
+		},
+		{
+			name: "MissingFields",
+			modify: func(c *DomainContacts) {
+				c.Admin.City = ""
+				c.Admin.EmailAddress = " "
+			},
+			err: "Admin contact is missing City, EmailAddress",
+		},
+		{
+			name:   "InvalidPhone",
+			modify: func(c *DomainContacts) { c.Tech.Phone = "661-310-2107" },
+			err:    `Tech contact phone "661-310-2107" must be in the format +NNN.NNNNNNNNNN`,
+		},
+		{
+			name:   "InvalidFax",
+			modify: func(c *DomainContacts) { c.Registrant.Fax = "6613102107" },
+			err:    `Registrant contact fax "6613102107" must be in the format +NNN.NNNNNNNNNN`,
+		},
+		{
+			name:   "InvalidEmail",
+			modify: func(c *DomainContacts) { c.Registrant.EmailAddress = "john" },
+			err:    `Registrant contact email address "john" is not valid`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contacts := testContacts()
+			tt.modify(&contacts)
+
+			err := contacts.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.err, err.Error())
+		})
+	}
+}
+
+func TestDomainContacts_AddParams(t *testing.T) {
+	contacts := testContacts()
+	contacts.Registrant.OrganizationName = "Example Inc"
+	contacts.AuxBilling.EmailAddress = "billing@example.com"
+
+	p := newParams()
+	contacts.addParams(p)
+	require.NoError(t, p.validate())
+	params := p.asMap()
+
+	// Nine required fields for each of the four roles, plus the organization
+	assert.Len(t, params, 37)
+	assert.Equal(t, "John", params["RegistrantFirstName"])
+	assert.Equal(t, "Example Inc", params["RegistrantOrganizationName"])
+	assert.Equal(t, "+1.6613102107", params["TechPhone"])
+	assert.Equal(t, "US", params["AdminCountry"])
+	assert.Equal(t, "billing@example.com", params["AuxBillingEmailAddress"])
+
+	// Empty optional fields are not sent
+	assert.NotContains(t, params, "RegistrantAddress2")
+	assert.NotContains(t, params, "TechFax")
+}
+
+// newContactsClient returns a client whose API checks each request is for
+// command and replies with body
+func newContactsClient(t *testing.T, command Command, body string, check func(*http.Request)) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, command.Method(), r.Method)
+		assert.Equal(t, command.String(), r.FormValue("Command"))
+		assert.Equal(t, "example.com", r.FormValue("DomainName"))
+		if check != nil {
+			check(r)
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	return NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+}
+
+const contactsErrorXML = `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="2019166">Domain not found</Error>
+	</Errors>
+</ApiResponse>`
+
+func TestClient_GetDomainContacts(t *testing.T) {
+	client := newContactsClient(t, CommandDomainsGetContacts, string(commandFixture(t, CommandDomainsGetContacts)), nil)
+
+	contacts, err := client.GetDomainContacts(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, Contact{
+		OrganizationName:    "NameCheap.com",
+		JobTitle:            "Software Developer",
+		FirstName:           "John",
+		LastName:            "Smith",
+		Address1:            "8939 S. Cross Blvd",
+		Address2:            "ca 110-708",
+		City:                "Phoenix",
+		StateProvince:       "AZ",
+		StateProvinceChoice: "A",
+		PostalCode:          "85284",
+		Country:             "US",
+		Phone:               "+1.6613102107",
+		Fax:                 "+1.6613102107",
+		EmailAddress:        "john@example.com",
+	}, contacts.Registrant)
+	assert.Equal(t, "Jane", contacts.Tech.FirstName)
+	assert.Equal(t, "tech@example.com", contacts.Tech.EmailAddress)
+	assert.Equal(t, "admin@example.com", contacts.Admin.EmailAddress)
+	assert.Equal(t, "billing@example.com", contacts.AuxBilling.EmailAddress)
+
+	// The contacts read back are complete enough to be set again
+	assert.NoError(t, contacts.Validate())
+}
+
+func TestClient_GetDomainContacts_Error(t *testing.T) {
+	client := newContactsClient(t, CommandDomainsGetContacts, contactsErrorXML, nil)
+
+	contacts, err := client.GetDomainContacts(context.Background(), "example.com")
+	var ncErr Error
+	require.ErrorAs(t, err, &ncErr)
+	assert.Equal(t, "2019166", ncErr.Number)
+	assert.Nil(t, contacts)
+}
+
+func TestClient_SetDomainContacts(t *testing.T) {
+	contacts := testContacts()
+	contacts.Tech.FirstName = "Jane"
+
+	client := newContactsClient(t, CommandDomainsSetContacts, string(commandFixture(t, CommandDomainsSetContacts)), func(r *http.Request) {
+		assert.Equal(t, "John", r.FormValue("RegistrantFirstName"))
+		assert.Equal(t, "Jane", r.FormValue("TechFirstName"))
+		assert.Equal(t, "+1.6613102107", r.FormValue("AdminPhone"))
+		assert.Equal(t, "john@example.com", r.FormValue("AuxBillingEmailAddress"))
+	})
+
+	require.NoError(t, client.SetDomainContacts(context.Background(), "example.com", contacts))
+}
+
+func TestClient_SetDomainContacts_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		contacts DomainContacts
+		err      string
+	}{
+		{
+			name:     "APIError",
+			body:     contactsErrorXML,
+			contacts: testContacts(),
+			err:      "Domain not found",
+		},
+		{
+			name: "NotSuccessful",
+			body: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainSetContactResult Domain="example.com" IsSuccess="false"/>
+	</CommandResponse>
+</ApiResponse>`,
+			contacts: testContacts(),
+			err:      "failed to update contacts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newContactsClient(t, CommandDomainsSetContacts, tt.body, nil)
+			err := client.SetDomainContacts(context.Background(), "example.com", tt.contacts)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	// Invalid contacts are rejected before any request is made
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s request", r.FormValue("Command"))
+	}))
+	defer server.Close()
+	client := NewClient(Config{APIUser: "testuser", APIKey: "testkey", Username: "testuser", ClientIP: "127.0.0.1", BaseURL: server.URL})
+	err := client.SetDomainContacts(context.Background(), "example.com", DomainContacts{})
+	assert.ErrorContains(t, err, "contacts are required")
+}
@@ -0,0 +1,38 @@
+package namecheap
+
+import "context"
+
+type freshReadKey struct{}
+
+type resourceKey struct{}
+
+// resource identifies the managed resource requests are made for
+type resource struct {
+	namespace string
+	kind      string
+}
+
+// WithFreshRead returns a context requesting that reads made with it bypass
+// any caching between the provider and the Namecheap API
+func WithFreshRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshReadKey{}, true)
+}
+
+// IsFreshRead reports whether the context requests a fresh read
+func IsFreshRead(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshReadKey{}).(bool)
+	return fresh
+}
+
+// WithResource returns a context attributing the API requests made with it to
+// a managed resource of the supplied namespace and kind in the request metrics
+func WithResource(ctx context.Context, namespace, kind string) context.Context {
+	return context.WithValue(ctx, resourceKey{}, resource{namespace: namespace, kind: kind})
+}
+
+// resourceFromContext returns the namespace and kind requests made with the
+// context are attributed to
+func resourceFromContext(ctx context.Context) (string, string) {
+	r, _ := ctx.Value(resourceKey{}).(resource)
+	return r.namespace, r.kind
+}
@@ -0,0 +1,435 @@
+package namecheap
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultMXPref is the MX preference Namecheap assigns when none is given
+const DefaultMXPref = 10
+
+// AutomaticTTL is the TTL that stands for Namecheap's "Automatic" TTL, as
+// reported by domains.dns.getHosts and accepted by domains.dns.setHosts
+const AutomaticTTL = 1799
+
+// ErrDNSRecordNotFound is returned when no DNS record matches the requested name and type
+var ErrDNSRecordNotFound = errors.New("DNS record not found")
+
+// Email types supported by domains.dns.setHosts
+const (
+	EmailTypeNone = "NONE"
+	EmailTypeMX   = "MX"
+	EmailTypeMXE  = "MXE"
+	EmailTypeFWD  = "FWD"
+	EmailTypeOX   = "OX"
+)
+
+// DNSRecord represents a DNS record in Namecheap
+type DNSRecord struct {
+	HostID  int    `xml:"HostId,attr"`
+	Name    string `xml:"Name,attr"`
+	Type    string `xml:"Type,attr"`
+	Address string `xml:"Address,attr"`
+	MXPref  int    `xml:"MXPref,attr"`
+	TTL     int    `xml:"TTL,attr"`
+	// Flag and Tag are the flags and tag of a CAA record, kept as reported
+	// so that rewriting the zone preserves them
+	Flag               string `xml:"Flag,attr"`
+	Tag                string `xml:"Tag,attr"`
+	AssociatedAppTitle string `xml:"AssociatedAppTitle,attr"`
+	FriendlyName       string `xml:"FriendlyName,attr"`
+	IsActive           bool   `xml:"IsActive,attr"`
+	IsDDNSEnabled      bool   `xml:"IsDDNSEnabled,attr"`
+}
+
+// DNSHostsResponse represents the response from domains.dns.getHosts
+type DNSHostsResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainDNSGetHostsResult struct {
+			Domain        string      `xml:"Domain,attr"`
+			EmailType     string      `xml:"EmailType,attr"`
+			IsUsingOurDNS bool        `xml:"IsUsingOurDNS,attr"`
+			Hosts         []DNSRecord `xml:"host"`
+		} `xml:"DomainDNSGetHostsResult"`
+	} `xml:"CommandResponse"`
+}
+
+// DNSSetHostsResponse represents the response from domains.dns.setHosts
+type DNSSetHostsResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainDNSSetHostsResult struct {
+			Domain    string `xml:"Domain,attr"`
+			IsSuccess bool   `xml:"IsSuccess,attr"`
+		} `xml:"DomainDNSSetHostsResult"`
+	} `xml:"CommandResponse"`
+}
+
+// DNSHosts is the complete host set of a domain together with its zone-level
+// email settings
+type DNSHosts struct {
+	Domain        string
+	EmailType     string
+	IsUsingOurDNS bool
+	Records       []DNSRecord
+}
+
+// GetDNSRecords retrieves all DNS records for a domain
+func (c *Client) GetDNSRecords(ctx context.Context, domainName string) ([]DNSRecord, error) {
+	hosts, err := c.GetDNSHosts(ctx, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	return hosts.Records, nil
+}
+
+// GetDNSHosts retrieves all DNS records for a domain along with its email type
+func (c *Client) GetDNSHosts(ctx context.Context, domainName string) (*DNSHosts, error) {
+	resp, err := c.makeRequest(ctx, CommandDomainsDNSGetHosts, newParams().setDomain(domainName))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make domains.dns.getHosts request")
+	}
+
+	var result DNSHostsResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse domains.dns.getHosts response")
+	}
+
+	hostsResult := result.CommandResponse.DomainDNSGetHostsResult
+	zones.observe(c.zoneKey(domainName), len(hostsResult.Hosts))
+	return &DNSHosts{
+		Domain:        hostsResult.Domain,
+		EmailType:     hostsResult.EmailType,
+		IsUsingOurDNS: hostsResult.IsUsingOurDNS,
+		Records:       hostsResult.Hosts,
+	}, nil
+}
+
+// ValidateEmailType checks that the email type is consistent with the MX and
+// MXE records in the host set. MX requires at least one MX record, MXE requires
+// exactly one MXE record, and every other email type forbids both. An empty
+// email type leaves the zone setting untouched and is always valid.
+func ValidateEmailType(emailType string, records []DNSRecord) error {
+	mx, mxe := 0, 0
+	for _, record := range records {
+		switch record.Type {
+		case "MX":
+			mx++
+		case "MXE":
+			mxe++
+		}
+	}
+
+	switch emailType {
+	case "":
+		return nil
+	case EmailTypeMX:
+		if mx == 0 {
+			return errors.New("email type MX requires at least one MX record")
+		}
+		if mxe > 0 {
+			return errors.New("email type MX cannot be combined with MXE records")
+		}
+	case EmailTypeMXE:
+		if mxe != 1 {
+			return errors.Errorf("email type MXE requires exactly one MXE record, found %d", mxe)
+		}
+		if mx > 0 {
+			return errors.New("email type MXE cannot be combined with MX records")
+		}
+	case EmailTypeNone, EmailTypeFWD, EmailTypeOX:
+		if mx > 0 || mxe > 0 {
+			return errors.Errorf("email type %s cannot be combined with MX or MXE records", emailType)
+		}
+	default:
+		return errors.Errorf("unsupported email type: %s", emailType)
+	}
+
+	return nil
+}
+
+// FindDNSRecords retrieves every DNS record with the given name and type,
+// e.g. the A records of a round-robin name. It returns none if there are
+// none.
+func (c *Client) FindDNSRecords(ctx context.Context, domainName, recordName, recordType string) ([]DNSRecord, error) {
+	records, err := c.GetDNSRecords(ctx, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []DNSRecord
+	for _, record := range records {
+		if record.Name == recordName && record.Type == recordType {
+			found = append(found, record)
+		}
+	}
+	return found, nil
+}
+
+// emailTypeFor returns the email type to rewrite a domain's records with,
+// given its current one. The current email type is kept while the records
+// allow it. Otherwise an MXE record or MX records call for the MXE or MX
+// email type, which they only take effect with, and without either the
+// email type is left to Namecheap.
+func emailTypeFor(current string, records []DNSRecord) string {
+	if current != "" && ValidateEmailType(current, records) == nil {
+		return current
+	}
+	for _, emailType := range []string{EmailTypeMXE, EmailTypeMX} {
+		if ValidateEmailType(emailType, records) == nil {
+			return emailType
+		}
+	}
+	return ""
+}
+
+// GetDNSRecord retrieves a specific DNS record by name and type, the first
+// one if there are several
+func (c *Client) GetDNSRecord(ctx context.Context, domainName, recordName, recordType string) (*DNSRecord, error) {
+	records, err := c.GetDNSRecords(ctx, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if record.Name == recordName && record.Type == recordType {
+			return &record, nil
+		}
+	}
+
+	return nil, ErrDNSRecordNotFound
+}
+
+// CreateDNSRecord creates a new DNS record
+func (c *Client) CreateDNSRecord(ctx context.Context, domainName string, record DNSRecord) error {
+	return c.updateDNSRecords(ctx, domainName, func(existingRecords []DNSRecord) ([]DNSRecord, bool, error) {
+		return append(existingRecords, record), true, nil
+	})
+}
+
+// UpdateDNSRecord updates an existing DNS record, the one with the record's
+// host ID if it has one, else the first with its name and type
+func (c *Client) UpdateDNSRecord(ctx context.Context, domainName string, record DNSRecord) error {
+	return c.updateDNSRecords(ctx, domainName, func(existingRecords []DNSRecord) ([]DNSRecord, bool, error) {
+		// Find and update the record
+		for i, existingRecord := range existingRecords {
+			if (record.HostID != 0 && existingRecord.HostID == record.HostID) ||
+				(record.HostID == 0 && existingRecord.Name == record.Name && existingRecord.Type == record.Type) {
+				existingRecords[i] = record
+				return existingRecords, true, nil
+			}
+		}
+		return nil, false, errors.New("DNS record not found for update")
+	})
+}
+
+// DeleteDNSRecord deletes a DNS record
+func (c *Client) DeleteDNSRecord(ctx context.Context, domainName string, recordName, recordType string) error {
+	_, err := c.deleteDNSRecord(ctx, domainName, recordName, recordType, nil)
+	return err
+}
+
+// DeleteDNSRecordIfValue deletes a DNS record only if its current address still
+// matches the given value. It reports whether the record was deleted; a record
+// whose value was changed out-of-band is left in place and false is returned.
+func (c *Client) DeleteDNSRecordIfValue(ctx context.Context, domainName, recordName, recordType, value string) (bool, error) {
+	return c.deleteDNSRecord(ctx, domainName, recordName, recordType, func(record DNSRecord) bool {
+		return record.Address == value
+	})
+}
+
+// DeleteDNSRecordExact deletes the one DNS record with the host ID, name,
+// type and address of record, leaving other records with the same name and
+// type in place. It reports whether the record was deleted; a record whose
+// value was changed meanwhile is left in place and false is returned.
+func (c *Client) DeleteDNSRecordExact(ctx context.Context, domainName string, record DNSRecord) (bool, error) {
+	return c.deleteDNSRecord(ctx, domainName, record.Name, record.Type, func(existing DNSRecord) bool {
+		return existing.HostID == record.HostID && existing.Address == record.Address
+	})
+}
+
+// deleteDNSRecord removes the records with the given name and type that are
+// accepted by owned (all of them when owned is nil) and rewrites the zone. It
+// returns ErrDNSRecordNotFound if no record with the given name and type exists.
+func (c *Client) deleteDNSRecord(ctx context.Context, domainName, recordName, recordType string, owned func(DNSRecord) bool) (bool, error) {
+	deleted := false
+	err := c.updateDNSRecords(ctx, domainName, func(existingRecords []DNSRecord) ([]DNSRecord, bool, error) {
+		// Filter out the record to delete
+		var updatedRecords []DNSRecord
+		found := false
+		for _, record := range existingRecords {
+			if record.Name == recordName && record.Type == recordType {
+				found = true
+				if owned == nil || owned(record) {
+					deleted = true
+					continue // Skip this record (delete it)
+				}
+			}
+			updatedRecords = append(updatedRecords, record)
+		}
+
+		if !found {
+			return nil, false, errors.Wrap(ErrDNSRecordNotFound, "cannot delete DNS record")
+		}
+		return updatedRecords, deleted, nil
+	})
+	return deleted && err == nil, err
+}
+
+// updateDNSRecords rewrites a domain's host records with what update makes
+// of the current ones, unless update reports no change. Rewrites of the
+// same domain are serialized so that concurrent ones don't overwrite each
+// other's changes, and the records are read back afterwards to catch a
+// change made elsewhere meanwhile, which is returned as ErrZoneConflict.
+func (c *Client) updateDNSRecords(ctx context.Context, domainName string, update func([]DNSRecord) ([]DNSRecord, bool, error)) error {
+	return c.rewriteDNSRecords(ctx, domainName, "", update)
+}
+
+// rewriteDNSRecords rewrites a domain's host records as updateDNSRecords
+// does, with the given email type, or if it is empty the current one while
+// the records allow it.
+func (c *Client) rewriteDNSRecords(ctx context.Context, domainName, emailType string, update func([]DNSRecord) ([]DNSRecord, bool, error)) error {
+	unlock, err := zoneWrites.lock(ctx, c.zoneKey(domainName))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	hosts, err := c.GetDNSHosts(ctx, domainName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get existing DNS records")
+	}
+
+	records, changed, err := update(hosts.Records)
+	if err != nil || !changed {
+		return err
+	}
+
+	// Rewrite the email type too, which setHosts otherwise resets
+	if emailType == "" {
+		emailType = emailTypeFor(hosts.EmailType, records)
+	}
+	if err := c.setDNSRecords(ctx, domainName, emailType, records); err != nil {
+		return err
+	}
+
+	written, err := c.GetDNSRecords(WithFreshRead(ctx), domainName)
+	if err != nil {
+		return errors.Wrap(err, "failed to read back DNS records")
+	}
+	if !sameRecords(records, written) {
+		return errors.Wrapf(ErrZoneConflict, "%s has %d host records where %d were written", domainName, len(written), len(records))
+	}
+	return nil
+}
+
+// recordTypes are the record types domains.dns.setHosts accepts
+var recordTypes = map[string]bool{
+	"A": true, "AAAA": true, "ALIAS": true, "CAA": true, "CNAME": true,
+	"MX": true, "MXE": true, "NS": true, "PTR": true, "SRV": true, "TXT": true,
+	"URL": true, "URL301": true, "FRAME": true,
+}
+
+// SetDNSRecords replaces the complete host set of a domain with records in
+// a single domains.dns.setHosts call. The domain's email type is kept while
+// the records allow it, and the records are read back afterwards, as for
+// every other rewrite. The records are validated before any API call.
+func (c *Client) SetDNSRecords(ctx context.Context, domainName string, records []DNSRecord) error {
+	return c.ReplaceDNSHosts(ctx, domainName, DNSHosts{Records: records})
+}
+
+// ReplaceDNSHosts replaces the complete host set of a domain and its email
+// type as SetDNSRecords does, keeping the current email type while the
+// records allow it if hosts has none. The email type is validated against
+// the records before any API call.
+func (c *Client) ReplaceDNSHosts(ctx context.Context, domainName string, hosts DNSHosts) error {
+	if len(hosts.Records) == 0 {
+		return errors.New("at least one DNS record is required")
+	}
+	for i, record := range hosts.Records {
+		if !recordTypes[record.Type] {
+			return errors.Errorf("DNS record %d (%s) has unsupported type %q", i+1, record.Name, record.Type)
+		}
+	}
+	if err := ValidateEmailType(hosts.EmailType, hosts.Records); err != nil {
+		return err
+	}
+
+	return c.rewriteDNSRecords(ctx, domainName, hosts.EmailType, func([]DNSRecord) ([]DNSRecord, bool, error) {
+		return hosts.Records, true, nil
+	})
+}
+
+// setDNSRecords sets all DNS records for a domain (replaces existing
+// records) along with its email type. The records are based on a read of the
+// zone, so they aren't written if that read was an unconfirmed drastic drop
+// in their number.
+func (c *Client) setDNSRecords(ctx context.Context, domainName, emailType string, records []DNSRecord) error {
+	if !isZoneShrinkAllowed(ctx) {
+		if err := zones.checkWrite(c.zoneKey(domainName)); err != nil {
+			return err
+		}
+	}
+	return c.setHosts(ctx, domainName, emailType, records)
+}
+
+// setHostsParams returns the parameters of domains.dns.setHosts, which lists
+// every record with parameters numbered from 1
+func setHostsParams(domainName, emailType string, records []DNSRecord) *params {
+	params := newParams().
+		setDomain(domainName).
+		setOptional("EmailType", emailType)
+
+	for i, record := range records {
+		params.
+			setRequired(indexed("HostName", i+1), record.Name).
+			setRequired(indexed("RecordType", i+1), record.Type).
+			setRequired(indexed("Address", i+1), record.Address)
+
+		// Send every TTL and MX preference as it is, so that rewriting the
+		// zone preserves them exactly. 0 is a valid MX preference, and SRV
+		// records hold their priority in it too.
+		params.
+			setInt(indexed("TTL", i+1), record.TTL).
+			setInt(indexed("MXPref", i+1), record.MXPref).
+			setOptional(indexed("Flag", i+1), record.Flag).
+			setOptional(indexed("Tag", i+1), record.Tag)
+	}
+	return params
+}
+
+// setHosts issues domains.dns.setHosts with the given records, sending the
+// email type when one is provided
+func (c *Client) setHosts(ctx context.Context, domainName, emailType string, records []DNSRecord) error {
+	resp, err := c.makeRequest(ctx, CommandDomainsDNSSetHosts, setHostsParams(domainName, emailType, records))
+	if err != nil {
+		return errors.Wrap(err, "failed to make domains.dns.setHosts request")
+	}
+
+	var result DNSSetHostsResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse domains.dns.setHosts response")
+	}
+
+	if !result.CommandResponse.DomainDNSSetHostsResult.IsSuccess {
+		return errors.New("failed to update DNS records")
+	}
+
+	zones.wrote(c.zoneKey(domainName), len(records))
+	return nil
+}
+
+// DNSRecordExists checks if a DNS record exists
+func (c *Client) DNSRecordExists(ctx context.Context, domainName, recordName, recordType string) (bool, error) {
+	_, err := c.GetDNSRecord(ctx, domainName, recordName, recordType)
+	if err != nil {
+		if errors.Is(err, ErrDNSRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
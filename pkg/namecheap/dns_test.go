@@ -0,0 +1,739 @@
+package namecheap
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testHostsXML = `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSGetHostsResult Domain="example.com" IsUsingOurDNS="true">
+			<host HostId="1" Name="www" Type="A" Address="192.0.2.1" MXPref="10" TTL="300"/>
+			<host HostId="2" Name="@" Type="TXT" Address="v=spf1 -all" MXPref="10" TTL="300"/>
+		</DomainDNSGetHostsResult>
+	</CommandResponse>
+</ApiResponse>`
+
+const testSetHostsXML = `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSSetHostsResult Domain="example.com" IsSuccess="true"/>
+	</CommandResponse>
+</ApiResponse>`
+
+func TestClient_DeleteDNSRecord(t *testing.T) {
+	tests := []struct {
+		name          string
+		recordName    string
+		recordType    string
+		value         *string
+		expectDeleted bool
+		expectSet     bool
+		expectedError error
+	}{
+		{
+			name:          "delete existing record",
+			recordName:    "www",
+			recordType:    "A",
+			expectDeleted: true,
+			expectSet:     true,
+		},
+		{
+			name:          "record already gone",
+			recordName:    "api",
+			recordType:    "A",
+			expectedError: ErrDNSRecordNotFound,
+		},
+		{
+			name:          "value still matches",
+			recordName:    "www",
+			recordType:    "A",
+			value:         strPtr("192.0.2.1"),
+			expectDeleted: true,
+			expectSet:     true,
+		},
+		{
+			name:       "value changed out-of-band",
+			recordName: "www",
+			recordType: "A",
+			value:      strPtr("198.51.100.7"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone := newFakeZone(t, "example.com",
+				DNSRecord{Name: "www", Type: "A", Address: "192.0.2.1", TTL: 300},
+				DNSRecord{Name: "@", Type: "TXT", Address: "v=spf1 -all", TTL: 300})
+			server := httptest.NewServer(zone)
+			defer server.Close()
+
+			client := NewClient(Config{
+				APIUser:    "testuser",
+				APIKey:     "testkey",
+				Username:   "testuser",
+				ClientIP:   "127.0.0.1",
+				BaseURL:    server.URL,
+				HTTPClient: &http.Client{Timeout: 5 * time.Second},
+			})
+
+			var deleted bool
+			var err error
+			if tt.value != nil {
+				deleted, err = client.DeleteDNSRecordIfValue(context.Background(), "example.com", tt.recordName, tt.recordType, *tt.value)
+			} else {
+				err = client.DeleteDNSRecord(context.Background(), "example.com", tt.recordName, tt.recordType)
+				deleted = err == nil
+			}
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectDeleted, deleted)
+			assert.Equal(t, tt.expectSet, zone.setHosts > 0)
+			if tt.expectSet {
+				// Only the TXT record must remain
+				assert.Equal(t, []DNSRecord{{Name: "@", Type: "TXT", Address: "v=spf1 -all", TTL: 300}}, zone.records)
+			}
+		})
+	}
+}
+
+// fakeZone serves a domain's host records from domains.dns.getHosts and
+// replaces them on domains.dns.setHosts
+type fakeZone struct {
+	t      *testing.T
+	domain string
+
+	mu        sync.Mutex
+	emailType string
+	records   []DNSRecord
+	getHosts  int
+	setHosts  int
+}
+
+func newFakeZone(t *testing.T, domain string, records ...DNSRecord) *fakeZone {
+	return &fakeZone{t: t, domain: domain, records: records}
+}
+
+func (z *fakeZone) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/xml")
+	switch command := r.FormValue("Command"); command {
+	case "namecheap.domains.dns.getHosts":
+		z.getHosts++
+		_, err := w.Write([]byte(hostsXML(z.domain, z.emailType, z.records)))
+		require.NoError(z.t, err)
+	case "namecheap.domains.dns.setHosts":
+		z.setHosts++
+		z.emailType = r.FormValue("EmailType")
+		z.records = formRecords(r)
+		_, err := w.Write([]byte(testSetHostsXML))
+		require.NoError(z.t, err)
+	default:
+		z.t.Errorf("unexpected command %s", command)
+	}
+}
+
+// hostsXML returns a domains.dns.getHosts response listing records
+func hostsXML(domain, emailType string, records []DNSRecord) string {
+	var hosts strings.Builder
+	for i, record := range records {
+		fmt.Fprintf(&hosts, `<host HostId="%d" Name="%s" Type="%s" Address="%s" MXPref="%d" TTL="%d"`,
+			i+1, html.EscapeString(record.Name), record.Type, html.EscapeString(record.Address), record.MXPref, record.TTL)
+		if record.Type == "CAA" {
+			fmt.Fprintf(&hosts, ` Flag="%s" Tag="%s"`, record.Flag, record.Tag)
+		}
+		hosts.WriteString("/>")
+	}
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSGetHostsResult Domain="%s" EmailType="%s" IsUsingOurDNS="true">%s</DomainDNSGetHostsResult>
+	</CommandResponse>
+</ApiResponse>`, domain, emailType, hosts.String())
+}
+
+// formRecords returns the records of a domains.dns.setHosts request
+func formRecords(r *http.Request) []DNSRecord {
+	var records []DNSRecord
+	for i := 1; r.FormValue(fmt.Sprintf("HostName%d", i)) != ""; i++ {
+		ttl, _ := strconv.Atoi(r.FormValue(fmt.Sprintf("TTL%d", i)))
+		mxPref, _ := strconv.Atoi(r.FormValue(fmt.Sprintf("MXPref%d", i)))
+		records = append(records, DNSRecord{
+			Name:    r.FormValue(fmt.Sprintf("HostName%d", i)),
+			Type:    r.FormValue(fmt.Sprintf("RecordType%d", i)),
+			Address: r.FormValue(fmt.Sprintf("Address%d", i)),
+			MXPref:  mxPref,
+			TTL:     ttl,
+			Flag:    r.FormValue(fmt.Sprintf("Flag%d", i)),
+			Tag:     r.FormValue(fmt.Sprintf("Tag%d", i)),
+		})
+	}
+	return records
+}
+
+func TestClient_UpdateDNSRecord_PreservesZone(t *testing.T) {
+	zone := newFakeZone(t, "roundtrip.example",
+		DNSRecord{Name: "@", Type: "A", Address: "192.0.2.1", MXPref: 10, TTL: 1800},
+		DNSRecord{Name: "@", Type: "MXE", Address: "192.0.2.25", MXPref: 10, TTL: 0},
+		DNSRecord{Name: "@", Type: "CAA", Address: `128 issue "letsencrypt.org"`, MXPref: 10, TTL: 1799, Flag: "128", Tag: "issue"},
+		DNSRecord{Name: "www", Type: "CNAME", Address: "example.com.", MXPref: 10, TTL: 60})
+	zone.emailType = EmailTypeMXE
+	before := hostsXML(zone.domain, zone.emailType, zone.records)
+
+	server := httptest.NewServer(zone)
+	defer server.Close()
+	client := NewClient(Config{
+		APIUser:             "roundtripuser",
+		APIKey:              "testkey",
+		Username:            "roundtripuser",
+		ClientIP:            "127.0.0.1",
+		BaseURL:             server.URL,
+		HTTPClient:          &http.Client{Timeout: 5 * time.Second},
+		DomainWriteInterval: -1,
+	})
+
+	// Rewriting the zone with an unchanged record leaves it as it was
+	hosts, err := client.GetDNSHosts(context.Background(), "roundtrip.example")
+	require.NoError(t, err)
+	require.NoError(t, client.UpdateDNSRecord(context.Background(), "roundtrip.example", hosts.Records[0]))
+	assert.Equal(t, 1, zone.setHosts)
+	assert.Equal(t, before, hostsXML(zone.domain, zone.emailType, zone.records))
+
+	after, err := client.GetDNSHosts(WithFreshRead(context.Background()), "roundtrip.example")
+	require.NoError(t, err)
+	assert.Equal(t, hosts, after)
+}
+
+func TestClient_CreateDNSRecord_Concurrent(t *testing.T) {
+	zone := newFakeZone(t, "concurrent.example",
+		DNSRecord{Name: "@", Type: "TXT", Address: "v=spf1 -all", TTL: 300})
+	server := httptest.NewServer(zone)
+	defer server.Close()
+
+	// Every reconcile creates its own client
+	newClient := func() *Client {
+		return NewClient(Config{
+			APIUser:             "concurrentuser",
+			APIKey:              "testkey",
+			Username:            "concurrentuser",
+			ClientIP:            "127.0.0.1",
+			BaseURL:             server.URL,
+			HTTPClient:          &http.Client{Timeout: 5 * time.Second},
+			DomainWriteInterval: -1,
+		})
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			record := DNSRecord{Name: fmt.Sprintf("host%d", i), Type: "A", Address: fmt.Sprintf("192.0.2.%d", i+1), TTL: 300}
+			errs[i] = newClient().CreateDNSRecord(context.Background(), "concurrent.example", record)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 10, zone.setHosts)
+	require.Len(t, zone.records, 11, "every record should survive the concurrent rewrites")
+	names := map[string]bool{}
+	for _, record := range zone.records {
+		names[record.Name] = true
+	}
+	for i := range errs {
+		assert.True(t, names[fmt.Sprintf("host%d", i)], "host%d was lost", i)
+	}
+}
+
+func TestClient_CreateDNSRecord_Redirect(t *testing.T) {
+	zone := newFakeZone(t, "vanity.example")
+	server := httptest.NewServer(zone)
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:             "redirectuser",
+		APIKey:              "testkey",
+		Username:            "redirectuser",
+		ClientIP:            "127.0.0.1",
+		BaseURL:             server.URL,
+		HTTPClient:          &http.Client{Timeout: 5 * time.Second},
+		DomainWriteInterval: -1,
+	})
+
+	// The target goes through the request and back untouched
+	const target = "https://www.example.net/Landing/page?utm_source=vanity&ref=a%20b#top"
+	err := client.CreateDNSRecord(context.Background(), "vanity.example",
+		DNSRecord{Name: "@", Type: "URL301", Address: target, TTL: 1800})
+	require.NoError(t, err)
+	require.Len(t, zone.records, 1)
+	assert.Equal(t, target, zone.records[0].Address)
+
+	record, err := client.GetDNSRecord(context.Background(), "vanity.example", "@", "URL301")
+	require.NoError(t, err)
+	assert.Equal(t, target, record.Address)
+}
+
+func TestClient_MXEZone(t *testing.T) {
+	var hosts DNSHostsResponse
+	require.NoError(t, xml.Unmarshal(fixture(t, "domains.dns.getHosts.mxe"), &hosts))
+	result := hosts.CommandResponse.DomainDNSGetHostsResult
+	zone := newFakeZone(t, "example.com", result.Hosts...)
+	zone.emailType = result.EmailType
+	server := httptest.NewServer(zone)
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:             "mxeuser",
+		APIKey:              "testkey",
+		Username:            "mxeuser",
+		ClientIP:            "127.0.0.1",
+		BaseURL:             server.URL,
+		HTTPClient:          &http.Client{Timeout: 5 * time.Second},
+		DomainWriteInterval: -1,
+	})
+	ctx := context.Background()
+
+	mxe, err := client.GetDNSRecord(ctx, "example.com", "mail", "MXE")
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.25", mxe.Address)
+
+	// Writing an unrelated record keeps the MXE record and email type
+	require.NoError(t, client.CreateDNSRecord(ctx, "example.com",
+		DNSRecord{Name: "www", Type: "A", Address: "192.0.2.1", MXPref: 10, TTL: 1800}))
+	assert.Equal(t, EmailTypeMXE, zone.emailType)
+	require.Len(t, zone.records, 3)
+	assert.Equal(t, DNSRecord{Name: "mail", Type: "MXE", Address: "192.0.2.25", MXPref: 10, TTL: 1800}, zone.records[1])
+
+	// Without its MXE record the email type is left to Namecheap
+	require.NoError(t, client.DeleteDNSRecord(ctx, "example.com", "mail", "MXE"))
+	assert.Empty(t, zone.emailType)
+
+	// and an MXE record switches it to MXE
+	require.NoError(t, client.CreateDNSRecord(ctx, "example.com",
+		DNSRecord{Name: "mail", Type: "MXE", Address: "192.0.2.26", MXPref: 10, TTL: 1800}))
+	assert.Equal(t, EmailTypeMXE, zone.emailType)
+}
+
+func TestClient_SetDNSRecords(t *testing.T) {
+	var existing, replacement []DNSRecord
+	for i := 0; i < 50; i++ {
+		existing = append(existing, DNSRecord{Name: fmt.Sprintf("old%d", i), Type: "A", Address: "192.0.2.1", MXPref: 10, TTL: 1800})
+		replacement = append(replacement, DNSRecord{Name: fmt.Sprintf("new%d", i), Type: "CNAME", Address: "example.com.", MXPref: 10, TTL: 300})
+	}
+	zone := newFakeZone(t, "batch.example", existing...)
+	zone.emailType = EmailTypeFWD
+	server := httptest.NewServer(zone)
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:             "batchuser",
+		APIKey:              "testkey",
+		Username:            "batchuser",
+		ClientIP:            "127.0.0.1",
+		BaseURL:             server.URL,
+		HTTPClient:          &http.Client{Timeout: 5 * time.Second},
+		DomainWriteInterval: -1,
+		RateLimitConfig:     &RateLimitConfig{RequestsPerSecond: 1000, BurstSize: 1000},
+	})
+	ctx := context.Background()
+
+	// The whole zone is replaced in one write, between the read of its
+	// email type and the read back of the written records
+	require.NoError(t, client.SetDNSRecords(ctx, "batch.example", replacement))
+	assert.Equal(t, 1, zone.setHosts)
+	assert.Equal(t, 2, zone.getHosts)
+	assert.Equal(t, replacement, zone.records)
+	assert.Equal(t, EmailTypeFWD, zone.emailType)
+
+	// Invalid record sets are refused before any request
+	for _, records := range [][]DNSRecord{
+		nil,
+		{{Name: "@", Type: "HINFO", Address: "x"}},
+		{{Name: "@", Type: "a", Address: "192.0.2.1"}},
+	} {
+		assert.Error(t, client.SetDNSRecords(ctx, "batch.example", records))
+	}
+	assert.Equal(t, 1, zone.setHosts)
+	assert.Equal(t, 2, zone.getHosts)
+
+	// ReplaceDNSHosts sets the email type too, once the records allow it
+	mx := append(replacement[:1:1], DNSRecord{Name: "@", Type: "MX", Address: "mail.example.com", MXPref: 10, TTL: 300})
+	require.Error(t, client.ReplaceDNSHosts(ctx, "batch.example", DNSHosts{EmailType: EmailTypeFWD, Records: mx}))
+	require.NoError(t, client.ReplaceDNSHosts(ctx, "batch.example", DNSHosts{EmailType: EmailTypeMX, Records: mx}))
+	assert.Equal(t, EmailTypeMX, zone.emailType)
+	assert.Equal(t, mx, zone.records)
+}
+
+func TestEmailTypeFor(t *testing.T) {
+	a := DNSRecord{Type: "A"}
+	mx := DNSRecord{Type: "MX"}
+	mxe := DNSRecord{Type: "MXE"}
+
+	assert.Equal(t, EmailTypeFWD, emailTypeFor(EmailTypeFWD, []DNSRecord{a}))
+	assert.Equal(t, EmailTypeMX, emailTypeFor(EmailTypeMX, []DNSRecord{a, mx}))
+	assert.Equal(t, EmailTypeMXE, emailTypeFor(EmailTypeNone, []DNSRecord{a, mxe}))
+	assert.Equal(t, EmailTypeMX, emailTypeFor(EmailTypeFWD, []DNSRecord{mx}))
+	assert.Equal(t, "", emailTypeFor(EmailTypeMX, []DNSRecord{a}))
+	assert.Equal(t, "", emailTypeFor(EmailTypeMXE, []DNSRecord{mx, mxe}))
+	assert.Equal(t, "", emailTypeFor("", []DNSRecord{a}))
+}
+
+func TestClient_RoundRobin(t *testing.T) {
+	zone := newFakeZone(t, "roundrobin.example",
+		DNSRecord{Name: "www", Type: "A", Address: "192.0.2.1", TTL: 300},
+		DNSRecord{Name: "www", Type: "A", Address: "192.0.2.2", TTL: 300},
+		DNSRecord{Name: "@", Type: "A", Address: "192.0.2.1", TTL: 300})
+	server := httptest.NewServer(zone)
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:             "roundrobinuser",
+		APIKey:              "testkey",
+		Username:            "roundrobinuser",
+		ClientIP:            "127.0.0.1",
+		BaseURL:             server.URL,
+		HTTPClient:          &http.Client{Timeout: 5 * time.Second},
+		DomainWriteInterval: -1,
+	})
+	ctx := context.Background()
+
+	records, err := client.FindDNSRecords(ctx, "roundrobin.example", "www", "A")
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "192.0.2.1", records[0].Address)
+	assert.Equal(t, "192.0.2.2", records[1].Address)
+
+	// The host ID picks the record to update
+	second := records[1]
+	second.Address = "192.0.2.3"
+	require.NoError(t, client.UpdateDNSRecord(ctx, "roundrobin.example", second))
+	assert.Equal(t, "192.0.2.1", zone.records[0].Address)
+	assert.Equal(t, "192.0.2.3", zone.records[1].Address)
+
+	// Only the exact record is deleted, and not once its value changed
+	deleted, err := client.DeleteDNSRecordExact(ctx, "roundrobin.example", records[1])
+	require.NoError(t, err)
+	assert.False(t, deleted)
+	require.Len(t, zone.records, 3)
+
+	deleted, err = client.DeleteDNSRecordExact(ctx, "roundrobin.example", records[0])
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	require.Len(t, zone.records, 2)
+	assert.Equal(t, "192.0.2.3", zone.records[0].Address)
+	assert.Equal(t, "@", zone.records[1].Name)
+
+	records, err = client.FindDNSRecords(ctx, "roundrobin.example", "mail", "A")
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestClient_CreateDNSRecord_Conflict(t *testing.T) {
+	// The zone gains a record elsewhere right after it is rewritten
+	outOfBand := DNSRecord{Name: "mail", Type: "A", Address: "192.0.2.25", TTL: 300}
+	zone := newFakeZone(t, "conflict.example")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zone.ServeHTTP(w, r)
+		if r.FormValue("Command") == "namecheap.domains.dns.setHosts" {
+			zone.records = append(zone.records, outOfBand)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:             "conflictuser",
+		APIKey:              "testkey",
+		Username:            "conflictuser",
+		ClientIP:            "127.0.0.1",
+		BaseURL:             server.URL,
+		HTTPClient:          &http.Client{Timeout: 5 * time.Second},
+		DomainWriteInterval: -1,
+	})
+
+	err := client.CreateDNSRecord(context.Background(), "conflict.example",
+		DNSRecord{Name: "www", Type: "A", Address: "192.0.2.1", TTL: 300})
+	assert.ErrorIs(t, err, ErrZoneConflict)
+}
+
+func TestZoneLocks_Cancelled(t *testing.T) {
+	locks := &zoneLocks{locks: map[zoneKey]*zoneLock{}}
+	key := zoneKey{domain: "locked.example"}
+
+	unlock, err := locks.lock(context.Background(), key)
+	require.NoError(t, err)
+
+	// A second writer gives up once its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = locks.lock(ctx, key)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// and the lock is forgotten once released
+	unlock()
+	assert.Empty(t, locks.locks)
+}
+
+func TestClient_DNSRecordExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(testHostsXML))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	exists, err := client.DNSRecordExists(context.Background(), "example.com", "www", "A")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = client.DNSRecordExists(context.Background(), "example.com", "www", "AAAA")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestValidateEmailType(t *testing.T) {
+	mx := DNSRecord{Name: "@", Type: "MX", Address: "mail.example.com", MXPref: 10}
+	mxe := DNSRecord{Name: "@", Type: "MXE", Address: "192.0.2.25"}
+	a := DNSRecord{Name: "www", Type: "A", Address: "192.0.2.1"}
+
+	tests := []struct {
+		name          string
+		emailType     string
+		records       []DNSRecord
+		expectedError string
+	}{
+		{name: "unset", emailType: "", records: []DNSRecord{a, mx}},
+		{name: "NONE without mail records", emailType: EmailTypeNone, records: []DNSRecord{a}},
+		{name: "MX with one MX record", emailType: EmailTypeMX, records: []DNSRecord{a, mx}},
+		{name: "MX with two MX records", emailType: EmailTypeMX, records: []DNSRecord{mx, mx}},
+		{name: "MXE with one MXE record", emailType: EmailTypeMXE, records: []DNSRecord{a, mxe}},
+		{name: "FWD without mail records", emailType: EmailTypeFWD, records: []DNSRecord{a}},
+		{name: "OX without mail records", emailType: EmailTypeOX, records: []DNSRecord{a}},
+		{
+			name:          "MX without MX records",
+			emailType:     EmailTypeMX,
+			records:       []DNSRecord{a},
+			expectedError: "requires at least one MX record",
+		},
+		{
+			name:          "MX mixed with MXE",
+			emailType:     EmailTypeMX,
+			records:       []DNSRecord{mx, mxe},
+			expectedError: "cannot be combined with MXE records",
+		},
+		{
+			name:          "MXE without MXE record",
+			emailType:     EmailTypeMXE,
+			records:       []DNSRecord{a},
+			expectedError: "requires exactly one MXE record, found 0",
+		},
+		{
+			name:          "MXE with two MXE records",
+			emailType:     EmailTypeMXE,
+			records:       []DNSRecord{mxe, mxe},
+			expectedError: "requires exactly one MXE record, found 2",
+		},
+		{
+			name:          "MXE mixed with MX",
+			emailType:     EmailTypeMXE,
+			records:       []DNSRecord{mxe, mx},
+			expectedError: "cannot be combined with MX records",
+		},
+		{
+			name:          "NONE with MX record",
+			emailType:     EmailTypeNone,
+			records:       []DNSRecord{mx},
+			expectedError: "email type NONE cannot be combined",
+		},
+		{
+			name:          "FWD with MXE record",
+			emailType:     EmailTypeFWD,
+			records:       []DNSRecord{mxe},
+			expectedError: "email type FWD cannot be combined",
+		},
+		{
+			name:          "OX with MX record",
+			emailType:     EmailTypeOX,
+			records:       []DNSRecord{mx},
+			expectedError: "email type OX cannot be combined",
+		},
+		{
+			name:          "unknown email type",
+			emailType:     "GMAIL",
+			expectedError: "unsupported email type: GMAIL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEmailType(tt.emailType, tt.records)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClient_DNSHostsEmailType(t *testing.T) {
+	var sentEmailType string
+	setCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		switch r.FormValue("Command") {
+		case "namecheap.domains.dns.getHosts":
+			_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSGetHostsResult Domain="example.com" EmailType="MXE" IsUsingOurDNS="true">
+			<host HostId="1" Name="@" Type="MXE" Address="192.0.2.25" MXPref="10" TTL="1800"/>
+		</DomainDNSGetHostsResult>
+	</CommandResponse>
+</ApiResponse>`))
+			require.NoError(t, err)
+		case "namecheap.domains.dns.setHosts":
+			setCalls++
+			sentEmailType = r.FormValue("EmailType")
+			_, err := w.Write([]byte(testSetHostsXML))
+			require.NoError(t, err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	hosts, err := client.GetDNSHosts(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, EmailTypeMXE, hosts.EmailType)
+	assert.True(t, hosts.IsUsingOurDNS)
+	require.Len(t, hosts.Records, 1)
+
+	// A consistent host set is written with its email type
+	err = client.ReplaceDNSHosts(context.Background(), "example.com", *hosts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, setCalls)
+	assert.Equal(t, EmailTypeMXE, sentEmailType)
+
+	// An inconsistent host set is rejected before reaching the API
+	hosts.EmailType = EmailTypeMX
+	err = client.ReplaceDNSHosts(context.Background(), "example.com", *hosts)
+	assert.Error(t, err)
+	assert.Equal(t, 1, setCalls)
+}
+
+func TestSetHostsParams(t *testing.T) {
+	tests := []struct {
+		name      string
+		emailType string
+		records   []DNSRecord
+		want      map[string]string
+	}{
+		{
+			name: "records",
+			records: []DNSRecord{
+				{Name: "@", Type: "A", Address: "192.0.2.1", TTL: 1800},
+				{Name: "www", Type: "CNAME", Address: "example.com"},
+			},
+			want: map[string]string{
+				"SLD": "example", "TLD": "com",
+				"HostName1": "@", "RecordType1": "A", "Address1": "192.0.2.1", "TTL1": "1800", "MXPref1": "0",
+				"HostName2": "www", "RecordType2": "CNAME", "Address2": "example.com", "TTL2": "0", "MXPref2": "0",
+			},
+		},
+		{
+			// 0 is a valid MX preference
+			name:      "MX preference 0",
+			emailType: "MX",
+			records: []DNSRecord{
+				{Name: "@", Type: "A", Address: "192.0.2.1"},
+				{Name: "@", Type: "MX", Address: "mail.example.com", MXPref: 0, TTL: 300},
+			},
+			want: map[string]string{
+				"SLD": "example", "TLD": "com", "EmailType": "MX",
+				"HostName1": "@", "RecordType1": "A", "Address1": "192.0.2.1", "TTL1": "0", "MXPref1": "0",
+				"HostName2": "@", "RecordType2": "MX", "Address2": "mail.example.com", "TTL2": "300", "MXPref2": "0",
+			},
+		},
+		{
+			name:    "SRV priority",
+			records: []DNSRecord{{Name: "_sip._tcp", Type: "SRV", Address: "10 60 5060 sip.example.com", MXPref: 10, TTL: 300}},
+			want: map[string]string{
+				"SLD": "example", "TLD": "com",
+				"HostName1": "_sip._tcp", "RecordType1": "SRV", "Address1": "10 60 5060 sip.example.com", "TTL1": "300", "MXPref1": "10",
+			},
+		},
+		{
+			name:    "MX preference 10",
+			records: []DNSRecord{{Name: "@", Type: "MX", Address: "mail.example.com", MXPref: 10}},
+			want: map[string]string{
+				"SLD": "example", "TLD": "com",
+				"HostName1": "@", "RecordType1": "MX", "Address1": "mail.example.com", "TTL1": "0", "MXPref1": "10",
+			},
+		},
+		{
+			// Flags and tags are sent as reported
+			name:    "CAA flag and tag",
+			records: []DNSRecord{{Name: "@", Type: "CAA", Address: `0 issue "letsencrypt.org"`, TTL: 1800, Flag: "0", Tag: "issue"}},
+			want: map[string]string{
+				"SLD": "example", "TLD": "com",
+				"HostName1": "@", "RecordType1": "CAA", "Address1": `0 issue "letsencrypt.org"`, "TTL1": "1800", "MXPref1": "0", "Flag1": "0", "Tag1": "issue",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := setHostsParams("example.com", tt.emailType, tt.records)
+			require.NoError(t, params.validate())
+			assert.Equal(t, tt.want, params.asMap())
+		})
+	}
+
+	// A record without an address is refused before any request
+	params := setHostsParams("example.com", "", []DNSRecord{{Name: "@", Type: "A"}})
+	assert.EqualError(t, params.validate(), "missing required parameter Address1")
+}
@@ -0,0 +1,15 @@
+// Package namecheap is a client for the Namecheap XML API, used by the
+// provider's controllers and importable by other tools.
+//
+// NewClient returns a Client configured by Config. Client implements API,
+// which consumers may depend on instead so that tests can substitute a fake.
+// Errors reported by the API are returned as Error, and HTTP failures as
+// HTTPError.
+//
+// The exported identifiers of this package are its public surface and are
+// listed in testdata/api.golden. TestExportedAPI fails whenever they change,
+// so that changes to the surface are deliberate; accept an intended change by
+// running
+//
+//	go test ./pkg/namecheap -run TestExportedAPI -update
+package namecheap
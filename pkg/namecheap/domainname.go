@@ -0,0 +1,55 @@
+package namecheap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/publicsuffix"
+)
+
+// SubdomainError is returned for a subdomain given where a registrable
+// domain is expected, e.g. www.example.com instead of example.com
+type SubdomainError struct {
+	// DomainName is the name given
+	DomainName string
+
+	// Registrable is the registrable domain DomainName is a subdomain of
+	Registrable string
+}
+
+func (e *SubdomainError) Error() string {
+	return fmt.Sprintf("%q is a subdomain of %q, not a registrable domain", e.DomainName, e.Registrable)
+}
+
+// IsSubdomain reports whether err is a subdomain given where a registrable
+// domain is expected
+func IsSubdomain(err error) bool {
+	var subdomain *SubdomainError
+	return errors.As(err, &subdomain)
+}
+
+// SplitDomain splits a registrable domain name into the SLD and TLD the API
+// takes, in punycode. The TLD is the domain's public suffix, which may have
+// several labels, e.g. example.co.uk splits into example and co.uk. A
+// subdomain, such as www.example.com, returns a *SubdomainError.
+//
+// Public suffixes are those of the Public Suffix List, including its
+// privately operated ones, such as us.com, some of which Namecheap sells
+// registrations under. Names under an unlisted TLD take their last label as
+// the TLD.
+func SplitDomain(domainName string) (sld, tld string, err error) {
+	ascii, err := ToASCII(domainName)
+	if err != nil {
+		return "", "", err
+	}
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(ascii)
+	if err != nil {
+		return "", "", errors.Errorf("invalid domain name format %q", domainName)
+	}
+	if registrable != ascii {
+		return "", "", &SubdomainError{DomainName: domainName, Registrable: registrable}
+	}
+	sld, tld, _ = strings.Cut(ascii, ".")
+	return sld, tld, nil
+}
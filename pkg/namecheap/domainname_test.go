@@ -0,0 +1,90 @@
+package namecheap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitDomain(t *testing.T) {
+	tests := []struct {
+		name        string
+		domain      string
+		sld         string
+		tld         string
+		registrable string
+		wantErr     string
+	}{
+		{name: "com", domain: "example.com", sld: "example", tld: "com"},
+		{name: "co.uk", domain: "example.co.uk", sld: "example", tld: "co.uk"},
+		{name: "org.uk", domain: "example.org.uk", sld: "example", tld: "org.uk"},
+		{name: "private suffix", domain: "example.us.com", sld: "example", tld: "us.com"},
+		{name: "unlisted TLD", domain: "example.internal", sld: "example", tld: "internal"},
+		{name: "upper case and trailing dot", domain: "Example.CO.UK.", sld: "example", tld: "co.uk"},
+		{name: "Unicode SLD", domain: "bücher.com", sld: "xn--bcher-kva", tld: "com"},
+		{name: "Unicode TLD", domain: "例え.テスト", sld: "xn--r8jz45g", tld: "xn--zckzah"},
+		{name: "subdomain", domain: "www.example.com", registrable: "example.com"},
+		{name: "subdomain of co.uk", domain: "www.example.co.uk", registrable: "example.co.uk"},
+		{name: "public suffix", domain: "co.uk", wantErr: `invalid domain name format "co.uk"`},
+		{name: "no TLD", domain: "example", wantErr: `invalid domain name format "example"`},
+		{name: "invalid label", domain: "exa mple.com", wantErr: `invalid domain name "exa mple.com": idna: disallowed rune U+0020`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sld, tld, err := SplitDomain(tt.domain)
+			switch {
+			case tt.registrable != "":
+				var subdomain *SubdomainError
+				require.ErrorAs(t, err, &subdomain)
+				assert.Equal(t, tt.domain, subdomain.DomainName)
+				assert.Equal(t, tt.registrable, subdomain.Registrable)
+				assert.True(t, IsSubdomain(err))
+			case tt.wantErr != "":
+				assert.EqualError(t, err, tt.wantErr)
+				assert.False(t, IsSubdomain(err))
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.sld, sld)
+				assert.Equal(t, tt.tld, tld)
+			}
+		})
+	}
+}
+
+func TestClient_Subdomain(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+	ctx := context.Background()
+
+	err := client.SetNameservers(ctx, "www.example.com", []string{"ns1.example.net", "ns2.example.net"})
+	assert.True(t, IsSubdomain(err), "SetNameservers: %v", err)
+
+	_, err = client.GetDNSRecords(ctx, "www.example.com")
+	assert.True(t, IsSubdomain(err), "GetDNSRecords: %v", err)
+
+	err = client.CreateDNSRecord(ctx, "www.example.com", DNSRecord{Name: "@", Type: "A", Address: "192.0.2.1"})
+	assert.True(t, IsSubdomain(err), "CreateDNSRecord: %v", err)
+
+	_, err = client.GetTLDForDomain(ctx, "www.example.co.uk")
+	assert.True(t, IsSubdomain(err), "GetTLDForDomain: %v", err)
+
+	assert.Zero(t, calls, "subdomains must not be sent")
+}
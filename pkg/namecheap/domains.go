@@ -0,0 +1,940 @@
+package namecheap
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Domain represents a domain in Namecheap
+type Domain struct {
+	ID         int    `xml:"ID,attr"`
+	Name       string `xml:"Name,attr"`
+	User       string `xml:"User,attr"`
+	Created    ncTime `xml:"Created,attr"`
+	Expires    ncTime `xml:"Expires,attr"`
+	IsExpired  bool   `xml:"IsExpired,attr"`
+	IsLocked   bool   `xml:"IsLocked,attr"`
+	AutoRenew  bool   `xml:"AutoRenew,attr"`
+	WhoisGuard string `xml:"WhoisGuard,attr"`
+	IsPremium  bool   `xml:"IsPremium,attr"`
+	// IsOurDNS is set when the domain uses Namecheap DNS. domains.getInfo
+	// reports it in its DnsDetails.
+	IsOurDNS bool `xml:"IsOurDNS,attr"`
+
+	// TransferOutPending is populated from the LockDetails of
+	// domains.getInfo and is always false for domains.getList results.
+	TransferOutPending bool `xml:"-"`
+
+	// Statuses are the EPP status codes reported by domains.getInfo and are
+	// always empty for domains.getList results.
+	Statuses []string `xml:"-"`
+
+	// Nameservers are the nameservers reported in the DnsDetails of
+	// domains.getInfo and are always empty for domains.getList results.
+	Nameservers []string `xml:"-"`
+}
+
+// LockDetails describes the lock and transfer-out state reported by
+// domains.getInfo
+type LockDetails struct {
+	// TransferOutPending is set when a transfer-out (EPP code) request is
+	// in progress for the domain.
+	TransferOutPending bool `xml:"TransferOutPending,attr"`
+}
+
+// DNSDetails describes the DNS a domain is delegated to, as reported by
+// domains.getInfo
+type DNSDetails struct {
+	// ProviderType is the kind of DNS the domain uses, such as FREE for
+	// Namecheap's free DNS or CUSTOM for custom nameservers
+	ProviderType string `xml:"ProviderType,attr"`
+
+	// IsUsingOurDNS is set when the domain uses Namecheap DNS
+	IsUsingOurDNS bool `xml:"IsUsingOurDNS,attr"`
+
+	// Nameservers are the domain's nameservers, normalized
+	Nameservers []string `xml:"Nameserver"`
+}
+
+// DomainInfo is a domain with the details only domains.getInfo reports
+type DomainInfo struct {
+	Domain
+
+	// DNS is the DNS the domain is delegated to
+	DNS DNSDetails
+
+	// WhoisGuardForwardedTo is the address mail to the domain's WhoisGuard
+	// email is forwarded to. whoisguard.getList doesn't report it.
+	WhoisGuardForwardedTo string
+}
+
+// DomainListResponse represents the response from domains.getList
+type DomainListResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainGetListResult struct {
+			Domains []Domain `xml:"Domain"`
+		} `xml:"DomainGetListResult"`
+		Paging Paging `xml:"Paging"`
+	} `xml:"CommandResponse"`
+}
+
+// DomainInfoResponse represents the response from domains.getInfo. Unlike
+// domains.getList, it reports the domain's identity in attributes of
+// DomainGetInfoResult and its dates in elements of DomainDetails.
+type DomainInfoResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainGetInfoResult struct {
+			Status         string            `xml:"Status,attr"`
+			ID             int               `xml:"ID,attr"`
+			DomainName     string            `xml:"DomainName,attr"`
+			OwnerName      string            `xml:"OwnerName,attr"`
+			IsPremium      bool              `xml:"IsPremium,attr"`
+			DomainDetails  DomainInfoDetails `xml:"DomainDetails"`
+			LockDetails    LockDetails       `xml:"LockDetails"`
+			DomainStatuses []string          `xml:"DomainStatuses>Status"`
+			DnsDetails     DNSDetails        `xml:"DnsDetails"`
+			Whoisguard     struct {
+				ForwardedTo string `xml:"ForwardedTo,attr"`
+			} `xml:"Whoisguard>EmailDetails"`
+		} `xml:"DomainGetInfoResult"`
+	} `xml:"CommandResponse"`
+}
+
+// DomainStatusExpired is the Status domains.getInfo reports for an expired
+// domain
+const DomainStatusExpired = "Expired"
+
+// DomainInfoDetails are the dates of a domain reported by domains.getInfo
+type DomainInfoDetails struct {
+	CreatedDate ncTime `xml:"CreatedDate"`
+	ExpiredDate ncTime `xml:"ExpiredDate"`
+}
+
+// RegistrarLockResponse represents the response from domains.getRegistrarLock
+type RegistrarLockResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainGetRegistrarLockResult struct {
+			Domain              string `xml:"Domain,attr"`
+			RegistrarLockStatus bool   `xml:"RegistrarLockStatus,attr"`
+		} `xml:"DomainGetRegistrarLockResult"`
+	} `xml:"CommandResponse"`
+}
+
+// RegistrarLockSetResponse represents the response from domains.setRegistrarLock
+type RegistrarLockSetResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainSetRegistrarLockResult struct {
+			Domain    string `xml:"Domain,attr"`
+			IsSuccess bool   `xml:"IsSuccess,attr"`
+		} `xml:"DomainSetRegistrarLockResult"`
+	} `xml:"CommandResponse"`
+}
+
+// DomainCreateResponse represents the response from domains.create
+type DomainCreateResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainCreateResult struct {
+			Domain            string  `xml:"Domain,attr"`
+			Registered        bool    `xml:"Registered,attr"`
+			ChargedAmount     float64 `xml:"ChargedAmount,attr"`
+			DomainID          int     `xml:"DomainID,attr"`
+			OrderID           int     `xml:"OrderID,attr"`
+			TransactionID     int     `xml:"TransactionID,attr"`
+			WhoisGuardEnable  bool    `xml:"WhoisguardEnable,attr"`
+			NonRealTimeDomain bool    `xml:"NonRealTimeDomain,attr"`
+		} `xml:"DomainCreateResult"`
+	} `xml:"CommandResponse"`
+}
+
+// DNSSetCustomResponse represents the response from domains.dns.setCustom
+type DNSSetCustomResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainDNSSetCustomResult struct {
+			Domain  string `xml:"Domain,attr"`
+			Updated bool   `xml:"Updated,attr"`
+			// Update is the attribute the API actually sends for
+			// domains.dns.setCustom, unlike domains.dns.setDefault
+			Update bool `xml:"Update,attr"`
+		} `xml:"DomainDNSSetCustomResult"`
+	} `xml:"CommandResponse"`
+}
+
+// Domain list types accepted by domains.getList
+const (
+	DomainListAll      = "ALL"
+	DomainListExpiring = "EXPIRING"
+	DomainListExpired  = "EXPIRED"
+)
+
+// Domain list sort orders accepted by domains.getList
+const (
+	DomainSortName           = "NAME"
+	DomainSortNameDesc       = "NAME_DESC"
+	DomainSortExpireDate     = "EXPIREDATE"
+	DomainSortExpireDateDesc = "EXPIREDATE_DESC"
+	DomainSortCreateDate     = "CREATEDATE"
+	DomainSortCreateDateDesc = "CREATEDATE_DESC"
+)
+
+// DomainListOptions narrows and orders the domains listed by
+// GetDomainsWithOptions. Empty fields are left to Namecheap's defaults.
+type DomainListOptions struct {
+	// ListType is DomainListAll, DomainListExpiring or DomainListExpired
+	ListType string
+
+	// SearchTerm only lists domains whose name contains it
+	SearchTerm string
+
+	// SortBy is one of the DomainSort orders
+	SortBy string
+}
+
+// validate checks that the list type and sort order are ones
+// domains.getList accepts
+func (o DomainListOptions) validate() error {
+	switch o.ListType {
+	case "", DomainListAll, DomainListExpiring, DomainListExpired:
+	default:
+		return errors.Errorf("invalid domain list type %q", o.ListType)
+	}
+	switch o.SortBy {
+	case "", DomainSortName, DomainSortNameDesc, DomainSortExpireDate,
+		DomainSortExpireDateDesc, DomainSortCreateDate, DomainSortCreateDateDesc:
+	default:
+		return errors.Errorf("invalid domain sort order %q", o.SortBy)
+	}
+	return nil
+}
+
+// GetDomains retrieves every domain in the account, requesting as many pages
+// of domains.getList as the account needs. Cancelling ctx stops the fetch
+// between pages.
+func (c *Client) GetDomains(ctx context.Context) ([]Domain, error) {
+	return c.GetDomainsWithOptions(ctx, DomainListOptions{})
+}
+
+// GetDomainsWithOptions retrieves the account's domains matching opts,
+// requesting as many pages of domains.getList as the matches need.
+// Cancelling ctx stops the fetch between pages.
+func (c *Client) GetDomainsWithOptions(ctx context.Context, opts DomainListOptions) ([]Domain, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	var domains []Domain
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.Wrap(err, "domains.getList cancelled")
+		}
+
+		result, err := c.listDomains(ctx, newParams().
+			setOptional("ListType", opts.ListType).
+			setOptional("SearchTerm", opts.SearchTerm).
+			setOptional("SortBy", opts.SortBy).
+			setInt("Page", page).
+			setInt("PageSize", domainListMaxPageSize))
+		if err != nil {
+			return nil, err
+		}
+
+		pageDomains := result.CommandResponse.DomainGetListResult.Domains
+		domains = append(domains, pageDomains...)
+		if len(pageDomains) == 0 || len(domains) >= result.CommandResponse.Paging.TotalItems {
+			return domains, nil
+		}
+	}
+}
+
+// Page sizes accepted by domains.getList
+const (
+	domainListMinPageSize = 10
+	domainListMaxPageSize = 100
+)
+
+// listDomains requests a page of domains.getList
+func (c *Client) listDomains(ctx context.Context, params *params) (*DomainListResponse, error) {
+	resp, err := c.makeRequest(ctx, CommandDomainsGetList, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make domains.getList request")
+	}
+
+	var result DomainListResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse domains.getList response")
+	}
+	return &result, nil
+}
+
+// GetExpiringDomains returns the account's unexpired domains that expire
+// within the given number of days, soonest first. It lists only the domains
+// Namecheap reports as expiring, so the rest of the account is never paged
+// through.
+func (c *Client) GetExpiringDomains(ctx context.Context, withinDays int) ([]Domain, error) {
+	if withinDays < 0 {
+		return nil, errors.New("withinDays must not be negative")
+	}
+	cutoff := time.Now().AddDate(0, 0, withinDays)
+
+	domains, err := c.GetDomainsWithOptions(ctx, DomainListOptions{
+		ListType: DomainListExpiring,
+		SortBy:   DomainSortExpireDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var expiring []Domain
+	for _, domain := range domains {
+		if domain.Expires.After(cutoff) {
+			break
+		}
+		if !domain.IsExpired {
+			expiring = append(expiring, domain)
+		}
+	}
+	return expiring, nil
+}
+
+// GetDomainCount returns the number of domains in the account. It requests
+// the smallest page domains.getList accepts and reads the total from its
+// paging information.
+func (c *Client) GetDomainCount(ctx context.Context) (int, error) {
+	result, err := c.listDomains(ctx, newParams().
+		set("ListType", DomainListAll).
+		setInt("Page", 1).
+		setInt("PageSize", domainListMinPageSize))
+	if err != nil {
+		return 0, err
+	}
+	return result.CommandResponse.Paging.TotalItems, nil
+}
+
+// GetDomain retrieves detailed information about a specific domain
+func (c *Client) GetDomain(ctx context.Context, domainName string) (*Domain, error) {
+	info, err := c.GetDomainInfo(ctx, domainName)
+	if err != nil {
+		return nil, err
+	}
+	return &info.Domain, nil
+}
+
+// GetDomainInfo retrieves detailed information about a specific domain,
+// including the DNS it is delegated to
+func (c *Client) GetDomainInfo(ctx context.Context, domainName string) (*DomainInfo, error) {
+	resp, err := c.makeRequest(ctx, CommandDomainsGetInfo, newParams().
+		setDomainName(domainName))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make domains.getInfo request")
+	}
+
+	var result DomainInfoResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse domains.getInfo response")
+	}
+
+	info := result.CommandResponse.DomainGetInfoResult
+	dns := info.DnsDetails
+	dns.Nameservers = NormalizeNameservers(dns.Nameservers)
+
+	domain := Domain{
+		ID:                 info.ID,
+		Name:               info.DomainName,
+		User:               info.OwnerName,
+		Created:            info.DomainDetails.CreatedDate,
+		Expires:            info.DomainDetails.ExpiredDate,
+		IsExpired:          strings.EqualFold(info.Status, DomainStatusExpired),
+		IsPremium:          info.IsPremium,
+		IsOurDNS:           dns.IsUsingOurDNS,
+		TransferOutPending: info.LockDetails.TransferOutPending,
+		Nameservers:        dns.Nameservers,
+	}
+	for _, status := range info.DomainStatuses {
+		if status = strings.TrimSpace(status); status != "" {
+			domain.Statuses = append(domain.Statuses, status)
+		}
+	}
+	return &DomainInfo{
+		Domain:                domain,
+		DNS:                   dns,
+		WhoisGuardForwardedTo: info.Whoisguard.ForwardedTo,
+	}, nil
+}
+
+// GetRegistrarLock reports whether the registrar lock is enabled for a domain
+func (c *Client) GetRegistrarLock(ctx context.Context, domainName string) (bool, error) {
+	resp, err := c.makeRequest(ctx, CommandDomainsGetRegistrarLock, newParams().
+		setDomainName(domainName))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to make domains.getRegistrarLock request")
+	}
+
+	var result RegistrarLockResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return false, errors.Wrap(err, "failed to parse domains.getRegistrarLock response")
+	}
+
+	return result.CommandResponse.DomainGetRegistrarLockResult.RegistrarLockStatus, nil
+}
+
+// SetRegistrarLock enables or disables the registrar lock for a domain
+func (c *Client) SetRegistrarLock(ctx context.Context, domainName string, locked bool) error {
+	action := "UNLOCK"
+	if locked {
+		action = "LOCK"
+	}
+
+	resp, err := c.makeRequest(ctx, CommandDomainsSetRegistrarLock, newParams().
+		setDomainName(domainName).
+		set("LockAction", action))
+	if err != nil {
+		return errors.Wrap(err, "failed to make domains.setRegistrarLock request")
+	}
+
+	var result RegistrarLockSetResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse domains.setRegistrarLock response")
+	}
+
+	if !result.CommandResponse.DomainSetRegistrarLockResult.IsSuccess {
+		return errors.New("failed to update registrar lock")
+	}
+	return nil
+}
+
+// RegistryStatuses returns the registry-imposed EPP status codes, such as
+// serverTransferProhibited, from a domain's status codes. Registry statuses
+// are set by the registry rather than the registrar and cannot be changed
+// through the Namecheap API.
+func RegistryStatuses(statuses []string) []string {
+	var out []string
+	for _, status := range statuses {
+		if strings.HasPrefix(strings.ToLower(status), "server") {
+			out = append(out, status)
+		}
+	}
+	return out
+}
+
+// DomainRegistration is the outcome of registering a domain
+type DomainRegistration struct {
+	DomainName    string
+	Registered    bool
+	ChargedAmount float64
+	DomainID      int
+	OrderID       int
+	TransactionID int
+
+	// Domain holds the registered domain's details, or nil if they could
+	// not be read back after registration
+	Domain *Domain
+}
+
+// DomainCreateOptions are the optional parameters of a registration
+type DomainCreateOptions struct {
+	// IdnCode is the language code of an internationalized domain name,
+	// such as "ger". It is required for such names and ignored for others.
+	IdnCode string
+
+	// AddFreeWhoisguard adds a free WhoisGuard subscription to the domain
+	AddFreeWhoisguard bool
+
+	// EnableWhoisguard enables WhoisGuard as soon as the domain is
+	// registered
+	EnableWhoisguard bool
+}
+
+// CreateDomain registers a new domain with the given contacts, which are
+// validated before any API call. An internationalized domain name may be
+// given in its Unicode or punycode form, and must come with the IdnCode of
+// its language in opts. If the domain is registered but its details cannot
+// be read back, the registration is returned along with the error, so the
+// caller still learns the order was placed. A client that checks balances
+// returns ErrInsufficientFunds rather than order a registration the account
+// can't pay for.
+func (c *Client) CreateDomain(ctx context.Context, domainName string, years int, contacts DomainContacts, opts DomainCreateOptions) (*DomainRegistration, error) {
+	if err := contacts.Validate(); err != nil {
+		return nil, err
+	}
+
+	idnCode := opts.IdnCode
+	if !IsIDN(domainName) {
+		idnCode = ""
+	} else if idnCode == "" {
+		return nil, errors.Errorf("an IdnCode is required to register the internationalized domain %s", domainName)
+	}
+
+	if _, tld, err := SplitDomain(domainName); err == nil {
+		if err := c.checkFunds(ctx, productTypeDomain, actionRegister, tldASCII(tld), years); err != nil {
+			return nil, err
+		}
+	}
+
+	params := newParams().
+		setDomainName(domainName).
+		setInt("Years", years).
+		setOptional("IdnCode", idnCode).
+		set("AddFreeWhoisguard", yesNo(opts.AddFreeWhoisguard)).
+		set("WGEnabled", yesNo(opts.EnableWhoisguard))
+	contacts.addParams(params)
+
+	resp, err := c.makeRequest(ctx, CommandDomainsCreate, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make domains.create request")
+	}
+
+	var result DomainCreateResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse domains.create response")
+	}
+
+	created := result.CommandResponse.DomainCreateResult
+	if !created.Registered {
+		return nil, errors.New("domain registration failed")
+	}
+
+	registration := &DomainRegistration{
+		DomainName:    created.Domain,
+		Registered:    created.Registered,
+		ChargedAmount: created.ChargedAmount,
+		DomainID:      created.DomainID,
+		OrderID:       created.OrderID,
+		TransactionID: created.TransactionID,
+	}
+
+	// After registration, get the domain details
+	domain, err := c.GetDomain(ctx, domainName)
+	if err != nil {
+		return registration, errors.Wrap(err, "domain registered but cannot read its details")
+	}
+	registration.Domain = domain
+	return registration, nil
+}
+
+// SetNameservers sets custom nameservers for a domain. Nameservers are
+// normalized and validated before any API call.
+func (c *Client) SetNameservers(ctx context.Context, domainName string, nameservers []string) error {
+	nameservers = NormalizeNameservers(nameservers)
+	if len(nameservers) == 0 {
+		return errors.New("at least one nameserver must be provided")
+	}
+	if err := ValidateNameservers(nameservers); err != nil {
+		return err
+	}
+
+	resp, err := c.makeRequest(ctx, CommandDomainsDNSSetCustom, newParams().
+		setDomain(domainName).
+		set("Nameservers", strings.Join(nameservers, ",")))
+	if err != nil {
+		return errors.Wrap(err, "failed to make domains.dns.setCustom request")
+	}
+
+	var result DNSSetCustomResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse domains.dns.setCustom response")
+	}
+
+	if setCustom := result.CommandResponse.DomainDNSSetCustomResult; !setCustom.Updated && !setCustom.Update {
+		return errors.New("failed to update nameservers")
+	}
+
+	return nil
+}
+
+// DNSSetDefaultResponse represents the response from domains.dns.setDefault
+type DNSSetDefaultResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainDNSSetDefaultResult struct {
+			Domain  string `xml:"Domain,attr"`
+			Updated bool   `xml:"Updated,attr"`
+		} `xml:"DomainDNSSetDefaultResult"`
+	} `xml:"CommandResponse"`
+}
+
+// SetDefaultNameservers points a domain back at Namecheap's own nameservers,
+// re-enabling its hosted DNS zone
+func (c *Client) SetDefaultNameservers(ctx context.Context, domainName string) error {
+	resp, err := c.makeRequest(ctx, CommandDomainsDNSSetDefault, newParams().setDomain(domainName))
+	if err != nil {
+		return errors.Wrap(err, "failed to make domains.dns.setDefault request")
+	}
+
+	var result DNSSetDefaultResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse domains.dns.setDefault response")
+	}
+
+	if !result.CommandResponse.DomainDNSSetDefaultResult.Updated {
+		return errors.New("failed to reset nameservers to defaults")
+	}
+
+	return nil
+}
+
+// NormalizeNameservers trims whitespace and any trailing dot from each
+// nameserver, lowercases it, and drops empty entries
+func NormalizeNameservers(nameservers []string) []string {
+	var out []string
+	for _, ns := range nameservers {
+		ns = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(ns)), ".")
+		if ns != "" {
+			out = append(out, ns)
+		}
+	}
+	return out
+}
+
+// ValidateNameservers checks that every nameserver is a fully qualified
+// domain name. Nameservers should be normalized first.
+func ValidateNameservers(nameservers []string) error {
+	for _, ns := range nameservers {
+		if !isFQDN(ns) {
+			return errors.Errorf("nameserver %q is not a fully qualified domain name", ns)
+		}
+	}
+	return nil
+}
+
+// isFQDN reports whether name is a fully qualified domain name of at least
+// two labels, each made up of letters, digits and inner hyphens
+func isFQDN(name string) bool {
+	if len(name) > 253 {
+		return false
+	}
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// NameserversEqual reports whether two nameserver lists hold the same
+// nameservers once normalized, regardless of order
+func NameserversEqual(a, b []string) bool {
+	a, b = NormalizeNameservers(a), NormalizeNameservers(b)
+	if len(a) != len(b) {
+		return false
+	}
+	counts := map[string]int{}
+	for _, ns := range a {
+		counts[ns]++
+	}
+	for _, ns := range b {
+		if counts[ns] == 0 {
+			return false
+		}
+		counts[ns]--
+	}
+	return true
+}
+
+// IsNamecheapNameserver reports whether a nameserver is one of Namecheap's
+// own, such as dns1.registrar-servers.com. Setting these as custom
+// nameservers disables the domain's hosted DNS zone.
+func IsNamecheapNameserver(nameserver string) bool {
+	ns := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(nameserver)), ".")
+	return strings.HasSuffix(ns, ".registrar-servers.com")
+}
+
+// DomainRenewResponse represents the response from domains.renew
+type DomainRenewResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainRenewResult struct {
+			DomainName    string  `xml:"DomainName,attr"`
+			DomainID      int     `xml:"DomainID,attr"`
+			Renew         bool    `xml:"Renew,attr"`
+			ChargedAmount float64 `xml:"ChargedAmount,attr"`
+			TransactionID int     `xml:"TransactionID,attr"`
+			OrderID       int     `xml:"OrderID,attr"`
+		} `xml:"DomainRenewResult"`
+	} `xml:"CommandResponse"`
+}
+
+// DomainReactivateResponse represents the response from domains.reactivate
+type DomainReactivateResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainReactivateResult struct {
+			Domain        string  `xml:"Domain,attr"`
+			IsSuccess     bool    `xml:"IsSuccess,attr"`
+			ChargedAmount float64 `xml:"ChargedAmount,attr"`
+			OrderID       int     `xml:"OrderID,attr"`
+			TransactionID int     `xml:"TransactionID,attr"`
+		} `xml:"DomainReactivateResult"`
+	} `xml:"CommandResponse"`
+}
+
+// DomainCheckResponse represents the response from domains.check
+type DomainCheckResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainCheckResults []struct {
+			Domain                   string  `xml:"Domain,attr"`
+			Available                bool    `xml:"Available,attr"`
+			ErrorNo                  string  `xml:"ErrorNo,attr"`
+			Description              string  `xml:"Description,attr"`
+			IsPremiumName            bool    `xml:"IsPremiumName,attr"`
+			PremiumRegistrationPrice float64 `xml:"PremiumRegistrationPrice,attr"`
+			PremiumRenewalPrice      float64 `xml:"PremiumRenewalPrice,attr"`
+			PremiumRestorePrice      float64 `xml:"PremiumRestorePrice,attr"`
+			PremiumTransferPrice     float64 `xml:"PremiumTransferPrice,attr"`
+			IcannFee                 float64 `xml:"IcannFee,attr"`
+			EapFee                   float64 `xml:"EapFee,attr"`
+		} `xml:"DomainCheckResult"`
+	} `xml:"CommandResponse"`
+}
+
+// DomainCheckResult represents a single domain availability check result
+type DomainCheckResult struct {
+	Domain                   string
+	Available                bool
+	ErrorCode                string
+	Description              string
+	IsPremium                bool
+	PremiumRegistrationPrice float64
+	PremiumRenewalPrice      float64
+	PremiumRestorePrice      float64
+	PremiumTransferPrice     float64
+	IcannFee                 float64
+	EapFee                   float64
+}
+
+// DomainRenewal is the outcome of renewing a domain
+type DomainRenewal struct {
+	DomainName    string
+	Renewed       bool
+	ChargedAmount float64
+	DomainID      int
+	OrderID       int
+	TransactionID int
+
+	// Domain holds the renewed domain's details, or nil if they could not be
+	// read back after renewal
+	Domain *Domain
+}
+
+// RenewDomain renews a domain for specified number of years. If the domain
+// is renewed but its details cannot be read back, the renewal is returned
+// along with the error, so the caller still learns the order was placed.
+// A client that checks balances returns ErrInsufficientFunds rather than
+// order a renewal the account can't pay for.
+func (c *Client) RenewDomain(ctx context.Context, domainName string, years int) (*DomainRenewal, error) {
+	if _, tld, err := SplitDomain(domainName); err == nil {
+		if err := c.checkFunds(ctx, productTypeDomain, actionRenew, tldASCII(tld), years); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.makeRequest(ctx, CommandDomainsRenew, newParams().
+		setDomainName(domainName).
+		setInt("Years", years))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make domains.renew request")
+	}
+
+	var result DomainRenewResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse domains.renew response")
+	}
+
+	renewed := result.CommandResponse.DomainRenewResult
+	if !renewed.Renew {
+		return nil, errors.New("domain renewal failed")
+	}
+
+	renewal := &DomainRenewal{
+		DomainName:    renewed.DomainName,
+		Renewed:       renewed.Renew,
+		ChargedAmount: renewed.ChargedAmount,
+		DomainID:      renewed.DomainID,
+		OrderID:       renewed.OrderID,
+		TransactionID: renewed.TransactionID,
+	}
+
+	// After renewal, get the updated domain details
+	domain, err := c.GetDomain(ctx, domainName)
+	if err != nil {
+		return renewal, errors.Wrap(err, "domain renewed but cannot read its details")
+	}
+	renewal.Domain = domain
+	return renewal, nil
+}
+
+// DomainReactivation is the outcome of reactivating an expired domain
+type DomainReactivation struct {
+	DomainName    string
+	ChargedAmount float64
+	OrderID       int
+	TransactionID int
+}
+
+// errNotReactivated is returned when domains.reactivate reports failure
+// without an API error
+var errNotReactivated = errors.New("domain reactivation failed")
+
+// IsReactivationRefused reports whether err is Namecheap refusing to
+// reactivate a domain, for example because it is past its redemption
+// period, rather than a failure to reach Namecheap or another API error.
+// Retrying a refused reactivation without changing it won't succeed.
+func IsReactivationRefused(err error) bool {
+	if errors.Is(err, errNotReactivated) {
+		return true
+	}
+	var ncErr Error
+	if !errors.As(err, &ncErr) {
+		return false
+	}
+	switch ncErr.Number {
+	case "2019166", // Domain not found
+		"2016166", // Domain is not associated with your account
+		"2030166", // Edit permission for domain is not supported
+		"2020166", // Domain does not meet the expire date for reactivation
+		"4023166": // Error occurred while reactivating domain
+		return true
+	}
+	return false
+}
+
+// ReactivateDomain reactivates a domain that has expired but is still in
+// its grace or redemption period. promoCode is optional.
+func (c *Client) ReactivateDomain(ctx context.Context, domainName, promoCode string) (*DomainReactivation, error) {
+	resp, err := c.makeRequest(ctx, CommandDomainsReactivate, newParams().
+		setDomainName(domainName).
+		setOptional("PromotionCode", promoCode))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make domains.reactivate request")
+	}
+
+	var result DomainReactivateResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse domains.reactivate response")
+	}
+
+	reactivated := result.CommandResponse.DomainReactivateResult
+	if !reactivated.IsSuccess {
+		return nil, errNotReactivated
+	}
+
+	return &DomainReactivation{
+		DomainName:    reactivated.Domain,
+		ChargedAmount: reactivated.ChargedAmount,
+		OrderID:       reactivated.OrderID,
+		TransactionID: reactivated.TransactionID,
+	}, nil
+}
+
+// domainCheckMaxBatch is the most domains domains.check accepts in one
+// DomainList
+const domainCheckMaxBatch = 50
+
+// CheckDomainAvailability checks if domains are available for registration.
+// Lists longer than domains.check accepts are checked in batches, one after
+// another, and the results are returned in the order of domainNames. If some
+// batches fail, the results of the others are returned along with an error
+// joining each failure. The failed batches' domains have no results then, so
+// results must be matched to domainNames by their Domain, not by position.
+func (c *Client) CheckDomainAvailability(ctx context.Context, domainNames []string) ([]DomainCheckResult, error) {
+	if len(domainNames) == 0 {
+		return nil, errors.New("at least one domain name must be provided")
+	}
+
+	var checkResults []DomainCheckResult
+	var errs []error
+	for start := 0; start < len(domainNames); start += domainCheckMaxBatch {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, errors.Wrap(err, "domains.check cancelled"))
+			break
+		}
+
+		end := min(start+domainCheckMaxBatch, len(domainNames))
+		results, err := c.checkDomains(ctx, domainNames[start:end])
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to check domains %d to %d", start+1, end))
+			continue
+		}
+		checkResults = append(checkResults, results...)
+	}
+
+	return checkResults, stderrors.Join(errs...)
+}
+
+// checkDomains requests domains.check for a batch of domains
+func (c *Client) checkDomains(ctx context.Context, domainNames []string) ([]DomainCheckResult, error) {
+	resp, err := c.makeRequest(ctx, CommandDomainsCheck, newParams().
+		set("DomainList", strings.Join(domainNames, ",")))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make domains.check request")
+	}
+
+	var result DomainCheckResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse domains.check response")
+	}
+
+	// Convert API response to our result type
+	checkResults := make([]DomainCheckResult, len(result.CommandResponse.DomainCheckResults))
+	for i, domain := range result.CommandResponse.DomainCheckResults {
+		checkResults[i] = DomainCheckResult{
+			Domain:                   domain.Domain,
+			Available:                domain.Available,
+			ErrorCode:                domain.ErrorNo,
+			Description:              domain.Description,
+			IsPremium:                domain.IsPremiumName,
+			PremiumRegistrationPrice: domain.PremiumRegistrationPrice,
+			PremiumRenewalPrice:      domain.PremiumRenewalPrice,
+			PremiumRestorePrice:      domain.PremiumRestorePrice,
+			PremiumTransferPrice:     domain.PremiumTransferPrice,
+			IcannFee:                 domain.IcannFee,
+			EapFee:                   domain.EapFee,
+		}
+	}
+
+	return checkResults, nil
+}
+
+// DomainExists checks if a domain exists in the account. Only the error
+// numbers domains.getInfo answers a domain outside the account with mean it
+// doesn't; any other error, such as an authentication failure, is returned,
+// as treating it as a missing domain would have it registered again.
+func (c *Client) DomainExists(ctx context.Context, domainName string) (bool, error) {
+	_, err := c.GetDomain(ctx, domainName)
+	switch {
+	case err == nil:
+		return true, nil
+	case isDomainNotFound(err):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// isDomainNotFound reports whether err is domains.getInfo stating that the
+// domain isn't in the account, by its error number
+func isDomainNotFound(err error) bool {
+	if IsDomainNotInAccount(err) {
+		return true
+	}
+	var ncErr Error
+	return errors.As(err, &ncErr) && ncErr.Number == "2030166" // Edit permission for domain is not supported
+}
@@ -0,0 +1,1420 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_RenewDomain(t *testing.T) {
+	tests := []struct {
+		name          string
+		domainName    string
+		years         int
+		renewXML      string
+		getInfoXML    string
+		expectedError string
+		expectRenewal bool
+		expectSuccess bool
+	}{
+		{
+			name:       "successful domain renewal",
+			domainName: "example.com",
+			years:      2,
+			renewXML: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainRenewResult DomainName="example.com" DomainID="123" Renew="true" ChargedAmount="18.50" TransactionID="456" OrderID="789"/>
+	</CommandResponse>
+</ApiResponse>`,
+			getInfoXML: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainGetInfoResult Status="Ok" ID="123" DomainName="example.com" OwnerName="testuser" IsOwner="true" IsPremium="false">
+			<DomainDetails>
+				<CreatedDate>01/01/2024</CreatedDate>
+				<ExpiredDate>01/01/2026</ExpiredDate>
+			</DomainDetails>
+			<DnsDetails ProviderType="FREE" IsUsingOurDNS="true"/>
+		</DomainGetInfoResult>
+	</CommandResponse>
+</ApiResponse>`,
+			expectRenewal: true,
+			expectSuccess: true,
+		},
+		{
+			name:       "renewed but details unavailable",
+			domainName: "example.com",
+			years:      2,
+			renewXML: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainRenewResult DomainName="example.com" DomainID="123" Renew="true" ChargedAmount="18.50" TransactionID="456" OrderID="789"/>
+	</CommandResponse>
+</ApiResponse>`,
+			getInfoXML: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="2019166">Domain not found</Error>
+	</Errors>
+</ApiResponse>`,
+			expectedError: "domain renewed but cannot read its details",
+			expectRenewal: true,
+		},
+		{
+			name:       "failed domain renewal",
+			domainName: "example.com",
+			years:      1,
+			renewXML: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainRenewResult DomainName="example.com" DomainID="123" Renew="false"/>
+	</CommandResponse>
+</ApiResponse>`,
+			expectedError: "domain renewal failed",
+		},
+		{
+			name:       "API error response",
+			domainName: "example.com",
+			years:      1,
+			renewXML: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="2030166">Domain not found</Error>
+	</Errors>
+</ApiResponse>`,
+			expectedError: "Namecheap API Error 2030166: Domain not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callCount := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				callCount++
+				if callCount == 1 {
+					// First call - domain renewal
+					assert.Equal(t, "POST", r.Method)
+					assert.Equal(t, "namecheap.domains.renew", r.FormValue("Command"))
+					assert.Equal(t, tt.domainName, r.FormValue("DomainName"))
+					assert.Equal(t, strconv.Itoa(tt.years), r.FormValue("Years"))
+
+					w.Header().Set("Content-Type", "application/xml")
+					w.WriteHeader(http.StatusOK)
+					_, err := w.Write([]byte(tt.renewXML))
+					require.NoError(t, err)
+				} else if callCount == 2 && tt.getInfoXML != "" {
+					// Second call - get domain info (only for successful renewals)
+					assert.Equal(t, "GET", r.Method)
+					assert.Equal(t, "namecheap.domains.getInfo", r.FormValue("Command"))
+					assert.Equal(t, tt.domainName, r.FormValue("DomainName"))
+
+					w.Header().Set("Content-Type", "application/xml")
+					w.WriteHeader(http.StatusOK)
+					_, err := w.Write([]byte(tt.getInfoXML))
+					require.NoError(t, err)
+				}
+			}))
+			defer server.Close()
+
+			config := Config{
+				APIUser:  "testuser",
+				APIKey:   "testkey",
+				Username: "testuser",
+				ClientIP: "127.0.0.1",
+				BaseURL:  server.URL,
+				HTTPClient: &http.Client{
+					Timeout: 5 * time.Second,
+				},
+			}
+			client := NewClient(config)
+
+			renewal, err := client.RenewDomain(context.Background(), tt.domainName, tt.years)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			}
+			if !tt.expectRenewal {
+				assert.Nil(t, renewal)
+				return
+			}
+
+			// The renewal is reported even if its details can't be read back
+			require.NotNil(t, renewal)
+			assert.True(t, renewal.Renewed)
+			assert.Equal(t, 123, renewal.DomainID)
+			assert.Equal(t, 789, renewal.OrderID)
+			assert.Equal(t, 456, renewal.TransactionID)
+			assert.Equal(t, 18.50, renewal.ChargedAmount)
+			assert.Equal(t, 2, callCount) // Verify both API calls were made
+
+			if tt.expectSuccess {
+				assert.NoError(t, err)
+				require.NotNil(t, renewal.Domain)
+				assert.Equal(t, tt.domainName, renewal.Domain.Name)
+				assert.Equal(t, 123, renewal.Domain.ID)
+			} else {
+				assert.Nil(t, renewal.Domain)
+			}
+		})
+	}
+}
+
+func TestClient_CheckDomainAvailability(t *testing.T) {
+	tests := []struct {
+		name          string
+		domainNames   []string
+		responseXML   string
+		expectedCount int
+		expectedError string
+	}{
+		{
+			name:        "single domain available",
+			domainNames: []string{"example.com"},
+			responseXML: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainCheckResult Domain="example.com" Available="true" ErrorNo="0" Description="" IsPremiumName="false"/>
+	</CommandResponse>
+</ApiResponse>`,
+			expectedCount: 1,
+		},
+		{
+			name:        "multiple domains mixed availability",
+			domainNames: []string{"example.com", "google.com", "newdomain.net"},
+			responseXML: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainCheckResult Domain="example.com" Available="false" ErrorNo="0" Description="Domain taken"/>
+		<DomainCheckResult Domain="google.com" Available="false" ErrorNo="0" Description="Domain taken"/>
+		<DomainCheckResult Domain="newdomain.net" Available="true" ErrorNo="0" Description="" IsPremiumName="false"/>
+	</CommandResponse>
+</ApiResponse>`,
+			expectedCount: 3,
+		},
+		{
+			name:          "empty domain list",
+			domainNames:   []string{},
+			expectedError: "at least one domain name must be provided",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.expectedError != "" && len(tt.domainNames) == 0 {
+				// Test error case without server
+				client := &Client{}
+				results, err := client.CheckDomainAvailability(context.Background(), tt.domainNames)
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.Nil(t, results)
+				return
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "GET", r.Method)
+				assert.Equal(t, "namecheap.domains.check", r.FormValue("Command"))
+				assert.Equal(t, strings.Join(tt.domainNames, ","), r.FormValue("DomainList"))
+
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(tt.responseXML))
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			config := Config{
+				APIUser:  "testuser",
+				APIKey:   "testkey",
+				Username: "testuser",
+				ClientIP: "127.0.0.1",
+				BaseURL:  server.URL,
+				HTTPClient: &http.Client{
+					Timeout: 5 * time.Second,
+				},
+			}
+			client := NewClient(config)
+
+			results, err := client.CheckDomainAvailability(context.Background(), tt.domainNames)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, results, tt.expectedCount)
+
+				if len(results) > 0 {
+					assert.Equal(t, tt.domainNames[0], results[0].Domain)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_CheckDomainAvailability_LongQuery(t *testing.T) {
+	var domainNames []string
+	for i := 0; i < 40; i++ {
+		domainNames = append(domainNames, fmt.Sprintf("%s%d.com", strings.Repeat("a", 60), i))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// domains.check is a GET command, but this query is too long for a URL
+		assert.Equal(t, "POST", r.Method)
+		assert.Empty(t, r.URL.RawQuery)
+		assert.Equal(t, strings.Join(domainNames, ","), r.FormValue("DomainList"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse></CommandResponse>
+</ApiResponse>`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	_, err := client.CheckDomainAvailability(context.Background(), domainNames)
+	assert.NoError(t, err)
+}
+
+func TestClient_CheckDomainAvailability_Batches(t *testing.T) {
+	var domainNames []string
+	for i := 0; i < 120; i++ {
+		domainNames = append(domainNames, fmt.Sprintf("domain%d.com", i))
+	}
+
+	tests := []struct {
+		name      string
+		failBatch int
+		wantCount int
+		wantErr   string
+	}{
+		{name: "all batches succeed", wantCount: 120},
+		{name: "second batch fails", failBatch: 2, wantCount: 70, wantErr: "failed to check domains 51 to 100"},
+		// The results then start with the second batch's first domain
+		{name: "first batch fails", failBatch: 1, wantCount: 70, wantErr: "failed to check domains 1 to 50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var batches [][]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, string(CommandDomainsCheck), r.FormValue("Command"))
+				batch := strings.Split(r.FormValue("DomainList"), ",")
+				batches = append(batches, batch)
+
+				w.Header().Set("Content-Type", "application/xml")
+				if len(batches) == tt.failBatch {
+					_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="3031510">Error response from provider</Error>
+	</Errors>
+</ApiResponse>`))
+					require.NoError(t, err)
+					return
+				}
+
+				var results strings.Builder
+				for _, domain := range batch {
+					fmt.Fprintf(&results, `<DomainCheckResult Domain="%s" Available="true"/>`, domain)
+				}
+				_, err := fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		%s
+	</CommandResponse>
+</ApiResponse>`, results.String())
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			client := NewClient(Config{
+				APIUser:    "testuser",
+				APIKey:     "testkey",
+				Username:   "testuser",
+				ClientIP:   "127.0.0.1",
+				BaseURL:    server.URL,
+				HTTPClient: &http.Client{Timeout: 5 * time.Second},
+			})
+
+			results, err := client.CheckDomainAvailability(context.Background(), domainNames)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Len(t, batches, 3)
+			assert.Equal(t, domainNames[:50], batches[0])
+			assert.Equal(t, domainNames[50:100], batches[1])
+			assert.Equal(t, domainNames[100:], batches[2])
+
+			require.Len(t, results, tt.wantCount)
+			var want []string
+			for i, domain := range domainNames {
+				if tt.failBatch == 0 || i/50 != tt.failBatch-1 {
+					want = append(want, domain)
+				}
+			}
+			var got []string
+			for _, result := range results {
+				got = append(got, result.Domain)
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestClient_GetDomains(t *testing.T) {
+	responseXML := `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainGetListResult>
+			<Domain ID="123" Name="example.com" User="testuser" Created="01/01/2024" Expires="01/01/2025" IsExpired="false" IsLocked="false" AutoRenew="false" WhoisGuard="ENABLED" IsPremium="false" IsOurDNS="true"/>
+			<Domain ID="124" Name="test.com" User="testuser" Created="01/01/2024" Expires="01/01/2025" IsExpired="false" IsLocked="false" AutoRenew="true" WhoisGuard="DISABLED" IsPremium="false" IsOurDNS="false"/>
+		</DomainGetListResult>
+	</CommandResponse>
+</ApiResponse>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "namecheap.domains.getList", r.FormValue("Command"))
+		assert.Equal(t, "100", r.FormValue("PageSize"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(responseXML))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	config := Config{
+		APIUser:  "testuser",
+		APIKey:   "testkey",
+		Username: "testuser",
+		ClientIP: "127.0.0.1",
+		BaseURL:  server.URL,
+		HTTPClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+	client := NewClient(config)
+
+	domains, err := client.GetDomains(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, domains, 2)
+	assert.Equal(t, "example.com", domains[0].Name)
+	assert.Equal(t, "test.com", domains[1].Name)
+	assert.Equal(t, 123, domains[0].ID)
+	assert.Equal(t, 124, domains[1].ID)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), domains[0].Created.Time)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), domains[0].Expires.Time)
+}
+
+func TestClient_CreateDomain(t *testing.T) {
+	responseXML := `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainCreateResult Domain="newdomain.com" Registered="true" ChargedAmount="12.50" DomainID="125" OrderID="456" TransactionID="789" WhoisguardEnable="false" NonRealTimeDomain="false"/>
+	</CommandResponse>
+</ApiResponse>`
+
+	getInfoXML := `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainGetInfoResult Status="Ok" ID="125" DomainName="newdomain.com" OwnerName="testuser" IsOwner="true" IsPremium="false">
+			<DomainDetails>
+				<CreatedDate>01/01/2024</CreatedDate>
+				<ExpiredDate>01/01/2025</ExpiredDate>
+			</DomainDetails>
+			<DnsDetails ProviderType="FREE" IsUsingOurDNS="true"/>
+		</DomainGetInfoResult>
+	</CommandResponse>
+</ApiResponse>`
+
+	callCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		if callCount == 1 {
+			// First call - domain creation
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "namecheap.domains.create", r.FormValue("Command"))
+			assert.Equal(t, "newdomain.com", r.FormValue("DomainName"))
+			assert.Equal(t, "2", r.FormValue("Years"))
+			assert.Equal(t, "John", r.FormValue("RegistrantFirstName"))
+			assert.Equal(t, "john@example.com", r.FormValue("AuxBillingEmailAddress"))
+
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(responseXML))
+			require.NoError(t, err)
+		} else {
+			// Second call - get domain info
+			assert.Equal(t, "GET", r.Method)
+			assert.Equal(t, "namecheap.domains.getInfo", r.FormValue("Command"))
+			assert.Equal(t, "newdomain.com", r.FormValue("DomainName"))
+
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(getInfoXML))
+			require.NoError(t, err)
+		}
+	}))
+	defer server.Close()
+
+	config := Config{
+		APIUser:  "testuser",
+		APIKey:   "testkey",
+		Username: "testuser",
+		ClientIP: "127.0.0.1",
+		BaseURL:  server.URL,
+		HTTPClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+	client := NewClient(config)
+
+	registration, err := client.CreateDomain(context.Background(), "newdomain.com", 2, testContacts(), DomainCreateOptions{})
+
+	assert.NoError(t, err)
+	require.NotNil(t, registration)
+	assert.True(t, registration.Registered)
+	assert.Equal(t, 125, registration.DomainID)
+	assert.Equal(t, 456, registration.OrderID)
+	assert.Equal(t, 789, registration.TransactionID)
+	assert.Equal(t, 12.50, registration.ChargedAmount)
+	require.NotNil(t, registration.Domain)
+	assert.Equal(t, "newdomain.com", registration.Domain.Name)
+	assert.Equal(t, 125, registration.Domain.ID)
+	assert.Equal(t, 2, callCount) // Verify both API calls were made
+}
+
+func TestClient_CreateDomain_NoContacts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s request", r.FormValue("Command"))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	// Contacts are validated before the domain is ordered
+	registration, err := client.CreateDomain(context.Background(), "newdomain.com", 1, DomainContacts{}, DomainCreateOptions{})
+	assert.ErrorContains(t, err, "contacts are required")
+	assert.Nil(t, registration)
+}
+
+func TestClient_CreateDomain_IDN(t *testing.T) {
+	var created url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		command := Command(r.FormValue("Command"))
+		if command == CommandDomainsCreate {
+			created = r.Form
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write(commandFixture(t, command))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	// An internationalized name needs the IdnCode of its language
+	_, err := client.CreateDomain(context.Background(), "bücher.example", 1, testContacts(), DomainCreateOptions{})
+	assert.ErrorContains(t, err, "an IdnCode is required")
+	assert.Nil(t, created)
+
+	// and is registered in punycode along with it
+	_, err = client.CreateDomain(context.Background(), "bücher.example", 1, testContacts(), DomainCreateOptions{IdnCode: "ger"})
+	require.NoError(t, err)
+	assert.Equal(t, "xn--bcher-kva.example", created.Get("DomainName"))
+	assert.Equal(t, "ger", created.Get("IdnCode"))
+
+	// IdnCode is left out for other names
+	_, err = client.CreateDomain(context.Background(), "example.com", 1, testContacts(), DomainCreateOptions{IdnCode: "ger"})
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", created.Get("DomainName"))
+	assert.NotContains(t, created, "IdnCode")
+}
+
+func TestClient_CreateDomain_Whoisguard(t *testing.T) {
+	var created url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		command := Command(r.FormValue("Command"))
+		if command == CommandDomainsCreate {
+			created = r.Form
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write(commandFixture(t, command))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	_, err := client.CreateDomain(context.Background(), "example.com", 1, testContacts(), DomainCreateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "no", created.Get("AddFreeWhoisguard"))
+	assert.Equal(t, "no", created.Get("WGEnabled"))
+
+	_, err = client.CreateDomain(context.Background(), "example.com", 1, testContacts(), DomainCreateOptions{
+		AddFreeWhoisguard: true,
+		EnableWhoisguard:  true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "yes", created.Get("AddFreeWhoisguard"))
+	assert.Equal(t, "yes", created.Get("WGEnabled"))
+}
+
+func TestClient_CreateDomain_DetailsUnavailable(t *testing.T) {
+	client := newFixtureClient(t, map[Command]string{
+		CommandDomainsGetInfo: "error.domainNotFound",
+	})
+
+	registration, err := client.CreateDomain(context.Background(), "example.com", 1, testContacts(), DomainCreateOptions{})
+
+	// The registration is reported even though its details can't be read
+	// back, so the caller knows the order was placed
+	assert.ErrorContains(t, err, "domain registered but cannot read its details")
+	require.NotNil(t, registration)
+	assert.True(t, registration.Registered)
+	assert.Equal(t, 9007, registration.DomainID)
+	assert.Equal(t, 196074, registration.OrderID)
+	assert.Equal(t, 380716, registration.TransactionID)
+	assert.Nil(t, registration.Domain)
+}
+func TestClient_GetDomain_TransferOutPending(t *testing.T) {
+	tests := []struct {
+		name        string
+		lockDetails string
+		expected    bool
+	}{
+		{
+			name:        "transfer-out pending",
+			lockDetails: `<LockDetails TransferOutPending="true"/>`,
+			expected:    true,
+		},
+		{
+			name:        "no transfer-out",
+			lockDetails: `<LockDetails TransferOutPending="false"/>`,
+			expected:    false,
+		},
+		{
+			name:        "no lock details",
+			lockDetails: ``,
+			expected:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responseXML := `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainGetInfoResult Status="Ok" ID="125" DomainName="example.com" OwnerName="testuser" IsOwner="true" IsPremium="false">
+			<DomainDetails>
+				<CreatedDate>01/01/2024</CreatedDate>
+				<ExpiredDate>01/01/2025</ExpiredDate>
+			</DomainDetails>
+			<DnsDetails ProviderType="FREE" IsUsingOurDNS="true"/>
+			` + tt.lockDetails + `
+		</DomainGetInfoResult>
+	</CommandResponse>
+</ApiResponse>`
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "namecheap.domains.getInfo", r.FormValue("Command"))
+				w.Header().Set("Content-Type", "application/xml")
+				_, err := w.Write([]byte(responseXML))
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			client := NewClient(Config{
+				APIUser:    "testuser",
+				APIKey:     "testkey",
+				Username:   "testuser",
+				ClientIP:   "127.0.0.1",
+				BaseURL:    server.URL,
+				HTTPClient: &http.Client{Timeout: 5 * time.Second},
+			})
+
+			domain, err := client.GetDomain(context.Background(), "example.com")
+			require.NoError(t, err)
+			assert.Equal(t, 125, domain.ID)
+			assert.Equal(t, tt.expected, domain.TransferOutPending)
+		})
+	}
+}
+
+func TestClient_FreshRead(t *testing.T) {
+	var cacheControl []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cacheControl = append(cacheControl, r.Header.Get("Cache-Control"))
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainGetInfoResult Status="Ok" ID="125" DomainName="example.com">
+		</DomainGetInfoResult>
+	</CommandResponse>
+</ApiResponse>`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	_, err := client.GetDomain(context.Background(), "example.com")
+	require.NoError(t, err)
+	_, err = client.GetDomain(WithFreshRead(context.Background()), "example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"", "no-cache"}, cacheControl)
+}
+
+func TestClient_GetRegistrarLock(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   string
+		expected bool
+	}{
+		{name: "locked", status: "true", expected: true},
+		{name: "unlocked", status: "false", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "namecheap.domains.getRegistrarLock", r.FormValue("Command"))
+				assert.Equal(t, "example.com", r.FormValue("DomainName"))
+				w.Header().Set("Content-Type", "application/xml")
+				_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainGetRegistrarLockResult Domain="example.com" RegistrarLockStatus="` + tt.status + `"/>
+	</CommandResponse>
+</ApiResponse>`))
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			client := NewClient(Config{
+				APIUser:    "testuser",
+				APIKey:     "testkey",
+				Username:   "testuser",
+				ClientIP:   "127.0.0.1",
+				BaseURL:    server.URL,
+				HTTPClient: &http.Client{Timeout: 5 * time.Second},
+			})
+
+			locked, err := client.GetRegistrarLock(context.Background(), "example.com")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, locked)
+		})
+	}
+}
+
+func TestClient_SetRegistrarLock(t *testing.T) {
+	tests := []struct {
+		name    string
+		locked  bool
+		action  string
+		success string
+		err     string
+	}{
+		{name: "lock", locked: true, action: "LOCK", success: "true"},
+		{name: "unlock", locked: false, action: "UNLOCK", success: "true"},
+		{name: "failure", locked: true, action: "LOCK", success: "false", err: "failed to update registrar lock"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "namecheap.domains.setRegistrarLock", r.FormValue("Command"))
+				assert.Equal(t, "example.com", r.FormValue("DomainName"))
+				assert.Equal(t, tt.action, r.FormValue("LockAction"))
+				w.Header().Set("Content-Type", "application/xml")
+				_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainSetRegistrarLockResult Domain="example.com" IsSuccess="` + tt.success + `"/>
+	</CommandResponse>
+</ApiResponse>`))
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			client := NewClient(Config{
+				APIUser:    "testuser",
+				APIKey:     "testkey",
+				Username:   "testuser",
+				ClientIP:   "127.0.0.1",
+				BaseURL:    server.URL,
+				HTTPClient: &http.Client{Timeout: 5 * time.Second},
+			})
+
+			err := client.SetRegistrarLock(context.Background(), "example.com", tt.locked)
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_ReactivateDomain(t *testing.T) {
+	tests := []struct {
+		name      string
+		promoCode string
+		response  string
+		refused   bool
+	}{
+		{
+			name:      "reactivated",
+			promoCode: "REACT10",
+			response: `<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainReactivateResult Domain="example.com" IsSuccess="true" ChargedAmount="650.00" OrderID="23569" TransactionID="25080"/>
+	</CommandResponse>
+</ApiResponse>`,
+		},
+		{
+			name: "not successful",
+			response: `<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainReactivateResult Domain="example.com" IsSuccess="false"/>
+	</CommandResponse>
+</ApiResponse>`,
+			refused: true,
+		},
+		{
+			name: "past redemption",
+			response: `<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="2019166">Domain not found</Error>
+	</Errors>
+</ApiResponse>`,
+			refused: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "namecheap.domains.reactivate", r.FormValue("Command"))
+				assert.Equal(t, "example.com", r.FormValue("DomainName"))
+				assert.Equal(t, tt.promoCode, r.FormValue("PromotionCode"))
+				_, hasPromo := r.Form["PromotionCode"]
+				assert.Equal(t, tt.promoCode != "", hasPromo)
+				w.Header().Set("Content-Type", "application/xml")
+				_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" + tt.response))
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			client := NewClient(Config{
+				APIUser:    "testuser",
+				APIKey:     "testkey",
+				Username:   "testuser",
+				ClientIP:   "127.0.0.1",
+				BaseURL:    server.URL,
+				HTTPClient: &http.Client{Timeout: 5 * time.Second},
+			})
+
+			reactivation, err := client.ReactivateDomain(context.Background(), "example.com", tt.promoCode)
+			if tt.refused {
+				require.Error(t, err)
+				assert.True(t, IsReactivationRefused(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &DomainReactivation{
+				DomainName:    "example.com",
+				ChargedAmount: 650,
+				OrderID:       23569,
+				TransactionID: 25080,
+			}, reactivation)
+		})
+	}
+
+	assert.False(t, IsReactivationRefused(fmt.Errorf("connection refused")))
+
+	// Only the errors refusing a reactivation are refusals
+	assert.True(t, IsReactivationRefused(fmt.Errorf("failed: %w", Error{Number: "2020166"})))
+	assert.False(t, IsReactivationRefused(Error{Number: "1011102", Description: "Parameter APIKey is invalid"}))
+	assert.False(t, IsReactivationRefused(Error{Number: "5050900", Description: "Unhandled exception"}))
+}
+
+func TestClient_GetDomain_Statuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainGetInfoResult Status="Ok" ID="125" DomainName="example.com">
+			<DomainStatuses>
+				<Status>clientTransferProhibited</Status>
+				<Status> serverTransferProhibited </Status>
+				<Status></Status>
+			</DomainStatuses>
+		</DomainGetInfoResult>
+	</CommandResponse>
+</ApiResponse>`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	domain, err := client.GetDomain(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"clientTransferProhibited", "serverTransferProhibited"}, domain.Statuses)
+	assert.Equal(t, []string{"serverTransferProhibited"}, RegistryStatuses(domain.Statuses))
+}
+
+func TestIsNamecheapNameserver(t *testing.T) {
+	tests := map[string]bool{
+		"dns1.registrar-servers.com":  true,
+		"DNS2.Registrar-Servers.com.": true,
+		"pdns1.registrar-servers.com": true,
+		"ns1.example.com":             false,
+		"registrar-servers.com.evil":  false,
+	}
+
+	for ns, expected := range tests {
+		assert.Equal(t, expected, IsNamecheapNameserver(ns), ns)
+	}
+}
+
+func TestClient_SetDefaultNameservers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "namecheap.domains.dns.setDefault", r.FormValue("Command"))
+		assert.Equal(t, "example", r.FormValue("SLD"))
+		assert.Equal(t, "co.uk", r.FormValue("TLD"))
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSSetDefaultResult Domain="example.co.uk" Updated="true"/>
+	</CommandResponse>
+</ApiResponse>`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	require.NoError(t, client.SetDefaultNameservers(context.Background(), "example.co.uk"))
+}
+
+func TestNormalizeNameservers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{name: "nil", input: nil, expected: nil},
+		{name: "only empty", input: []string{"", " ", "\t"}, expected: nil},
+		{name: "whitespace", input: []string{" ns1.example.com ", "ns2.example.com\n"}, expected: []string{"ns1.example.com", "ns2.example.com"}},
+		{name: "case and trailing dot", input: []string{"NS1.Example.COM."}, expected: []string{"ns1.example.com"}},
+		{name: "empty entries dropped", input: []string{"", "ns1.example.com ", ""}, expected: []string{"ns1.example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeNameservers(tt.input))
+		})
+	}
+}
+
+func TestValidateNameservers(t *testing.T) {
+	tests := []struct {
+		name        string
+		nameservers []string
+		valid       bool
+	}{
+		{name: "valid", nameservers: []string{"ns1.example.com", "ns-2.example.co.uk"}, valid: true},
+		{name: "single label", nameservers: []string{"localhost"}},
+		{name: "underscore", nameservers: []string{"ns_1.example.com"}},
+		{name: "empty label", nameservers: []string{"ns1..example.com"}},
+		{name: "leading hyphen", nameservers: []string{"-ns1.example.com"}},
+		{name: "inner space", nameservers: []string{"ns1 .example.com"}},
+		{name: "URL", nameservers: []string{"https://ns1.example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNameservers(tt.nameservers)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNameserversEqual(t *testing.T) {
+	assert.True(t, NameserversEqual([]string{"ns2.example.com", "NS1.example.com. "}, []string{"ns1.example.com", "ns2.example.com"}))
+	assert.True(t, NameserversEqual([]string{""}, nil))
+	assert.False(t, NameserversEqual([]string{"ns1.example.com"}, []string{"ns1.example.com", "ns2.example.com"}))
+	assert.False(t, NameserversEqual([]string{"ns1.example.com", "ns1.example.com"}, []string{"ns1.example.com", "ns2.example.com"}))
+}
+
+func TestClient_SetNameservers_Normalized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ns1.example.net,ns2.example.net", r.FormValue("Nameservers"))
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSSetCustomResult Domain="example.com" Updated="true"/>
+	</CommandResponse>
+</ApiResponse>`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	require.NoError(t, client.SetNameservers(context.Background(), "example.com", []string{"", "ns1.example.net ", "NS2.example.net."}))
+
+	err := client.SetNameservers(context.Background(), "example.com", []string{"", " "})
+	require.Error(t, err)
+	err = client.SetNameservers(context.Background(), "example.com", []string{"not a nameserver"})
+	require.Error(t, err)
+}
+
+func TestClient_Environment(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		expected string
+	}{
+		{name: "production default", config: Config{}, expected: EnvironmentProduction},
+		{name: "sandbox default", config: Config{Sandbox: true}, expected: EnvironmentSandbox},
+		{name: "sandbox API base", config: Config{BaseURL: "https://api.sandbox.namecheap.com/xml.response"}, expected: EnvironmentSandbox},
+		{name: "production API base overrides sandbox flag", config: Config{Sandbox: true, BaseURL: "https://api.namecheap.com/xml.response"}, expected: EnvironmentProduction},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NewClient(tt.config).Environment())
+		})
+	}
+}
+
+// domainListPage returns a domains.getList response listing domains that
+// expire after the given numbers of days
+func domainListPage(totalItems int, expiresInDays ...int) string {
+	domains := ""
+	for i, days := range expiresInDays {
+		expires := time.Now().AddDate(0, 0, days).UTC().Format(time.RFC3339)
+		domains += fmt.Sprintf(`<Domain ID="%d" Name="example%d.com" Created="01/01/2020" Expires="%s" IsExpired="%t"/>`,
+			i+1, i+1, expires, days < 0)
+	}
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainGetListResult>%s</DomainGetListResult>
+		<Paging>
+			<TotalItems>%d</TotalItems>
+			<CurrentPage>1</CurrentPage>
+			<PageSize>100</PageSize>
+		</Paging>
+	</CommandResponse>
+</ApiResponse>`, domains, totalItems)
+}
+
+func TestClient_GetExpiringDomains(t *testing.T) {
+	// Two pages of expiring domains sorted by expiry date
+	firstPage := make([]int, 100)
+	firstPage[0] = -3 // already expired
+	for i := 1; i < 100; i++ {
+		firstPage[i] = 1
+	}
+	pages := []string{
+		domainListPage(104, firstPage...),
+		domainListPage(104, 20, 29, 31, 40),
+	}
+
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "namecheap.domains.getList", r.FormValue("Command"))
+		assert.Equal(t, "EXPIRING", r.FormValue("ListType"))
+		assert.Equal(t, "EXPIREDATE", r.FormValue("SortBy"))
+		assert.Equal(t, "100", r.FormValue("PageSize"))
+		requested = append(requested, r.FormValue("Page"))
+
+		page, err := strconv.Atoi(r.FormValue("Page"))
+		require.NoError(t, err)
+		require.LessOrEqual(t, page, len(pages))
+		w.Header().Set("Content-Type", "application/xml")
+		_, err = w.Write([]byte(pages[page-1]))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	domains, err := client.GetExpiringDomains(context.Background(), 30)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, requested)
+	// 99 from the first page, skipping the expired domain, and 2 from the
+	// second, stopping at the cutoff
+	assert.Len(t, domains, 101)
+
+	_, err = client.GetExpiringDomains(context.Background(), -1)
+	assert.Error(t, err)
+}
+
+func TestClient_GetDomainCount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "namecheap.domains.getList", r.FormValue("Command"))
+		assert.Equal(t, "ALL", r.FormValue("ListType"))
+		assert.Equal(t, "1", r.FormValue("Page"))
+		assert.Equal(t, "10", r.FormValue("PageSize"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(domainListPage(1234, 100)))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	count, err := client.GetDomainCount(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1234, count)
+}
+
+func TestClient_GetDomains_Pages(t *testing.T) {
+	page := func(size, totalItems int) string {
+		return domainListPage(totalItems, make([]int, size)...)
+	}
+
+	tests := []struct {
+		name      string
+		pages     []string
+		requested []string
+		domains   int
+	}{
+		{
+			name:      "ThreePages",
+			pages:     []string{page(100, 240), page(100, 240), page(40, 240)},
+			requested: []string{"1", "2", "3"},
+			domains:   240,
+		},
+		{
+			name:      "EmptyAccount",
+			pages:     []string{page(0, 0)},
+			requested: []string{"1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "namecheap.domains.getList", r.FormValue("Command"))
+				assert.Equal(t, "100", r.FormValue("PageSize"))
+				requested = append(requested, r.FormValue("Page"))
+
+				n, err := strconv.Atoi(r.FormValue("Page"))
+				require.NoError(t, err)
+				require.LessOrEqual(t, n, len(tt.pages))
+				w.Header().Set("Content-Type", "application/xml")
+				_, err = w.Write([]byte(tt.pages[n-1]))
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			client := NewClient(Config{
+				APIUser:    "testuser",
+				APIKey:     "testkey",
+				Username:   "testuser",
+				ClientIP:   "127.0.0.1",
+				BaseURL:    server.URL,
+				HTTPClient: &http.Client{Timeout: 5 * time.Second},
+			})
+
+			domains, err := client.GetDomains(context.Background())
+			require.NoError(t, err)
+			assert.Len(t, domains, tt.domains)
+			assert.Equal(t, tt.requested, requested)
+		})
+	}
+}
+
+func TestClient_GetDomainsWithOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  DomainListOptions
+		query []url.Values
+	}{
+		{
+			name: "NoOptions",
+			query: []url.Values{
+				{"Page": {"1"}, "PageSize": {"100"}},
+				{"Page": {"2"}, "PageSize": {"100"}},
+			},
+		},
+		{
+			name: "Expiring",
+			opts: DomainListOptions{ListType: DomainListExpiring, SortBy: DomainSortExpireDate},
+			query: []url.Values{
+				{"ListType": {"EXPIRING"}, "SortBy": {"EXPIREDATE"}, "Page": {"1"}, "PageSize": {"100"}},
+				{"ListType": {"EXPIRING"}, "SortBy": {"EXPIREDATE"}, "Page": {"2"}, "PageSize": {"100"}},
+			},
+		},
+		{
+			name: "Search",
+			opts: DomainListOptions{ListType: DomainListAll, SearchTerm: "shop", SortBy: DomainSortNameDesc},
+			query: []url.Values{
+				{"ListType": {"ALL"}, "SearchTerm": {"shop"}, "SortBy": {"NAME_DESC"}, "Page": {"1"}, "PageSize": {"100"}},
+				{"ListType": {"ALL"}, "SearchTerm": {"shop"}, "SortBy": {"NAME_DESC"}, "Page": {"2"}, "PageSize": {"100"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query []url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "namecheap.domains.getList", r.FormValue("Command"))
+				q := url.Values{}
+				for _, key := range []string{"ListType", "SearchTerm", "SortBy", "Page", "PageSize"} {
+					if values, ok := r.URL.Query()[key]; ok {
+						q[key] = values
+					}
+				}
+				query = append(query, q)
+
+				// Two pages of matches: 100 and then 20
+				size := 100
+				if r.FormValue("Page") == "2" {
+					size = 20
+				}
+				w.Header().Set("Content-Type", "application/xml")
+				_, err := w.Write([]byte(domainListPage(120, make([]int, size)...)))
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			client := NewClient(Config{
+				APIUser:    "testuser",
+				APIKey:     "testkey",
+				Username:   "testuser",
+				ClientIP:   "127.0.0.1",
+				BaseURL:    server.URL,
+				HTTPClient: &http.Client{Timeout: 5 * time.Second},
+			})
+
+			domains, err := client.GetDomainsWithOptions(context.Background(), tt.opts)
+			require.NoError(t, err)
+			assert.Len(t, domains, 120)
+			assert.Equal(t, tt.query, query)
+		})
+	}
+}
+
+func TestClient_GetDomainsWithOptions_Invalid(t *testing.T) {
+	client := NewClient(Config{
+		APIUser:  "testuser",
+		APIKey:   "testkey",
+		Username: "testuser",
+		ClientIP: "127.0.0.1",
+		BaseURL:  "http://127.0.0.1:0",
+	})
+
+	_, err := client.GetDomainsWithOptions(context.Background(), DomainListOptions{ListType: "SOON"})
+	assert.EqualError(t, err, `invalid domain list type "SOON"`)
+
+	_, err = client.GetDomainsWithOptions(context.Background(), DomainListOptions{SortBy: "SIZE"})
+	assert.EqualError(t, err, `invalid domain sort order "SIZE"`)
+}
+
+func TestClient_GetDomains_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// Cancel while the first of three pages is being fetched
+		cancel()
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(domainListPage(300, make([]int, 100)...)))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	_, err := client.GetDomains(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, requests)
+}
+
+func TestClient_DomainExists(t *testing.T) {
+	const errorXML = `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="%s">%s</Error>
+	</Errors>
+</ApiResponse>`
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		exists  bool
+		wantErr bool
+	}{
+		{
+			name:   "exists",
+			status: http.StatusOK,
+			body: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainGetInfoResult Status="Ok" ID="125" DomainName="example.com">
+		</DomainGetInfoResult>
+	</CommandResponse>
+</ApiResponse>`,
+			exists: true,
+		},
+		{name: "domain not found", status: http.StatusOK, body: fmt.Sprintf(errorXML, "2019166", "Domain not found")},
+		{name: "domain not found reworded", status: http.StatusOK, body: fmt.Sprintf(errorXML, "2019166", "Le domaine est introuvable")},
+		{name: "not associated with the account", status: http.StatusOK, body: fmt.Sprintf(errorXML, "2016166", "Domain is not associated with your account")},
+		{name: "edit permission not supported", status: http.StatusOK, body: fmt.Sprintf(errorXML, "2030166", "Edit permission for domain is not supported")},
+		{name: "authentication failure", status: http.StatusOK, body: fmt.Sprintf(errorXML, "1011102", "API Key is invalid or API access has not been enabled"), wantErr: true},
+		{name: "other error mentioning domain not found", status: http.StatusOK, body: fmt.Sprintf(errorXML, "1017101", "Domain not found for disabled user"), wantErr: true},
+		{name: "HTTP error", status: http.StatusForbidden, body: "Domain not found", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, string(CommandDomainsGetInfo), r.FormValue("Command"))
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(tt.status)
+				_, err := w.Write([]byte(tt.body))
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			client := NewClient(Config{
+				APIUser:    "testuser",
+				APIKey:     "testkey",
+				Username:   "testuser",
+				ClientIP:   "127.0.0.1",
+				BaseURL:    server.URL,
+				HTTPClient: &http.Client{Timeout: 5 * time.Second},
+			})
+
+			exists, err := client.DomainExists(context.Background(), "example.com")
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.exists, exists)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package namecheap
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// EmailForward forwards the mail of a mailbox of a domain, such as "info",
+// to another address
+type EmailForward struct {
+	Mailbox   string `xml:"mailbox,attr"`
+	ForwardTo string `xml:",chardata"`
+}
+
+// EmailForwardingResponse represents the response from
+// domains.dns.getEmailForwarding
+type EmailForwardingResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainDNSGetEmailForwardingResult struct {
+			Domain   string         `xml:"Domain,attr"`
+			Forwards []EmailForward `xml:"Forward"`
+		} `xml:"DomainDNSGetEmailForwardingResult"`
+	} `xml:"CommandResponse"`
+}
+
+// EmailForwardingSetResponse represents the response from
+// domains.dns.setEmailForwarding
+type EmailForwardingSetResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainDNSSetEmailForwardingResult struct {
+			Domain    string `xml:"Domain,attr"`
+			IsSuccess bool   `xml:"IsSuccess,attr"`
+		} `xml:"DomainDNSSetEmailForwardingResult"`
+	} `xml:"CommandResponse"`
+}
+
+// forwardingWrites serializes the read-modify-write cycles on each domain's
+// email forwarding, as zoneWrites does for its host records
+var forwardingWrites = &zoneLocks{locks: map[zoneKey]*zoneLock{}}
+
+// GetEmailForwarding retrieves the email forwarding of a domain
+func (c *Client) GetEmailForwarding(ctx context.Context, domainName string) ([]EmailForward, error) {
+	resp, err := c.makeRequest(ctx, CommandDomainsDNSGetEmailForwarding, newParams().setDomainName(domainName))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make domains.dns.getEmailForwarding request")
+	}
+
+	var result EmailForwardingResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse domains.dns.getEmailForwarding response")
+	}
+
+	forwards := result.CommandResponse.DomainDNSGetEmailForwardingResult.Forwards
+	for i := range forwards {
+		forwards[i].ForwardTo = strings.TrimSpace(forwards[i].ForwardTo)
+	}
+	return forwards, nil
+}
+
+// SetEmailForwarding replaces the complete email forwarding of a domain with
+// forwards
+func (c *Client) SetEmailForwarding(ctx context.Context, domainName string, forwards []EmailForward) error {
+	params := newParams().setDomainName(domainName)
+	for i, forward := range forwards {
+		params.
+			setRequired(indexed("MailBox", i+1), forward.Mailbox).
+			setRequired(indexed("ForwardTo", i+1), forward.ForwardTo)
+	}
+
+	resp, err := c.makeRequest(ctx, CommandDomainsDNSSetEmailForwarding, params)
+	if err != nil {
+		return errors.Wrap(err, "failed to make domains.dns.setEmailForwarding request")
+	}
+
+	var result EmailForwardingSetResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse domains.dns.setEmailForwarding response")
+	}
+
+	if !result.CommandResponse.DomainDNSSetEmailForwardingResult.IsSuccess {
+		return errors.New("failed to set email forwarding")
+	}
+	return nil
+}
+
+// AddEmailForward adds forward to a domain's email forwarding, keeping its
+// other forwards. domains.dns.setEmailForwarding replaces them all, so the
+// current ones are read and written back along with forward, unless it is
+// already among them. Additions to the same domain are serialized.
+func (c *Client) AddEmailForward(ctx context.Context, domainName string, forward EmailForward) error {
+	unlock, err := forwardingWrites.lock(ctx, c.zoneKey(domainName))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	forwards, err := c.GetEmailForwarding(ctx, domainName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get existing email forwarding")
+	}
+
+	for _, existing := range forwards {
+		if strings.EqualFold(existing.Mailbox, forward.Mailbox) && strings.EqualFold(existing.ForwardTo, forward.ForwardTo) {
+			return nil
+		}
+	}
+	return c.SetEmailForwarding(ctx, domainName, append(forwards, forward))
+}
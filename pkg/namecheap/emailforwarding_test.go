@@ -0,0 +1,138 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeForwarding serves a domain's email forwarding from
+// domains.dns.getEmailForwarding and replaces it on
+// domains.dns.setEmailForwarding
+type fakeForwarding struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	forwards []EmailForward
+	sets     int
+}
+
+func (f *fakeForwarding) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	assert.Equal(f.t, "example.com", r.FormValue("DomainName"))
+	assert.Empty(f.t, r.FormValue("SLD"))
+
+	w.Header().Set("Content-Type", "application/xml")
+	switch command := r.FormValue("Command"); command {
+	case "namecheap.domains.dns.getEmailForwarding":
+		var forwards strings.Builder
+		for _, forward := range f.forwards {
+			fmt.Fprintf(&forwards, `<Forward mailbox="%s">%s</Forward>`,
+				html.EscapeString(forward.Mailbox), html.EscapeString(forward.ForwardTo))
+		}
+		_, err := fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSGetEmailForwardingResult Domain="example.com">%s</DomainDNSGetEmailForwardingResult>
+	</CommandResponse>
+</ApiResponse>`, forwards.String())
+		require.NoError(f.t, err)
+	case "namecheap.domains.dns.setEmailForwarding":
+		f.sets++
+		f.forwards = nil
+		for i := 1; r.FormValue(indexed("MailBox", i)) != ""; i++ {
+			f.forwards = append(f.forwards, EmailForward{
+				Mailbox:   r.FormValue(indexed("MailBox", i)),
+				ForwardTo: r.FormValue(indexed("ForwardTo", i)),
+			})
+		}
+		_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSSetEmailForwardingResult Domain="example.com" IsSuccess="true"/>
+	</CommandResponse>
+</ApiResponse>`))
+		require.NoError(f.t, err)
+	default:
+		f.t.Errorf("unexpected command %s", command)
+	}
+}
+
+func newForwardingClient(t *testing.T, forwarding *fakeForwarding) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(forwarding)
+	t.Cleanup(server.Close)
+
+	return NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+}
+
+func TestClient_GetEmailForwarding(t *testing.T) {
+	client := newFixtureClient(t, nil)
+
+	forwards, err := client.GetEmailForwarding(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []EmailForward{
+		{Mailbox: "info", ForwardTo: "owner@example.net"},
+		{Mailbox: "sales", ForwardTo: "sales@example.net"},
+	}, forwards)
+}
+
+func TestClient_SetEmailForwarding(t *testing.T) {
+	forwarding := &fakeForwarding{t: t, forwards: []EmailForward{{Mailbox: "old", ForwardTo: "old@example.net"}}}
+	client := newForwardingClient(t, forwarding)
+
+	// The forwards replace the existing ones
+	forwards := []EmailForward{
+		{Mailbox: "info", ForwardTo: "owner@example.net"},
+		{Mailbox: "sales", ForwardTo: "sales@example.net"},
+	}
+	require.NoError(t, client.SetEmailForwarding(context.Background(), "example.com", forwards))
+	assert.Equal(t, forwards, forwarding.forwards)
+
+	// A forward without a mailbox is refused before any request
+	err := client.SetEmailForwarding(context.Background(), "example.com", []EmailForward{{ForwardTo: "owner@example.net"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "MailBox1")
+	assert.Equal(t, 1, forwarding.sets)
+}
+
+func TestClient_AddEmailForward(t *testing.T) {
+	info := EmailForward{Mailbox: "info", ForwardTo: "owner@example.net"}
+	forwarding := &fakeForwarding{t: t, forwards: []EmailForward{info}}
+	client := newForwardingClient(t, forwarding)
+	ctx := context.Background()
+
+	// The new forward is written along with the existing one
+	sales := EmailForward{Mailbox: "sales", ForwardTo: "sales@example.net"}
+	require.NoError(t, client.AddEmailForward(ctx, "example.com", sales))
+	assert.Equal(t, []EmailForward{info, sales}, forwarding.forwards)
+
+	// A mailbox may forward to several addresses
+	other := EmailForward{Mailbox: "info", ForwardTo: "deputy@example.net"}
+	require.NoError(t, client.AddEmailForward(ctx, "example.com", other))
+	assert.Equal(t, []EmailForward{info, sales, other}, forwarding.forwards)
+	assert.Equal(t, 2, forwarding.sets)
+
+	// An existing forward isn't written again
+	require.NoError(t, client.AddEmailForward(ctx, "example.com", EmailForward{Mailbox: "Sales", ForwardTo: "Sales@example.net"}))
+	assert.Equal(t, 2, forwarding.sets)
+}
@@ -0,0 +1,145 @@
+package namecheap
+
+import "fmt"
+
+// ErrorInfo describes a known Namecheap API error number
+type ErrorInfo struct {
+	// Description is a short description of the error
+	Description string
+
+	// Remediation is a hint on how to resolve the error
+	Remediation string
+}
+
+// errorCatalog maps known Namecheap API error numbers to their description
+// and a remediation hint. Every error number referenced in the code must be
+// listed here; TestErrorNumbersCataloged enforces this.
+var errorCatalog = map[string]ErrorInfo{
+	// Authentication and access
+	"1010101": {
+		Description: "Parameter APIUser is missing",
+		Remediation: "set api_user in the ProviderConfig credentials secret",
+	},
+	"1010102": {
+		Description: "Parameter APIKey is missing",
+		Remediation: "set api_key in the ProviderConfig credentials secret",
+	},
+	"1010104": {
+		Description: "Parameter Command is missing",
+		Remediation: "this is a provider bug; please report it",
+	},
+	"1010105": {
+		Description: "Parameter ClientIp is missing",
+		Remediation: "set client_ip in the ProviderConfig credentials secret",
+	},
+	"1011102": {
+		Description: "Parameter APIKey is invalid",
+		Remediation: "check api_key; sandbox and production accounts have different API keys",
+	},
+	ErrNumberInvalidClientIP: {
+		Description: "Parameter RequestIP is invalid",
+		Remediation: "whitelist the client IP in Profile → Tools → API Access, or list more candidates in client_ips",
+	},
+	"1017101": {
+		Description: "Parameter APIUser is disabled or locked",
+		Remediation: "check the account status and API access in the Namecheap dashboard",
+	},
+	"1017105": {
+		Description: "Parameter ClientIP is disabled or locked",
+		Remediation: "re-enable the client IP in Profile → Tools → API Access",
+	},
+	"1017150": {
+		Description: "Parameter RequestIP is disabled or locked",
+		Remediation: "re-enable the client IP in Profile → Tools → API Access",
+	},
+	"1017410": {
+		Description: "Too many declined payments",
+		Remediation: "resolve the declined payments in the Namecheap dashboard",
+	},
+	"1017411": {
+		Description: "Too many login attempts",
+		Remediation: "wait before retrying and check the credentials are correct",
+	},
+
+	// Domains
+	"2011169": {
+		Description: "Only 50 domains are allowed in a single check command",
+		Remediation: "check fewer domains per request",
+	},
+	"2016166": {
+		Description: "Domain is not associated with your account",
+		Remediation: "check the domain name and that the API user owns the domain",
+	},
+	"2019166": {
+		Description: "Domain not found",
+		Remediation: "check the domain name and that it is registered in this account",
+	},
+	"2020166": {
+		Description: "Domain does not meet the expire date for reactivation",
+		Remediation: "only expired domains within their grace or redemption period can be reactivated",
+	},
+	"2030166": {
+		Description: "Edit permission for domain is not supported",
+		Remediation: "check the domain is in this account and is not expired or locked by Namecheap",
+	},
+	"2030288": {
+		Description: "Domain is not using Namecheap DNS",
+		Remediation: "switch the domain to Namecheap's default nameservers before managing DNS records",
+	},
+	"4019337": {
+		Description: "Unable to retrieve domain list",
+		Remediation: "temporary Namecheap error; retry later",
+	},
+	"4023166": {
+		Description: "Error occurred while reactivating domain",
+		Remediation: "check the domain can still be reactivated in the Namecheap dashboard, and contact Namecheap support if it can",
+	},
+
+	// SSL certificates
+	"2011280": {
+		Description: "Invalid CSR",
+		Remediation: "check spec.forProvider.csr is a complete PEM encoded certificate signing request",
+	},
+
+	// Transient errors, retried automatically
+	"2011170": {
+		Description: "Server temporarily unavailable",
+		Remediation: "retried automatically; retry later if it persists",
+	},
+	"2030280": {
+		Description: "Too many requests",
+		Remediation: "retried automatically; lower the request rate if it persists",
+	},
+	"2030281": {
+		Description: "Too many requests",
+		Remediation: "retried automatically; lower the request rate if it persists",
+	},
+	ErrNumberWhoisGuardNotReady: {
+		Description: "WhoisGuard is not yet active for the domain",
+		Remediation: "retried automatically with a backoff while the WhoisGuard subscription of a newly registered domain activates",
+	},
+	"3050900": {
+		Description: "Unknown response from the registry",
+		Remediation: "temporary registry error; retry later",
+	},
+	"5050900": {
+		Description: "Unhandled exception",
+		Remediation: "temporary Namecheap error; retry later, and contact Namecheap support if it persists",
+	},
+}
+
+// LookupError returns the catalog entry for a Namecheap API error number
+func LookupError(number string) (ErrorInfo, bool) {
+	info, ok := errorCatalog[number]
+	return info, ok
+}
+
+// ExplainError returns a human readable explanation of a Namecheap API
+// error number
+func ExplainError(number string) string {
+	info, ok := LookupError(number)
+	if !ok {
+		return fmt.Sprintf("%s: unknown Namecheap API error", number)
+	}
+	return fmt.Sprintf("%s: %s (hint: %s)", number, info.Description, info.Remediation)
+}
@@ -0,0 +1,80 @@
+package namecheap
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errorNumber matches the seven digit Namecheap API error numbers
+var errorNumber = regexp.MustCompile(`^[1-5][0-9]{6}$`)
+
+// TestErrorNumbersCataloged parses the provider source and checks that every
+// error number referenced in it is in the error catalog.
+func TestErrorNumbersCataloged(t *testing.T) {
+	root := filepath.Join("..", "..")
+
+	referenced := 0
+	for _, dir := range []string{"internal", "cmd", "pkg"} {
+		err := filepath.WalkDir(filepath.Join(root, dir), func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, path, nil, 0)
+			if err != nil {
+				return err
+			}
+			ast.Inspect(file, func(n ast.Node) bool {
+				lit, ok := n.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+				value, err := strconv.Unquote(lit.Value)
+				if err != nil || !errorNumber.MatchString(value) {
+					return true
+				}
+				referenced++
+				_, ok = LookupError(value)
+				assert.True(t, ok, "%s: error number %s is not in the error catalog", fset.Position(lit.Pos()), value)
+				return true
+			})
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	assert.Positive(t, referenced, "no error numbers found; has the source moved?")
+}
+
+func TestErrorCatalogComplete(t *testing.T) {
+	for number, info := range errorCatalog {
+		assert.Regexp(t, errorNumber, number)
+		assert.NotEmpty(t, info.Description, number)
+		assert.NotEmpty(t, info.Remediation, number)
+	}
+}
+
+func TestError_Hint(t *testing.T) {
+	err := Error{Number: ErrNumberInvalidClientIP, Description: "Invalid request IP"}
+	assert.Equal(t, "Namecheap API Error 1011150: Invalid request IP "+
+		"(hint: whitelist the client IP in Profile → Tools → API Access, or list more candidates in client_ips)", err.Error())
+
+	err = Error{Number: "9999999", Description: "Something else"}
+	assert.Equal(t, "Namecheap API Error 9999999: Something else", err.Error())
+}
+
+func TestExplainError(t *testing.T) {
+	assert.Equal(t, "2019166: Domain not found (hint: check the domain name and that it is registered in this account)",
+		ExplainError("2019166"))
+	assert.Equal(t, "9999999: unknown Namecheap API error", ExplainError("9999999"))
+}
@@ -0,0 +1,401 @@
+// Package fake provides fakes of the parts of the Namecheap API the
+// controllers use, for testing them without a Namecheap API.
+//
+// Each fake calls the Mock function of the method called. A method whose
+// Mock function isn't set returns an error naming it, so that a test fails
+// on calls it didn't expect, except for Environment, which reports
+// production.
+package fake
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/rossigee/provider-namecheap/pkg/namecheap"
+)
+
+var (
+	_ namecheap.DomainAPI   = &DomainAPI{}
+	_ namecheap.DNSAPI      = &DNSAPI{}
+	_ namecheap.TransferAPI = &TransferAPI{}
+	_ namecheap.SSLAPI      = &SSLAPI{}
+)
+
+// unexpected is returned by methods whose Mock function isn't set
+func unexpected(method string) error {
+	return errors.Errorf("unexpected call to %s", method)
+}
+
+// environment returns the environment reported by mock, production if it
+// isn't set
+func environment(mock func() string) string {
+	if mock == nil {
+		return namecheap.EnvironmentProduction
+	}
+	return mock()
+}
+
+// DomainAPI is a fake namecheap.DomainAPI
+type DomainAPI struct {
+	MockEnvironment            func() string
+	MockDomainExists           func(ctx context.Context, domainName string) (bool, error)
+	MockGetDomainInfo          func(ctx context.Context, domainName string) (*namecheap.DomainInfo, error)
+	MockCreateDomain           func(ctx context.Context, domainName string, years int, contacts namecheap.DomainContacts, opts namecheap.DomainCreateOptions) (*namecheap.DomainRegistration, error)
+	MockRenewDomain            func(ctx context.Context, domainName string, years int) (*namecheap.DomainRenewal, error)
+	MockReactivateDomain       func(ctx context.Context, domainName, promoCode string) (*namecheap.DomainReactivation, error)
+	MockGetRegistrarLock       func(ctx context.Context, domainName string) (bool, error)
+	MockSetRegistrarLock       func(ctx context.Context, domainName string, locked bool) error
+	MockGetDomainContacts      func(ctx context.Context, domainName string) (*namecheap.DomainContacts, error)
+	MockSetDomainContacts      func(ctx context.Context, domainName string, contacts namecheap.DomainContacts) error
+	MockSetNameservers         func(ctx context.Context, domainName string, nameservers []string) error
+	MockSetDefaultNameservers  func(ctx context.Context, domainName string) error
+	MockGetDNSHosts            func(ctx context.Context, domainName string) (*namecheap.DNSHosts, error)
+	MockGetTLDForDomain        func(ctx context.Context, domainName string) (*namecheap.TLD, error)
+	MockGetWhoisGuardForDomain func(ctx context.Context, domainName string) (*namecheap.WhoisGuard, error)
+	MockEnableWhoisGuard       func(ctx context.Context, whoisGuardID int, domainName, forwardedToEmail string) error
+	MockDisableWhoisGuard      func(ctx context.Context, whoisGuardID int, domainName string) error
+}
+
+// Environment calls MockEnvironment
+func (f *DomainAPI) Environment() string {
+	return environment(f.MockEnvironment)
+}
+
+// DomainExists calls MockDomainExists
+func (f *DomainAPI) DomainExists(ctx context.Context, domainName string) (bool, error) {
+	if f.MockDomainExists == nil {
+		return false, unexpected("DomainExists")
+	}
+	return f.MockDomainExists(ctx, domainName)
+}
+
+// GetDomainInfo calls MockGetDomainInfo
+func (f *DomainAPI) GetDomainInfo(ctx context.Context, domainName string) (*namecheap.DomainInfo, error) {
+	if f.MockGetDomainInfo == nil {
+		return nil, unexpected("GetDomainInfo")
+	}
+	return f.MockGetDomainInfo(ctx, domainName)
+}
+
+// CreateDomain calls MockCreateDomain
+func (f *DomainAPI) CreateDomain(ctx context.Context, domainName string, years int, contacts namecheap.DomainContacts, opts namecheap.DomainCreateOptions) (*namecheap.DomainRegistration, error) {
+	if f.MockCreateDomain == nil {
+		return nil, unexpected("CreateDomain")
+	}
+	return f.MockCreateDomain(ctx, domainName, years, contacts, opts)
+}
+
+// RenewDomain calls MockRenewDomain
+func (f *DomainAPI) RenewDomain(ctx context.Context, domainName string, years int) (*namecheap.DomainRenewal, error) {
+	if f.MockRenewDomain == nil {
+		return nil, unexpected("RenewDomain")
+	}
+	return f.MockRenewDomain(ctx, domainName, years)
+}
+
+// ReactivateDomain calls MockReactivateDomain
+func (f *DomainAPI) ReactivateDomain(ctx context.Context, domainName, promoCode string) (*namecheap.DomainReactivation, error) {
+	if f.MockReactivateDomain == nil {
+		return nil, unexpected("ReactivateDomain")
+	}
+	return f.MockReactivateDomain(ctx, domainName, promoCode)
+}
+
+// GetRegistrarLock calls MockGetRegistrarLock
+func (f *DomainAPI) GetRegistrarLock(ctx context.Context, domainName string) (bool, error) {
+	if f.MockGetRegistrarLock == nil {
+		return false, unexpected("GetRegistrarLock")
+	}
+	return f.MockGetRegistrarLock(ctx, domainName)
+}
+
+// SetRegistrarLock calls MockSetRegistrarLock
+func (f *DomainAPI) SetRegistrarLock(ctx context.Context, domainName string, locked bool) error {
+	if f.MockSetRegistrarLock == nil {
+		return unexpected("SetRegistrarLock")
+	}
+	return f.MockSetRegistrarLock(ctx, domainName, locked)
+}
+
+// GetDomainContacts calls MockGetDomainContacts
+func (f *DomainAPI) GetDomainContacts(ctx context.Context, domainName string) (*namecheap.DomainContacts, error) {
+	if f.MockGetDomainContacts == nil {
+		return nil, unexpected("GetDomainContacts")
+	}
+	return f.MockGetDomainContacts(ctx, domainName)
+}
+
+// SetDomainContacts calls MockSetDomainContacts
+func (f *DomainAPI) SetDomainContacts(ctx context.Context, domainName string, contacts namecheap.DomainContacts) error {
+	if f.MockSetDomainContacts == nil {
+		return unexpected("SetDomainContacts")
+	}
+	return f.MockSetDomainContacts(ctx, domainName, contacts)
+}
+
+// SetNameservers calls MockSetNameservers
+func (f *DomainAPI) SetNameservers(ctx context.Context, domainName string, nameservers []string) error {
+	if f.MockSetNameservers == nil {
+		return unexpected("SetNameservers")
+	}
+	return f.MockSetNameservers(ctx, domainName, nameservers)
+}
+
+// SetDefaultNameservers calls MockSetDefaultNameservers
+func (f *DomainAPI) SetDefaultNameservers(ctx context.Context, domainName string) error {
+	if f.MockSetDefaultNameservers == nil {
+		return unexpected("SetDefaultNameservers")
+	}
+	return f.MockSetDefaultNameservers(ctx, domainName)
+}
+
+// GetDNSHosts calls MockGetDNSHosts
+func (f *DomainAPI) GetDNSHosts(ctx context.Context, domainName string) (*namecheap.DNSHosts, error) {
+	if f.MockGetDNSHosts == nil {
+		return nil, unexpected("GetDNSHosts")
+	}
+	return f.MockGetDNSHosts(ctx, domainName)
+}
+
+// GetTLDForDomain calls MockGetTLDForDomain
+func (f *DomainAPI) GetTLDForDomain(ctx context.Context, domainName string) (*namecheap.TLD, error) {
+	if f.MockGetTLDForDomain == nil {
+		return nil, unexpected("GetTLDForDomain")
+	}
+	return f.MockGetTLDForDomain(ctx, domainName)
+}
+
+// GetWhoisGuardForDomain calls MockGetWhoisGuardForDomain
+func (f *DomainAPI) GetWhoisGuardForDomain(ctx context.Context, domainName string) (*namecheap.WhoisGuard, error) {
+	if f.MockGetWhoisGuardForDomain == nil {
+		return nil, unexpected("GetWhoisGuardForDomain")
+	}
+	return f.MockGetWhoisGuardForDomain(ctx, domainName)
+}
+
+// EnableWhoisGuard calls MockEnableWhoisGuard
+func (f *DomainAPI) EnableWhoisGuard(ctx context.Context, whoisGuardID int, domainName, forwardedToEmail string) error {
+	if f.MockEnableWhoisGuard == nil {
+		return unexpected("EnableWhoisGuard")
+	}
+	return f.MockEnableWhoisGuard(ctx, whoisGuardID, domainName, forwardedToEmail)
+}
+
+// DisableWhoisGuard calls MockDisableWhoisGuard
+func (f *DomainAPI) DisableWhoisGuard(ctx context.Context, whoisGuardID int, domainName string) error {
+	if f.MockDisableWhoisGuard == nil {
+		return unexpected("DisableWhoisGuard")
+	}
+	return f.MockDisableWhoisGuard(ctx, whoisGuardID, domainName)
+}
+
+// DNSAPI is a fake namecheap.DNSAPI
+type DNSAPI struct {
+	MockEnvironment          func() string
+	MockGetDNSHosts          func(ctx context.Context, domainName string) (*namecheap.DNSHosts, error)
+	MockReplaceDNSHosts      func(ctx context.Context, domainName string, hosts namecheap.DNSHosts) error
+	MockFindDNSRecords       func(ctx context.Context, domainName, recordName, recordType string) ([]namecheap.DNSRecord, error)
+	MockCreateDNSRecord      func(ctx context.Context, domainName string, record namecheap.DNSRecord) error
+	MockUpdateDNSRecord      func(ctx context.Context, domainName string, record namecheap.DNSRecord) error
+	MockDeleteDNSRecordExact func(ctx context.Context, domainName string, record namecheap.DNSRecord) (bool, error)
+}
+
+// Environment calls MockEnvironment
+func (f *DNSAPI) Environment() string {
+	return environment(f.MockEnvironment)
+}
+
+// GetDNSHosts calls MockGetDNSHosts
+func (f *DNSAPI) GetDNSHosts(ctx context.Context, domainName string) (*namecheap.DNSHosts, error) {
+	if f.MockGetDNSHosts == nil {
+		return nil, unexpected("GetDNSHosts")
+	}
+	return f.MockGetDNSHosts(ctx, domainName)
+}
+
+// ReplaceDNSHosts calls MockReplaceDNSHosts
+func (f *DNSAPI) ReplaceDNSHosts(ctx context.Context, domainName string, hosts namecheap.DNSHosts) error {
+	if f.MockReplaceDNSHosts == nil {
+		return unexpected("ReplaceDNSHosts")
+	}
+	return f.MockReplaceDNSHosts(ctx, domainName, hosts)
+}
+
+// FindDNSRecords calls MockFindDNSRecords
+func (f *DNSAPI) FindDNSRecords(ctx context.Context, domainName, recordName, recordType string) ([]namecheap.DNSRecord, error) {
+	if f.MockFindDNSRecords == nil {
+		return nil, unexpected("FindDNSRecords")
+	}
+	return f.MockFindDNSRecords(ctx, domainName, recordName, recordType)
+}
+
+// CreateDNSRecord calls MockCreateDNSRecord
+func (f *DNSAPI) CreateDNSRecord(ctx context.Context, domainName string, record namecheap.DNSRecord) error {
+	if f.MockCreateDNSRecord == nil {
+		return unexpected("CreateDNSRecord")
+	}
+	return f.MockCreateDNSRecord(ctx, domainName, record)
+}
+
+// UpdateDNSRecord calls MockUpdateDNSRecord
+func (f *DNSAPI) UpdateDNSRecord(ctx context.Context, domainName string, record namecheap.DNSRecord) error {
+	if f.MockUpdateDNSRecord == nil {
+		return unexpected("UpdateDNSRecord")
+	}
+	return f.MockUpdateDNSRecord(ctx, domainName, record)
+}
+
+// DeleteDNSRecordExact calls MockDeleteDNSRecordExact
+func (f *DNSAPI) DeleteDNSRecordExact(ctx context.Context, domainName string, record namecheap.DNSRecord) (bool, error) {
+	if f.MockDeleteDNSRecordExact == nil {
+		return false, unexpected("DeleteDNSRecordExact")
+	}
+	return f.MockDeleteDNSRecordExact(ctx, domainName, record)
+}
+
+// TransferAPI is a fake namecheap.TransferAPI
+type TransferAPI struct {
+	MockEnvironment       func() string
+	MockCreateTransfer    func(ctx context.Context, domainName string, years int, opts namecheap.TransferOptions) (*namecheap.TransferOrder, error)
+	MockGetTransferStatus func(ctx context.Context, transferID int) (*namecheap.TransferStatus, error)
+	MockResubmitTransfer  func(ctx context.Context, transferID int, eppCode string) error
+}
+
+// Environment calls MockEnvironment
+func (f *TransferAPI) Environment() string {
+	return environment(f.MockEnvironment)
+}
+
+// CreateTransfer calls MockCreateTransfer
+func (f *TransferAPI) CreateTransfer(ctx context.Context, domainName string, years int, opts namecheap.TransferOptions) (*namecheap.TransferOrder, error) {
+	if f.MockCreateTransfer == nil {
+		return nil, unexpected("CreateTransfer")
+	}
+	return f.MockCreateTransfer(ctx, domainName, years, opts)
+}
+
+// GetTransferStatus calls MockGetTransferStatus
+func (f *TransferAPI) GetTransferStatus(ctx context.Context, transferID int) (*namecheap.TransferStatus, error) {
+	if f.MockGetTransferStatus == nil {
+		return nil, unexpected("GetTransferStatus")
+	}
+	return f.MockGetTransferStatus(ctx, transferID)
+}
+
+// ResubmitTransfer calls MockResubmitTransfer
+func (f *TransferAPI) ResubmitTransfer(ctx context.Context, transferID int, eppCode string) error {
+	if f.MockResubmitTransfer == nil {
+		return unexpected("ResubmitTransfer")
+	}
+	return f.MockResubmitTransfer(ctx, transferID, eppCode)
+}
+
+// SSLAPI is a fake namecheap.SSLAPI
+type SSLAPI struct {
+	MockEnvironment                  func() string
+	MockGetSSLCertificate            func(ctx context.Context, certificateID int) (*namecheap.SSLGetInfoResponse, error)
+	MockGetSSLCertificateWithOptions func(ctx context.Context, certificateID int, opts namecheap.SSLInfoOptions) (*namecheap.SSLGetInfoResponse, error)
+	MockCreateSSLCertificate         func(ctx context.Context, certificateType, years int, sansToAdd string) (int, error)
+	MockActivateSSLCertificate       func(ctx context.Context, certificateID int, req namecheap.ActivationRequest) error
+	MockReissueSSLCertificate        func(ctx context.Context, certificateID int, csr, approverEmail string) error
+	MockResendSSLApprovalEmail       func(ctx context.Context, certificateID int) error
+	MockGetSSLApproverEmailList      func(ctx context.Context, domainName string, certificateType int) (*namecheap.SSLApproverEmails, error)
+	MockParseCSR                     func(ctx context.Context, csr string, certificateType int) (*namecheap.CSRDetails, error)
+	MockRenewSSLCertificate          func(ctx context.Context, certificateID int, sslType string, years int, promoCode string) (*namecheap.SSLRenewal, error)
+	MockRevokeSSLCertificate         func(ctx context.Context, certificateID, certificateType int) error
+	MockEditSSLDCValidation          func(ctx context.Context, certificateID int, dnsNames []string) ([]namecheap.DNSValidationRecord, error)
+}
+
+// Environment calls MockEnvironment
+func (f *SSLAPI) Environment() string {
+	return environment(f.MockEnvironment)
+}
+
+// GetSSLCertificate calls MockGetSSLCertificate
+func (f *SSLAPI) GetSSLCertificate(ctx context.Context, certificateID int) (*namecheap.SSLGetInfoResponse, error) {
+	if f.MockGetSSLCertificate == nil {
+		return nil, unexpected("GetSSLCertificate")
+	}
+	return f.MockGetSSLCertificate(ctx, certificateID)
+}
+
+// GetSSLCertificateWithOptions calls MockGetSSLCertificateWithOptions
+func (f *SSLAPI) GetSSLCertificateWithOptions(ctx context.Context, certificateID int, opts namecheap.SSLInfoOptions) (*namecheap.SSLGetInfoResponse, error) {
+	if f.MockGetSSLCertificateWithOptions == nil {
+		return nil, unexpected("GetSSLCertificateWithOptions")
+	}
+	return f.MockGetSSLCertificateWithOptions(ctx, certificateID, opts)
+}
+
+// CreateSSLCertificate calls MockCreateSSLCertificate
+func (f *SSLAPI) CreateSSLCertificate(ctx context.Context, certificateType, years int, sansToAdd string) (int, error) {
+	if f.MockCreateSSLCertificate == nil {
+		return 0, unexpected("CreateSSLCertificate")
+	}
+	return f.MockCreateSSLCertificate(ctx, certificateType, years, sansToAdd)
+}
+
+// ActivateSSLCertificate calls MockActivateSSLCertificate
+func (f *SSLAPI) ActivateSSLCertificate(ctx context.Context, certificateID int, req namecheap.ActivationRequest) error {
+	if f.MockActivateSSLCertificate == nil {
+		return unexpected("ActivateSSLCertificate")
+	}
+	return f.MockActivateSSLCertificate(ctx, certificateID, req)
+}
+
+// ReissueSSLCertificate calls MockReissueSSLCertificate
+func (f *SSLAPI) ReissueSSLCertificate(ctx context.Context, certificateID int, csr, approverEmail string) error {
+	if f.MockReissueSSLCertificate == nil {
+		return unexpected("ReissueSSLCertificate")
+	}
+	return f.MockReissueSSLCertificate(ctx, certificateID, csr, approverEmail)
+}
+
+// ResendSSLApprovalEmail calls MockResendSSLApprovalEmail
+func (f *SSLAPI) ResendSSLApprovalEmail(ctx context.Context, certificateID int) error {
+	if f.MockResendSSLApprovalEmail == nil {
+		return unexpected("ResendSSLApprovalEmail")
+	}
+	return f.MockResendSSLApprovalEmail(ctx, certificateID)
+}
+
+// GetSSLApproverEmailList calls MockGetSSLApproverEmailList
+func (f *SSLAPI) GetSSLApproverEmailList(ctx context.Context, domainName string, certificateType int) (*namecheap.SSLApproverEmails, error) {
+	if f.MockGetSSLApproverEmailList == nil {
+		return nil, unexpected("GetSSLApproverEmailList")
+	}
+	return f.MockGetSSLApproverEmailList(ctx, domainName, certificateType)
+}
+
+// ParseCSR calls MockParseCSR
+func (f *SSLAPI) ParseCSR(ctx context.Context, csr string, certificateType int) (*namecheap.CSRDetails, error) {
+	if f.MockParseCSR == nil {
+		return nil, unexpected("ParseCSR")
+	}
+	return f.MockParseCSR(ctx, csr, certificateType)
+}
+
+// RenewSSLCertificate calls MockRenewSSLCertificate
+func (f *SSLAPI) RenewSSLCertificate(ctx context.Context, certificateID int, sslType string, years int, promoCode string) (*namecheap.SSLRenewal, error) {
+	if f.MockRenewSSLCertificate == nil {
+		return nil, unexpected("RenewSSLCertificate")
+	}
+	return f.MockRenewSSLCertificate(ctx, certificateID, sslType, years, promoCode)
+}
+
+// RevokeSSLCertificate calls MockRevokeSSLCertificate
+func (f *SSLAPI) RevokeSSLCertificate(ctx context.Context, certificateID, certificateType int) error {
+	if f.MockRevokeSSLCertificate == nil {
+		return unexpected("RevokeSSLCertificate")
+	}
+	return f.MockRevokeSSLCertificate(ctx, certificateID, certificateType)
+}
+
+// EditSSLDCValidation calls MockEditSSLDCValidation
+func (f *SSLAPI) EditSSLDCValidation(ctx context.Context, certificateID int, dnsNames []string) ([]namecheap.DNSValidationRecord, error) {
+	if f.MockEditSSLDCValidation == nil {
+		return nil, unexpected("EditSSLDCValidation")
+	}
+	return f.MockEditSSLDCValidation(ctx, certificateID, dnsNames)
+}
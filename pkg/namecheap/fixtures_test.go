@@ -0,0 +1,300 @@
+package namecheap
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Fixtures in testdata are Namecheap API responses in the shape documented
+// by Namecheap, with account details replaced by example values. Successful
+// responses are named after their command without the "namecheap." prefix,
+// e.g. testdata/domains.getInfo.xml; error responses are named
+// error.<description>.xml.
+
+// fixture returns the named fixture from testdata.
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name+".xml"))
+	require.NoError(t, err)
+	return data
+}
+
+// commandFixture returns the fixture of a successful response to command.
+func commandFixture(t *testing.T, command Command) []byte {
+	t.Helper()
+	return fixture(t, strings.TrimPrefix(command.String(), "namecheap."))
+}
+
+// newFixtureClient returns a client whose API replies to every command with
+// the command's fixture. Commands in overrides are answered with the named
+// fixture instead.
+func newFixtureClient(t *testing.T, overrides map[Command]string) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		command := Command(r.FormValue("Command"))
+		w.Header().Set("Content-Type", "application/xml")
+		if name, ok := overrides[command]; ok {
+			_, _ = w.Write(fixture(t, name))
+			return
+		}
+		_, _ = w.Write(commandFixture(t, command))
+	}))
+	t.Cleanup(server.Close)
+
+	return NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+}
+
+// responseFixtures pairs every response struct with the command whose
+// fixture exercises it.
+var responseFixtures = []struct {
+	command Command
+	result  interface{}
+}{
+	{CommandDomainsGetList, &DomainListResponse{}},
+	{CommandDomainsGetInfo, &DomainInfoResponse{}},
+	{CommandDomainsGetTLDList, &TLDListResponse{}},
+	{CommandDomainsCheck, &DomainCheckResponse{}},
+	{CommandDomainsCreate, &DomainCreateResponse{}},
+	{CommandDomainsRenew, &DomainRenewResponse{}},
+	{CommandDomainsReactivate, &DomainReactivateResponse{}},
+	{CommandDomainsGetRegistrarLock, &RegistrarLockResponse{}},
+	{CommandDomainsSetRegistrarLock, &RegistrarLockSetResponse{}},
+	{CommandDomainsGetContacts, &DomainContactsResponse{}},
+	{CommandDomainsSetContacts, &DomainSetContactsResponse{}},
+	{CommandDomainsDNSSetCustom, &DNSSetCustomResponse{}},
+	{CommandDomainsDNSSetDefault, &DNSSetDefaultResponse{}},
+	{CommandDomainsDNSGetHosts, &DNSHostsResponse{}},
+	{CommandDomainsDNSSetHosts, &DNSSetHostsResponse{}},
+	{CommandDomainsDNSGetEmailForwarding, &EmailForwardingResponse{}},
+	{CommandDomainsDNSSetEmailForwarding, &EmailForwardingSetResponse{}},
+	{CommandDomainsTransferCreate, &TransferCreateResponse{}},
+	{CommandDomainsTransferGetStatus, &TransferGetStatusResponse{}},
+	{CommandDomainsTransferGetList, &TransferListResponse{}},
+	{CommandDomainsTransferUpdateStatus, &TransferUpdateStatusResponse{}},
+	{CommandSSLGetList, &SSLListResponse{}},
+	{CommandSSLGetInfo, &SSLGetInfoResponse{}},
+	{CommandSSLCreate, &SSLCreateResponse{}},
+	{CommandSSLActivate, &SSLActivateResponse{}},
+	{CommandSSLResend, &SSLResendResponse{}},
+	{CommandSSLReissue, &SSLReissueResponse{}},
+	{CommandSSLGetApproverEmailList, &SSLApproverEmailListResponse{}},
+	{CommandSSLParseCSR, &SSLParseCSRResponse{}},
+	{CommandSSLRenew, &SSLRenewResponse{}},
+	{CommandSSLRevoke, &SSLRevokeResponse{}},
+	{CommandSSLEditDCValidation, &SSLEditDCValidationResponse{}},
+	{CommandUsersGetBalances, &UserBalanceResponse{}},
+	{CommandUsersGetPricing, &UserPricingResponse{}},
+	{CommandUsersCreateAddFundsRequest, &AddFundsRequestResponse{}},
+	{CommandUsersGetAddFundsStatus, &AddFundsStatusResponse{}},
+	{CommandUsersAddressGetList, &AccountAddressListResponse{}},
+	{CommandUsersAddressGetInfo, &AccountAddressInfoResponse{}},
+	{CommandUsersAddressCreate, &AccountAddressCreateResponse{}},
+	{CommandUsersAddressUpdate, &AccountAddressUpdateResponse{}},
+	{CommandUsersAddressDelete, &AccountAddressDeleteResponse{}},
+	{CommandUsersAddressSetDefault, &AccountAddressSetDefaultResponse{}},
+	{CommandWhoisGuardGetList, &WhoisGuardListResponse{}},
+	{CommandWhoisGuardEnable, &WhoisGuardEnableResponse{}},
+	{CommandWhoisGuardDisable, &WhoisGuardDisableResponse{}},
+	{CommandWhoisGuardRenew, &WhoisGuardRenewResponse{}},
+	{CommandWhoisGuardAllot, &WhoisGuardAllotResponse{}},
+	{CommandWhoisGuardUnallot, &WhoisGuardUnallotResponse{}},
+	{CommandWhoisGuardDiscard, &WhoisGuardDiscardResponse{}},
+	{CommandWhoisGuardChangeEmail, &WhoisGuardChangeEmailResponse{}},
+}
+
+// TestFixturesCoverResponses checks that every response struct in the
+// package has a fixture, and that every command has one.
+func TestFixturesCoverResponses(t *testing.T) {
+	covered := map[string]bool{}
+	for _, rf := range responseFixtures {
+		covered[reflect.TypeOf(rf.result).Elem().Name()] = true
+	}
+
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+	fset := token.NewFileSet()
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, f, nil, 0)
+		require.NoError(t, err)
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				name := spec.(*ast.TypeSpec).Name.Name
+				if strings.HasSuffix(name, "Response") && name != "APIResponse" {
+					assert.True(t, covered[name], "%s has no fixture in responseFixtures", name)
+				}
+			}
+		}
+	}
+
+	for command := range commands {
+		_, err := os.Stat(filepath.Join("testdata", strings.TrimPrefix(command.String(), "namecheap.")+".xml"))
+		assert.NoError(t, err, "command %s has no fixture", command)
+	}
+}
+
+// TestFixturesParse checks that every response struct parses its fixture
+// and that the fixture populates the struct's command response.
+func TestFixturesParse(t *testing.T) {
+	for _, rf := range responseFixtures {
+		t.Run(rf.command.String(), func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(commandFixture(t, rf.command))),
+			}
+			require.NoError(t, parseResponse(resp, rf.result))
+
+			commandResponse := reflect.ValueOf(rf.result).Elem().FieldByName("CommandResponse")
+			require.True(t, commandResponse.IsValid())
+			assert.False(t, commandResponse.IsZero(), "fixture populates none of the command response")
+		})
+	}
+}
+
+func TestFixtures_Errors(t *testing.T) {
+	client := newFixtureClient(t, map[Command]string{
+		CommandDomainsGetInfo:   "error.domainNotFound",
+		CommandUsersGetBalances: "error.invalidClientIP",
+	})
+
+	exists, err := client.DomainExists(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = client.GetUserBalances(context.Background())
+	var ncErr Error
+	require.ErrorAs(t, err, &ncErr)
+	assert.Equal(t, ErrNumberInvalidClientIP, ncErr.Number)
+}
+
+func TestFixtures_Client(t *testing.T) {
+	client := newFixtureClient(t, nil)
+	ctx := context.Background()
+
+	// domains.dns.setCustom reports success with Update, not Updated
+	require.NoError(t, client.SetNameservers(ctx, "example.com", []string{"dns1.example.net", "dns2.example.net"}))
+
+	// domains.getInfo reports the identity in attributes of
+	// DomainGetInfoResult and the dates in elements of DomainDetails
+	info, err := client.GetDomainInfo(ctx, "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 127, info.ID)
+	assert.Equal(t, "example.com", info.Name)
+	assert.Equal(t, "testuser", info.User)
+	assert.Equal(t, time.Date(2016, 2, 15, 0, 0, 0, 0, time.UTC), info.Created.Time)
+	assert.Equal(t, time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), info.Expires.Time)
+	assert.Equal(t, DNSDetails{
+		ProviderType:  "FREE",
+		IsUsingOurDNS: true,
+		Nameservers:   []string{"dns1.registrar-servers.com", "dns2.registrar-servers.com"},
+	}, info.DNS)
+	assert.Equal(t, info.DNS.Nameservers, info.Nameservers)
+	assert.True(t, info.IsOurDNS)
+	assert.Equal(t, "owner@example.org", info.WhoisGuardForwardedTo)
+
+	locked, err := client.GetRegistrarLock(ctx, "example.com")
+	require.NoError(t, err)
+	assert.True(t, locked)
+	require.NoError(t, client.SetRegistrarLock(ctx, "example.com", true))
+
+	balance, err := client.GetUserBalances(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", balance.Currency)
+	assert.Equal(t, 4932.96, balance.AvailableBalance)
+
+	whoisGuard, err := client.GetWhoisGuardForDomain(ctx, "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 53536, whoisGuard.ID)
+	assert.Equal(t, "ENABLED", whoisGuard.Status)
+
+	// ssl.getInfo reports the certificate's type and dates in attributes
+	// and its common name in CertificateDetails
+	cert, err := client.GetSSLCertificate(ctx, 1234)
+	require.NoError(t, err)
+	certInfo := cert.CommandResponse.SSLGetInfoResult
+	assert.Equal(t, "active", certInfo.Status)
+	assert.Equal(t, "PositiveSSL", certInfo.Type)
+	assert.Equal(t, "example.com", certInfo.CertificateDetails.CommonName)
+	assert.Equal(t, time.Date(2025, 9, 26, 0, 0, 0, 0, time.UTC), certInfo.Expires.Time)
+	assert.Equal(t, "COMODO", certInfo.Provider.Name)
+
+	// domains.check reports each domain in a DomainCheckResult directly
+	// under CommandResponse
+	results, err := client.CheckDomainAvailability(ctx, []string{"example.com", "example-available.com"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "example.com", results[0].Domain)
+	assert.False(t, results[0].Available)
+	assert.Equal(t, "0", results[0].ErrorCode)
+	assert.Equal(t, "example-available.com", results[1].Domain)
+	assert.True(t, results[1].Available)
+}
+
+func TestFixturesParse_Encodings(t *testing.T) {
+	body := commandFixture(t, CommandUsersGetBalances)
+	crlf := bytes.ReplaceAll(body, []byte("\n"), []byte("\r\n"))
+	withEncoding := func(encoding string) []byte {
+		return bytes.Replace(body, []byte(`encoding="utf-8"`), []byte(`encoding="`+encoding+`"`), 1)
+	}
+
+	tests := []struct {
+		name    string
+		body    []byte
+		wantErr bool
+	}{
+		{name: "BOM", body: append(append([]byte{}, utf8BOM...), body...)},
+		{name: "CRLF", body: crlf},
+		{name: "BOMAndCRLF", body: append(append([]byte{}, utf8BOM...), crlf...)},
+		{name: "UpperCaseUTF8", body: withEncoding("UTF-8")},
+		{name: "UTF8WithoutHyphen", body: withEncoding("utf8")},
+		{name: "ASCII", body: withEncoding("us-ascii")},
+		{name: "UnsupportedEncoding", body: withEncoding("ISO-8859-1"), wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(tc.body)),
+			}
+			var result UserBalanceResponse
+			err := parseResponse(resp, &result)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "USD", result.CommandResponse.UserGetBalancesResult.Currency)
+		})
+	}
+}
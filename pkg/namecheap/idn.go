@@ -0,0 +1,46 @@
+package namecheap
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/idna"
+)
+
+// ToASCII returns a domain name in the form the API takes and reports:
+// lower case, with any internationalized labels punycode-encoded, e.g.
+// bücher.example as xn--bcher-kva.example. Names already in that form are
+// returned unchanged.
+func ToASCII(domainName string) (string, error) {
+	ascii, err := idna.Lookup.ToASCII(strings.TrimSuffix(strings.TrimSpace(domainName), "."))
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid domain name %q", domainName)
+	}
+	return ascii, nil
+}
+
+// ToUnicode returns a domain name with any punycode-encoded labels decoded,
+// e.g. xn--bcher-kva.example as bücher.example. A name that can't be decoded
+// is returned unchanged.
+func ToUnicode(domainName string) string {
+	unicode, err := idna.Display.ToUnicode(domainName)
+	if err != nil {
+		return domainName
+	}
+	return unicode
+}
+
+// IsIDN reports whether a domain name has internationalized labels, in
+// either form
+func IsIDN(domainName string) bool {
+	ascii, err := ToASCII(domainName)
+	if err != nil {
+		return false
+	}
+	for _, label := range strings.Split(ascii, ".") {
+		if strings.HasPrefix(label, "xn--") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package namecheap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToASCII(t *testing.T) {
+	tests := []struct {
+		name   string
+		domain string
+		want   string
+	}{
+		{name: "ASCII", domain: "example.com", want: "example.com"},
+		{name: "upper case", domain: "Example.COM", want: "example.com"},
+		{name: "trailing dot", domain: "example.com.", want: "example.com"},
+		{name: "Unicode", domain: "bücher.example", want: "xn--bcher-kva.example"},
+		{name: "punycode", domain: "xn--bcher-kva.example", want: "xn--bcher-kva.example"},
+		{name: "Unicode TLD", domain: "例え.テスト", want: "xn--r8jz45g.xn--zckzah"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToASCII(tt.domain)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := ToASCII("exa mple.com")
+	assert.Error(t, err)
+}
+
+func TestToUnicode(t *testing.T) {
+	assert.Equal(t, "bücher.example", ToUnicode("xn--bcher-kva.example"))
+	assert.Equal(t, "bücher.example", ToUnicode("bücher.example"))
+	assert.Equal(t, "example.com", ToUnicode("example.com"))
+}
+
+func TestIsIDN(t *testing.T) {
+	assert.True(t, IsIDN("bücher.example"))
+	assert.True(t, IsIDN("xn--bcher-kva.example"))
+	assert.False(t, IsIDN("example.com"))
+	assert.False(t, IsIDN("exa mple.com"))
+}
@@ -0,0 +1,104 @@
+package namecheap
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Labels of the API request metrics. The label set is deliberately bounded so
+// that the number of series stays proportional to commands × namespaces ×
+// kinds: never add resource names or other per-object values, and keep
+// MetricLabels in sync with this list.
+const (
+	// LabelCommand is the API command; unregistered commands are reported
+	// as CategoryUnknown
+	LabelCommand = "command"
+
+	// LabelOutcome is OutcomeSuccess or OutcomeError
+	LabelOutcome = "outcome"
+
+	// LabelNamespace is the namespace of the managed resource the request
+	// was made for, or empty when made outside a reconcile
+	LabelNamespace = "namespace"
+
+	// LabelKind is the kind of the managed resource the request was made for
+	LabelKind = "kind"
+)
+
+// Request outcomes reported in LabelOutcome
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// MetricLabels is the complete label set of the API request metrics
+var MetricLabels = []string{LabelCommand, LabelOutcome, LabelNamespace, LabelKind}
+
+// Labels of the cache metrics
+const (
+	// LabelCache is the cached response, tlds or pricing
+	LabelCache = "cache"
+
+	// LabelResult is CacheResultHit or CacheResultMiss
+	LabelResult = "result"
+)
+
+// Cache results reported in LabelResult. A load that waits for a fetch
+// already in progress is a hit, as it makes no request of its own.
+const (
+	CacheResultHit  = "hit"
+	CacheResultMiss = "miss"
+)
+
+var (
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "namecheap_api_requests_total",
+		Help: "Number of Namecheap API requests, by command, outcome and the namespace and kind of the resource they were made for.",
+	}, MetricLabels)
+
+	apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "namecheap_api_request_duration_seconds",
+		Help:    "Duration of Namecheap API requests including retries, by command, outcome and the namespace and kind of the resource they were made for.",
+		Buckets: prometheus.DefBuckets,
+	}, MetricLabels)
+
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "namecheap_api_cache_requests_total",
+		Help: "Number of reads of cached Namecheap API responses, by cache and whether they were served without a request.",
+	}, []string{LabelCache, LabelResult})
+)
+
+// RegisterMetrics registers the package's metrics with reg, e.g.
+// controller-runtime's metrics.Registry. The metrics are recorded whether or
+// not they are registered.
+func RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{apiRequests, apiRequestDuration, cacheRequests, delayedDomainWrites} {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// observeCache records a read of a cached response in the cache metrics
+func observeCache(cache, result string) {
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// observeRequest records a completed API request in the request metrics
+func observeRequest(ctx context.Context, command Command, start time.Time, err error) {
+	name := command.String()
+	if !command.Registered() {
+		name = string(CategoryUnknown)
+	}
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeError
+	}
+	namespace, kind := resourceFromContext(ctx)
+
+	apiRequests.WithLabelValues(name, outcome, namespace, kind).Inc()
+	apiRequestDuration.WithLabelValues(name, outcome, namespace, kind).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,114 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetricLabels guards the label set of the request metrics against
+// accidental growth. Adding a label multiplies the number of series, so it
+// must be a deliberate change to this test.
+func TestMetricLabels(t *testing.T) {
+	assert.ElementsMatch(t, []string{"command", "outcome", "namespace", "kind"}, MetricLabels)
+
+	for _, c := range []prometheus.Collector{apiRequests, apiRequestDuration} {
+		ch := make(chan *prometheus.Desc, 1)
+		c.Describe(ch)
+		desc := (<-ch).String()
+		assert.Contains(t, desc, fmt.Sprintf("variableLabels: {%s}", strings.Join(MetricLabels, ",")))
+	}
+}
+
+func TestRegisterMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	require.NoError(t, RegisterMetrics(reg))
+
+	observeCache("tlds", CacheResultHit)
+	names := map[string]bool{}
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, f := range families {
+		names[f.GetName()] = true
+	}
+	assert.True(t, names["namecheap_api_cache_requests_total"])
+
+	// Each registry may only hold the metrics once
+	assert.Error(t, RegisterMetrics(reg))
+}
+
+func TestRequestMetrics(t *testing.T) {
+	apiRequests.Reset()
+	apiRequestDuration.Reset()
+	t.Cleanup(func() {
+		apiRequests.Reset()
+		apiRequestDuration.Reset()
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainGetInfoResult Status="Ok" ID="125" DomainName="example.com">
+		</DomainGetInfoResult>
+	</CommandResponse>
+</ApiResponse>`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		RateLimitConfig: &RateLimitConfig{
+			RequestsPerSecond: 1000,
+			BurstSize:         1000,
+		},
+	})
+
+	// Many resources of the same namespace and kind share one series
+	for i := 0; i < 20; i++ {
+		ctx := WithResource(context.Background(), "team-a", "Domain")
+		_, err := client.GetDomain(ctx, fmt.Sprintf("example%d.com", i))
+		require.NoError(t, err)
+	}
+	_, err := client.GetDomain(WithResource(context.Background(), "team-b", "Domain"), "example.com")
+	require.NoError(t, err)
+	_, err = client.GetDomain(context.Background(), "example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, testutil.CollectAndCount(apiRequests))
+	assert.Equal(t, 3, testutil.CollectAndCount(apiRequestDuration))
+	assert.Equal(t, float64(20), testutil.ToFloat64(apiRequests.WithLabelValues(string(CommandDomainsGetInfo), OutcomeSuccess, "team-a", "Domain")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(apiRequests.WithLabelValues(string(CommandDomainsGetInfo), OutcomeSuccess, "team-b", "Domain")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(apiRequests.WithLabelValues(string(CommandDomainsGetInfo), OutcomeSuccess, "", "")))
+}
+
+func TestObserveRequest_UnregisteredCommand(t *testing.T) {
+	apiRequests.Reset()
+	apiRequestDuration.Reset()
+	t.Cleanup(func() {
+		apiRequests.Reset()
+		apiRequestDuration.Reset()
+	})
+
+	observeRequest(context.Background(), Command("namecheap.example.one"), time.Now(), assert.AnError)
+	observeRequest(context.Background(), Command("namecheap.example.two"), time.Now(), assert.AnError)
+
+	assert.Equal(t, 1, testutil.CollectAndCount(apiRequests))
+	assert.Equal(t, float64(2), testutil.ToFloat64(apiRequests.WithLabelValues(string(CategoryUnknown), OutcomeError, "", "")))
+}
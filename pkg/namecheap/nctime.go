@@ -0,0 +1,66 @@
+package namecheap
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ncTimeLayouts are the layouts of the dates Namecheap reports. The
+// production API reports month-first dates, with or without a time, and
+// the sandbox sometimes RFC 3339 ones. Months and days may be a single
+// digit.
+var ncTimeLayouts = []string{
+	"1/2/2006",
+	"1/2/2006T15:04:05",
+	"1/2/2006 15:04:05",
+	"1/2/2006 3:04:05 PM",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+}
+
+// ncTime is a date reported by the Namecheap API. An empty date is the zero
+// time.
+type ncTime struct {
+	time.Time
+}
+
+// parseNCTime parses a date in any of the layouts Namecheap reports
+func parseNCTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range ncTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.Errorf("unrecognized date %q", value)
+}
+
+// UnmarshalXMLAttr parses a date reported in an attribute
+func (t *ncTime) UnmarshalXMLAttr(attr xml.Attr) error {
+	parsed, err := parseNCTime(attr.Value)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse %s", attr.Name.Local)
+	}
+	t.Time = parsed
+	return nil
+}
+
+// UnmarshalXML parses a date reported in an element
+func (t *ncTime) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var value string
+	if err := d.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+	parsed, err := parseNCTime(value)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse %s", start.Name.Local)
+	}
+	t.Time = parsed
+	return nil
+}
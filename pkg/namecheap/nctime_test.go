@@ -0,0 +1,59 @@
+package namecheap
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNCTime_UnmarshalXMLAttr(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "MonthFirst", value: "02/15/2025", want: time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC)},
+		{name: "SingleDigits", value: "2/5/2025", want: time.Date(2025, 2, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "MonthFirstWithTime", value: "2/5/2025T13:04:05", want: time.Date(2025, 2, 5, 13, 4, 5, 0, time.UTC)},
+		{name: "MonthFirstWithSpacedTime", value: "02/15/2025 13:04:05", want: time.Date(2025, 2, 15, 13, 4, 5, 0, time.UTC)},
+		{name: "MonthFirstWithClockTime", value: "2/15/2025 1:04:05 PM", want: time.Date(2025, 2, 15, 13, 4, 5, 0, time.UTC)},
+		{name: "RFC3339", value: "2025-02-15T00:00:00Z", want: time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC)},
+		{name: "RFC3339WithOffset", value: "2025-02-15T00:00:00-04:00", want: time.Date(2025, 2, 15, 4, 0, 0, 0, time.UTC)},
+		{name: "WithoutZone", value: "0001-01-01T00:00:00"},
+		{name: "Empty"},
+		{name: "DayFirst", value: "15/02/2025", wantErr: true},
+		{name: "Garbage", value: "soon", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var result struct {
+				Expires ncTime `xml:"Expires,attr"`
+			}
+			err := xml.Unmarshal([]byte(`<Domain Expires="`+tc.value+`"/>`), &result)
+			if tc.wantErr {
+				assert.ErrorContains(t, err, "cannot parse Expires")
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tc.want.Equal(result.Expires.Time), "got %s", result.Expires.Time)
+		})
+	}
+}
+
+func TestNCTime_UnmarshalXML(t *testing.T) {
+	var result struct {
+		Created ncTime `xml:"CreatedDate"`
+		Expires ncTime `xml:"ExpiredDate"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(`<DomainDetails><CreatedDate>02/15/2016</CreatedDate><ExpiredDate> 2/15/2026 </ExpiredDate></DomainDetails>`), &result))
+	assert.Equal(t, time.Date(2016, 2, 15, 0, 0, 0, 0, time.UTC), result.Created.Time)
+	assert.Equal(t, time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), result.Expires.Time)
+
+	err := xml.Unmarshal([]byte(`<DomainDetails><ExpiredDate>soon</ExpiredDate></DomainDetails>`), &result)
+	assert.ErrorContains(t, err, "cannot parse ExpiredDate")
+}
@@ -0,0 +1,153 @@
+package namecheap
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// params builds the parameters of an API command in insertion order, so that
+// they can be logged and compared in a stable order. Setting a parameter
+// again replaces its value but keeps its position. Values marked sensitive
+// are redacted when the parameters are logged.
+//
+// A missing required parameter is recorded rather than returned, so that a
+// command's parameters can be built in one chain; makeRequest refuses to
+// send parameters that fail validate.
+type params struct {
+	keys      []string
+	values    map[string]string
+	sensitive map[string]bool
+	err       error
+}
+
+// newParams returns an empty set of parameters
+func newParams() *params {
+	return &params{values: map[string]string{}, sensitive: map[string]bool{}}
+}
+
+// set sets a parameter
+func (p *params) set(key, value string) *params {
+	if _, ok := p.values[key]; !ok {
+		p.keys = append(p.keys, key)
+	}
+	p.values[key] = value
+	return p
+}
+
+// setInt sets a parameter to an integer
+func (p *params) setInt(key string, value int) *params {
+	return p.set(key, strconv.Itoa(value))
+}
+
+// setOptional sets a parameter only if value isn't empty
+func (p *params) setOptional(key, value string) *params {
+	if value == "" {
+		return p
+	}
+	return p.set(key, value)
+}
+
+// setRequired sets a parameter, recording an error if value is empty
+func (p *params) setRequired(key, value string) *params {
+	if strings.TrimSpace(value) == "" && p.err == nil {
+		p.err = errors.Errorf("missing required parameter %s", key)
+	}
+	return p.set(key, value)
+}
+
+// setSensitive sets a parameter whose value is redacted when logged
+func (p *params) setSensitive(key, value string) *params {
+	p.sensitive[key] = true
+	return p.set(key, value)
+}
+
+// setDomainName sets the DomainName parameter in the punycode form the API
+// takes, recording an error if domainName is missing or invalid
+func (p *params) setDomainName(domainName string) *params {
+	ascii, err := ToASCII(domainName)
+	if err != nil {
+		if p.err == nil {
+			p.err = err
+		}
+		ascii = domainName
+	}
+	return p.setRequired("DomainName", ascii)
+}
+
+// setDomain sets the SLD and TLD parameters some commands take instead of
+// DomainName, in punycode, recording an error if domainName is invalid or
+// not a registrable domain
+func (p *params) setDomain(domainName string) *params {
+	sld, tld, err := SplitDomain(domainName)
+	if err != nil && p.err == nil {
+		p.err = err
+	}
+	return p.set("SLD", sld).set("TLD", tld)
+}
+
+// domain returns the domain set by setDomainName or setDomain, or empty if
+// neither was
+func (p *params) domain() string {
+	if domainName := p.values["DomainName"]; domainName != "" {
+		return domainName
+	}
+	if p.values["SLD"] == "" {
+		return ""
+	}
+	return p.values["SLD"] + "." + p.values["TLD"]
+}
+
+// indexed returns the name of the index'th parameter of a list, such as
+// HostName1, counting from 1 as the API does
+func indexed(key string, index int) string {
+	return key + strconv.Itoa(index)
+}
+
+// validate returns the first error recorded while building the parameters
+func (p *params) validate() error {
+	return p.err
+}
+
+// asMap returns the parameters as a map
+func (p *params) asMap() map[string]string {
+	m := make(map[string]string, len(p.keys))
+	for _, key := range p.keys {
+		m[key] = p.values[key]
+	}
+	return m
+}
+
+// addTo adds the parameters to values
+func (p *params) addTo(values url.Values) {
+	for _, key := range p.keys {
+		values.Set(key, p.values[key])
+	}
+}
+
+// sensitiveKeys returns the keys of the parameters marked sensitive
+func (p *params) sensitiveKeys() []string {
+	var keys []string
+	for _, key := range p.keys {
+		if p.sensitive[key] {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// String returns the parameters as key=value pairs in insertion order, with
+// sensitive values redacted
+func (p *params) String() string {
+	pairs := make([]string, 0, len(p.keys))
+	for _, key := range p.keys {
+		value := p.values[key]
+		if p.sensitive[key] {
+			value = redacted
+		}
+		pairs = append(pairs, key+"="+value)
+	}
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,132 @@
+package namecheap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParams_Order(t *testing.T) {
+	params := newParams().
+		set("DomainName", "example.com").
+		setInt("Years", 2).
+		set(indexed("HostName", 1), "@").
+		set("Years", "3")
+
+	// Setting Years again replaces its value but keeps its position
+	assert.Equal(t, "DomainName=example.com Years=3 HostName1=@", params.String())
+	assert.Equal(t, map[string]string{"DomainName": "example.com", "Years": "3", "HostName1": "@"}, params.asMap())
+
+	values := url.Values{"Command": {"namecheap.domains.create"}}
+	params.addTo(values)
+	assert.Equal(t, url.Values{
+		"Command":    {"namecheap.domains.create"},
+		"DomainName": {"example.com"},
+		"Years":      {"3"},
+		"HostName1":  {"@"},
+	}, values)
+}
+
+func TestParams_Optional(t *testing.T) {
+	params := newParams().setOptional("PromotionCode", "").setOptional("AddFreeWhoisguard", "yes")
+	assert.Equal(t, map[string]string{"AddFreeWhoisguard": "yes"}, params.asMap())
+	assert.NoError(t, params.validate())
+}
+
+func TestParams_DomainPunycode(t *testing.T) {
+	// Internationalized names are sent in the punycode form the API takes
+	params := newParams().setDomainName("Bücher.example").setDomain("bücher.co.uk")
+	assert.Equal(t, map[string]string{
+		"DomainName": "xn--bcher-kva.example",
+		"SLD":        "xn--bcher-kva",
+		"TLD":        "co.uk",
+	}, params.asMap())
+	assert.NoError(t, params.validate())
+}
+
+func TestParams_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  *params
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			params: newParams().setRequired("DomainName", "example.com").setDomain("example.co.uk"),
+		},
+		{
+			name:    "missing required",
+			params:  newParams().setRequired("DomainName", " ").setRequired("Years", ""),
+			wantErr: "missing required parameter DomainName",
+		},
+		{
+			name:    "no TLD",
+			params:  newParams().setDomain("example"),
+			wantErr: `invalid domain name format "example"`,
+		},
+		{
+			name:    "no SLD",
+			params:  newParams().setDomain(".com"),
+			wantErr: `invalid domain name format ".com"`,
+		},
+		{
+			name:    "invalid label",
+			params:  newParams().setDomainName("exa mple.com"),
+			wantErr: `invalid domain name "exa mple.com": idna: disallowed rune U+0020`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestParams_SetDomain(t *testing.T) {
+	params := newParams().setDomain("example.co.uk")
+	assert.Equal(t, map[string]string{"SLD": "example", "TLD": "co.uk"}, params.asMap())
+}
+
+func TestParams_Redaction(t *testing.T) {
+	params := newParams().setRequired("DomainName", "example.com").setSensitive("EPPCode", "s3cr3t-epp")
+
+	assert.Equal(t, "DomainName=example.com EPPCode="+redacted, params.String())
+	assert.NotContains(t, params.String(), "s3cr3t-epp")
+
+	// The value itself is still sent
+	assert.Equal(t, "s3cr3t-epp", params.asMap()["EPPCode"])
+}
+
+func TestMakeRequest_InvalidParams(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	_, err := client.GetDNSHosts(context.Background(), "example")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid domain name format "example"`)
+	assert.Zero(t, calls, "invalid parameters must not be sent")
+}
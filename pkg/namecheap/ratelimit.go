@@ -101,6 +101,19 @@ const (
 	CircuitHalfOpen
 )
 
+// String returns the state as closed, open or half-open
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
 // CircuitBreakerConfig defines circuit breaker configuration
 type CircuitBreakerConfig struct {
 	MaxFailures  int
@@ -193,4 +206,4 @@ func (cb *CircuitBreaker) Reset() {
 	cb.state = CircuitClosed
 	cb.failures = 0
 	cb.lastFailTime = time.Time{}
-}
\ No newline at end of file
+}
@@ -106,10 +106,10 @@ func (c *Client) isRetryableError(err error) bool {
 	if errors.As(err, &httpErr) {
 		switch httpErr.StatusCode {
 		case http.StatusTooManyRequests,
-			 http.StatusInternalServerError,
-			 http.StatusBadGateway,
-			 http.StatusServiceUnavailable,
-			 http.StatusGatewayTimeout:
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
 			return true
 		}
 	}
@@ -175,4 +175,4 @@ func (c *Client) logRetrySuccess(operation string, totalAttempts int) {
 			"operation", operation,
 			"attempts", totalAttempts)
 	}
-}
\ No newline at end of file
+}
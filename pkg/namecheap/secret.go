@@ -0,0 +1,121 @@
+package namecheap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// redacted is printed in place of secret material
+const redacted = "REDACTED"
+
+// Secret holds sensitive material such as an API key. Every formatting path
+// (fmt verbs, Stringer, JSON) prints "REDACTED" so that accidentally logging a
+// Secret, or a struct containing one, does not leak its value. Use Value to
+// obtain the underlying string where it is genuinely needed.
+type Secret string
+
+// Value returns the raw secret value
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// String implements fmt.Stringer
+func (s Secret) String() string {
+	return redacted
+}
+
+// GoString implements fmt.GoStringer
+func (s Secret) GoString() string {
+	return redacted
+}
+
+// Format implements fmt.Formatter so that every verb is redacted
+func (s Secret) Format(f fmt.State, verb rune) {
+	_, _ = f.Write([]byte(redacted))
+}
+
+// MarshalJSON implements json.Marshaler
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+// Credentials are the contents of a ProviderConfig credentials secret
+type Credentials struct {
+	APIUser  string `json:"api_user"`
+	APIKey   Secret `json:"api_key"`
+	Username string `json:"username"`
+	ClientIP string `json:"client_ip"`
+
+	// ClientIPs are additional whitelisted client IPs, such as the other
+	// egress IPs of a NAT pool
+	ClientIPs []string `json:"client_ips,omitempty"`
+}
+
+// ParseCredentials decodes a JSON credentials payload and normalizes its
+// client IPs, rejecting any the API would not accept. The payload buffer is
+// zeroed once decoded so the raw API key does not linger in memory.
+func ParseCredentials(data []byte) (Credentials, error) {
+	defer zeroBytes(data)
+
+	var creds Credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return Credentials{}, err
+	}
+
+	if creds.ClientIP != "" {
+		ip, err := NormalizeClientIP(creds.ClientIP)
+		if err != nil {
+			return Credentials{}, err
+		}
+		creds.ClientIP = ip
+	}
+	for i, candidate := range creds.ClientIPs {
+		ip, err := NormalizeClientIP(candidate)
+		if err != nil {
+			return Credentials{}, err
+		}
+		creds.ClientIPs[i] = ip
+	}
+	return creds, nil
+}
+
+// credentialsRevisionLength is the number of hex digits of the payload's
+// SHA-256 kept in a credentials revision
+const credentialsRevisionLength = 16
+
+// CredentialsRevision returns a short, non-reversible checksum of a raw
+// credentials payload: the first 16 hex digits of its SHA-256. Comparing it
+// with the checksum of the secret shows whether a rotated secret has been
+// picked up. It must be computed before ParseCredentials zeroes the payload.
+func CredentialsRevision(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:credentialsRevisionLength]
+}
+
+// zeroBytes overwrites a buffer that held secret material
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// redactURL returns the URL as a string with the API key and any other
+// sensitive query parameters masked
+func redactURL(u *url.URL, sensitive ...string) string {
+	redactedURL := *u
+	query := redactedURL.Query()
+	changed := false
+	for _, key := range append([]string{"ApiKey"}, sensitive...) {
+		if query.Has(key) {
+			query.Set(key, redacted)
+			changed = true
+		}
+	}
+	if changed {
+		redactedURL.RawQuery = query.Encode()
+	}
+	return redactedURL.String()
+}
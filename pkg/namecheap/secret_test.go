@@ -0,0 +1,111 @@
+package namecheap
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecret_Redaction(t *testing.T) {
+	secret := Secret("super-secret-api-key")
+
+	for _, verb := range []string{"%s", "%v", "%+v", "%#v", "%q", "%x", "%10s"} {
+		t.Run(verb, func(t *testing.T) {
+			out := fmt.Sprintf(verb, secret)
+			assert.NotContains(t, out, "super-secret-api-key")
+			assert.Contains(t, out, "REDACTED")
+		})
+	}
+
+	t.Run("nested in struct", func(t *testing.T) {
+		config := Config{APIUser: "testuser", APIKey: secret}
+		for _, verb := range []string{"%v", "%+v", "%#v"} {
+			out := fmt.Sprintf(verb, config)
+			assert.NotContains(t, out, "super-secret-api-key")
+			assert.Contains(t, out, "testuser")
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, err := json.Marshal(Credentials{APIUser: "testuser", APIKey: secret})
+		require.NoError(t, err)
+		assert.NotContains(t, string(out), "super-secret-api-key")
+	})
+
+	t.Run("value", func(t *testing.T) {
+		assert.Equal(t, "super-secret-api-key", secret.Value())
+	})
+}
+
+func TestParseCredentials(t *testing.T) {
+	data := []byte(`{"api_user":"testuser","api_key":"super-secret-api-key","username":"testuser","client_ip":"127.0.0.1"}`)
+
+	creds, err := ParseCredentials(data)
+	require.NoError(t, err)
+	assert.Equal(t, "testuser", creds.APIUser)
+	assert.Equal(t, "super-secret-api-key", creds.APIKey.Value())
+	assert.Equal(t, "127.0.0.1", creds.ClientIP)
+
+	// The input buffer is zeroed once parsed
+	assert.Equal(t, make([]byte, len(data)), data)
+
+	_, err = ParseCredentials([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestParseCredentials_ClientIPs(t *testing.T) {
+	creds, err := ParseCredentials([]byte(`{"api_user":"testuser","api_key":"key","client_ip":"::ffff:192.0.2.1","client_ips":[" 192.0.2.2 "]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.1", creds.ClientIP)
+	assert.Equal(t, []string{"192.0.2.2"}, creds.ClientIPs)
+
+	_, err = ParseCredentials([]byte(`{"api_user":"testuser","api_key":"key","client_ip":"2001:db8::1"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only accepts IPv4")
+
+	_, err = ParseCredentials([]byte(`{"api_user":"testuser","api_key":"key","client_ip":"192.0.2.1","client_ips":["2001:db8::2"]}`))
+	require.Error(t, err)
+}
+
+func TestCredentialsRevision(t *testing.T) {
+	data := `{"api_user":"testuser","api_key":"super-secret-api-key","username":"testuser","client_ip":"127.0.0.1"}`
+
+	// Matches `sha256sum | cut -c1-16` of the secret, so operators can
+	// compare the two
+	revision := CredentialsRevision([]byte(data))
+	assert.Equal(t, "ae5d1c6b75041800", revision)
+	assert.Equal(t, revision, CredentialsRevision([]byte(data)))
+	assert.NotContains(t, revision, "super-secret-api-key")
+
+	// A rotated key changes the revision
+	rotated := strings.Replace(data, "super-secret-api-key", "rotated-api-key", 1)
+	assert.NotEqual(t, revision, CredentialsRevision([]byte(rotated)))
+}
+
+func TestRedactURL(t *testing.T) {
+	u, err := url.Parse("https://api.namecheap.com/xml.response?ApiKey=super-secret-api-key&ApiUser=testuser&Command=namecheap.domains.getList")
+	require.NoError(t, err)
+
+	out := redactURL(u)
+	assert.NotContains(t, out, "super-secret-api-key")
+	assert.Contains(t, out, "ApiKey=REDACTED")
+	assert.Contains(t, out, "Command=namecheap.domains.getList")
+
+	// The original URL is left untouched
+	assert.Contains(t, u.String(), "super-secret-api-key")
+}
+
+func TestRedactURL_Sensitive(t *testing.T) {
+	u, err := url.Parse("https://api.namecheap.com/xml.response?ApiKey=super-secret-api-key&Command=namecheap.domains.transfer.create&EPPCode=s3cr3t-epp")
+	require.NoError(t, err)
+
+	out := redactURL(u, "EPPCode")
+	assert.NotContains(t, out, "super-secret-api-key")
+	assert.NotContains(t, out, "s3cr3t-epp")
+	assert.Contains(t, out, "EPPCode=REDACTED")
+}
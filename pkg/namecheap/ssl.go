@@ -0,0 +1,832 @@
+package namecheap
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SSLCertificate represents an SSL certificate
+type SSLCertificate struct {
+	CertificateID        int    `xml:"CertificateID,attr"`
+	HostName             string `xml:"HostName,attr"`
+	SSLType              string `xml:"SSLType,attr"`
+	PurchaseDate         ncTime `xml:"PurchaseDate,attr"`
+	ExpireDate           ncTime `xml:"ExpireDate,attr"`
+	ActivationExpireDate ncTime `xml:"ActivationExpireDate,attr"`
+	IsExpiredYN          bool   `xml:"IsExpiredYN,attr"`
+	Status               string `xml:"Status,attr"`
+	StatusDescription    string `xml:"StatusDescription,attr"`
+	Years                int    `xml:"Years,attr"`
+}
+
+// SSLListResponse represents the response from ssl.getList
+type SSLListResponse struct {
+	APIResponse
+	CommandResponse struct {
+		SSLGetListResult struct {
+			SSLCertificates []SSLCertificate `xml:"SSL"`
+		} `xml:"SSLListResult"`
+		Paging Paging `xml:"Paging"`
+	} `xml:"CommandResponse"`
+}
+
+// SSLCreateResponse represents the response from ssl.create
+type SSLCreateResponse struct {
+	APIResponse
+	CommandResponse struct {
+		SSLCreateResult struct {
+			IsSuccess      bool    `xml:"IsSuccess,attr"`
+			OrderID        int     `xml:"OrderId,attr"`
+			TransactionID  int     `xml:"TransactionId,attr"`
+			ChargedAmount  float64 `xml:"ChargedAmount,attr"`
+			SSLCertificate struct {
+				CertificateID int    `xml:"CertificateID,attr"`
+				SSLType       string `xml:"SSLType,attr"`
+				Years         int    `xml:"Years,attr"`
+				Status        string `xml:"Status,attr"`
+			} `xml:"SSLCertificate"`
+		} `xml:"SSLCreateResult"`
+	} `xml:"CommandResponse"`
+}
+
+// SSLActivateResponse represents the response from ssl.activate
+type SSLActivateResponse struct {
+	APIResponse
+	CommandResponse struct {
+		SSLActivateResult struct {
+			IsSuccess bool `xml:"IsSuccess,attr"`
+			ID        int  `xml:"ID,attr"`
+		} `xml:"SSLActivateResult"`
+	} `xml:"CommandResponse"`
+}
+
+// SSLGetInfoResponse represents the response from ssl.getInfo. It doesn't
+// report the certificate ID, which the request names, nor whether the
+// certificate expired other than in its status.
+type SSLGetInfoResponse struct {
+	APIResponse
+	CommandResponse struct {
+		SSLGetInfoResult struct {
+			Status               string `xml:"Status,attr"`
+			StatusDescription    string `xml:"StatusDescription,attr"`
+			Type                 string `xml:"Type,attr"`
+			IssuedOn             ncTime `xml:"IssuedOn,attr"`
+			Expires              ncTime `xml:"Expires,attr"`
+			ActivationExpireDate ncTime `xml:"ActivationExpireDate,attr"`
+			OrderID              int    `xml:"OrderId,attr"`
+			ReplacedBy           int    `xml:"ReplacedBy,attr"`
+			SANSCount            int    `xml:"SANSCount,attr"`
+			Provider             struct {
+				OrderID string `xml:"OrderID"`
+				Name    string `xml:"Name"`
+			} `xml:"Provider"`
+			CertificateDetails struct {
+				CommonName         string `xml:"CommonName"`
+				ApproverEmail      string `xml:"ApproverEmail"`
+				AdministratorName  string `xml:"AdministratorName"`
+				AdministratorEmail string `xml:"AdministratorEmail"`
+				// Certificates are only returned when requested
+				Certificates SSLIssuedCertificates `xml:"Certificates"`
+			} `xml:"CertificateDetails"`
+		} `xml:"SSLGetInfoResult"`
+	} `xml:"CommandResponse"`
+}
+
+// Certificate statuses reported by ssl.getInfo and ssl.getList, which
+// compare case-insensitively: ssl.getInfo reports them in lower case
+const (
+	SSLStatusActive  = "ACTIVE"
+	SSLStatusExpired = "EXPIRED"
+	SSLStatusRevoked = "REVOKED"
+)
+
+// SSLIssuedCertificates are an issued certificate and the certificate
+// authority's certificates, as returned individually by ssl.getInfo
+type SSLIssuedCertificates struct {
+	Returned   bool   `xml:"CertificateReturned,attr"`
+	ReturnType string `xml:"ReturnType,attr"`
+	// Certificate is the issued certificate in PEM
+	Certificate    string             `xml:"Certificate"`
+	CACertificates []SSLCACertificate `xml:"CaCertificates>Certificate"`
+}
+
+// SSLCACertificate is a certificate authority's certificate: an
+// intermediate one, or the root one
+type SSLCACertificate struct {
+	Type        string `xml:"Type,attr"`
+	Certificate string `xml:"Certificate"`
+}
+
+// CertificatePEM returns the issued certificate in PEM
+func (s SSLIssuedCertificates) CertificatePEM() string {
+	return pemBlock(s.Certificate)
+}
+
+// CABundle returns the certificate authority's certificates in PEM, in the
+// order Namecheap returned them
+func (s SSLIssuedCertificates) CABundle() string {
+	var b strings.Builder
+	for _, ca := range s.CACertificates {
+		b.WriteString(pemBlock(ca.Certificate))
+	}
+	return b.String()
+}
+
+// Chain returns the issued certificate followed by the intermediate
+// certificates in PEM, as servers present it
+func (s SSLIssuedCertificates) Chain() string {
+	var b strings.Builder
+	b.WriteString(s.CertificatePEM())
+	for _, ca := range s.CACertificates {
+		if strings.EqualFold(ca.Type, "INTERMEDIATE") {
+			b.WriteString(pemBlock(ca.Certificate))
+		}
+	}
+	return b.String()
+}
+
+// pemBlock returns a PEM certificate as returned by Namecheap, trimmed and
+// ending in a newline
+func pemBlock(certificate string) string {
+	certificate = strings.TrimSpace(certificate)
+	if certificate == "" {
+		return ""
+	}
+	return certificate + "\n"
+}
+
+// SSLInfoOptions selects what GetSSLCertificateWithOptions returns besides
+// the certificate's details
+type SSLInfoOptions struct {
+	// ReturnCertificate returns an issued certificate and the certificate
+	// authority's certificates, which are large, in
+	// CertificateDetails.Certificates
+	ReturnCertificate bool
+}
+
+// SSLResendResponse represents the response from ssl.resend
+type SSLResendResponse struct {
+	APIResponse
+	CommandResponse struct {
+		SSLResendResult struct {
+			IsSuccess bool `xml:"IsSuccess,attr"`
+		} `xml:"SSLResendResult"`
+	} `xml:"CommandResponse"`
+}
+
+// SSLReissueResponse represents the response from ssl.reissue
+type SSLReissueResponse struct {
+	APIResponse
+	CommandResponse struct {
+		SSLReissueResult struct {
+			IsSuccess bool `xml:"IsSuccess,attr"`
+		} `xml:"SSLReissueResult"`
+	} `xml:"CommandResponse"`
+}
+
+// SSLApproverEmailListResponse represents the response from
+// ssl.getApproverEmailList
+type SSLApproverEmailListResponse struct {
+	APIResponse
+	CommandResponse struct {
+		GetApproverEmailListResult struct {
+			Domain        string   `xml:"Domain,attr"`
+			DomainEmails  []string `xml:"Domainemails>email"`
+			GenericEmails []string `xml:"Genericemails>email"`
+		} `xml:"GetApproverEmailListResult"`
+	} `xml:"CommandResponse"`
+}
+
+// SSLApproverEmails are the addresses the certificate authority accepts as
+// the approver of a domain's certificate: those of the domain's WHOIS
+// contacts and the generic administrative mailboxes of the domain
+type SSLApproverEmails struct {
+	Domain  []string
+	Generic []string
+}
+
+// All returns every accepted address, those of the WHOIS contacts first
+func (e SSLApproverEmails) All() []string {
+	return append(append([]string{}, e.Domain...), e.Generic...)
+}
+
+// Accepts reports whether email is an accepted address, ignoring case
+func (e SSLApproverEmails) Accepts(email string) bool {
+	for _, accepted := range e.All() {
+		if strings.EqualFold(strings.TrimSpace(accepted), strings.TrimSpace(email)) {
+			return true
+		}
+	}
+	return false
+}
+
+// SSLParseCSRResponse represents the response from ssl.parseCSR
+type SSLParseCSRResponse struct {
+	APIResponse
+	Warnings        []string `xml:"Warnings>Warning"`
+	CommandResponse struct {
+		SSLParseCSRResult struct {
+			CSRDetails struct {
+				CommonName   string `xml:"CommonName"`
+				DomainName   string `xml:"DomainName"`
+				Country      string `xml:"Country"`
+				Organisation string `xml:"Organisation"`
+				State        string `xml:"State"`
+				Locality     string `xml:"Locality"`
+				Email        string `xml:"Email"`
+			} `xml:"CSRDetails"`
+		} `xml:"SSLParseCSRResult"`
+	} `xml:"CommandResponse"`
+}
+
+// SSLRenewResponse represents the response from ssl.renew
+type SSLRenewResponse struct {
+	APIResponse
+	CommandResponse struct {
+		SSLRenewResult struct {
+			CertificateID int     `xml:"CertificateID,attr"`
+			Years         int     `xml:"Years,attr"`
+			SSLType       string  `xml:"SSLType,attr"`
+			OrderID       int     `xml:"OrderId,attr"`
+			TransactionID int     `xml:"TransactionId,attr"`
+			ChargedAmount float64 `xml:"ChargedAmount,attr"`
+		} `xml:"SSLRenewResult"`
+	} `xml:"CommandResponse"`
+}
+
+// SSLRenewal is the outcome of renewing an SSL certificate. Namecheap
+// renews a certificate by purchasing a new one, which must be activated.
+type SSLRenewal struct {
+	// CertificateID is the ID of the new certificate
+	CertificateID int
+	ChargedAmount float64
+	OrderID       int
+	TransactionID int
+}
+
+// SSLRevokeResponse represents the response from ssl.revoke
+type SSLRevokeResponse struct {
+	APIResponse
+	CommandResponse struct {
+		RevokeCertificateResult struct {
+			ID        int  `xml:"ID,attr"`
+			IsSuccess bool `xml:"IsSuccess,attr"`
+		} `xml:"RevokeCertificateResult"`
+	} `xml:"CommandResponse"`
+}
+
+// SSLEditDCValidationResponse represents the response from
+// ssl.editDCValidation
+type SSLEditDCValidationResponse struct {
+	APIResponse
+	CommandResponse struct {
+		SSLEditDCVResult struct {
+			ID                   int                   `xml:"ID,attr"`
+			IsSuccess            bool                  `xml:"IsSuccess,attr"`
+			DNSValidationRecords []DNSValidationRecord `xml:"DNSDCValidation>DNS"`
+		} `xml:"SSLEditDCVResult"`
+	} `xml:"CommandResponse"`
+}
+
+// DNSValidationRecord is a CNAME record proving control of a domain to the
+// certificate authority: HostName must be an alias for Target
+type DNSValidationRecord struct {
+	Domain   string `xml:"domain,attr"`
+	HostName string `xml:"HostName"`
+	Target   string `xml:"Target"`
+}
+
+// dcvMethodCNAME is the domain control validation method by a CNAME record
+// of the CSR's hash
+const dcvMethodCNAME = "CNAME_CSR_HASH"
+
+// CSRDetails are the details of a certificate signing request. The common
+// name is as Namecheap decoded it; the SANs and key size are decoded from
+// the request itself, as Namecheap doesn't report them.
+type CSRDetails struct {
+	CommonName string
+	SANs       []string
+	// KeySize is the size of the public key in bits
+	KeySize int
+	// Warnings are those reported by Namecheap, and any problem decoding
+	// the request locally
+	Warnings []string
+}
+
+// SSL certificate list types accepted by ssl.getList
+const (
+	SSLListAll              = "ALL"
+	SSLListProcessing       = "Processing"
+	SSLListEmailSent        = "EmailSent"
+	SSLListTechnicalProblem = "TechnicalProblem"
+	SSLListInProgress       = "InProgress"
+	SSLListCompleted        = "Completed"
+	SSLListDeactivated      = "Deactivated"
+	SSLListActive           = "Active"
+	SSLListCancelled        = "Cancelled"
+	SSLListNewPurchase      = "NewPurchase"
+	SSLListNewRenewal       = "NewRenewal"
+)
+
+// SSL certificate list sort orders accepted by ssl.getList
+const (
+	SSLSortPurchaseDate       = "PURCHASEDATE"
+	SSLSortPurchaseDateDesc   = "PURCHASEDATE_DESC"
+	SSLSortSSLType            = "SSLTYPE"
+	SSLSortSSLTypeDesc        = "SSLTYPE_DESC"
+	SSLSortExpireDateTime     = "EXPIREDATETIME"
+	SSLSortExpireDateTimeDesc = "EXPIREDATETIME_DESC"
+	SSLSortHostName           = "Host_Name"
+	SSLSortHostNameDesc       = "Host_Name_DESC"
+)
+
+// SSLListOptions narrows and orders the certificates listed by
+// GetSSLCertificatesWithOptions. Empty fields are left to Namecheap's
+// defaults.
+type SSLListOptions struct {
+	// ListType is one of the SSLList types
+	ListType string
+
+	// SearchTerm only lists certificates whose host name contains it
+	SearchTerm string
+
+	// SortBy is one of the SSLSort orders
+	SortBy string
+}
+
+// validate checks that the list type and sort order are ones ssl.getList
+// accepts
+func (o SSLListOptions) validate() error {
+	switch o.ListType {
+	case "", SSLListAll, SSLListProcessing, SSLListEmailSent, SSLListTechnicalProblem,
+		SSLListInProgress, SSLListCompleted, SSLListDeactivated, SSLListActive,
+		SSLListCancelled, SSLListNewPurchase, SSLListNewRenewal:
+	default:
+		return errors.Errorf("invalid SSL certificate list type %q", o.ListType)
+	}
+	switch o.SortBy {
+	case "", SSLSortPurchaseDate, SSLSortPurchaseDateDesc, SSLSortSSLType, SSLSortSSLTypeDesc,
+		SSLSortExpireDateTime, SSLSortExpireDateTimeDesc, SSLSortHostName, SSLSortHostNameDesc:
+	default:
+		return errors.Errorf("invalid SSL certificate sort order %q", o.SortBy)
+	}
+	return nil
+}
+
+// GetSSLCertificates retrieves all SSL certificates for the account
+func (c *Client) GetSSLCertificates(ctx context.Context) ([]SSLCertificate, error) {
+	return c.GetSSLCertificatesWithOptions(ctx, SSLListOptions{})
+}
+
+// GetSSLCertificatesWithOptions retrieves the account's SSL certificates
+// matching opts, requesting as many pages of ssl.getList as the matches
+// need. Cancelling ctx stops the fetch between pages.
+func (c *Client) GetSSLCertificatesWithOptions(ctx context.Context, opts SSLListOptions) ([]SSLCertificate, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	var certificates []SSLCertificate
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.Wrap(err, "ssl.getList cancelled")
+		}
+
+		resp, err := c.makeRequest(ctx, CommandSSLGetList, newParams().
+			setOptional("ListType", opts.ListType).
+			setOptional("SearchTerm", opts.SearchTerm).
+			setOptional("SortBy", opts.SortBy).
+			setInt("Page", page).
+			setInt("PageSize", sslListMaxPageSize))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to make ssl.getList request")
+		}
+
+		var result SSLListResponse
+		if err := parseResponse(resp, &result); err != nil {
+			return nil, errors.Wrap(err, "failed to parse ssl.getList response")
+		}
+
+		pageCertificates := result.CommandResponse.SSLGetListResult.SSLCertificates
+		certificates = append(certificates, pageCertificates...)
+		if len(pageCertificates) == 0 || len(certificates) >= result.CommandResponse.Paging.TotalItems {
+			return certificates, nil
+		}
+	}
+}
+
+// sslListMaxPageSize is the largest page ssl.getList returns
+const sslListMaxPageSize = 100
+
+// CreateSSLCertificate purchases a new SSL certificate. A client that checks
+// balances returns ErrInsufficientFunds rather than purchase a certificate
+// the account can't pay for.
+func (c *Client) CreateSSLCertificate(ctx context.Context, certificateType, years int, sansToAdd string) (int, error) {
+	if typeName, ok := SSLTypeName(certificateType); ok {
+		if err := c.checkFunds(ctx, productTypeSSLCertificate, actionPurchase, typeName, years); err != nil {
+			return 0, err
+		}
+	}
+
+	resp, err := c.makeRequest(ctx, CommandSSLCreate, newParams().
+		setInt("Type", certificateType).
+		setInt("Years", years).
+		setOptional("SANStoAdd", sansToAdd))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to make ssl.create request")
+	}
+
+	var result SSLCreateResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return 0, errors.Wrap(err, "failed to parse ssl.create response")
+	}
+
+	if !result.CommandResponse.SSLCreateResult.IsSuccess {
+		return 0, errors.New("SSL certificate creation failed")
+	}
+
+	return result.CommandResponse.SSLCreateResult.SSLCertificate.CertificateID, nil
+}
+
+// Domain control validation methods of a SAN
+const (
+	DCVMethodEmail = "Email"
+	DCVMethodHTTP  = "HTTP"
+	DCVMethodDNS   = "DNS"
+)
+
+// ActivationRequest is how a certificate is activated: with its CSR, and
+// how control of its primary domain and of each SAN is validated
+type ActivationRequest struct {
+	CSR string
+
+	// DomainName is the primary domain, validated by ApproverEmail unless
+	// HTTPDCValidation or DNSValidation is set
+	DomainName       string
+	ApproverEmail    string
+	HTTPDCValidation string
+	DNSValidation    string
+	WebServerType    string
+
+	// SANs are the certificate's additional domains, each validated on
+	// its own
+	SANs []SANActivation
+}
+
+// SANActivation is how control of a SAN is validated
+type SANActivation struct {
+	DomainName string
+
+	// Method is one of the DCVMethods, and defaults to DCVMethodEmail
+	Method string
+
+	// ApproverEmail is emailed to validate the SAN by DCVMethodEmail
+	ApproverEmail string
+}
+
+// approver returns the value ssl.activate takes for the SAN in
+// DNSApproverEmails: its approver email, or the placeholder of its
+// validation method
+func (s SANActivation) approver() (string, error) {
+	switch s.Method {
+	case "", DCVMethodEmail:
+		if s.ApproverEmail == "" {
+			return "", errors.Errorf("SAN %s is validated by email, but has no approver email", s.DomainName)
+		}
+		return s.ApproverEmail, nil
+	case DCVMethodHTTP:
+		return "HTTPCSRHASH", nil
+	case DCVMethodDNS:
+		return "CNAMECSRHASH", nil
+	default:
+		return "", errors.Errorf("invalid validation method %q for SAN %s", s.Method, s.DomainName)
+	}
+}
+
+// params returns the ssl.activate parameters of the request. The SANs
+// and their approvers are sent as aligned comma-separated lists.
+func (r ActivationRequest) params(certificateID int) (*params, error) {
+	p := newParams().
+		setInt("CertificateID", certificateID).
+		set("CSR", r.CSR).
+		set("DomainName", r.DomainName).
+		set("ApproverEmail", r.ApproverEmail).
+		setOptional("HTTPDCValidation", r.HTTPDCValidation).
+		setOptional("DNSValidation", r.DNSValidation).
+		setOptional("WebServerType", r.WebServerType)
+	if len(r.SANs) == 0 {
+		return p, nil
+	}
+
+	names := make([]string, len(r.SANs))
+	approvers := make([]string, len(r.SANs))
+	for i, san := range r.SANs {
+		approver, err := san.approver()
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(san.DomainName, ",") || strings.Contains(approver, ",") {
+			return nil, errors.Errorf("SAN %s can't contain a comma", san.DomainName)
+		}
+		names[i], approvers[i] = san.DomainName, approver
+	}
+	return p.set("DNSNames", strings.Join(names, ",")).
+		set("DNSApproverEmails", strings.Join(approvers, ",")), nil
+}
+
+// ActivateSSLCertificate activates an SSL certificate as req describes
+func (c *Client) ActivateSSLCertificate(ctx context.Context, certificateID int, req ActivationRequest) error {
+	params, err := req.params(certificateID)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.makeRequest(ctx, CommandSSLActivate, params)
+	if err != nil {
+		return errors.Wrap(err, "failed to make ssl.activate request")
+	}
+
+	var result SSLActivateResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse ssl.activate response")
+	}
+
+	if !result.CommandResponse.SSLActivateResult.IsSuccess {
+		return errors.New("SSL certificate activation failed")
+	}
+
+	return nil
+}
+
+// GetSSLCertificate retrieves detailed information about a specific SSL certificate
+func (c *Client) GetSSLCertificate(ctx context.Context, certificateID int) (*SSLGetInfoResponse, error) {
+	return c.GetSSLCertificateWithOptions(ctx, certificateID, SSLInfoOptions{})
+}
+
+// GetSSLCertificateWithOptions retrieves information about a specific SSL
+// certificate, with what opts selects
+func (c *Client) GetSSLCertificateWithOptions(ctx context.Context, certificateID int, opts SSLInfoOptions) (*SSLGetInfoResponse, error) {
+	params := newParams().setInt("CertificateID", certificateID)
+	if opts.ReturnCertificate {
+		params.set("Returncertificate", "true").set("Returntype", "Individual")
+	}
+
+	resp, err := c.makeRequest(ctx, CommandSSLGetInfo, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make ssl.getInfo request")
+	}
+
+	var result SSLGetInfoResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse ssl.getInfo response")
+	}
+
+	return &result, nil
+}
+
+// GetSSLApproverEmailList retrieves the addresses accepted as the approver
+// of a certificate of the given type for domainName
+func (c *Client) GetSSLApproverEmailList(ctx context.Context, domainName string, certificateType int) (*SSLApproverEmails, error) {
+	resp, err := c.makeRequest(ctx, CommandSSLGetApproverEmailList, newParams().
+		set("DomainName", domainName).
+		setInt("CertificateType", certificateType))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make ssl.getApproverEmailList request")
+	}
+
+	var result SSLApproverEmailListResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse ssl.getApproverEmailList response")
+	}
+
+	list := result.CommandResponse.GetApproverEmailListResult
+	return &SSLApproverEmails{Domain: list.DomainEmails, Generic: list.GenericEmails}, nil
+}
+
+// RenewSSLCertificate renews a certificate of the given type for the
+// specified number of years. promoCode is optional.
+func (c *Client) RenewSSLCertificate(ctx context.Context, certificateID int, sslType string, years int, promoCode string) (*SSLRenewal, error) {
+	resp, err := c.makeRequest(ctx, CommandSSLRenew, newParams().
+		setInt("CertificateID", certificateID).
+		setRequired("SSLType", sslType).
+		setInt("Years", years).
+		setOptional("PromotionCode", promoCode))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make ssl.renew request")
+	}
+
+	var result SSLRenewResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse ssl.renew response")
+	}
+
+	renewed := result.CommandResponse.SSLRenewResult
+	if renewed.CertificateID == 0 {
+		return nil, errors.New("SSL certificate renewal failed")
+	}
+
+	return &SSLRenewal{
+		CertificateID: renewed.CertificateID,
+		ChargedAmount: renewed.ChargedAmount,
+		OrderID:       renewed.OrderID,
+		TransactionID: renewed.TransactionID,
+	}, nil
+}
+
+// RevokeSSLCertificate revokes a certificate of the given type. A revoked
+// certificate can't be used again.
+func (c *Client) RevokeSSLCertificate(ctx context.Context, certificateID, certificateType int) error {
+	resp, err := c.makeRequest(ctx, CommandSSLRevoke, newParams().
+		setInt("CertificateID", certificateID).
+		setInt("CertificateType", certificateType))
+	if err != nil {
+		return errors.Wrap(err, "failed to make ssl.revoke request")
+	}
+
+	var result SSLRevokeResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse ssl.revoke response")
+	}
+
+	if !result.CommandResponse.RevokeCertificateResult.IsSuccess {
+		return errors.New("SSL certificate revocation failed")
+	}
+
+	return nil
+}
+
+// EditSSLDCValidation switches the domain control validation of an
+// activated certificate's dnsNames to DNS, e.g. when an activation is stuck
+// on email validation, returning the CNAME records to create
+func (c *Client) EditSSLDCValidation(ctx context.Context, certificateID int, dnsNames []string) ([]DNSValidationRecord, error) {
+	if len(dnsNames) == 0 {
+		return nil, errors.New("at least one DNS name must be provided")
+	}
+	methods := make([]string, len(dnsNames))
+	for i := range methods {
+		methods[i] = dcvMethodCNAME
+	}
+
+	resp, err := c.makeRequest(ctx, CommandSSLEditDCValidation, newParams().
+		setInt("CertificateID", certificateID).
+		set("DNSNames", strings.Join(dnsNames, ",")).
+		set("DCVMethods", strings.Join(methods, ",")))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make ssl.editDCValidation request")
+	}
+
+	var result SSLEditDCValidationResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse ssl.editDCValidation response")
+	}
+
+	if !result.CommandResponse.SSLEditDCVResult.IsSuccess {
+		return nil, errors.New("SSL domain control validation change failed")
+	}
+
+	return result.CommandResponse.SSLEditDCVResult.DNSValidationRecords, nil
+}
+
+// ParseCSR decodes csr as Namecheap does when activating a certificate of
+// the given type, so that a request it can't use is found before the
+// activation. certificateType is optional.
+func (c *Client) ParseCSR(ctx context.Context, csr string, certificateType int) (*CSRDetails, error) {
+	params := newParams().set("csr", csr)
+	if certificateType > 0 {
+		params.setInt("CertificateType", certificateType)
+	}
+	resp, err := c.makeRequest(ctx, CommandSSLParseCSR, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make ssl.parseCSR request")
+	}
+
+	var result SSLParseCSRResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse ssl.parseCSR response")
+	}
+
+	details := &CSRDetails{
+		CommonName: result.CommandResponse.SSLParseCSRResult.CSRDetails.CommonName,
+		Warnings:   result.Warnings,
+	}
+	request, err := decodeCSR(csr)
+	if err != nil {
+		details.Warnings = append(details.Warnings, err.Error())
+		return details, nil
+	}
+	details.SANs = request.DNSNames
+	details.KeySize = keySize(request.PublicKey)
+	return details, nil
+}
+
+// IsCSRRejected reports whether err is Namecheap refusing to parse a CSR,
+// rather than a failure to reach Namecheap
+func IsCSRRejected(err error) bool {
+	var ncErr Error
+	return errors.As(err, &ncErr)
+}
+
+// decodeCSR decodes a PEM encoded certificate signing request
+func decodeCSR(csr string) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode([]byte(csr))
+	if block == nil {
+		return nil, errors.New("cannot decode CSR: no PEM block found")
+	}
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot decode CSR")
+	}
+	return request, nil
+}
+
+// keySize returns the size in bits of key, or 0 for an unknown kind of key
+func keySize(key interface{}) int {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return k.N.BitLen()
+	case *ecdsa.PublicKey:
+		return k.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return len(k) * 8
+	}
+	return 0
+}
+
+// ResendSSLApprovalEmail resends the SSL certificate approval email
+func (c *Client) ResendSSLApprovalEmail(ctx context.Context, certificateID int) error {
+	resp, err := c.makeRequest(ctx, CommandSSLResend, newParams().
+		setInt("CertificateID", certificateID))
+	if err != nil {
+		return errors.Wrap(err, "failed to make ssl.resend request")
+	}
+
+	var result SSLResendResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse ssl.resend response")
+	}
+
+	if !result.CommandResponse.SSLResendResult.IsSuccess {
+		return errors.New("failed to resend SSL approval email")
+	}
+
+	return nil
+}
+
+// ReissueSSLCertificate reissues an SSL certificate
+func (c *Client) ReissueSSLCertificate(ctx context.Context, certificateID int, csr, approverEmail string) error {
+	resp, err := c.makeRequest(ctx, CommandSSLReissue, newParams().
+		setInt("CertificateID", certificateID).
+		set("CSR", csr).
+		set("ApproverEmail", approverEmail))
+	if err != nil {
+		return errors.Wrap(err, "failed to make ssl.reissue request")
+	}
+
+	var result SSLReissueResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse ssl.reissue response")
+	}
+
+	if !result.CommandResponse.SSLReissueResult.IsSuccess {
+		return errors.New("SSL certificate reissue failed")
+	}
+
+	return nil
+}
+
+// GetSSLCertificatesByDomain retrieves SSL certificates for a specific domain
+func (c *Client) GetSSLCertificatesByDomain(ctx context.Context, domainName string) ([]SSLCertificate, error) {
+	// Namecheap only lists the certificates whose host name contains the
+	// domain, which are then narrowed to the domain and its subdomains
+	certificates, err := c.GetSSLCertificatesWithOptions(ctx, SSLListOptions{SearchTerm: domainName})
+	if err != nil {
+		return nil, err
+	}
+
+	var domainCertificates []SSLCertificate
+	for _, cert := range certificates {
+		if strings.EqualFold(cert.HostName, domainName) ||
+			strings.HasSuffix(strings.ToLower(cert.HostName), "."+strings.ToLower(domainName)) {
+			domainCertificates = append(domainCertificates, cert)
+		}
+	}
+
+	return domainCertificates, nil
+}
+
+// SSLCertificateExists checks if an SSL certificate exists for a domain
+func (c *Client) SSLCertificateExists(ctx context.Context, domainName string) (bool, error) {
+	certificates, err := c.GetSSLCertificatesByDomain(ctx, domainName)
+	if err != nil {
+		return false, err
+	}
+
+	return len(certificates) > 0, nil
+}
@@ -0,0 +1,838 @@
+package namecheap
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetSSLCertificates(t *testing.T) {
+	responseXML := `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<SSLListResult>
+			<SSL CertificateID="123" HostName="example.com" SSLType="PositiveSSL" PurchaseDate="01/01/2024" ExpireDate="01/01/2025" ActivationExpireDate="12/01/2024" IsExpiredYN="false" Status="ACTIVE" StatusDescription="Certificate is active" Years="1"/>
+			<SSL CertificateID="124" HostName="test.com" SSLType="EssentialSSL" PurchaseDate="01/01/2024" ExpireDate="01/01/2025" ActivationExpireDate="12/01/2024" IsExpiredYN="false" Status="PENDING" StatusDescription="Certificate is pending activation" Years="1"/>
+		</SSLListResult>
+	</CommandResponse>
+</ApiResponse>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "namecheap.ssl.getList", r.FormValue("Command"))
+		assert.Equal(t, "100", r.FormValue("PageSize"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(responseXML))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	config := Config{
+		APIUser:  "testuser",
+		APIKey:   "testkey",
+		Username: "testuser",
+		ClientIP: "127.0.0.1",
+		BaseURL:  server.URL,
+		HTTPClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+	client := NewClient(config)
+
+	certificates, err := client.GetSSLCertificates(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, certificates, 2)
+
+	// Check first certificate
+	assert.Equal(t, 123, certificates[0].CertificateID)
+	assert.Equal(t, "example.com", certificates[0].HostName)
+	assert.Equal(t, "PositiveSSL", certificates[0].SSLType)
+	assert.Equal(t, "ACTIVE", certificates[0].Status)
+	assert.False(t, certificates[0].IsExpiredYN)
+
+	// Check second certificate
+	assert.Equal(t, 124, certificates[1].CertificateID)
+	assert.Equal(t, "test.com", certificates[1].HostName)
+	assert.Equal(t, "EssentialSSL", certificates[1].SSLType)
+	assert.Equal(t, "PENDING", certificates[1].Status)
+}
+
+func TestClient_CreateSSLCertificate(t *testing.T) {
+	tests := []struct {
+		name            string
+		certificateType int
+		years           int
+		sansToAdd       string
+		responseXML     string
+		expectedCertID  int
+		expectedError   string
+	}{
+		{
+			name:            "successful creation",
+			certificateType: 1,
+			years:           1,
+			sansToAdd:       "",
+			responseXML:     string(commandFixture(t, CommandSSLCreate)),
+			expectedCertID:  52556,
+		},
+		{
+			name:            "successful creation with SANs",
+			certificateType: 2,
+			years:           2,
+			sansToAdd:       "www.example.com,mail.example.com",
+			responseXML:     string(commandFixture(t, CommandSSLCreate)),
+			expectedCertID:  52556,
+		},
+		{
+			name:            "failed creation",
+			certificateType: 1,
+			years:           1,
+			sansToAdd:       "",
+			responseXML: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<SSLCreateResult IsSuccess="false"/>
+	</CommandResponse>
+</ApiResponse>`,
+			expectedError: "SSL certificate creation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "POST", r.Method)
+				assert.Equal(t, "namecheap.ssl.create", r.FormValue("Command"))
+				assert.Equal(t, string(rune(tt.certificateType+'0')), r.FormValue("Type"))
+				assert.Equal(t, string(rune(tt.years+'0')), r.FormValue("Years"))
+
+				if tt.sansToAdd != "" {
+					assert.Equal(t, tt.sansToAdd, r.FormValue("SANStoAdd"))
+				}
+
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(tt.responseXML))
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			config := Config{
+				APIUser:  "testuser",
+				APIKey:   "testkey",
+				Username: "testuser",
+				ClientIP: "127.0.0.1",
+				BaseURL:  server.URL,
+				HTTPClient: &http.Client{
+					Timeout: 5 * time.Second,
+				},
+			}
+			client := NewClient(config)
+
+			certID, err := client.CreateSSLCertificate(context.Background(), tt.certificateType, tt.years, tt.sansToAdd)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.Equal(t, 0, certID)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedCertID, certID)
+			}
+		})
+	}
+}
+
+func TestClient_ActivateSSLCertificate(t *testing.T) {
+	tests := []struct {
+		name             string
+		certificateID    int
+		csr              string
+		domainName       string
+		approverEmail    string
+		httpDCValidation string
+		dnsValidation    string
+		webServerType    string
+		responseXML      string
+		expectedError    string
+	}{
+		{
+			name:          "successful activation",
+			certificateID: 123,
+			csr:           "-----BEGIN CERTIFICATE REQUEST-----\nMIICZjCCAU4...\n-----END CERTIFICATE REQUEST-----",
+			domainName:    "example.com",
+			approverEmail: "admin@example.com",
+			responseXML: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<SSLActivateResult IsSuccess="true" ID="123"/>
+	</CommandResponse>
+</ApiResponse>`,
+		},
+		{
+			name:          "activation with DNS validation",
+			certificateID: 123,
+			csr:           "-----BEGIN CERTIFICATE REQUEST-----\nMIICZjCCAU4...\n-----END CERTIFICATE REQUEST-----",
+			domainName:    "example.com",
+			approverEmail: "admin@example.com",
+			dnsValidation: "DNS_CNAME",
+			webServerType: "Apache",
+			responseXML: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<SSLActivateResult IsSuccess="true" ID="123"/>
+	</CommandResponse>
+</ApiResponse>`,
+		},
+		{
+			name:          "failed activation",
+			certificateID: 123,
+			csr:           "invalid-csr",
+			domainName:    "example.com",
+			approverEmail: "admin@example.com",
+			responseXML: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<SSLActivateResult IsSuccess="false"/>
+	</CommandResponse>
+</ApiResponse>`,
+			expectedError: "SSL certificate activation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "POST", r.Method)
+				assert.Equal(t, "namecheap.ssl.activate", r.FormValue("Command"))
+				assert.Equal(t, "123", r.FormValue("CertificateID"))
+				assert.Equal(t, tt.csr, r.FormValue("CSR"))
+				assert.Equal(t, tt.domainName, r.FormValue("DomainName"))
+				assert.Equal(t, tt.approverEmail, r.FormValue("ApproverEmail"))
+
+				if tt.dnsValidation != "" {
+					assert.Equal(t, tt.dnsValidation, r.FormValue("DNSValidation"))
+				}
+				if tt.webServerType != "" {
+					assert.Equal(t, tt.webServerType, r.FormValue("WebServerType"))
+				}
+
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(tt.responseXML))
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			config := Config{
+				APIUser:  "testuser",
+				APIKey:   "testkey",
+				Username: "testuser",
+				ClientIP: "127.0.0.1",
+				BaseURL:  server.URL,
+				HTTPClient: &http.Client{
+					Timeout: 5 * time.Second,
+				},
+			}
+			client := NewClient(config)
+
+			err := client.ActivateSSLCertificate(context.Background(), tt.certificateID, ActivationRequest{
+				CSR:              tt.csr,
+				DomainName:       tt.domainName,
+				ApproverEmail:    tt.approverEmail,
+				HTTPDCValidation: tt.httpDCValidation,
+				DNSValidation:    tt.dnsValidation,
+				WebServerType:    tt.webServerType,
+			})
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClient_ActivateSSLCertificate_LargeCSR(t *testing.T) {
+	csr := "-----BEGIN CERTIFICATE REQUEST-----\n" +
+		strings.Repeat(strings.Repeat("A", 63)+"\n", 64) +
+		"-----END CERTIFICATE REQUEST-----"
+	require.Greater(t, len(csr), 4096)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The CSR and the credentials travel in the body, not the URL
+		assert.Equal(t, "POST", r.Method)
+		assert.Empty(t, r.URL.RawQuery)
+		assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, csr, r.PostForm.Get("CSR"))
+		assert.Equal(t, "testkey", r.PostForm.Get("ApiKey"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write(commandFixture(t, CommandSSLActivate))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	err := client.ActivateSSLCertificate(context.Background(), 123, ActivationRequest{
+		CSR:           csr,
+		DomainName:    "example.com",
+		ApproverEmail: "admin@example.com",
+	})
+	assert.NoError(t, err)
+}
+
+func TestClient_ActivateSSLCertificate_SANs(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		form = r.PostForm
+
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write(commandFixture(t, CommandSSLActivate))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	// Each SAN's approver is aligned with it, with the placeholders of
+	// HTTP and DNS validation
+	err := client.ActivateSSLCertificate(context.Background(), 123, ActivationRequest{
+		CSR:           "csr",
+		DomainName:    "example.com",
+		ApproverEmail: "admin@example.com",
+		SANs: []SANActivation{
+			{DomainName: "www.example.com", ApproverEmail: "hostmaster@example.com"},
+			{DomainName: "shop.example.net", Method: DCVMethodHTTP},
+			{DomainName: "api.example.org", Method: DCVMethodDNS},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "www.example.com,shop.example.net,api.example.org", form.Get("DNSNames"))
+	assert.Equal(t, "hostmaster@example.com,HTTPCSRHASH,CNAMECSRHASH", form.Get("DNSApproverEmails"))
+
+	// A single-domain activation sends neither list
+	err = client.ActivateSSLCertificate(context.Background(), 123, ActivationRequest{
+		CSR: "csr", DomainName: "example.com", ApproverEmail: "admin@example.com",
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, form, "DNSNames")
+	assert.NotContains(t, form, "DNSApproverEmails")
+
+	// SANs that can't be encoded are rejected before any request
+	for _, san := range []SANActivation{
+		{DomainName: "www.example.com"},
+		{DomainName: "www.example.com", Method: "FAX"},
+		{DomainName: "www.example.com,example.net", Method: DCVMethodDNS},
+	} {
+		form = nil
+		err = client.ActivateSSLCertificate(context.Background(), 123, ActivationRequest{
+			CSR: "csr", DomainName: "example.com", ApproverEmail: "admin@example.com", SANs: []SANActivation{san},
+		})
+		assert.Error(t, err, san)
+		assert.Nil(t, form)
+	}
+}
+
+func TestClient_GetSSLCertificate(t *testing.T) {
+	responseXML := `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<SSLGetInfoResult Status="active" StatusDescription="Certificate is active" Type="PositiveSSL" IssuedOn="01/01/2024" Expires="01/01/2025" ActivationExpireDate="12/01/2024" OrderId="1234" ReplacedBy="0" SANSCount="0">
+			<CertificateDetails>
+				<CommonName>example.com</CommonName>
+				<ApproverEmail>admin@example.com</ApproverEmail>
+			</CertificateDetails>
+			<Provider>
+				<OrderID>12345678</OrderID>
+				<Name>COMODO</Name>
+			</Provider>
+		</SSLGetInfoResult>
+	</CommandResponse>
+</ApiResponse>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "namecheap.ssl.getInfo", r.FormValue("Command"))
+		assert.Equal(t, "123", r.FormValue("CertificateID"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(responseXML))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	config := Config{
+		APIUser:  "testuser",
+		APIKey:   "testkey",
+		Username: "testuser",
+		ClientIP: "127.0.0.1",
+		BaseURL:  server.URL,
+		HTTPClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+	client := NewClient(config)
+
+	cert, err := client.GetSSLCertificate(context.Background(), 123)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, cert)
+
+	result := cert.CommandResponse.SSLGetInfoResult
+	assert.Equal(t, "example.com", result.CertificateDetails.CommonName)
+	assert.Equal(t, "PositiveSSL", result.Type)
+	assert.Equal(t, "active", result.Status)
+	assert.Equal(t, 1234, result.OrderID)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), result.Expires.Time)
+	assert.Equal(t, "COMODO", result.Provider.Name)
+	assert.Equal(t, "admin@example.com", result.CertificateDetails.ApproverEmail)
+}
+
+func TestClient_GetSSLCertificateWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "namecheap.ssl.getInfo", r.FormValue("Command"))
+		assert.Equal(t, "true", r.FormValue("Returncertificate"))
+		assert.Equal(t, "Individual", r.FormValue("Returntype"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write(commandFixture(t, CommandSSLGetInfo))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	cert, err := client.GetSSLCertificateWithOptions(context.Background(), 123, SSLInfoOptions{ReturnCertificate: true})
+	require.NoError(t, err)
+
+	const (
+		leaf         = "-----BEGIN CERTIFICATE-----\nMIIBleafexamplecom\n-----END CERTIFICATE-----\n"
+		intermediate = "-----BEGIN CERTIFICATE-----\nMIIBintermediate\n-----END CERTIFICATE-----\n"
+		root         = "-----BEGIN CERTIFICATE-----\nMIIBroot\n-----END CERTIFICATE-----\n"
+	)
+	issued := cert.CommandResponse.SSLGetInfoResult.CertificateDetails.Certificates
+	assert.True(t, issued.Returned)
+	assert.Equal(t, "INDIVIDUAL", issued.ReturnType)
+	assert.Equal(t, leaf, issued.CertificatePEM())
+	assert.Equal(t, intermediate+root, issued.CABundle())
+	assert.Equal(t, leaf+intermediate, issued.Chain())
+}
+
+func TestClient_GetSSLCertificatesByDomain(t *testing.T) {
+	responseXML := `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<SSLListResult>
+			<SSL CertificateID="123" HostName="example.com" SSLType="PositiveSSL" Status="ACTIVE" PurchaseDate="01/01/2024" ExpireDate="01/01/2025" ActivationExpireDate="12/01/2024" IsExpiredYN="false" StatusDescription="Certificate is active" Years="1"/>
+			<SSL CertificateID="124" HostName="www.example.com" SSLType="EssentialSSL" Status="ACTIVE" PurchaseDate="01/01/2024" ExpireDate="01/01/2025" ActivationExpireDate="12/01/2024" IsExpiredYN="false" StatusDescription="Certificate is active" Years="1"/>
+			<SSL CertificateID="125" HostName="test.com" SSLType="PositiveSSL" Status="ACTIVE" PurchaseDate="01/01/2024" ExpireDate="01/01/2025" ActivationExpireDate="12/01/2024" IsExpiredYN="false" StatusDescription="Certificate is active" Years="1"/>
+			<SSL CertificateID="126" HostName="mail.example.com" SSLType="WildcardSSL" Status="PENDING" PurchaseDate="01/01/2024" ExpireDate="01/01/2025" ActivationExpireDate="12/01/2024" IsExpiredYN="false" StatusDescription="Certificate is pending" Years="1"/>
+		</SSLListResult>
+	</CommandResponse>
+</ApiResponse>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(responseXML))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	config := Config{
+		APIUser:  "testuser",
+		APIKey:   "testkey",
+		Username: "testuser",
+		ClientIP: "127.0.0.1",
+		BaseURL:  server.URL,
+		HTTPClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+	client := NewClient(config)
+
+	// Test finding certificates for exact domain match
+	certs, err := client.GetSSLCertificatesByDomain(context.Background(), "example.com")
+	assert.NoError(t, err)
+	assert.Len(t, certs, 3) // example.com, www.example.com, mail.example.com
+
+	// Verify the certificates returned
+	certIDs := make([]int, len(certs))
+	for i, cert := range certs {
+		certIDs[i] = cert.CertificateID
+	}
+	assert.Contains(t, certIDs, 123) // example.com
+	assert.Contains(t, certIDs, 124) // www.example.com
+	assert.Contains(t, certIDs, 126) // mail.example.com
+
+	// Test finding certificates for different domain
+	certs, err = client.GetSSLCertificatesByDomain(context.Background(), "test.com")
+	assert.NoError(t, err)
+	assert.Len(t, certs, 1)
+	assert.Equal(t, 125, certs[0].CertificateID)
+
+	// Test domain with no certificates
+	certs, err = client.GetSSLCertificatesByDomain(context.Background(), "notfound.com")
+	assert.NoError(t, err)
+	assert.Len(t, certs, 0)
+}
+
+func TestClient_GetSSLCertificatesByDomain_Pages(t *testing.T) {
+	// Two pages of certificates; the one for example.com is on page 2
+	page := func(totalItems int, hostNames ...string) string {
+		var b strings.Builder
+		for i, hostName := range hostNames {
+			fmt.Fprintf(&b, `<SSL CertificateID="%d" HostName="%s" SSLType="PositiveSSL" Status="ACTIVE" IsExpiredYN="false" Years="1"/>`, 1000+i, hostName)
+		}
+		return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<SSLListResult>%s</SSLListResult>
+		<Paging><TotalItems>%d</TotalItems><CurrentPage>1</CurrentPage><PageSize>100</PageSize></Paging>
+	</CommandResponse>
+</ApiResponse>`, b.String(), totalItems)
+	}
+
+	firstPage := make([]string, 100)
+	for i := range firstPage {
+		firstPage[i] = fmt.Sprintf("site%d.net", i)
+	}
+	pages := []string{page(101, firstPage...), page(101, "www.example.com")}
+
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "namecheap.ssl.getList", r.FormValue("Command"))
+		assert.Equal(t, "100", r.FormValue("PageSize"))
+		assert.Equal(t, "example.com", r.FormValue("SearchTerm"))
+		requested = append(requested, r.FormValue("Page"))
+
+		n, err := strconv.Atoi(r.FormValue("Page"))
+		require.NoError(t, err)
+		require.LessOrEqual(t, n, len(pages))
+		w.Header().Set("Content-Type", "application/xml")
+		_, err = w.Write([]byte(pages[n-1]))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	certs, err := client.GetSSLCertificatesByDomain(context.Background(), "example.com")
+	require.NoError(t, err)
+	require.Len(t, certs, 1)
+	assert.Equal(t, "www.example.com", certs[0].HostName)
+	assert.Equal(t, []string{"1", "2"}, requested)
+}
+
+func TestClient_GetSSLCertificatesWithOptions(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "namecheap.ssl.getList", r.FormValue("Command"))
+		query = url.Values{}
+		for _, key := range []string{"ListType", "SearchTerm", "SortBy", "Page", "PageSize"} {
+			if values, ok := r.URL.Query()[key]; ok {
+				query[key] = values
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<SSLListResult>
+			<SSL CertificateID="123" HostName="shop.example.com" SSLType="PositiveSSL" Status="ACTIVE" IsExpiredYN="false" Years="1"/>
+		</SSLListResult>
+		<Paging><TotalItems>1</TotalItems><CurrentPage>1</CurrentPage><PageSize>100</PageSize></Paging>
+	</CommandResponse>
+</ApiResponse>`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	certs, err := client.GetSSLCertificatesWithOptions(context.Background(),
+		SSLListOptions{ListType: SSLListActive, SearchTerm: "shop", SortBy: SSLSortExpireDateTime})
+	require.NoError(t, err)
+	require.Len(t, certs, 1)
+	assert.Equal(t, url.Values{
+		"ListType": {"Active"}, "SearchTerm": {"shop"}, "SortBy": {"EXPIREDATETIME"}, "Page": {"1"}, "PageSize": {"100"},
+	}, query)
+
+	// Unknown list types and sort orders are rejected before any request
+	query = nil
+	_, err = client.GetSSLCertificatesWithOptions(context.Background(), SSLListOptions{ListType: "Expiring"})
+	assert.EqualError(t, err, `invalid SSL certificate list type "Expiring"`)
+	_, err = client.GetSSLCertificatesWithOptions(context.Background(), SSLListOptions{SortBy: "NAME"})
+	assert.EqualError(t, err, `invalid SSL certificate sort order "NAME"`)
+	assert.Nil(t, query)
+}
+
+func TestClient_ResendSSLApprovalEmail(t *testing.T) {
+	responseXML := `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<SSLResendResult IsSuccess="true"/>
+	</CommandResponse>
+</ApiResponse>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "namecheap.ssl.resend", r.FormValue("Command"))
+		assert.Equal(t, "123", r.FormValue("CertificateID"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(responseXML))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	config := Config{
+		APIUser:  "testuser",
+		APIKey:   "testkey",
+		Username: "testuser",
+		ClientIP: "127.0.0.1",
+		BaseURL:  server.URL,
+		HTTPClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+	client := NewClient(config)
+
+	err := client.ResendSSLApprovalEmail(context.Background(), 123)
+	assert.NoError(t, err)
+}
+func TestClient_GetSSLApproverEmailList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "namecheap.ssl.getApproverEmailList", r.FormValue("Command"))
+		assert.Equal(t, "example.com", r.FormValue("DomainName"))
+		assert.Equal(t, "1", r.FormValue("CertificateType"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write(commandFixture(t, CommandSSLGetApproverEmailList))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	emails, err := client.GetSSLApproverEmailList(context.Background(), "example.com", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"owner@example.net"}, emails.Domain)
+	assert.Equal(t, []string{
+		"admin@example.com",
+		"administrator@example.com",
+		"hostmaster@example.com",
+		"postmaster@example.com",
+		"webmaster@example.com",
+	}, emails.Generic)
+	assert.Equal(t, "owner@example.net", emails.All()[0])
+	assert.Len(t, emails.All(), 6)
+
+	// Addresses are accepted whatever their case
+	assert.True(t, emails.Accepts("Admin@Example.com"))
+	assert.True(t, emails.Accepts("owner@example.net"))
+	assert.False(t, emails.Accepts("info@example.com"))
+}
+
+func TestClient_ParseCSR(t *testing.T) {
+	csr := newCSR(t, "example.com", "www.example.com")
+
+	// Namecheap decodes the common name and the rest is decoded locally
+	client := newFixtureClient(t, nil)
+	details, err := client.ParseCSR(context.Background(), csr, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", details.CommonName)
+	assert.Equal(t, []string{"example.com", "www.example.com"}, details.SANs)
+	assert.Equal(t, 256, details.KeySize)
+	assert.Empty(t, details.Warnings)
+
+	// A request that can't be decoded locally is only a warning, as
+	// Namecheap decides what it accepts
+	details, err = client.ParseCSR(context.Background(), "not a CSR", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cannot decode CSR: no PEM block found"}, details.Warnings)
+
+	// A request Namecheap can't parse is refused
+	client = newFixtureClient(t, map[Command]string{CommandSSLParseCSR: "error.invalidCSR"})
+	_, err = client.ParseCSR(context.Background(), csr, 1)
+	require.Error(t, err)
+	assert.True(t, IsCSRRejected(err))
+	assert.Contains(t, err.Error(), "Invalid CSR")
+}
+
+// newCSR returns a PEM encoded certificate signing request for commonName,
+// listing it and sans as SANs, with a new P-256 key
+func newCSR(t *testing.T, commonName string, sans ...string) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: commonName},
+		DNSNames: append([]string{commonName}, sans...),
+	}, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+func TestClient_RenewSSLCertificate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "namecheap.ssl.renew", r.FormValue("Command"))
+		assert.Equal(t, "52556", r.FormValue("CertificateID"))
+		assert.Equal(t, "PositiveSSL", r.FormValue("SSLType"))
+		assert.Equal(t, "2", r.FormValue("Years"))
+		assert.Equal(t, "SAVE10", r.FormValue("PromotionCode"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write(commandFixture(t, CommandSSLRenew))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	renewal, err := client.RenewSSLCertificate(context.Background(), 52556, "PositiveSSL", 2, "SAVE10")
+	require.NoError(t, err)
+	assert.Equal(t, &SSLRenewal{CertificateID: 52557, ChargedAmount: 9, OrderID: 1235, TransactionID: 5679}, renewal)
+
+	// The certificate type is required
+	_, err = client.RenewSSLCertificate(context.Background(), 52556, "", 2, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SSLType")
+}
+
+func TestClient_RevokeSSLCertificate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "namecheap.ssl.revoke", r.FormValue("Command"))
+		assert.Equal(t, "52556", r.FormValue("CertificateID"))
+		assert.Equal(t, "1", r.FormValue("CertificateType"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write(commandFixture(t, CommandSSLRevoke))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	require.NoError(t, client.RevokeSSLCertificate(context.Background(), 52556, 1))
+}
+
+func TestClient_EditSSLDCValidation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "namecheap.ssl.editDCValidation", r.FormValue("Command"))
+		assert.Equal(t, "52556", r.FormValue("CertificateID"))
+		assert.Equal(t, "example.com,www.example.com", r.FormValue("DNSNames"))
+		assert.Equal(t, "CNAME_CSR_HASH,CNAME_CSR_HASH", r.FormValue("DCVMethods"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write(commandFixture(t, CommandSSLEditDCValidation))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	records, err := client.EditSSLDCValidation(context.Background(), 52556, []string{"example.com", "www.example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, []DNSValidationRecord{
+		{Domain: "example.com", HostName: "_0a1b2c3d4e5f.example.com", Target: "0a1b2c3d.4e5f6a7b.comodoca.com"},
+		{Domain: "www.example.com", HostName: "_0a1b2c3d4e5f.www.example.com", Target: "0a1b2c3d.4e5f6a7b.comodoca.com"},
+	}, records)
+
+	// Some name must be validated
+	_, err = client.EditSSLDCValidation(context.Background(), 52556, nil)
+	require.Error(t, err)
+}
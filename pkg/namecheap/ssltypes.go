@@ -0,0 +1,75 @@
+package namecheap
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SSL certificate types by the names Namecheap sells them under
+const (
+	SSLTypePositiveSSL            = "PositiveSSL"
+	SSLTypeEssentialSSL           = "EssentialSSL"
+	SSLTypeInstantSSL             = "InstantSSL"
+	SSLTypeInstantSSLPro          = "InstantSSL Pro"
+	SSLTypePremiumSSL             = "PremiumSSL"
+	SSLTypeEVSSL                  = "EV SSL"
+	SSLTypePositiveSSLMultiDomain = "PositiveSSL Multi-Domain"
+	SSLTypeMultiDomainSSL         = "Multi-Domain SSL"
+	SSLTypeUnifiedCommunications  = "Unified Communications"
+	SSLTypeEVMultiDomainSSL       = "EV Multi-Domain SSL"
+	SSLTypePositiveSSLWildcard    = "PositiveSSL Wildcard"
+	SSLTypeEssentialSSLWildcard   = "EssentialSSL Wildcard"
+	SSLTypePremiumSSLWildcard     = "PremiumSSL Wildcard"
+)
+
+// sslTypeIDs maps the SSL certificate type names to the numeric type IDs
+// ssl.create and the other certificate commands take
+var sslTypeIDs = map[string]int{
+	SSLTypePositiveSSL:            1,
+	SSLTypeEssentialSSL:           2,
+	SSLTypeInstantSSL:             3,
+	SSLTypeInstantSSLPro:          4,
+	SSLTypePremiumSSL:             5,
+	SSLTypeEVSSL:                  6,
+	SSLTypePositiveSSLMultiDomain: 7,
+	SSLTypeMultiDomainSSL:         8,
+	SSLTypeUnifiedCommunications:  9,
+	SSLTypeEVMultiDomainSSL:       10,
+	SSLTypePositiveSSLWildcard:    35,
+	SSLTypeEssentialSSLWildcard:   36,
+	SSLTypePremiumSSLWildcard:     37,
+}
+
+// SSLTypeID returns the numeric type ID of the SSL certificate type with
+// the given name, which is matched case-insensitively
+func SSLTypeID(name string) (int, error) {
+	for typeName, id := range sslTypeIDs {
+		if strings.EqualFold(typeName, strings.TrimSpace(name)) {
+			return id, nil
+		}
+	}
+	return 0, errors.Errorf("unknown SSL certificate type %q; use one of: %s", name, strings.Join(SSLTypeNames(), ", "))
+}
+
+// SSLTypeName returns the name of the SSL certificate type with the given
+// numeric type ID, and whether it is known
+func SSLTypeName(id int) (string, bool) {
+	for typeName, typeID := range sslTypeIDs {
+		if typeID == id {
+			return typeName, true
+		}
+	}
+	return "", false
+}
+
+// SSLTypeNames returns the names of the known SSL certificate types, sorted
+func SSLTypeNames() []string {
+	names := make([]string, 0, len(sslTypeIDs))
+	for typeName := range sslTypeIDs {
+		names = append(names, typeName)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,56 @@
+package namecheap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSLTypeID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   int
+	}{
+		{name: "PositiveSSL", id: 1},
+		{name: "EV SSL", id: 6},
+		{name: "EssentialSSL Wildcard", id: 36},
+		{name: " essentialssl wildcard ", id: 36},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := SSLTypeID(tt.name)
+			require.NoError(t, err)
+			assert.Equal(t, tt.id, id)
+		})
+	}
+
+	_, err := SSLTypeID("CheapSSL")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown SSL certificate type "CheapSSL"; use one of: EV Multi-Domain SSL, EV SSL, `)
+}
+
+func TestSSLTypeName(t *testing.T) {
+	name, ok := SSLTypeName(36)
+	assert.True(t, ok)
+	assert.Equal(t, SSLTypeEssentialSSLWildcard, name)
+
+	_, ok = SSLTypeName(999)
+	assert.False(t, ok)
+}
+
+func TestSSLTypeNames(t *testing.T) {
+	// Every name maps to its own ID and back
+	ids := map[int]string{}
+	for _, name := range SSLTypeNames() {
+		id, err := SSLTypeID(name)
+		require.NoError(t, err)
+		assert.NotContains(t, ids, id, "%s and %s share an ID", name, ids[id])
+		ids[id] = name
+
+		back, ok := SSLTypeName(id)
+		assert.True(t, ok)
+		assert.Equal(t, name, back)
+	}
+	assert.Len(t, ids, len(sslTypeIDs))
+}
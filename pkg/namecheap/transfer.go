@@ -0,0 +1,304 @@
+package namecheap
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Transfer list types accepted by domains.transfer.getList
+const (
+	TransferListAll        = "ALL"
+	TransferListInProgress = "INPROGRESS"
+	TransferListCancelled  = "CANCELLED"
+	TransferListCompleted  = "COMPLETED"
+)
+
+// TransferStatusID identifies the state of a transfer, as reported in the
+// StatusID of domains.transfer.getStatus and domains.transfer.getList.
+// Negative IDs are final.
+type TransferStatusID int
+
+// Transfer status IDs with a meaning of their own
+const (
+	// TransferStatusCompleted is a transfer that completed
+	TransferStatusCompleted TransferStatusID = -1
+
+	// TransferStatusEPPInvalid is a transfer stalled on an invalid EPP
+	// code, which can be resubmitted with a corrected one
+	TransferStatusEPPInvalid TransferStatusID = 5
+)
+
+// TransferPhase is the phase of its life a transfer is in
+type TransferPhase string
+
+// Transfer phases
+const (
+	// TransferPhasePending is a transfer in progress
+	TransferPhasePending TransferPhase = "Pending"
+
+	// TransferPhaseAwaitingEPP is a transfer stalled until it is
+	// resubmitted with a valid EPP code
+	TransferPhaseAwaitingEPP TransferPhase = "AwaitingEPP"
+
+	// TransferPhaseCompleted is a transfer that completed
+	TransferPhaseCompleted TransferPhase = "Completed"
+
+	// TransferPhaseFailed is a transfer that was cancelled or rejected
+	TransferPhaseFailed TransferPhase = "Failed"
+)
+
+// TransferPhaseOf returns the phase of a transfer with the given status ID
+// and status
+func TransferPhaseOf(statusID TransferStatusID, status string) TransferPhase {
+	switch {
+	case statusID == TransferStatusCompleted:
+		return TransferPhaseCompleted
+	case statusID < 0:
+		return TransferPhaseFailed
+	case statusID == TransferStatusEPPInvalid || TransferNeedsAction(status):
+		return TransferPhaseAwaitingEPP
+	default:
+		return TransferPhasePending
+	}
+}
+
+// Transfer represents a domain transfer into the Namecheap account
+type Transfer struct {
+	ID                int              `xml:"ID,attr"`
+	DomainName        string           `xml:"DomainName,attr"`
+	User              string           `xml:"User,attr"`
+	TransferDate      string           `xml:"TransferDate,attr"`
+	OrderID           int              `xml:"OrderID,attr"`
+	StatusID          TransferStatusID `xml:"StatusID,attr"`
+	Status            string           `xml:"Status,attr"`
+	StatusDate        string           `xml:"StatusDate,attr"`
+	StatusDescription string           `xml:"StatusDescription,attr"`
+}
+
+// TransferStatus is the state of a transfer reported by
+// domains.transfer.getStatus
+type TransferStatus struct {
+	TransferID int              `xml:"TransferID,attr"`
+	Status     string           `xml:"Status,attr"`
+	StatusID   TransferStatusID `xml:"StatusID,attr"`
+}
+
+// Phase returns the phase of the transfer
+func (s TransferStatus) Phase() TransferPhase {
+	return TransferPhaseOf(s.StatusID, s.Status)
+}
+
+// TransferCreateResponse represents the response from
+// domains.transfer.create
+type TransferCreateResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainTransferCreateResult struct {
+			DomainName    string           `xml:"DomainName,attr"`
+			Transfer      bool             `xml:"Transfer,attr"`
+			TransferID    int              `xml:"TransferID,attr"`
+			StatusID      TransferStatusID `xml:"StatusID,attr"`
+			OrderID       int              `xml:"OrderID,attr"`
+			TransactionID int              `xml:"TransactionID,attr"`
+			ChargedAmount float64          `xml:"ChargedAmount,attr"`
+		} `xml:"DomainTransferCreateResult"`
+	} `xml:"CommandResponse"`
+}
+
+// TransferGetStatusResponse represents the response from
+// domains.transfer.getStatus
+type TransferGetStatusResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainTransferGetStatusResult TransferStatus `xml:"DomainTransferGetStatusResult"`
+	} `xml:"CommandResponse"`
+}
+
+// TransferListResponse represents the response from domains.transfer.getList
+type TransferListResponse struct {
+	APIResponse
+	CommandResponse struct {
+		TransferGetListResult struct {
+			Transfers []Transfer `xml:"Transfer"`
+		} `xml:"TransferGetListResult"`
+	} `xml:"CommandResponse"`
+}
+
+// TransferUpdateStatusResponse represents the response from
+// domains.transfer.updateStatus
+type TransferUpdateStatusResponse struct {
+	APIResponse
+	CommandResponse struct {
+		TransferUpdateStatusResult struct {
+			TransferID int  `xml:"TransferID,attr"`
+			Resubmit   bool `xml:"Resubmit,attr"`
+		} `xml:"TransferUpdateStatusResult"`
+	} `xml:"CommandResponse"`
+}
+
+// TransferOptions are the optional parameters of a transfer
+type TransferOptions struct {
+	// EPPCode is the domain's authorization code from its current
+	// registrar, which most TLDs require
+	EPPCode string
+
+	// PromotionCode is a promotion code to apply to the transfer
+	PromotionCode string
+
+	// AddFreeWhoisguard adds a free WhoisGuard subscription to the domain
+	AddFreeWhoisguard bool
+
+	// EnableWhoisguard enables WhoisGuard once the domain is transferred
+	EnableWhoisguard bool
+}
+
+// TransferOrder is the outcome of ordering a transfer
+type TransferOrder struct {
+	DomainName    string
+	TransferID    int
+	StatusID      TransferStatusID
+	ChargedAmount float64
+	OrderID       int
+	TransactionID int
+}
+
+// errNotTransferred is returned when domains.transfer.create reports that
+// no transfer was ordered without an API error
+var errNotTransferred = errors.New("domain transfer was not ordered")
+
+// yesNo returns a boolean as the yes or no some commands take
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// CreateTransfer orders the transfer of a domain from another registrar into
+// the account, renewing it by years once transferred. The order is charged
+// to the account.
+func (c *Client) CreateTransfer(ctx context.Context, domainName string, years int, opts TransferOptions) (*TransferOrder, error) {
+	if years < 1 {
+		return nil, errors.Errorf("invalid transfer years %d", years)
+	}
+
+	params := newParams().
+		setDomainName(domainName).
+		setInt("Years", years)
+	if opts.EPPCode != "" {
+		params.setSensitive("EPPCode", opts.EPPCode)
+	}
+	params.
+		setOptional("PromotionCode", opts.PromotionCode).
+		set("AddFreeWhoisguard", yesNo(opts.AddFreeWhoisguard)).
+		set("WGenable", yesNo(opts.EnableWhoisguard))
+
+	resp, err := c.makeRequest(ctx, CommandDomainsTransferCreate, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make domains.transfer.create request")
+	}
+
+	var result TransferCreateResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse domains.transfer.create response")
+	}
+
+	created := result.CommandResponse.DomainTransferCreateResult
+	if !created.Transfer {
+		return nil, errNotTransferred
+	}
+
+	return &TransferOrder{
+		DomainName:    created.DomainName,
+		TransferID:    created.TransferID,
+		StatusID:      created.StatusID,
+		ChargedAmount: created.ChargedAmount,
+		OrderID:       created.OrderID,
+		TransactionID: created.TransactionID,
+	}, nil
+}
+
+// GetTransferStatus retrieves the state of a transfer
+func (c *Client) GetTransferStatus(ctx context.Context, transferID int) (*TransferStatus, error) {
+	resp, err := c.makeRequest(ctx, CommandDomainsTransferGetStatus, newParams().
+		setInt("TransferID", transferID))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make domains.transfer.getStatus request")
+	}
+
+	var result TransferGetStatusResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse domains.transfer.getStatus response")
+	}
+
+	return &result.CommandResponse.DomainTransferGetStatusResult, nil
+}
+
+// GetTransfers retrieves the account's domain transfers of the given list
+// type, optionally filtered by a search term matched against domain names
+func (c *Client) GetTransfers(ctx context.Context, listType, searchTerm string) ([]Transfer, error) {
+	resp, err := c.makeRequest(ctx, CommandDomainsTransferGetList, newParams().
+		setInt("PageSize", 100).
+		setOptional("ListType", listType).
+		setOptional("SearchTerm", searchTerm))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make domains.transfer.getList request")
+	}
+
+	var result TransferListResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse domains.transfer.getList response")
+	}
+
+	return result.CommandResponse.TransferGetListResult.Transfers, nil
+}
+
+// ResubmitTransfer resubmits a stalled transfer. A non-empty eppCode replaces
+// the authorization code the transfer was originally submitted with.
+func (c *Client) ResubmitTransfer(ctx context.Context, transferID int, eppCode string) error {
+	params := newParams().
+		setInt("TransferID", transferID).
+		set("Resubmit", "true")
+	if eppCode != "" {
+		params.setSensitive("EPPCode", eppCode)
+	}
+
+	resp, err := c.makeRequest(ctx, CommandDomainsTransferUpdateStatus, params)
+	if err != nil {
+		return errors.Wrap(err, "failed to make domains.transfer.updateStatus request")
+	}
+
+	var result TransferUpdateStatusResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse domains.transfer.updateStatus response")
+	}
+
+	if !result.CommandResponse.TransferUpdateStatusResult.Resubmit {
+		return errors.New("transfer was not resubmitted")
+	}
+
+	return nil
+}
+
+// TransferNeedsAction reports whether a transfer status indicates the transfer
+// has stalled and can be resubmitted, such as after an invalid EPP code
+func TransferNeedsAction(status string) bool {
+	s := strings.ToLower(status)
+	return strings.Contains(s, "epp invalid") ||
+		strings.Contains(s, "invalid epp") ||
+		strings.Contains(s, "action required")
+}
+
+// ShouldResubmitTransfer reports whether a stalled transfer should be
+// resubmitted. Transfers are resubmitted at most once per version of the
+// secret holding the EPP code, so that a still-wrong code does not cause a
+// resubmission loop; updating the secret allows one further attempt. Without
+// a secret version there is no corrected code to resubmit with.
+func ShouldResubmitTransfer(status, secretVersion, lastResubmittedVersion string) bool {
+	if !TransferNeedsAction(status) {
+		return false
+	}
+	return secretVersion != "" && secretVersion != lastResubmittedVersion
+}
@@ -0,0 +1,256 @@
+package namecheap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTransferClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+}
+
+func TestClient_GetTransfers(t *testing.T) {
+	client := newTestTransferClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "namecheap.domains.transfer.getList", r.FormValue("Command"))
+		assert.Equal(t, TransferListInProgress, r.FormValue("ListType"))
+		assert.Equal(t, "example.com", r.FormValue("SearchTerm"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<TransferGetListResult>
+			<Transfer ID="19" DomainName="example.com" User="testuser" TransferDate="10/05/2024" OrderID="1234" StatusID="5" Status="EPP invalid" StatusDate="10/06/2024" StatusDescription="The EPP code supplied is invalid"/>
+		</TransferGetListResult>
+	</CommandResponse>
+</ApiResponse>`))
+		require.NoError(t, err)
+	})
+
+	transfers, err := client.GetTransfers(context.Background(), TransferListInProgress, "example.com")
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	assert.Equal(t, 19, transfers[0].ID)
+	assert.Equal(t, "example.com", transfers[0].DomainName)
+	assert.Equal(t, TransferStatusEPPInvalid, transfers[0].StatusID)
+	assert.Equal(t, "EPP invalid", transfers[0].Status)
+	assert.True(t, TransferNeedsAction(transfers[0].Status))
+}
+
+func TestClient_CreateTransfer(t *testing.T) {
+	client := newTestTransferClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "namecheap.domains.transfer.create", r.Form.Get("Command"))
+		assert.Equal(t, "example.com", r.Form.Get("DomainName"))
+		assert.Equal(t, "2", r.Form.Get("Years"))
+		assert.Equal(t, "s3cr3t-epp", r.Form.Get("EPPCode"))
+		assert.Equal(t, "yes", r.Form.Get("AddFreeWhoisguard"))
+		assert.Equal(t, "no", r.Form.Get("WGenable"))
+		assert.NotContains(t, r.Form, "PromotionCode")
+
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write(commandFixture(t, CommandDomainsTransferCreate))
+	})
+
+	order, err := client.CreateTransfer(context.Background(), "example.com", 2, TransferOptions{
+		EPPCode:           "s3cr3t-epp",
+		AddFreeWhoisguard: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &TransferOrder{
+		DomainName:    "example.com",
+		TransferID:    23,
+		StatusID:      0,
+		ChargedAmount: 9.1,
+		OrderID:       1236,
+		TransactionID: 2561,
+	}, order)
+}
+
+func TestClient_CreateTransfer_Refused(t *testing.T) {
+	requests := 0
+	client := newTestTransferClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainTransferCreateResult DomainName="example.com" Transfer="false"/>
+	</CommandResponse>
+</ApiResponse>`))
+	})
+
+	// Years are checked before any API call
+	_, err := client.CreateTransfer(context.Background(), "example.com", 0, TransferOptions{})
+	assert.ErrorContains(t, err, "invalid transfer years 0")
+	assert.Zero(t, requests)
+
+	order, err := client.CreateTransfer(context.Background(), "example.com", 1, TransferOptions{})
+	assert.ErrorIs(t, err, errNotTransferred)
+	assert.Nil(t, order)
+}
+
+func TestClient_GetTransferStatus(t *testing.T) {
+	client := newTestTransferClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "namecheap.domains.transfer.getStatus", r.FormValue("Command"))
+		assert.Equal(t, "23", r.FormValue("TransferID"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write(commandFixture(t, CommandDomainsTransferGetStatus))
+	})
+
+	status, err := client.GetTransferStatus(context.Background(), 23)
+	require.NoError(t, err)
+	assert.Equal(t, &TransferStatus{TransferID: 23, Status: "EPP invalid", StatusID: TransferStatusEPPInvalid}, status)
+	assert.Equal(t, TransferPhaseAwaitingEPP, status.Phase())
+}
+
+func TestTransferPhaseOf(t *testing.T) {
+	tests := []struct {
+		statusID TransferStatusID
+		status   string
+		expected TransferPhase
+	}{
+		{statusID: 0, status: "Awaiting registrant approval", expected: TransferPhasePending},
+		{statusID: TransferStatusEPPInvalid, status: "EPP invalid", expected: TransferPhaseAwaitingEPP},
+		{statusID: 7, status: "Action required by registrant", expected: TransferPhaseAwaitingEPP},
+		{statusID: TransferStatusCompleted, status: "COMPLETED", expected: TransferPhaseCompleted},
+		{statusID: -22, status: "CANCELLED", expected: TransferPhaseFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			assert.Equal(t, tt.expected, TransferPhaseOf(tt.statusID, tt.status))
+		})
+	}
+}
+
+func TestClient_ResubmitTransfer(t *testing.T) {
+	tests := []struct {
+		name        string
+		eppCode     string
+		resubmitted string
+		expectError bool
+	}{
+		{
+			name:        "resubmit with corrected EPP code",
+			eppCode:     "new-epp",
+			resubmitted: "true",
+		},
+		{
+			name:        "resubmit without EPP code",
+			resubmitted: "true",
+		},
+		{
+			name:        "not resubmitted",
+			eppCode:     "new-epp",
+			resubmitted: "false",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestTransferClient(t, func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseForm())
+				q := r.Form
+				assert.Equal(t, "namecheap.domains.transfer.updateStatus", q.Get("Command"))
+				assert.Equal(t, "19", q.Get("TransferID"))
+				assert.Equal(t, "true", q.Get("Resubmit"))
+				assert.Equal(t, tt.eppCode, q.Get("EPPCode"))
+				_, hasEPP := q["EPPCode"]
+				assert.Equal(t, tt.eppCode != "", hasEPP)
+
+				w.Header().Set("Content-Type", "application/xml")
+				_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<TransferUpdateStatusResult TransferID="19" Resubmit="` + tt.resubmitted + `"/>
+	</CommandResponse>
+</ApiResponse>`))
+				require.NoError(t, err)
+			})
+
+			err := client.ResubmitTransfer(context.Background(), 19, tt.eppCode)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestShouldResubmitTransfer(t *testing.T) {
+	tests := []struct {
+		name                   string
+		status                 string
+		secretVersion          string
+		lastResubmittedVersion string
+		expected               bool
+	}{
+		{
+			name:          "EPP invalid, never resubmitted",
+			status:        "EPP invalid",
+			secretVersion: "100",
+			expected:      true,
+		},
+		{
+			name:          "action required, never resubmitted",
+			status:        "Action required by registrant",
+			secretVersion: "100",
+			expected:      true,
+		},
+		{
+			name:                   "already resubmitted with this secret version",
+			status:                 "EPP invalid",
+			secretVersion:          "100",
+			lastResubmittedVersion: "100",
+			expected:               false,
+		},
+		{
+			name:                   "secret updated since last resubmission",
+			status:                 "EPP invalid",
+			secretVersion:          "101",
+			lastResubmittedVersion: "100",
+			expected:               true,
+		},
+		{
+			name:     "no EPP secret",
+			status:   "EPP invalid",
+			expected: false,
+		},
+		{
+			name:          "transfer progressing normally",
+			status:        "Awaiting registrant approval",
+			secretVersion: "100",
+			expected:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ShouldResubmitTransfer(tt.status, tt.secretVersion, tt.lastResubmittedVersion))
+		})
+	}
+}
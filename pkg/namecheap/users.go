@@ -0,0 +1,280 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// UserBalance represents account balance information
+type UserBalance struct {
+	Currency                  string  `xml:"Currency,attr"`
+	AvailableBalance          float64 `xml:"AvailableBalance,attr"`
+	AccountBalance            float64 `xml:"AccountBalance,attr"`
+	EarnedAmount              float64 `xml:"EarnedAmount,attr"`
+	WithdrawableAmount        float64 `xml:"WithdrawableAmount,attr"`
+	FundsRequiredForAutoRenew float64 `xml:"FundsRequiredForAutoRenew,attr"`
+}
+
+// UserBalanceResponse represents the response from users.getBalances
+type UserBalanceResponse struct {
+	APIResponse
+	CommandResponse struct {
+		UserGetBalancesResult UserBalance `xml:"UserGetBalancesResult"`
+	} `xml:"CommandResponse"`
+}
+
+// TLD represents a top-level domain with pricing information
+type TLD struct {
+	Name                          string  `xml:"Name,attr"`
+	NonRealTime                   bool    `xml:"NonRealTime,attr"`
+	MinRegisterYears              int     `xml:"MinRegisterYears,attr"`
+	MaxRegisterYears              int     `xml:"MaxRegisterYears,attr"`
+	MinRenewYears                 int     `xml:"MinRenewYears,attr"`
+	MaxRenewYears                 int     `xml:"MaxRenewYears,attr"`
+	MinTransferYears              int     `xml:"MinTransferYears,attr"`
+	MaxTransferYears              int     `xml:"MaxTransferYears,attr"`
+	IsApiRegisterable             bool    `xml:"IsApiRegisterable,attr"`
+	IsApiRenewable                bool    `xml:"IsApiRenewable,attr"`
+	IsApiTransferable             bool    `xml:"IsApiTransferable,attr"`
+	IsEppRequired                 bool    `xml:"IsEppRequired,attr"`
+	IsDisableModContact           bool    `xml:"IsDisableModContact,attr"`
+	IsDisableWGAllot              bool    `xml:"IsDisableWGAllot,attr"`
+	IsIncludeInExtendedSearchOnly bool    `xml:"IsIncludeInExtendedSearchOnly,attr"`
+	SequenceNumber                int     `xml:"SequenceNumber,attr"`
+	Type                          string  `xml:"Type,attr"`
+	SubType                       string  `xml:"SubType,attr"`
+	IsSupportsIDN                 bool    `xml:"IsSupportsIDN,attr"`
+	Category                      string  `xml:"Category,attr"`
+	SupportsRegistrarLock         bool    `xml:"SupportsRegistrarLock,attr"`
+	AddGracePeriodFee             float64 `xml:"AddGracePeriodFee,attr"`
+	WhoisVerification             bool    `xml:"WhoisVerification,attr"`
+	ProviderApiDelete             bool    `xml:"ProviderApiDelete,attr"`
+	TldState                      string  `xml:"TldState,attr"`
+	SearchGroup                   string  `xml:"SearchGroup,attr"`
+	Registry                      string  `xml:"Registry,attr"`
+}
+
+// TLDListResponse represents the response from domains.getTldList
+type TLDListResponse struct {
+	APIResponse
+	CommandResponse struct {
+		DomainsTldListResult struct {
+			TLDs []TLD `xml:"Tld"`
+		} `xml:"Tlds"`
+	} `xml:"CommandResponse"`
+}
+
+// PricingType represents the price of a product, such as a TLD, for one
+// duration
+type PricingType struct {
+	// Name is the product priced, such as com or positivessl
+	Name string `xml:"-"`
+	// Category is the category the price is listed under, which
+	// users.getPricing names after the action, such as register
+	Category       string  `xml:"-"`
+	Price          float64 `xml:"Price,attr"`
+	RegularPrice   float64 `xml:"RegularPrice,attr"`
+	YourPrice      float64 `xml:"YourPrice,attr"`
+	YourPriceRange string  `xml:"YourPriceRange,attr"`
+	PromoPrice     float64 `xml:"PromotionPrice,attr"`
+	Currency       string  `xml:"Currency,attr"`
+	Duration       int     `xml:"Duration,attr"`
+	DurationType   string  `xml:"DurationType,attr"`
+	PricingType    string  `xml:"PricingType,attr"`
+	AdditionalCost float64 `xml:"AdditionalCost,attr"`
+	// YourAdditionalCost is the fee, such as the ICANN fee, charged on top
+	// of YourPrice. Namecheap misspells its attribute.
+	YourAdditionalCost float64 `xml:"YourAdditonalCost,attr"`
+}
+
+// UserPricingResponse represents the response from users.getPricing, which
+// nests prices under their product type, category and product
+type UserPricingResponse struct {
+	APIResponse
+	CommandResponse struct {
+		UserGetPricingResult struct {
+			ProductTypes []struct {
+				Name       string `xml:"Name,attr"`
+				Categories []struct {
+					Name     string `xml:"Name,attr"`
+					Products []struct {
+						Name   string        `xml:"Name,attr"`
+						Prices []PricingType `xml:"Price"`
+					} `xml:"Product"`
+				} `xml:"ProductCategory"`
+			} `xml:"ProductType"`
+		} `xml:"UserGetPricingResult"`
+	} `xml:"CommandResponse"`
+}
+
+// prices returns the response's prices, each named after its product and
+// category
+func (r *UserPricingResponse) prices() []PricingType {
+	var prices []PricingType
+	for _, productType := range r.CommandResponse.UserGetPricingResult.ProductTypes {
+		for _, category := range productType.Categories {
+			for _, product := range category.Products {
+				for _, price := range product.Prices {
+					price.Name = product.Name
+					price.Category = category.Name
+					prices = append(prices, price)
+				}
+			}
+		}
+	}
+	return prices
+}
+
+// GetUserBalances retrieves account balance information
+func (c *Client) GetUserBalances(ctx context.Context) (*UserBalance, error) {
+	resp, err := c.makeRequest(ctx, CommandUsersGetBalances, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make users.getBalances request")
+	}
+
+	var result UserBalanceResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse users.getBalances response")
+	}
+
+	return &result.CommandResponse.UserGetBalancesResult, nil
+}
+
+// GetTLDList retrieves list of TLDs with their properties and capabilities.
+// The list is cached for the client's cache TTL; a fresh read bypasses the
+// cache.
+func (c *Client) GetTLDList(ctx context.Context) ([]TLD, error) {
+	return cachedSlice(ctx, c, cacheTLDs, "", func() ([]TLD, error) {
+		resp, err := c.makeRequest(ctx, CommandDomainsGetTLDList, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to make domains.getTldList request")
+		}
+
+		var result TLDListResponse
+		if err := parseResponse(resp, &result); err != nil {
+			return nil, errors.Wrap(err, "failed to parse domains.getTldList response")
+		}
+
+		return result.CommandResponse.DomainsTldListResult.TLDs, nil
+	})
+}
+
+// GetPricing retrieves pricing information for domain registration, renewal, transfer, etc.
+// Prices are cached like the TLD list.
+func (c *Client) GetPricing(ctx context.Context, productType, productCategory, action string) ([]PricingType, error) {
+	request := productType + "/" + productCategory + "/" + action
+	return cachedSlice(ctx, c, cachePricing, request, func() ([]PricingType, error) {
+		resp, err := c.makeRequest(ctx, CommandUsersGetPricing, newParams().
+			set("ProductType", productType).
+			set("ActionName", action).
+			setOptional("ProductCategory", productCategory))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to make users.getPricing request")
+		}
+
+		var result UserPricingResponse
+		if err := parseResponse(resp, &result); err != nil {
+			return nil, errors.Wrap(err, "failed to parse users.getPricing response")
+		}
+
+		return result.prices(), nil
+	})
+}
+
+// GetDomainPricing retrieves pricing for domain operations (register, renew, transfer)
+func (c *Client) GetDomainPricing(ctx context.Context, action string) ([]PricingType, error) {
+	return c.GetPricing(ctx, "DOMAIN", "", action)
+}
+
+// GetSSLPricing retrieves pricing for SSL certificate operations
+func (c *Client) GetSSLPricing(ctx context.Context, action string) ([]PricingType, error) {
+	return c.GetPricing(ctx, "SSLCERTIFICATE", "", action)
+}
+
+// GetWhoisGuardPricing retrieves pricing for WhoisGuard privacy protection
+func (c *Client) GetWhoisGuardPricing(ctx context.Context, action string) ([]PricingType, error) {
+	return c.GetPricing(ctx, "WHOISGUARD", "", action)
+}
+
+// HasSufficientBalance checks if account has sufficient balance for an amount
+func (c *Client) HasSufficientBalance(ctx context.Context, requiredAmount float64) (bool, error) {
+	balance, err := c.GetUserBalances(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return balance.AvailableBalance >= requiredAmount, nil
+}
+
+// tldNotFoundError is returned for a TLD Namecheap doesn't list
+type tldNotFoundError string
+
+func (e tldNotFoundError) Error() string {
+	return fmt.Sprintf("TLD '%s' not found", string(e))
+}
+
+// IsTLDNotFound reports whether err is a TLD not being in Namecheap's TLD
+// list, rather than a failure to read the list
+func IsTLDNotFound(err error) bool {
+	var notFound tldNotFoundError
+	return errors.As(err, &notFound)
+}
+
+// GetTLDByName retrieves TLD information by name. Internationalized TLDs
+// may be named in their Unicode or punycode form.
+func (c *Client) GetTLDByName(ctx context.Context, tldName string) (*TLD, error) {
+	tlds, err := c.GetTLDList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	want := tldASCII(tldName)
+	for _, tld := range tlds {
+		if tldASCII(tld.Name) == want {
+			return &tld, nil
+		}
+	}
+
+	return nil, tldNotFoundError(tldName)
+}
+
+// tldASCII returns a TLD's name in punycode, or lower-cased if it doesn't
+// convert
+func tldASCII(name string) string {
+	if ascii, err := ToASCII(name); err == nil {
+		return ascii
+	}
+	return strings.ToLower(name)
+}
+
+// GetTLDForDomain retrieves information about the TLD a domain is registered
+// under, which is its public suffix, e.g. co.uk for example.co.uk
+func (c *Client) GetTLDForDomain(ctx context.Context, domainName string) (*TLD, error) {
+	_, tld, err := SplitDomain(domainName)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetTLDByName(ctx, tld)
+}
+
+// IsTLDSupported checks if a TLD is supported for API operations
+func (c *Client) IsTLDSupported(ctx context.Context, tldName, operation string) (bool, error) {
+	tld, err := c.GetTLDByName(ctx, tldName)
+	if err != nil {
+		return false, err
+	}
+
+	switch operation {
+	case "register":
+		return tld.IsApiRegisterable, nil
+	case "renew":
+		return tld.IsApiRenewable, nil
+	case "transfer":
+		return tld.IsApiTransferable, nil
+	default:
+		return false, errors.Errorf("unsupported operation: %s", operation)
+	}
+}
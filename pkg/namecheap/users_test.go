@@ -22,7 +22,7 @@ func TestClient_GetUserBalances(t *testing.T) {
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "GET", r.Method)
-		assert.Equal(t, "namecheap.users.getBalances", r.URL.Query().Get("Command"))
+		assert.Equal(t, "namecheap.users.getBalances", r.FormValue("Command"))
 
 		w.Header().Set("Content-Type", "application/xml")
 		w.WriteHeader(http.StatusOK)
@@ -56,72 +56,49 @@ func TestClient_GetUserBalances(t *testing.T) {
 }
 
 func TestClient_GetTLDList(t *testing.T) {
-	responseXML := `<?xml version="1.0" encoding="UTF-8"?>
-<ApiResponse Status="OK">
-	<CommandResponse>
-		<DomainsGetTldListResult>
-			<Tld Name="com" NonRealTime="false" MinRegisterYears="1" MaxRegisterYears="10" MinRenewYears="1" MaxRenewYears="10" MinTransferYears="1" MaxTransferYears="1" IsApiRegisterable="true" IsApiRenewable="true" IsApiTransferable="true" IsEppRequired="false" IsDisableModContact="false" IsDisableWGAllot="false" IsIncludeInExtendedSearchOnly="false" SequenceNumber="10" Type="GTLD" SubType="" IsSupportsIDN="true" Category="A" SupportsRegistrarLock="true" AddGracePeriodFee="0" WhoisVerification="true" ProviderApiDelete="false" TldState="clientTransferProhibited" SearchGroup="com" Registry="Verisign"/>
-			<Tld Name="net" NonRealTime="false" MinRegisterYears="1" MaxRegisterYears="10" MinRenewYears="1" MaxRenewYears="10" MinTransferYears="1" MaxTransferYears="1" IsApiRegisterable="true" IsApiRenewable="true" IsApiTransferable="true" IsEppRequired="false" IsDisableModContact="false" IsDisableWGAllot="false" IsIncludeInExtendedSearchOnly="false" SequenceNumber="20" Type="GTLD" SubType="" IsSupportsIDN="true" Category="A" SupportsRegistrarLock="true" AddGracePeriodFee="0" WhoisVerification="true" ProviderApiDelete="false" TldState="clientTransferProhibited" SearchGroup="net" Registry="Verisign"/>
-		</DomainsGetTldListResult>
-	</CommandResponse>
-</ApiResponse>`
-
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "GET", r.Method)
-		assert.Equal(t, "namecheap.domains.getTldList", r.URL.Query().Get("Command"))
-
-		w.Header().Set("Content-Type", "application/xml")
-		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(responseXML))
-		require.NoError(t, err)
-	}))
-	defer server.Close()
-
-	config := Config{
-		APIUser:  "testuser",
-		APIKey:   "testkey",
-		Username: "testuser",
-		ClientIP: "127.0.0.1",
-		BaseURL:  server.URL,
-		HTTPClient: &http.Client{
-			Timeout: 5 * time.Second,
-		},
-	}
-	client := NewClient(config)
+	client := newFixtureClient(t, nil)
 
 	tlds, err := client.GetTLDList(context.Background())
 
 	assert.NoError(t, err)
 	assert.Len(t, tlds, 2)
 
+	// Check .biz TLD
+	biz := tlds[0]
+	assert.Equal(t, "biz", biz.Name)
+	assert.False(t, biz.NonRealTime)
+	assert.Equal(t, 1, biz.MinRegisterYears)
+	assert.Equal(t, 10, biz.MaxRegisterYears)
+	assert.True(t, biz.IsApiRegisterable)
+	assert.True(t, biz.IsApiRenewable)
+	assert.True(t, biz.IsApiTransferable)
+	assert.Equal(t, "GTLD", biz.Type)
+	assert.Equal(t, "P", biz.Category)
+
 	// Check .com TLD
-	com := tlds[0]
+	com := tlds[1]
 	assert.Equal(t, "com", com.Name)
-	assert.False(t, com.NonRealTime)
-	assert.Equal(t, 1, com.MinRegisterYears)
-	assert.Equal(t, 10, com.MaxRegisterYears)
 	assert.True(t, com.IsApiRegisterable)
 	assert.True(t, com.IsApiRenewable)
 	assert.True(t, com.IsApiTransferable)
-	assert.Equal(t, "GTLD", com.Type)
-	assert.Equal(t, "Verisign", com.Registry)
-
-	// Check .net TLD
-	net := tlds[1]
-	assert.Equal(t, "net", net.Name)
-	assert.True(t, net.IsApiRegisterable)
-	assert.True(t, net.IsApiRenewable)
-	assert.True(t, net.IsApiTransferable)
+	assert.True(t, com.SupportsRegistrarLock)
 }
 
 func TestClient_GetPricing(t *testing.T) {
 	responseXML := `<?xml version="1.0" encoding="UTF-8"?>
 <ApiResponse Status="OK">
 	<CommandResponse>
-		<UserGetPricingResult ProductType="DOMAIN" ProductCategory="" Product="">
-			<ProductType>
-				<PricingType Name="DOMAIN" Price="12.50" RegularPrice="12.50" YourPrice="12.50" YourPriceRange="" PromoPrice="0.00" Currency="USD" Duration="1" DurationType="YEAR" PricingType="REGISTRATION" AdditionalCost="0.00"/>
-				<PricingType Name="DOMAIN" Price="12.50" RegularPrice="12.50" YourPrice="12.50" YourPriceRange="" PromoPrice="0.00" Currency="USD" Duration="1" DurationType="YEAR" PricingType="RENEWAL" AdditionalCost="0.00"/>
+		<UserGetPricingResult>
+			<ProductType Name="domains">
+				<ProductCategory Name="register">
+					<Product Name="com">
+						<Price Duration="1" DurationType="YEAR" Price="12.50" PricingType="MULTIPLE" AdditionalCost="0.18" RegularPrice="12.50" YourPrice="12.50" YourAdditonalCost="0.18" PromotionPrice="0.0" Currency="USD"/>
+						<Price Duration="2" DurationType="YEAR" Price="25.00" PricingType="MULTIPLE" AdditionalCost="0.36" RegularPrice="25.00" YourPrice="25.00" YourAdditonalCost="0.36" PromotionPrice="0.0" Currency="USD"/>
+					</Product>
+					<Product Name="net">
+						<Price Duration="1" DurationType="YEAR" Price="14.00" PricingType="MULTIPLE" RegularPrice="14.00" YourPrice="13.00" PromotionPrice="9.00" Currency="USD"/>
+					</Product>
+				</ProductCategory>
 			</ProductType>
 		</UserGetPricingResult>
 	</CommandResponse>
@@ -129,9 +106,9 @@ func TestClient_GetPricing(t *testing.T) {
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "GET", r.Method)
-		assert.Equal(t, "namecheap.users.getPricing", r.URL.Query().Get("Command"))
-		assert.Equal(t, "DOMAIN", r.URL.Query().Get("ProductType"))
-		assert.Equal(t, "REGISTER", r.URL.Query().Get("Action"))
+		assert.Equal(t, "namecheap.users.getPricing", r.FormValue("Command"))
+		assert.Equal(t, "DOMAIN", r.FormValue("ProductType"))
+		assert.Equal(t, "REGISTER", r.FormValue("ActionName"))
 
 		w.Header().Set("Content-Type", "application/xml")
 		w.WriteHeader(http.StatusOK)
@@ -155,39 +132,49 @@ func TestClient_GetPricing(t *testing.T) {
 	pricing, err := client.GetPricing(context.Background(), "DOMAIN", "", "REGISTER")
 
 	assert.NoError(t, err)
-	assert.Len(t, pricing, 2)
-
-	// Check registration pricing
-	reg := pricing[0]
-	assert.Equal(t, "DOMAIN", reg.Name)
-	assert.Equal(t, 12.50, reg.Price)
-	assert.Equal(t, 12.50, reg.RegularPrice)
-	assert.Equal(t, 12.50, reg.YourPrice)
-	assert.Equal(t, "USD", reg.Currency)
-	assert.Equal(t, 1, reg.Duration)
-	assert.Equal(t, "YEAR", reg.DurationType)
-	assert.Equal(t, "REGISTRATION", reg.PricingType)
-
-	// Check renewal pricing
-	ren := pricing[1]
-	assert.Equal(t, "RENEWAL", ren.PricingType)
+	assert.Len(t, pricing, 3)
+
+	// Prices are named after their product and category
+	com := pricing[0]
+	assert.Equal(t, "com", com.Name)
+	assert.Equal(t, "register", com.Category)
+	assert.Equal(t, 12.50, com.Price)
+	assert.Equal(t, 12.50, com.RegularPrice)
+	assert.Equal(t, 12.50, com.YourPrice)
+	assert.Equal(t, 0.18, com.YourAdditionalCost)
+	assert.Equal(t, "USD", com.Currency)
+	assert.Equal(t, 1, com.Duration)
+	assert.Equal(t, "YEAR", com.DurationType)
+	assert.Equal(t, "MULTIPLE", com.PricingType)
+
+	assert.Equal(t, 2, pricing[1].Duration)
+	assert.Equal(t, 25.00, pricing[1].YourPrice)
+
+	net := pricing[2]
+	assert.Equal(t, "net", net.Name)
+	assert.Equal(t, 13.00, net.YourPrice)
+	assert.Equal(t, 9.00, net.PromoPrice)
 }
 
 func TestClient_GetDomainPricing(t *testing.T) {
 	responseXML := `<?xml version="1.0" encoding="UTF-8"?>
 <ApiResponse Status="OK">
 	<CommandResponse>
-		<UserGetPricingResult ProductType="DOMAIN" ProductCategory="" Product="">
-			<ProductType>
-				<PricingType Name="DOMAIN" Price="12.50" RegularPrice="12.50" YourPrice="12.50" Currency="USD" Duration="1" DurationType="YEAR" PricingType="REGISTRATION"/>
+		<UserGetPricingResult>
+			<ProductType Name="domains">
+				<ProductCategory Name="register">
+					<Product Name="com">
+						<Price Duration="1" DurationType="YEAR" Price="12.50" RegularPrice="12.50" YourPrice="12.50" Currency="USD"/>
+					</Product>
+				</ProductCategory>
 			</ProductType>
 		</UserGetPricingResult>
 	</CommandResponse>
 </ApiResponse>`
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "DOMAIN", r.URL.Query().Get("ProductType"))
-		assert.Equal(t, "REGISTER", r.URL.Query().Get("Action"))
+		assert.Equal(t, "DOMAIN", r.FormValue("ProductType"))
+		assert.Equal(t, "REGISTER", r.FormValue("ActionName"))
 
 		w.Header().Set("Content-Type", "application/xml")
 		w.WriteHeader(http.StatusOK)
@@ -212,7 +199,7 @@ func TestClient_GetDomainPricing(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Len(t, pricing, 1)
-	assert.Equal(t, "REGISTRATION", pricing[0].PricingType)
+	assert.Equal(t, "com", pricing[0].Name)
 }
 
 func TestClient_HasSufficientBalance(t *testing.T) {
@@ -283,10 +270,10 @@ func TestClient_GetTLDByName(t *testing.T) {
 	responseXML := `<?xml version="1.0" encoding="UTF-8"?>
 <ApiResponse Status="OK">
 	<CommandResponse>
-		<DomainsGetTldListResult>
+		<Tlds>
 			<Tld Name="com" IsApiRegisterable="true" IsApiRenewable="true" IsApiTransferable="true"/>
 			<Tld Name="net" IsApiRegisterable="true" IsApiRenewable="true" IsApiTransferable="false"/>
-		</DomainsGetTldListResult>
+		</Tlds>
 	</CommandResponse>
 </ApiResponse>`
 
@@ -335,10 +322,10 @@ func TestClient_IsTLDSupported(t *testing.T) {
 	responseXML := `<?xml version="1.0" encoding="UTF-8"?>
 <ApiResponse Status="OK">
 	<CommandResponse>
-		<DomainsGetTldListResult>
+		<Tlds>
 			<Tld Name="com" IsApiRegisterable="true" IsApiRenewable="true" IsApiTransferable="true"/>
 			<Tld Name="net" IsApiRegisterable="false" IsApiRenewable="true" IsApiTransferable="false"/>
-		</DomainsGetTldListResult>
+		</Tlds>
 	</CommandResponse>
 </ApiResponse>`
 
@@ -393,4 +380,4 @@ func TestClient_IsTLDSupported(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unsupported operation: invalid")
 	assert.False(t, supported)
-}
\ No newline at end of file
+}
@@ -0,0 +1,378 @@
+package namecheap
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// WhoisGuard represents a WhoisGuard privacy protection service.
+// whoisguard.getList doesn't report EmailDetails; the address WhoisGuard
+// emails are forwarded to is in DomainInfo.WhoisGuardForwardedTo.
+type WhoisGuard struct {
+	ID           int    `xml:"ID,attr"`
+	DomainName   string `xml:"DomainName,attr"`
+	Created      ncTime `xml:"Created,attr"`
+	Expires      ncTime `xml:"Expires,attr"`
+	Status       string `xml:"Status,attr"`
+	EmailDetails struct {
+		ForwardedTo       string `xml:"ForwardedTo,attr"`
+		LastAutoEmailDate ncTime `xml:"LastAutoEmailDate,attr"`
+		AutoEmailCount    int    `xml:"AutoEmailCount,attr"`
+	} `xml:"EmailDetails"`
+}
+
+// Allotted reports whether the WhoisGuard subscription is allotted to a
+// domain. whoisguard.getList also lists the account's free subscriptions,
+// which have no domain name until they are allotted.
+func (wg WhoisGuard) Allotted() bool {
+	return wg.DomainName != ""
+}
+
+// WhoisGuardListResponse represents the response from whoisguard.getList
+type WhoisGuardListResponse struct {
+	APIResponse
+	CommandResponse struct {
+		WhoisGuardGetListResult struct {
+			WhoisGuards []WhoisGuard `xml:"Whoisguard"`
+		} `xml:"WhoisguardGetListResult"`
+		Paging Paging `xml:"Paging"`
+	} `xml:"CommandResponse"`
+}
+
+// WhoisGuardEnableResponse represents the response from whoisguard.enable
+type WhoisGuardEnableResponse struct {
+	APIResponse
+	CommandResponse struct {
+		WhoisGuardEnableResult struct {
+			Domain    string `xml:"Domain,attr"`
+			IsSuccess bool   `xml:"IsSuccess,attr"`
+		} `xml:"WhoisguardEnableResult"`
+	} `xml:"CommandResponse"`
+}
+
+// WhoisGuardDisableResponse represents the response from whoisguard.disable
+type WhoisGuardDisableResponse struct {
+	APIResponse
+	CommandResponse struct {
+		WhoisGuardDisableResult struct {
+			Domain    string `xml:"Domain,attr"`
+			IsSuccess bool   `xml:"IsSuccess,attr"`
+		} `xml:"WhoisguardDisableResult"`
+	} `xml:"CommandResponse"`
+}
+
+// WhoisGuardAllotResponse represents the response from whoisguard.allot
+type WhoisGuardAllotResponse struct {
+	APIResponse
+	CommandResponse struct {
+		WhoisGuardAllotResult struct {
+			Domain    string `xml:"Domain,attr"`
+			IsSuccess bool   `xml:"IsSuccess,attr"`
+		} `xml:"WhoisguardAllotResult"`
+	} `xml:"CommandResponse"`
+}
+
+// WhoisGuardUnallotResponse represents the response from
+// whoisguard.unallot
+type WhoisGuardUnallotResponse struct {
+	APIResponse
+	CommandResponse struct {
+		WhoisGuardUnallotResult struct {
+			WhoisguardID int  `xml:"WhoisguardId,attr"`
+			IsSuccess    bool `xml:"IsSuccess,attr"`
+		} `xml:"WhoisguardUnallotResult"`
+	} `xml:"CommandResponse"`
+}
+
+// WhoisGuardDiscardResponse represents the response from
+// whoisguard.discard
+type WhoisGuardDiscardResponse struct {
+	APIResponse
+	CommandResponse struct {
+		WhoisGuardDiscardResult struct {
+			WhoisguardID int  `xml:"WhoisguardId,attr"`
+			IsSuccess    bool `xml:"IsSuccess,attr"`
+		} `xml:"WhoisguardDiscardResult"`
+	} `xml:"CommandResponse"`
+}
+
+// WhoisGuardChangeEmailResponse represents the response from
+// whoisguard.changeemailaddress
+type WhoisGuardChangeEmailResponse struct {
+	APIResponse
+	CommandResponse struct {
+		WhoisGuardChangeEmailResult struct {
+			ID         int    `xml:"ID,attr"`
+			IsSuccess  bool   `xml:"IsSuccess,attr"`
+			WGEmail    string `xml:"WGEmail,attr"`
+			WGOldEmail string `xml:"WGOldEmail,attr"`
+		} `xml:"WhoisguardChangeEmailAddressResult"`
+	} `xml:"CommandResponse"`
+}
+
+// WhoisGuardEmailChange is the address a WhoisGuard subscription publishes
+// in the domain's WHOIS record, before and after it was changed
+type WhoisGuardEmailChange struct {
+	Email    string
+	OldEmail string
+}
+
+// WhoisGuardRenewResponse represents the response from whoisguard.renew
+type WhoisGuardRenewResponse struct {
+	APIResponse
+	CommandResponse struct {
+		WhoisGuardRenewResult struct {
+			WhoisguardID  int     `xml:"WhoisguardID,attr"`
+			Renew         bool    `xml:"Renew,attr"`
+			ChargedAmount float64 `xml:"ChargedAmount,attr"`
+			TransactionID int     `xml:"TransactionID,attr"`
+			OrderID       int     `xml:"OrderID,attr"`
+		} `xml:"WhoisguardRenewResult"`
+	} `xml:"CommandResponse"`
+}
+
+// GetWhoisGuards retrieves all WhoisGuard services for the account
+func (c *Client) GetWhoisGuards(ctx context.Context) ([]WhoisGuard, error) {
+	var whoisGuards []WhoisGuard
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.Wrap(err, "whoisguard.getList cancelled")
+		}
+
+		resp, err := c.makeRequest(ctx, CommandWhoisGuardGetList, newParams().
+			setInt("Page", page).
+			setInt("PageSize", whoisGuardListMaxPageSize))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to make whoisguard.getList request")
+		}
+
+		var result WhoisGuardListResponse
+		if err := parseResponse(resp, &result); err != nil {
+			return nil, errors.Wrap(err, "failed to parse whoisguard.getList response")
+		}
+
+		pageWhoisGuards := result.CommandResponse.WhoisGuardGetListResult.WhoisGuards
+		whoisGuards = append(whoisGuards, pageWhoisGuards...)
+		if len(pageWhoisGuards) == 0 || len(whoisGuards) >= result.CommandResponse.Paging.TotalItems {
+			return whoisGuards, nil
+		}
+	}
+}
+
+// whoisGuardListMaxPageSize is the largest page whoisguard.getList returns
+const whoisGuardListMaxPageSize = 100
+
+// ErrNumberWhoisGuardNotReady is the API error returned by whoisguard.enable
+// while the WhoisGuard subscription of a newly registered domain is still
+// being activated
+const ErrNumberWhoisGuardNotReady = "3031510"
+
+// IsWhoisGuardNotReady reports whether err is a Namecheap API error stating
+// that a domain's WhoisGuard subscription is not active yet. It clears
+// within minutes of registration, so enabling WhoisGuard should be retried.
+func IsWhoisGuardNotReady(err error) bool {
+	var ncErr Error
+	return errors.As(err, &ncErr) && ncErr.Number == ErrNumberWhoisGuardNotReady
+}
+
+// EnableWhoisGuard enables WhoisGuard privacy protection for a domain
+func (c *Client) EnableWhoisGuard(ctx context.Context, whoisGuardID int, domainName, forwardedToEmail string) error {
+	resp, err := c.makeRequest(ctx, CommandWhoisGuardEnable, newParams().
+		setInt("WhoisguardID", whoisGuardID).
+		set("DomainName", domainName).
+		setOptional("ForwardedToEmail", forwardedToEmail))
+	if err != nil {
+		return errors.Wrap(err, "failed to make whoisguard.enable request")
+	}
+
+	var result WhoisGuardEnableResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse whoisguard.enable response")
+	}
+
+	if !result.CommandResponse.WhoisGuardEnableResult.IsSuccess {
+		return errors.New("failed to enable WhoisGuard")
+	}
+
+	return nil
+}
+
+// DisableWhoisGuard disables WhoisGuard privacy protection for a domain
+func (c *Client) DisableWhoisGuard(ctx context.Context, whoisGuardID int, domainName string) error {
+	resp, err := c.makeRequest(ctx, CommandWhoisGuardDisable, newParams().
+		setInt("WhoisguardID", whoisGuardID).
+		set("DomainName", domainName))
+	if err != nil {
+		return errors.Wrap(err, "failed to make whoisguard.disable request")
+	}
+
+	var result WhoisGuardDisableResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse whoisguard.disable response")
+	}
+
+	if !result.CommandResponse.WhoisGuardDisableResult.IsSuccess {
+		return errors.New("failed to disable WhoisGuard")
+	}
+
+	return nil
+}
+
+// RenewWhoisGuard renews WhoisGuard privacy protection service
+func (c *Client) RenewWhoisGuard(ctx context.Context, whoisGuardID int, years int) error {
+	resp, err := c.makeRequest(ctx, CommandWhoisGuardRenew, newParams().
+		setInt("WhoisguardID", whoisGuardID).
+		setInt("Years", years))
+	if err != nil {
+		return errors.Wrap(err, "failed to make whoisguard.renew request")
+	}
+
+	var result WhoisGuardRenewResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse whoisguard.renew response")
+	}
+
+	if !result.CommandResponse.WhoisGuardRenewResult.Renew {
+		return errors.New("WhoisGuard renewal failed")
+	}
+
+	return nil
+}
+
+// GetUnallottedWhoisGuards retrieves the account's free WhoisGuard
+// subscriptions, which can be allotted to a domain that has none
+func (c *Client) GetUnallottedWhoisGuards(ctx context.Context) ([]WhoisGuard, error) {
+	whoisGuards, err := c.GetWhoisGuards(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var unallotted []WhoisGuard
+	for _, wg := range whoisGuards {
+		if !wg.Allotted() {
+			unallotted = append(unallotted, wg)
+		}
+	}
+	return unallotted, nil
+}
+
+// AllotWhoisGuard allots a free WhoisGuard subscription to a domain that
+// has none, which it can then be enabled for
+func (c *Client) AllotWhoisGuard(ctx context.Context, whoisGuardID int, domainName string) error {
+	resp, err := c.makeRequest(ctx, CommandWhoisGuardAllot, newParams().
+		setInt("WhoisguardID", whoisGuardID).
+		setRequired("DomainName", domainName))
+	if err != nil {
+		return errors.Wrap(err, "failed to make whoisguard.allot request")
+	}
+
+	var result WhoisGuardAllotResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse whoisguard.allot response")
+	}
+
+	if !result.CommandResponse.WhoisGuardAllotResult.IsSuccess {
+		return errors.New("failed to allot WhoisGuard")
+	}
+
+	return nil
+}
+
+// UnallotWhoisGuard frees a WhoisGuard subscription from its domain, so
+// that it can be allotted to another
+func (c *Client) UnallotWhoisGuard(ctx context.Context, whoisGuardID int) error {
+	resp, err := c.makeRequest(ctx, CommandWhoisGuardUnallot, newParams().
+		setInt("WhoisguardID", whoisGuardID))
+	if err != nil {
+		return errors.Wrap(err, "failed to make whoisguard.unallot request")
+	}
+
+	var result WhoisGuardUnallotResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse whoisguard.unallot response")
+	}
+
+	if !result.CommandResponse.WhoisGuardUnallotResult.IsSuccess {
+		return errors.New("failed to unallot WhoisGuard")
+	}
+
+	return nil
+}
+
+// DiscardWhoisGuard discards a WhoisGuard subscription, e.g. one left
+// unused by a deleted or transferred domain. A discarded subscription can't
+// be allotted again.
+func (c *Client) DiscardWhoisGuard(ctx context.Context, whoisGuardID int) error {
+	resp, err := c.makeRequest(ctx, CommandWhoisGuardDiscard, newParams().
+		setInt("WhoisguardID", whoisGuardID))
+	if err != nil {
+		return errors.Wrap(err, "failed to make whoisguard.discard request")
+	}
+
+	var result WhoisGuardDiscardResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return errors.Wrap(err, "failed to parse whoisguard.discard response")
+	}
+
+	if !result.CommandResponse.WhoisGuardDiscardResult.IsSuccess {
+		return errors.New("failed to discard WhoisGuard")
+	}
+
+	return nil
+}
+
+// ChangeWhoisGuardEmail replaces the address a WhoisGuard subscription
+// publishes in the domain's WHOIS record with a new one, e.g. once the old
+// one attracts spam. Mail to either is forwarded to the forwarded-to
+// address, which EnableWhoisGuard sets.
+func (c *Client) ChangeWhoisGuardEmail(ctx context.Context, whoisGuardID int) (*WhoisGuardEmailChange, error) {
+	resp, err := c.makeRequest(ctx, CommandWhoisGuardChangeEmail, newParams().
+		setInt("WhoisguardID", whoisGuardID))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make whoisguard.changeemailaddress request")
+	}
+
+	var result WhoisGuardChangeEmailResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse whoisguard.changeemailaddress response")
+	}
+
+	change := result.CommandResponse.WhoisGuardChangeEmailResult
+	if !change.IsSuccess {
+		return nil, errors.New("failed to change WhoisGuard email address")
+	}
+
+	return &WhoisGuardEmailChange{Email: change.WGEmail, OldEmail: change.WGOldEmail}, nil
+}
+
+// GetWhoisGuardForDomain retrieves WhoisGuard information for a specific domain
+func (c *Client) GetWhoisGuardForDomain(ctx context.Context, domainName string) (*WhoisGuard, error) {
+	whoisGuards, err := c.GetWhoisGuards(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Free subscriptions have no domain name to match
+	for _, wg := range whoisGuards {
+		if wg.Allotted() && strings.EqualFold(wg.DomainName, domainName) {
+			return &wg, nil
+		}
+	}
+
+	return nil, errors.New("WhoisGuard not found for domain")
+}
+
+// IsWhoisGuardEnabled checks if WhoisGuard is enabled for a domain
+func (c *Client) IsWhoisGuardEnabled(ctx context.Context, domainName string) (bool, error) {
+	whoisGuard, err := c.GetWhoisGuardForDomain(ctx, domainName)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return whoisGuard.Status == "ENABLED", nil
+}
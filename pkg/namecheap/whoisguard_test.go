@@ -2,8 +2,11 @@ package namecheap
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,10 +19,10 @@ func TestClient_GetWhoisGuards(t *testing.T) {
 <ApiResponse Status="OK">
 	<CommandResponse>
 		<WhoisguardGetListResult>
-			<Whoisguard ID="123" DomainName="example.com" Created="2024-01-01T00:00:00Z" Status="ENABLED">
+			<Whoisguard ID="123" DomainName="example.com" Created="01/01/2024" Expires="1/1/2027" Status="ENABLED">
 				<EmailDetails ForwardedTo="user@email.com" LastAutoEmailDate="2024-01-01T12:00:00Z" AutoEmailCount="5"/>
 			</Whoisguard>
-			<Whoisguard ID="124" DomainName="test.com" Created="2024-01-01T00:00:00Z" Status="DISABLED">
+			<Whoisguard ID="124" DomainName="test.com" Created="01/01/2024" Expires="" Status="DISABLED">
 				<EmailDetails ForwardedTo="" LastAutoEmailDate="" AutoEmailCount="0"/>
 			</Whoisguard>
 		</WhoisguardGetListResult>
@@ -28,7 +31,7 @@ func TestClient_GetWhoisGuards(t *testing.T) {
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "GET", r.Method)
-		assert.Equal(t, "namecheap.whoisguard.getList", r.URL.Query().Get("Command"))
+		assert.Equal(t, "namecheap.whoisguard.getList", r.FormValue("Command"))
 
 		w.Header().Set("Content-Type", "application/xml")
 		w.WriteHeader(http.StatusOK)
@@ -59,21 +62,26 @@ func TestClient_GetWhoisGuards(t *testing.T) {
 	assert.Equal(t, "example.com", whoisGuards[0].DomainName)
 	assert.Equal(t, "ENABLED", whoisGuards[0].Status)
 	assert.Equal(t, "user@email.com", whoisGuards[0].EmailDetails.ForwardedTo)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), whoisGuards[0].Created.Time)
+	assert.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), whoisGuards[0].Expires.Time)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), whoisGuards[0].EmailDetails.LastAutoEmailDate.Time)
 
-	// Check second WhoisGuard (disabled)
+	// Check second WhoisGuard (disabled), whose empty dates are zero
 	assert.Equal(t, 124, whoisGuards[1].ID)
 	assert.Equal(t, "test.com", whoisGuards[1].DomainName)
 	assert.Equal(t, "DISABLED", whoisGuards[1].Status)
+	assert.True(t, whoisGuards[1].Expires.IsZero())
+	assert.True(t, whoisGuards[1].EmailDetails.LastAutoEmailDate.IsZero())
 }
 
 func TestClient_EnableWhoisGuard(t *testing.T) {
 	tests := []struct {
-		name           string
-		whoisGuardID   int
-		domainName     string
-		forwardEmail   string
-		responseXML    string
-		expectedError  string
+		name          string
+		whoisGuardID  int
+		domainName    string
+		forwardEmail  string
+		responseXML   string
+		expectedError string
 	}{
 		{
 			name:         "successful enable",
@@ -117,13 +125,13 @@ func TestClient_EnableWhoisGuard(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				assert.Equal(t, "GET", r.Method)
-				assert.Equal(t, "namecheap.whoisguard.enable", r.URL.Query().Get("Command"))
-				assert.Equal(t, "123", r.URL.Query().Get("WhoisguardID"))
-				assert.Equal(t, tt.domainName, r.URL.Query().Get("DomainName"))
+				assert.Equal(t, "POST", r.Method)
+				assert.Equal(t, "namecheap.whoisguard.enable", r.FormValue("Command"))
+				assert.Equal(t, "123", r.FormValue("WhoisguardID"))
+				assert.Equal(t, tt.domainName, r.FormValue("DomainName"))
 
 				if tt.forwardEmail != "" {
-					assert.Equal(t, tt.forwardEmail, r.URL.Query().Get("ForwardedToEmail"))
+					assert.Equal(t, tt.forwardEmail, r.FormValue("ForwardedToEmail"))
 				}
 
 				w.Header().Set("Content-Type", "application/xml")
@@ -159,11 +167,11 @@ func TestClient_EnableWhoisGuard(t *testing.T) {
 
 func TestClient_DisableWhoisGuard(t *testing.T) {
 	tests := []struct {
-		name           string
-		whoisGuardID   int
-		domainName     string
-		responseXML    string
-		expectedError  string
+		name          string
+		whoisGuardID  int
+		domainName    string
+		responseXML   string
+		expectedError string
 	}{
 		{
 			name:         "successful disable",
@@ -193,10 +201,10 @@ func TestClient_DisableWhoisGuard(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				assert.Equal(t, "GET", r.Method)
-				assert.Equal(t, "namecheap.whoisguard.disable", r.URL.Query().Get("Command"))
-				assert.Equal(t, "123", r.URL.Query().Get("WhoisguardID"))
-				assert.Equal(t, tt.domainName, r.URL.Query().Get("DomainName"))
+				assert.Equal(t, "POST", r.Method)
+				assert.Equal(t, "namecheap.whoisguard.disable", r.FormValue("Command"))
+				assert.Equal(t, "123", r.FormValue("WhoisguardID"))
+				assert.Equal(t, tt.domainName, r.FormValue("DomainName"))
 
 				w.Header().Set("Content-Type", "application/xml")
 				w.WriteHeader(http.StatusOK)
@@ -234,10 +242,10 @@ func TestClient_GetWhoisGuardForDomain(t *testing.T) {
 <ApiResponse Status="OK">
 	<CommandResponse>
 		<WhoisguardGetListResult>
-			<Whoisguard ID="123" DomainName="example.com" Created="2024-01-01T00:00:00Z" Status="ENABLED">
+			<Whoisguard ID="123" DomainName="example.com" Created="01/01/2024" Status="ENABLED">
 				<EmailDetails ForwardedTo="user@email.com" LastAutoEmailDate="2024-01-01T12:00:00Z" AutoEmailCount="5"/>
 			</Whoisguard>
-			<Whoisguard ID="124" DomainName="test.com" Created="2024-01-01T00:00:00Z" Status="DISABLED">
+			<Whoisguard ID="124" DomainName="test.com" Created="01/01/2024" Status="DISABLED">
 				<EmailDetails ForwardedTo="" LastAutoEmailDate="" AutoEmailCount="0"/>
 			</Whoisguard>
 		</WhoisguardGetListResult>
@@ -285,6 +293,58 @@ func TestClient_GetWhoisGuardForDomain(t *testing.T) {
 	assert.Nil(t, whoisGuard)
 }
 
+func TestClient_GetWhoisGuardForDomain_Pages(t *testing.T) {
+	// Two pages of subscriptions; the one for example.com is on page 2
+	page := func(totalItems int, domainNames ...string) string {
+		var b strings.Builder
+		for i, domainName := range domainNames {
+			fmt.Fprintf(&b, `<Whoisguard ID="%d" DomainName="%s" Created="02/15/2016" Expires="02/15/2026" Status="ENABLED"/>`, 1000+i, domainName)
+		}
+		return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<WhoisguardGetListResult>%s</WhoisguardGetListResult>
+		<Paging><TotalItems>%d</TotalItems><CurrentPage>1</CurrentPage><PageSize>100</PageSize></Paging>
+	</CommandResponse>
+</ApiResponse>`, b.String(), totalItems)
+	}
+
+	firstPage := make([]string, 100)
+	for i := range firstPage {
+		firstPage[i] = fmt.Sprintf("site%d.net", i)
+	}
+	pages := []string{page(101, firstPage...), page(101, "example.com")}
+
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "namecheap.whoisguard.getList", r.FormValue("Command"))
+		assert.Equal(t, "100", r.FormValue("PageSize"))
+		requested = append(requested, r.FormValue("Page"))
+
+		n, err := strconv.Atoi(r.FormValue("Page"))
+		require.NoError(t, err)
+		require.LessOrEqual(t, n, len(pages))
+		w.Header().Set("Content-Type", "application/xml")
+		_, err = w.Write([]byte(pages[n-1]))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	whoisGuard, err := client.GetWhoisGuardForDomain(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 1000, whoisGuard.ID)
+	assert.Equal(t, []string{"1", "2"}, requested)
+}
+
 func TestClient_IsWhoisGuardEnabled(t *testing.T) {
 	responseXML := `<?xml version="1.0" encoding="UTF-8"?>
 <ApiResponse Status="OK">
@@ -334,4 +394,160 @@ func TestClient_IsWhoisGuardEnabled(t *testing.T) {
 	enabled, err = client.IsWhoisGuardEnabled(context.Background(), "notfound.com")
 	assert.NoError(t, err)
 	assert.False(t, enabled)
-}
\ No newline at end of file
+}
+func TestClient_GetUnallottedWhoisGuards(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "namecheap.whoisguard.getList", r.FormValue("Command"))
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write(commandFixture(t, CommandWhoisGuardGetList))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	// Only the subscription without a domain is free
+	whoisGuards, err := client.GetUnallottedWhoisGuards(context.Background())
+	require.NoError(t, err)
+	require.Len(t, whoisGuards, 1)
+	assert.Equal(t, 53537, whoisGuards[0].ID)
+	assert.False(t, whoisGuards[0].Allotted())
+
+	// and isn't taken for a domain's
+	_, err = client.GetWhoisGuardForDomain(context.Background(), "")
+	assert.Error(t, err)
+}
+
+func TestClient_AllotWhoisGuard(t *testing.T) {
+	var form []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		command := Command(r.FormValue("Command"))
+		form = append(form, fmt.Sprintf("%s %s %s", command, r.FormValue("WhoisguardID"), r.FormValue("DomainName")))
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write(commandFixture(t, command))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	require.NoError(t, client.AllotWhoisGuard(context.Background(), 53537, "example.com"))
+	require.NoError(t, client.UnallotWhoisGuard(context.Background(), 53536))
+	assert.Equal(t, []string{
+		"namecheap.whoisguard.allot 53537 example.com",
+		"namecheap.whoisguard.unallot 53536 ",
+	}, form)
+
+	// A subscription is allotted to a domain
+	require.Error(t, client.AllotWhoisGuard(context.Background(), 53537, ""))
+	assert.Len(t, form, 2)
+}
+
+func TestClient_DiscardWhoisGuard(t *testing.T) {
+	tests := []struct {
+		name        string
+		responseXML string
+		wantErr     string
+	}{
+		{
+			name: "Discarded",
+		},
+		{
+			name: "NotDiscarded",
+			responseXML: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<WhoisguardDiscardResult WhoisguardId="53537" IsSuccess="false"/>
+	</CommandResponse>
+</ApiResponse>`,
+			wantErr: "failed to discard WhoisGuard",
+		},
+		{
+			name: "Error",
+			responseXML: `<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="ERROR">
+	<Errors>
+		<Error Number="5050900">Unhandled exception</Error>
+	</Errors>
+</ApiResponse>`,
+			wantErr: "Unhandled exception",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "namecheap.whoisguard.discard", r.FormValue("Command"))
+				assert.Equal(t, "53537", r.FormValue("WhoisguardID"))
+
+				w.Header().Set("Content-Type", "application/xml")
+				response := []byte(tt.responseXML)
+				if tt.responseXML == "" {
+					response = commandFixture(t, CommandWhoisGuardDiscard)
+				}
+				_, err := w.Write(response)
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			client := NewClient(Config{
+				APIUser:    "testuser",
+				APIKey:     "testkey",
+				Username:   "testuser",
+				ClientIP:   "127.0.0.1",
+				BaseURL:    server.URL,
+				HTTPClient: &http.Client{Timeout: 5 * time.Second},
+			})
+
+			err := client.DiscardWhoisGuard(context.Background(), 53537)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestClient_ChangeWhoisGuardEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "namecheap.whoisguard.changeemailaddress", r.FormValue("Command"))
+		assert.Equal(t, "53536", r.FormValue("WhoisguardID"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write(commandFixture(t, CommandWhoisGuardChangeEmail))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "testuser",
+		APIKey:     "testkey",
+		Username:   "testuser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+
+	change, err := client.ChangeWhoisGuardEmail(context.Background(), 53536)
+	require.NoError(t, err)
+	assert.Equal(t, &WhoisGuardEmailChange{
+		Email:    "f0e1d2c3b4a5@whoisguard.com",
+		OldEmail: "a5b4c3d2e1f0@whoisguard.com",
+	}, change)
+}
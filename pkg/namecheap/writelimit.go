@@ -0,0 +1,104 @@
+package namecheap
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultDomainWriteInterval is the default minimum interval between writes
+// to the same domain's DNS settings
+const DefaultDomainWriteInterval = 5 * time.Second
+
+// domainWriteCommands are the commands that rewrite a domain's DNS settings,
+// and so are limited to one per Config.DomainWriteInterval per domain
+var domainWriteCommands = map[Command]bool{
+	CommandDomainsDNSSetHosts:   true,
+	CommandDomainsDNSSetCustom:  true,
+	CommandDomainsDNSSetDefault: true,
+}
+
+var delayedDomainWrites = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "namecheap_delayed_domain_writes_total",
+	Help: "Number of writes to a domain's DNS settings delayed to keep to the minimum interval between them, by command.",
+}, []string{LabelCommand})
+
+// writeLimiter spaces out the writes to each domain. Clients are created for
+// every reconcile, so the schedule is shared by every client.
+type writeLimiter struct {
+	mu   sync.Mutex
+	now  func() time.Time
+	next map[zoneKey]time.Time
+}
+
+var domainWrites = &writeLimiter{now: time.Now, next: map[zoneKey]time.Time{}}
+
+// reserve returns the slot of a write to key at least interval after the
+// previous one and how long it must wait for it, and reserves the slot
+func (l *writeLimiter) reserve(key zoneKey, interval time.Duration) (time.Time, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	at := now
+	if next, ok := l.next[key]; ok && next.After(now) {
+		at = next
+	}
+	l.next[key] = at.Add(interval)
+
+	// Forget domains whose slots have all passed
+	for k, next := range l.next {
+		if !next.After(now) {
+			delete(l.next, k)
+		}
+	}
+	return at, at.Sub(now)
+}
+
+// release gives back the slot at reserved by a write to key that was not
+// sent. Only the last slot can be given back; the writes queued behind an
+// earlier one keep their slots, so the next write waits no longer than it
+// would have done behind the abandoned one.
+func (l *writeLimiter) release(key zoneKey, at time.Time, interval time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if next, ok := l.next[key]; ok && next.Equal(at.Add(interval)) {
+		l.next[key] = at
+	}
+}
+
+// waitForDomainWrite blocks until command may be sent for the domain named
+// by params, if it is a write limited by the client's domain write interval
+func (c *Client) waitForDomainWrite(ctx context.Context, command Command, params *params) error {
+	interval := c.domainWriteInterval
+	if interval <= 0 || !domainWriteCommands[command] {
+		return nil
+	}
+
+	domain := params.values["SLD"] + "." + params.values["TLD"]
+	key := c.zoneKey(domain)
+	at, delay := domainWrites.reserve(key, interval)
+	if delay <= 0 {
+		return nil
+	}
+
+	delayedDomainWrites.WithLabelValues(command.String()).Inc()
+	if c.logger.Enabled() {
+		c.logger.V(1).Info("Delaying write to keep to the minimum interval between writes to a domain",
+			"command", command, "domain", domain, "delay", delay)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		domainWrites.release(key, at, interval)
+		return errors.Wrapf(ctx.Err(), "%s for %s cancelled while waiting for the previous write", command, domain)
+	}
+}
@@ -0,0 +1,213 @@
+package namecheap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteLimiter_Reserve(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := &writeLimiter{now: func() time.Time { return now }, next: map[zoneKey]time.Time{}}
+	a := zoneKey{apiUser: "testuser", domain: "a.example"}
+	b := zoneKey{apiUser: "testuser", domain: "b.example"}
+
+	delay := func(key zoneKey) time.Duration {
+		_, d := l.reserve(key, 5*time.Second)
+		return d
+	}
+
+	// Writes arriving together are queued an interval apart
+	assert.Zero(t, delay(a))
+	assert.Equal(t, 5*time.Second, delay(a))
+	assert.Equal(t, 10*time.Second, delay(a))
+
+	// Other domains aren't held up
+	assert.Zero(t, delay(b))
+
+	// Once the queue has passed, writes go straight through
+	now = now.Add(time.Minute)
+	assert.Zero(t, delay(a))
+}
+
+func TestWriteLimiter_Release(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := &writeLimiter{now: func() time.Time { return now }, next: map[zoneKey]time.Time{}}
+	a := zoneKey{apiUser: "testuser", domain: "a.example"}
+
+	_, _ = l.reserve(a, 5*time.Second)
+	first, _ := l.reserve(a, 5*time.Second)
+	second, _ := l.reserve(a, 5*time.Second)
+
+	// Giving up the last slot hands it to the next write
+	l.release(a, second, 5*time.Second)
+	at, delay := l.reserve(a, 5*time.Second)
+	assert.Equal(t, second, at)
+	assert.Equal(t, 10*time.Second, delay)
+
+	// A slot with writes queued behind it is kept, so they aren't brought
+	// closer together than the interval
+	l.release(a, first, 5*time.Second)
+	_, delay = l.reserve(a, 5*time.Second)
+	assert.Equal(t, 15*time.Second, delay)
+}
+
+func TestClient_DomainWriteInterval(t *testing.T) {
+	const interval = 200 * time.Millisecond
+	delayedDomainWrites.Reset()
+	t.Cleanup(delayedDomainWrites.Reset)
+
+	var mu sync.Mutex
+	writes := map[string][]time.Time{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		domain := r.FormValue("SLD") + "." + r.FormValue("TLD")
+		switch r.FormValue("Command") {
+		case "namecheap.domains.dns.setHosts":
+			mu.Lock()
+			writes[domain] = append(writes[domain], time.Now())
+			mu.Unlock()
+			_, err := w.Write([]byte(testSetHostsXML))
+			require.NoError(t, err)
+		case "namecheap.domains.dns.setCustom":
+			mu.Lock()
+			writes[domain] = append(writes[domain], time.Now())
+			mu.Unlock()
+			_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSSetCustomResult Domain="` + domain + `" Updated="true"/>
+	</CommandResponse>
+</ApiResponse>`))
+			require.NoError(t, err)
+		}
+	}))
+	defer server.Close()
+
+	// Every goroutine uses its own client, as every reconcile does
+	newClient := func() *Client {
+		return NewClient(Config{
+			APIUser:             "writelimituser",
+			APIKey:              "testkey",
+			Username:            "writelimituser",
+			ClientIP:            "127.0.0.1",
+			BaseURL:             server.URL,
+			HTTPClient:          &http.Client{Timeout: 5 * time.Second},
+			RateLimitConfig:     &RateLimitConfig{RequestsPerSecond: 1000, BurstSize: 1000},
+			DomainWriteInterval: interval,
+		})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			records := []DNSRecord{{Name: "@", Type: "A", Address: "192.0.2.1"}}
+			assert.NoError(t, newClient().setHosts(context.Background(), "limited.example", "", records))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, newClient().SetNameservers(context.Background(), "limited.example",
+				[]string{"ns1.example.net", "ns2.example.net"}))
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		records := []DNSRecord{{Name: "@", Type: "A", Address: "192.0.2.1"}}
+		assert.NoError(t, newClient().setHosts(context.Background(), "other.example", "", records))
+	}()
+	wg.Wait()
+
+	// The writes to one domain were spaced at least the interval apart,
+	// allowing for timer granularity
+	limited := writes["limited.example"]
+	require.Len(t, limited, 8)
+	sort.Slice(limited, func(i, j int) bool { return limited[i].Before(limited[j]) })
+	for i := 1; i < len(limited); i++ {
+		assert.GreaterOrEqual(t, limited[i].Sub(limited[i-1]), interval-10*time.Millisecond,
+			"write %d came too soon after the previous one", i)
+	}
+	assert.Len(t, writes["other.example"], 1)
+
+	delayed := testutil.ToFloat64(delayedDomainWrites.WithLabelValues(string(CommandDomainsDNSSetHosts))) +
+		testutil.ToFloat64(delayedDomainWrites.WithLabelValues(string(CommandDomainsDNSSetCustom)))
+	assert.Equal(t, float64(7), delayed)
+}
+
+func TestClient_DomainWriteInterval_Cancelled(t *testing.T) {
+	const interval = 200 * time.Millisecond
+
+	var mu sync.Mutex
+	var writes []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		writes = append(writes, time.Now())
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(testSetHostsXML))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:             "writelimituser",
+		APIKey:              "testkey",
+		Username:            "writelimituser",
+		ClientIP:            "127.0.0.1",
+		BaseURL:             server.URL,
+		HTTPClient:          &http.Client{Timeout: 5 * time.Second},
+		DomainWriteInterval: interval,
+	})
+	records := []DNSRecord{{Name: "@", Type: "A", Address: "192.0.2.1"}}
+	require.NoError(t, client.setHosts(context.Background(), "cancelled.example", "", records))
+
+	// Giving up on the next write while it waits doesn't send it
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := client.setHosts(ctx, "cancelled.example", "", records)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, writes, 1)
+
+	// nor hold up the write after it by the slot it gave up
+	require.NoError(t, client.setHosts(context.Background(), "cancelled.example", "", records))
+	require.Len(t, writes, 2)
+	assert.GreaterOrEqual(t, writes[1].Sub(writes[0]), interval-10*time.Millisecond)
+	assert.Less(t, writes[1].Sub(writes[0]), 2*interval-10*time.Millisecond)
+}
+
+func TestClient_DomainWriteInterval_Disabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(testSetHostsXML))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "writelimituser",
+		APIKey:     "testkey",
+		Username:   "writelimituser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		// A negative interval disables the limit
+		DomainWriteInterval: -1,
+	})
+	records := []DNSRecord{{Name: "@", Type: "A", Address: "192.0.2.1"}}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, client.setHosts(context.Background(), "disabled.example", "", records))
+	}
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,132 @@
+package namecheap
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrZoneShrunk is returned instead of rewriting a domain's host records
+// when domains.dns.getHosts reported far fewer records than were last known
+// and the drop has not been confirmed yet. getHosts has been seen to
+// transiently report an empty host list, and a setHosts based on it would
+// wipe the zone.
+var ErrZoneShrunk = errors.New("refusing to rewrite DNS host records after a sudden drop in their number")
+
+// Zones with fewer than zoneGuardMinRecords known records are not guarded, as
+// a drop in them is neither drastic nor costly to recover from
+const zoneGuardMinRecords = 3
+
+// zoneShrinkSettleTime is how long after a drastic drop in a zone's record
+// count a read must report the same count again for the drop to be trusted.
+// It spans reconciles, so that the reads within one reconcile can't confirm
+// a drop on their own.
+const zoneShrinkSettleTime = time.Minute
+
+type allowZoneShrinkKey struct{}
+
+// WithZoneShrinkAllowed returns a context allowing host records to be
+// rewritten by requests made with it even after an unconfirmed drop in
+// their number
+func WithZoneShrinkAllowed(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowZoneShrinkKey{}, true)
+}
+
+// isZoneShrinkAllowed reports whether the context allows writes after an
+// unconfirmed drop in a zone's record count
+func isZoneShrinkAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(allowZoneShrinkKey{}).(bool)
+	return allowed
+}
+
+// zoneKey identifies a domain in an account. The API endpoint stands in for
+// the environment, so that sandbox and production are kept apart.
+type zoneKey struct {
+	apiUser string
+	baseURL string
+	domain  string
+}
+
+// zoneState is what is known of a zone's record count
+type zoneState struct {
+	// known is the last record count that was trusted
+	known int
+
+	// shrunk is the count of an unconfirmed drastic drop, read at shrunkAt
+	shrunk   *int
+	shrunkAt time.Time
+}
+
+// zoneGuard tracks the record count of every zone read, so that a rewrite
+// based on a read that suddenly lost most records can be refused
+type zoneGuard struct {
+	mu    sync.Mutex
+	now   func() time.Time
+	zones map[zoneKey]*zoneState
+}
+
+// zones is shared by every client. Clients are created for every reconcile,
+// so the record counts must outlive any single client.
+var zones = &zoneGuard{now: time.Now, zones: map[zoneKey]*zoneState{}}
+
+// drastic reports whether a zone dropping from known to observed records is
+// suspicious: it had enough records to guard and lost all or most of them
+func drastic(known, observed int) bool {
+	return known >= zoneGuardMinRecords && (observed == 0 || observed*2 < known)
+}
+
+// observe records the number of records a read of the zone reported. A
+// drastic drop is only trusted once a read at least zoneShrinkSettleTime
+// later reports the same count.
+func (g *zoneGuard) observe(key zoneKey, count int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state, ok := g.zones[key]
+	if !ok {
+		g.zones[key] = &zoneState{known: count}
+		return
+	}
+
+	if !drastic(state.known, count) {
+		state.known, state.shrunk = count, nil
+		return
+	}
+
+	now := g.now()
+	switch {
+	case state.shrunk == nil || *state.shrunk != count:
+		state.shrunk, state.shrunkAt = &count, now
+	case now.Sub(state.shrunkAt) >= zoneShrinkSettleTime:
+		state.known, state.shrunk = count, nil
+	}
+}
+
+// wrote records that the zone was rewritten with count records
+func (g *zoneGuard) wrote(key zoneKey, count int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.zones[key] = &zoneState{known: count}
+}
+
+// checkWrite returns an error wrapping ErrZoneShrunk if the zone's last read
+// was an unconfirmed drastic drop
+func (g *zoneGuard) checkWrite(key zoneKey) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state, ok := g.zones[key]
+	if !ok || state.shrunk == nil {
+		return nil
+	}
+	return errors.Wrapf(ErrZoneShrunk, "domains.dns.getHosts reported %d records for %s where %d were known; "+
+		"retrying once the count is confirmed", *state.shrunk, key.domain, state.known)
+}
+
+// zoneKey returns the key of a domain's zone in the client's account
+func (c *Client) zoneKey(domainName string) zoneKey {
+	return zoneKey{apiUser: c.apiUser, baseURL: c.baseURL, domain: strings.ToLower(domainName)}
+}
@@ -0,0 +1,122 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneGuard(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	g := &zoneGuard{now: func() time.Time { return now }, zones: map[zoneKey]*zoneState{}}
+	key := zoneKey{apiUser: "testuser", baseURL: "https://api.namecheap.com/xml.response", domain: "example.com"}
+
+	// Unknown zones and gradual changes are trusted
+	assert.NoError(t, g.checkWrite(key))
+	g.observe(key, 10)
+	g.observe(key, 8)
+	assert.NoError(t, g.checkWrite(key))
+
+	// A sudden drop to nothing is not
+	g.observe(key, 0)
+	assert.ErrorIs(t, g.checkWrite(key), ErrZoneShrunk)
+
+	// Another read in the same reconcile doesn't confirm it
+	now = now.Add(time.Second)
+	g.observe(key, 0)
+	assert.ErrorIs(t, g.checkWrite(key), ErrZoneShrunk)
+
+	// The records coming back clears it
+	g.observe(key, 8)
+	assert.NoError(t, g.checkWrite(key))
+
+	// A drop reported consistently for the settle time is trusted
+	g.observe(key, 2)
+	assert.ErrorIs(t, g.checkWrite(key), ErrZoneShrunk)
+	now = now.Add(zoneShrinkSettleTime)
+	g.observe(key, 2)
+	assert.NoError(t, g.checkWrite(key))
+
+	// Small zones aren't guarded
+	g.observe(key, 0)
+	assert.NoError(t, g.checkWrite(key))
+
+	// A write resets what is known
+	g.observe(key, 6)
+	g.observe(key, 0)
+	g.wrote(key, 1)
+	assert.NoError(t, g.checkWrite(key))
+}
+
+func TestClient_CreateDNSRecord_ZoneShrunk(t *testing.T) {
+	hosts := func(n int) string {
+		var b strings.Builder
+		for i := 1; i <= n; i++ {
+			fmt.Fprintf(&b, `<host HostId="%d" Name="host%d" Type="A" Address="192.0.2.%d" TTL="300"/>`, i, i, i)
+		}
+		return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<ApiResponse Status="OK">
+	<CommandResponse>
+		<DomainDNSGetHostsResult Domain="shrunk.example" IsUsingOurDNS="true">%s</DomainDNSGetHostsResult>
+	</CommandResponse>
+</ApiResponse>`, b.String())
+	}
+
+	reported, setHosts := 5, 0
+	var written []DNSRecord
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		switch r.FormValue("Command") {
+		case "namecheap.domains.dns.getHosts":
+			// Once rewritten, the zone reads back as written
+			body := hosts(reported)
+			if written != nil {
+				body = hostsXML("shrunk.example", "", written)
+			}
+			_, err := w.Write([]byte(body))
+			require.NoError(t, err)
+		case "namecheap.domains.dns.setHosts":
+			setHosts++
+			written = formRecords(r)
+			_, err := w.Write([]byte(testSetHostsXML))
+			require.NoError(t, err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		APIUser:    "zoneguarduser",
+		APIKey:     "testkey",
+		Username:   "zoneguarduser",
+		ClientIP:   "127.0.0.1",
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	})
+	ctx := context.Background()
+	record := DNSRecord{Name: "www", Type: "A", Address: "192.0.2.10", TTL: 300}
+
+	// The zone is known to have five records
+	_, err := client.GetDNSRecords(ctx, "shrunk.example")
+	require.NoError(t, err)
+
+	// getHosts transiently reports none: adding a record must not wipe the
+	// other five
+	reported = 0
+	_, err = client.GetDNSRecords(ctx, "shrunk.example")
+	require.NoError(t, err)
+	err = client.CreateDNSRecord(ctx, "shrunk.example", record)
+	assert.ErrorIs(t, err, ErrZoneShrunk)
+	assert.Zero(t, setHosts, "no setHosts may be based on the empty read")
+
+	// An operator can override the check
+	err = client.CreateDNSRecord(WithZoneShrinkAllowed(ctx), "shrunk.example", record)
+	require.NoError(t, err)
+	assert.Equal(t, 1, setHosts)
+}
@@ -0,0 +1,94 @@
+package namecheap
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrZoneConflict is returned when a domain's host records don't read back
+// as they were just written, because they were changed elsewhere
+// meanwhile. The change can be retried against the new records.
+var ErrZoneConflict = errors.New("DNS host records changed while they were being rewritten")
+
+// zoneLocks serializes the read-modify-write cycles on each domain's host
+// records, so that concurrent rewrites don't overwrite each other's changes.
+// Clients are created for every reconcile, so the locks are shared by every
+// client.
+type zoneLocks struct {
+	mu    sync.Mutex
+	locks map[zoneKey]*zoneLock
+}
+
+// zoneLock is the lock of one zone, along with the number of callers
+// holding or waiting for it
+type zoneLock struct {
+	held chan struct{}
+	refs int
+}
+
+var zoneWrites = &zoneLocks{locks: map[zoneKey]*zoneLock{}}
+
+// lock blocks until the zone's lock is held or ctx is done, returning the
+// function that releases it
+func (l *zoneLocks) lock(ctx context.Context, key zoneKey) (func(), error) {
+	l.mu.Lock()
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = &zoneLock{held: make(chan struct{}, 1)}
+		l.locks[key] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	select {
+	case lock.held <- struct{}{}:
+		return func() {
+			<-lock.held
+			l.release(key, lock)
+		}, nil
+	case <-ctx.Done():
+		l.release(key, lock)
+		return nil, errors.Wrapf(ctx.Err(), "cancelled waiting to rewrite the host records of %s", key.domain)
+	}
+}
+
+// release drops a reference to the zone's lock, forgetting it once unused
+func (l *zoneLocks) release(key zoneKey, lock *zoneLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock.refs--
+	if lock.refs == 0 {
+		delete(l.locks, key)
+	}
+}
+
+// recordKey identifies a host record by name and type. Namecheap may
+// normalize addresses, so they are left out of comparisons of written and
+// read records.
+type recordKey struct {
+	name       string
+	recordType string
+}
+
+// sameRecords reports whether two host sets hold the same number of records
+// of each name and type
+func sameRecords(written, read []DNSRecord) bool {
+	if len(written) != len(read) {
+		return false
+	}
+	counts := map[recordKey]int{}
+	for _, record := range written {
+		counts[recordKey{strings.ToLower(record.Name), strings.ToUpper(record.Type)}]++
+	}
+	for _, record := range read {
+		key := recordKey{strings.ToLower(record.Name), strings.ToUpper(record.Type)}
+		if counts[key] == 0 {
+			return false
+		}
+		counts[key]--
+	}
+	return true
+}